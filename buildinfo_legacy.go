@@ -0,0 +1,27 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !go1.18
+// +build !go1.18
+
+package main
+
+import (
+	"runtime/debug"
+)
+
+// vcsRevision returns the version control revision from which the binary was built.
+// Toolchains prior to Go 1.18 do not record the revision.
+func vcsRevision(_ *debug.BuildInfo) string {
+	return ""
+}
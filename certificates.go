@@ -0,0 +1,73 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+
+	grpcapi "github.com/attestantio/dirk/services/api/grpc"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	"github.com/wealdtech/go-majordomo"
+)
+
+// fetchCertificates fetches the server certificate, server key and CA certificate.
+func fetchCertificates(ctx context.Context, majordomo majordomo.Service) ([]byte, []byte, []byte, error) {
+	certPEMBlock, err := majordomo.Fetch(ctx, viper.GetString("certificates.server-cert"))
+	if err != nil {
+		return nil, nil, nil, errors.Wrap(err, "failed to obtain server certificate")
+	}
+	keyPEMBlock, err := majordomo.Fetch(ctx, viper.GetString("certificates.server-key"))
+	if err != nil {
+		return nil, nil, nil, errors.Wrap(err, "failed to obtain server key")
+	}
+	var caPEMBlock []byte
+	if viper.GetString("certificates.ca-cert") != "" {
+		caPEMBlock, err = majordomo.Fetch(ctx, viper.GetString("certificates.ca-cert"))
+		if err != nil {
+			return nil, nil, nil, errors.Wrap(err, "failed to obtain client CA certificate")
+		}
+	}
+
+	return certPEMBlock, keyPEMBlock, caPEMBlock, nil
+}
+
+// reloadCertificates fetches the certificates and supplies them to the API service.
+func reloadCertificates(ctx context.Context, majordomo majordomo.Service, api *grpcapi.Service) {
+	certPEMBlock, keyPEMBlock, caPEMBlock, err := fetchCertificates(ctx, majordomo)
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to fetch certificates; continuing with existing certificates")
+		return
+	}
+	if err := api.ReloadCertificates(ctx, certPEMBlock, keyPEMBlock, caPEMBlock); err != nil {
+		log.Warn().Err(err).Msg("Failed to reload certificates")
+	}
+}
+
+// refreshCertificates reloads the certificates if they have changed since they were last loaded.
+func refreshCertificates(ctx context.Context, majordomo majordomo.Service, api *grpcapi.Service) {
+	certPEMBlock, keyPEMBlock, caPEMBlock, err := fetchCertificates(ctx, majordomo)
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to fetch certificates; continuing with existing certificates")
+		return
+	}
+	if !api.CertificatesChanged(certPEMBlock, keyPEMBlock, caPEMBlock) {
+		log.Trace().Msg("Certificates unchanged")
+		return
+	}
+	log.Info().Msg("Certificates have changed; reloading")
+	if err := api.ReloadCertificates(ctx, certPEMBlock, keyPEMBlock, caPEMBlock); err != nil {
+		log.Warn().Err(err).Msg("Failed to reload certificates")
+	}
+}
@@ -0,0 +1,200 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	e2wallet "github.com/wealdtech/go-eth2-wallet"
+	keystorev4 "github.com/wealdtech/go-eth2-wallet-encryptor-keystorev4"
+	e2wtypes "github.com/wealdtech/go-eth2-wallet-types/v2"
+)
+
+// RotatePassphrase re-encrypts a wallet or account in the first of the stores that contains it
+// with a new passphrase.  The name is either that of a wallet, in which case the wallet's own
+// passphrase is rotated, or of an account in the form wallet/account.  The existing passphrase
+// must decrypt the wallet or account before anything is written, and the re-encrypted data must
+// decrypt with the new passphrase after it is written.  For distributed accounts only the
+// encryption of the local share is changed.
+func RotatePassphrase(ctx context.Context, stores []e2wtypes.Store, name string, oldPassphrase []byte, newPassphrase []byte) error {
+	if name == "" {
+		return errors.New("no wallet or account specified")
+	}
+	if len(oldPassphrase) == 0 {
+		return errors.New("no existing passphrase specified")
+	}
+	if len(newPassphrase) == 0 {
+		return errors.New("no new passphrase specified")
+	}
+	walletName, accountName, err := e2wallet.WalletAndAccountNames(name)
+	if err != nil {
+		return errors.Wrap(err, "invalid wallet or account")
+	}
+
+	for _, store := range stores {
+		wallet, err := e2wallet.OpenWallet(walletName, e2wallet.WithStore(store))
+		if err != nil {
+			continue
+		}
+		if accountName == "" {
+			return rotateWalletPassphrase(store, wallet, oldPassphrase, newPassphrase)
+		}
+		return rotateAccountPassphrase(ctx, store, wallet, accountName, oldPassphrase, newPassphrase)
+	}
+
+	return fmt.Errorf("wallet %q not found", walletName)
+}
+
+func rotateWalletPassphrase(store e2wtypes.Store, wallet e2wtypes.Wallet, oldPassphrase []byte, newPassphrase []byte) error {
+	data, err := store.RetrieveWalletByID(wallet.ID())
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve wallet")
+	}
+	rotated, err := reencrypt(data, oldPassphrase, newPassphrase)
+	if err != nil {
+		return errors.Wrapf(err, "wallet %q", wallet.Name())
+	}
+	if err := replaceData(store, wallet.ID(), wallet.ID(), rotated, func() error {
+		return store.StoreWallet(wallet.ID(), wallet.Name(), rotated)
+	}); err != nil {
+		return errors.Wrap(err, "failed to store wallet")
+	}
+
+	stored, err := store.RetrieveWalletByID(wallet.ID())
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve rotated wallet")
+	}
+	return verifyRotation(stored, newPassphrase)
+}
+
+func rotateAccountPassphrase(ctx context.Context, store e2wtypes.Store, wallet e2wtypes.Wallet, accountName string, oldPassphrase []byte, newPassphrase []byte) error {
+	provider, isProvider := wallet.(e2wtypes.WalletAccountByNameProvider)
+	if !isProvider {
+		return fmt.Errorf("wallet %q does not support account lookup", wallet.Name())
+	}
+	account, err := provider.AccountByName(ctx, accountName)
+	if err != nil {
+		return fmt.Errorf("account %s/%s not found", wallet.Name(), accountName)
+	}
+
+	data, err := store.RetrieveAccount(wallet.ID(), account.ID())
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve account")
+	}
+	rotated, err := reencrypt(data, oldPassphrase, newPassphrase)
+	if err != nil {
+		return errors.Wrapf(err, "account %s/%s", wallet.Name(), accountName)
+	}
+	if err := replaceData(store, wallet.ID(), account.ID(), rotated, func() error {
+		return store.StoreAccount(wallet.ID(), account.ID(), rotated)
+	}); err != nil {
+		return errors.Wrap(err, "failed to store account")
+	}
+
+	stored, err := store.RetrieveAccount(wallet.ID(), account.ID())
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve rotated account")
+	}
+	return verifyRotation(stored, newPassphrase)
+}
+
+// reencrypt decrypts the keystore in the given wallet or account data with the old passphrase,
+// and returns the data with the keystore encrypted with the new passphrase.  The key derivation
+// function of the existing keystore is retained.
+func reencrypt(data []byte, oldPassphrase []byte, newPassphrase []byte) ([]byte, error) {
+	unmarshalled := make(map[string]interface{})
+	if err := json.Unmarshal(data, &unmarshalled); err != nil {
+		return nil, errors.Wrap(err, "failed to parse data")
+	}
+	crypto, exists := unmarshalled["crypto"].(map[string]interface{})
+	if !exists {
+		return nil, errors.New("no passphrase to rotate")
+	}
+	encryptor := keystoreEncryptor(crypto)
+	secret, err := encryptor.Decrypt(crypto, string(oldPassphrase))
+	if err != nil {
+		return nil, errors.New("existing passphrase is incorrect")
+	}
+	rotatedCrypto, err := encryptor.Encrypt(secret, string(newPassphrase))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encrypt with new passphrase")
+	}
+	check, err := encryptor.Decrypt(rotatedCrypto, string(newPassphrase))
+	if err != nil || !bytes.Equal(check, secret) {
+		return nil, errors.New("failed to decrypt with new passphrase")
+	}
+	unmarshalled["crypto"] = rotatedCrypto
+
+	return json.Marshal(unmarshalled)
+}
+
+// verifyRotation checks that the stored data decrypts with the new passphrase.
+func verifyRotation(data []byte, newPassphrase []byte) error {
+	unmarshalled := make(map[string]interface{})
+	if err := json.Unmarshal(data, &unmarshalled); err != nil {
+		return errors.Wrap(err, "failed to parse rotated data")
+	}
+	crypto, exists := unmarshalled["crypto"].(map[string]interface{})
+	if !exists {
+		return errors.New("rotated data has no keystore")
+	}
+	if _, err := keystoreEncryptor(crypto).Decrypt(crypto, string(newPassphrase)); err != nil {
+		return errors.New("rotated data does not decrypt with new passphrase")
+	}
+	return nil
+}
+
+// keystoreEncryptor returns an encryptor that uses the same key derivation function as the keystore.
+func keystoreEncryptor(crypto map[string]interface{}) *keystorev4.Encryptor {
+	if kdf, isMap := crypto["kdf"].(map[string]interface{}); isMap {
+		if function, isString := kdf["function"].(string); isString && function == "scrypt" {
+			return keystorev4.New(keystorev4.WithCipher("scrypt"))
+		}
+	}
+	return keystorev4.New()
+}
+
+// replaceData replaces the data with the given ID in a wallet.  Filesystem stores write their
+// files in place, so for these the data is written to a temporary file alongside the existing
+// one, using the store's own encryption, and then renamed over it so that the existing file is
+// never partially overwritten.  Other stores use the supplied function, as they replace data in
+// a single operation.
+func replaceData(store e2wtypes.Store, walletID uuid.UUID, id uuid.UUID, data []byte, write func() error) error {
+	locationProvider, isLocationProvider := store.(e2wtypes.StoreLocationProvider)
+	if store.Name() != "filesystem" || !isLocationProvider {
+		return write()
+	}
+
+	tmpID, err := uuid.NewRandom()
+	if err != nil {
+		return errors.Wrap(err, "failed to generate temporary ID")
+	}
+	if err := store.StoreAccount(walletID, tmpID, data); err != nil {
+		return err
+	}
+	walletPath := filepath.Join(locationProvider.Location(), walletID.String())
+	tmpPath := filepath.Join(walletPath, tmpID.String())
+	if err := os.Rename(tmpPath, filepath.Join(walletPath, id.String())); err != nil {
+		_ = os.Remove(tmpPath)
+		return errors.Wrap(err, "failed to replace existing data")
+	}
+	return nil
+}
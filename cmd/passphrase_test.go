@@ -0,0 +1,106 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/attestantio/dirk/cmd"
+	"github.com/attestantio/dirk/testing/accounts"
+	"github.com/stretchr/testify/require"
+	e2wallet "github.com/wealdtech/go-eth2-wallet"
+	filesystem "github.com/wealdtech/go-eth2-wallet-store-filesystem"
+	scratch "github.com/wealdtech/go-eth2-wallet-store-scratch"
+	e2wtypes "github.com/wealdtech/go-eth2-wallet-types/v2"
+)
+
+func TestRotatePassphrase(t *testing.T) {
+	ctx := context.Background()
+
+	base, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(base)
+
+	tests := []struct {
+		name  string
+		store e2wtypes.Store
+	}{
+		{
+			name:  "Scratch",
+			store: scratch.New(),
+		},
+		{
+			name:  "Filesystem",
+			store: filesystem.New(filesystem.WithLocation(base)),
+		},
+		{
+			name:  "EncryptedFilesystem",
+			store: filesystem.New(filesystem.WithLocation(base+"-encrypted"), filesystem.WithPassphrase([]byte("store secret"))),
+		},
+	}
+	defer os.RemoveAll(base + "-encrypted")
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store, err := accounts.SetupWithStore(ctx, test.store)
+			require.NoError(t, err)
+			stores := []e2wtypes.Store{scratch.New(), store}
+
+			require.EqualError(t, cmd.RotatePassphrase(ctx, stores, "", []byte("old"), []byte("new")), "no wallet or account specified")
+			require.EqualError(t, cmd.RotatePassphrase(ctx, stores, "Wallet 1", nil, []byte("new")), "no existing passphrase specified")
+			require.EqualError(t, cmd.RotatePassphrase(ctx, stores, "Wallet 1", []byte("old"), nil), "no new passphrase specified")
+			require.EqualError(t, cmd.RotatePassphrase(ctx, stores, "Unknown/Account 1", []byte("old"), []byte("new")), `wallet "Unknown" not found`)
+			require.EqualError(t, cmd.RotatePassphrase(ctx, stores, "Wallet 1/Unknown", []byte("old"), []byte("new")), "account Wallet 1/Unknown not found")
+			require.EqualError(t, cmd.RotatePassphrase(ctx, stores, "Wallet 1/Account 1", []byte("wrong"), []byte("new")), "account Wallet 1/Account 1: existing passphrase is incorrect")
+			require.EqualError(t, cmd.RotatePassphrase(ctx, stores, "Wallet 2", []byte("old"), []byte("new")), `wallet "Wallet 2": no passphrase to rotate`)
+
+			// Rotate a wallet, an account and a distributed account.
+			require.NoError(t, cmd.RotatePassphrase(ctx, stores, "Wallet 1", []byte("Wallet 1 passphrase"), []byte("new wallet secret")))
+			require.NoError(t, cmd.RotatePassphrase(ctx, stores, "Wallet 1/Account 1", []byte("Account 1 passphrase"), []byte("new secret")))
+			require.NoError(t, cmd.RotatePassphrase(ctx, stores, "Wallet 2/Account 1", []byte("Account 1 passphrase"), []byte("new secret")))
+
+			wallet1, err := e2wallet.OpenWallet("Wallet 1", e2wallet.WithStore(store))
+			require.NoError(t, err)
+			require.Error(t, wallet1.(e2wtypes.WalletLocker).Unlock(ctx, []byte("Wallet 1 passphrase")))
+			require.NoError(t, wallet1.(e2wtypes.WalletLocker).Unlock(ctx, []byte("new wallet secret")))
+			requireRotated(ctx, t, wallet1, "Account 1", []byte("Account 1 passphrase"), []byte("new secret"))
+			// Other accounts are unaffected.
+			requireRotated(ctx, t, wallet1, "Account 2", []byte("new secret"), []byte("Account 2 passphrase"))
+
+			wallet2, err := e2wallet.OpenWallet("Wallet 2", e2wallet.WithStore(store))
+			require.NoError(t, err)
+			requireRotated(ctx, t, wallet2, "Account 1", []byte("Account 1 passphrase"), []byte("new secret"))
+
+			// No temporary files should remain: the wallet, its index and its six accounts.
+			if provider, isProvider := store.(e2wtypes.StoreLocationProvider); isProvider {
+				files, err := ioutil.ReadDir(filepath.Join(provider.Location(), wallet1.ID().String()))
+				require.NoError(t, err)
+				require.Len(t, files, 8)
+			}
+		})
+	}
+}
+
+// requireRotated requires that the account cannot be unlocked with the old passphrase, and can with the new.
+func requireRotated(ctx context.Context, t *testing.T, wallet e2wtypes.Wallet, name string, oldPassphrase []byte, newPassphrase []byte) {
+	account, err := wallet.(e2wtypes.WalletAccountByNameProvider).AccountByName(ctx, name)
+	require.NoError(t, err)
+	locker := account.(e2wtypes.AccountLocker)
+	require.Error(t, locker.Unlock(ctx, oldPassphrase))
+	require.NoError(t, locker.Unlock(ctx, newPassphrase))
+}
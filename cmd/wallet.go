@@ -0,0 +1,76 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	e2wallet "github.com/wealdtech/go-eth2-wallet"
+	e2wtypes "github.com/wealdtech/go-eth2-wallet-types/v2"
+)
+
+// ExportWallet exports the named wallet from the first of the stores that contains it.  The export
+// contains the wallet and all of its accounts, with the accounts still encrypted by their own
+// passphrases, and the export as a whole is encrypted with the supplied passphrase.  For
+// distributed wallets the export contains the local share of each account, along with the
+// threshold, verification vector and participants required to use it.
+func ExportWallet(ctx context.Context, stores []e2wtypes.Store, name string, passphrase []byte) ([]byte, error) {
+	if name == "" {
+		return nil, errors.New("no wallet specified")
+	}
+	if len(passphrase) == 0 {
+		return nil, errors.New("no passphrase specified")
+	}
+
+	for _, store := range stores {
+		wallet, err := e2wallet.OpenWallet(name, e2wallet.WithStore(store))
+		if err != nil {
+			continue
+		}
+		exporter, isExporter := wallet.(e2wtypes.WalletExporter)
+		if !isExporter {
+			return nil, fmt.Errorf("wallet %q does not support export", name)
+		}
+		data, err := exporter.Export(ctx, passphrase)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to export wallet")
+		}
+		return data, nil
+	}
+
+	return nil, fmt.Errorf("wallet %q not found", name)
+}
+
+// ImportWallet imports a wallet exported by ExportWallet in to the given store.  The wallet must
+// not already exist in the store.
+func ImportWallet(ctx context.Context, store e2wtypes.Store, data []byte, passphrase []byte) (e2wtypes.Wallet, error) {
+	if len(data) == 0 {
+		return nil, errors.New("no wallet data supplied")
+	}
+	if len(passphrase) == 0 {
+		return nil, errors.New("no passphrase specified")
+	}
+
+	if err := e2wallet.UseStore(store); err != nil {
+		return nil, errors.Wrap(err, "failed to use store")
+	}
+	wallet, err := e2wallet.ImportWallet(data, passphrase)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to import wallet")
+	}
+
+	return wallet, nil
+}
@@ -0,0 +1,89 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/attestantio/dirk/cmd"
+	"github.com/attestantio/dirk/testing/accounts"
+	"github.com/stretchr/testify/require"
+	e2wallet "github.com/wealdtech/go-eth2-wallet"
+	scratch "github.com/wealdtech/go-eth2-wallet-store-scratch"
+	e2wtypes "github.com/wealdtech/go-eth2-wallet-types/v2"
+)
+
+func TestWalletExportImport(t *testing.T) {
+	ctx := context.Background()
+	store, err := accounts.Setup(ctx)
+	require.NoError(t, err)
+	stores := []e2wtypes.Store{scratch.New(), store}
+	passphrase := []byte("backup secret")
+
+	_, err = cmd.ExportWallet(ctx, stores, "", passphrase)
+	require.EqualError(t, err, "no wallet specified")
+	_, err = cmd.ExportWallet(ctx, stores, "Wallet 2", nil)
+	require.EqualError(t, err, "no passphrase specified")
+	_, err = cmd.ExportWallet(ctx, stores, "Unknown", passphrase)
+	require.EqualError(t, err, `wallet "Unknown" not found`)
+
+	for _, name := range []string{"Wallet 1", "Wallet 2"} {
+		t.Run(name, func(t *testing.T) {
+			data, err := cmd.ExportWallet(ctx, stores, name, passphrase)
+			require.NoError(t, err)
+
+			target := scratch.New()
+			_, err = cmd.ImportWallet(ctx, target, nil, passphrase)
+			require.EqualError(t, err, "no wallet data supplied")
+			_, err = cmd.ImportWallet(ctx, target, data, []byte("wrong"))
+			require.Error(t, err)
+			imported, err := cmd.ImportWallet(ctx, target, data, passphrase)
+			require.NoError(t, err)
+			require.Equal(t, name, imported.Name())
+
+			// The wallet cannot be imported twice.
+			_, err = cmd.ImportWallet(ctx, target, data, passphrase)
+			require.Error(t, err)
+
+			// The imported wallet should match the original.
+			original, err := e2wallet.OpenWallet(name, e2wallet.WithStore(store))
+			require.NoError(t, err)
+			restored, err := e2wallet.OpenWallet(name, e2wallet.WithStore(target))
+			require.NoError(t, err)
+			require.Equal(t, original.ID(), restored.ID())
+			require.Equal(t, original.Type(), restored.Type())
+			originalAccounts := make(map[string]e2wtypes.Account)
+			for account := range original.Accounts(ctx) {
+				originalAccounts[account.Name()] = account
+			}
+			require.NotEmpty(t, originalAccounts)
+			restoredAccounts := 0
+			for account := range restored.Accounts(ctx) {
+				restoredAccounts++
+				originalAccount, exists := originalAccounts[account.Name()]
+				require.True(t, exists)
+				require.Equal(t, originalAccount.ID(), account.ID())
+				require.Equal(t, originalAccount.PublicKey().Marshal(), account.PublicKey().Marshal())
+				if distributedAccount, isDistributed := account.(e2wtypes.DistributedAccount); isDistributed {
+					originalDistributed := originalAccount.(e2wtypes.DistributedAccount)
+					require.Equal(t, originalDistributed.CompositePublicKey().Marshal(), distributedAccount.CompositePublicKey().Marshal())
+					require.Equal(t, originalDistributed.SigningThreshold(), distributedAccount.SigningThreshold())
+					require.Equal(t, originalDistributed.Participants(), distributedAccount.Participants())
+				}
+			}
+			require.Equal(t, len(originalAccounts), restoredAccounts)
+		})
+	}
+}
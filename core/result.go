@@ -1,4 +1,4 @@
-// Copyright © 2020 Attestant Limited.
+// Copyright © 2020, 2021 Attestant Limited.
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
 // You may obtain a copy of the License at
@@ -22,8 +22,14 @@ const (
 	ResultSucceeded
 	ResultDenied
 	ResultFailed
+	// ResultInProgress is returned when the operation is already being carried out by another client.
+	ResultInProgress
+	// ResultTagNotPermitted is returned when a tagged message has a tag that the client is not permitted to sign.
+	ResultTagNotPermitted
+	// ResultResourceExhausted is returned when there is insufficient capacity to carry out the operation.
+	ResultResourceExhausted
 )
 
 func (r Result) String() string {
-	return [...]string{"Unknown", "Succeeded", "Denied", "Failed"}[r]
+	return [...]string{"Unknown", "Succeeded", "Denied", "Failed", "InProgress", "TagNotPermitted", "ResourceExhausted"}[r]
 }
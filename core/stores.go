@@ -16,7 +16,11 @@ package core
 import (
 	"context"
 	"fmt"
+	"os"
 
+	"github.com/attestantio/dirk/stores/gcs"
+	s3store "github.com/attestantio/dirk/stores/s3"
+	"github.com/attestantio/dirk/util"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
 	filesystem "github.com/wealdtech/go-eth2-wallet-store-filesystem"
@@ -36,6 +40,19 @@ type Store struct {
 	Type       string `mapstructure:"type"`
 	Location   string `mapstructure:"location"`
 	Passphrase string `mapstructure:"passphrase"`
+	// Bucket, Prefix, Region and Endpoint locate the wallets of an S3 or GCS store.
+	// Region applies only to S3 stores.
+	Bucket   string `mapstructure:"bucket"`
+	Prefix   string `mapstructure:"prefix"`
+	Region   string `mapstructure:"region"`
+	Endpoint string `mapstructure:"endpoint"`
+	// AccessKeyID and SecretAccessKey are the credentials for an S3 store.
+	// If not present the AWS default credential chain is used.
+	AccessKeyID     string `mapstructure:"access-key-id"`
+	SecretAccessKey string `mapstructure:"secret-access-key"`
+	// Credentials is the path to the service account credentials for a GCS
+	// store.  If not present the application default credentials are used.
+	Credentials string `mapstructure:"credentials"`
 }
 
 // InitStores initialises the stores from a configuration.
@@ -60,16 +77,35 @@ func InitStores(ctx context.Context, stores []*Store) ([]e2wtypes.Store, error)
 				opts = append(opts, filesystem.WithPassphrase([]byte(store.Passphrase)))
 			}
 			if store.Location != "" {
+				// Wallet stores may legitimately be read-only, but account creation will fail against them.
+				if _, err := os.Stat(store.Location); err != nil {
+					log.Warn().Str("name", store.Name).Str("location", store.Location).Err(err).Msg("Filesystem store location is not accessible")
+				} else if err := util.CheckWritable(store.Location); err != nil {
+					log.Warn().Str("name", store.Name).Str("location", store.Location).Err(err).Msg("Filesystem store is not writable; account creation will fail")
+				}
 				opts = append(opts, filesystem.WithLocation(store.Location))
 			}
 			res = append(res, filesystem.New(opts...))
 		case "s3":
-			log.Trace().Str("name", store.Name).Msg("Adding S3 store")
-			s3Store, err := s3.New(s3.WithPassphrase([]byte(store.Passphrase)))
+			log.Trace().Str("name", store.Name).Str("bucket", store.Bucket).Str("prefix", store.Prefix).Msg("Adding S3 store")
+			s3Store, err := initS3Store(ctx, store)
 			if err != nil {
 				return nil, errors.Wrap(err, fmt.Sprintf("failed to access store %d", i))
 			}
 			res = append(res, s3Store)
+		case "gcs":
+			log.Trace().Str("name", store.Name).Str("bucket", store.Bucket).Str("prefix", store.Prefix).Msg("Adding GCS store")
+			gcsStore, err := gcs.New(ctx,
+				gcs.WithBucket(store.Bucket),
+				gcs.WithPrefix(store.Prefix),
+				gcs.WithCredentialsPath(store.Credentials),
+				gcs.WithEndpoint(store.Endpoint),
+				gcs.WithPassphrase([]byte(store.Passphrase)),
+			)
+			if err != nil {
+				return nil, errors.Wrap(err, fmt.Sprintf("failed to access store %d", i))
+			}
+			res = append(res, gcsStore)
 		case "scratch":
 			log.Trace().Msg("Adding scratch store")
 			res = append(res, scratch.New())
@@ -80,6 +116,30 @@ func InitStores(ctx context.Context, stores []*Store) ([]e2wtypes.Store, error)
 	return res, nil
 }
 
+// initS3Store initialises an S3 store.
+func initS3Store(ctx context.Context, store *Store) (e2wtypes.Store, error) {
+	if store.Bucket == "" {
+		// Without a bucket the bucket name is derived from the credentials.
+		opts := []s3.Option{s3.WithPassphrase([]byte(store.Passphrase))}
+		if store.Region != "" {
+			opts = append(opts, s3.WithRegion(store.Region))
+		}
+		return s3.New(opts...)
+	}
+
+	params := []s3store.Parameter{
+		s3store.WithBucket(store.Bucket),
+		s3store.WithPrefix(store.Prefix),
+		s3store.WithEndpoint(store.Endpoint),
+		s3store.WithCredentials(store.AccessKeyID, store.SecretAccessKey),
+		s3store.WithPassphrase([]byte(store.Passphrase)),
+	}
+	if store.Region != "" {
+		params = append(params, s3store.WithRegion(store.Region))
+	}
+	return s3store.New(ctx, params...)
+}
+
 // initDefaultStores initialises the default stores.
 func initDefaultStores() []e2wtypes.Store {
 	res := make([]e2wtypes.Store, 1)
@@ -0,0 +1,144 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/attestantio/dirk/services/fetcher"
+	"github.com/attestantio/dirk/services/unlocker"
+	"github.com/attestantio/dirk/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	e2wtypes "github.com/wealdtech/go-eth2-wallet-types/v2"
+)
+
+// eagerUnlockAccount is an account to be unlocked at startup.
+type eagerUnlockAccount struct {
+	wallet  e2wtypes.Wallet
+	account e2wtypes.Account
+}
+
+// eagerUnlock unlocks all accounts at startup, if configured to do so.
+// If fewer than unlocker.min-unlock-fraction of the accounts not excluded by
+// unlocker.eager-exclusions are unlocked an error is returned, as this
+// suggests that the unlocker's passphrases are misconfigured.
+func eagerUnlock(ctx context.Context, fetcherSvc fetcher.Service, unlockerSvc unlocker.Service) error {
+	if !viper.GetBool("unlocker.eager") {
+		return nil
+	}
+	minFraction := viper.GetFloat64("unlocker.min-unlock-fraction")
+	if minFraction < 0 || minFraction > 1 {
+		return errors.New("unlocker.min-unlock-fraction must be between 0 and 1")
+	}
+	exclusions := make([]*regexp.Regexp, 0)
+	for _, exclusion := range viper.GetStringSlice("unlocker.eager-exclusions") {
+		exclusionRegex, err := regexp.Compile(fmt.Sprintf("^%s$", exclusion))
+		if err != nil {
+			return errors.Wrap(err, fmt.Sprintf("invalid unlocker.eager-exclusions entry %q", exclusion))
+		}
+		exclusions = append(exclusions, exclusionRegex)
+	}
+
+	walletEnumerator, isWalletEnumerator := fetcherSvc.(fetcher.WalletEnumerator)
+	if !isWalletEnumerator {
+		return errors.New("fetcher does not support eager unlocking")
+	}
+
+	candidates := make([]*eagerUnlockAccount, 0)
+	for _, wallet := range walletEnumerator.FetchWallets(ctx) {
+		accounts, err := fetcherSvc.FetchAccounts(ctx, wallet.Name())
+		if err != nil {
+			return errors.Wrap(err, fmt.Sprintf("failed to obtain accounts for wallet %s", wallet.Name()))
+		}
+		for _, account := range accounts {
+			if isEagerUnlockExcluded(exclusions, fmt.Sprintf("%s/%s", wallet.Name(), account.Name())) {
+				continue
+			}
+			candidates = append(candidates, &eagerUnlockAccount{
+				wallet:  wallet,
+				account: account,
+			})
+		}
+	}
+	if len(candidates) == 0 {
+		log.Debug().Msg("No accounts to unlock eagerly")
+		return nil
+	}
+
+	log.Trace().Int("accounts", len(candidates)).Msg("Unlocking accounts")
+	started := time.Now()
+	unlocked := 0
+	// Decrypting keystores is expensive, so spread the work across CPUs.
+	_, err := util.Scatter(len(candidates), func(offset int, entries int, mu *sync.RWMutex) (interface{}, error) {
+		for i := offset; i < offset+entries; i++ {
+			if eagerUnlockOne(ctx, unlockerSvc, candidates[i]) {
+				mu.Lock()
+				unlocked++
+				mu.Unlock()
+			}
+		}
+		return nil, nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to unlock accounts")
+	}
+
+	fraction := float64(unlocked) / float64(len(candidates))
+	log.Info().Int("accounts", len(candidates)).Int("unlocked", unlocked).Dur("elapsed", time.Since(started)).Msg("Eagerly unlocked accounts")
+	if fraction < minFraction {
+		return fmt.Errorf("only %d of %d accounts (%.2f) unlocked, below unlocker.min-unlock-fraction of %.2f; check unlocker passphrases", unlocked, len(candidates), fraction, minFraction)
+	}
+	return nil
+}
+
+// eagerUnlockOne attempts to unlock a single account, returning true if it is unlocked.
+func eagerUnlockOne(ctx context.Context, unlockerSvc unlocker.Service, candidate *eagerUnlockAccount) bool {
+	locker, isLocker := candidate.account.(e2wtypes.AccountLocker)
+	if !isLocker {
+		return true
+	}
+	log := log.With().Str("wallet", candidate.wallet.Name()).Str("account", candidate.account.Name()).Logger()
+	unlocked, err := locker.IsUnlocked(ctx)
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to establish if account is unlocked")
+		return false
+	}
+	if unlocked {
+		return true
+	}
+	unlocked, err = unlockerSvc.UnlockAccount(ctx, candidate.wallet, candidate.account)
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed during attempt to unlock account")
+		return false
+	}
+	if !unlocked {
+		log.Debug().Msg("No passphrase unlocked account")
+	}
+	return unlocked
+}
+
+// isEagerUnlockExcluded returns true if the account is excluded from eager unlocking.
+func isEagerUnlockExcluded(exclusions []*regexp.Regexp, accountName string) bool {
+	for _, exclusion := range exclusions {
+		if exclusion.MatchString(accountName) {
+			return true
+		}
+	}
+	return false
+}
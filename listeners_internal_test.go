@@ -0,0 +1,91 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"os"
+	"testing"
+
+	grpcapi "github.com/attestantio/dirk/services/api/grpc"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObtainListeners(t *testing.T) {
+	// Entries are as supplied by the YAML parser.
+	viper.Set("server.listeners", []interface{}{
+		map[interface{}]interface{}{
+			"address":        "127.0.0.1:13141",
+			"client-auth":    "request",
+			"default-client": "local",
+		},
+		map[interface{}]interface{}{
+			"address":    "10.0.0.10:13141",
+			"token-auth": "require",
+		},
+		// An unquoted octal mode is supplied as an integer.
+		map[interface{}]interface{}{
+			"address":     "unix:///run/dirk/dirk.sock",
+			"client-auth": "none",
+			"socket-mode": 0660,
+		},
+		map[interface{}]interface{}{
+			"address":     "unix:///run/dirk/admin.sock",
+			"socket-mode": "0640",
+		},
+	})
+	defer viper.Set("server.listeners", nil)
+	viper.Set("server.default-client", "server")
+	defer viper.Set("server.default-client", nil)
+
+	listeners, err := obtainListeners(grpcapi.ClientAuthRequire, grpcapi.TokenAuthOptional)
+	require.NoError(t, err)
+	require.Equal(t, []*grpcapi.Listener{
+		{
+			Address:       "127.0.0.1:13141",
+			ClientAuth:    grpcapi.ClientAuthRequest,
+			TokenAuth:     grpcapi.TokenAuthOptional,
+			DefaultClient: "local",
+		},
+		{
+			Address:       "10.0.0.10:13141",
+			ClientAuth:    grpcapi.ClientAuthRequire,
+			TokenAuth:     grpcapi.TokenAuthRequire,
+			DefaultClient: "server",
+		},
+		{
+			Address:       "unix:///run/dirk/dirk.sock",
+			SocketMode:    os.FileMode(0660),
+			ClientAuth:    grpcapi.ClientAuthNone,
+			TokenAuth:     grpcapi.TokenAuthOptional,
+			DefaultClient: "server",
+		},
+		{
+			Address:       "unix:///run/dirk/admin.sock",
+			SocketMode:    os.FileMode(0640),
+			ClientAuth:    grpcapi.ClientAuthRequire,
+			TokenAuth:     grpcapi.TokenAuthOptional,
+			DefaultClient: "server",
+		},
+	}, listeners)
+
+	viper.Set("server.listeners", []interface{}{
+		map[interface{}]interface{}{
+			"address":     "127.0.0.1:13141",
+			"client-auth": "bad",
+		},
+	})
+	_, err = obtainListeners(grpcapi.ClientAuthRequire, grpcapi.TokenAuthNone)
+	require.EqualError(t, err, `invalid client auth policy for 127.0.0.1:13141: unrecognised client auth policy "bad"`)
+}
@@ -14,7 +14,10 @@
 package main
 
 import (
+	"fmt"
+	"io"
 	"os"
+	"strings"
 
 	"github.com/attestantio/dirk/util"
 	"github.com/pkg/errors"
@@ -26,6 +29,15 @@ import (
 // log.
 var log zerolog.Logger
 
+// logFile is the file to which logs are written, if any.
+var logFile *util.LogFile
+
+// logOutput is the output to which logs are written.
+var logOutput io.Writer
+
+// accessLogFile is the file to which the access log is written, if any.
+var accessLogFile *util.LogFile
+
 // initLogging initialises logging.
 func initLogging() error {
 	// We set the global logging level to trace, because if the global log level is higher than the
@@ -33,16 +45,75 @@ func initLogging() error {
 	zerolog.SetGlobalLevel(zerolog.TraceLevel)
 
 	// Change the output file.
+	var output io.Writer = os.Stderr
 	if viper.GetString("log-file") != "" {
-		f, err := os.OpenFile(resolvePath(viper.GetString("log-file")), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+		var err error
+		logFile, err = util.NewLogFile(resolvePath(viper.GetString("log-file")),
+			viper.GetInt("log-rotate.max-size-mb"),
+			viper.GetInt("log-rotate.max-age-days"),
+			viper.GetInt("log-rotate.max-backups"),
+		)
 		if err != nil {
 			return errors.Wrap(err, "failed to open log file")
 		}
-		zerologger.Logger = zerologger.Logger.Output(f)
+		output = logFile
 	}
 
+	// Change the output format.
+	switch strings.ToLower(viper.GetString("log-format")) {
+	case "", "json":
+		// JSON is zerolog's native format, with one object per line.
+	case "console":
+		output = zerolog.ConsoleWriter{
+			Out: output,
+			// Colours are only of use on a terminal.
+			NoColor: viper.GetString("log-file") != "",
+		}
+	default:
+		return fmt.Errorf("unrecognised log format %q", viper.GetString("log-format"))
+	}
+	logOutput = output
+	zerologger.Logger = zerologger.Logger.Output(output)
+
 	// Set the local logger from the global logger.
 	log = zerologger.Logger.With().Logger().Level(util.LogLevel(""))
 
 	return nil
 }
+
+// initAccessLog initialises the access log, returning its output.
+// It returns nil if the access log is not enabled.
+func initAccessLog() (io.Writer, error) {
+	if !viper.GetBool("server.access-log.enabled") {
+		return nil, nil
+	}
+	if viper.GetString("server.access-log.file") == "" {
+		// Access log entries are written alongside other logs.
+		return logOutput, nil
+	}
+	var err error
+	accessLogFile, err = util.NewLogFile(resolvePath(viper.GetString("server.access-log.file")),
+		viper.GetInt("log-rotate.max-size-mb"),
+		viper.GetInt("log-rotate.max-age-days"),
+		viper.GetInt("log-rotate.max-backups"),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open access log file")
+	}
+	return accessLogFile, nil
+}
+
+// reopenLogFile reopens the log file and access log file, if any, for use with external log rotation.
+func reopenLogFile() {
+	if logFile != nil {
+		if err := logFile.Reopen(); err != nil {
+			// Logs continue to be written to the previous file.
+			log.Warn().Err(err).Msg("Failed to reopen log file")
+		}
+	}
+	if accessLogFile != nil {
+		if err := accessLogFile.Reopen(); err != nil {
+			log.Warn().Err(err).Msg("Failed to reopen access log file")
+		}
+	}
+}
@@ -15,6 +15,7 @@ package main
 
 import (
 	"context"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"net/http"
@@ -34,17 +35,30 @@ import (
 	"github.com/attestantio/dirk/cmd"
 	"github.com/attestantio/dirk/core"
 	"github.com/attestantio/dirk/rules"
+	postgresrules "github.com/attestantio/dirk/rules/postgres"
 	standardrules "github.com/attestantio/dirk/rules/standard"
 	standardaccountmanager "github.com/attestantio/dirk/services/accountmanager/standard"
+	"github.com/attestantio/dirk/services/adminauth"
+	standardadminauth "github.com/attestantio/dirk/services/adminauth/standard"
 	grpcapi "github.com/attestantio/dirk/services/api/grpc"
+	"github.com/attestantio/dirk/services/authenticator"
+	jwtauthenticator "github.com/attestantio/dirk/services/authenticator/jwt"
+	"github.com/attestantio/dirk/services/chaintime"
+	standardchaintime "github.com/attestantio/dirk/services/chaintime/standard"
 	"github.com/attestantio/dirk/services/checker"
 	staticchecker "github.com/attestantio/dirk/services/checker/static"
 	"github.com/attestantio/dirk/services/fetcher"
 	memfetcher "github.com/attestantio/dirk/services/fetcher/mem"
+	"github.com/attestantio/dirk/services/health"
+	standardhealth "github.com/attestantio/dirk/services/health/standard"
+	"github.com/attestantio/dirk/services/hooks"
+	"github.com/attestantio/dirk/services/hooks/webhook"
 	"github.com/attestantio/dirk/services/lister"
 	standardlister "github.com/attestantio/dirk/services/lister/standard"
 	"github.com/attestantio/dirk/services/locker"
 	syncmaplocker "github.com/attestantio/dirk/services/locker/syncmap"
+	monitoredmajordomo "github.com/attestantio/dirk/services/majordomo/monitored"
+	vaultconfidant "github.com/attestantio/dirk/services/majordomo/vault"
 	"github.com/attestantio/dirk/services/metrics"
 	prometheusmetrics "github.com/attestantio/dirk/services/metrics/prometheus"
 	"github.com/attestantio/dirk/services/peers"
@@ -52,8 +66,10 @@ import (
 	standardprocess "github.com/attestantio/dirk/services/process/standard"
 	"github.com/attestantio/dirk/services/ruler"
 	goruler "github.com/attestantio/dirk/services/ruler/golang"
+	"github.com/attestantio/dirk/services/sender"
 	sendergrpc "github.com/attestantio/dirk/services/sender/grpc"
 	standardsigner "github.com/attestantio/dirk/services/signer/standard"
+	otlptracing "github.com/attestantio/dirk/services/tracing/otlp"
 	"github.com/attestantio/dirk/services/unlocker"
 	localunlocker "github.com/attestantio/dirk/services/unlocker/local"
 	standardwalletmanager "github.com/attestantio/dirk/services/walletmanager/standard"
@@ -78,6 +94,10 @@ import (
 // ReleaseVersion is the release version for the code.
 var ReleaseVersion = "1.1.0-pre-3"
 
+// GitCommit is the commit from which the code was built.  It can be set at build time with
+// -ldflags "-X main.GitCommit=<commit>"; if not set the commit recorded by the toolchain is used.
+var GitCommit = ""
+
 func main() {
 	ctx, cancel := context.WithCancel(context.Background())
 
@@ -101,7 +121,7 @@ func main() {
 		log.Fatal().Err(err).Msg("No server name set; cannot start")
 	}
 
-	logModules()
+	buildInfo := logModules()
 	log.Info().Str("version", ReleaseVersion).Msg("Starting dirk")
 
 	if err := initProfiling(); err != nil {
@@ -117,7 +137,10 @@ func main() {
 		defer closer.Close()
 	}
 
-	runtime.GOMAXPROCS(runtime.NumCPU() * 8)
+	if err := initGOMAXPROCS(); err != nil {
+		log.Error().Err(err).Msg("Failed to set GOMAXPROCS")
+		return
+	}
 
 	if err := e2types.InitBLS(); err != nil {
 		log.Error().Err(err).Msg("Failed to initialise BLS library")
@@ -133,14 +156,41 @@ func main() {
 		log.Error().Err(err).Msg("Failed to register metrics")
 		return
 	}
-	setRelease(ctx, ReleaseVersion)
+	setRelease(ctx, monitor, ReleaseVersion, buildInfo)
+
+	healthSvc, err := startHealth(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to start health service")
+		return
+	}
+	if healthSvc != nil {
+		onReady(healthSvc.SetReady)
+	}
 	setReady(ctx, false)
 
-	err = startServices(ctx, majordomo, monitor)
+	majordomo, err = monitorMajordomo(ctx, majordomo, monitor)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to monitor majordomo")
+		return
+	}
+
+	shutdownExport, err := initShutdownExport()
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to initialise slashing protection export on shutdown")
+		return
+	}
+
+	// The rules service has its own context, so that slashing protection remains
+	// available until any export on shutdown has completed.
+	rulesCtx, rulesCancel := context.WithCancel(context.Background())
+	defer rulesCancel()
+	api, rulesSvc, err := startServices(ctx, rulesCtx, majordomo, monitor)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to initialise services")
 		return
 	}
+	// The gRPC health service follows readiness.
+	onReady(api.SetServing)
 	setReady(ctx, true)
 
 	log.Info().Msg("All services operational")
@@ -159,6 +209,19 @@ func main() {
 	log.Info().Msg("Stopping dirk")
 	setReady(ctx, false)
 
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), viper.GetDuration("server.shutdown-timeout"))
+	drained, cancelled := api.Stop(shutdownCtx)
+	if cancelled > 0 {
+		log.Warn().Int("drained", drained).Int("cancelled", cancelled).Msg("Timed out waiting for in-flight requests to complete; remaining requests cancelled")
+	} else {
+		log.Info().Int("drained", drained).Int("cancelled", cancelled).Msg("In-flight requests completed")
+	}
+	if shutdownExport != nil {
+		exportSlashingProtectionOnShutdown(shutdownCtx, rulesSvc, shutdownExport)
+	}
+	shutdownCancel()
+	rulesCancel()
+
 	// Give services a chance to stop cleanly before we exit.
 	time.Sleep(2 * time.Second)
 }
@@ -168,15 +231,33 @@ func fetchConfig() error {
 	pflag.String("base-dir", "", "base directory for configuration files")
 	pflag.String("log-level", "info", "minimum level of messsages to log")
 	pflag.String("log-file", "", "redirect log output to a file")
+	pflag.String("log-format", "json", "format for log output (json or console)")
 	pflag.String("profile-address", "", "Address on which to run Go profile server")
 	pflag.String("tracing-address", "", "Address to which to send tracing data")
+	pflag.Int("gomaxprocs", 0, "value for GOMAXPROCS (default 8 times the number of available CPUs)")
+	pflag.String("tracing-type", "jaeger", "Type of tracing to use (jaeger or otlp)")
 	pflag.Bool("show-certificates", false, "show server certificates and exit")
 	pflag.Bool("show-permissions", false, "show client permissions and exit")
+	pflag.String("permissions-format", "text", "format for show-permissions (text, json or yaml)")
 	pflag.Bool("version", false, "show Dirk version exit")
 	pflag.Bool("export-slashing-protection", false, "export slashing protection data and exit")
 	pflag.Bool("import-slashing-protection", false, "import slashing protection data and exit")
+	pflag.Bool("migrate-slashing-protection", false, "migrate slashing protection data from local storage to SQLite storage and exit")
+	pflag.String("interchange-format", "minimal", "interchange format for slashing protection export (minimal or complete)")
 	pflag.String("genesis-validators-root", "", "genesis validators root required for slashing protection import or export")
+	pflag.Bool("import-dry-run", false, "report the changes that a slashing protection import would make without making them")
+	pflag.String("validators", "", "public keys of validators for which to import slashing protection, as a comma-separated list or a file with one per line")
 	pflag.String("slashing-protection-file", "", "location of slashing protection file for import or export")
+	pflag.String("export-wallet", "", "export the named wallet to the wallet backup file and exit")
+	pflag.Bool("import-wallet", false, "import a wallet from the wallet backup file and exit")
+	pflag.String("wallet-backup-file", "", "location of wallet backup file for import or export")
+	pflag.String("wallet-backup-passphrase", "", "passphrase with which to encrypt or decrypt the wallet backup file, as a majordomo URL")
+	pflag.String("rotate-passphrase", "", "re-encrypt the named wallet or account (in the form wallet/account) with the new passphrase and exit")
+	pflag.String("old-passphrase", "", "existing passphrase of the wallet or account for passphrase rotation, as a majordomo URL")
+	pflag.String("new-passphrase", "", "new passphrase of the wallet or account for passphrase rotation, as a majordomo URL")
+	pflag.Bool("verify-stores", false, "verify that all accounts in the stores can be loaded and exit")
+	pflag.Bool("verify-stores-decrypt", false, "also verify that all accounts can be decrypted with the unlocker passphrases when verifying stores")
+	pflag.Bool("validate-config", false, "validate the configuration, reporting any problems, and exit")
 	pflag.Parse()
 	if err := viper.BindPFlags(pflag.CommandLine); err != nil {
 		return errors.Wrap(err, "failed to bind pflags to viper")
@@ -203,6 +284,16 @@ func fetchConfig() error {
 
 	// Defaults.
 	viper.SetDefault("storage-path", "storage")
+	viper.SetDefault("server.token-auth.claim", "sub")
+	viper.SetDefault("process.operation-timeout", 30*time.Second)
+	viper.SetDefault("process.resharing.state-path", "resharing")
+	viper.SetDefault("server.rules.storage-type", "badger")
+	viper.SetDefault("server.rules.reconnect-interval", time.Second)
+	viper.SetDefault("server.rules.max-reconnect-interval", time.Minute)
+	viper.SetDefault("server.shutdown-timeout", 30*time.Second)
+	viper.SetDefault("certificates.expiry-warning", 14*24*time.Hour)
+	viper.SetDefault("server.admin.nonce-lifetime", time.Minute)
+	viper.SetDefault("auth.clock-skew", 30*time.Second)
 
 	if err := viper.ReadInConfig(); err != nil {
 		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
@@ -229,8 +320,40 @@ func initProfiling() error {
 	return nil
 }
 
+// initGOMAXPROCS sets GOMAXPROCS.  An explicit gomaxprocs is used as-is,
+// otherwise it is a multiple of the CPUs available to the process.
+func initGOMAXPROCS() error {
+	gomaxprocs := viper.GetInt("gomaxprocs")
+	if gomaxprocs < 0 {
+		return fmt.Errorf("invalid gomaxprocs %d", gomaxprocs)
+	}
+	cpus := util.AvailableCPUs()
+	source := "default"
+	if gomaxprocs == 0 {
+		gomaxprocs = cpus * 8
+	} else {
+		source = "configuration"
+	}
+	runtime.GOMAXPROCS(gomaxprocs)
+	log.Info().Int("gomaxprocs", runtime.GOMAXPROCS(0)).Int("available_cpus", cpus).Str("source", source).Msg("Set GOMAXPROCS")
+
+	return nil
+}
+
 // initTracing initialises the tracing.
 func initTracing() (io.Closer, error) {
+	switch strings.ToLower(viper.GetString("tracing-type")) {
+	case "", "jaeger":
+		return initJaegerTracing()
+	case "otlp":
+		return initOTLPTracing()
+	default:
+		return nil, fmt.Errorf("unknown tracing type %q", viper.GetString("tracing-type"))
+	}
+}
+
+// initJaegerTracing initialises tracing with a Jaeger agent.
+func initJaegerTracing() (io.Closer, error) {
 	tracingAddress := viper.GetString("tracing-address")
 	if tracingAddress == "" {
 		return nil, nil
@@ -257,6 +380,27 @@ func initTracing() (io.Closer, error) {
 	return closer, nil
 }
 
+// initOTLPTracing initialises tracing with an OpenTelemetry collector.
+// The address can also be supplied with the standard OTEL_EXPORTER_OTLP_ENDPOINT
+// environment variable.
+func initOTLPTracing() (io.Closer, error) {
+	tracingAddress := viper.GetString("tracing-address")
+	if tracingAddress == "" && os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") == "" && os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") == "" {
+		return nil, nil
+	}
+	tracer, err := otlptracing.New(context.Background(),
+		otlptracing.WithLogLevel(util.LogLevel("tracing")),
+		otlptracing.WithAddress(tracingAddress),
+		otlptracing.WithServiceName("dirk"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	opentracing.SetGlobalTracer(tracer)
+
+	return tracer, nil
+}
+
 func runCommands(ctx context.Context, majordomo majordomo.Service) {
 	if viper.GetBool("version") {
 		fmt.Printf("%s\n", ReleaseVersion)
@@ -272,71 +416,107 @@ func runCommands(ctx context.Context, majordomo majordomo.Service) {
 	}
 
 	if viper.GetBool("show-permissions") {
-		permissionsCfg := viper.GetStringMap("permissions")
-		permissions := make(map[string][]*checker.Permissions)
-		for client := range permissionsCfg {
-			perms := viper.GetStringMapStringSlice(fmt.Sprintf("permissions.%s", client))
-			permissions[client] = make([]*checker.Permissions, 0, len(perms))
-			for path, operations := range perms {
-				permissions[client] = append(permissions[client], &checker.Permissions{
-					Path:       path,
-					Operations: operations,
-				})
-			}
+		permissions, err := obtainPermissions()
+		if err != nil {
+			log.Fatal().Err(err).Msg("show-permissions failed")
+		}
+		if format := viper.GetString("permissions-format"); format == "" || strings.EqualFold(format, "text") {
+			checker.DumpPermissions(permissions)
+		} else if err := checker.WritePermissions(os.Stdout, permissions, format); err != nil {
+			log.Fatal().Err(err).Msg("show-permissions failed")
 		}
-		checker.DumpPermissions(permissions)
 		os.Exit(0)
 	}
 
 	if viper.GetBool("export-slashing-protection") {
-		exportSlashingProtection(ctx)
+		exportSlashingProtection(ctx, majordomo)
 	}
 
 	if viper.GetBool("import-slashing-protection") {
-		importSlashingProtection(ctx)
+		importSlashingProtection(ctx, majordomo)
+	}
+
+	if viper.GetBool("migrate-slashing-protection") {
+		migrateSlashingProtection(ctx)
+	}
+
+	if viper.GetString("export-wallet") != "" {
+		exportWallet(ctx, majordomo)
+	}
+
+	if viper.GetBool("import-wallet") {
+		importWallet(ctx, majordomo)
+	}
+
+	if viper.GetString("rotate-passphrase") != "" {
+		rotatePassphrase(ctx, majordomo)
+	}
+
+	if viper.GetBool("verify-stores") {
+		verifyStores(ctx, majordomo)
+	}
+
+	if viper.GetBool("validate-config") {
+		validateConfig(ctx, majordomo)
 	}
 }
 
-func startServices(ctx context.Context, majordomo majordomo.Service, monitor metrics.Service) error {
-	var err error
+func startServices(ctx context.Context, rulesCtx context.Context, majordomo majordomo.Service, monitor metrics.Service) (*grpcapi.Service, rules.Service, error) {
+	// TLS settings are checked first so that bad values are reported before any service starts.
+	tlsMinVersion, err := util.TLSVersion(viper.GetString("server.tls.min-version"))
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "invalid server.tls.min-version")
+	}
+	tlsCipherSuites, err := util.TLSCipherSuites(viper.GetStringSlice("server.tls.cipher-suites"))
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "invalid server.tls.cipher-suites")
+	}
+
+	stores, err := initStores(ctx, majordomo)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	stores, err := initStores(ctx)
+	chainTime, err := startChainTime(ctx)
 	if err != nil {
-		return err
+		return nil, nil, errors.Wrap(err, "failed to initialise chain time")
 	}
 
 	unlocker, err := startUnlocker(ctx, majordomo, monitor)
 	if err != nil {
-		return errors.Wrap(err, "failed to initialise local unlocker")
+		return nil, nil, errors.Wrap(err, "failed to initialise local unlocker")
 	}
 
 	checker, err := startChecker(ctx, monitor)
 	if err != nil {
-		return errors.Wrap(err, "failed to start permissions checker")
+		return nil, nil, errors.Wrap(err, "failed to start permissions checker")
 	}
 
 	// Set up the fetcher.
 	fetcher, err := startFetcher(ctx, stores, monitor)
 	if err != nil {
-		return errors.Wrap(err, "failed to initialise account fetcher")
+		return nil, nil, errors.Wrap(err, "failed to initialise account fetcher")
+	}
+	if err := eagerUnlock(ctx, fetcher, unlocker); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to unlock accounts")
 	}
 
 	// Set up the locker.
 	locker, err := startLocker(ctx, monitor)
 	if err != nil {
-		return errors.Wrap(err, "failed to set up locker service")
+		return nil, nil, errors.Wrap(err, "failed to set up locker service")
 	}
 
 	// Set up the ruler.
-	ruler, err := startRuler(ctx, locker, monitor)
+	ruler, rulesSvc, err := startRuler(ctx, rulesCtx, majordomo, locker, monitor, chainTime)
 	if err != nil {
-		return errors.Wrap(err, "failed to set up ruler service")
+		return nil, nil, errors.Wrap(err, "failed to set up ruler service")
 	}
 
 	// Set up the lister.
 	lister, err := startLister(ctx, monitor, fetcher, checker, ruler)
 	if err != nil {
-		return errors.Wrap(err, "failed to initialise lister")
+		return nil, nil, errors.Wrap(err, "failed to initialise lister")
 	}
 
 	// Set up the signer.
@@ -351,34 +531,23 @@ func startServices(ctx context.Context, majordomo majordomo.Service, monitor met
 		standardsigner.WithChecker(checker),
 		standardsigner.WithFetcher(fetcher),
 		standardsigner.WithRuler(ruler),
+		standardsigner.WithMaxConcurrent(viper.GetInt("signer.max-concurrent")),
+		standardsigner.WithQueueTimeout(viper.GetDuration("signer.queue-timeout")),
+		standardsigner.WithMessageTags(viper.GetStringMapStringSlice("signer.message-tags")),
+		standardsigner.WithDeduplicate(viper.GetBool("signer.deduplicate")),
+		standardsigner.WithDomains(viper.GetStringMapString("signer.domains")),
 	)
 	if err != nil {
-		return errors.Wrap(err, "failed to create signer service")
-	}
-
-	peers, err := startPeers(ctx, monitor)
-	if err != nil {
-		return errors.Wrap(err, "failed to start peers service")
+		return nil, nil, errors.Wrap(err, "failed to create signer service")
 	}
 
 	var senderMonitor metrics.SenderMonitor
 	if monitor, isMonitor := monitor.(metrics.SenderMonitor); isMonitor {
 		senderMonitor = monitor
 	}
-	certPEMBlock, err := majordomo.Fetch(ctx, viper.GetString("certificates.server-cert"))
+	certPEMBlock, keyPEMBlock, caPEMBlock, err := fetchCertificates(ctx, majordomo)
 	if err != nil {
-		return errors.Wrap(err, "failed to obtain server certificate")
-	}
-	keyPEMBlock, err := majordomo.Fetch(ctx, viper.GetString("certificates.server-key"))
-	if err != nil {
-		return errors.Wrap(err, "failed to obtain server key")
-	}
-	var caPEMBlock []byte
-	if viper.GetString("certificates.ca-cert") != "" {
-		caPEMBlock, err = majordomo.Fetch(ctx, viper.GetString("certificates.ca-cert"))
-		if err != nil {
-			return errors.Wrap(err, "failed to obtain client CA certificate")
-		}
+		return nil, nil, err
 	}
 	sender, err := sendergrpc.New(ctx,
 		sendergrpc.WithLogLevel(util.LogLevel("sender")),
@@ -387,18 +556,28 @@ func startServices(ctx context.Context, majordomo majordomo.Service, monitor met
 		sendergrpc.WithServerCert(certPEMBlock),
 		sendergrpc.WithServerKey(keyPEMBlock),
 		sendergrpc.WithCACert(caPEMBlock),
+		sendergrpc.WithTLSMinVersion(tlsMinVersion),
+		sendergrpc.WithTLSCipherSuites(tlsCipherSuites),
 	)
 	if err != nil {
-		return errors.Wrap(err, "failed to create sender service")
+		return nil, nil, errors.Wrap(err, "failed to create sender service")
+	}
+
+	peers, err := startPeers(ctx, monitor, sender)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to start peers service")
 	}
 
 	serverID, err := strconv.ParseUint(viper.GetString("server.id"), 10, 64)
 	if err != nil {
-		return errors.Wrap(err, "failed to obtain server ID")
+		return nil, nil, errors.Wrap(err, "failed to obtain server ID")
 	}
 
 	endpoints := make(map[uint64]string)
 	for k, v := range viper.GetStringMapString("peers") {
+		if k == "probe-interval" {
+			continue
+		}
 		peerID, err := strconv.ParseUint(k, 10, 64)
 		if err != nil {
 			log.Error().Err(err).Str("peer_id", k).Msg("Invalid peer ID")
@@ -415,9 +594,27 @@ func startServices(ctx context.Context, majordomo majordomo.Service, monitor met
 	if viper.GetString("process.generation-passphrase") != "" {
 		generationPassphrase, err = majordomo.Fetch(ctx, viper.GetString("process.generation-passphrase"))
 		if err != nil {
-			return errors.Wrap(err, "failed to obtain account generation passphrase for process")
+			return nil, nil, errors.Wrap(err, "failed to obtain account generation passphrase for process")
+		}
+	}
+	var generationSeed []byte
+	if viper.GetString("process.generation.seed") != "" {
+		generationSeed, err = majordomo.Fetch(ctx, viper.GetString("process.generation.seed"))
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "failed to obtain generation seed for process")
+		}
+	}
+	var generationWalletPassphrase []byte
+	if viper.GetString("process.generation.wallet-passphrase") != "" {
+		generationWalletPassphrase, err = majordomo.Fetch(ctx, viper.GetString("process.generation.wallet-passphrase"))
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "failed to obtain generation wallet passphrase for process")
 		}
 	}
+	accountHook, err := startAccountHook(ctx, majordomo)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to start account creation hook")
+	}
 	process, err := standardprocess.New(ctx,
 		standardprocess.WithLogLevel(util.LogLevel("process")),
 		standardprocess.WithMonitor(processMonitor),
@@ -429,15 +626,28 @@ func startServices(ctx context.Context, majordomo majordomo.Service, monitor met
 		standardprocess.WithID(serverID),
 		standardprocess.WithStores(stores),
 		standardprocess.WithGenerationPassphrase(generationPassphrase),
+		standardprocess.WithGenerationWalletType(viper.GetString("process.generation.wallet-type")),
+		standardprocess.WithGenerationPathTemplate(viper.GetString("process.generation.path-template")),
+		standardprocess.WithGenerationSeed(generationSeed),
+		standardprocess.WithGenerationWalletPassphrase(generationWalletPassphrase),
+		standardprocess.WithOperationTimeout(viper.GetDuration("process.operation-timeout")),
+		standardprocess.WithDKGRoundTimeout(viper.GetDuration("process.dkg.round-timeout")),
+		standardprocess.WithDKGMaxRetries(viper.GetUint32("process.dkg.max-retries")),
+		standardprocess.WithAccountHook(accountHook),
+		standardprocess.WithResharingStatePath(resolvePath(viper.GetString("process.resharing.state-path"))),
 	)
 	if err != nil {
-		return errors.Wrap(err, "failed to create process service")
+		return nil, nil, errors.Wrap(err, "failed to create process service")
 	}
 
 	var accountManagerMonitor metrics.AccountManagerMonitor
 	if monitor, isMonitor := monitor.(metrics.AccountManagerMonitor); isMonitor {
 		accountManagerMonitor = monitor
 	}
+	var slashingProtectionPurger rules.SlashingProtectionPurger
+	if purger, isPurger := rulesSvc.(rules.SlashingProtectionPurger); isPurger {
+		slashingProtectionPurger = purger
+	}
 	accountManager, err := standardaccountmanager.New(ctx,
 		standardaccountmanager.WithLogLevel(util.LogLevel("accountmanager")),
 		standardaccountmanager.WithMonitor(accountManagerMonitor),
@@ -446,9 +656,12 @@ func startServices(ctx context.Context, majordomo majordomo.Service, monitor met
 		standardaccountmanager.WithFetcher(fetcher),
 		standardaccountmanager.WithRuler(ruler),
 		standardaccountmanager.WithProcess(process),
+		standardaccountmanager.WithSlashingProtectionPurger(slashingProtectionPurger),
+		standardaccountmanager.WithGenerationNameTemplate(viper.GetString("accountmanager.generation.name-template")),
+		standardaccountmanager.WithGenerationPathTemplate(viper.GetString("accountmanager.generation.path-template")),
 	)
 	if err != nil {
-		return errors.Wrap(err, "failed to create account manager service")
+		return nil, nil, errors.Wrap(err, "failed to create account manager service")
 	}
 
 	var walletManagerMonitor metrics.WalletManagerMonitor
@@ -464,15 +677,53 @@ func startServices(ctx context.Context, majordomo majordomo.Service, monitor met
 		standardwalletmanager.WithRuler(ruler),
 	)
 	if err != nil {
-		return errors.Wrap(err, "failed to create wallet manager service")
+		return nil, nil, errors.Wrap(err, "failed to create wallet manager service")
 	}
 
 	// Initialise the API service.
+	clientAuth, err := grpcapi.ParseClientAuth(viper.GetString("server.client-auth"))
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "invalid client auth policy")
+	}
+	tokenAuth, err := grpcapi.ParseTokenAuth(viper.GetString("server.token-auth.mode"))
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "invalid token auth policy")
+	}
+	listeners, err := obtainListeners(clientAuth, tokenAuth)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "invalid listeners")
+	}
+	var tokenAuthenticator authenticator.Service
+	if tokenAuthRequired(tokenAuth, listeners) {
+		tokenAuthenticator, err = startAuthenticator(ctx, majordomo)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "failed to create token authenticator")
+		}
+	}
+	var adminAuth adminauth.Service
+	if len(viper.GetStringSlice("server.admin.public-keys")) > 0 {
+		adminAuth, err = standardadminauth.New(ctx,
+			standardadminauth.WithLogLevel(util.LogLevel("adminauth")),
+			standardadminauth.WithPublicKeys(viper.GetStringSlice("server.admin.public-keys")),
+			standardadminauth.WithNonceLifetime(viper.GetDuration("server.admin.nonce-lifetime")),
+		)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "failed to create admin auth service")
+		}
+	}
+	rateLimit, clientRateLimits, err := obtainRateLimits()
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "invalid rate limits")
+	}
+	accessLog, err := initAccessLog()
+	if err != nil {
+		return nil, nil, err
+	}
 	var apiMonitor metrics.APIMonitor
 	if monitor, isMonitor := monitor.(metrics.APIMonitor); isMonitor {
 		apiMonitor = monitor
 	}
-	_, err = grpcapi.New(ctx,
+	api, err := grpcapi.New(ctx,
 		grpcapi.WithLogLevel(util.LogLevel("api")),
 		grpcapi.WithMonitor(apiMonitor),
 		grpcapi.WithSigner(signer),
@@ -486,13 +737,115 @@ func startServices(ctx context.Context, majordomo majordomo.Service, monitor met
 		grpcapi.WithServerCert(certPEMBlock),
 		grpcapi.WithServerKey(keyPEMBlock),
 		grpcapi.WithCACert(caPEMBlock),
-		grpcapi.WithListenAddress(viper.GetString("server.listen-address")),
+		grpcapi.WithListenAddresses(viper.GetStringSlice("server.listen-address")),
+		grpcapi.WithClientAuth(clientAuth),
+		grpcapi.WithDefaultClient(viper.GetString("server.default-client")),
+		grpcapi.WithListeners(listeners),
+		grpcapi.WithProxyProtocol(viper.GetBool("server.proxy-protocol")),
+		grpcapi.WithTrustedProxies(viper.GetStringSlice("server.trusted-proxies")),
+		grpcapi.WithListenBacklog(viper.GetInt("server.listen-backlog")),
+		grpcapi.WithReusePort(viper.GetBool("server.reuse-port")),
+		grpcapi.WithReusePortListeners(viper.GetInt("server.reuse-port-listeners")),
+		grpcapi.WithClockSkew(viper.GetDuration("auth.clock-skew")),
+		grpcapi.WithCertificateExpiryWarning(viper.GetDuration("certificates.expiry-warning")),
+		grpcapi.WithTLSMinVersion(tlsMinVersion),
+		grpcapi.WithTLSCipherSuites(tlsCipherSuites),
+		grpcapi.WithTokenAuth(tokenAuth),
+		grpcapi.WithAuthenticator(tokenAuthenticator),
+		grpcapi.WithAdminAuth(adminAuth),
+		grpcapi.WithAdminIPs(viper.GetStringSlice("server.rules.admin-ips")),
+		grpcapi.WithAdminAllowAll(viper.GetBool("server.rules.admin-allow-all")),
+		grpcapi.WithRateLimit(rateLimit),
+		grpcapi.WithClientRateLimits(clientRateLimits),
+		grpcapi.WithAccessLog(accessLog),
+		grpcapi.WithDenialDetails(viper.GetBool("server.denial-details")),
 	)
 	if err != nil {
-		return errors.Wrap(err, "failed to create API service")
+		return nil, nil, errors.Wrap(err, "failed to create API service")
 	}
+	go reloadOnSignal(ctx, majordomo, api, checker, lister, viper.GetDuration("certificates.reload-interval"))
 
-	return nil
+	return api, rulesSvc, nil
+}
+
+// listenerConfig is the configuration of an entry in server.listeners.
+type listenerConfig struct {
+	Address       string      `mapstructure:"address"`
+	ClientAuth    string      `mapstructure:"client-auth"`
+	TokenAuth     string      `mapstructure:"token-auth"`
+	DefaultClient string      `mapstructure:"default-client"`
+	SocketMode    os.FileMode `mapstructure:"socket-mode"`
+}
+
+// obtainListeners obtains the listeners with their own authentication policies
+// from the configuration.  Policies that are not set for a listener are taken
+// from the server-wide configuration.
+func obtainListeners(clientAuth grpcapi.ClientAuth, tokenAuth grpcapi.TokenAuth) ([]*grpcapi.Listener, error) {
+	configs := make([]*listenerConfig, 0)
+	if err := viper.UnmarshalKey("server.listeners", &configs); err != nil {
+		return nil, errors.Wrap(err, "failed to parse server.listeners")
+	}
+
+	listeners := make([]*grpcapi.Listener, 0, len(configs))
+	for _, config := range configs {
+		listener := &grpcapi.Listener{
+			Address:       config.Address,
+			ClientAuth:    clientAuth,
+			TokenAuth:     tokenAuth,
+			DefaultClient: viper.GetString("server.default-client"),
+			SocketMode:    config.SocketMode,
+		}
+		if config.ClientAuth != "" {
+			var err error
+			listener.ClientAuth, err = grpcapi.ParseClientAuth(config.ClientAuth)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid client auth policy for %s", config.Address)
+			}
+		}
+		if config.TokenAuth != "" {
+			var err error
+			listener.TokenAuth, err = grpcapi.ParseTokenAuth(config.TokenAuth)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid token auth policy for %s", config.Address)
+			}
+		}
+		if config.DefaultClient != "" {
+			listener.DefaultClient = config.DefaultClient
+		}
+		listeners = append(listeners, listener)
+	}
+	return listeners, nil
+}
+
+// tokenAuthRequired returns true if any listener checks bearer tokens.
+func tokenAuthRequired(tokenAuth grpcapi.TokenAuth, listeners []*grpcapi.Listener) bool {
+	if tokenAuth != grpcapi.TokenAuthNone && len(viper.GetStringSlice("server.listen-address")) > 0 {
+		return true
+	}
+	for _, listener := range listeners {
+		if listener.TokenAuth != grpcapi.TokenAuthNone {
+			return true
+		}
+	}
+	return false
+}
+
+// obtainRateLimits obtains the default and per-client rate limits from the configuration.
+func obtainRateLimits() (float64, map[string]float64, error) {
+	defaultLimit := 0.0
+	clientLimits := make(map[string]float64)
+	for client, limit := range viper.GetStringMapString("server.rate-limits") {
+		rate, err := grpcapi.ParseRateLimit(limit)
+		if err != nil {
+			return 0, nil, errors.Wrapf(err, "invalid rate limit for %s", client)
+		}
+		if client == "default" {
+			defaultLimit = rate
+			continue
+		}
+		clientLimits[client] = rate
+	}
+	return defaultLimit, clientLimits, nil
 }
 
 func initMajordomo(ctx context.Context) (majordomo.Service, error) {
@@ -537,9 +890,96 @@ func initMajordomo(ctx context.Context) (majordomo.Service, error) {
 		}
 	}
 
+	if viper.GetString("majordomo.vault.address") != "" {
+		vaultConfidant, err := initVaultConfidant(ctx, majordomo)
+		if err != nil {
+			return nil, err
+		}
+		if err := majordomo.RegisterConfidant(ctx, vaultConfidant); err != nil {
+			return nil, errors.Wrap(err, "failed to register Vault confidant")
+		}
+	}
+
 	return majordomo, nil
 }
 
+// initVaultConfidant creates the Vault confidant.  Its credentials are
+// themselves majordomo URLs, fetched with the confidants already registered.
+func initVaultConfidant(ctx context.Context, majordomo majordomo.Service) (*vaultconfidant.Service, error) {
+	params := []vaultconfidant.Parameter{
+		vaultconfidant.WithLogLevel(util.LogLevel("majordomo.confidants.vault")),
+		vaultconfidant.WithAddress(viper.GetString("majordomo.vault.address")),
+		vaultconfidant.WithNamespace(viper.GetString("majordomo.vault.namespace")),
+	}
+	if viper.GetString("majordomo.vault.token") != "" {
+		token, err := majordomo.Fetch(ctx, viper.GetString("majordomo.vault.token"))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to obtain Vault token")
+		}
+		params = append(params, vaultconfidant.WithToken(strings.TrimSpace(string(token))))
+	}
+	if viper.GetString("majordomo.vault.role-id") != "" {
+		roleID, err := majordomo.Fetch(ctx, viper.GetString("majordomo.vault.role-id"))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to obtain Vault AppRole role ID")
+		}
+		secretID, err := majordomo.Fetch(ctx, viper.GetString("majordomo.vault.secret-id"))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to obtain Vault AppRole secret ID")
+		}
+		params = append(params, vaultconfidant.WithAppRole(strings.TrimSpace(string(roleID)), strings.TrimSpace(string(secretID))))
+	}
+	if viper.GetString("majordomo.vault.approle-mount") != "" {
+		params = append(params, vaultconfidant.WithAppRoleMount(viper.GetString("majordomo.vault.approle-mount")))
+	}
+	switch viper.GetString("majordomo.vault.auth") {
+	case "", "token", "approle":
+		// Selected by the credentials supplied.
+	case "kubernetes":
+		if viper.GetString("majordomo.vault.kubernetes-role") == "" {
+			return nil, errors.New("majordomo.vault.kubernetes-role is required for Kubernetes authentication")
+		}
+		params = append(params, vaultconfidant.WithKubernetesRole(viper.GetString("majordomo.vault.kubernetes-role")))
+		if viper.GetString("majordomo.vault.kubernetes-mount") != "" {
+			params = append(params, vaultconfidant.WithKubernetesMount(viper.GetString("majordomo.vault.kubernetes-mount")))
+		}
+		if viper.GetString("majordomo.vault.kubernetes-token-path") != "" {
+			params = append(params, vaultconfidant.WithKubernetesTokenPath(viper.GetString("majordomo.vault.kubernetes-token-path")))
+		}
+	default:
+		return nil, fmt.Errorf("unknown Vault authentication method %q", viper.GetString("majordomo.vault.auth"))
+	}
+	if viper.GetString("majordomo.vault.ca-cert") != "" {
+		caCert, err := majordomo.Fetch(ctx, viper.GetString("majordomo.vault.ca-cert"))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to obtain Vault CA certificate")
+		}
+		params = append(params, vaultconfidant.WithCACert(caCert))
+	}
+	if viper.GetString("majordomo.vault.timeout") != "" {
+		params = append(params, vaultconfidant.WithTimeout(viper.GetDuration("majordomo.vault.timeout")))
+	}
+
+	vaultConfidant, err := vaultconfidant.New(ctx, params...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create Vault confidant")
+	}
+	return vaultConfidant, nil
+}
+
+// monitorMajordomo wraps majordomo to report fetch metrics, if the monitor supports them.
+func monitorMajordomo(ctx context.Context, majordomo majordomo.Service, monitor metrics.Service) (majordomo.Service, error) {
+	majordomoMonitor, isMonitor := monitor.(metrics.MajordomoMonitor)
+	if !isMonitor {
+		return majordomo, nil
+	}
+	return monitoredmajordomo.New(ctx,
+		monitoredmajordomo.WithLogLevel(util.LogLevel("majordomo")),
+		monitoredmajordomo.WithMonitor(majordomoMonitor),
+		monitoredmajordomo.WithMajordomo(majordomo),
+	)
+}
+
 func startMonitor(ctx context.Context) (metrics.Service, error) {
 	log.Trace().Msg("Starting metrics service")
 	var monitor metrics.Service
@@ -551,6 +991,9 @@ func startMonitor(ctx context.Context) (metrics.Service, error) {
 	monitor, err = prometheusmetrics.New(ctx,
 		prometheusmetrics.WithLogLevel(util.LogLevel("metrics")),
 		prometheusmetrics.WithAddress(viper.GetString("metrics.listen-address")),
+		prometheusmetrics.WithMaxScrapesInFlight(viper.GetInt("metrics.max-scrapes-in-flight")),
+		prometheusmetrics.WithScrapeTimeout(viper.GetDuration("metrics.scrape-timeout")),
+		prometheusmetrics.WithMaxSeriesPerMetric(viper.GetInt("metrics.max-series-per-metric")),
 	)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to start metrics service")
@@ -558,36 +1001,179 @@ func startMonitor(ctx context.Context) (metrics.Service, error) {
 	return monitor, nil
 }
 
-func logModules() {
+// startHealth starts the health service, which serves liveness and readiness endpoints.
+func startHealth(ctx context.Context) (health.Service, error) {
+	address := viper.GetString("health.listen-address")
+	if address == "" && viper.GetString("metrics.listen-address") == "" {
+		log.Debug().Msg("No health or metrics listen address supplied; health service not starting")
+		return nil, nil
+	}
+	log.Trace().Msg("Starting health service")
+	healthSvc, err := standardhealth.New(ctx,
+		standardhealth.WithLogLevel(util.LogLevel("health")),
+		standardhealth.WithAddress(address),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return healthSvc, nil
+}
+
+// logModules logs the modules that make up the binary, returning its build information if available.
+func logModules() *debug.BuildInfo {
 	buildInfo, ok := debug.ReadBuildInfo()
-	if ok {
-		log.Trace().Str("path", buildInfo.Path).Msg("Main package")
-		for _, dep := range buildInfo.Deps {
-			log := log.Trace()
-			if dep.Replace == nil {
-				log = log.Str("path", dep.Path).Str("version", dep.Version)
-			} else {
-				log = log.Str("path", dep.Replace.Path).Str("version", dep.Replace.Version)
-			}
-			log.Msg("Dependency")
+	if !ok {
+		return nil
+	}
+	log.Trace().Str("path", buildInfo.Path).Msg("Main package")
+	for _, dep := range buildInfo.Deps {
+		log := log.Trace()
+		if dep.Replace == nil {
+			log = log.Str("path", dep.Path).Str("version", dep.Version)
+		} else {
+			log = log.Str("path", dep.Replace.Path).Str("version", dep.Replace.Version)
 		}
+		log.Msg("Dependency")
 	}
+	return buildInfo
 }
 
 // initRules initialises a rules service.
-func initRules(ctx context.Context) (rules.Service, error) {
-	return standardrules.New(ctx,
+func initRules(ctx context.Context, majordomo majordomo.Service, monitor metrics.Service, extraParams ...standardrules.Parameter) (rules.Service, error) {
+	var rulesMonitor metrics.RulesMonitor
+	if monitor, isMonitor := monitor.(metrics.RulesMonitor); isMonitor {
+		rulesMonitor = monitor
+	}
+	blsToExecutionChangeValidators := make([]uint64, 0)
+	for _, validator := range viper.GetStringSlice("server.rules.bls-to-execution-change-validators") {
+		index, err := strconv.ParseUint(validator, 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid BLS to execution change validator index %q", validator)
+		}
+		blsToExecutionChangeValidators = append(blsToExecutionChangeValidators, index)
+	}
+	registrationFeeRecipients := make([][]byte, 0)
+	for _, feeRecipient := range viper.GetStringSlice("server.rules.validator-registration.fee-recipients") {
+		address, err := hex.DecodeString(strings.TrimPrefix(feeRecipient, "0x"))
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid validator registration fee recipient %q", feeRecipient)
+		}
+		registrationFeeRecipients = append(registrationFeeRecipients, address)
+	}
+	validatorAllowList, err := obtainValidatorAllowList(ctx, majordomo)
+	if err != nil {
+		return nil, err
+	}
+	storage, err := initRulesStorage(ctx, majordomo)
+	if err != nil {
+		return nil, err
+	}
+	params := []standardrules.Parameter{
 		standardrules.WithLogLevel(util.LogLevel("rules")),
+		standardrules.WithMonitor(rulesMonitor),
+		standardrules.WithStorage(storage),
+		standardrules.WithStorageType(viper.GetString("server.rules.storage-type")),
 		standardrules.WithStoragePath(resolvePath(viper.GetString("storage-path"))),
 		standardrules.WithAdminIPs(viper.GetStringSlice("server.rules.admin-ips")),
-	)
+		standardrules.WithAdminAllowAll(viper.GetBool("server.rules.admin-allow-all")),
+		standardrules.WithReconnectInterval(viper.GetDuration("server.rules.reconnect-interval")),
+		standardrules.WithMaxReconnectInterval(viper.GetDuration("server.rules.max-reconnect-interval")),
+		standardrules.WithMinAttestationGap(viper.GetUint64("server.rules.min-attestation-gap")),
+		standardrules.WithBLSToExecutionChangeValidators(blsToExecutionChangeValidators),
+		standardrules.WithValidatorAllowList(validatorAllowList),
+		standardrules.WithMaxRegistrationGasLimit(viper.GetUint64("server.rules.validator-registration.max-gas-limit")),
+		standardrules.WithRegistrationFeeRecipients(registrationFeeRecipients),
+	}
+	params = append(params, extraParams...)
+	return standardrules.New(ctx, params...)
 }
 
-func initStores(ctx context.Context) ([]e2wtypes.Store, error) {
+// obtainValidatorAllowList obtains the public keys of the validators for which signing is permitted.
+// It returns nil if no allow-list is configured.
+func obtainValidatorAllowList(ctx context.Context, majordomo majordomo.Service) ([][]byte, error) {
+	location := viper.GetString("server.rules.validator-allow-list")
+	if location == "" {
+		return nil, nil
+	}
+	data, err := majordomo.Fetch(ctx, location)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to obtain validator allow-list")
+	}
+	entries := publicKeyLines(data)
+	if len(entries) == 0 {
+		// An empty list would otherwise permit every validator.
+		return nil, errors.New("validator allow-list contains no validators")
+	}
+	pubKeys := make([][]byte, 0, len(entries))
+	for _, entry := range entries {
+		key, err := parsePublicKey(entry)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid validator %q in allow-list", entry)
+		}
+		pubKeys = append(pubKeys, key[:])
+	}
+	return pubKeys, nil
+}
+
+// initRulesStorage initialises the storage for slashing protection.
+// It returns nil if a local store at the storage path is to be used.
+func initRulesStorage(ctx context.Context, majordomo majordomo.Service) (standardrules.Storage, error) {
+	switch viper.GetString("server.rules.storage-type") {
+	case "badger", "sqlite":
+		return nil, nil
+	case "postgres":
+		if viper.GetString("server.rules.postgres.dsn") == "" {
+			return nil, errors.New("server.rules.postgres.dsn is required for postgres storage")
+		}
+		dsn, err := majordomo.Fetch(ctx, viper.GetString("server.rules.postgres.dsn"))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to obtain postgres DSN")
+		}
+		store, err := postgresrules.New(ctx,
+			postgresrules.WithLogLevel(util.LogLevel("rules.postgres")),
+			postgresrules.WithDSN(string(dsn)),
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to initialise postgres storage")
+		}
+		return store, nil
+	default:
+		return nil, fmt.Errorf("unknown rules storage type %q", viper.GetString("server.rules.storage-type"))
+	}
+}
+
+func initStores(ctx context.Context, majordomo majordomo.Service) ([]e2wtypes.Store, error) {
 	storesCfg := &core.Stores{}
 	if err := viper.Unmarshal(&storesCfg); err != nil {
 		return nil, errors.Wrap(err, "failed to obtain stores configuration")
 	}
+	// Store credentials are resolved here: S3 credentials are majordomo URLs, and
+	// GCS credentials are paths relative to the base directory.
+	for i, store := range storesCfg.Stores {
+		if store.Type == "gcs" {
+			// GCS stores share the credentials of the GSM confidant unless given their own.
+			if store.Credentials == "" {
+				store.Credentials = viper.GetString("majordomo.gsm.credentials")
+			}
+			if store.Credentials != "" {
+				store.Credentials = resolvePath(store.Credentials)
+			}
+		}
+		if store.AccessKeyID != "" {
+			value, err := majordomo.Fetch(ctx, store.AccessKeyID)
+			if err != nil {
+				return nil, errors.Wrap(err, fmt.Sprintf("failed to obtain access key ID for store %d", i))
+			}
+			store.AccessKeyID = string(value)
+		}
+		if store.SecretAccessKey != "" {
+			value, err := majordomo.Fetch(ctx, store.SecretAccessKey)
+			if err != nil {
+				return nil, errors.Wrap(err, fmt.Sprintf("failed to obtain secret access key for store %d", i))
+			}
+			store.SecretAccessKey = string(value)
+		}
+	}
 	stores, err := core.InitStores(ctx, storesCfg.Stores)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to initialise stores")
@@ -625,22 +1211,15 @@ func startUnlocker(ctx context.Context, majordomo majordomo.Service, monitor met
 		localunlocker.WithMonitor(unlockerMonitor),
 		localunlocker.WithWalletPassphrases(walletPassphrases),
 		localunlocker.WithAccountPassphrases(accountPassphrases),
+		localunlocker.WithRelockAfter(viper.GetDuration("unlocker.relock-after")),
 	)
 }
 
 func startChecker(ctx context.Context, monitor metrics.Service) (checker.Service, error) {
 	// Set up the checker.
-	permissionsCfg := viper.GetStringMap("permissions")
-	permissions := make(map[string][]*checker.Permissions)
-	for client := range permissionsCfg {
-		perms := viper.GetStringMapStringSlice(fmt.Sprintf("permissions.%s", client))
-		permissions[client] = make([]*checker.Permissions, 0, len(perms))
-		for path, operations := range perms {
-			permissions[client] = append(permissions[client], &checker.Permissions{
-				Path:       path,
-				Operations: operations,
-			})
-		}
+	permissions, err := obtainPermissions()
+	if err != nil {
+		return nil, err
 	}
 	var checkerMonitor metrics.CheckerMonitor
 	if monitor, isMonitor := monitor.(metrics.CheckerMonitor); isMonitor {
@@ -650,9 +1229,68 @@ func startChecker(ctx context.Context, monitor metrics.Service) (checker.Service
 		staticchecker.WithLogLevel(util.LogLevel("checker")),
 		staticchecker.WithMonitor(checkerMonitor),
 		staticchecker.WithPermissions(permissions),
+		staticchecker.WithAllowedIPs(viper.GetStringMapStringSlice("checker.allowed-ips")),
 	)
 }
 
+// obtainPermissions obtains the client permissions from configuration,
+// expanding any references to roles.
+func obtainPermissions() (map[string][]*checker.Permissions, error) {
+	permissionsCfg := viper.GetStringMap("permissions")
+	permissions := make(map[string][]*checker.Permissions)
+	for client := range permissionsCfg {
+		perms := viper.GetStringMap(fmt.Sprintf("permissions.%s", client))
+		permissions[client] = make([]*checker.Permissions, 0, len(perms))
+		for path, value := range perms {
+			permission, err := parsePermission(path, value)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid permissions for client %q", client)
+			}
+			permissions[client] = append(permissions[client], permission)
+		}
+	}
+
+	permissions, err := checker.ExpandRoles(permissions, viper.GetStringMapStringSlice("roles"))
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid permission roles")
+	}
+
+	return permissions, nil
+}
+
+// parsePermission parses the configuration for a single path.  This is either
+// a list of operations, or an object with "operations" and "effect".
+func parsePermission(path string, value interface{}) (*checker.Permissions, error) {
+	permission := &checker.Permissions{
+		Path: path,
+	}
+	if object, isObject := value.(map[string]interface{}); isObject {
+		for key := range object {
+			if key != "operations" && key != "effect" {
+				return nil, fmt.Errorf("unknown key %q for path %s", key, path)
+			}
+		}
+		if effect, exists := object["effect"]; exists {
+			permission.Effect = fmt.Sprintf("%v", effect)
+		}
+		value = object["operations"]
+	}
+	switch operations := value.(type) {
+	case string:
+		permission.Operations = []string{operations}
+	case []string:
+		permission.Operations = operations
+	case []interface{}:
+		permission.Operations = make([]string, len(operations))
+		for i := range operations {
+			permission.Operations[i] = fmt.Sprintf("%v", operations[i])
+		}
+	default:
+		return nil, fmt.Errorf("invalid operations for path %s", path)
+	}
+	return permission, nil
+}
+
 func startFetcher(ctx context.Context, stores []e2wtypes.Store, monitor metrics.Service) (fetcher.Service, error) {
 	var fetcherMonitor metrics.FetcherMonitor
 	if monitor, isMonitor := monitor.(metrics.FetcherMonitor); isMonitor {
@@ -662,6 +1300,7 @@ func startFetcher(ctx context.Context, stores []e2wtypes.Store, monitor metrics.
 		memfetcher.WithLogLevel(util.LogLevel("fetcher")),
 		memfetcher.WithMonitor(fetcherMonitor),
 		memfetcher.WithStores(stores),
+		memfetcher.WithRefreshInterval(viper.GetDuration("fetcher.refresh-interval")),
 	)
 }
 
@@ -676,43 +1315,109 @@ func startLocker(ctx context.Context, monitor metrics.Service) (locker.Service,
 	)
 }
 
-func startRuler(ctx context.Context, locker locker.Service, monitor metrics.Service) (ruler.Service, error) {
-	rules, err := initRules(ctx)
+func startRuler(ctx context.Context, rulesCtx context.Context, majordomo majordomo.Service, locker locker.Service, monitor metrics.Service, chainTime chaintime.Service) (ruler.Service, rules.Service, error) {
+	params := make([]standardrules.Parameter, 0)
+	if distance := viper.GetUint64("server.rules.max-attestation-epoch-distance"); distance > 0 {
+		if chainTime == nil {
+			return nil, nil, errors.New("server.rules.max-attestation-epoch-distance requires beacon chain time configuration")
+		}
+		params = append(params,
+			standardrules.WithChainTime(chainTime),
+			standardrules.WithMaxAttestationEpochDistance(distance),
+		)
+	}
+	rules, err := initRules(rulesCtx, majordomo, monitor, params...)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to set up rules")
+		return nil, nil, errors.Wrap(err, "failed to set up rules")
 	}
 	var rulerMonitor metrics.RulerMonitor
 	if monitor, isMonitor := monitor.(metrics.RulerMonitor); isMonitor {
 		rulerMonitor = monitor
 	}
-	return goruler.New(ctx,
+	ruler, err := goruler.New(ctx,
 		goruler.WithLogLevel(util.LogLevel("ruler")),
 		goruler.WithMonitor(rulerMonitor),
 		goruler.WithLocker(locker),
 		goruler.WithRules(rules),
+		goruler.WithProposalLease(viper.GetDuration("signer.proposal-lease")),
+		goruler.WithNonSlashableFastPath(viper.GetBool("signer.non-slashable-fast-path")),
 	)
+	if err != nil {
+		return nil, nil, err
+	}
+	return ruler, rules, nil
 }
 
-func startPeers(ctx context.Context, monitor metrics.Service) (peers.Service, error) {
+func startPeers(ctx context.Context, monitor metrics.Service, prober sender.Prober) (peers.Service, error) {
+	var peersMonitor metrics.PeersMonitor
+	if monitor, isMonitor := monitor.(metrics.PeersMonitor); isMonitor {
+		peersMonitor = monitor
+	}
+	params := []staticpeers.Parameter{
+		staticpeers.WithLogLevel(util.LogLevel("peers")),
+		staticpeers.WithMonitor(peersMonitor),
+	}
+	// Peers are probed unless the probe interval is explicitly set to 0.
+	if viper.GetString("peers.probe-interval") == "" || viper.GetDuration("peers.probe-interval") != 0 {
+		params = append(params, staticpeers.WithProber(prober))
+		if viper.GetString("peers.probe-interval") != "" {
+			params = append(params, staticpeers.WithProbeInterval(viper.GetDuration("peers.probe-interval")))
+		}
+	}
+
+	if peersFile := viper.GetString("peers.file"); peersFile != "" {
+		for k := range viper.GetStringMap("peers") {
+			if k != "file" && k != "reload-interval" && k != "probe-interval" {
+				return nil, errors.New("peers cannot be listed in the configuration when peers.file is set")
+			}
+		}
+		params = append(params, staticpeers.WithPeersFile(resolvePath(peersFile)))
+		if viper.GetString("peers.reload-interval") != "" {
+			params = append(params, staticpeers.WithReloadInterval(viper.GetDuration("peers.reload-interval")))
+		}
+		return staticpeers.New(ctx, params...)
+	}
+
 	// Keys are strings.
 	peersInfo := viper.GetStringMapString("peers")
 	peersMap := make(map[uint64]string)
 	for k, v := range peersInfo {
+		if k == "probe-interval" {
+			continue
+		}
 		id, err := strconv.ParseUint(k, 10, 64)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to parse peers info")
 		}
 		peersMap[id] = v
 	}
-	var peersMonitor metrics.PeersMonitor
-	if monitor, isMonitor := monitor.(metrics.PeersMonitor); isMonitor {
-		peersMonitor = monitor
+	params = append(params, staticpeers.WithPeers(peersMap))
+	return staticpeers.New(ctx, params...)
+}
+
+// startAccountHook starts the account creation hook, if configured.
+func startAccountHook(ctx context.Context, majordomo majordomo.Service) (hooks.Service, error) {
+	if viper.GetString("hooks.account-created.url") == "" {
+		return nil, nil
 	}
-	return staticpeers.New(ctx,
-		staticpeers.WithLogLevel(util.LogLevel("peers")),
-		staticpeers.WithMonitor(peersMonitor),
-		staticpeers.WithPeers(peersMap),
-	)
+	params := []webhook.Parameter{
+		webhook.WithLogLevel(util.LogLevel("hooks")),
+		webhook.WithURL(viper.GetString("hooks.account-created.url")),
+	}
+	if viper.GetString("hooks.account-created.authorization") != "" {
+		authorization, err := majordomo.Fetch(ctx, viper.GetString("hooks.account-created.authorization"))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to obtain authorization for account creation hook")
+		}
+		params = append(params, webhook.WithAuthorization(strings.TrimSpace(string(authorization))))
+	}
+	if viper.GetString("hooks.account-created.timeout") != "" {
+		params = append(params, webhook.WithTimeout(viper.GetDuration("hooks.account-created.timeout")))
+	}
+	if viper.GetString("hooks.account-created.max-attempts") != "" {
+		params = append(params, webhook.WithMaxAttempts(viper.GetInt("hooks.account-created.max-attempts")))
+	}
+	return webhook.New(ctx, params...)
 }
 
 func startLister(ctx context.Context, monitor metrics.Service, fetcher fetcher.Service, checker checker.Service, ruler ruler.Service) (lister.Service, error) {
@@ -726,9 +1431,82 @@ func startLister(ctx context.Context, monitor metrics.Service, fetcher fetcher.S
 		standardlister.WithFetcher(fetcher),
 		standardlister.WithChecker(checker),
 		standardlister.WithRuler(ruler),
+		standardlister.WithCacheTTL(viper.GetDuration("lister.cache-ttl")),
 	)
 }
 
+// startAuthenticator starts the bearer token authenticator.
+func startAuthenticator(ctx context.Context, majordomo majordomo.Service) (authenticator.Service, error) {
+	if viper.GetString("server.token-auth.jwks") == "" {
+		return nil, errors.New("server.token-auth.jwks is required")
+	}
+	jwks, err := majordomo.Fetch(ctx, viper.GetString("server.token-auth.jwks"))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to obtain JWKS")
+	}
+
+	return jwtauthenticator.New(ctx,
+		jwtauthenticator.WithLogLevel(util.LogLevel("authenticator")),
+		jwtauthenticator.WithIssuer(viper.GetString("server.token-auth.issuer")),
+		jwtauthenticator.WithAudience(viper.GetString("server.token-auth.audience")),
+		jwtauthenticator.WithJWKS(jwks),
+		jwtauthenticator.WithClaim(viper.GetString("server.token-auth.claim")),
+		jwtauthenticator.WithIdentities(viper.GetStringMapString("server.token-auth.identities")),
+		jwtauthenticator.WithClockSkew(viper.GetDuration("auth.clock-skew")),
+	)
+}
+
+// startChainTime starts the chain time service.
+// It returns nil if neither a network nor a genesis time is configured.
+func startChainTime(ctx context.Context) (chaintime.Service, error) {
+	if viper.GetString("beacon.network") == "" && viper.GetString("beacon.genesis-time") == "" {
+		log.Debug().Msg("No beacon network or genesis time configured; time-based features are disabled")
+		return nil, nil
+	}
+
+	var genesisTime time.Time
+	if viper.GetString("beacon.genesis-time") != "" {
+		var err error
+		genesisTime, err = parseGenesisTime(viper.GetString("beacon.genesis-time"))
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid beacon.genesis-time")
+		}
+	}
+
+	chainTime, err := standardchaintime.New(ctx,
+		standardchaintime.WithLogLevel(util.LogLevel("chaintime")),
+		standardchaintime.WithNetwork(viper.GetString("beacon.network")),
+		standardchaintime.WithGenesisTime(genesisTime),
+		standardchaintime.WithSlotDuration(time.Duration(viper.GetInt64("beacon.seconds-per-slot"))*time.Second),
+		standardchaintime.WithSlotsPerEpoch(viper.GetUint64("beacon.slots-per-epoch")),
+	)
+	if err != nil {
+		return nil, err
+	}
+	log.Info().
+		Time("genesis_time", chainTime.GenesisTime()).
+		Uint64("current_slot", chainTime.CurrentSlot()).
+		Uint64("current_epoch", chainTime.CurrentEpoch()).
+		Msg("Chain time configured")
+
+	return chainTime, nil
+}
+
+// parseGenesisTime parses a genesis time, supplied either as a Unix timestamp or in RFC3339 format.
+func parseGenesisTime(input string) (time.Time, error) {
+	if timestamp, err := strconv.ParseInt(input, 10, 64); err == nil {
+		if timestamp <= 0 {
+			return time.Time{}, errors.New("timestamp must be positive")
+		}
+		return time.Unix(timestamp, 0), nil
+	}
+	genesisTime, err := time.Parse(time.RFC3339, input)
+	if err != nil {
+		return time.Time{}, errors.New("must be a Unix timestamp or an RFC3339 time")
+	}
+	return genesisTime, nil
+}
+
 // resolvePath resolves a potentially relative path to an absolute path.
 func resolvePath(path string) string {
 	if filepath.IsAbs(path) {
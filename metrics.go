@@ -15,6 +15,8 @@ package main
 
 import (
 	"context"
+	"runtime"
+	"runtime/debug"
 
 	"github.com/attestantio/dirk/services/metrics"
 	"github.com/pkg/errors"
@@ -26,6 +28,9 @@ var metricsNamespace = "dirk"
 var releaseMetric *prometheus.GaugeVec
 var readyMetric prometheus.Gauge
 
+// readyHooks are called whenever readiness changes.
+var readyHooks []func(ready bool)
+
 func registerMetrics(ctx context.Context, monitor metrics.Service) error {
 	if releaseMetric != nil {
 		// Already registered.
@@ -77,7 +82,18 @@ func registerPrometheusMetrics(ctx context.Context) error {
 }
 
 // SetRelease is called when the release version is established.
-func setRelease(ctx context.Context, version string) {
+func setRelease(ctx context.Context, monitor metrics.Service, version string, buildInfo *debug.BuildInfo) {
+	if buildMonitor, isMonitor := monitor.(metrics.BuildMonitor); isMonitor {
+		commit := GitCommit
+		if commit == "" && buildInfo != nil {
+			commit = vcsRevision(buildInfo)
+		}
+		if commit == "" {
+			commit = "unknown"
+		}
+		buildMonitor.BuildInfo(version, runtime.Version(), commit)
+	}
+
 	if releaseMetric == nil {
 		return
 	}
@@ -85,7 +101,16 @@ func setRelease(ctx context.Context, version string) {
 	releaseMetric.WithLabelValues(version).Set(1)
 }
 
+// onReady registers a function to be called whenever readiness changes.
+func onReady(hook func(ready bool)) {
+	readyHooks = append(readyHooks, hook)
+}
+
 func setReady(ctx context.Context, ready bool) {
+	for _, hook := range readyHooks {
+		hook(ready)
+	}
+
 	if readyMetric == nil {
 		return
 	}
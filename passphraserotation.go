@@ -0,0 +1,58 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/attestantio/dirk/cmd"
+	"github.com/spf13/viper"
+	"github.com/wealdtech/go-majordomo"
+)
+
+// rotatePassphrase re-encrypts the wallet or account given by rotate-passphrase with new-passphrase.
+func rotatePassphrase(ctx context.Context, majordomo majordomo.Service) {
+	if viper.GetString("old-passphrase") == "" {
+		fmt.Println("Existing passphrase required for passphrase rotation")
+		os.Exit(1)
+	}
+	if viper.GetString("new-passphrase") == "" {
+		fmt.Println("New passphrase required for passphrase rotation")
+		os.Exit(1)
+	}
+	oldPassphrase, err := majordomo.Fetch(ctx, viper.GetString("old-passphrase"))
+	if err != nil {
+		fmt.Printf("Failed to obtain existing passphrase: %v\n", err)
+		os.Exit(1)
+	}
+	newPassphrase, err := majordomo.Fetch(ctx, viper.GetString("new-passphrase"))
+	if err != nil {
+		fmt.Printf("Failed to obtain new passphrase: %v\n", err)
+		os.Exit(1)
+	}
+	stores, err := initStores(ctx, majordomo)
+	if err != nil {
+		fmt.Printf("%v\n", err)
+		os.Exit(1)
+	}
+
+	if err := cmd.RotatePassphrase(ctx, stores, viper.GetString("rotate-passphrase"), oldPassphrase, newPassphrase); err != nil {
+		fmt.Printf("Failed to rotate passphrase: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Rotated passphrase for %s\n", viper.GetString("rotate-passphrase"))
+	os.Exit(0)
+}
@@ -0,0 +1,173 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package v1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// AccountAdminClient is the client API for AccountAdmin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type AccountAdminClient interface {
+	Delete(ctx context.Context, in *DeleteAccountRequest, opts ...grpc.CallOption) (*DeleteAccountResponse, error)
+	Reshare(ctx context.Context, in *ReshareAccountRequest, opts ...grpc.CallOption) (*ReshareAccountResponse, error)
+	ListUnlocked(ctx context.Context, in *ListUnlockedAccountsRequest, opts ...grpc.CallOption) (*ListUnlockedAccountsResponse, error)
+}
+
+type accountAdminClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAccountAdminClient(cc grpc.ClientConnInterface) AccountAdminClient {
+	return &accountAdminClient{cc}
+}
+
+func (c *accountAdminClient) Delete(ctx context.Context, in *DeleteAccountRequest, opts ...grpc.CallOption) (*DeleteAccountResponse, error) {
+	out := new(DeleteAccountResponse)
+	err := c.cc.Invoke(ctx, "/dirk.v1.AccountAdmin/Delete", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountAdminClient) Reshare(ctx context.Context, in *ReshareAccountRequest, opts ...grpc.CallOption) (*ReshareAccountResponse, error) {
+	out := new(ReshareAccountResponse)
+	err := c.cc.Invoke(ctx, "/dirk.v1.AccountAdmin/Reshare", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountAdminClient) ListUnlocked(ctx context.Context, in *ListUnlockedAccountsRequest, opts ...grpc.CallOption) (*ListUnlockedAccountsResponse, error) {
+	out := new(ListUnlockedAccountsResponse)
+	err := c.cc.Invoke(ctx, "/dirk.v1.AccountAdmin/ListUnlocked", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AccountAdminServer is the server API for AccountAdmin service.
+// All implementations must embed UnimplementedAccountAdminServer
+// for forward compatibility
+type AccountAdminServer interface {
+	Delete(context.Context, *DeleteAccountRequest) (*DeleteAccountResponse, error)
+	Reshare(context.Context, *ReshareAccountRequest) (*ReshareAccountResponse, error)
+	ListUnlocked(context.Context, *ListUnlockedAccountsRequest) (*ListUnlockedAccountsResponse, error)
+	mustEmbedUnimplementedAccountAdminServer()
+}
+
+// UnimplementedAccountAdminServer must be embedded to have forward compatible implementations.
+type UnimplementedAccountAdminServer struct {
+}
+
+func (UnimplementedAccountAdminServer) Delete(context.Context, *DeleteAccountRequest) (*DeleteAccountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
+}
+func (UnimplementedAccountAdminServer) Reshare(context.Context, *ReshareAccountRequest) (*ReshareAccountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Reshare not implemented")
+}
+func (UnimplementedAccountAdminServer) ListUnlocked(context.Context, *ListUnlockedAccountsRequest) (*ListUnlockedAccountsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListUnlocked not implemented")
+}
+func (UnimplementedAccountAdminServer) mustEmbedUnimplementedAccountAdminServer() {}
+
+// UnsafeAccountAdminServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AccountAdminServer will
+// result in compilation errors.
+type UnsafeAccountAdminServer interface {
+	mustEmbedUnimplementedAccountAdminServer()
+}
+
+func RegisterAccountAdminServer(s grpc.ServiceRegistrar, srv AccountAdminServer) {
+	s.RegisterService(&AccountAdmin_ServiceDesc, srv)
+}
+
+func _AccountAdmin_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountAdminServer).Delete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dirk.v1.AccountAdmin/Delete",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountAdminServer).Delete(ctx, req.(*DeleteAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AccountAdmin_Reshare_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReshareAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountAdminServer).Reshare(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dirk.v1.AccountAdmin/Reshare",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountAdminServer).Reshare(ctx, req.(*ReshareAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AccountAdmin_ListUnlocked_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListUnlockedAccountsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountAdminServer).ListUnlocked(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dirk.v1.AccountAdmin/ListUnlocked",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountAdminServer).ListUnlocked(ctx, req.(*ListUnlockedAccountsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// AccountAdmin_ServiceDesc is the grpc.ServiceDesc for AccountAdmin service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AccountAdmin_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "dirk.v1.AccountAdmin",
+	HandlerType: (*AccountAdminServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Delete",
+			Handler:    _AccountAdmin_Delete_Handler,
+		},
+		{
+			MethodName: "Reshare",
+			Handler:    _AccountAdmin_Reshare_Handler,
+		},
+		{
+			MethodName: "ListUnlocked",
+			Handler:    _AccountAdmin_ListUnlocked_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "accountadmin.proto",
+}
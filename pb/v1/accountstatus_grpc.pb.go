@@ -0,0 +1,101 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package v1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// AccountStatusClient is the client API for AccountStatus service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type AccountStatusClient interface {
+	Status(ctx context.Context, in *AccountStatusRequest, opts ...grpc.CallOption) (*AccountStatusResponse, error)
+}
+
+type accountStatusClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAccountStatusClient(cc grpc.ClientConnInterface) AccountStatusClient {
+	return &accountStatusClient{cc}
+}
+
+func (c *accountStatusClient) Status(ctx context.Context, in *AccountStatusRequest, opts ...grpc.CallOption) (*AccountStatusResponse, error) {
+	out := new(AccountStatusResponse)
+	err := c.cc.Invoke(ctx, "/dirk.v1.AccountStatus/Status", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AccountStatusServer is the server API for AccountStatus service.
+// All implementations must embed UnimplementedAccountStatusServer
+// for forward compatibility
+type AccountStatusServer interface {
+	Status(context.Context, *AccountStatusRequest) (*AccountStatusResponse, error)
+	mustEmbedUnimplementedAccountStatusServer()
+}
+
+// UnimplementedAccountStatusServer must be embedded to have forward compatible implementations.
+type UnimplementedAccountStatusServer struct {
+}
+
+func (UnimplementedAccountStatusServer) Status(context.Context, *AccountStatusRequest) (*AccountStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Status not implemented")
+}
+func (UnimplementedAccountStatusServer) mustEmbedUnimplementedAccountStatusServer() {}
+
+// UnsafeAccountStatusServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AccountStatusServer will
+// result in compilation errors.
+type UnsafeAccountStatusServer interface {
+	mustEmbedUnimplementedAccountStatusServer()
+}
+
+func RegisterAccountStatusServer(s grpc.ServiceRegistrar, srv AccountStatusServer) {
+	s.RegisterService(&AccountStatus_ServiceDesc, srv)
+}
+
+func _AccountStatus_Status_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AccountStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountStatusServer).Status(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dirk.v1.AccountStatus/Status",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountStatusServer).Status(ctx, req.(*AccountStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// AccountStatus_ServiceDesc is the grpc.ServiceDesc for AccountStatus service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AccountStatus_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "dirk.v1.AccountStatus",
+	HandlerType: (*AccountStatusServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Status",
+			Handler:    _AccountStatus_Status_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "accountstatus.proto",
+}
@@ -0,0 +1,101 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package v1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// AdminAuthClient is the client API for AdminAuth service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type AdminAuthClient interface {
+	Challenge(ctx context.Context, in *ChallengeRequest, opts ...grpc.CallOption) (*ChallengeResponse, error)
+}
+
+type adminAuthClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAdminAuthClient(cc grpc.ClientConnInterface) AdminAuthClient {
+	return &adminAuthClient{cc}
+}
+
+func (c *adminAuthClient) Challenge(ctx context.Context, in *ChallengeRequest, opts ...grpc.CallOption) (*ChallengeResponse, error) {
+	out := new(ChallengeResponse)
+	err := c.cc.Invoke(ctx, "/dirk.v1.AdminAuth/Challenge", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AdminAuthServer is the server API for AdminAuth service.
+// All implementations must embed UnimplementedAdminAuthServer
+// for forward compatibility
+type AdminAuthServer interface {
+	Challenge(context.Context, *ChallengeRequest) (*ChallengeResponse, error)
+	mustEmbedUnimplementedAdminAuthServer()
+}
+
+// UnimplementedAdminAuthServer must be embedded to have forward compatible implementations.
+type UnimplementedAdminAuthServer struct {
+}
+
+func (UnimplementedAdminAuthServer) Challenge(context.Context, *ChallengeRequest) (*ChallengeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Challenge not implemented")
+}
+func (UnimplementedAdminAuthServer) mustEmbedUnimplementedAdminAuthServer() {}
+
+// UnsafeAdminAuthServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AdminAuthServer will
+// result in compilation errors.
+type UnsafeAdminAuthServer interface {
+	mustEmbedUnimplementedAdminAuthServer()
+}
+
+func RegisterAdminAuthServer(s grpc.ServiceRegistrar, srv AdminAuthServer) {
+	s.RegisterService(&AdminAuth_ServiceDesc, srv)
+}
+
+func _AdminAuth_Challenge_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChallengeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminAuthServer).Challenge(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dirk.v1.AdminAuth/Challenge",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminAuthServer).Challenge(ctx, req.(*ChallengeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// AdminAuth_ServiceDesc is the grpc.ServiceDesc for AdminAuth service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AdminAuth_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "dirk.v1.AdminAuth",
+	HandlerType: (*AdminAuthServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Challenge",
+			Handler:    _AdminAuth_Challenge_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "adminauth.proto",
+}
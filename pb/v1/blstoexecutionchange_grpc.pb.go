@@ -0,0 +1,103 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package v1
+
+import (
+	context "context"
+	v1 "github.com/wealdtech/eth2-signer-api/pb/v1"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// BLSToExecutionChangeSignerClient is the client API for BLSToExecutionChangeSigner service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type BLSToExecutionChangeSignerClient interface {
+	SignBLSToExecutionChange(ctx context.Context, in *SignBLSToExecutionChangeRequest, opts ...grpc.CallOption) (*v1.SignResponse, error)
+}
+
+type bLSToExecutionChangeSignerClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewBLSToExecutionChangeSignerClient(cc grpc.ClientConnInterface) BLSToExecutionChangeSignerClient {
+	return &bLSToExecutionChangeSignerClient{cc}
+}
+
+func (c *bLSToExecutionChangeSignerClient) SignBLSToExecutionChange(ctx context.Context, in *SignBLSToExecutionChangeRequest, opts ...grpc.CallOption) (*v1.SignResponse, error) {
+	out := new(v1.SignResponse)
+	err := c.cc.Invoke(ctx, "/dirk.v1.BLSToExecutionChangeSigner/SignBLSToExecutionChange", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// BLSToExecutionChangeSignerServer is the server API for BLSToExecutionChangeSigner service.
+// All implementations must embed UnimplementedBLSToExecutionChangeSignerServer
+// for forward compatibility
+type BLSToExecutionChangeSignerServer interface {
+	SignBLSToExecutionChange(context.Context, *SignBLSToExecutionChangeRequest) (*v1.SignResponse, error)
+	mustEmbedUnimplementedBLSToExecutionChangeSignerServer()
+}
+
+// UnimplementedBLSToExecutionChangeSignerServer must be embedded to have forward compatible implementations.
+type UnimplementedBLSToExecutionChangeSignerServer struct {
+}
+
+func (UnimplementedBLSToExecutionChangeSignerServer) SignBLSToExecutionChange(context.Context, *SignBLSToExecutionChangeRequest) (*v1.SignResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SignBLSToExecutionChange not implemented")
+}
+func (UnimplementedBLSToExecutionChangeSignerServer) mustEmbedUnimplementedBLSToExecutionChangeSignerServer() {
+}
+
+// UnsafeBLSToExecutionChangeSignerServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to BLSToExecutionChangeSignerServer will
+// result in compilation errors.
+type UnsafeBLSToExecutionChangeSignerServer interface {
+	mustEmbedUnimplementedBLSToExecutionChangeSignerServer()
+}
+
+func RegisterBLSToExecutionChangeSignerServer(s grpc.ServiceRegistrar, srv BLSToExecutionChangeSignerServer) {
+	s.RegisterService(&BLSToExecutionChangeSigner_ServiceDesc, srv)
+}
+
+func _BLSToExecutionChangeSigner_SignBLSToExecutionChange_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignBLSToExecutionChangeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BLSToExecutionChangeSignerServer).SignBLSToExecutionChange(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dirk.v1.BLSToExecutionChangeSigner/SignBLSToExecutionChange",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BLSToExecutionChangeSignerServer).SignBLSToExecutionChange(ctx, req.(*SignBLSToExecutionChangeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// BLSToExecutionChangeSigner_ServiceDesc is the grpc.ServiceDesc for BLSToExecutionChangeSigner service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var BLSToExecutionChangeSigner_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "dirk.v1.BLSToExecutionChangeSigner",
+	HandlerType: (*BLSToExecutionChangeSignerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SignBLSToExecutionChange",
+			Handler:    _BLSToExecutionChangeSigner_SignBLSToExecutionChange_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "blstoexecutionchange.proto",
+}
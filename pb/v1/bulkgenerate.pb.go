@@ -0,0 +1,405 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.27.1
+// 	protoc        v3.17.3
+// source: bulkgenerate.proto
+
+package v1
+
+import (
+	v1 "github.com/wealdtech/eth2-signer-api/pb/v1"
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type GenerateBulkRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// wallet is the name of the wallet in which to generate the accounts.
+	Wallet string `protobuf:"bytes,1,opt,name=wallet,proto3" json:"wallet,omitempty"`
+	// name_prefix is prepended to the index of each account to form its name.
+	NamePrefix string `protobuf:"bytes,2,opt,name=name_prefix,json=namePrefix,proto3" json:"name_prefix,omitempty"`
+	// start_index is the index of the first account.
+	StartIndex uint32 `protobuf:"varint,3,opt,name=start_index,json=startIndex,proto3" json:"start_index,omitempty"`
+	// count is the number of accounts to generate.
+	Count            uint32 `protobuf:"varint,4,opt,name=count,proto3" json:"count,omitempty"`
+	Passphrase       []byte `protobuf:"bytes,5,opt,name=passphrase,proto3" json:"passphrase,omitempty"`
+	SigningThreshold uint32 `protobuf:"varint,6,opt,name=signing_threshold,json=signingThreshold,proto3" json:"signing_threshold,omitempty"`
+	Participants     uint32 `protobuf:"varint,7,opt,name=participants,proto3" json:"participants,omitempty"`
+}
+
+func (x *GenerateBulkRequest) Reset() {
+	*x = GenerateBulkRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_bulkgenerate_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GenerateBulkRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GenerateBulkRequest) ProtoMessage() {}
+
+func (x *GenerateBulkRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bulkgenerate_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GenerateBulkRequest.ProtoReflect.Descriptor instead.
+func (*GenerateBulkRequest) Descriptor() ([]byte, []int) {
+	return file_bulkgenerate_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GenerateBulkRequest) GetWallet() string {
+	if x != nil {
+		return x.Wallet
+	}
+	return ""
+}
+
+func (x *GenerateBulkRequest) GetNamePrefix() string {
+	if x != nil {
+		return x.NamePrefix
+	}
+	return ""
+}
+
+func (x *GenerateBulkRequest) GetStartIndex() uint32 {
+	if x != nil {
+		return x.StartIndex
+	}
+	return 0
+}
+
+func (x *GenerateBulkRequest) GetCount() uint32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *GenerateBulkRequest) GetPassphrase() []byte {
+	if x != nil {
+		return x.Passphrase
+	}
+	return nil
+}
+
+func (x *GenerateBulkRequest) GetSigningThreshold() uint32 {
+	if x != nil {
+		return x.SigningThreshold
+	}
+	return 0
+}
+
+func (x *GenerateBulkRequest) GetParticipants() uint32 {
+	if x != nil {
+		return x.Participants
+	}
+	return 0
+}
+
+type GenerateBulkResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	State   v1.ResponseState `protobuf:"varint,1,opt,name=state,proto3,enum=v1.ResponseState" json:"state,omitempty"`
+	Message string           `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	// accounts are the accounts generated, in index order.  If generation failed
+	// part-way through these are the accounts that were created before it failed.
+	Accounts []*GeneratedAccount `protobuf:"bytes,3,rep,name=accounts,proto3" json:"accounts,omitempty"`
+}
+
+func (x *GenerateBulkResponse) Reset() {
+	*x = GenerateBulkResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_bulkgenerate_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GenerateBulkResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GenerateBulkResponse) ProtoMessage() {}
+
+func (x *GenerateBulkResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bulkgenerate_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GenerateBulkResponse.ProtoReflect.Descriptor instead.
+func (*GenerateBulkResponse) Descriptor() ([]byte, []int) {
+	return file_bulkgenerate_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GenerateBulkResponse) GetState() v1.ResponseState {
+	if x != nil {
+		return x.State
+	}
+	return v1.ResponseState(0)
+}
+
+func (x *GenerateBulkResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *GenerateBulkResponse) GetAccounts() []*GeneratedAccount {
+	if x != nil {
+		return x.Accounts
+	}
+	return nil
+}
+
+type GeneratedAccount struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Account      string         `protobuf:"bytes,1,opt,name=account,proto3" json:"account,omitempty"`
+	PublicKey    []byte         `protobuf:"bytes,2,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+	Participants []*v1.Endpoint `protobuf:"bytes,3,rep,name=participants,proto3" json:"participants,omitempty"`
+}
+
+func (x *GeneratedAccount) Reset() {
+	*x = GeneratedAccount{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_bulkgenerate_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GeneratedAccount) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GeneratedAccount) ProtoMessage() {}
+
+func (x *GeneratedAccount) ProtoReflect() protoreflect.Message {
+	mi := &file_bulkgenerate_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GeneratedAccount.ProtoReflect.Descriptor instead.
+func (*GeneratedAccount) Descriptor() ([]byte, []int) {
+	return file_bulkgenerate_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GeneratedAccount) GetAccount() string {
+	if x != nil {
+		return x.Account
+	}
+	return ""
+}
+
+func (x *GeneratedAccount) GetPublicKey() []byte {
+	if x != nil {
+		return x.PublicKey
+	}
+	return nil
+}
+
+func (x *GeneratedAccount) GetParticipants() []*v1.Endpoint {
+	if x != nil {
+		return x.Participants
+	}
+	return nil
+}
+
+var File_bulkgenerate_proto protoreflect.FileDescriptor
+
+var file_bulkgenerate_proto_rawDesc = []byte{
+	0x0a, 0x12, 0x62, 0x75, 0x6c, 0x6b, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x12, 0x07, 0x64, 0x69, 0x72, 0x6b, 0x2e, 0x76, 0x31, 0x1a, 0x1c, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x61, 0x6e, 0x6e, 0x6f, 0x74, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x0e, 0x65, 0x6e, 0x64,
+	0x70, 0x6f, 0x69, 0x6e, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x13, 0x72, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x73, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x22, 0xf6, 0x01, 0x0a, 0x13, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x42, 0x75, 0x6c,
+	0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x77, 0x61, 0x6c, 0x6c,
+	0x65, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x77, 0x61, 0x6c, 0x6c, 0x65, 0x74,
+	0x12, 0x1f, 0x0a, 0x0b, 0x6e, 0x61, 0x6d, 0x65, 0x5f, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6e, 0x61, 0x6d, 0x65, 0x50, 0x72, 0x65, 0x66, 0x69,
+	0x78, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0a, 0x73, 0x74, 0x61, 0x72, 0x74, 0x49, 0x6e, 0x64,
+	0x65, 0x78, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x70, 0x61, 0x73, 0x73,
+	0x70, 0x68, 0x72, 0x61, 0x73, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x70, 0x61,
+	0x73, 0x73, 0x70, 0x68, 0x72, 0x61, 0x73, 0x65, 0x12, 0x2b, 0x0a, 0x11, 0x73, 0x69, 0x67, 0x6e,
+	0x69, 0x6e, 0x67, 0x5f, 0x74, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x10, 0x73, 0x69, 0x67, 0x6e, 0x69, 0x6e, 0x67, 0x54, 0x68, 0x72, 0x65,
+	0x73, 0x68, 0x6f, 0x6c, 0x64, 0x12, 0x22, 0x0a, 0x0c, 0x70, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69,
+	0x70, 0x61, 0x6e, 0x74, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c, 0x70, 0x61, 0x72,
+	0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x73, 0x22, 0x90, 0x01, 0x0a, 0x14, 0x47, 0x65,
+	0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x42, 0x75, 0x6c, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x27, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x11, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x53,
+	0x74, 0x61, 0x74, 0x65, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x35, 0x0a, 0x08, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74,
+	0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x64, 0x69, 0x72, 0x6b, 0x2e, 0x76,
+	0x31, 0x2e, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x64, 0x41, 0x63, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x52, 0x08, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x22, 0x7d, 0x0a, 0x10,
+	0x47, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x64, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74,
+	0x12, 0x18, 0x0a, 0x07, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x07, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x70, 0x75,
+	0x62, 0x6c, 0x69, 0x63, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09,
+	0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x4b, 0x65, 0x79, 0x12, 0x30, 0x0a, 0x0c, 0x70, 0x61, 0x72,
+	0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x0c, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x0c, 0x70,
+	0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x73, 0x32, 0x84, 0x01, 0x0a, 0x0c,
+	0x42, 0x75, 0x6c, 0x6b, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x12, 0x74, 0x0a, 0x0c,
+	0x47, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x42, 0x75, 0x6c, 0x6b, 0x12, 0x1c, 0x2e, 0x64,
+	0x69, 0x72, 0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x42,
+	0x75, 0x6c, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x64, 0x69, 0x72,
+	0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x42, 0x75, 0x6c,
+	0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x27, 0x82, 0xd3, 0xe4, 0x93, 0x02,
+	0x21, 0x22, 0x1f, 0x2f, 0x76, 0x31, 0x2f, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x6d, 0x61,
+	0x6e, 0x61, 0x67, 0x65, 0x72, 0x2f, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x62, 0x75,
+	0x6c, 0x6b, 0x42, 0x62, 0x0a, 0x14, 0x69, 0x6f, 0x2e, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x61,
+	0x6e, 0x74, 0x2e, 0x64, 0x69, 0x72, 0x6b, 0x2e, 0x76, 0x31, 0x42, 0x11, 0x42, 0x75, 0x6c, 0x6b,
+	0x47, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x50, 0x01, 0x5a,
+	0x21, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x61, 0x74, 0x74, 0x65,
+	0x73, 0x74, 0x61, 0x6e, 0x74, 0x69, 0x6f, 0x2f, 0x64, 0x69, 0x72, 0x6b, 0x2f, 0x70, 0x62, 0x2f,
+	0x76, 0x31, 0xaa, 0x02, 0x07, 0x44, 0x69, 0x72, 0x6b, 0x2e, 0x76, 0x31, 0xca, 0x02, 0x07, 0x44,
+	0x69, 0x72, 0x6b, 0x5c, 0x76, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_bulkgenerate_proto_rawDescOnce sync.Once
+	file_bulkgenerate_proto_rawDescData = file_bulkgenerate_proto_rawDesc
+)
+
+func file_bulkgenerate_proto_rawDescGZIP() []byte {
+	file_bulkgenerate_proto_rawDescOnce.Do(func() {
+		file_bulkgenerate_proto_rawDescData = protoimpl.X.CompressGZIP(file_bulkgenerate_proto_rawDescData)
+	})
+	return file_bulkgenerate_proto_rawDescData
+}
+
+var file_bulkgenerate_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_bulkgenerate_proto_goTypes = []interface{}{
+	(*GenerateBulkRequest)(nil),  // 0: dirk.v1.GenerateBulkRequest
+	(*GenerateBulkResponse)(nil), // 1: dirk.v1.GenerateBulkResponse
+	(*GeneratedAccount)(nil),     // 2: dirk.v1.GeneratedAccount
+	(v1.ResponseState)(0),        // 3: v1.ResponseState
+	(*v1.Endpoint)(nil),          // 4: v1.Endpoint
+}
+var file_bulkgenerate_proto_depIdxs = []int32{
+	3, // 0: dirk.v1.GenerateBulkResponse.state:type_name -> v1.ResponseState
+	2, // 1: dirk.v1.GenerateBulkResponse.accounts:type_name -> dirk.v1.GeneratedAccount
+	4, // 2: dirk.v1.GeneratedAccount.participants:type_name -> v1.Endpoint
+	0, // 3: dirk.v1.BulkGenerate.GenerateBulk:input_type -> dirk.v1.GenerateBulkRequest
+	1, // 4: dirk.v1.BulkGenerate.GenerateBulk:output_type -> dirk.v1.GenerateBulkResponse
+	4, // [4:5] is the sub-list for method output_type
+	3, // [3:4] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_bulkgenerate_proto_init() }
+func file_bulkgenerate_proto_init() {
+	if File_bulkgenerate_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_bulkgenerate_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GenerateBulkRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_bulkgenerate_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GenerateBulkResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_bulkgenerate_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GeneratedAccount); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_bulkgenerate_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_bulkgenerate_proto_goTypes,
+		DependencyIndexes: file_bulkgenerate_proto_depIdxs,
+		MessageInfos:      file_bulkgenerate_proto_msgTypes,
+	}.Build()
+	File_bulkgenerate_proto = out.File
+	file_bulkgenerate_proto_rawDesc = nil
+	file_bulkgenerate_proto_goTypes = nil
+	file_bulkgenerate_proto_depIdxs = nil
+}
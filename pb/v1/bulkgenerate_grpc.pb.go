@@ -0,0 +1,101 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package v1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// BulkGenerateClient is the client API for BulkGenerate service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type BulkGenerateClient interface {
+	GenerateBulk(ctx context.Context, in *GenerateBulkRequest, opts ...grpc.CallOption) (*GenerateBulkResponse, error)
+}
+
+type bulkGenerateClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewBulkGenerateClient(cc grpc.ClientConnInterface) BulkGenerateClient {
+	return &bulkGenerateClient{cc}
+}
+
+func (c *bulkGenerateClient) GenerateBulk(ctx context.Context, in *GenerateBulkRequest, opts ...grpc.CallOption) (*GenerateBulkResponse, error) {
+	out := new(GenerateBulkResponse)
+	err := c.cc.Invoke(ctx, "/dirk.v1.BulkGenerate/GenerateBulk", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// BulkGenerateServer is the server API for BulkGenerate service.
+// All implementations must embed UnimplementedBulkGenerateServer
+// for forward compatibility
+type BulkGenerateServer interface {
+	GenerateBulk(context.Context, *GenerateBulkRequest) (*GenerateBulkResponse, error)
+	mustEmbedUnimplementedBulkGenerateServer()
+}
+
+// UnimplementedBulkGenerateServer must be embedded to have forward compatible implementations.
+type UnimplementedBulkGenerateServer struct {
+}
+
+func (UnimplementedBulkGenerateServer) GenerateBulk(context.Context, *GenerateBulkRequest) (*GenerateBulkResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GenerateBulk not implemented")
+}
+func (UnimplementedBulkGenerateServer) mustEmbedUnimplementedBulkGenerateServer() {}
+
+// UnsafeBulkGenerateServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to BulkGenerateServer will
+// result in compilation errors.
+type UnsafeBulkGenerateServer interface {
+	mustEmbedUnimplementedBulkGenerateServer()
+}
+
+func RegisterBulkGenerateServer(s grpc.ServiceRegistrar, srv BulkGenerateServer) {
+	s.RegisterService(&BulkGenerate_ServiceDesc, srv)
+}
+
+func _BulkGenerate_GenerateBulk_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GenerateBulkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BulkGenerateServer).GenerateBulk(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dirk.v1.BulkGenerate/GenerateBulk",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BulkGenerateServer).GenerateBulk(ctx, req.(*GenerateBulkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// BulkGenerate_ServiceDesc is the grpc.ServiceDesc for BulkGenerate service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var BulkGenerate_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "dirk.v1.BulkGenerate",
+	HandlerType: (*BulkGenerateServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GenerateBulk",
+			Handler:    _BulkGenerate_GenerateBulk_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "bulkgenerate.proto",
+}
@@ -0,0 +1,373 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.27.1
+// 	protoc        v3.17.3
+// source: deposit.proto
+
+package v1
+
+import (
+	v1 "github.com/wealdtech/eth2-signer-api/pb/v1"
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type DepositDataRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Id:
+	//	*DepositDataRequest_Account
+	//	*DepositDataRequest_PublicKey
+	Id                    isDepositDataRequest_Id `protobuf_oneof:"id"`
+	WithdrawalCredentials []byte                  `protobuf:"bytes,3,opt,name=withdrawal_credentials,json=withdrawalCredentials,proto3" json:"withdrawal_credentials,omitempty"`
+	// amount is the amount of the deposit, in Gwei.
+	Amount      uint64 `protobuf:"varint,4,opt,name=amount,proto3" json:"amount,omitempty"`
+	ForkVersion []byte `protobuf:"bytes,5,opt,name=fork_version,json=forkVersion,proto3" json:"fork_version,omitempty"`
+}
+
+func (x *DepositDataRequest) Reset() {
+	*x = DepositDataRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_deposit_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DepositDataRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DepositDataRequest) ProtoMessage() {}
+
+func (x *DepositDataRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_deposit_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DepositDataRequest.ProtoReflect.Descriptor instead.
+func (*DepositDataRequest) Descriptor() ([]byte, []int) {
+	return file_deposit_proto_rawDescGZIP(), []int{0}
+}
+
+func (m *DepositDataRequest) GetId() isDepositDataRequest_Id {
+	if m != nil {
+		return m.Id
+	}
+	return nil
+}
+
+func (x *DepositDataRequest) GetAccount() string {
+	if x, ok := x.GetId().(*DepositDataRequest_Account); ok {
+		return x.Account
+	}
+	return ""
+}
+
+func (x *DepositDataRequest) GetPublicKey() []byte {
+	if x, ok := x.GetId().(*DepositDataRequest_PublicKey); ok {
+		return x.PublicKey
+	}
+	return nil
+}
+
+func (x *DepositDataRequest) GetWithdrawalCredentials() []byte {
+	if x != nil {
+		return x.WithdrawalCredentials
+	}
+	return nil
+}
+
+func (x *DepositDataRequest) GetAmount() uint64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *DepositDataRequest) GetForkVersion() []byte {
+	if x != nil {
+		return x.ForkVersion
+	}
+	return nil
+}
+
+type isDepositDataRequest_Id interface {
+	isDepositDataRequest_Id()
+}
+
+type DepositDataRequest_Account struct {
+	Account string `protobuf:"bytes,1,opt,name=account,proto3,oneof"`
+}
+
+type DepositDataRequest_PublicKey struct {
+	PublicKey []byte `protobuf:"bytes,2,opt,name=public_key,json=publicKey,proto3,oneof"`
+}
+
+func (*DepositDataRequest_Account) isDepositDataRequest_Id() {}
+
+func (*DepositDataRequest_PublicKey) isDepositDataRequest_Id() {}
+
+type DepositDataResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	State                 v1.ResponseState `protobuf:"varint,1,opt,name=state,proto3,enum=v1.ResponseState" json:"state,omitempty"`
+	PublicKey             []byte           `protobuf:"bytes,2,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+	WithdrawalCredentials []byte           `protobuf:"bytes,3,opt,name=withdrawal_credentials,json=withdrawalCredentials,proto3" json:"withdrawal_credentials,omitempty"`
+	Amount                uint64           `protobuf:"varint,4,opt,name=amount,proto3" json:"amount,omitempty"`
+	Signature             []byte           `protobuf:"bytes,5,opt,name=signature,proto3" json:"signature,omitempty"`
+	DepositMessageRoot    []byte           `protobuf:"bytes,6,opt,name=deposit_message_root,json=depositMessageRoot,proto3" json:"deposit_message_root,omitempty"`
+	DepositDataRoot       []byte           `protobuf:"bytes,7,opt,name=deposit_data_root,json=depositDataRoot,proto3" json:"deposit_data_root,omitempty"`
+	ForkVersion           []byte           `protobuf:"bytes,8,opt,name=fork_version,json=forkVersion,proto3" json:"fork_version,omitempty"`
+}
+
+func (x *DepositDataResponse) Reset() {
+	*x = DepositDataResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_deposit_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DepositDataResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DepositDataResponse) ProtoMessage() {}
+
+func (x *DepositDataResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_deposit_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DepositDataResponse.ProtoReflect.Descriptor instead.
+func (*DepositDataResponse) Descriptor() ([]byte, []int) {
+	return file_deposit_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *DepositDataResponse) GetState() v1.ResponseState {
+	if x != nil {
+		return x.State
+	}
+	return v1.ResponseState(0)
+}
+
+func (x *DepositDataResponse) GetPublicKey() []byte {
+	if x != nil {
+		return x.PublicKey
+	}
+	return nil
+}
+
+func (x *DepositDataResponse) GetWithdrawalCredentials() []byte {
+	if x != nil {
+		return x.WithdrawalCredentials
+	}
+	return nil
+}
+
+func (x *DepositDataResponse) GetAmount() uint64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *DepositDataResponse) GetSignature() []byte {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
+func (x *DepositDataResponse) GetDepositMessageRoot() []byte {
+	if x != nil {
+		return x.DepositMessageRoot
+	}
+	return nil
+}
+
+func (x *DepositDataResponse) GetDepositDataRoot() []byte {
+	if x != nil {
+		return x.DepositDataRoot
+	}
+	return nil
+}
+
+func (x *DepositDataResponse) GetForkVersion() []byte {
+	if x != nil {
+		return x.ForkVersion
+	}
+	return nil
+}
+
+var File_deposit_proto protoreflect.FileDescriptor
+
+var file_deposit_proto_rawDesc = []byte{
+	0x0a, 0x0d, 0x64, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12,
+	0x07, 0x64, 0x69, 0x72, 0x6b, 0x2e, 0x76, 0x31, 0x1a, 0x1c, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2f, 0x61, 0x70, 0x69, 0x2f, 0x61, 0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x13, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x73, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xc9, 0x01, 0x0a, 0x12,
+	0x44, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x44, 0x61, 0x74, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x1a, 0x0a, 0x07, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x07, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1f,
+	0x0a, 0x0a, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0c, 0x48, 0x00, 0x52, 0x09, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x4b, 0x65, 0x79, 0x12,
+	0x35, 0x0a, 0x16, 0x77, 0x69, 0x74, 0x68, 0x64, 0x72, 0x61, 0x77, 0x61, 0x6c, 0x5f, 0x63, 0x72,
+	0x65, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x15, 0x77, 0x69, 0x74, 0x68, 0x64, 0x72, 0x61, 0x77, 0x61, 0x6c, 0x43, 0x72, 0x65, 0x64, 0x65,
+	0x6e, 0x74, 0x69, 0x61, 0x6c, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x21,
+	0x0a, 0x0c, 0x66, 0x6f, 0x72, 0x6b, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x66, 0x6f, 0x72, 0x6b, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x42, 0x04, 0x0a, 0x02, 0x69, 0x64, 0x22, 0xcb, 0x02, 0x0a, 0x13, 0x44, 0x65, 0x70, 0x6f,
+	0x73, 0x69, 0x74, 0x44, 0x61, 0x74, 0x61, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x27, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x11,
+	0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x53, 0x74, 0x61, 0x74,
+	0x65, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x70, 0x75, 0x62, 0x6c,
+	0x69, 0x63, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x70, 0x75,
+	0x62, 0x6c, 0x69, 0x63, 0x4b, 0x65, 0x79, 0x12, 0x35, 0x0a, 0x16, 0x77, 0x69, 0x74, 0x68, 0x64,
+	0x72, 0x61, 0x77, 0x61, 0x6c, 0x5f, 0x63, 0x72, 0x65, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c,
+	0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x15, 0x77, 0x69, 0x74, 0x68, 0x64, 0x72, 0x61,
+	0x77, 0x61, 0x6c, 0x43, 0x72, 0x65, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c, 0x73, 0x12, 0x16,
+	0x0a, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06,
+	0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74,
+	0x75, 0x72, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61,
+	0x74, 0x75, 0x72, 0x65, 0x12, 0x30, 0x0a, 0x14, 0x64, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x5f,
+	0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x5f, 0x72, 0x6f, 0x6f, 0x74, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x12, 0x64, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x52, 0x6f, 0x6f, 0x74, 0x12, 0x2a, 0x0a, 0x11, 0x64, 0x65, 0x70, 0x6f, 0x73, 0x69,
+	0x74, 0x5f, 0x64, 0x61, 0x74, 0x61, 0x5f, 0x72, 0x6f, 0x6f, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x0f, 0x64, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x44, 0x61, 0x74, 0x61, 0x52, 0x6f,
+	0x6f, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x66, 0x6f, 0x72, 0x6b, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x66, 0x6f, 0x72, 0x6b, 0x56, 0x65,
+	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x32, 0x75, 0x0a, 0x07, 0x44, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74,
+	0x12, 0x6a, 0x0a, 0x13, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x44, 0x65, 0x70, 0x6f,
+	0x73, 0x69, 0x74, 0x44, 0x61, 0x74, 0x61, 0x12, 0x1b, 0x2e, 0x64, 0x69, 0x72, 0x6b, 0x2e, 0x76,
+	0x31, 0x2e, 0x44, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x44, 0x61, 0x74, 0x61, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x64, 0x69, 0x72, 0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x44,
+	0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x44, 0x61, 0x74, 0x61, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x22, 0x18, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x12, 0x22, 0x10, 0x2f, 0x76, 0x31, 0x2f,
+	0x64, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x2f, 0x64, 0x61, 0x74, 0x61, 0x42, 0x5d, 0x0a, 0x14,
+	0x69, 0x6f, 0x2e, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x61, 0x6e, 0x74, 0x2e, 0x64, 0x69, 0x72,
+	0x6b, 0x2e, 0x76, 0x31, 0x42, 0x0c, 0x44, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x50, 0x72, 0x6f,
+	0x74, 0x6f, 0x50, 0x01, 0x5a, 0x21, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d,
+	0x2f, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x61, 0x6e, 0x74, 0x69, 0x6f, 0x2f, 0x64, 0x69, 0x72,
+	0x6b, 0x2f, 0x70, 0x62, 0x2f, 0x76, 0x31, 0xaa, 0x02, 0x07, 0x44, 0x69, 0x72, 0x6b, 0x2e, 0x76,
+	0x31, 0xca, 0x02, 0x07, 0x44, 0x69, 0x72, 0x6b, 0x5c, 0x76, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x33,
+}
+
+var (
+	file_deposit_proto_rawDescOnce sync.Once
+	file_deposit_proto_rawDescData = file_deposit_proto_rawDesc
+)
+
+func file_deposit_proto_rawDescGZIP() []byte {
+	file_deposit_proto_rawDescOnce.Do(func() {
+		file_deposit_proto_rawDescData = protoimpl.X.CompressGZIP(file_deposit_proto_rawDescData)
+	})
+	return file_deposit_proto_rawDescData
+}
+
+var file_deposit_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_deposit_proto_goTypes = []interface{}{
+	(*DepositDataRequest)(nil),  // 0: dirk.v1.DepositDataRequest
+	(*DepositDataResponse)(nil), // 1: dirk.v1.DepositDataResponse
+	(v1.ResponseState)(0),       // 2: v1.ResponseState
+}
+var file_deposit_proto_depIdxs = []int32{
+	2, // 0: dirk.v1.DepositDataResponse.state:type_name -> v1.ResponseState
+	0, // 1: dirk.v1.Deposit.GenerateDepositData:input_type -> dirk.v1.DepositDataRequest
+	1, // 2: dirk.v1.Deposit.GenerateDepositData:output_type -> dirk.v1.DepositDataResponse
+	2, // [2:3] is the sub-list for method output_type
+	1, // [1:2] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_deposit_proto_init() }
+func file_deposit_proto_init() {
+	if File_deposit_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_deposit_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DepositDataRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_deposit_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DepositDataResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_deposit_proto_msgTypes[0].OneofWrappers = []interface{}{
+		(*DepositDataRequest_Account)(nil),
+		(*DepositDataRequest_PublicKey)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_deposit_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_deposit_proto_goTypes,
+		DependencyIndexes: file_deposit_proto_depIdxs,
+		MessageInfos:      file_deposit_proto_msgTypes,
+	}.Build()
+	File_deposit_proto = out.File
+	file_deposit_proto_rawDesc = nil
+	file_deposit_proto_goTypes = nil
+	file_deposit_proto_depIdxs = nil
+}
@@ -0,0 +1,101 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package v1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// DepositClient is the client API for Deposit service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type DepositClient interface {
+	GenerateDepositData(ctx context.Context, in *DepositDataRequest, opts ...grpc.CallOption) (*DepositDataResponse, error)
+}
+
+type depositClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewDepositClient(cc grpc.ClientConnInterface) DepositClient {
+	return &depositClient{cc}
+}
+
+func (c *depositClient) GenerateDepositData(ctx context.Context, in *DepositDataRequest, opts ...grpc.CallOption) (*DepositDataResponse, error) {
+	out := new(DepositDataResponse)
+	err := c.cc.Invoke(ctx, "/dirk.v1.Deposit/GenerateDepositData", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DepositServer is the server API for Deposit service.
+// All implementations must embed UnimplementedDepositServer
+// for forward compatibility
+type DepositServer interface {
+	GenerateDepositData(context.Context, *DepositDataRequest) (*DepositDataResponse, error)
+	mustEmbedUnimplementedDepositServer()
+}
+
+// UnimplementedDepositServer must be embedded to have forward compatible implementations.
+type UnimplementedDepositServer struct {
+}
+
+func (UnimplementedDepositServer) GenerateDepositData(context.Context, *DepositDataRequest) (*DepositDataResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GenerateDepositData not implemented")
+}
+func (UnimplementedDepositServer) mustEmbedUnimplementedDepositServer() {}
+
+// UnsafeDepositServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to DepositServer will
+// result in compilation errors.
+type UnsafeDepositServer interface {
+	mustEmbedUnimplementedDepositServer()
+}
+
+func RegisterDepositServer(s grpc.ServiceRegistrar, srv DepositServer) {
+	s.RegisterService(&Deposit_ServiceDesc, srv)
+}
+
+func _Deposit_GenerateDepositData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DepositDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DepositServer).GenerateDepositData(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dirk.v1.Deposit/GenerateDepositData",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DepositServer).GenerateDepositData(ctx, req.(*DepositDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Deposit_ServiceDesc is the grpc.ServiceDesc for Deposit service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Deposit_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "dirk.v1.Deposit",
+	HandlerType: (*DepositServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GenerateDepositData",
+			Handler:    _Deposit_GenerateDepositData_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "deposit.proto",
+}
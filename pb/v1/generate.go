@@ -0,0 +1,19 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package v1 contains the gRPC definitions for dirk-specific services.
+// Shared messages are imported from the eth2-signer-api definitions, so
+// ETH2_SIGNER_API must point at a checkout of that repository to generate.
+package v1
+
+//go:generate protoc -I ${ETH2_SIGNER_API}/third-party -I ${ETH2_SIGNER_API}/pb/v1 -I . --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative accountadmin.proto accountstatus.proto adminauth.proto blstoexecutionchange.proto bulkgenerate.proto deposit.proto peeradmin.proto reshare.proto rootsign.proto signcheck.proto taggedsign.proto validatorregistration.proto walletlister.proto
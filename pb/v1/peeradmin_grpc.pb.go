@@ -0,0 +1,137 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package v1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// PeerAdminClient is the client API for PeerAdmin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type PeerAdminClient interface {
+	ListPeers(ctx context.Context, in *ListPeersRequest, opts ...grpc.CallOption) (*PeersResponse, error)
+	UpdatePeers(ctx context.Context, in *UpdatePeersRequest, opts ...grpc.CallOption) (*PeersResponse, error)
+}
+
+type peerAdminClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPeerAdminClient(cc grpc.ClientConnInterface) PeerAdminClient {
+	return &peerAdminClient{cc}
+}
+
+func (c *peerAdminClient) ListPeers(ctx context.Context, in *ListPeersRequest, opts ...grpc.CallOption) (*PeersResponse, error) {
+	out := new(PeersResponse)
+	err := c.cc.Invoke(ctx, "/dirk.v1.PeerAdmin/ListPeers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peerAdminClient) UpdatePeers(ctx context.Context, in *UpdatePeersRequest, opts ...grpc.CallOption) (*PeersResponse, error) {
+	out := new(PeersResponse)
+	err := c.cc.Invoke(ctx, "/dirk.v1.PeerAdmin/UpdatePeers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PeerAdminServer is the server API for PeerAdmin service.
+// All implementations must embed UnimplementedPeerAdminServer
+// for forward compatibility
+type PeerAdminServer interface {
+	ListPeers(context.Context, *ListPeersRequest) (*PeersResponse, error)
+	UpdatePeers(context.Context, *UpdatePeersRequest) (*PeersResponse, error)
+	mustEmbedUnimplementedPeerAdminServer()
+}
+
+// UnimplementedPeerAdminServer must be embedded to have forward compatible implementations.
+type UnimplementedPeerAdminServer struct {
+}
+
+func (UnimplementedPeerAdminServer) ListPeers(context.Context, *ListPeersRequest) (*PeersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPeers not implemented")
+}
+func (UnimplementedPeerAdminServer) UpdatePeers(context.Context, *UpdatePeersRequest) (*PeersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdatePeers not implemented")
+}
+func (UnimplementedPeerAdminServer) mustEmbedUnimplementedPeerAdminServer() {}
+
+// UnsafePeerAdminServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PeerAdminServer will
+// result in compilation errors.
+type UnsafePeerAdminServer interface {
+	mustEmbedUnimplementedPeerAdminServer()
+}
+
+func RegisterPeerAdminServer(s grpc.ServiceRegistrar, srv PeerAdminServer) {
+	s.RegisterService(&PeerAdmin_ServiceDesc, srv)
+}
+
+func _PeerAdmin_ListPeers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPeersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeerAdminServer).ListPeers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dirk.v1.PeerAdmin/ListPeers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeerAdminServer).ListPeers(ctx, req.(*ListPeersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PeerAdmin_UpdatePeers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdatePeersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeerAdminServer).UpdatePeers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dirk.v1.PeerAdmin/UpdatePeers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeerAdminServer).UpdatePeers(ctx, req.(*UpdatePeersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// PeerAdmin_ServiceDesc is the grpc.ServiceDesc for PeerAdmin service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PeerAdmin_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "dirk.v1.PeerAdmin",
+	HandlerType: (*PeerAdminServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListPeers",
+			Handler:    _PeerAdmin_ListPeers_Handler,
+		},
+		{
+			MethodName: "UpdatePeers",
+			Handler:    _PeerAdmin_UpdatePeers_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "peeradmin.proto",
+}
@@ -0,0 +1,318 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package v1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// ReshareClient is the client API for Reshare service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ReshareClient interface {
+	Prepare(ctx context.Context, in *PrepareReshareRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	Execute(ctx context.Context, in *ReshareRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	Contribute(ctx context.Context, in *ContributeReshareRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	Confirm(ctx context.Context, in *ConfirmReshareRequest, opts ...grpc.CallOption) (*ConfirmReshareResponse, error)
+	Commit(ctx context.Context, in *ReshareRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	Complete(ctx context.Context, in *ReshareRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	Abort(ctx context.Context, in *ReshareRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+}
+
+type reshareClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewReshareClient(cc grpc.ClientConnInterface) ReshareClient {
+	return &reshareClient{cc}
+}
+
+func (c *reshareClient) Prepare(ctx context.Context, in *PrepareReshareRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/dirk.v1.Reshare/Prepare", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reshareClient) Execute(ctx context.Context, in *ReshareRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/dirk.v1.Reshare/Execute", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reshareClient) Contribute(ctx context.Context, in *ContributeReshareRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/dirk.v1.Reshare/Contribute", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reshareClient) Confirm(ctx context.Context, in *ConfirmReshareRequest, opts ...grpc.CallOption) (*ConfirmReshareResponse, error) {
+	out := new(ConfirmReshareResponse)
+	err := c.cc.Invoke(ctx, "/dirk.v1.Reshare/Confirm", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reshareClient) Commit(ctx context.Context, in *ReshareRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/dirk.v1.Reshare/Commit", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reshareClient) Complete(ctx context.Context, in *ReshareRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/dirk.v1.Reshare/Complete", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reshareClient) Abort(ctx context.Context, in *ReshareRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/dirk.v1.Reshare/Abort", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ReshareServer is the server API for Reshare service.
+// All implementations must embed UnimplementedReshareServer
+// for forward compatibility
+type ReshareServer interface {
+	Prepare(context.Context, *PrepareReshareRequest) (*emptypb.Empty, error)
+	Execute(context.Context, *ReshareRequest) (*emptypb.Empty, error)
+	Contribute(context.Context, *ContributeReshareRequest) (*emptypb.Empty, error)
+	Confirm(context.Context, *ConfirmReshareRequest) (*ConfirmReshareResponse, error)
+	Commit(context.Context, *ReshareRequest) (*emptypb.Empty, error)
+	Complete(context.Context, *ReshareRequest) (*emptypb.Empty, error)
+	Abort(context.Context, *ReshareRequest) (*emptypb.Empty, error)
+	mustEmbedUnimplementedReshareServer()
+}
+
+// UnimplementedReshareServer must be embedded to have forward compatible implementations.
+type UnimplementedReshareServer struct {
+}
+
+func (UnimplementedReshareServer) Prepare(context.Context, *PrepareReshareRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Prepare not implemented")
+}
+func (UnimplementedReshareServer) Execute(context.Context, *ReshareRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Execute not implemented")
+}
+func (UnimplementedReshareServer) Contribute(context.Context, *ContributeReshareRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Contribute not implemented")
+}
+func (UnimplementedReshareServer) Confirm(context.Context, *ConfirmReshareRequest) (*ConfirmReshareResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Confirm not implemented")
+}
+func (UnimplementedReshareServer) Commit(context.Context, *ReshareRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Commit not implemented")
+}
+func (UnimplementedReshareServer) Complete(context.Context, *ReshareRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Complete not implemented")
+}
+func (UnimplementedReshareServer) Abort(context.Context, *ReshareRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Abort not implemented")
+}
+func (UnimplementedReshareServer) mustEmbedUnimplementedReshareServer() {}
+
+// UnsafeReshareServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ReshareServer will
+// result in compilation errors.
+type UnsafeReshareServer interface {
+	mustEmbedUnimplementedReshareServer()
+}
+
+func RegisterReshareServer(s grpc.ServiceRegistrar, srv ReshareServer) {
+	s.RegisterService(&Reshare_ServiceDesc, srv)
+}
+
+func _Reshare_Prepare_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PrepareReshareRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReshareServer).Prepare(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dirk.v1.Reshare/Prepare",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReshareServer).Prepare(ctx, req.(*PrepareReshareRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Reshare_Execute_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReshareRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReshareServer).Execute(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dirk.v1.Reshare/Execute",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReshareServer).Execute(ctx, req.(*ReshareRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Reshare_Contribute_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ContributeReshareRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReshareServer).Contribute(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dirk.v1.Reshare/Contribute",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReshareServer).Contribute(ctx, req.(*ContributeReshareRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Reshare_Confirm_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConfirmReshareRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReshareServer).Confirm(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dirk.v1.Reshare/Confirm",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReshareServer).Confirm(ctx, req.(*ConfirmReshareRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Reshare_Commit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReshareRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReshareServer).Commit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dirk.v1.Reshare/Commit",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReshareServer).Commit(ctx, req.(*ReshareRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Reshare_Complete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReshareRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReshareServer).Complete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dirk.v1.Reshare/Complete",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReshareServer).Complete(ctx, req.(*ReshareRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Reshare_Abort_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReshareRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReshareServer).Abort(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dirk.v1.Reshare/Abort",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReshareServer).Abort(ctx, req.(*ReshareRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Reshare_ServiceDesc is the grpc.ServiceDesc for Reshare service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Reshare_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "dirk.v1.Reshare",
+	HandlerType: (*ReshareServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Prepare",
+			Handler:    _Reshare_Prepare_Handler,
+		},
+		{
+			MethodName: "Execute",
+			Handler:    _Reshare_Execute_Handler,
+		},
+		{
+			MethodName: "Contribute",
+			Handler:    _Reshare_Contribute_Handler,
+		},
+		{
+			MethodName: "Confirm",
+			Handler:    _Reshare_Confirm_Handler,
+		},
+		{
+			MethodName: "Commit",
+			Handler:    _Reshare_Commit_Handler,
+		},
+		{
+			MethodName: "Complete",
+			Handler:    _Reshare_Complete_Handler,
+		},
+		{
+			MethodName: "Abort",
+			Handler:    _Reshare_Abort_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "reshare.proto",
+}
@@ -0,0 +1,212 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.27.1
+// 	protoc        v3.17.3
+// source: rootsign.proto
+
+package v1
+
+import (
+	v1 "github.com/wealdtech/eth2-signer-api/pb/v1"
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type SignRootRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Id:
+	//	*SignRootRequest_Account
+	//	*SignRootRequest_PublicKey
+	Id isSignRootRequest_Id `protobuf_oneof:"id"`
+	// signing_root is the 32-byte signing root to sign.
+	SigningRoot []byte `protobuf:"bytes,3,opt,name=signing_root,json=signingRoot,proto3" json:"signing_root,omitempty"`
+}
+
+func (x *SignRootRequest) Reset() {
+	*x = SignRootRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rootsign_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SignRootRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignRootRequest) ProtoMessage() {}
+
+func (x *SignRootRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rootsign_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignRootRequest.ProtoReflect.Descriptor instead.
+func (*SignRootRequest) Descriptor() ([]byte, []int) {
+	return file_rootsign_proto_rawDescGZIP(), []int{0}
+}
+
+func (m *SignRootRequest) GetId() isSignRootRequest_Id {
+	if m != nil {
+		return m.Id
+	}
+	return nil
+}
+
+func (x *SignRootRequest) GetAccount() string {
+	if x, ok := x.GetId().(*SignRootRequest_Account); ok {
+		return x.Account
+	}
+	return ""
+}
+
+func (x *SignRootRequest) GetPublicKey() []byte {
+	if x, ok := x.GetId().(*SignRootRequest_PublicKey); ok {
+		return x.PublicKey
+	}
+	return nil
+}
+
+func (x *SignRootRequest) GetSigningRoot() []byte {
+	if x != nil {
+		return x.SigningRoot
+	}
+	return nil
+}
+
+type isSignRootRequest_Id interface {
+	isSignRootRequest_Id()
+}
+
+type SignRootRequest_Account struct {
+	Account string `protobuf:"bytes,1,opt,name=account,proto3,oneof"`
+}
+
+type SignRootRequest_PublicKey struct {
+	PublicKey []byte `protobuf:"bytes,2,opt,name=public_key,json=publicKey,proto3,oneof"`
+}
+
+func (*SignRootRequest_Account) isSignRootRequest_Id() {}
+
+func (*SignRootRequest_PublicKey) isSignRootRequest_Id() {}
+
+var File_rootsign_proto protoreflect.FileDescriptor
+
+var file_rootsign_proto_rawDesc = []byte{
+	0x0a, 0x0e, 0x72, 0x6f, 0x6f, 0x74, 0x73, 0x69, 0x67, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x12, 0x07, 0x64, 0x69, 0x72, 0x6b, 0x2e, 0x76, 0x31, 0x1a, 0x1c, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x61, 0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x0c, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x77, 0x0a, 0x0f, 0x53, 0x69, 0x67, 0x6e, 0x52, 0x6f, 0x6f,
+	0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1a, 0x0a, 0x07, 0x61, 0x63, 0x63, 0x6f,
+	0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x07, 0x61, 0x63, 0x63,
+	0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1f, 0x0a, 0x0a, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x5f, 0x6b,
+	0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x00, 0x52, 0x09, 0x70, 0x75, 0x62, 0x6c,
+	0x69, 0x63, 0x4b, 0x65, 0x79, 0x12, 0x21, 0x0a, 0x0c, 0x73, 0x69, 0x67, 0x6e, 0x69, 0x6e, 0x67,
+	0x5f, 0x72, 0x6f, 0x6f, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x73, 0x69, 0x67,
+	0x6e, 0x69, 0x6e, 0x67, 0x52, 0x6f, 0x6f, 0x74, 0x42, 0x04, 0x0a, 0x02, 0x69, 0x64, 0x32, 0x61,
+	0x0a, 0x0a, 0x52, 0x6f, 0x6f, 0x74, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x12, 0x53, 0x0a, 0x08,
+	0x53, 0x69, 0x67, 0x6e, 0x52, 0x6f, 0x6f, 0x74, 0x12, 0x18, 0x2e, 0x64, 0x69, 0x72, 0x6b, 0x2e,
+	0x76, 0x31, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x52, 0x6f, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x10, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x22, 0x1b, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x15, 0x22, 0x13, 0x2f, 0x76,
+	0x31, 0x2f, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x2f, 0x73, 0x69, 0x67, 0x6e, 0x72, 0x6f, 0x6f,
+	0x74, 0x42, 0x5e, 0x0a, 0x14, 0x69, 0x6f, 0x2e, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x61, 0x6e,
+	0x74, 0x2e, 0x64, 0x69, 0x72, 0x6b, 0x2e, 0x76, 0x31, 0x42, 0x0d, 0x52, 0x6f, 0x6f, 0x74, 0x53,
+	0x69, 0x67, 0x6e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x50, 0x01, 0x5a, 0x21, 0x67, 0x69, 0x74, 0x68,
+	0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x61, 0x6e, 0x74,
+	0x69, 0x6f, 0x2f, 0x64, 0x69, 0x72, 0x6b, 0x2f, 0x70, 0x62, 0x2f, 0x76, 0x31, 0xaa, 0x02, 0x07,
+	0x44, 0x69, 0x72, 0x6b, 0x2e, 0x76, 0x31, 0xca, 0x02, 0x07, 0x44, 0x69, 0x72, 0x6b, 0x5c, 0x76,
+	0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_rootsign_proto_rawDescOnce sync.Once
+	file_rootsign_proto_rawDescData = file_rootsign_proto_rawDesc
+)
+
+func file_rootsign_proto_rawDescGZIP() []byte {
+	file_rootsign_proto_rawDescOnce.Do(func() {
+		file_rootsign_proto_rawDescData = protoimpl.X.CompressGZIP(file_rootsign_proto_rawDescData)
+	})
+	return file_rootsign_proto_rawDescData
+}
+
+var file_rootsign_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_rootsign_proto_goTypes = []interface{}{
+	(*SignRootRequest)(nil), // 0: dirk.v1.SignRootRequest
+	(*v1.SignResponse)(nil), // 1: v1.SignResponse
+}
+var file_rootsign_proto_depIdxs = []int32{
+	0, // 0: dirk.v1.RootSigner.SignRoot:input_type -> dirk.v1.SignRootRequest
+	1, // 1: dirk.v1.RootSigner.SignRoot:output_type -> v1.SignResponse
+	1, // [1:2] is the sub-list for method output_type
+	0, // [0:1] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_rootsign_proto_init() }
+func file_rootsign_proto_init() {
+	if File_rootsign_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_rootsign_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SignRootRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_rootsign_proto_msgTypes[0].OneofWrappers = []interface{}{
+		(*SignRootRequest_Account)(nil),
+		(*SignRootRequest_PublicKey)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_rootsign_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_rootsign_proto_goTypes,
+		DependencyIndexes: file_rootsign_proto_depIdxs,
+		MessageInfos:      file_rootsign_proto_msgTypes,
+	}.Build()
+	File_rootsign_proto = out.File
+	file_rootsign_proto_rawDesc = nil
+	file_rootsign_proto_goTypes = nil
+	file_rootsign_proto_depIdxs = nil
+}
@@ -0,0 +1,102 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package v1
+
+import (
+	context "context"
+	v1 "github.com/wealdtech/eth2-signer-api/pb/v1"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// RootSignerClient is the client API for RootSigner service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type RootSignerClient interface {
+	SignRoot(ctx context.Context, in *SignRootRequest, opts ...grpc.CallOption) (*v1.SignResponse, error)
+}
+
+type rootSignerClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewRootSignerClient(cc grpc.ClientConnInterface) RootSignerClient {
+	return &rootSignerClient{cc}
+}
+
+func (c *rootSignerClient) SignRoot(ctx context.Context, in *SignRootRequest, opts ...grpc.CallOption) (*v1.SignResponse, error) {
+	out := new(v1.SignResponse)
+	err := c.cc.Invoke(ctx, "/dirk.v1.RootSigner/SignRoot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RootSignerServer is the server API for RootSigner service.
+// All implementations must embed UnimplementedRootSignerServer
+// for forward compatibility
+type RootSignerServer interface {
+	SignRoot(context.Context, *SignRootRequest) (*v1.SignResponse, error)
+	mustEmbedUnimplementedRootSignerServer()
+}
+
+// UnimplementedRootSignerServer must be embedded to have forward compatible implementations.
+type UnimplementedRootSignerServer struct {
+}
+
+func (UnimplementedRootSignerServer) SignRoot(context.Context, *SignRootRequest) (*v1.SignResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SignRoot not implemented")
+}
+func (UnimplementedRootSignerServer) mustEmbedUnimplementedRootSignerServer() {}
+
+// UnsafeRootSignerServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to RootSignerServer will
+// result in compilation errors.
+type UnsafeRootSignerServer interface {
+	mustEmbedUnimplementedRootSignerServer()
+}
+
+func RegisterRootSignerServer(s grpc.ServiceRegistrar, srv RootSignerServer) {
+	s.RegisterService(&RootSigner_ServiceDesc, srv)
+}
+
+func _RootSigner_SignRoot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignRootRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RootSignerServer).SignRoot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dirk.v1.RootSigner/SignRoot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RootSignerServer).SignRoot(ctx, req.(*SignRootRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// RootSigner_ServiceDesc is the grpc.ServiceDesc for RootSigner service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var RootSigner_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "dirk.v1.RootSigner",
+	HandlerType: (*RootSignerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SignRoot",
+			Handler:    _RootSigner_SignRoot_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "rootsign.proto",
+}
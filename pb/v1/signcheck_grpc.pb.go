@@ -0,0 +1,174 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package v1
+
+import (
+	context "context"
+	v1 "github.com/wealdtech/eth2-signer-api/pb/v1"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// SignCheckClient is the client API for SignCheck service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type SignCheckClient interface {
+	CheckSign(ctx context.Context, in *v1.SignRequest, opts ...grpc.CallOption) (*CheckResponse, error)
+	CheckSignBeaconAttestation(ctx context.Context, in *v1.SignBeaconAttestationRequest, opts ...grpc.CallOption) (*CheckResponse, error)
+	CheckSignBeaconProposal(ctx context.Context, in *v1.SignBeaconProposalRequest, opts ...grpc.CallOption) (*CheckResponse, error)
+}
+
+type signCheckClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSignCheckClient(cc grpc.ClientConnInterface) SignCheckClient {
+	return &signCheckClient{cc}
+}
+
+func (c *signCheckClient) CheckSign(ctx context.Context, in *v1.SignRequest, opts ...grpc.CallOption) (*CheckResponse, error) {
+	out := new(CheckResponse)
+	err := c.cc.Invoke(ctx, "/dirk.v1.SignCheck/CheckSign", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *signCheckClient) CheckSignBeaconAttestation(ctx context.Context, in *v1.SignBeaconAttestationRequest, opts ...grpc.CallOption) (*CheckResponse, error) {
+	out := new(CheckResponse)
+	err := c.cc.Invoke(ctx, "/dirk.v1.SignCheck/CheckSignBeaconAttestation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *signCheckClient) CheckSignBeaconProposal(ctx context.Context, in *v1.SignBeaconProposalRequest, opts ...grpc.CallOption) (*CheckResponse, error) {
+	out := new(CheckResponse)
+	err := c.cc.Invoke(ctx, "/dirk.v1.SignCheck/CheckSignBeaconProposal", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SignCheckServer is the server API for SignCheck service.
+// All implementations must embed UnimplementedSignCheckServer
+// for forward compatibility
+type SignCheckServer interface {
+	CheckSign(context.Context, *v1.SignRequest) (*CheckResponse, error)
+	CheckSignBeaconAttestation(context.Context, *v1.SignBeaconAttestationRequest) (*CheckResponse, error)
+	CheckSignBeaconProposal(context.Context, *v1.SignBeaconProposalRequest) (*CheckResponse, error)
+	mustEmbedUnimplementedSignCheckServer()
+}
+
+// UnimplementedSignCheckServer must be embedded to have forward compatible implementations.
+type UnimplementedSignCheckServer struct {
+}
+
+func (UnimplementedSignCheckServer) CheckSign(context.Context, *v1.SignRequest) (*CheckResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckSign not implemented")
+}
+func (UnimplementedSignCheckServer) CheckSignBeaconAttestation(context.Context, *v1.SignBeaconAttestationRequest) (*CheckResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckSignBeaconAttestation not implemented")
+}
+func (UnimplementedSignCheckServer) CheckSignBeaconProposal(context.Context, *v1.SignBeaconProposalRequest) (*CheckResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckSignBeaconProposal not implemented")
+}
+func (UnimplementedSignCheckServer) mustEmbedUnimplementedSignCheckServer() {}
+
+// UnsafeSignCheckServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SignCheckServer will
+// result in compilation errors.
+type UnsafeSignCheckServer interface {
+	mustEmbedUnimplementedSignCheckServer()
+}
+
+func RegisterSignCheckServer(s grpc.ServiceRegistrar, srv SignCheckServer) {
+	s.RegisterService(&SignCheck_ServiceDesc, srv)
+}
+
+func _SignCheck_CheckSign_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(v1.SignRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SignCheckServer).CheckSign(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dirk.v1.SignCheck/CheckSign",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SignCheckServer).CheckSign(ctx, req.(*v1.SignRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SignCheck_CheckSignBeaconAttestation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(v1.SignBeaconAttestationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SignCheckServer).CheckSignBeaconAttestation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dirk.v1.SignCheck/CheckSignBeaconAttestation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SignCheckServer).CheckSignBeaconAttestation(ctx, req.(*v1.SignBeaconAttestationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SignCheck_CheckSignBeaconProposal_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(v1.SignBeaconProposalRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SignCheckServer).CheckSignBeaconProposal(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dirk.v1.SignCheck/CheckSignBeaconProposal",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SignCheckServer).CheckSignBeaconProposal(ctx, req.(*v1.SignBeaconProposalRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// SignCheck_ServiceDesc is the grpc.ServiceDesc for SignCheck service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var SignCheck_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "dirk.v1.SignCheck",
+	HandlerType: (*SignCheckServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CheckSign",
+			Handler:    _SignCheck_CheckSign_Handler,
+		},
+		{
+			MethodName: "CheckSignBeaconAttestation",
+			Handler:    _SignCheck_CheckSignBeaconAttestation_Handler,
+		},
+		{
+			MethodName: "CheckSignBeaconProposal",
+			Handler:    _SignCheck_CheckSignBeaconProposal_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "signcheck.proto",
+}
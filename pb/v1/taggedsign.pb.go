@@ -0,0 +1,232 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.27.1
+// 	protoc        v3.17.3
+// source: taggedsign.proto
+
+package v1
+
+import (
+	v1 "github.com/wealdtech/eth2-signer-api/pb/v1"
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type SignTaggedRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Id:
+	//	*SignTaggedRequest_Account
+	//	*SignTaggedRequest_PublicKey
+	Id isSignTaggedRequest_Id `protobuf_oneof:"id"`
+	// tag is the type of the message being signed.
+	Tag string `protobuf:"bytes,3,opt,name=tag,proto3" json:"tag,omitempty"`
+	// data is the hash tree root of the message being signed.
+	Data   []byte `protobuf:"bytes,4,opt,name=data,proto3" json:"data,omitempty"`
+	Domain []byte `protobuf:"bytes,5,opt,name=domain,proto3" json:"domain,omitempty"`
+}
+
+func (x *SignTaggedRequest) Reset() {
+	*x = SignTaggedRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taggedsign_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SignTaggedRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignTaggedRequest) ProtoMessage() {}
+
+func (x *SignTaggedRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_taggedsign_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignTaggedRequest.ProtoReflect.Descriptor instead.
+func (*SignTaggedRequest) Descriptor() ([]byte, []int) {
+	return file_taggedsign_proto_rawDescGZIP(), []int{0}
+}
+
+func (m *SignTaggedRequest) GetId() isSignTaggedRequest_Id {
+	if m != nil {
+		return m.Id
+	}
+	return nil
+}
+
+func (x *SignTaggedRequest) GetAccount() string {
+	if x, ok := x.GetId().(*SignTaggedRequest_Account); ok {
+		return x.Account
+	}
+	return ""
+}
+
+func (x *SignTaggedRequest) GetPublicKey() []byte {
+	if x, ok := x.GetId().(*SignTaggedRequest_PublicKey); ok {
+		return x.PublicKey
+	}
+	return nil
+}
+
+func (x *SignTaggedRequest) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
+func (x *SignTaggedRequest) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *SignTaggedRequest) GetDomain() []byte {
+	if x != nil {
+		return x.Domain
+	}
+	return nil
+}
+
+type isSignTaggedRequest_Id interface {
+	isSignTaggedRequest_Id()
+}
+
+type SignTaggedRequest_Account struct {
+	Account string `protobuf:"bytes,1,opt,name=account,proto3,oneof"`
+}
+
+type SignTaggedRequest_PublicKey struct {
+	PublicKey []byte `protobuf:"bytes,2,opt,name=public_key,json=publicKey,proto3,oneof"`
+}
+
+func (*SignTaggedRequest_Account) isSignTaggedRequest_Id() {}
+
+func (*SignTaggedRequest_PublicKey) isSignTaggedRequest_Id() {}
+
+var File_taggedsign_proto protoreflect.FileDescriptor
+
+var file_taggedsign_proto_rawDesc = []byte{
+	0x0a, 0x10, 0x74, 0x61, 0x67, 0x67, 0x65, 0x64, 0x73, 0x69, 0x67, 0x6e, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x12, 0x07, 0x64, 0x69, 0x72, 0x6b, 0x2e, 0x76, 0x31, 0x1a, 0x1c, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x61, 0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x0c, 0x73, 0x69, 0x67, 0x6e, 0x65,
+	0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x94, 0x01, 0x0a, 0x11, 0x53, 0x69, 0x67, 0x6e,
+	0x54, 0x61, 0x67, 0x67, 0x65, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1a, 0x0a,
+	0x07, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00,
+	0x52, 0x07, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1f, 0x0a, 0x0a, 0x70, 0x75, 0x62,
+	0x6c, 0x69, 0x63, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x00, 0x52,
+	0x09, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x4b, 0x65, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x74, 0x61,
+	0x67, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x74, 0x61, 0x67, 0x12, 0x12, 0x0a, 0x04,
+	0x64, 0x61, 0x74, 0x61, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61,
+	0x12, 0x16, 0x0a, 0x06, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x06, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x42, 0x04, 0x0a, 0x02, 0x69, 0x64, 0x32, 0x69,
+	0x0a, 0x0c, 0x54, 0x61, 0x67, 0x67, 0x65, 0x64, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x12, 0x59,
+	0x0a, 0x0a, 0x53, 0x69, 0x67, 0x6e, 0x54, 0x61, 0x67, 0x67, 0x65, 0x64, 0x12, 0x1a, 0x2e, 0x64,
+	0x69, 0x72, 0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x54, 0x61, 0x67, 0x67, 0x65,
+	0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x10, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x69,
+	0x67, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x1d, 0x82, 0xd3, 0xe4, 0x93,
+	0x02, 0x17, 0x22, 0x15, 0x2f, 0x76, 0x31, 0x2f, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x2f, 0x73,
+	0x69, 0x67, 0x6e, 0x74, 0x61, 0x67, 0x67, 0x65, 0x64, 0x42, 0x60, 0x0a, 0x14, 0x69, 0x6f, 0x2e,
+	0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x61, 0x6e, 0x74, 0x2e, 0x64, 0x69, 0x72, 0x6b, 0x2e, 0x76,
+	0x31, 0x42, 0x0f, 0x54, 0x61, 0x67, 0x67, 0x65, 0x64, 0x53, 0x69, 0x67, 0x6e, 0x50, 0x72, 0x6f,
+	0x74, 0x6f, 0x50, 0x01, 0x5a, 0x21, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d,
+	0x2f, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x61, 0x6e, 0x74, 0x69, 0x6f, 0x2f, 0x64, 0x69, 0x72,
+	0x6b, 0x2f, 0x70, 0x62, 0x2f, 0x76, 0x31, 0xaa, 0x02, 0x07, 0x44, 0x69, 0x72, 0x6b, 0x2e, 0x76,
+	0x31, 0xca, 0x02, 0x07, 0x44, 0x69, 0x72, 0x6b, 0x5c, 0x76, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x33,
+}
+
+var (
+	file_taggedsign_proto_rawDescOnce sync.Once
+	file_taggedsign_proto_rawDescData = file_taggedsign_proto_rawDesc
+)
+
+func file_taggedsign_proto_rawDescGZIP() []byte {
+	file_taggedsign_proto_rawDescOnce.Do(func() {
+		file_taggedsign_proto_rawDescData = protoimpl.X.CompressGZIP(file_taggedsign_proto_rawDescData)
+	})
+	return file_taggedsign_proto_rawDescData
+}
+
+var file_taggedsign_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_taggedsign_proto_goTypes = []interface{}{
+	(*SignTaggedRequest)(nil), // 0: dirk.v1.SignTaggedRequest
+	(*v1.SignResponse)(nil),   // 1: v1.SignResponse
+}
+var file_taggedsign_proto_depIdxs = []int32{
+	0, // 0: dirk.v1.TaggedSigner.SignTagged:input_type -> dirk.v1.SignTaggedRequest
+	1, // 1: dirk.v1.TaggedSigner.SignTagged:output_type -> v1.SignResponse
+	1, // [1:2] is the sub-list for method output_type
+	0, // [0:1] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_taggedsign_proto_init() }
+func file_taggedsign_proto_init() {
+	if File_taggedsign_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_taggedsign_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SignTaggedRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_taggedsign_proto_msgTypes[0].OneofWrappers = []interface{}{
+		(*SignTaggedRequest_Account)(nil),
+		(*SignTaggedRequest_PublicKey)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_taggedsign_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_taggedsign_proto_goTypes,
+		DependencyIndexes: file_taggedsign_proto_depIdxs,
+		MessageInfos:      file_taggedsign_proto_msgTypes,
+	}.Build()
+	File_taggedsign_proto = out.File
+	file_taggedsign_proto_rawDesc = nil
+	file_taggedsign_proto_goTypes = nil
+	file_taggedsign_proto_depIdxs = nil
+}
@@ -0,0 +1,102 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package v1
+
+import (
+	context "context"
+	v1 "github.com/wealdtech/eth2-signer-api/pb/v1"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// TaggedSignerClient is the client API for TaggedSigner service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type TaggedSignerClient interface {
+	SignTagged(ctx context.Context, in *SignTaggedRequest, opts ...grpc.CallOption) (*v1.SignResponse, error)
+}
+
+type taggedSignerClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTaggedSignerClient(cc grpc.ClientConnInterface) TaggedSignerClient {
+	return &taggedSignerClient{cc}
+}
+
+func (c *taggedSignerClient) SignTagged(ctx context.Context, in *SignTaggedRequest, opts ...grpc.CallOption) (*v1.SignResponse, error) {
+	out := new(v1.SignResponse)
+	err := c.cc.Invoke(ctx, "/dirk.v1.TaggedSigner/SignTagged", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TaggedSignerServer is the server API for TaggedSigner service.
+// All implementations must embed UnimplementedTaggedSignerServer
+// for forward compatibility
+type TaggedSignerServer interface {
+	SignTagged(context.Context, *SignTaggedRequest) (*v1.SignResponse, error)
+	mustEmbedUnimplementedTaggedSignerServer()
+}
+
+// UnimplementedTaggedSignerServer must be embedded to have forward compatible implementations.
+type UnimplementedTaggedSignerServer struct {
+}
+
+func (UnimplementedTaggedSignerServer) SignTagged(context.Context, *SignTaggedRequest) (*v1.SignResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SignTagged not implemented")
+}
+func (UnimplementedTaggedSignerServer) mustEmbedUnimplementedTaggedSignerServer() {}
+
+// UnsafeTaggedSignerServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TaggedSignerServer will
+// result in compilation errors.
+type UnsafeTaggedSignerServer interface {
+	mustEmbedUnimplementedTaggedSignerServer()
+}
+
+func RegisterTaggedSignerServer(s grpc.ServiceRegistrar, srv TaggedSignerServer) {
+	s.RegisterService(&TaggedSigner_ServiceDesc, srv)
+}
+
+func _TaggedSigner_SignTagged_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignTaggedRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaggedSignerServer).SignTagged(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dirk.v1.TaggedSigner/SignTagged",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaggedSignerServer).SignTagged(ctx, req.(*SignTaggedRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// TaggedSigner_ServiceDesc is the grpc.ServiceDesc for TaggedSigner service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TaggedSigner_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "dirk.v1.TaggedSigner",
+	HandlerType: (*TaggedSignerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SignTagged",
+			Handler:    _TaggedSigner_SignTagged_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "taggedsign.proto",
+}
@@ -0,0 +1,251 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.27.1
+// 	protoc        v3.17.3
+// source: validatorregistration.proto
+
+package v1
+
+import (
+	v1 "github.com/wealdtech/eth2-signer-api/pb/v1"
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type SignValidatorRegistrationRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Id:
+	//	*SignValidatorRegistrationRequest_Account
+	//	*SignValidatorRegistrationRequest_PublicKey
+	Id           isSignValidatorRegistrationRequest_Id `protobuf_oneof:"id"`
+	FeeRecipient []byte                                `protobuf:"bytes,3,opt,name=fee_recipient,json=feeRecipient,proto3" json:"fee_recipient,omitempty"`
+	GasLimit     uint64                                `protobuf:"varint,4,opt,name=gas_limit,json=gasLimit,proto3" json:"gas_limit,omitempty"`
+	// timestamp is the time of the registration, in seconds since the Unix epoch.
+	Timestamp uint64 `protobuf:"varint,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// genesis_fork_version is used to compute the signing domain.
+	GenesisForkVersion []byte `protobuf:"bytes,6,opt,name=genesis_fork_version,json=genesisForkVersion,proto3" json:"genesis_fork_version,omitempty"`
+}
+
+func (x *SignValidatorRegistrationRequest) Reset() {
+	*x = SignValidatorRegistrationRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_validatorregistration_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SignValidatorRegistrationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignValidatorRegistrationRequest) ProtoMessage() {}
+
+func (x *SignValidatorRegistrationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_validatorregistration_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignValidatorRegistrationRequest.ProtoReflect.Descriptor instead.
+func (*SignValidatorRegistrationRequest) Descriptor() ([]byte, []int) {
+	return file_validatorregistration_proto_rawDescGZIP(), []int{0}
+}
+
+func (m *SignValidatorRegistrationRequest) GetId() isSignValidatorRegistrationRequest_Id {
+	if m != nil {
+		return m.Id
+	}
+	return nil
+}
+
+func (x *SignValidatorRegistrationRequest) GetAccount() string {
+	if x, ok := x.GetId().(*SignValidatorRegistrationRequest_Account); ok {
+		return x.Account
+	}
+	return ""
+}
+
+func (x *SignValidatorRegistrationRequest) GetPublicKey() []byte {
+	if x, ok := x.GetId().(*SignValidatorRegistrationRequest_PublicKey); ok {
+		return x.PublicKey
+	}
+	return nil
+}
+
+func (x *SignValidatorRegistrationRequest) GetFeeRecipient() []byte {
+	if x != nil {
+		return x.FeeRecipient
+	}
+	return nil
+}
+
+func (x *SignValidatorRegistrationRequest) GetGasLimit() uint64 {
+	if x != nil {
+		return x.GasLimit
+	}
+	return 0
+}
+
+func (x *SignValidatorRegistrationRequest) GetTimestamp() uint64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *SignValidatorRegistrationRequest) GetGenesisForkVersion() []byte {
+	if x != nil {
+		return x.GenesisForkVersion
+	}
+	return nil
+}
+
+type isSignValidatorRegistrationRequest_Id interface {
+	isSignValidatorRegistrationRequest_Id()
+}
+
+type SignValidatorRegistrationRequest_Account struct {
+	Account string `protobuf:"bytes,1,opt,name=account,proto3,oneof"`
+}
+
+type SignValidatorRegistrationRequest_PublicKey struct {
+	PublicKey []byte `protobuf:"bytes,2,opt,name=public_key,json=publicKey,proto3,oneof"`
+}
+
+func (*SignValidatorRegistrationRequest_Account) isSignValidatorRegistrationRequest_Id() {}
+
+func (*SignValidatorRegistrationRequest_PublicKey) isSignValidatorRegistrationRequest_Id() {}
+
+var File_validatorregistration_proto protoreflect.FileDescriptor
+
+var file_validatorregistration_proto_rawDesc = []byte{
+	0x0a, 0x1b, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x6f, 0x72, 0x72, 0x65, 0x67, 0x69, 0x73,
+	0x74, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x07, 0x64,
+	0x69, 0x72, 0x6b, 0x2e, 0x76, 0x31, 0x1a, 0x1c, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x61,
+	0x70, 0x69, 0x2f, 0x61, 0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x0c, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x22, 0xf7, 0x01, 0x0a, 0x20, 0x53, 0x69, 0x67, 0x6e, 0x56, 0x61, 0x6c, 0x69, 0x64,
+	0x61, 0x74, 0x6f, 0x72, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1a, 0x0a, 0x07, 0x61, 0x63, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x07, 0x61, 0x63, 0x63, 0x6f,
+	0x75, 0x6e, 0x74, 0x12, 0x1f, 0x0a, 0x0a, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x5f, 0x6b, 0x65,
+	0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x00, 0x52, 0x09, 0x70, 0x75, 0x62, 0x6c, 0x69,
+	0x63, 0x4b, 0x65, 0x79, 0x12, 0x23, 0x0a, 0x0d, 0x66, 0x65, 0x65, 0x5f, 0x72, 0x65, 0x63, 0x69,
+	0x70, 0x69, 0x65, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0c, 0x66, 0x65, 0x65,
+	0x52, 0x65, 0x63, 0x69, 0x70, 0x69, 0x65, 0x6e, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x67, 0x61, 0x73,
+	0x5f, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x67, 0x61,
+	0x73, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x12, 0x30, 0x0a, 0x14, 0x67, 0x65, 0x6e, 0x65, 0x73, 0x69, 0x73, 0x5f,
+	0x66, 0x6f, 0x72, 0x6b, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x12, 0x67, 0x65, 0x6e, 0x65, 0x73, 0x69, 0x73, 0x46, 0x6f, 0x72, 0x6b, 0x56,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x42, 0x04, 0x0a, 0x02, 0x69, 0x64, 0x32, 0xa6, 0x01, 0x0a,
+	0x1b, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x6f, 0x72, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x12, 0x86, 0x01, 0x0a,
+	0x19, 0x53, 0x69, 0x67, 0x6e, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x6f, 0x72, 0x52, 0x65,
+	0x67, 0x69, 0x73, 0x74, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x29, 0x2e, 0x64, 0x69, 0x72,
+	0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74,
+	0x6f, 0x72, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x10, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x2c, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x26, 0x22,
+	0x24, 0x2f, 0x76, 0x31, 0x2f, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x2f, 0x73, 0x69, 0x67, 0x6e,
+	0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x6f, 0x72, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x42, 0x6b, 0x0a, 0x14, 0x69, 0x6f, 0x2e, 0x61, 0x74, 0x74, 0x65,
+	0x73, 0x74, 0x61, 0x6e, 0x74, 0x2e, 0x64, 0x69, 0x72, 0x6b, 0x2e, 0x76, 0x31, 0x42, 0x1a, 0x56,
+	0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x6f, 0x72, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x50, 0x01, 0x5a, 0x21, 0x67, 0x69, 0x74,
+	0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x61, 0x6e,
+	0x74, 0x69, 0x6f, 0x2f, 0x64, 0x69, 0x72, 0x6b, 0x2f, 0x70, 0x62, 0x2f, 0x76, 0x31, 0xaa, 0x02,
+	0x07, 0x44, 0x69, 0x72, 0x6b, 0x2e, 0x76, 0x31, 0xca, 0x02, 0x07, 0x44, 0x69, 0x72, 0x6b, 0x5c,
+	0x76, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_validatorregistration_proto_rawDescOnce sync.Once
+	file_validatorregistration_proto_rawDescData = file_validatorregistration_proto_rawDesc
+)
+
+func file_validatorregistration_proto_rawDescGZIP() []byte {
+	file_validatorregistration_proto_rawDescOnce.Do(func() {
+		file_validatorregistration_proto_rawDescData = protoimpl.X.CompressGZIP(file_validatorregistration_proto_rawDescData)
+	})
+	return file_validatorregistration_proto_rawDescData
+}
+
+var file_validatorregistration_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_validatorregistration_proto_goTypes = []interface{}{
+	(*SignValidatorRegistrationRequest)(nil), // 0: dirk.v1.SignValidatorRegistrationRequest
+	(*v1.SignResponse)(nil),                  // 1: v1.SignResponse
+}
+var file_validatorregistration_proto_depIdxs = []int32{
+	0, // 0: dirk.v1.ValidatorRegistrationSigner.SignValidatorRegistration:input_type -> dirk.v1.SignValidatorRegistrationRequest
+	1, // 1: dirk.v1.ValidatorRegistrationSigner.SignValidatorRegistration:output_type -> v1.SignResponse
+	1, // [1:2] is the sub-list for method output_type
+	0, // [0:1] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_validatorregistration_proto_init() }
+func file_validatorregistration_proto_init() {
+	if File_validatorregistration_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_validatorregistration_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SignValidatorRegistrationRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_validatorregistration_proto_msgTypes[0].OneofWrappers = []interface{}{
+		(*SignValidatorRegistrationRequest_Account)(nil),
+		(*SignValidatorRegistrationRequest_PublicKey)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_validatorregistration_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_validatorregistration_proto_goTypes,
+		DependencyIndexes: file_validatorregistration_proto_depIdxs,
+		MessageInfos:      file_validatorregistration_proto_msgTypes,
+	}.Build()
+	File_validatorregistration_proto = out.File
+	file_validatorregistration_proto_rawDesc = nil
+	file_validatorregistration_proto_goTypes = nil
+	file_validatorregistration_proto_depIdxs = nil
+}
@@ -0,0 +1,103 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package v1
+
+import (
+	context "context"
+	v1 "github.com/wealdtech/eth2-signer-api/pb/v1"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// ValidatorRegistrationSignerClient is the client API for ValidatorRegistrationSigner service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ValidatorRegistrationSignerClient interface {
+	SignValidatorRegistration(ctx context.Context, in *SignValidatorRegistrationRequest, opts ...grpc.CallOption) (*v1.SignResponse, error)
+}
+
+type validatorRegistrationSignerClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewValidatorRegistrationSignerClient(cc grpc.ClientConnInterface) ValidatorRegistrationSignerClient {
+	return &validatorRegistrationSignerClient{cc}
+}
+
+func (c *validatorRegistrationSignerClient) SignValidatorRegistration(ctx context.Context, in *SignValidatorRegistrationRequest, opts ...grpc.CallOption) (*v1.SignResponse, error) {
+	out := new(v1.SignResponse)
+	err := c.cc.Invoke(ctx, "/dirk.v1.ValidatorRegistrationSigner/SignValidatorRegistration", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ValidatorRegistrationSignerServer is the server API for ValidatorRegistrationSigner service.
+// All implementations must embed UnimplementedValidatorRegistrationSignerServer
+// for forward compatibility
+type ValidatorRegistrationSignerServer interface {
+	SignValidatorRegistration(context.Context, *SignValidatorRegistrationRequest) (*v1.SignResponse, error)
+	mustEmbedUnimplementedValidatorRegistrationSignerServer()
+}
+
+// UnimplementedValidatorRegistrationSignerServer must be embedded to have forward compatible implementations.
+type UnimplementedValidatorRegistrationSignerServer struct {
+}
+
+func (UnimplementedValidatorRegistrationSignerServer) SignValidatorRegistration(context.Context, *SignValidatorRegistrationRequest) (*v1.SignResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SignValidatorRegistration not implemented")
+}
+func (UnimplementedValidatorRegistrationSignerServer) mustEmbedUnimplementedValidatorRegistrationSignerServer() {
+}
+
+// UnsafeValidatorRegistrationSignerServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ValidatorRegistrationSignerServer will
+// result in compilation errors.
+type UnsafeValidatorRegistrationSignerServer interface {
+	mustEmbedUnimplementedValidatorRegistrationSignerServer()
+}
+
+func RegisterValidatorRegistrationSignerServer(s grpc.ServiceRegistrar, srv ValidatorRegistrationSignerServer) {
+	s.RegisterService(&ValidatorRegistrationSigner_ServiceDesc, srv)
+}
+
+func _ValidatorRegistrationSigner_SignValidatorRegistration_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignValidatorRegistrationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ValidatorRegistrationSignerServer).SignValidatorRegistration(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dirk.v1.ValidatorRegistrationSigner/SignValidatorRegistration",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ValidatorRegistrationSignerServer).SignValidatorRegistration(ctx, req.(*SignValidatorRegistrationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ValidatorRegistrationSigner_ServiceDesc is the grpc.ServiceDesc for ValidatorRegistrationSigner service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ValidatorRegistrationSigner_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "dirk.v1.ValidatorRegistrationSigner",
+	HandlerType: (*ValidatorRegistrationSignerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SignValidatorRegistration",
+			Handler:    _ValidatorRegistrationSigner_SignValidatorRegistration_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "validatorregistration.proto",
+}
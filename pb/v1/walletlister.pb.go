@@ -0,0 +1,292 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.27.1
+// 	protoc        v3.17.3
+// source: walletlister.proto
+
+package v1
+
+import (
+	v1 "github.com/wealdtech/eth2-signer-api/pb/v1"
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ListAccountsByWalletResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	State   v1.ResponseState  `protobuf:"varint,1,opt,name=state,proto3,enum=v1.ResponseState" json:"state,omitempty"`
+	Wallets []*WalletAccounts `protobuf:"bytes,2,rep,name=wallets,proto3" json:"wallets,omitempty"`
+}
+
+func (x *ListAccountsByWalletResponse) Reset() {
+	*x = ListAccountsByWalletResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_walletlister_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListAccountsByWalletResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAccountsByWalletResponse) ProtoMessage() {}
+
+func (x *ListAccountsByWalletResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_walletlister_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAccountsByWalletResponse.ProtoReflect.Descriptor instead.
+func (*ListAccountsByWalletResponse) Descriptor() ([]byte, []int) {
+	return file_walletlister_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ListAccountsByWalletResponse) GetState() v1.ResponseState {
+	if x != nil {
+		return x.State
+	}
+	return v1.ResponseState(0)
+}
+
+func (x *ListAccountsByWalletResponse) GetWallets() []*WalletAccounts {
+	if x != nil {
+		return x.Wallets
+	}
+	return nil
+}
+
+type WalletAccounts struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Type string `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	// account_count is the number of accounts in the wallet that are listed for the client.
+	AccountCount        uint32                   `protobuf:"varint,3,opt,name=account_count,json=accountCount,proto3" json:"account_count,omitempty"`
+	Accounts            []*v1.Account            `protobuf:"bytes,4,rep,name=accounts,proto3" json:"accounts,omitempty"`
+	DistributedAccounts []*v1.DistributedAccount `protobuf:"bytes,5,rep,name=distributed_accounts,json=distributedAccounts,proto3" json:"distributed_accounts,omitempty"`
+}
+
+func (x *WalletAccounts) Reset() {
+	*x = WalletAccounts{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_walletlister_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WalletAccounts) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WalletAccounts) ProtoMessage() {}
+
+func (x *WalletAccounts) ProtoReflect() protoreflect.Message {
+	mi := &file_walletlister_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WalletAccounts.ProtoReflect.Descriptor instead.
+func (*WalletAccounts) Descriptor() ([]byte, []int) {
+	return file_walletlister_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *WalletAccounts) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *WalletAccounts) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *WalletAccounts) GetAccountCount() uint32 {
+	if x != nil {
+		return x.AccountCount
+	}
+	return 0
+}
+
+func (x *WalletAccounts) GetAccounts() []*v1.Account {
+	if x != nil {
+		return x.Accounts
+	}
+	return nil
+}
+
+func (x *WalletAccounts) GetDistributedAccounts() []*v1.DistributedAccount {
+	if x != nil {
+		return x.DistributedAccounts
+	}
+	return nil
+}
+
+var File_walletlister_proto protoreflect.FileDescriptor
+
+var file_walletlister_proto_rawDesc = []byte{
+	0x0a, 0x12, 0x77, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x6c, 0x69, 0x73, 0x74, 0x65, 0x72, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x12, 0x07, 0x64, 0x69, 0x72, 0x6b, 0x2e, 0x76, 0x31, 0x1a, 0x1c, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x61, 0x6e, 0x6e, 0x6f, 0x74, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x0c, 0x6c, 0x69, 0x73,
+	0x74, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x13, 0x72, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x73, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x7a,
+	0x0a, 0x1c, 0x4c, 0x69, 0x73, 0x74, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x42, 0x79,
+	0x57, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x27,
+	0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x11, 0x2e,
+	0x76, 0x31, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65,
+	0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x31, 0x0a, 0x07, 0x77, 0x61, 0x6c, 0x6c, 0x65,
+	0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x64, 0x69, 0x72, 0x6b, 0x2e,
+	0x76, 0x31, 0x2e, 0x57, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74,
+	0x73, 0x52, 0x07, 0x77, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x73, 0x22, 0xd1, 0x01, 0x0a, 0x0e, 0x57,
+	0x61, 0x6c, 0x6c, 0x65, 0x74, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x12, 0x12, 0x0a,
+	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74,
+	0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c, 0x61, 0x63,
+	0x63, 0x6f, 0x75, 0x6e, 0x74, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x27, 0x0a, 0x08, 0x61, 0x63,
+	0x63, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x76,
+	0x31, 0x2e, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x08, 0x61, 0x63, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x73, 0x12, 0x49, 0x0a, 0x14, 0x64, 0x69, 0x73, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74,
+	0x65, 0x64, 0x5f, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x16, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x69, 0x73, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74,
+	0x65, 0x64, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x13, 0x64, 0x69, 0x73, 0x74, 0x72,
+	0x69, 0x62, 0x75, 0x74, 0x65, 0x64, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x32, 0x8b,
+	0x01, 0x0a, 0x0c, 0x57, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x4c, 0x69, 0x73, 0x74, 0x65, 0x72, 0x12,
+	0x7b, 0x0a, 0x14, 0x4c, 0x69, 0x73, 0x74, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x42,
+	0x79, 0x57, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x12, 0x17, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73,
+	0x74, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x25, 0x2e, 0x64, 0x69, 0x72, 0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x41,
+	0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x42, 0x79, 0x57, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x23, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x1d, 0x22,
+	0x1b, 0x2f, 0x76, 0x31, 0x2f, 0x6c, 0x69, 0x73, 0x74, 0x65, 0x72, 0x2f, 0x61, 0x63, 0x63, 0x6f,
+	0x75, 0x6e, 0x74, 0x73, 0x62, 0x79, 0x77, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x42, 0x62, 0x0a, 0x14,
+	0x69, 0x6f, 0x2e, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x61, 0x6e, 0x74, 0x2e, 0x64, 0x69, 0x72,
+	0x6b, 0x2e, 0x76, 0x31, 0x42, 0x11, 0x57, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x4c, 0x69, 0x73, 0x74,
+	0x65, 0x72, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x50, 0x01, 0x5a, 0x21, 0x67, 0x69, 0x74, 0x68, 0x75,
+	0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x61, 0x6e, 0x74, 0x69,
+	0x6f, 0x2f, 0x64, 0x69, 0x72, 0x6b, 0x2f, 0x70, 0x62, 0x2f, 0x76, 0x31, 0xaa, 0x02, 0x07, 0x44,
+	0x69, 0x72, 0x6b, 0x2e, 0x76, 0x31, 0xca, 0x02, 0x07, 0x44, 0x69, 0x72, 0x6b, 0x5c, 0x76, 0x31,
+	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_walletlister_proto_rawDescOnce sync.Once
+	file_walletlister_proto_rawDescData = file_walletlister_proto_rawDesc
+)
+
+func file_walletlister_proto_rawDescGZIP() []byte {
+	file_walletlister_proto_rawDescOnce.Do(func() {
+		file_walletlister_proto_rawDescData = protoimpl.X.CompressGZIP(file_walletlister_proto_rawDescData)
+	})
+	return file_walletlister_proto_rawDescData
+}
+
+var file_walletlister_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_walletlister_proto_goTypes = []interface{}{
+	(*ListAccountsByWalletResponse)(nil), // 0: dirk.v1.ListAccountsByWalletResponse
+	(*WalletAccounts)(nil),               // 1: dirk.v1.WalletAccounts
+	(v1.ResponseState)(0),                // 2: v1.ResponseState
+	(*v1.Account)(nil),                   // 3: v1.Account
+	(*v1.DistributedAccount)(nil),        // 4: v1.DistributedAccount
+	(*v1.ListAccountsRequest)(nil),       // 5: v1.ListAccountsRequest
+}
+var file_walletlister_proto_depIdxs = []int32{
+	2, // 0: dirk.v1.ListAccountsByWalletResponse.state:type_name -> v1.ResponseState
+	1, // 1: dirk.v1.ListAccountsByWalletResponse.wallets:type_name -> dirk.v1.WalletAccounts
+	3, // 2: dirk.v1.WalletAccounts.accounts:type_name -> v1.Account
+	4, // 3: dirk.v1.WalletAccounts.distributed_accounts:type_name -> v1.DistributedAccount
+	5, // 4: dirk.v1.WalletLister.ListAccountsByWallet:input_type -> v1.ListAccountsRequest
+	0, // 5: dirk.v1.WalletLister.ListAccountsByWallet:output_type -> dirk.v1.ListAccountsByWalletResponse
+	5, // [5:6] is the sub-list for method output_type
+	4, // [4:5] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_walletlister_proto_init() }
+func file_walletlister_proto_init() {
+	if File_walletlister_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_walletlister_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListAccountsByWalletResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_walletlister_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WalletAccounts); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_walletlister_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_walletlister_proto_goTypes,
+		DependencyIndexes: file_walletlister_proto_depIdxs,
+		MessageInfos:      file_walletlister_proto_msgTypes,
+	}.Build()
+	File_walletlister_proto = out.File
+	file_walletlister_proto_rawDesc = nil
+	file_walletlister_proto_goTypes = nil
+	file_walletlister_proto_depIdxs = nil
+}
@@ -0,0 +1,102 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package v1
+
+import (
+	context "context"
+	v1 "github.com/wealdtech/eth2-signer-api/pb/v1"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// WalletListerClient is the client API for WalletLister service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type WalletListerClient interface {
+	ListAccountsByWallet(ctx context.Context, in *v1.ListAccountsRequest, opts ...grpc.CallOption) (*ListAccountsByWalletResponse, error)
+}
+
+type walletListerClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewWalletListerClient(cc grpc.ClientConnInterface) WalletListerClient {
+	return &walletListerClient{cc}
+}
+
+func (c *walletListerClient) ListAccountsByWallet(ctx context.Context, in *v1.ListAccountsRequest, opts ...grpc.CallOption) (*ListAccountsByWalletResponse, error) {
+	out := new(ListAccountsByWalletResponse)
+	err := c.cc.Invoke(ctx, "/dirk.v1.WalletLister/ListAccountsByWallet", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// WalletListerServer is the server API for WalletLister service.
+// All implementations must embed UnimplementedWalletListerServer
+// for forward compatibility
+type WalletListerServer interface {
+	ListAccountsByWallet(context.Context, *v1.ListAccountsRequest) (*ListAccountsByWalletResponse, error)
+	mustEmbedUnimplementedWalletListerServer()
+}
+
+// UnimplementedWalletListerServer must be embedded to have forward compatible implementations.
+type UnimplementedWalletListerServer struct {
+}
+
+func (UnimplementedWalletListerServer) ListAccountsByWallet(context.Context, *v1.ListAccountsRequest) (*ListAccountsByWalletResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAccountsByWallet not implemented")
+}
+func (UnimplementedWalletListerServer) mustEmbedUnimplementedWalletListerServer() {}
+
+// UnsafeWalletListerServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to WalletListerServer will
+// result in compilation errors.
+type UnsafeWalletListerServer interface {
+	mustEmbedUnimplementedWalletListerServer()
+}
+
+func RegisterWalletListerServer(s grpc.ServiceRegistrar, srv WalletListerServer) {
+	s.RegisterService(&WalletLister_ServiceDesc, srv)
+}
+
+func _WalletLister_ListAccountsByWallet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(v1.ListAccountsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletListerServer).ListAccountsByWallet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dirk.v1.WalletLister/ListAccountsByWallet",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletListerServer).ListAccountsByWallet(ctx, req.(*v1.ListAccountsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// WalletLister_ServiceDesc is the grpc.ServiceDesc for WalletLister service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var WalletLister_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "dirk.v1.WalletLister",
+	HandlerType: (*WalletListerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListAccountsByWallet",
+			Handler:    _WalletLister_ListAccountsByWallet_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "walletlister.proto",
+}
@@ -0,0 +1,87 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	grpcapi "github.com/attestantio/dirk/services/api/grpc"
+	"github.com/attestantio/dirk/services/checker"
+	"github.com/attestantio/dirk/services/lister"
+	"github.com/spf13/viper"
+	"github.com/wealdtech/go-majordomo"
+)
+
+// reloadOnSignal reopens the log file and reloads the permissions and API
+// certificates whenever a SIGHUP is received.  If certReloadInterval is
+// non-zero the API certificates are also re-fetched at that interval, and
+// reloaded if they have changed.
+func reloadOnSignal(ctx context.Context, majordomo majordomo.Service, api *grpcapi.Service, checkerSvc checker.Service, listerSvc lister.Service, certReloadInterval time.Duration) {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGHUP)
+	defer signal.Stop(sigCh)
+	// Periodic reloads share this loop with SIGHUP so that configuration is never read concurrently.
+	var certReloadCh <-chan time.Time
+	if certReloadInterval > 0 {
+		ticker := time.NewTicker(certReloadInterval)
+		defer ticker.Stop()
+		certReloadCh = ticker.C
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-certReloadCh:
+			refreshCertificates(ctx, majordomo, api)
+		case <-sigCh:
+			reopenLogFile()
+			log.Info().Msg("Received SIGHUP; reloading permissions and certificates")
+			// Configuration is re-read here rather than in each reload, as viper is not safe for concurrent use.
+			if err := viper.ReadInConfig(); err != nil {
+				if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
+					log.Warn().Err(err).Msg("Failed to re-read configuration file; continuing with existing configuration")
+					continue
+				}
+			}
+			reloadPermissions(ctx, checkerSvc, listerSvc)
+			reloadCertificates(ctx, majordomo, api)
+		}
+	}
+}
+
+// reloadPermissions obtains the client permissions and supplies them to the checker.
+// Any cached account lists are invalidated, as they were filtered by the old permissions.
+func reloadPermissions(ctx context.Context, checkerSvc checker.Service, listerSvc lister.Service) {
+	reloader, isReloader := checkerSvc.(checker.PermissionsReloader)
+	if !isReloader {
+		log.Debug().Msg("Checker does not support reloading permissions")
+		return
+	}
+	permissions, err := obtainPermissions()
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to obtain permissions; continuing with existing permissions")
+		return
+	}
+	if err := reloader.ReloadPermissions(ctx, permissions); err != nil {
+		log.Warn().Err(err).Msg("Failed to reload permissions; continuing with existing permissions")
+		return
+	}
+	if invalidator, isInvalidator := listerSvc.(lister.CacheInvalidator); isInvalidator {
+		invalidator.InvalidateCache(ctx)
+	}
+}
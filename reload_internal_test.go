@@ -0,0 +1,78 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mockrules "github.com/attestantio/dirk/rules/mock"
+	"github.com/attestantio/dirk/services/checker"
+	"github.com/attestantio/dirk/services/checker/static"
+	memfetcher "github.com/attestantio/dirk/services/fetcher/mem"
+	standardlister "github.com/attestantio/dirk/services/lister/standard"
+	syncmaplocker "github.com/attestantio/dirk/services/locker/syncmap"
+	"github.com/attestantio/dirk/services/ruler/golang"
+	"github.com/attestantio/dirk/testing/accounts"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	e2types "github.com/wealdtech/go-eth2-types/v2"
+	e2wtypes "github.com/wealdtech/go-eth2-wallet-types/v2"
+)
+
+func TestReloadPermissionsInvalidatesListCache(t *testing.T) {
+	ctx := context.Background()
+	require.NoError(t, e2types.InitBLS())
+
+	store, err := accounts.Setup(ctx)
+	require.NoError(t, err)
+	fetcher, err := memfetcher.New(ctx, memfetcher.WithStores([]e2wtypes.Store{store}))
+	require.NoError(t, err)
+	locker, err := syncmaplocker.New(ctx)
+	require.NoError(t, err)
+	ruler, err := golang.New(ctx, golang.WithLocker(locker), golang.WithRules(mockrules.New()))
+	require.NoError(t, err)
+	checkerSvc, err := static.New(ctx, static.WithPermissions(map[string][]*checker.Permissions{
+		"client1": {
+			{Path: "Wallet 1/Account 1", Operations: []string{"All"}},
+			{Path: "Wallet 1/Account 2", Operations: []string{"All"}},
+		},
+	}))
+	require.NoError(t, err)
+	listerSvc, err := standardlister.New(ctx,
+		standardlister.WithChecker(checkerSvc),
+		standardlister.WithFetcher(fetcher),
+		standardlister.WithRuler(ruler),
+		standardlister.WithCacheTTL(time.Hour),
+	)
+	require.NoError(t, err)
+
+	credentials := &checker.Credentials{Client: "client1"}
+	_, listed := listerSvc.ListAccounts(ctx, credentials, []string{"Wallet 1"})
+	require.Len(t, listed, 2)
+
+	// Revoke access to the second account.
+	viper.Set("permissions", map[string]interface{}{
+		"client1": map[string]interface{}{
+			"Wallet 1/Account 1": "All",
+		},
+	})
+	defer viper.Set("permissions", nil)
+	reloadPermissions(ctx, checkerSvc, listerSvc)
+
+	_, listed = listerSvc.ListAccounts(ctx, credentials, []string{"Wallet 1"})
+	require.Len(t, listed, 1)
+	require.Equal(t, "Account 1", listed[0].Name())
+}
@@ -0,0 +1,95 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rules
+
+import (
+	"context"
+	"sync"
+)
+
+type evaluationKey struct{}
+
+// evaluation holds the state of an evaluation of rules.
+type evaluation struct {
+	mu        sync.Mutex
+	checkOnly bool
+	reason    string
+	code      DenialReason
+}
+
+// WithCheckOnly returns a context that marks the rules run with it as check-only.
+// Check-only rules carry out their usual evaluation, but must not update any state.
+func WithCheckOnly(ctx context.Context) context.Context {
+	return context.WithValue(ctx, evaluationKey{}, &evaluation{checkOnly: true})
+}
+
+// WithReason returns a context on which rules run with it can record the
+// reason for their result, without marking them as check-only.
+func WithReason(ctx context.Context) context.Context {
+	return context.WithValue(ctx, evaluationKey{}, &evaluation{})
+}
+
+// IsCheckOnly returns true if the context is check-only.
+func IsCheckOnly(ctx context.Context) bool {
+	state, isState := ctx.Value(evaluationKey{}).(*evaluation)
+	return isState && state.checkOnly
+}
+
+// SetReason records the reason for a rule's result.
+// Only the first reason is retained.  This does nothing if the context was not
+// created with WithCheckOnly or WithReason.
+func SetReason(ctx context.Context, reason string) {
+	SetCodedReason(ctx, "", reason)
+}
+
+// SetCodedReason records the reason for a rule's result along with a
+// machine-readable code.  Only the first reason and the first code are retained.
+// This does nothing if the context was not created with WithCheckOnly or WithReason.
+func SetCodedReason(ctx context.Context, code DenialReason, reason string) {
+	state, isState := ctx.Value(evaluationKey{}).(*evaluation)
+	if !isState {
+		return
+	}
+	state.mu.Lock()
+	if state.reason == "" {
+		state.reason = reason
+	}
+	if state.code == "" {
+		state.code = code
+	}
+	state.mu.Unlock()
+}
+
+// Reason returns the reason recorded on the context, if any.
+func Reason(ctx context.Context) string {
+	state, isState := ctx.Value(evaluationKey{}).(*evaluation)
+	if !isState {
+		return ""
+	}
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	return state.reason
+}
+
+// ReasonCode returns the machine-readable code of the reason recorded on the
+// context, if any.
+func ReasonCode(ctx context.Context) DenialReason {
+	state, isState := ctx.Value(evaluationKey{}).(*evaluation)
+	if !isState {
+		return ""
+	}
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	return state.code
+}
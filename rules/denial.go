@@ -0,0 +1,41 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rules
+
+// DenialReason is a machine-readable reason for the denial of a request.
+type DenialReason string
+
+// Reasons for denials.
+const (
+	// DenialDoubleProposal is a proposal for a slot at or before that of a previously signed proposal.
+	DenialDoubleProposal DenialReason = "DOUBLE_PROPOSAL"
+	// DenialDoubleAttestation is an attestation for a target epoch at or before that of a previously signed attestation.
+	DenialDoubleAttestation DenialReason = "DOUBLE_ATTESTATION"
+	// DenialSurroundVote is an attestation that would surround a previously signed attestation.
+	DenialSurroundVote DenialReason = "SURROUND_VOTE"
+	// DenialTargetTooFarAhead is an attestation with a target epoch too far beyond the current epoch.
+	DenialTargetTooFarAhead DenialReason = "TARGET_TOO_FAR_AHEAD"
+	// DenialMinAttestationGap is an attestation within the minimum gap of a previously signed attestation.
+	DenialMinAttestationGap DenialReason = "MIN_ATTESTATION_GAP"
+)
+
+// IsSlashingProtection returns true if the reason is a slashing protection denial.
+func (r DenialReason) IsSlashingProtection() bool {
+	switch r {
+	case DenialDoubleProposal, DenialDoubleAttestation, DenialSurroundVote:
+		return true
+	default:
+		return false
+	}
+}
@@ -79,6 +79,16 @@ func (s *denyingService) OnSign(ctx context.Context, metadata *rules.ReqMetadata
 	return rules.DENIED
 }
 
+// OnSignBLSToExecutionChange is called when a request to sign a BLS to execution change needs to be approved.
+func (s *denyingService) OnSignBLSToExecutionChange(ctx context.Context, metadata *rules.ReqMetadata, req *rules.SignBLSToExecutionChangeData) rules.Result {
+	return rules.DENIED
+}
+
+// OnSignValidatorRegistration is called when a request to sign a validator registration needs to be approved.
+func (s *denyingService) OnSignValidatorRegistration(ctx context.Context, metadata *rules.ReqMetadata, req *rules.SignValidatorRegistrationData) rules.Result {
+	return rules.DENIED
+}
+
 // ExportSlashingProtection exports the slashing protection data.
 func (s *denyingService) ExportSlashingProtection(ctx context.Context) (map[[48]byte]*rules.SlashingProtection, error) {
 	return nil, nil
@@ -79,6 +79,16 @@ func (s *failingService) OnSign(ctx context.Context, metadata *rules.ReqMetadata
 	return rules.FAILED
 }
 
+// OnSignBLSToExecutionChange is called when a request to sign a BLS to execution change needs to be approved.
+func (s *failingService) OnSignBLSToExecutionChange(ctx context.Context, metadata *rules.ReqMetadata, req *rules.SignBLSToExecutionChangeData) rules.Result {
+	return rules.FAILED
+}
+
+// OnSignValidatorRegistration is called when a request to sign a validator registration needs to be approved.
+func (s *failingService) OnSignValidatorRegistration(ctx context.Context, metadata *rules.ReqMetadata, req *rules.SignValidatorRegistrationData) rules.Result {
+	return rules.FAILED
+}
+
 // ExportSlashingProtection exports the slashing protection data.
 func (s *failingService) ExportSlashingProtection(ctx context.Context) (map[[48]byte]*rules.SlashingProtection, error) {
 	return nil, nil
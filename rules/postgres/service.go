@@ -0,0 +1,353 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"hash/fnv"
+	"sort"
+
+	standardrules "github.com/attestantio/dirk/rules/standard"
+	"github.com/lib/pq"
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	zerologger "github.com/rs/zerolog/log"
+)
+
+// Store holds slashing protection information in a PostgreSQL database.
+// Each request is checked and recorded within a single transaction that
+// holds an advisory lock on its keys, so multiple instances of Dirk can
+// safely share the same database.
+type Store struct {
+	db *sql.DB
+}
+
+// log is a module-wide log.
+var log zerolog.Logger
+
+// schema is the schema for the slashing protection table.  Keys are
+// split in to the public key and the action to which the value refers.
+const schema = `CREATE TABLE IF NOT EXISTS slashing_protection (
+  pubkey BYTEA NOT NULL,
+  action BYTEA NOT NULL,
+  value BYTEA NOT NULL,
+  PRIMARY KEY (pubkey, action)
+)`
+
+// New creates a new PostgreSQL store, creating its table if required.
+func New(ctx context.Context, params ...Parameter) (*Store, error) {
+	parameters, err := parseAndCheckParameters(params...)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem with parameters")
+	}
+
+	// Set logging.
+	log = zerologger.With().Str("service", "rules").Str("impl", "postgres").Logger()
+	if parameters.logLevel != log.GetLevel() {
+		log = log.Level(parameters.logLevel)
+	}
+
+	db, err := sql.Open("postgres", parameters.dsn)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open database")
+	}
+	if err := db.PingContext(ctx); err != nil {
+		if closeErr := db.Close(); closeErr != nil {
+			log.Warn().Err(closeErr).Msg("Failed to close database")
+		}
+		return nil, errors.Wrap(err, "failed to connect to database")
+	}
+	if _, err := db.ExecContext(ctx, schema); err != nil {
+		if closeErr := db.Close(); closeErr != nil {
+			log.Warn().Err(closeErr).Msg("Failed to close database")
+		}
+		return nil, errors.Wrap(err, "failed to create slashing protection table")
+	}
+
+	return &Store{
+		db: db,
+	}, nil
+}
+
+// splitKey splits a key in to its public key and action.
+func splitKey(key []byte) ([]byte, []byte) {
+	return key[:len(key)-1], key[len(key)-1:]
+}
+
+// lockIDs returns the sorted, unique advisory lock IDs for the given keys.
+func lockIDs(keys [][]byte) []int64 {
+	seen := make(map[int64]bool, len(keys))
+	ids := make([]int64, 0, len(keys))
+	for _, key := range keys {
+		hash := fnv.New64a()
+		_, _ = hash.Write(key)
+		id := int64(hash.Sum64())
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	// Always lock in the same order, to avoid deadlocks between transactions.
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// Update fetches the values for the given keys, passes them to the update
+// function, and stores the values it returns, in a single transaction.
+func (s *Store) Update(ctx context.Context, keys [][]byte, update func(values [][]byte) ([][]byte, error)) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "storage.Update")
+	defer span.Finish()
+
+	if len(keys) == 0 {
+		return errors.New("no keys provided")
+	}
+	for i := range keys {
+		if len(keys[i]) == 0 {
+			return errors.New("empty key provided")
+		}
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to begin transaction")
+	}
+	defer func() {
+		// Rollback is a no-op once the transaction has been committed.
+		_ = tx.Rollback()
+	}()
+
+	// Lock the keys for the duration of the transaction, so that concurrent
+	// requests for the same keys are checked one after the other.
+	for _, id := range lockIDs(keys) {
+		if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock($1)", id); err != nil {
+			return errors.Wrap(err, "failed to lock keys")
+		}
+	}
+
+	values, err := fetchValues(ctx, tx, keys)
+	if err != nil {
+		return err
+	}
+
+	newValues, err := update(values)
+	if err != nil {
+		return err
+	}
+	if newValues == nil {
+		return nil
+	}
+	if len(newValues) != len(keys) {
+		return errors.New("key/value length mismatch")
+	}
+	for i := range newValues {
+		if len(newValues[i]) == 0 {
+			return errors.New("empty value provided")
+		}
+	}
+
+	if err := storeValues(ctx, tx, keys, newValues); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, "failed to commit transaction")
+	}
+	return nil
+}
+
+// fetchValues fetches the values for the given keys within a transaction.
+func fetchValues(ctx context.Context, tx *sql.Tx, keys [][]byte) ([][]byte, error) {
+	pubKeys := make([][]byte, len(keys))
+	actions := make([][]byte, len(keys))
+	for i := range keys {
+		pubKeys[i], actions[i] = splitKey(keys[i])
+	}
+
+	rows, err := tx.QueryContext(ctx, `SELECT pubkey, action, value FROM slashing_protection
+WHERE (pubkey, action) IN (SELECT * FROM unnest($1::BYTEA[], $2::BYTEA[]))`,
+		pq.Array(pubKeys), pq.Array(actions))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch values")
+	}
+	defer rows.Close()
+
+	found := make(map[string][]byte, len(keys))
+	for rows.Next() {
+		var pubKey, action, value []byte
+		if err := rows.Scan(&pubKey, &action, &value); err != nil {
+			return nil, errors.Wrap(err, "failed to read value")
+		}
+		found[string(pubKey)+string(action)] = value
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to fetch values")
+	}
+
+	values := make([][]byte, len(keys))
+	for i := range keys {
+		values[i] = found[string(keys[i])]
+	}
+	return values, nil
+}
+
+// storeValues stores the values for the given keys within a transaction.
+func storeValues(ctx context.Context, tx *sql.Tx, keys [][]byte, values [][]byte) error {
+	// A single insert cannot update the same row twice, so only the last
+	// value for each key is stored.
+	last := make(map[string]int, len(keys))
+	for i := range keys {
+		last[string(keys[i])] = i
+	}
+	pubKeys := make([][]byte, 0, len(last))
+	actions := make([][]byte, 0, len(last))
+	uniqueValues := make([][]byte, 0, len(last))
+	for i := range keys {
+		if last[string(keys[i])] != i {
+			continue
+		}
+		pubKey, action := splitKey(keys[i])
+		pubKeys = append(pubKeys, pubKey)
+		actions = append(actions, action)
+		uniqueValues = append(uniqueValues, values[i])
+	}
+
+	if _, err := tx.ExecContext(ctx, `INSERT INTO slashing_protection (pubkey, action, value)
+SELECT * FROM unnest($1::BYTEA[], $2::BYTEA[], $3::BYTEA[])
+ON CONFLICT (pubkey, action) DO UPDATE SET value = EXCLUDED.value`,
+		pq.Array(pubKeys), pq.Array(actions), pq.Array(uniqueValues)); err != nil {
+		return errors.Wrap(err, "failed to store values")
+	}
+	return nil
+}
+
+// Fetch fetches a value for a given key.
+func (s *Store) Fetch(ctx context.Context, key []byte) ([]byte, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "storage.Fetch")
+	defer span.Finish()
+
+	if len(key) == 0 {
+		return nil, errors.New("no key provided")
+	}
+
+	pubKey, action := splitKey(key)
+	var value []byte
+	err := s.db.QueryRowContext(ctx, "SELECT value FROM slashing_protection WHERE pubkey = $1 AND action = $2", pubKey, action).Scan(&value)
+	if err == sql.ErrNoRows {
+		return nil, standardrules.ErrNotFound
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch value")
+	}
+	return value, nil
+}
+
+// FetchAll fetches a map of all keys and values.
+func (s *Store) FetchAll(ctx context.Context) (map[[49]byte][]byte, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT pubkey, action, value FROM slashing_protection")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch values")
+	}
+	defer rows.Close()
+
+	items := make(map[[49]byte][]byte)
+	for rows.Next() {
+		var pubKey, action, value []byte
+		if err := rows.Scan(&pubKey, &action, &value); err != nil {
+			return nil, errors.Wrap(err, "failed to read value")
+		}
+		var key [49]byte
+		copy(key[:], pubKey)
+		copy(key[len(pubKey):], action)
+		items[key] = value
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to fetch values")
+	}
+	return items, nil
+}
+
+// Store stores the value for a given key.
+func (s *Store) Store(ctx context.Context, key []byte, value []byte) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "storage.Store")
+	defer span.Finish()
+
+	if len(key) == 0 {
+		return errors.New("no key provided")
+	}
+	if len(value) == 0 {
+		return errors.New("no value provided")
+	}
+
+	return s.BatchStore(ctx, [][]byte{key}, [][]byte{value})
+}
+
+// BatchStore stores multiple keys and values.
+func (s *Store) BatchStore(ctx context.Context, keys [][]byte, values [][]byte) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "storage.BatchStore")
+	defer span.Finish()
+
+	if len(keys) == 0 {
+		return errors.New("no keys provided")
+	}
+	if len(keys) != len(values) {
+		return errors.New("key/value length mismatch")
+	}
+	for i := range keys {
+		if len(keys[i]) == 0 {
+			return errors.New("empty key provided")
+		}
+		if len(values[i]) == 0 {
+			return errors.New("empty value provided")
+		}
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to begin transaction")
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	if err := storeValues(ctx, tx, keys, values); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, "failed to commit transaction")
+	}
+	return nil
+}
+
+// Delete deletes the value for a given key.
+// It is not an error to delete a key that does not exist.
+func (s *Store) Delete(ctx context.Context, key []byte) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "storage.Delete")
+	defer span.Finish()
+
+	if len(key) == 0 {
+		return errors.New("no key provided")
+	}
+
+	pubKey, action := splitKey(key)
+	if _, err := s.db.ExecContext(ctx, "DELETE FROM slashing_protection WHERE pubkey = $1 AND action = $2", pubKey, action); err != nil {
+		return errors.Wrap(err, "failed to delete value")
+	}
+	return nil
+}
+
+// Close closes the store.
+func (s *Store) Close(ctx context.Context) error {
+	return s.db.Close()
+}
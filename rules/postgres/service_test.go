@@ -0,0 +1,155 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgres_test
+
+import (
+	"context"
+	"encoding/binary"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/attestantio/dirk/rules"
+	"github.com/attestantio/dirk/rules/postgres"
+	standardrules "github.com/attestantio/dirk/rules/standard"
+	"github.com/stretchr/testify/require"
+	e2types "github.com/wealdtech/go-eth2-types/v2"
+)
+
+// testStore creates a store against the database in DIRK_TEST_POSTGRES_DSN,
+// skipping the test if it is not set.
+func testStore(ctx context.Context, t *testing.T) *postgres.Store {
+	dsn := os.Getenv("DIRK_TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("DIRK_TEST_POSTGRES_DSN not set")
+	}
+	store, err := postgres.New(ctx, postgres.WithDSN(dsn))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, store.Close(ctx))
+	})
+	return store
+}
+
+// testKey returns a unique key for a test.
+func testKey(t *testing.T, action byte) []byte {
+	key := make([]byte, 49)
+	copy(key, t.Name())
+	key[48] = action
+	return key
+}
+
+func TestNew(t *testing.T) {
+	_, err := postgres.New(context.Background())
+	require.EqualError(t, err, "problem with parameters: no DSN specified")
+}
+
+func TestStore(t *testing.T) {
+	ctx := context.Background()
+	store := testStore(ctx, t)
+	key := testKey(t, 0x01)
+	require.NoError(t, store.Delete(ctx, key))
+
+	_, err := store.Fetch(ctx, key)
+	require.Equal(t, standardrules.ErrNotFound, err)
+	require.EqualError(t, store.Store(ctx, nil, []byte{0x01}), "no key provided")
+	require.EqualError(t, store.Store(ctx, key, nil), "no value provided")
+
+	require.NoError(t, store.Store(ctx, key, []byte{0x01}))
+	require.NoError(t, store.BatchStore(ctx, [][]byte{key, key}, [][]byte{{0x02}, {0x03}}))
+	value, err := store.Fetch(ctx, key)
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x03}, value)
+
+	items, err := store.FetchAll(ctx)
+	require.NoError(t, err)
+	var itemKey [49]byte
+	copy(itemKey[:], key)
+	require.Equal(t, []byte{0x03}, items[itemKey])
+
+	require.NoError(t, store.Delete(ctx, key))
+	_, err = store.Fetch(ctx, key)
+	require.Equal(t, standardrules.ErrNotFound, err)
+}
+
+func TestConcurrentUpdate(t *testing.T) {
+	ctx := context.Background()
+	store := testStore(ctx, t)
+	key := testKey(t, 0x01)
+	require.NoError(t, store.Delete(ctx, key))
+
+	// Each update increments a counter; if updates were not serialised
+	// some increments would be lost.
+	updates := 20
+	var wg sync.WaitGroup
+	for i := 0; i < updates; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			require.NoError(t, store.Update(ctx, [][]byte{key}, func(values [][]byte) ([][]byte, error) {
+				counter := make([]byte, 8)
+				if values[0] != nil {
+					binary.LittleEndian.PutUint64(counter, binary.LittleEndian.Uint64(values[0])+1)
+				} else {
+					binary.LittleEndian.PutUint64(counter, 1)
+				}
+				return [][]byte{counter}, nil
+			}))
+		}()
+	}
+	wg.Wait()
+
+	value, err := store.Fetch(ctx, key)
+	require.NoError(t, err)
+	require.Equal(t, uint64(updates), binary.LittleEndian.Uint64(value))
+}
+
+func TestSlashingProtection(t *testing.T) {
+	ctx := context.Background()
+	require.NoError(t, e2types.InitBLS())
+	store := testStore(ctx, t)
+	pubKey := testKey(t, 0x00)[:48]
+	require.NoError(t, store.Delete(ctx, append(append([]byte{}, pubKey...), 0x03)))
+
+	rulesSvc, err := standardrules.New(ctx, standardrules.WithStorage(store))
+	require.NoError(t, err)
+
+	root := make([]byte, 32)
+	req := &rules.SignBeaconProposalData{
+		Domain:     e2types.Domain(e2types.DomainBeaconProposer, e2types.ZeroForkVersion, e2types.ZeroGenesisValidatorsRoot),
+		Slot:       1,
+		ParentRoot: root,
+		StateRoot:  root,
+		BodyRoot:   root,
+	}
+	metadata := &rules.ReqMetadata{PubKey: pubKey}
+
+	// Only one of a number of concurrent requests for the same slot is approved.
+	var mu sync.Mutex
+	approved := 0
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if rulesSvc.OnSignBeaconProposal(ctx, metadata, req) == rules.APPROVED {
+				mu.Lock()
+				approved++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	require.Equal(t, 1, approved)
+}
@@ -76,6 +76,53 @@ type UnlockAccountData struct{}
 // CreateAccountData is passed to 'OnCreateAccount' rules.
 type CreateAccountData struct{}
 
+// GenerateDepositData is the data for a request to generate deposit data.
+// Deposits are not slashable, so this is not passed to the rules.
+type GenerateDepositData struct {
+	WithdrawalCredentials []byte
+	Amount                uint64
+	ForkVersion           []byte
+}
+
+// SignTaggedData is the data for a request to sign a tagged message.
+// Tagged messages are not slashable, so this is not passed to the rules.
+type SignTaggedData struct {
+	Tag    string
+	Domain []byte
+	Data   []byte
+}
+
+// SignRootData is passed to 'OnSignRoot' rules.
+// The signing root is supplied by the client, so the rules cannot tell what
+// message it is for.
+type SignRootData struct {
+	SigningRoot []byte
+}
+
+// SignBLSToExecutionChangeData is passed to 'OnSignBLSToExecutionChange' rules.
+// The domain and the BLS public key are calculated by the signer from the
+// genesis information and the signing account.
+type SignBLSToExecutionChangeData struct {
+	Domain                []byte
+	ValidatorIndex        uint64
+	FromBLSPubKey         []byte
+	ToExecutionAddress    []byte
+	GenesisForkVersion    []byte
+	GenesisValidatorsRoot []byte
+}
+
+// SignValidatorRegistrationData is passed to 'OnSignValidatorRegistration' rules.
+// The domain and the public key are calculated by the signer from the
+// genesis information and the signing account.
+type SignValidatorRegistrationData struct {
+	Domain             []byte
+	FeeRecipient       []byte
+	GasLimit           uint64
+	Timestamp          uint64
+	PubKey             []byte
+	GenesisForkVersion []byte
+}
+
 // Result represents the result of running a set of rules.
 type Result int
 
@@ -132,3 +179,33 @@ type Service interface {
 	// ImportSlashingProtection impports the slashing protection data.
 	ImportSlashingProtection(ctx context.Context, protection map[[48]byte]*SlashingProtection) error
 }
+
+// SlashingProtectionPurger is the interface for a rules service that can remove slashing protection data.
+type SlashingProtectionPurger interface {
+	// PurgeSlashingProtection removes all slashing protection data for the given public key.
+	PurgeSlashingProtection(ctx context.Context, pubKey []byte) error
+}
+
+// BLSToExecutionChangeApprover is the interface for a rules service that can approve
+// requests to sign BLS to execution change messages.
+// Rules services that do not implement this interface approve all such requests.
+type BLSToExecutionChangeApprover interface {
+	// OnSignBLSToExecutionChange is called when a request to sign a BLS to execution change needs to be approved.
+	OnSignBLSToExecutionChange(ctx context.Context, metadata *ReqMetadata, req *SignBLSToExecutionChangeData) Result
+}
+
+// ValidatorRegistrationApprover is the interface for a rules service that can approve
+// requests to sign validator registrations.
+// Rules services that do not implement this interface approve all such requests.
+type ValidatorRegistrationApprover interface {
+	// OnSignValidatorRegistration is called when a request to sign a validator registration needs to be approved.
+	OnSignValidatorRegistration(ctx context.Context, metadata *ReqMetadata, req *SignValidatorRegistrationData) Result
+}
+
+// RootSigningApprover is the interface for a rules service that can approve
+// requests to sign client-supplied signing roots.
+// Rules services that do not implement this interface approve all such requests.
+type RootSigningApprover interface {
+	// OnSignRoot is called when a request to sign a signing root needs to be approved.
+	OnSignRoot(ctx context.Context, metadata *ReqMetadata, req *SignRootData) Result
+}
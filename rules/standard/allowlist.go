@@ -0,0 +1,41 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/attestantio/dirk/rules"
+	"github.com/rs/zerolog"
+)
+
+// validatorAllowed returns true if signing is permitted for the public key in the metadata.
+// Signing is permitted for any public key if no allow-list is configured.
+func (s *Service) validatorAllowed(ctx context.Context, log zerolog.Logger, metadata *rules.ReqMetadata, request string) bool {
+	if s.validatorAllowList == nil {
+		return true
+	}
+	var key [48]byte
+	if len(metadata.PubKey) == len(key) {
+		copy(key[:], metadata.PubKey)
+		if s.validatorAllowList[key] {
+			return true
+		}
+	}
+	log.Warn().Str("pubkey", fmt.Sprintf("%#x", metadata.PubKey)).Msg("Not signing for validator that is not in the allow-list")
+	rules.SetReason(ctx, "Not signing for validator that is not in the allow-list")
+	s.monitor.ValidatorNotAllowed(request)
+	return false
+}
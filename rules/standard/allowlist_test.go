@@ -0,0 +1,118 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/attestantio/dirk/rules"
+	standardrules "github.com/attestantio/dirk/rules/standard"
+	"github.com/stretchr/testify/require"
+	e2types "github.com/wealdtech/go-eth2-types/v2"
+)
+
+func TestValidatorAllowList(t *testing.T) {
+	ctx := context.Background()
+
+	allowedKey := _byteStr(t, "0xa99a76ed7796f7be22d5b7e85deeb7c5677e88e511e0b337618f8c4eb61349b4bf2d153f649f7b53359fe8b94a38e44c")
+	otherKey := _byteStr(t, "0xb89bebc699769726a318c8e9971bd3171297c61aea4a6578a7a4f94b547dcba5bac16a89108b6b6a1fe3695d1a874a0b")
+
+	base, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(base)
+
+	_, err = standardrules.New(ctx,
+		standardrules.WithStoragePath(base),
+		standardrules.WithValidatorAllowList([][]byte{allowedKey[1:]}),
+	)
+	require.EqualError(t, err, "problem with parameters: validator allow-list public keys must be 48 bytes")
+
+	testRules, err := standardrules.New(ctx,
+		standardrules.WithStoragePath(base),
+		standardrules.WithAdminAllowAll(true),
+		standardrules.WithValidatorAllowList([][]byte{allowedKey}),
+	)
+	require.NoError(t, err)
+	defer testRules.Close(ctx)
+
+	allowed := &rules.ReqMetadata{PubKey: allowedKey}
+	other := &rules.ReqMetadata{PubKey: otherKey}
+	attestation := func(source uint64, target uint64) *rules.SignBeaconAttestationData {
+		return &rules.SignBeaconAttestationData{
+			Domain: _byteStr(t, "0x0100000000000000000000000000000000000000000000000000000000000000"),
+			Source: &rules.Checkpoint{Epoch: source},
+			Target: &rules.Checkpoint{Epoch: target},
+		}
+	}
+	proposal := &rules.SignBeaconProposalData{
+		Domain: _byteStr(t, "0x0000000000000000000000000000000000000000000000000000000000000000"),
+		Slot:   1,
+	}
+	generic := &rules.SignData{
+		Domain: _byteStr(t, "0x0400000000000000000000000000000000000000000000000000000000000000"),
+		Data:   _byteStr(t, "0x0102"),
+	}
+
+	require.Equal(t, rules.APPROVED, testRules.OnSign(ctx, allowed, generic))
+	require.Equal(t, rules.DENIED, testRules.OnSign(ctx, other, generic))
+	require.Equal(t, rules.APPROVED, testRules.OnSignBeaconProposal(ctx, allowed, proposal))
+	require.Equal(t, rules.DENIED, testRules.OnSignBeaconProposal(ctx, other, proposal))
+	require.Equal(t, rules.DENIED, testRules.OnSignBeaconProposal(ctx, &rules.ReqMetadata{}, proposal))
+	require.Equal(t, rules.APPROVED, testRules.OnSignBeaconAttestation(ctx, allowed, attestation(1, 2)))
+	require.Equal(t, rules.DENIED, testRules.OnSignBeaconAttestation(ctx, other, attestation(1, 2)))
+	require.Equal(t, rules.APPROVED, testRules.OnSignBLSToExecutionChange(ctx, allowed, &rules.SignBLSToExecutionChangeData{}))
+	require.Equal(t, rules.DENIED, testRules.OnSignBLSToExecutionChange(ctx, other, &rules.SignBLSToExecutionChangeData{}))
+	require.Equal(t, rules.APPROVED, testRules.OnSignValidatorRegistration(ctx, allowed, &rules.SignValidatorRegistrationData{}))
+	require.Equal(t, rules.DENIED, testRules.OnSignValidatorRegistration(ctx, other, &rules.SignValidatorRegistrationData{}))
+	require.Equal(t, rules.APPROVED, testRules.OnSignRoot(ctx, allowed, &rules.SignRootData{SigningRoot: make([]byte, 32)}))
+	require.Equal(t, rules.DENIED, testRules.OnSignRoot(ctx, other, &rules.SignRootData{SigningRoot: make([]byte, 32)}))
+
+	// Only the validator that is not allowed is denied in a batch.
+	require.Equal(t, []rules.Result{rules.DENIED, rules.APPROVED},
+		testRules.OnSignBeaconAttestations(ctx,
+			[]*rules.ReqMetadata{other, allowed},
+			[]*rules.SignBeaconAttestationData{attestation(2, 3), attestation(2, 3)},
+		))
+
+	// Denied requests do not record slashing protection.
+	protection, err := testRules.ExportSlashingProtection(ctx)
+	require.NoError(t, err)
+	var otherPubKey [48]byte
+	copy(otherPubKey[:], otherKey)
+	_, exists := protection[otherPubKey]
+	require.False(t, exists)
+}
+
+func TestValidatorAllowListUnset(t *testing.T) {
+	ctx := context.Background()
+
+	base, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(base)
+
+	testRules, err := standardrules.New(ctx,
+		standardrules.WithStoragePath(base),
+	)
+	require.NoError(t, err)
+	defer testRules.Close(ctx)
+
+	metadata := &rules.ReqMetadata{PubKey: _byteStr(t, "0xb89bebc699769726a318c8e9971bd3171297c61aea4a6578a7a4f94b547dcba5bac16a89108b6b6a1fe3695d1a874a0b")}
+	require.Equal(t, rules.APPROVED, testRules.OnSignBeaconProposal(ctx, metadata, &rules.SignBeaconProposalData{
+		Domain: e2types.DomainBeaconProposer[:],
+		Slot:   1,
+	}))
+}
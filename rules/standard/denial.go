@@ -0,0 +1,31 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard
+
+import (
+	"context"
+	"strings"
+
+	"github.com/attestantio/dirk/rules"
+)
+
+// slashingProtectionDenied records the reason for a request being denied by
+// slashing protection.  Check-only requests are not counted by the monitor, as
+// nothing would have been signed.
+func (s *Service) slashingProtectionDenied(ctx context.Context, request string, code rules.DenialReason, reason string) {
+	rules.SetCodedReason(ctx, code, reason)
+	if !rules.IsCheckOnly(ctx) {
+		s.monitor.SlashingProtectionDenied(request, strings.ToLower(string(code)))
+	}
+}
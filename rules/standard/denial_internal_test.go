@@ -0,0 +1,128 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/attestantio/dirk/rules"
+	standardchaintime "github.com/attestantio/dirk/services/chaintime/standard"
+	"github.com/stretchr/testify/require"
+	e2types "github.com/wealdtech/go-eth2-types/v2"
+)
+
+// denialMonitor counts slashing protection denials.
+type denialMonitor struct {
+	mu      sync.Mutex
+	denials map[string]int
+}
+
+func (m *denialMonitor) SlashingProtectionWriteFailed(request string) {}
+
+func (m *denialMonitor) ValidatorNotAllowed(request string) {}
+
+func (m *denialMonitor) SlashingProtectionDenied(request string, reason string) {
+	m.mu.Lock()
+	m.denials[reason]++
+	m.mu.Unlock()
+}
+
+func (m *denialMonitor) RulesStoreAvailable(available bool) {}
+
+func TestAttestationWindowDenialsNotSlashingProtection(t *testing.T) {
+	ctx := context.Background()
+	base, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(base)
+
+	// Genesis is set part way through epoch 10.
+	chainTime, err := standardchaintime.New(ctx,
+		standardchaintime.WithGenesisTime(time.Now().Add(-(10*32+16)*12*time.Second)),
+		standardchaintime.WithSlotDuration(12*time.Second),
+		standardchaintime.WithSlotsPerEpoch(32),
+	)
+	require.NoError(t, err)
+
+	rulesSvc, err := New(ctx,
+		WithStoragePath(base),
+		WithChainTime(chainTime),
+		WithMaxAttestationEpochDistance(2),
+		WithMinAttestationGap(2),
+	)
+	require.NoError(t, err)
+	monitor := &denialMonitor{
+		denials: make(map[string]int),
+	}
+	rulesSvc.monitor = monitor
+
+	tests := []struct {
+		name   string
+		source uint64
+		target uint64
+		res    rules.Result
+		code   rules.DenialReason
+	}{
+		{
+			name:   "First",
+			source: 7,
+			target: 9,
+			res:    rules.APPROVED,
+		},
+		{
+			name:   "DoubleAttestation",
+			source: 7,
+			target: 9,
+			res:    rules.DENIED,
+			code:   rules.DenialDoubleAttestation,
+		},
+		{
+			name:   "MinAttestationGap",
+			source: 9,
+			target: 10,
+			res:    rules.DENIED,
+			code:   rules.DenialMinAttestationGap,
+		},
+		{
+			name:   "TargetTooFarAhead",
+			source: 9,
+			target: 13,
+			res:    rules.DENIED,
+			code:   rules.DenialTargetTooFarAhead,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			reasonCtx := rules.WithReason(ctx)
+			res := rulesSvc.OnSignBeaconAttestation(reasonCtx, &rules.ReqMetadata{}, &rules.SignBeaconAttestationData{
+				Domain: e2types.Domain(e2types.DomainBeaconAttester, e2types.ZeroForkVersion, e2types.ZeroGenesisValidatorsRoot),
+				Source: &rules.Checkpoint{
+					Epoch: test.source,
+				},
+				Target: &rules.Checkpoint{
+					Epoch: test.target,
+				},
+			})
+			require.Equal(t, test.res, res)
+			require.Equal(t, test.code, rules.ReasonCode(reasonCtx))
+		})
+	}
+
+	require.Equal(t, map[string]int{"double_attestation": 1}, monitor.denials)
+}
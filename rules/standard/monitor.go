@@ -0,0 +1,30 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard
+
+// noopMonitor is a monitor that does nothing, used in place of nil if an
+// external monitor is not supplied.
+type noopMonitor struct{}
+
+// SlashingProtectionWriteFailed is called when slashing protection information fails to be written.
+func (n *noopMonitor) SlashingProtectionWriteFailed(request string) {}
+
+// RulesStoreAvailable is called when the availability of the rules store changes.
+func (n *noopMonitor) RulesStoreAvailable(available bool) {}
+
+// ValidatorNotAllowed is called when a signing request is denied because its validator is not in the allow-list.
+func (n *noopMonitor) ValidatorNotAllowed(request string) {}
+
+// SlashingProtectionDenied is called when a signing request is denied by slashing protection.
+func (n *noopMonitor) SlashingProtectionDenied(request string, reason string) {}
@@ -15,14 +15,35 @@ package standard
 
 import (
 	"errors"
+	"time"
 
+	"github.com/attestantio/dirk/services/chaintime"
+	"github.com/attestantio/dirk/services/metrics"
 	"github.com/rs/zerolog"
 )
 
 type parameters struct {
-	logLevel    zerolog.Level
-	storagePath string
-	adminIPs    []string
+	logLevel      zerolog.Level
+	monitor       metrics.RulesMonitor
+	storagePath   string
+	storageType   string
+	storage       Storage
+	readOnly      bool
+	adminIPs      []string
+	adminAllowAll bool
+
+	minAttestationGap           uint64
+	maxAttestationEpochDistance uint64
+	chainTime                   chaintime.Service
+
+	blsToExecutionChangeValidators []uint64
+	validatorAllowList             [][]byte
+
+	maxRegistrationGasLimit   uint64
+	registrationFeeRecipients [][]byte
+
+	reconnectInterval    time.Duration
+	maxReconnectInterval time.Duration
 }
 
 // Parameter is the interface for service parameters.
@@ -43,6 +64,13 @@ func WithLogLevel(logLevel zerolog.Level) Parameter {
 	})
 }
 
+// WithMonitor sets the monitor for the module.
+func WithMonitor(monitor metrics.RulesMonitor) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.monitor = monitor
+	})
+}
+
 // WithStoragePath sets the storage path for the module.
 func WithStoragePath(storagePath string) Parameter {
 	return parameterFunc(func(p *parameters) {
@@ -50,6 +78,28 @@ func WithStoragePath(storagePath string) Parameter {
 	})
 }
 
+// WithStorageType sets the type of the local store at the storage path, either "badger" or "sqlite".
+func WithStorageType(storageType string) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.storageType = storageType
+	})
+}
+
+// WithStorage sets the storage for the module, in place of the local store at the storage path.
+func WithStorage(storage Storage) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.storage = storage
+	})
+}
+
+// WithReadOnly sets whether the local store at the storage path is opened read-only, in which case
+// it must already exist and any attempt to update it fails.
+func WithReadOnly(readOnly bool) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.readOnly = readOnly
+	})
+}
+
 // WithAdminIPs sets the administration IP addreses for the module.
 func WithAdminIPs(adminIPs []string) Parameter {
 	return parameterFunc(func(p *parameters) {
@@ -57,10 +107,88 @@ func WithAdminIPs(adminIPs []string) Parameter {
 	})
 }
 
+// WithAdminAllowAll sets whether administrative requests are approved from any IP address.
+func WithAdminAllowAll(adminAllowAll bool) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.adminAllowAll = adminAllowAll
+	})
+}
+
+// WithReconnectInterval sets the initial interval between attempts to reopen a failed store.
+func WithReconnectInterval(interval time.Duration) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.reconnectInterval = interval
+	})
+}
+
+// WithMaxReconnectInterval sets the maximum interval between attempts to reopen a failed store.
+func WithMaxReconnectInterval(interval time.Duration) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.maxReconnectInterval = interval
+	})
+}
+
+// WithMinAttestationGap sets the minimum number of epochs between the targets of successive attestations.
+func WithMinAttestationGap(gap uint64) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.minAttestationGap = gap
+	})
+}
+
+// WithMaxAttestationEpochDistance sets the maximum number of epochs by which the target of an attestation
+// may be ahead of the current epoch.  If 0, attestations may be signed for any target epoch.
+func WithMaxAttestationEpochDistance(distance uint64) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.maxAttestationEpochDistance = distance
+	})
+}
+
+// WithChainTime sets the chain time service, used to obtain the current epoch.
+func WithChainTime(chainTime chaintime.Service) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.chainTime = chainTime
+	})
+}
+
+// WithValidatorAllowList sets the public keys of the validators for which signing is permitted.
+// If empty, signing is permitted for any validator.
+func WithValidatorAllowList(pubKeys [][]byte) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.validatorAllowList = pubKeys
+	})
+}
+
+// WithBLSToExecutionChangeValidators sets the indices of the validators for which BLS to execution changes may be signed.
+// If not set, BLS to execution changes may be signed for any validator.
+func WithBLSToExecutionChangeValidators(validators []uint64) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.blsToExecutionChangeValidators = validators
+	})
+}
+
+// WithMaxRegistrationGasLimit sets the maximum gas limit for which validator registrations may be signed.
+// If 0, registrations may be signed for any gas limit.
+func WithMaxRegistrationGasLimit(gasLimit uint64) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.maxRegistrationGasLimit = gasLimit
+	})
+}
+
+// WithRegistrationFeeRecipients sets the fee recipients for which validator registrations may be signed.
+// If not set, registrations may be signed for any fee recipient.
+func WithRegistrationFeeRecipients(feeRecipients [][]byte) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.registrationFeeRecipients = feeRecipients
+	})
+}
+
 // parseAndCheckParameters parses and checks parameters to ensure that mandatory parameters are present and correct.
 func parseAndCheckParameters(params ...Parameter) (*parameters, error) {
 	parameters := parameters{
-		logLevel: zerolog.GlobalLevel(),
+		logLevel:             zerolog.GlobalLevel(),
+		storageType:          "badger",
+		reconnectInterval:    time.Second,
+		maxReconnectInterval: time.Minute,
 	}
 	for _, p := range params {
 		if params != nil {
@@ -68,9 +196,38 @@ func parseAndCheckParameters(params ...Parameter) (*parameters, error) {
 		}
 	}
 
-	if parameters.storagePath == "" {
+	if parameters.monitor == nil {
+		// Use no-op monitor.
+		parameters.monitor = &noopMonitor{}
+	}
+	if parameters.storagePath == "" && parameters.storage == nil {
 		return nil, errors.New("no storage path specified")
 	}
+	if parameters.storage == nil && parameters.storageType != "badger" && parameters.storageType != "sqlite" {
+		return nil, errors.New("storage type must be badger or sqlite")
+	}
+	if parameters.reconnectInterval <= 0 {
+		return nil, errors.New("reconnect interval must be greater than 0")
+	}
+	if parameters.maxReconnectInterval < parameters.reconnectInterval {
+		return nil, errors.New("maximum reconnect interval cannot be less than reconnect interval")
+	}
+	if parameters.maxAttestationEpochDistance > 0 && parameters.chainTime == nil {
+		return nil, errors.New("chain time is required for maximum attestation epoch distance")
+	}
+	for _, pubKey := range parameters.validatorAllowList {
+		if len(pubKey) != 48 {
+			return nil, errors.New("validator allow-list public keys must be 48 bytes")
+		}
+	}
+	for _, feeRecipient := range parameters.registrationFeeRecipients {
+		if len(feeRecipient) != 20 {
+			return nil, errors.New("registration fee recipients must be 20 bytes")
+		}
+	}
+	if parameters.adminAllowAll && len(parameters.adminIPs) > 0 {
+		return nil, errors.New("admin IPs cannot be specified when all admin IPs are allowed")
+	}
 
 	return &parameters, nil
 }
@@ -0,0 +1,200 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/attestantio/dirk/services/metrics"
+	"github.com/pkg/errors"
+)
+
+// errStoreUnavailable is returned when the store is unavailable and awaiting reconnection.
+var errStoreUnavailable = errors.New("rules store unavailable")
+
+// reconnectingStore is a store that reopens its underlying store when it fails.
+// Whilst the underlying store is unavailable all operations fail, so signing
+// requests fail closed rather than being approved without slashing protection.
+type reconnectingStore struct {
+	open            func() (Storage, error)
+	monitor         metrics.RulesMonitor
+	initialInterval time.Duration
+	maxInterval     time.Duration
+
+	mu     sync.RWMutex
+	store  Storage
+	closed bool
+	done   chan struct{}
+}
+
+// newReconnectingStore creates a new reconnecting store around an initial store.
+func newReconnectingStore(store Storage,
+	open func() (Storage, error),
+	monitor metrics.RulesMonitor,
+	initialInterval time.Duration,
+	maxInterval time.Duration,
+) *reconnectingStore {
+	monitor.RulesStoreAvailable(true)
+	return &reconnectingStore{
+		open:            open,
+		monitor:         monitor,
+		initialInterval: initialInterval,
+		maxInterval:     maxInterval,
+		store:           store,
+		done:            make(chan struct{}),
+	}
+}
+
+// use runs an operation against the underlying store, starting reconnection if it fails.
+func (r *reconnectingStore) use(op func(store Storage) error) error {
+	store, err := r.run(op)
+	if isDBError(err) {
+		r.failed(store, err)
+	}
+	return err
+}
+
+// run runs an operation against the underlying store, returning the store used.
+func (r *reconnectingStore) run(op func(store Storage) error) (Storage, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.store == nil {
+		return nil, errStoreUnavailable
+	}
+	return r.store, op(r.store)
+}
+
+// failed marks the store as unavailable and starts reconnecting to it.
+func (r *reconnectingStore) failed(store Storage, err error) {
+	r.mu.Lock()
+	if r.closed || r.store != store {
+		// Closed, or another caller has already handled this failure.
+		r.mu.Unlock()
+		return
+	}
+	r.store = nil
+	r.mu.Unlock()
+
+	log.Error().Err(err).Msg("Rules store failed; signing requests will fail until it is reopened")
+	r.monitor.RulesStoreAvailable(false)
+	if err := store.Close(context.Background()); err != nil {
+		log.Debug().Err(err).Msg("Failed to close failed rules store")
+	}
+
+	go r.reconnect()
+}
+
+// reconnect reopens the underlying store, with backoff.
+func (r *reconnectingStore) reconnect() {
+	interval := r.initialInterval
+	for {
+		select {
+		case <-r.done:
+			return
+		case <-time.After(interval):
+		}
+
+		store, err := r.open()
+		if err != nil {
+			log.Debug().Err(err).Dur("retry_interval", interval).Msg("Failed to reopen rules store")
+			interval *= 2
+			if interval > r.maxInterval {
+				interval = r.maxInterval
+			}
+			continue
+		}
+
+		r.mu.Lock()
+		if r.closed {
+			r.mu.Unlock()
+			if err := store.Close(context.Background()); err != nil {
+				log.Debug().Err(err).Msg("Failed to close reopened rules store")
+			}
+			return
+		}
+		r.store = store
+		r.mu.Unlock()
+
+		log.Info().Msg("Rules store reopened; signing requests will resume")
+		r.monitor.RulesStoreAvailable(true)
+		return
+	}
+}
+
+// Fetch fetches a value for a given key.
+func (r *reconnectingStore) Fetch(ctx context.Context, key []byte) ([]byte, error) {
+	var value []byte
+	err := r.use(func(store Storage) error {
+		var err error
+		value, err = store.Fetch(ctx, key)
+		return err
+	})
+	return value, err
+}
+
+// Update fetches and updates values for the given keys in a single transaction.
+func (r *reconnectingStore) Update(ctx context.Context, keys [][]byte, update func(values [][]byte) ([][]byte, error)) error {
+	return r.use(func(store Storage) error {
+		return store.Update(ctx, keys, update)
+	})
+}
+
+// FetchAll fetches a map of all keys and values.
+func (r *reconnectingStore) FetchAll(ctx context.Context) (map[[49]byte][]byte, error) {
+	var items map[[49]byte][]byte
+	err := r.use(func(store Storage) error {
+		var err error
+		items, err = store.FetchAll(ctx)
+		return err
+	})
+	return items, err
+}
+
+// Store stores the value for a given key.
+func (r *reconnectingStore) Store(ctx context.Context, key []byte, value []byte) error {
+	return r.use(func(store Storage) error {
+		return store.Store(ctx, key, value)
+	})
+}
+
+// BatchStore stores multiple keys and values.
+func (r *reconnectingStore) BatchStore(ctx context.Context, keys [][]byte, values [][]byte) error {
+	return r.use(func(store Storage) error {
+		return store.BatchStore(ctx, keys, values)
+	})
+}
+
+// Delete deletes the value for a given key.
+func (r *reconnectingStore) Delete(ctx context.Context, key []byte) error {
+	return r.use(func(store Storage) error {
+		return store.Delete(ctx, key)
+	})
+}
+
+// Close closes the store, and stops any reconnection attempts.
+func (r *reconnectingStore) Close(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return nil
+	}
+	r.closed = true
+	close(r.done)
+	if r.store == nil {
+		return nil
+	}
+	return r.store.Close(ctx)
+}
@@ -0,0 +1,152 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/attestantio/dirk/rules"
+	"github.com/stretchr/testify/require"
+	e2types "github.com/wealdtech/go-eth2-types/v2"
+)
+
+// unavailableStore is a store whose database has failed.
+type unavailableStore struct {
+	Storage
+}
+
+func (s *unavailableStore) Fetch(ctx context.Context, key []byte) ([]byte, error) {
+	return nil, wrapDBError(errors.New("database failed"))
+}
+
+func (s *unavailableStore) Update(ctx context.Context, keys [][]byte, update func(values [][]byte) ([][]byte, error)) error {
+	return wrapDBError(errors.New("database failed"))
+}
+
+func (s *unavailableStore) Store(ctx context.Context, key []byte, value []byte) error {
+	return wrapDBError(errors.New("database failed"))
+}
+
+func (s *unavailableStore) Close(ctx context.Context) error {
+	return nil
+}
+
+// availabilityMonitor records changes in store availability.
+type availabilityMonitor struct {
+	mu           sync.Mutex
+	availability []bool
+}
+
+func (m *availabilityMonitor) SlashingProtectionWriteFailed(request string) {}
+
+func (m *availabilityMonitor) ValidatorNotAllowed(request string) {}
+
+func (m *availabilityMonitor) SlashingProtectionDenied(request string, reason string) {}
+
+func (m *availabilityMonitor) RulesStoreAvailable(available bool) {
+	m.mu.Lock()
+	m.availability = append(m.availability, available)
+	m.mu.Unlock()
+}
+
+func (m *availabilityMonitor) transitions() []bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]bool{}, m.availability...)
+}
+
+func TestReconnectingStore(t *testing.T) {
+	ctx := context.Background()
+	require.NoError(t, e2types.InitBLS())
+
+	base := t.TempDir()
+	rulesSvc, err := New(ctx,
+		WithStoragePath(base),
+		WithReconnectInterval(10*time.Millisecond),
+		WithMaxReconnectInterval(20*time.Millisecond),
+	)
+	require.NoError(t, err)
+	monitor := &availabilityMonitor{}
+
+	// Replace the store with one that has failed, and that opens successfully
+	// on the third attempt.
+	goodStore, err := NewStore(t.TempDir())
+	require.NoError(t, err)
+	var attemptsMu sync.Mutex
+	attempts := 0
+	store := newReconnectingStore(&unavailableStore{}, func() (Storage, error) {
+		attemptsMu.Lock()
+		defer attemptsMu.Unlock()
+		attempts++
+		if attempts < 3 {
+			return nil, errors.New("still unavailable")
+		}
+		return goodStore, nil
+	}, monitor, 10*time.Millisecond, 20*time.Millisecond)
+	require.NoError(t, rulesSvc.store.Close(ctx))
+	rulesSvc.store = store
+
+	pubKey := make([]byte, 48)
+	root := make([]byte, 32)
+	reqData := &rules.SignBeaconProposalData{
+		Domain:     e2types.Domain(e2types.DomainBeaconProposer, e2types.ZeroForkVersion, e2types.ZeroGenesisValidatorsRoot),
+		Slot:       1,
+		ParentRoot: root,
+		StateRoot:  root,
+		BodyRoot:   root,
+	}
+	metadata := &rules.ReqMetadata{PubKey: pubKey}
+
+	// Signing fails whilst the store is unavailable.
+	require.Equal(t, rules.FAILED, rulesSvc.OnSignBeaconProposal(ctx, metadata, reqData))
+	require.Equal(t, rules.FAILED, rulesSvc.OnSignBeaconProposal(ctx, metadata, reqData))
+	_, err = store.Fetch(ctx, pubKey)
+	require.Equal(t, errStoreUnavailable, err)
+
+	// Signing resumes once the store is reopened.
+	require.Eventually(t, func() bool {
+		return rulesSvc.OnSignBeaconProposal(ctx, metadata, reqData) == rules.APPROVED
+	}, time.Second, 10*time.Millisecond)
+	require.Equal(t, []bool{true, false, true}, monitor.transitions())
+	attemptsMu.Lock()
+	require.Equal(t, 3, attempts)
+	attemptsMu.Unlock()
+
+	// Slashing protection is enforced against the reopened store.
+	require.Equal(t, rules.DENIED, rulesSvc.OnSignBeaconProposal(ctx, metadata, reqData))
+	require.NoError(t, store.Close(ctx))
+}
+
+func TestReconnectingStoreIgnoresRequestErrors(t *testing.T) {
+	ctx := context.Background()
+
+	innerStore, err := NewStore(t.TempDir())
+	require.NoError(t, err)
+	monitor := &availabilityMonitor{}
+	store := newReconnectingStore(innerStore, func() (Storage, error) {
+		return nil, errors.New("should not reopen")
+	}, monitor, time.Second, time.Second)
+	defer store.Close(ctx)
+
+	// Errors with the request, or missing keys, do not mark the store as unavailable.
+	require.EqualError(t, store.Store(ctx, nil, []byte{0x01}), "no key provided")
+	_, err = store.Fetch(ctx, []byte{0x01})
+	require.EqualError(t, err, "not found")
+	require.NoError(t, store.Store(ctx, []byte{0x01}, []byte{0x02}))
+	require.Equal(t, []bool{true}, monitor.transitions())
+}
@@ -16,6 +16,9 @@ package standard
 import (
 	"context"
 
+	"github.com/attestantio/dirk/services/chaintime"
+	"github.com/attestantio/dirk/services/metrics"
+	"github.com/attestantio/dirk/util"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
 	zerologger "github.com/rs/zerolog/log"
@@ -23,8 +26,26 @@ import (
 
 // Service is the structure that keeps track of rules.
 type Service struct {
-	store    *Store
-	adminIPs []string
+	monitor           metrics.RulesMonitor
+	store             Storage
+	adminIPs          []string
+	adminAllowAll     bool
+	minAttestationGap uint64
+	// maxAttestationEpochDistance is the maximum number of epochs by which an attestation's target may
+	// be ahead of the current epoch; 0 for no maximum.
+	maxAttestationEpochDistance uint64
+	chainTime                   chaintime.Service
+	// blsToExecutionChangeValidators are the validators for which BLS to execution changes may be signed.
+	// If nil, changes may be signed for any validator.
+	blsToExecutionChangeValidators map[uint64]bool
+	// validatorAllowList are the public keys of the validators for which signing is permitted.
+	// If nil, signing is permitted for any validator.
+	validatorAllowList map[[48]byte]bool
+	// maxRegistrationGasLimit is the maximum gas limit for validator registrations; 0 for no maximum.
+	maxRegistrationGasLimit uint64
+	// registrationFeeRecipients are the fee recipients permitted in validator registrations.
+	// If nil, any fee recipient is permitted.
+	registrationFeeRecipients map[[20]byte]bool
 }
 
 // log is a module-wide log.
@@ -43,14 +64,50 @@ func New(ctx context.Context, params ...Parameter) (*Service, error) {
 		log = log.Level(parameters.logLevel)
 	}
 
-	store, err := NewStore(parameters.storagePath)
-	if err != nil {
-		return nil, err
+	if len(parameters.adminIPs) == 0 && !parameters.adminAllowAll {
+		log.Warn().Msg("No admin IPs configured; voluntary exits will not be signed")
+	}
+
+	store := parameters.storage
+	if store == nil {
+		store, err = newLocalStore(ctx, parameters)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	s := &Service{
-		store:    store,
-		adminIPs: parameters.adminIPs,
+		monitor:                     parameters.monitor,
+		store:                       store,
+		adminIPs:                    parameters.adminIPs,
+		adminAllowAll:               parameters.adminAllowAll,
+		minAttestationGap:           parameters.minAttestationGap,
+		maxRegistrationGasLimit:     parameters.maxRegistrationGasLimit,
+		maxAttestationEpochDistance: parameters.maxAttestationEpochDistance,
+		chainTime:                   parameters.chainTime,
+	}
+	if len(parameters.blsToExecutionChangeValidators) > 0 {
+		s.blsToExecutionChangeValidators = make(map[uint64]bool, len(parameters.blsToExecutionChangeValidators))
+		for _, index := range parameters.blsToExecutionChangeValidators {
+			s.blsToExecutionChangeValidators[index] = true
+		}
+	}
+	if len(parameters.validatorAllowList) > 0 {
+		s.validatorAllowList = make(map[[48]byte]bool, len(parameters.validatorAllowList))
+		for _, pubKey := range parameters.validatorAllowList {
+			var key [48]byte
+			copy(key[:], pubKey)
+			s.validatorAllowList[key] = true
+		}
+		log.Info().Int("validators", len(s.validatorAllowList)).Msg("Signing restricted to validators in the allow-list")
+	}
+	if len(parameters.registrationFeeRecipients) > 0 {
+		s.registrationFeeRecipients = make(map[[20]byte]bool, len(parameters.registrationFeeRecipients))
+		for _, feeRecipient := range parameters.registrationFeeRecipients {
+			var key [20]byte
+			copy(key[:], feeRecipient)
+			s.registrationFeeRecipients[key] = true
+		}
 	}
 
 	// Close the store when the context is cancelled.
@@ -66,6 +123,38 @@ func New(ctx context.Context, params ...Parameter) (*Service, error) {
 	return s, nil
 }
 
+// newLocalStore creates the store at the storage path, reopening it if it fails.
+func newLocalStore(ctx context.Context, parameters *parameters) (Storage, error) {
+	open := func() (Storage, error) {
+		switch {
+		case parameters.storageType == "sqlite" && parameters.readOnly:
+			return NewReadOnlySQLiteStore(parameters.storagePath)
+		case parameters.storageType == "sqlite":
+			return NewSQLiteStore(parameters.storagePath)
+		case parameters.readOnly:
+			return NewReadOnlyStore(parameters.storagePath)
+		default:
+			return NewStore(parameters.storagePath)
+		}
+	}
+
+	store, err := open()
+	if err != nil {
+		return nil, err
+	}
+	if parameters.readOnly {
+		return store, nil
+	}
+	// Refuse to start if slashing protection cannot be persisted.
+	if err := util.CheckWritable(parameters.storagePath); err != nil {
+		if closeErr := store.Close(ctx); closeErr != nil {
+			log.Warn().Err(closeErr).Msg("Failed to close rules storage")
+		}
+		return nil, errors.Wrap(err, "rules storage path is not usable")
+	}
+	return newReconnectingStore(store, open, parameters.monitor, parameters.reconnectInterval, parameters.maxReconnectInterval), nil
+}
+
 // Close closes the database for the persistent rules information.
 func (s *Service) Close(ctx context.Context) error {
 	return s.store.Close(ctx)
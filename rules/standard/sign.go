@@ -33,19 +33,33 @@ func (s *Service) OnSign(ctx context.Context, metadata *rules.ReqMetadata, req *
 	}
 	log := log.With().Str("client", metadata.Client).Str("account", metadata.Account).Str("rule", "sign").Logger()
 
+	if !s.validatorAllowed(ctx, log, metadata, "sign") {
+		return rules.DENIED
+	}
+
 	if bytes.Equal(req.Domain[0:4], e2types.DomainBeaconAttester[:]) {
 		log.Warn().Msg("Not signing beacon attestation request with generic signer")
+		rules.SetReason(ctx, "Not signing beacon attestation request with generic signer")
 		return rules.DENIED
 	}
 	if bytes.Equal(req.Domain[0:4], e2types.DomainBeaconProposer[:]) {
 		log.Warn().Msg("Not signing beacon proposal request with generic signer")
+		rules.SetReason(ctx, "Not signing beacon proposal request with generic signer")
+		return rules.DENIED
+	}
+
+	if bytes.Equal(req.Domain[0:4], domainBLSToExecutionChange) {
+		log.Warn().Msg("Not signing BLS to execution change request with generic signer")
+		rules.SetReason(ctx, "Not signing BLS to execution change request with generic signer")
 		return rules.DENIED
 	}
 
 	// Voluntary exit requests must come from an approved IP address.
-	if bytes.Equal(req.Domain[0:4], e2types.DomainVoluntaryExit[:]) {
+	// An empty list of addresses approves none, unless all addresses are explicitly allowed.
+	if bytes.Equal(req.Domain[0:4], e2types.DomainVoluntaryExit[:]) && !s.adminAllowAll {
 		if metadata.IP == "" {
 			log.Warn().Msg("Not signing voluntary exit request from unknown source")
+			rules.SetReason(ctx, "Not signing voluntary exit request from unknown source")
 			return rules.DENIED
 		}
 		validIP := false
@@ -57,6 +71,7 @@ func (s *Service) OnSign(ctx context.Context, metadata *rules.ReqMetadata, req *
 		}
 		if !validIP {
 			log.Warn().Str("request_ip", metadata.IP).Msg("Not signing voluntary exit request from unapproved IP address")
+			rules.SetReason(ctx, "Not signing voluntary exit request from unapproved IP address")
 			return rules.DENIED
 		}
 	}
@@ -77,6 +77,15 @@ func TestSign(t *testing.T) {
 			},
 			res: rules.DENIED,
 		},
+		{
+			name:     "BLSToExecutionChangeDomain",
+			metadata: &rules.ReqMetadata{},
+			req: &rules.SignData{
+				Data:   _byteStr(t, "0000000000000000000000000000000000000000000000000000000000000000"),
+				Domain: _byteStr(t, "0a00000000000000000000000000000000000000000000000000000000000000"),
+			},
+			res: rules.DENIED,
+		},
 		{
 			name:     "Good",
 			metadata: &rules.ReqMetadata{},
@@ -126,3 +135,72 @@ func TestSign(t *testing.T) {
 		})
 	}
 }
+
+func TestSignVoluntaryExitAdminIPs(t *testing.T) {
+	ctx := context.Background()
+	exit := &rules.SignData{
+		Data:   _byteStr(t, "0000000000000000000000000000000000000000000000000000000000000000"),
+		Domain: _byteStr(t, "0400000000000000000000000000000000000000000000000000000000000000"),
+	}
+
+	tests := []struct {
+		name     string
+		adminIPs []string
+		allowAll bool
+		err      string
+		results  map[string]rules.Result
+	}{
+		{
+			name: "Empty",
+			results: map[string]rules.Result{
+				"":        rules.DENIED,
+				"1.2.3.4": rules.DENIED,
+			},
+		},
+		{
+			name:     "Populated",
+			adminIPs: []string{"1.2.3.4"},
+			results: map[string]rules.Result{
+				"":        rules.DENIED,
+				"1.2.3.4": rules.APPROVED,
+				"2.3.4.5": rules.DENIED,
+			},
+		},
+		{
+			name:     "AllowAll",
+			allowAll: true,
+			results: map[string]rules.Result{
+				"":        rules.APPROVED,
+				"1.2.3.4": rules.APPROVED,
+			},
+		},
+		{
+			name:     "AllowAllWithIPs",
+			adminIPs: []string{"1.2.3.4"},
+			allowAll: true,
+			err:      "problem with parameters: admin IPs cannot be specified when all admin IPs are allowed",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			base, err := ioutil.TempDir("", "")
+			require.NoError(t, err)
+			defer os.RemoveAll(base)
+			testRules, err := standardrules.New(ctx,
+				standardrules.WithStoragePath(base),
+				standardrules.WithAdminIPs(test.adminIPs),
+				standardrules.WithAdminAllowAll(test.allowAll),
+			)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			defer testRules.Close(ctx)
+			for ip, res := range test.results {
+				require.Equal(t, res, testRules.OnSign(ctx, &rules.ReqMetadata{IP: ip}, exit), ip)
+			}
+		})
+	}
+}
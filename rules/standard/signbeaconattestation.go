@@ -70,62 +70,61 @@ func (s *Service) OnSignBeaconAttestation(ctx context.Context, metadata *rules.R
 	defer span.Finish()
 	log := log.With().Str("client", metadata.Client).Str("account", metadata.Account).Str("rule", "sign beacon attestation").Logger()
 
-	// Fetch state from previous signings.
-	state, err := s.fetchSignBeaconAttestationState(ctx, metadata.PubKey)
-	if err != nil {
-		log.Error().Err(err).Msg("Failed to fetch state for beacon attestation")
-		return rules.FAILED
-	}
-
-	res := s.runSignBeaconAttestationChecks(ctx, metadata, req, state)
-	if res != rules.APPROVED {
-		return res
+	if !s.validatorAllowed(ctx, log, metadata, "attestation") {
+		return rules.DENIED
 	}
 
-	state.SourceEpoch = int64(req.Source.Epoch)
-	state.TargetEpoch = int64(req.Target.Epoch)
-	if err = s.storeSignBeaconAttestationState(ctx, metadata.PubKey, state); err != nil {
+	// Fetch state from previous signings, check the request against it and
+	// update it, within a single transaction so that concurrent requests
+	// cannot both pass the checks.
+	key := signBeaconAttestationKey(metadata.PubKey)
+	res := rules.FAILED
+	checked := false
+	err := s.store.Update(ctx, [][]byte{key}, func(values [][]byte) ([][]byte, error) {
+		state, err := decodeSignBeaconAttestationState(values[0])
+		if err != nil {
+			return nil, err
+		}
+		checked = true
+		res = s.runSignBeaconAttestationChecks(ctx, metadata, req, state)
+		if res != rules.APPROVED || rules.IsCheckOnly(ctx) {
+			// State is not updated for denied or check-only requests.
+			return nil, nil
+		}
+		log.Trace().Int64("source_epoch", state.SourceEpoch).Int64("target_epoch", state.TargetEpoch).Msg("Storing attestation state to store")
+		return [][]byte{state.Encode()}, nil
+	})
+	if err != nil {
+		if !checked {
+			log.Error().Err(err).Msg("Failed to fetch state for beacon attestation")
+			return rules.FAILED
+		}
 		log.Error().Err(err).Msg("Failed to store state for beacon attestation")
+		s.monitor.SlashingProtectionWriteFailed("attestation")
 		return rules.FAILED
 	}
 
 	return res
 }
 
-func (s *Service) fetchSignBeaconAttestationState(ctx context.Context, pubKey []byte) (*signBeaconAttestationState, error) {
-	state := &signBeaconAttestationState{}
+// signBeaconAttestationKey returns the store key for the attestation state of a public key.
+func signBeaconAttestationKey(pubKey []byte) []byte {
 	key := make([]byte, len(pubKey)+len(actionSignBeaconAttestation))
 	copy(key, pubKey)
 	copy(key[len(pubKey):], actionSignBeaconAttestation)
-	data, err := s.store.Fetch(ctx, key)
-	if err != nil {
-		if err.Error() == "not found" {
-			// No values; set them to -1.
-			state.SourceEpoch = -1
-			state.TargetEpoch = -1
-		} else {
-			return nil, err
-		}
-	} else {
-		err := state.Decode(data)
-		if err != nil {
-			return nil, errors.Wrap(err, "failed to decode state")
-		}
-	}
-	log.Trace().Int64("source_epoch", state.SourceEpoch).Int64("target_epoch", state.TargetEpoch).Msg("Returning attestation state from store")
-	return state, nil
+	return key
 }
 
-func (s *Service) storeSignBeaconAttestationState(ctx context.Context, pubKey []byte, state *signBeaconAttestationState) error {
-	key := make([]byte, len(pubKey)+len(actionSignBeaconAttestation))
-	copy(key, pubKey)
-	copy(key[len(pubKey):], actionSignBeaconAttestation)
-
-	err := s.store.Store(ctx, key, state.Encode())
-	if err != nil {
-		return err
+// decodeSignBeaconAttestationState decodes attestation state from the store, which is nil if not present.
+func decodeSignBeaconAttestationState(data []byte) (*signBeaconAttestationState, error) {
+	state := &signBeaconAttestationState{}
+	if data == nil {
+		// No values; set them to -1.
+		state.SourceEpoch = -1
+		state.TargetEpoch = -1
+	} else if err := state.Decode(data); err != nil {
+		return nil, errors.Wrap(err, "failed to decode state")
 	}
-
-	log.Trace().Int64("source_epoch", state.SourceEpoch).Int64("target_epoch", state.TargetEpoch).Msg("Stored attestation state to store")
-	return nil
+	log.Trace().Int64("source_epoch", state.SourceEpoch).Int64("target_epoch", state.TargetEpoch).Msg("Returning attestation state from store")
+	return state, nil
 }
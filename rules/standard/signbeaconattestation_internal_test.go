@@ -14,8 +14,10 @@
 package standard
 
 import (
+	"context"
 	"testing"
 
+	"github.com/attestantio/dirk/rules"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -100,3 +102,37 @@ func TestSignBeaconAttestationStateDecode(t *testing.T) {
 		})
 	}
 }
+
+func TestMinAttestationGapNeverRelaxes(t *testing.T) {
+	ctx := context.Background()
+	baseRules := &Service{monitor: &noopMonitor{}}
+	domain := make([]byte, 32)
+	domain[0] = 0x01
+
+	for gap := uint64(0); gap <= 4; gap++ {
+		gapRules := &Service{minAttestationGap: gap, monitor: &noopMonitor{}}
+		for previousSource := int64(-1); previousSource <= 6; previousSource++ {
+			for previousTarget := int64(-1); previousTarget <= 8; previousTarget++ {
+				for source := uint64(0); source <= 8; source++ {
+					for target := uint64(0); target <= 10; target++ {
+						req := &rules.SignBeaconAttestationData{
+							Domain: domain,
+							Source: &rules.Checkpoint{Epoch: source},
+							Target: &rules.Checkpoint{Epoch: target},
+						}
+						baseRes := baseRules.runSignBeaconAttestationChecks(ctx, &rules.ReqMetadata{}, req,
+							&signBeaconAttestationState{SourceEpoch: previousSource, TargetEpoch: previousTarget})
+						gapRes := gapRules.runSignBeaconAttestationChecks(ctx, &rules.ReqMetadata{}, req,
+							&signBeaconAttestationState{SourceEpoch: previousSource, TargetEpoch: previousTarget})
+						if baseRes != rules.APPROVED {
+							require.Equal(t, baseRes, gapRes, "gap %d relaxed previous %d/%d request %d/%d", gap, previousSource, previousTarget, source, target)
+						}
+						if gap <= 1 {
+							require.Equal(t, baseRes, gapRes, "gap %d changed previous %d/%d request %d/%d", gap, previousSource, previousTarget, source, target)
+						}
+					}
+				}
+			}
+		}
+	}
+}
@@ -18,9 +18,11 @@ import (
 	"io/ioutil"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/attestantio/dirk/rules"
 	standardrules "github.com/attestantio/dirk/rules/standard"
+	standardchaintime "github.com/attestantio/dirk/services/chaintime/standard"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -40,6 +42,7 @@ func TestSignBeaconAttestation(t *testing.T) {
 		metadata *rules.ReqMetadata
 		req      *rules.SignBeaconAttestationData
 		res      rules.Result
+		reason   rules.DenialReason
 	}{
 		{
 			name:     "BadDomain",
@@ -89,7 +92,8 @@ func TestSignBeaconAttestation(t *testing.T) {
 					Epoch: 8,
 				},
 			},
-			res: rules.DENIED,
+			res:    rules.DENIED,
+			reason: rules.DenialDoubleAttestation,
 		},
 		{
 			name:     "EarlierSourceThanStored",
@@ -103,7 +107,8 @@ func TestSignBeaconAttestation(t *testing.T) {
 					Epoch: 8,
 				},
 			},
-			res: rules.DENIED,
+			res:    rules.DENIED,
+			reason: rules.DenialDoubleAttestation,
 		},
 		{
 			name:     "Surrounding",
@@ -117,7 +122,8 @@ func TestSignBeaconAttestation(t *testing.T) {
 					Epoch: 9,
 				},
 			},
-			res: rules.DENIED,
+			res:    rules.DENIED,
+			reason: rules.DenialSurroundVote,
 		},
 		{
 			name:     "Surrounded",
@@ -131,14 +137,201 @@ func TestSignBeaconAttestation(t *testing.T) {
 					Epoch: 6,
 				},
 			},
-			res: rules.DENIED,
+			res:    rules.DENIED,
+			reason: rules.DenialSurroundVote,
 		},
 	}
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
+			ctx := rules.WithReason(ctx)
 			res := testRules.OnSignBeaconAttestation(ctx, test.metadata, test.req)
 			assert.Equal(t, test.res, res)
+			assert.Equal(t, test.reason, rules.ReasonCode(ctx))
+		})
+	}
+}
+
+func TestSignBeaconAttestationMinAttestationGap(t *testing.T) {
+	ctx := context.Background()
+	base, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(base)
+	testRules, err := standardrules.New(ctx,
+		standardrules.WithStoragePath(base),
+		standardrules.WithMinAttestationGap(3),
+	)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		source uint64
+		target uint64
+		res    rules.Result
+		reason string
+		code   rules.DenialReason
+	}{
+		{
+			name:   "First",
+			source: 4,
+			target: 8,
+			res:    rules.APPROVED,
+		},
+		{
+			name:   "SameTargetAsStored",
+			source: 4,
+			target: 8,
+			res:    rules.DENIED,
+			reason: "Request target epoch 8 equal to or lower than previous signed target epoch 8",
+			code:   rules.DenialDoubleAttestation,
+		},
+		{
+			name:   "WithinGap",
+			source: 8,
+			target: 10,
+			res:    rules.DENIED,
+			reason: "Request target epoch 10 within minimum attestation gap of 3 epochs from previous signed target epoch 8",
+			code:   rules.DenialMinAttestationGap,
+		},
+		{
+			name:   "Surrounding",
+			source: 3,
+			target: 20,
+			res:    rules.DENIED,
+			reason: "Request source epoch 3 lower than previous signed source epoch 4",
+			code:   rules.DenialSurroundVote,
+		},
+		{
+			name:   "AtGap",
+			source: 8,
+			target: 11,
+			res:    rules.APPROVED,
+		},
+		{
+			name:   "BeyondGap",
+			source: 11,
+			target: 20,
+			res:    rules.APPROVED,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := &rules.SignBeaconAttestationData{
+				Domain: _byteStr(t, "0100000000000000000000000000000000000000000000000000000000000000"),
+				Source: &rules.Checkpoint{
+					Epoch: test.source,
+				},
+				Target: &rules.Checkpoint{
+					Epoch: test.target,
+				},
+			}
+			checkCtx := rules.WithCheckOnly(ctx)
+			require.Equal(t, test.res, testRules.OnSignBeaconAttestation(checkCtx, &rules.ReqMetadata{}, req))
+			require.Equal(t, test.reason, rules.Reason(checkCtx))
+			require.Equal(t, test.code, rules.ReasonCode(checkCtx))
+			reasonCtx := rules.WithReason(ctx)
+			require.Equal(t, test.res, testRules.OnSignBeaconAttestation(reasonCtx, &rules.ReqMetadata{}, req))
+			require.Equal(t, test.code, rules.ReasonCode(reasonCtx))
 		})
 	}
 }
+
+func TestSignBeaconAttestationMaxAttestationEpochDistance(t *testing.T) {
+	ctx := context.Background()
+	base, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(base)
+
+	_, err = standardrules.New(ctx,
+		standardrules.WithStoragePath(base),
+		standardrules.WithMaxAttestationEpochDistance(2),
+	)
+	require.EqualError(t, err, "problem with parameters: chain time is required for maximum attestation epoch distance")
+
+	// Genesis is set part way through epoch 10.
+	chainTime, err := standardchaintime.New(ctx,
+		standardchaintime.WithGenesisTime(time.Now().Add(-(10*32+16)*12*time.Second)),
+		standardchaintime.WithSlotDuration(12*time.Second),
+		standardchaintime.WithSlotsPerEpoch(32),
+	)
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), chainTime.CurrentEpoch())
+
+	testRules, err := standardrules.New(ctx,
+		standardrules.WithStoragePath(base),
+		standardrules.WithChainTime(chainTime),
+		standardrules.WithMaxAttestationEpochDistance(2),
+	)
+	require.NoError(t, err)
+
+	metadata := &rules.ReqMetadata{
+		PubKey: _byteStr(t, "a99a76ed7796f7be22d5b7e85deeb7c5677e88e511e0b337618f8c4eb61349b4bf2d153f649f7b53359fe8b94a38e44c"),
+	}
+	tests := []struct {
+		name   string
+		source uint64
+		target uint64
+		res    rules.Result
+		reason string
+		code   rules.DenialReason
+	}{
+		{
+			name:   "Current",
+			source: 9,
+			target: 10,
+			res:    rules.APPROVED,
+		},
+		{
+			name:   "AtDistance",
+			source: 10,
+			target: 12,
+			res:    rules.APPROVED,
+		},
+		{
+			name:   "BeyondDistance",
+			source: 12,
+			target: 13,
+			res:    rules.DENIED,
+			reason: "Request target epoch 13 more than 2 epochs beyond current epoch 10",
+			code:   rules.DenialTargetTooFarAhead,
+		},
+		{
+			name:   "FarFuture",
+			source: 12,
+			target: 1000000,
+			res:    rules.DENIED,
+			reason: "Request target epoch 1000000 more than 2 epochs beyond current epoch 10",
+			code:   rules.DenialTargetTooFarAhead,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := &rules.SignBeaconAttestationData{
+				Domain: _byteStr(t, "0100000000000000000000000000000000000000000000000000000000000000"),
+				Source: &rules.Checkpoint{
+					Epoch: test.source,
+				},
+				Target: &rules.Checkpoint{
+					Epoch: test.target,
+				},
+			}
+			checkCtx := rules.WithCheckOnly(ctx)
+			require.Equal(t, test.res, testRules.OnSignBeaconAttestation(checkCtx, metadata, req))
+			require.Equal(t, test.reason, rules.Reason(checkCtx))
+			require.Equal(t, test.code, rules.ReasonCode(checkCtx))
+			reasonCtx := rules.WithReason(ctx)
+			require.Equal(t, test.res, testRules.OnSignBeaconAttestation(reasonCtx, metadata, req))
+			require.Equal(t, test.code, rules.ReasonCode(reasonCtx))
+		})
+	}
+
+	// A denied request does not update slashing protection.
+	protection, err := testRules.ExportSlashingProtection(ctx)
+	require.NoError(t, err)
+	require.Len(t, protection, 1)
+	for _, entry := range protection {
+		require.Equal(t, int64(12), entry.HighestAttestedTargetEpoch)
+	}
+}
@@ -16,7 +16,7 @@ package standard
 import (
 	"bytes"
 	"context"
-	"errors"
+	"fmt"
 	"time"
 
 	"github.com/attestantio/dirk/rules"
@@ -69,52 +69,75 @@ func (s *Service) OnSignBeaconAttestations(ctx context.Context,
 		}
 	}
 
-	pubKeys := make([][]byte, len(metadata))
+	// Requests for validators that are not permitted are denied without consulting slashing protection.
+	indices := make([]int, 0, len(metadata))
 	for i := range metadata {
-		pubKeys[i] = metadata[i].PubKey
-	}
-
-	// Fetch state from previous signings.
-	states, err := s.fetchSignBeaconAttestationStates(ctx, pubKeys)
-	if err != nil {
-		log.Error().Err(err).Msg("Failed to fetch state for beacon attestations")
-		for i := range res {
-			res[i] = rules.FAILED
+		if s.validatorAllowed(ctx, log, metadata[i], "attestation") {
+			indices = append(indices, i)
+		} else {
+			res[i] = rules.DENIED
 		}
+	}
+	if len(indices) == 0 {
 		return res
 	}
-	log.Trace().Dur("elapsed", time.Since(started)).Msg("Fetched states")
 
-	// Run the rules.
-	for i := range req {
-		res[i] = s.runSignBeaconAttestationChecks(ctx, metadata[i], req[i], states[i])
+	keys := make([][]byte, len(indices))
+	for i, index := range indices {
+		keys[i] = signBeaconAttestationKey(metadata[index].PubKey)
 	}
-	log.Trace().Dur("elapsed", time.Since(started)).Msg("Checked rules")
 
-	// Update the state
-	if err = s.storeSignBeaconAttestationStates(ctx, pubKeys, states); err != nil {
-		log.Error().Err(err).Msg("Failed to store state for beacon attestations")
-		for i := range res {
-			res[i] = rules.FAILED
+	// Fetch state from previous signings, check the requests against it and
+	// update it, within a single transaction so that concurrent requests
+	// cannot both pass the checks.
+	checked := false
+	err := s.store.Update(ctx, keys, func(values [][]byte) ([][]byte, error) {
+		states := make([]*signBeaconAttestationState, len(values))
+		for i := range values {
+			var err error
+			states[i], err = decodeSignBeaconAttestationState(values[i])
+			if err != nil {
+				return nil, err
+			}
 		}
-		return res
-	}
-	log.Trace().Dur("elapsed", time.Since(started)).Msg("Stored states")
+		log.Trace().Dur("elapsed", time.Since(started)).Msg("Fetched states")
+		checked = true
 
-	return res
-}
+		// Run the rules.
+		for i, index := range indices {
+			res[index] = s.runSignBeaconAttestationChecks(ctx, metadata[index], req[index], states[i])
+		}
+		log.Trace().Dur("elapsed", time.Since(started)).Msg("Checked rules")
 
-func (s *Service) fetchSignBeaconAttestationStates(ctx context.Context, pubKeys [][]byte) ([]*signBeaconAttestationState, error) {
-	states := make([]*signBeaconAttestationState, len(pubKeys))
-	var err error
-	for i := range pubKeys {
-		states[i], err = s.fetchSignBeaconAttestationState(ctx, pubKeys[i])
-		if err != nil {
-			return nil, err
+		if rules.IsCheckOnly(ctx) {
+			// State is not updated for check-only requests.
+			return nil, nil
 		}
+
+		// Update the state.
+		newValues := make([][]byte, len(states))
+		for i := range states {
+			newValues[i] = states[i].Encode()
+		}
+		return newValues, nil
+	})
+	if err != nil {
+		if checked {
+			log.Error().Err(err).Msg("Failed to store state for beacon attestations")
+			s.monitor.SlashingProtectionWriteFailed("attestation")
+		} else {
+			log.Error().Err(err).Msg("Failed to fetch state for beacon attestations")
+		}
+		for _, index := range indices {
+			res[index] = rules.FAILED
+		}
+		return res
+	}
+	if !rules.IsCheckOnly(ctx) {
+		log.Trace().Dur("elapsed", time.Since(started)).Msg("Stored states")
 	}
 
-	return states, nil
+	return res
 }
 
 func (s *Service) runSignBeaconAttestationChecks(ctx context.Context, metadata *rules.ReqMetadata, req *rules.SignBeaconAttestationData, state *signBeaconAttestationState) rules.Result {
@@ -123,6 +146,7 @@ func (s *Service) runSignBeaconAttestationChecks(ctx context.Context, metadata *
 	// The request must have the appropriate domain.
 	if !bytes.Equal(req.Domain[0:4], e2types.DomainBeaconAttester[:]) {
 		log.Warn().Msg("Not approving non-beacon attestation due to incorrect domain")
+		rules.SetReason(ctx, "Not approving non-beacon attestation due to incorrect domain")
 		return rules.DENIED
 	}
 
@@ -135,6 +159,7 @@ func (s *Service) runSignBeaconAttestationChecks(ctx context.Context, metadata *
 			Uint64("sourceEpoch", sourceEpoch).
 			Uint64("targetEpoch", targetEpoch).
 			Msg("Request target epoch equal to or lower than request source epoch")
+		rules.SetReason(ctx, "Request target epoch equal to or lower than request source epoch")
 		return rules.DENIED
 	}
 
@@ -145,6 +170,13 @@ func (s *Service) runSignBeaconAttestationChecks(ctx context.Context, metadata *
 				Int64("previousTargetEpoch", state.TargetEpoch).
 				Uint64("targetEpoch", targetEpoch).
 				Msg("Request target epoch equal to or lower than previous signed target epoch")
+			reason := fmt.Sprintf("Request target epoch %d equal to or lower than previous signed target epoch %d", targetEpoch, state.TargetEpoch)
+			if int64(targetEpoch) == state.TargetEpoch {
+				s.slashingProtectionDenied(ctx, "attestation", rules.DenialDoubleAttestation, reason)
+			} else {
+				// An earlier target epoch is surrounded by, or would surround, a previous attestation.
+				s.slashingProtectionDenied(ctx, "attestation", rules.DenialSurroundVote, reason)
+			}
 			return rules.DENIED
 		}
 	}
@@ -156,39 +188,45 @@ func (s *Service) runSignBeaconAttestationChecks(ctx context.Context, metadata *
 				Int64("previousSourceEpoch", state.SourceEpoch).
 				Uint64("sourceEpoch", sourceEpoch).
 				Msg("Request source epoch lower than previous signed source epoch")
+			s.slashingProtectionDenied(ctx, "attestation", rules.DenialSurroundVote,
+				fmt.Sprintf("Request source epoch %d lower than previous signed source epoch %d", sourceEpoch, state.SourceEpoch))
 			return rules.DENIED
 		}
 	}
 
-	state.SourceEpoch = int64(sourceEpoch)
-	state.TargetEpoch = int64(targetEpoch)
-
-	return rules.APPROVED
-}
-
-func (s *Service) storeSignBeaconAttestationStates(ctx context.Context, pubKeys [][]byte, states []*signBeaconAttestationState) error {
-	if len(pubKeys) != len(states) {
-		return errors.New("mismatch between number of pubkeys and number of states")
+	if s.maxAttestationEpochDistance > 0 {
+		// The request target epoch must not be too far ahead of the current epoch.
+		currentEpoch := s.chainTime.CurrentEpoch()
+		if targetEpoch > currentEpoch+s.maxAttestationEpochDistance {
+			log.Warn().
+				Uint64("currentEpoch", currentEpoch).
+				Uint64("targetEpoch", targetEpoch).
+				Uint64("maxAttestationEpochDistance", s.maxAttestationEpochDistance).
+				Msg("Request target epoch beyond maximum attestation epoch distance from current epoch")
+			rules.SetCodedReason(ctx, rules.DenialTargetTooFarAhead,
+				fmt.Sprintf("Request target epoch %d more than %d epochs beyond current epoch %d", targetEpoch, s.maxAttestationEpochDistance, currentEpoch))
+			return rules.DENIED
+		}
 	}
 
-	keys := make([][]byte, len(pubKeys))
-	values := make([][]byte, len(states))
-	for i := range keys {
-		keys[i] = make([]byte, len(pubKeys[i])+len(actionSignBeaconAttestation))
-		copy(keys[i], pubKeys[i])
-		copy(keys[i][len(pubKeys[i]):], actionSignBeaconAttestation)
-		values[i] = states[i].Encode()
+	// The slashing checks above are mandatory; the minimum attestation gap can only deny
+	// requests that they approve, never approve requests that they deny.
+	if s.minAttestationGap > 0 && state.TargetEpoch != -1 {
+		// The request target epoch must be at least the minimum gap after the previous request target epoch.
+		if targetEpoch-uint64(state.TargetEpoch) < s.minAttestationGap {
+			log.Warn().
+				Int64("previousTargetEpoch", state.TargetEpoch).
+				Uint64("targetEpoch", targetEpoch).
+				Uint64("minAttestationGap", s.minAttestationGap).
+				Msg("Request target epoch within minimum attestation gap of previous signed target epoch")
+			rules.SetCodedReason(ctx, rules.DenialMinAttestationGap,
+				fmt.Sprintf("Request target epoch %d within minimum attestation gap of %d epochs from previous signed target epoch %d", targetEpoch, s.minAttestationGap, state.TargetEpoch))
+			return rules.DENIED
+		}
 	}
 
-	err := s.store.BatchStore(ctx, keys, values)
-	if err != nil {
-		return err
-	}
+	state.SourceEpoch = int64(sourceEpoch)
+	state.TargetEpoch = int64(targetEpoch)
 
-	if e := log.Trace(); e.Enabled() {
-		for _, state := range states {
-			log.Trace().Int64("source_epoch", state.SourceEpoch).Int64("target_epoch", state.TargetEpoch).Msg("Stored attestation state to store")
-		}
-	}
-	return nil
+	return rules.APPROVED
 }
@@ -70,15 +70,51 @@ func (s *Service) OnSignBeaconProposal(ctx context.Context, metadata *rules.ReqM
 	// The request must have the appropriate domain.
 	if !bytes.Equal(req.Domain[0:4], e2types.DomainBeaconProposer[:]) {
 		log.Warn().Msg("Not approving non-beacon proposal due to incorrect domain")
+		rules.SetReason(ctx, "Not approving non-beacon proposal due to incorrect domain")
 		return rules.DENIED
 	}
 
-	// Fetch state from previous signings.
-	state, err := s.fetchSignBeaconProposalState(ctx, metadata.PubKey)
+	if !s.validatorAllowed(ctx, log, metadata, "proposal") {
+		return rules.DENIED
+	}
+
+	// Fetch state from previous signings, check the request against it and
+	// update it, within a single transaction so that concurrent requests
+	// cannot both pass the checks.
+	key := signBeaconProposalKey(metadata.PubKey)
+	res := rules.FAILED
+	checked := false
+	err := s.store.Update(ctx, [][]byte{key}, func(values [][]byte) ([][]byte, error) {
+		state, err := decodeSignBeaconProposalState(values[0])
+		if err != nil {
+			return nil, err
+		}
+		checked = true
+		res = s.runSignBeaconProposalChecks(ctx, metadata, req, state)
+		if res != rules.APPROVED || rules.IsCheckOnly(ctx) {
+			// State is not updated for denied or check-only requests.
+			return nil, nil
+		}
+		state.Slot = int64(req.Slot)
+		log.Trace().Int64("slot", state.Slot).Msg("Storing proposal state to store")
+		return [][]byte{state.Encode()}, nil
+	})
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to fetch state for beacon proposal")
+		if !checked {
+			log.Error().Err(err).Msg("Failed to fetch state for beacon proposal")
+			return rules.FAILED
+		}
+		log.Error().Err(err).Msg("Failed to store state for beacon proposal")
+		s.monitor.SlashingProtectionWriteFailed("proposal")
 		return rules.FAILED
 	}
+
+	return res
+}
+
+func (s *Service) runSignBeaconProposalChecks(ctx context.Context, metadata *rules.ReqMetadata, req *rules.SignBeaconProposalData, state *signBeaconProposalState) rules.Result {
+	log := log.With().Str("client", metadata.Client).Str("account", metadata.Account).Str("rule", "sign beacon proposal").Logger()
+
 	slot := req.Slot
 
 	if state.Slot != -1 {
@@ -88,52 +124,32 @@ func (s *Service) OnSignBeaconProposal(ctx context.Context, metadata *rules.ReqM
 				Int64("previousSlot", state.Slot).
 				Uint64("slot", slot).
 				Msg("Request slot equal to or lower than previous signed slot")
+			s.slashingProtectionDenied(ctx, "proposal", rules.DenialDoubleProposal,
+				fmt.Sprintf("Request slot %d equal to or lower than previous signed slot %d", slot, state.Slot))
 			return rules.DENIED
 		}
 	}
 
-	state.Slot = int64(slot)
-	if err = s.storeSignBeaconProposalState(ctx, metadata.PubKey, state); err != nil {
-		log.Error().Err(err).Msg("Failed to store state for beacon proposal")
-		return rules.FAILED
-	}
-
 	return rules.APPROVED
 }
 
-func (s *Service) fetchSignBeaconProposalState(ctx context.Context, pubKey []byte) (*signBeaconProposalState, error) {
-	state := &signBeaconProposalState{}
+// signBeaconProposalKey returns the store key for the proposal state of a public key.
+func signBeaconProposalKey(pubKey []byte) []byte {
 	key := make([]byte, len(pubKey)+len(actionSignBeaconProposal))
 	copy(key, pubKey)
 	copy(key[len(pubKey):], actionSignBeaconProposal)
-	data, err := s.store.Fetch(ctx, key)
-	if err != nil {
-		if err.Error() == "not found" {
-			// No value; set it to -1.
-			state.Slot = -1
-		} else {
-			return nil, err
-		}
-	} else {
-		err = state.Decode(data)
-		if err != nil {
-			return nil, errors.Wrap(err, "failed to decode state")
-		}
-	}
-	log.Trace().Int64("slot", state.Slot).Msg("Returning proposal state from store")
-	return state, nil
+	return key
 }
 
-func (s *Service) storeSignBeaconProposalState(ctx context.Context, pubKey []byte, state *signBeaconProposalState) error {
-	key := make([]byte, len(pubKey)+len(actionSignBeaconProposal))
-	copy(key, pubKey)
-	copy(key[len(pubKey):], actionSignBeaconProposal)
-
-	err := s.store.Store(ctx, key, state.Encode())
-	if err != nil {
-		return err
+// decodeSignBeaconProposalState decodes proposal state from the store, which is nil if not present.
+func decodeSignBeaconProposalState(data []byte) (*signBeaconProposalState, error) {
+	state := &signBeaconProposalState{}
+	if data == nil {
+		// No value; set it to -1.
+		state.Slot = -1
+	} else if err := state.Decode(data); err != nil {
+		return nil, errors.Wrap(err, "failed to decode state")
 	}
-
-	log.Trace().Int64("slot", state.Slot).Msg("Stored proposal state to store")
-	return nil
+	log.Trace().Int64("slot", state.Slot).Msg("Returning proposal state from store")
+	return state, nil
 }
@@ -40,6 +40,7 @@ func TestSignBeaconProposal(t *testing.T) {
 		metadata *rules.ReqMetadata
 		req      *rules.SignBeaconProposalData
 		res      rules.Result
+		reason   rules.DenialReason
 	}{
 		{
 			name:     "BadDomain",
@@ -66,7 +67,8 @@ func TestSignBeaconProposal(t *testing.T) {
 				Domain: _byteStr(t, "0000000000000000000000000000000000000000000000000000000000000000"),
 				Slot:   2,
 			},
-			res: rules.DENIED,
+			res:    rules.DENIED,
+			reason: rules.DenialDoubleProposal,
 		},
 		{
 			name:     "LowerSlot",
@@ -75,14 +77,17 @@ func TestSignBeaconProposal(t *testing.T) {
 				Domain: _byteStr(t, "0000000000000000000000000000000000000000000000000000000000000000"),
 				Slot:   1,
 			},
-			res: rules.DENIED,
+			res:    rules.DENIED,
+			reason: rules.DenialDoubleProposal,
 		},
 	}
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
+			ctx := rules.WithReason(ctx)
 			res := testRules.OnSignBeaconProposal(ctx, test.metadata, test.req)
 			assert.Equal(t, test.res, res)
+			assert.Equal(t, test.reason, rules.ReasonCode(ctx))
 		})
 	}
 }
@@ -0,0 +1,53 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard
+
+import (
+	"context"
+
+	"github.com/attestantio/dirk/rules"
+	"github.com/opentracing/opentracing-go"
+)
+
+// domainBLSToExecutionChange is the domain type for BLS to execution changes.
+var domainBLSToExecutionChange = []byte{0x0a, 0x00, 0x00, 0x00}
+
+// OnSignBLSToExecutionChange is called when a request to sign a BLS to execution change needs to be approved.
+func (s *Service) OnSignBLSToExecutionChange(ctx context.Context, metadata *rules.ReqMetadata, req *rules.SignBLSToExecutionChangeData) rules.Result {
+	span, _ := opentracing.StartSpanFromContext(ctx, "rules.OnSignBLSToExecutionChange")
+	defer span.Finish()
+
+	if metadata == nil {
+		log.Warn().Msg("No metadata to evaluate request")
+		return rules.FAILED
+	}
+	if req == nil {
+		log.Warn().Msg("No data to evaluate request")
+		return rules.FAILED
+	}
+	log := log.With().Str("client", metadata.Client).Str("account", metadata.Account).Str("rule", "sign BLS to execution change").Logger()
+
+	if !s.validatorAllowed(ctx, log, metadata, "bls_to_execution_change") {
+		return rules.DENIED
+	}
+
+	// Changes are one-time and irreversible, so operators can limit them to a known set of validators.
+	if s.blsToExecutionChangeValidators != nil && !s.blsToExecutionChangeValidators[req.ValidatorIndex] {
+		log.Warn().Uint64("validator_index", req.ValidatorIndex).Msg("Not signing BLS to execution change for unapproved validator")
+		rules.SetReason(ctx, "Not signing BLS to execution change for unapproved validator")
+		return rules.DENIED
+	}
+
+	return rules.APPROVED
+}
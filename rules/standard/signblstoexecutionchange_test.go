@@ -0,0 +1,83 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/attestantio/dirk/rules"
+	standardrules "github.com/attestantio/dirk/rules/standard"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignBLSToExecutionChange(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		validators []uint64
+		metadata   *rules.ReqMetadata
+		req        *rules.SignBLSToExecutionChangeData
+		res        rules.Result
+	}{
+		{
+			name: "MetadataNil",
+			req:  &rules.SignBLSToExecutionChangeData{ValidatorIndex: 1},
+			res:  rules.FAILED,
+		},
+		{
+			name:     "ReqNil",
+			metadata: &rules.ReqMetadata{},
+			res:      rules.FAILED,
+		},
+		{
+			name:     "Unrestricted",
+			metadata: &rules.ReqMetadata{},
+			req:      &rules.SignBLSToExecutionChangeData{ValidatorIndex: 1},
+			res:      rules.APPROVED,
+		},
+		{
+			name:       "Approved",
+			validators: []uint64{1, 2},
+			metadata:   &rules.ReqMetadata{},
+			req:        &rules.SignBLSToExecutionChangeData{ValidatorIndex: 2},
+			res:        rules.APPROVED,
+		},
+		{
+			name:       "Unapproved",
+			validators: []uint64{1, 2},
+			metadata:   &rules.ReqMetadata{},
+			req:        &rules.SignBLSToExecutionChangeData{ValidatorIndex: 3},
+			res:        rules.DENIED,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			base, err := ioutil.TempDir("", "")
+			require.NoError(t, err)
+			defer os.RemoveAll(base)
+			testRules, err := standardrules.New(ctx,
+				standardrules.WithStoragePath(base),
+				standardrules.WithBLSToExecutionChangeValidators(test.validators),
+			)
+			require.NoError(t, err)
+			defer testRules.Close(ctx)
+			require.Equal(t, test.res, testRules.OnSignBLSToExecutionChange(ctx, test.metadata, test.req))
+		})
+	}
+}
@@ -0,0 +1,44 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard
+
+import (
+	"context"
+
+	"github.com/attestantio/dirk/rules"
+	"github.com/opentracing/opentracing-go"
+)
+
+// OnSignRoot is called when a request to sign a signing root needs to be approved.
+func (s *Service) OnSignRoot(ctx context.Context, metadata *rules.ReqMetadata, req *rules.SignRootData) rules.Result {
+	span, _ := opentracing.StartSpanFromContext(ctx, "rules.OnSignRoot")
+	defer span.Finish()
+
+	if metadata == nil {
+		log.Warn().Msg("No metadata to evaluate request")
+		return rules.FAILED
+	}
+	if req == nil {
+		log.Warn().Msg("No data to evaluate request")
+		return rules.FAILED
+	}
+	log := log.With().Str("client", metadata.Client).Str("account", metadata.Account).Str("rule", "sign root").Logger()
+
+	// The message behind the root is unknown, so slashing protection cannot be applied.
+	if !s.validatorAllowed(ctx, log, metadata, "root") {
+		return rules.DENIED
+	}
+
+	return rules.APPROVED
+}
@@ -0,0 +1,60 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/attestantio/dirk/rules"
+	"github.com/opentracing/opentracing-go"
+)
+
+// OnSignValidatorRegistration is called when a request to sign a validator registration needs to be approved.
+func (s *Service) OnSignValidatorRegistration(ctx context.Context, metadata *rules.ReqMetadata, req *rules.SignValidatorRegistrationData) rules.Result {
+	span, _ := opentracing.StartSpanFromContext(ctx, "rules.OnSignValidatorRegistration")
+	defer span.Finish()
+
+	if metadata == nil {
+		log.Warn().Msg("No metadata to evaluate request")
+		return rules.FAILED
+	}
+	if req == nil {
+		log.Warn().Msg("No data to evaluate request")
+		return rules.FAILED
+	}
+	log := log.With().Str("client", metadata.Client).Str("account", metadata.Account).Str("rule", "sign validator registration").Logger()
+
+	if !s.validatorAllowed(ctx, log, metadata, "validator_registration") {
+		return rules.DENIED
+	}
+
+	if s.maxRegistrationGasLimit != 0 && req.GasLimit > s.maxRegistrationGasLimit {
+		log.Warn().Uint64("gas_limit", req.GasLimit).Uint64("max_gas_limit", s.maxRegistrationGasLimit).Msg("Not signing validator registration with gas limit above maximum")
+		rules.SetReason(ctx, "Not signing validator registration with gas limit above maximum")
+		return rules.DENIED
+	}
+
+	if s.registrationFeeRecipients != nil {
+		var feeRecipient [20]byte
+		copy(feeRecipient[:], req.FeeRecipient)
+		if len(req.FeeRecipient) != 20 || !s.registrationFeeRecipients[feeRecipient] {
+			log.Warn().Str("fee_recipient", fmt.Sprintf("%#x", req.FeeRecipient)).Msg("Not signing validator registration for unapproved fee recipient")
+			rules.SetReason(ctx, "Not signing validator registration for unapproved fee recipient")
+			return rules.DENIED
+		}
+	}
+
+	return rules.APPROVED
+}
@@ -0,0 +1,112 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/attestantio/dirk/rules"
+	standardrules "github.com/attestantio/dirk/rules/standard"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignValidatorRegistration(t *testing.T) {
+	ctx := context.Background()
+
+	feeRecipient1 := _byteStr(t, "0x0102030405060708090a0b0c0d0e0f1011121314")
+	feeRecipient2 := _byteStr(t, "0x1112131415161718191a1b1c1d1e1f2021222324")
+
+	tests := []struct {
+		name          string
+		maxGasLimit   uint64
+		feeRecipients [][]byte
+		metadata      *rules.ReqMetadata
+		req           *rules.SignValidatorRegistrationData
+		err           string
+		res           rules.Result
+	}{
+		{
+			name:          "FeeRecipientInvalid",
+			feeRecipients: [][]byte{feeRecipient1[1:]},
+			err:           "problem with parameters: registration fee recipients must be 20 bytes",
+		},
+		{
+			name: "MetadataNil",
+			req:  &rules.SignValidatorRegistrationData{FeeRecipient: feeRecipient1, GasLimit: 30000000},
+			res:  rules.FAILED,
+		},
+		{
+			name:     "ReqNil",
+			metadata: &rules.ReqMetadata{},
+			res:      rules.FAILED,
+		},
+		{
+			name:     "Unrestricted",
+			metadata: &rules.ReqMetadata{},
+			req:      &rules.SignValidatorRegistrationData{FeeRecipient: feeRecipient2, GasLimit: 60000000},
+			res:      rules.APPROVED,
+		},
+		{
+			name:        "GasLimitAtMaximum",
+			maxGasLimit: 30000000,
+			metadata:    &rules.ReqMetadata{},
+			req:         &rules.SignValidatorRegistrationData{FeeRecipient: feeRecipient1, GasLimit: 30000000},
+			res:         rules.APPROVED,
+		},
+		{
+			name:        "GasLimitAboveMaximum",
+			maxGasLimit: 30000000,
+			metadata:    &rules.ReqMetadata{},
+			req:         &rules.SignValidatorRegistrationData{FeeRecipient: feeRecipient1, GasLimit: 30000001},
+			res:         rules.DENIED,
+		},
+		{
+			name:          "FeeRecipientApproved",
+			feeRecipients: [][]byte{feeRecipient1},
+			metadata:      &rules.ReqMetadata{},
+			req:           &rules.SignValidatorRegistrationData{FeeRecipient: feeRecipient1, GasLimit: 30000000},
+			res:           rules.APPROVED,
+		},
+		{
+			name:          "FeeRecipientUnapproved",
+			feeRecipients: [][]byte{feeRecipient1},
+			metadata:      &rules.ReqMetadata{},
+			req:           &rules.SignValidatorRegistrationData{FeeRecipient: feeRecipient2, GasLimit: 30000000},
+			res:           rules.DENIED,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			base, err := ioutil.TempDir("", "")
+			require.NoError(t, err)
+			defer os.RemoveAll(base)
+			testRules, err := standardrules.New(ctx,
+				standardrules.WithStoragePath(base),
+				standardrules.WithMaxRegistrationGasLimit(test.maxGasLimit),
+				standardrules.WithRegistrationFeeRecipients(test.feeRecipients),
+			)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			defer testRules.Close(ctx)
+			require.Equal(t, test.res, testRules.OnSignValidatorRegistration(ctx, test.metadata, test.req))
+		})
+	}
+}
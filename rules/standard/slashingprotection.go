@@ -94,3 +94,23 @@ func (s *Service) ImportSlashingProtection(ctx context.Context, protection map[[
 	}
 	return nil
 }
+
+// PurgeSlashingProtection removes all slashing protection data for the given public key.
+func (s *Service) PurgeSlashingProtection(ctx context.Context, pubKey []byte) error {
+	if len(pubKey) != 48 {
+		return errors.New("invalid public key")
+	}
+
+	var key [49]byte
+	copy(key[:], pubKey)
+	key[48] = actionSignBeaconProposal[0]
+	if err := s.store.Delete(ctx, key[:]); err != nil {
+		return errors.Wrap(err, "failed to delete proposal state")
+	}
+	key[48] = actionSignBeaconAttestation[0]
+	if err := s.store.Delete(ctx, key[:]); err != nil {
+		return errors.Wrap(err, "failed to delete attestation state")
+	}
+
+	return nil
+}
@@ -183,3 +183,49 @@ func TestImportSlashingProtection(t *testing.T) {
 	require.Equal(t, int64(0x0102030405060708), export[key2].HighestAttestedSourceEpoch)
 	require.Equal(t, int64(0x0203040506070809), export[key2].HighestAttestedTargetEpoch)
 }
+
+func TestPurgeSlashingProtection(t *testing.T) {
+	ctx := context.Background()
+	base, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(base)
+	service, err := standardrules.New(ctx,
+		standardrules.WithStoragePath(base),
+	)
+	require.NoError(t, err)
+
+	key1 := [48]byte{
+		0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f,
+		0x10, 0x11, 0x12, 0x13, 0x14, 0x15, 0x16, 0x17, 0x18, 0x19, 0x1a, 0x1b, 0x1c, 0x1d, 0x1e, 0x1f,
+		0x20, 0x21, 0x22, 0x23, 0x24, 0x25, 0x26, 0x27, 0x28, 0x29, 0x2a, 0x2b, 0x2c, 0x2d, 0x2e, 0x2f,
+	}
+	key2 := [48]byte{
+		0x30, 0x31, 0x32, 0x33, 0x34, 0x35, 0x36, 0x37, 0x38, 0x39, 0x3a, 0x3b, 0x3c, 0x3d, 0x3e, 0x3f,
+		0x40, 0x41, 0x42, 0x43, 0x44, 0x45, 0x46, 0x47, 0x48, 0x49, 0x4a, 0x4b, 0x4c, 0x4d, 0x4e, 0x4f,
+		0x50, 0x51, 0x52, 0x53, 0x54, 0x55, 0x56, 0x57, 0x58, 0x59, 0x5a, 0x5b, 0x5c, 0x5d, 0x5e, 0x5f,
+	}
+	protection := map[[48]byte]*rules.SlashingProtection{
+		key1: {
+			HighestProposedSlot:        1,
+			HighestAttestedSourceEpoch: 2,
+			HighestAttestedTargetEpoch: 3,
+		},
+		key2: {
+			HighestProposedSlot:        4,
+			HighestAttestedSourceEpoch: 5,
+			HighestAttestedTargetEpoch: 6,
+		},
+	}
+	require.NoError(t, service.ImportSlashingProtection(ctx, protection))
+
+	require.EqualError(t, service.PurgeSlashingProtection(ctx, key1[:10]), "invalid public key")
+
+	require.NoError(t, service.PurgeSlashingProtection(ctx, key1[:]))
+	export, err := service.ExportSlashingProtection(ctx)
+	require.NoError(t, err)
+	require.Len(t, export, 1)
+	require.Equal(t, int64(4), export[key2].HighestProposedSlot)
+
+	// Purging again is not an error.
+	require.NoError(t, service.PurgeSlashingProtection(ctx, key1[:]))
+}
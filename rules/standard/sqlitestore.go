@@ -0,0 +1,403 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/mattn/go-sqlite3"
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+)
+
+// sqliteFilename is the name of the SQLite database within the storage path.
+const sqliteFilename = "slashing-protection.sqlite"
+
+// sqliteSchema is the schema for the SQLite database.  Each validator has at
+// most one row in each table, holding the highest values signed.  Triggers
+// refuse any update that would lower these values, so the database itself
+// enforces that protection only ever moves forward.
+var sqliteSchema = []string{
+	`CREATE TABLE IF NOT EXISTS attestations (
+  pubkey BLOB PRIMARY KEY CHECK (length(pubkey) = 48),
+  source_epoch INTEGER NOT NULL,
+  target_epoch INTEGER NOT NULL,
+  CHECK (target_epoch >= source_epoch)
+)`,
+	`CREATE TABLE IF NOT EXISTS block_proposals (
+  pubkey BLOB PRIMARY KEY CHECK (length(pubkey) = 48),
+  slot INTEGER NOT NULL
+)`,
+	`CREATE TRIGGER IF NOT EXISTS attestations_no_regression BEFORE UPDATE ON attestations
+WHEN NEW.source_epoch < OLD.source_epoch OR NEW.target_epoch < OLD.target_epoch
+BEGIN
+  SELECT RAISE(ABORT, 'attestation epochs cannot decrease');
+END`,
+	`CREATE TRIGGER IF NOT EXISTS block_proposals_no_regression BEFORE UPDATE ON block_proposals
+WHEN NEW.slot < OLD.slot
+BEGIN
+  SELECT RAISE(ABORT, 'proposal slot cannot decrease');
+END`,
+}
+
+// SQLiteStore holds slashing protection information in a SQLite database,
+// with a row per validator for attestations and block proposals.
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+// NewSQLiteStore creates a new SQLite store in the given directory.
+func NewSQLiteStore(base string) (*SQLiteStore, error) {
+	if err := os.MkdirAll(base, 0700); err != nil {
+		return nil, errors.Wrap(err, "failed to create storage path")
+	}
+
+	// Write-ahead logging allows reads whilst a write is in progress, and
+	// immediate transactions take the write lock before reading state so
+	// that concurrent updates are run one after the other.
+	dsn := fmt.Sprintf("file:%s?_journal_mode=WAL&_synchronous=FULL&_busy_timeout=5000&_txlock=immediate",
+		filepath.Join(base, sqliteFilename))
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open database")
+	}
+	for _, statement := range sqliteSchema {
+		if _, err := db.Exec(statement); err != nil {
+			if closeErr := db.Close(); closeErr != nil {
+				log.Warn().Err(closeErr).Msg("Failed to close database")
+			}
+			return nil, errors.Wrap(err, "failed to create schema")
+		}
+	}
+
+	return &SQLiteStore{
+		db: db,
+	}, nil
+}
+
+// NewReadOnlySQLiteStore opens an existing SQLite store in the given directory without writing to it.
+func NewReadOnlySQLiteStore(base string) (*SQLiteStore, error) {
+	path := filepath.Join(base, sqliteFilename)
+	if _, err := os.Stat(path); err != nil {
+		return nil, errors.Wrap(err, "failed to access database")
+	}
+	db, err := sql.Open("sqlite3", fmt.Sprintf("file:%s?mode=ro&immutable=1", path))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open database")
+	}
+	// Confirm that the database can be read.
+	if err := db.Ping(); err != nil {
+		if closeErr := db.Close(); closeErr != nil {
+			log.Warn().Err(closeErr).Msg("Failed to close database")
+		}
+		return nil, errors.Wrap(err, "failed to open database")
+	}
+
+	return &SQLiteStore{
+		db: db,
+	}, nil
+}
+
+// wrapSQLiteError wraps an error from the database.  Constraint violations
+// are caused by the data rather than the database, so are not database errors.
+func wrapSQLiteError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if sqliteErr, isSQLiteErr := err.(sqlite3.Error); isSQLiteErr && sqliteErr.Code == sqlite3.ErrConstraint {
+		return errors.Wrap(err, "constraint violated")
+	}
+	return wrapDBError(err)
+}
+
+// sqliteQueryer is the interface for running queries against a database or transaction.
+type sqliteQueryer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// fetch fetches the value for a key, returning nil if it is not present.
+func (s *SQLiteStore) fetch(ctx context.Context, q sqliteQueryer, key []byte) ([]byte, error) {
+	pubKey := key[:len(key)-1]
+	switch key[len(key)-1] {
+	case actionSignBeaconAttestation[0]:
+		state := &signBeaconAttestationState{}
+		err := q.QueryRowContext(ctx, "SELECT source_epoch, target_epoch FROM attestations WHERE pubkey = ?", pubKey).Scan(&state.SourceEpoch, &state.TargetEpoch)
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, wrapSQLiteError(err)
+		}
+		return state.Encode(), nil
+	case actionSignBeaconProposal[0]:
+		state := &signBeaconProposalState{}
+		err := q.QueryRowContext(ctx, "SELECT slot FROM block_proposals WHERE pubkey = ?", pubKey).Scan(&state.Slot)
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, wrapSQLiteError(err)
+		}
+		return state.Encode(), nil
+	default:
+		return nil, fmt.Errorf("unsupported action %#x", key[len(key)-1])
+	}
+}
+
+// store stores the value for a key.
+func (s *SQLiteStore) store(ctx context.Context, q sqliteQueryer, key []byte, value []byte) error {
+	pubKey := key[:len(key)-1]
+	var err error
+	switch key[len(key)-1] {
+	case actionSignBeaconAttestation[0]:
+		state := &signBeaconAttestationState{}
+		if err := state.Decode(value); err != nil {
+			return errors.Wrap(err, "failed to decode attestation state")
+		}
+		_, err = q.ExecContext(ctx, `INSERT INTO attestations (pubkey, source_epoch, target_epoch) VALUES (?, ?, ?)
+ON CONFLICT (pubkey) DO UPDATE SET source_epoch = excluded.source_epoch, target_epoch = excluded.target_epoch`,
+			pubKey, state.SourceEpoch, state.TargetEpoch)
+	case actionSignBeaconProposal[0]:
+		state := &signBeaconProposalState{}
+		if err := state.Decode(value); err != nil {
+			return errors.Wrap(err, "failed to decode proposal state")
+		}
+		_, err = q.ExecContext(ctx, `INSERT INTO block_proposals (pubkey, slot) VALUES (?, ?)
+ON CONFLICT (pubkey) DO UPDATE SET slot = excluded.slot`,
+			pubKey, state.Slot)
+	default:
+		return fmt.Errorf("unsupported action %#x", key[len(key)-1])
+	}
+	return wrapSQLiteError(err)
+}
+
+// checkSQLiteKey checks that a key is supported by the store.
+func checkSQLiteKey(key []byte) error {
+	if len(key) == 0 {
+		return errors.New("no key provided")
+	}
+	if len(key) != 49 {
+		return errors.New("invalid key length")
+	}
+	return nil
+}
+
+// Update fetches the values for the given keys, passes them to the update
+// function, and stores the values it returns, in a single transaction.
+func (s *SQLiteStore) Update(ctx context.Context, keys [][]byte, update func(values [][]byte) ([][]byte, error)) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "storage.Update")
+	defer span.Finish()
+
+	if len(keys) == 0 {
+		return errors.New("no keys provided")
+	}
+	for i := range keys {
+		if len(keys[i]) == 0 {
+			return errors.New("empty key provided")
+		}
+		if err := checkSQLiteKey(keys[i]); err != nil {
+			return err
+		}
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return wrapSQLiteError(err)
+	}
+	defer func() {
+		// Rollback is a no-op once the transaction has been committed.
+		_ = tx.Rollback()
+	}()
+
+	values := make([][]byte, len(keys))
+	for i := range keys {
+		values[i], err = s.fetch(ctx, tx, keys[i])
+		if err != nil {
+			return err
+		}
+	}
+
+	newValues, err := update(values)
+	if err != nil {
+		return err
+	}
+	if newValues == nil {
+		return nil
+	}
+	if len(newValues) != len(keys) {
+		return errors.New("key/value length mismatch")
+	}
+	for i := range keys {
+		if len(newValues[i]) == 0 {
+			return errors.New("empty value provided")
+		}
+		if err := s.store(ctx, tx, keys[i], newValues[i]); err != nil {
+			return err
+		}
+	}
+
+	return wrapSQLiteError(tx.Commit())
+}
+
+// Fetch fetches a value for a given key.
+func (s *SQLiteStore) Fetch(ctx context.Context, key []byte) ([]byte, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "storage.Fetch")
+	defer span.Finish()
+
+	if err := checkSQLiteKey(key); err != nil {
+		return nil, err
+	}
+
+	value, err := s.fetch(ctx, s.db, key)
+	if err != nil {
+		return nil, err
+	}
+	if value == nil {
+		return nil, ErrNotFound
+	}
+	return value, nil
+}
+
+// FetchAll fetches a map of all keys and values.
+func (s *SQLiteStore) FetchAll(ctx context.Context) (map[[49]byte][]byte, error) {
+	items := make(map[[49]byte][]byte)
+
+	rows, err := s.db.QueryContext(ctx, "SELECT pubkey, source_epoch, target_epoch FROM attestations")
+	if err != nil {
+		return nil, wrapSQLiteError(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var pubKey []byte
+		state := &signBeaconAttestationState{}
+		if err := rows.Scan(&pubKey, &state.SourceEpoch, &state.TargetEpoch); err != nil {
+			return nil, wrapSQLiteError(err)
+		}
+		var key [49]byte
+		copy(key[:], pubKey)
+		key[48] = actionSignBeaconAttestation[0]
+		items[key] = state.Encode()
+	}
+	if err := rows.Err(); err != nil {
+		return nil, wrapSQLiteError(err)
+	}
+
+	rows, err = s.db.QueryContext(ctx, "SELECT pubkey, slot FROM block_proposals")
+	if err != nil {
+		return nil, wrapSQLiteError(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var pubKey []byte
+		state := &signBeaconProposalState{}
+		if err := rows.Scan(&pubKey, &state.Slot); err != nil {
+			return nil, wrapSQLiteError(err)
+		}
+		var key [49]byte
+		copy(key[:], pubKey)
+		key[48] = actionSignBeaconProposal[0]
+		items[key] = state.Encode()
+	}
+	if err := rows.Err(); err != nil {
+		return nil, wrapSQLiteError(err)
+	}
+
+	return items, nil
+}
+
+// Store stores the value for a given key.
+func (s *SQLiteStore) Store(ctx context.Context, key []byte, value []byte) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "storage.Store")
+	defer span.Finish()
+
+	if err := checkSQLiteKey(key); err != nil {
+		return err
+	}
+	if len(value) == 0 {
+		return errors.New("no value provided")
+	}
+
+	return s.store(ctx, s.db, key, value)
+}
+
+// BatchStore stores multiple keys and values.
+func (s *SQLiteStore) BatchStore(ctx context.Context, keys [][]byte, values [][]byte) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "storage.BatchStore")
+	defer span.Finish()
+
+	if len(keys) == 0 {
+		return errors.New("no keys provided")
+	}
+	if len(keys) != len(values) {
+		return errors.New("key/value length mismatch")
+	}
+	for i := range keys {
+		if len(keys[i]) == 0 {
+			return errors.New("empty key provided")
+		}
+		if err := checkSQLiteKey(keys[i]); err != nil {
+			return err
+		}
+		if len(values[i]) == 0 {
+			return errors.New("empty value provided")
+		}
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return wrapSQLiteError(err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	for i := range keys {
+		if err := s.store(ctx, tx, keys[i], values[i]); err != nil {
+			return err
+		}
+	}
+	return wrapSQLiteError(tx.Commit())
+}
+
+// Delete deletes the value for a given key.
+// It is not an error to delete a key that does not exist.
+func (s *SQLiteStore) Delete(ctx context.Context, key []byte) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "storage.Delete")
+	defer span.Finish()
+
+	if err := checkSQLiteKey(key); err != nil {
+		return err
+	}
+
+	pubKey := key[:len(key)-1]
+	var err error
+	switch key[len(key)-1] {
+	case actionSignBeaconAttestation[0]:
+		_, err = s.db.ExecContext(ctx, "DELETE FROM attestations WHERE pubkey = ?", pubKey)
+	case actionSignBeaconProposal[0]:
+		_, err = s.db.ExecContext(ctx, "DELETE FROM block_proposals WHERE pubkey = ?", pubKey)
+	default:
+		return fmt.Errorf("unsupported action %#x", key[len(key)-1])
+	}
+	return wrapSQLiteError(err)
+}
+
+// Close closes the store.
+func (s *SQLiteStore) Close(ctx context.Context) error {
+	return s.db.Close()
+}
@@ -0,0 +1,141 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/attestantio/dirk/rules"
+	standardrules "github.com/attestantio/dirk/rules/standard"
+	"github.com/stretchr/testify/require"
+	e2types "github.com/wealdtech/go-eth2-types/v2"
+)
+
+func TestSQLiteStore(t *testing.T) {
+	ctx := context.Background()
+	base := t.TempDir()
+	store, err := standardrules.NewSQLiteStore(base)
+	require.NoError(t, err)
+
+	pubKey := make([]byte, 48)
+	proposalKey := append(append([]byte{}, pubKey...), 0x03)
+	attestationKey := append(append([]byte{}, pubKey...), 0x02)
+	proposal := []byte{0x01, 0x05, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}
+	attestation := []byte{0x01, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x03, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}
+
+	require.EqualError(t, store.Store(ctx, nil, proposal), "no key provided")
+	require.EqualError(t, store.Store(ctx, []byte("key"), proposal), "invalid key length")
+	require.EqualError(t, store.Store(ctx, append(append([]byte{}, pubKey...), 0x09), proposal), "unsupported action 0x9")
+	require.EqualError(t, store.Store(ctx, proposalKey, nil), "no value provided")
+	_, err = store.Fetch(ctx, proposalKey)
+	require.Equal(t, standardrules.ErrNotFound, err)
+
+	require.NoError(t, store.BatchStore(ctx, [][]byte{proposalKey, attestationKey}, [][]byte{proposal, attestation}))
+	value, err := store.Fetch(ctx, proposalKey)
+	require.NoError(t, err)
+	require.Equal(t, proposal, value)
+	value, err = store.Fetch(ctx, attestationKey)
+	require.NoError(t, err)
+	require.Equal(t, attestation, value)
+
+	// The database refuses to lower the recorded values.
+	lowerProposal := []byte{0x01, 0x04, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}
+	require.Contains(t, store.Store(ctx, proposalKey, lowerProposal).Error(), "proposal slot cannot decrease")
+	lowerAttestation := []byte{0x01, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x03, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}
+	require.Contains(t, store.Store(ctx, attestationKey, lowerAttestation).Error(), "attestation epochs cannot decrease")
+
+	// Data persists across reopening the store.
+	require.NoError(t, store.Close(ctx))
+	store, err = standardrules.NewSQLiteStore(base)
+	require.NoError(t, err)
+	items, err := store.FetchAll(ctx)
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+
+	require.NoError(t, store.Close(ctx))
+
+	// A read-only store can read, but not update, the data.
+	_, err = standardrules.NewReadOnlySQLiteStore(t.TempDir())
+	require.Error(t, err)
+	store, err = standardrules.NewReadOnlySQLiteStore(base)
+	require.NoError(t, err)
+	value, err = store.Fetch(ctx, proposalKey)
+	require.NoError(t, err)
+	require.Equal(t, proposal, value)
+	require.Error(t, store.Store(ctx, proposalKey, proposal))
+	require.NoError(t, store.Close(ctx))
+
+	store, err = standardrules.NewSQLiteStore(base)
+	require.NoError(t, err)
+	require.NoError(t, store.Delete(ctx, proposalKey))
+	_, err = store.Fetch(ctx, proposalKey)
+	require.Equal(t, standardrules.ErrNotFound, err)
+	require.NoError(t, store.Close(ctx))
+}
+
+func TestSQLiteSlashingProtection(t *testing.T) {
+	ctx := context.Background()
+	require.NoError(t, e2types.InitBLS())
+
+	rulesSvc, err := standardrules.New(ctx,
+		standardrules.WithStoragePath(t.TempDir()),
+		standardrules.WithStorageType("sqlite"),
+	)
+	require.NoError(t, err)
+	defer rulesSvc.Close(ctx)
+
+	metadata := &rules.ReqMetadata{PubKey: make([]byte, 48)}
+	domain := e2types.Domain(e2types.DomainBeaconAttester, e2types.ZeroForkVersion, e2types.ZeroGenesisValidatorsRoot)
+	root := make([]byte, 32)
+	attestation := func(source uint64, target uint64) *rules.SignBeaconAttestationData {
+		return &rules.SignBeaconAttestationData{
+			Domain:          domain,
+			BeaconBlockRoot: root,
+			Source:          &rules.Checkpoint{Epoch: source, Root: root},
+			Target:          &rules.Checkpoint{Epoch: target, Root: root},
+		}
+	}
+
+	// Only one of a number of concurrent requests for the same target is approved.
+	var mu sync.Mutex
+	approved := 0
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if rulesSvc.OnSignBeaconAttestation(ctx, metadata, attestation(2, 3)) == rules.APPROVED {
+				mu.Lock()
+				approved++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	require.Equal(t, 1, approved)
+
+	// Surrounding and surrounded votes are denied.
+	require.Equal(t, rules.DENIED, rulesSvc.OnSignBeaconAttestation(ctx, metadata, attestation(1, 4)))
+	require.Equal(t, rules.APPROVED, rulesSvc.OnSignBeaconAttestation(ctx, metadata, attestation(2, 5)))
+	require.Equal(t, rules.DENIED, rulesSvc.OnSignBeaconAttestation(ctx, metadata, attestation(3, 4)))
+
+	protection, err := rulesSvc.ExportSlashingProtection(ctx)
+	require.NoError(t, err)
+	var key [48]byte
+	require.Equal(t, int64(2), protection[key].HighestAttestedSourceEpoch)
+	require.Equal(t, int64(5), protection[key].HighestAttestedTargetEpoch)
+	require.Equal(t, int64(-1), protection[key].HighestProposedSlot)
+}
@@ -23,22 +23,88 @@ import (
 	"github.com/pkg/errors"
 )
 
+// ErrNotFound is returned when a key is not present in the store.
+// Storage implementations must return it from Fetch for missing keys.
+var ErrNotFound = errors.New("not found")
+
+// dbError is an error returned by the underlying database, as opposed to
+// an error with the request itself.  It signals that the store may be
+// unavailable.
+type dbError struct {
+	err error
+}
+
+func (e *dbError) Error() string {
+	return e.err.Error()
+}
+
+// isDBError returns true if the error was returned by the underlying database.
+func isDBError(err error) bool {
+	_, isDBError := err.(*dbError)
+	return isDBError
+}
+
+// wrapDBError wraps a database error, if present.
+func wrapDBError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &dbError{err: err}
+}
+
 // Store holds key/value pairs in a badger database.
 type Store struct {
 	db *badger.DB
 }
 
+// Storage is the interface for persistent storage of rules information.
+type Storage interface {
+	// Update fetches the values for the given keys, passes them to the update
+	// function, and stores the values it returns, in a single transaction.
+	// Values for keys that are not present are nil.  If the update function
+	// returns nil values, or an error, nothing is stored.
+	Update(ctx context.Context, keys [][]byte, update func(values [][]byte) ([][]byte, error)) error
+	// Fetch fetches a value for a given key.
+	Fetch(ctx context.Context, key []byte) ([]byte, error)
+	// FetchAll fetches a map of all keys and values.
+	FetchAll(ctx context.Context) (map[[49]byte][]byte, error)
+	// Store stores the value for a given key.
+	Store(ctx context.Context, key []byte, value []byte) error
+	// BatchStore stores multiple keys and values.
+	BatchStore(ctx context.Context, keys [][]byte, values [][]byte) error
+	// Delete deletes the value for a given key.
+	Delete(ctx context.Context, key []byte) error
+	// Close closes the store.
+	Close(ctx context.Context) error
+}
+
 // NewStore creates a new badger store.
 func NewStore(base string) (*Store, error) {
+	return newStore(base, false)
+}
+
+// NewReadOnlyStore opens an existing badger store without writing to it.
+func NewReadOnlyStore(base string) (*Store, error) {
+	return newStore(base, true)
+}
+
+func newStore(base string, readOnly bool) (*Store, error) {
 	opt := badger.DefaultOptions(base)
 	opt.TableLoadingMode = options.LoadToRAM
 	opt.ValueLogLoadingMode = options.MemoryMap
 	opt.SyncWrites = true
+	opt.ReadOnly = readOnly
 	opt.Logger = loggers.NewBadgerLogger(log)
 	db, err := badger.Open(opt)
 	if err != nil {
 		return nil, err
 	}
+	if readOnly {
+		// Garbage collection writes to the database.
+		return &Store{
+			db: db,
+		}, nil
+	}
 
 	// Garbage collect in the background on start.
 	go func(db *badger.DB) {
@@ -79,7 +145,7 @@ func (s *Store) FetchAll(ctx context.Context) (map[[49]byte][]byte, error) {
 		return nil
 	})
 	if err != nil {
-		return nil, err
+		return nil, wrapDBError(err)
 	}
 	return items, nil
 }
@@ -98,19 +164,15 @@ func (s *Store) Fetch(ctx context.Context, key []byte) ([]byte, error) {
 		item, err := txn.Get(key)
 		if err != nil {
 			if err == badger.ErrKeyNotFound {
-				return errors.New("not found")
+				return ErrNotFound
 			}
+			return wrapDBError(err)
 		}
-		err = item.Value(func(val []byte) error {
+		return wrapDBError(item.Value(func(val []byte) error {
 			value = make([]byte, len(val))
 			copy(value, val)
 			return nil
-		})
-		if err != nil {
-			return err
-		}
-
-		return err
+		}))
 	})
 	if err != nil {
 		return nil, err
@@ -118,6 +180,72 @@ func (s *Store) Fetch(ctx context.Context, key []byte) ([]byte, error) {
 	return value, nil
 }
 
+// Update fetches the values for the given keys, passes them to the update
+// function, and stores the values it returns, in a single transaction.
+func (s *Store) Update(ctx context.Context, keys [][]byte, update func(values [][]byte) ([][]byte, error)) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "storage.Update")
+	defer span.Finish()
+
+	if len(keys) == 0 {
+		return errors.New("no keys provided")
+	}
+	for i := range keys {
+		if len(keys[i]) == 0 {
+			return errors.New("empty key provided")
+		}
+	}
+
+	var updateErr error
+	err := s.db.Update(func(txn *badger.Txn) error {
+		values := make([][]byte, len(keys))
+		for i := range keys {
+			item, err := txn.Get(keys[i])
+			if err == badger.ErrKeyNotFound {
+				continue
+			}
+			if err != nil {
+				return wrapDBError(err)
+			}
+			values[i], err = item.ValueCopy(nil)
+			if err != nil {
+				return wrapDBError(err)
+			}
+		}
+
+		var newValues [][]byte
+		newValues, updateErr = update(values)
+		if updateErr != nil {
+			return updateErr
+		}
+		if newValues == nil {
+			return nil
+		}
+		if len(newValues) != len(keys) {
+			updateErr = errors.New("key/value length mismatch")
+			return updateErr
+		}
+		for i := range keys {
+			if len(newValues[i]) == 0 {
+				updateErr = errors.New("empty value provided")
+				return updateErr
+			}
+			if err := txn.Set(keys[i], newValues[i]); err != nil {
+				return wrapDBError(errors.Wrap(err, "failed to set"))
+			}
+		}
+		return nil
+	})
+	switch {
+	case err == nil, err == updateErr, isDBError(err):
+		return err
+	case err == badger.ErrConflict:
+		// Another transaction updated the same keys; the store itself is fine.
+		return errors.Wrap(err, "concurrent update")
+	default:
+		return wrapDBError(err)
+	}
+}
+
 // BatchStore stores multiple keys and values.
 func (s *Store) BatchStore(ctx context.Context, keys [][]byte, values [][]byte) error {
 	span, _ := opentracing.StartSpanFromContext(ctx, "storage.BatchStore")
@@ -143,11 +271,11 @@ func (s *Store) BatchStore(ctx context.Context, keys [][]byte, values [][]byte)
 
 	for i := range keys {
 		if err := wb.Set(keys[i], values[i]); err != nil {
-			return errors.Wrap(err, "failed to set")
+			return wrapDBError(errors.Wrap(err, "failed to set"))
 		}
 	}
 
-	return wb.Flush()
+	return wrapDBError(wb.Flush())
 }
 
 // Store stores the value for a given key.
@@ -163,9 +291,24 @@ func (s *Store) Store(ctx context.Context, key []byte, value []byte) error {
 		return errors.New("no value provided")
 	}
 
-	return s.db.Update(func(txn *badger.Txn) error {
+	return wrapDBError(s.db.Update(func(txn *badger.Txn) error {
 		return txn.Set(key, value)
-	})
+	}))
+}
+
+// Delete deletes the value for a given key.
+// It is not an error to delete a key that does not exist.
+func (s *Store) Delete(ctx context.Context, key []byte) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "storage.Delete")
+	defer span.Finish()
+
+	if len(key) == 0 {
+		return errors.New("no key provided")
+	}
+
+	return wrapDBError(s.db.Update(func(txn *badger.Txn) error {
+		return txn.Delete(key)
+	}))
 }
 
 // Close closes the store.
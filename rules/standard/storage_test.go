@@ -15,8 +15,10 @@ package standard_test
 
 import (
 	"context"
+	"errors"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"testing"
 
 	standardrules "github.com/attestantio/dirk/rules/standard"
@@ -271,3 +273,111 @@ func TestFetchAll(t *testing.T) {
 		require.Equal(t, values[i], fetchedValues[keys[i]])
 	}
 }
+
+func TestUpdate(t *testing.T) {
+	ctx := context.Background()
+	store, err := standardrules.NewStore(t.TempDir())
+	require.NoError(t, err)
+	defer store.Close(ctx)
+
+	require.NoError(t, store.Store(ctx, []byte("key1"), []byte("value1")))
+
+	tests := []struct {
+		name     string
+		keys     [][]byte
+		values   [][]byte
+		existing [][]byte
+		err      string
+	}{
+		{
+			name: "Nil",
+			err:  "no keys provided",
+		},
+		{
+			name: "KeyEmpty",
+			keys: [][]byte{[]byte("")},
+			err:  "empty key provided",
+		},
+		{
+			name:     "ValueMissing",
+			keys:     [][]byte{[]byte("key1"), []byte("key2")},
+			values:   [][]byte{[]byte("value2")},
+			existing: [][]byte{[]byte("value1"), nil},
+			err:      "key/value length mismatch",
+		},
+		{
+			name:     "ValueEmpty",
+			keys:     [][]byte{[]byte("key1")},
+			values:   [][]byte{{}},
+			existing: [][]byte{[]byte("value1")},
+			err:      "empty value provided",
+		},
+		{
+			name:     "NoUpdate",
+			keys:     [][]byte{[]byte("key1"), []byte("key2")},
+			existing: [][]byte{[]byte("value1"), nil},
+		},
+		{
+			name:     "Good",
+			keys:     [][]byte{[]byte("key1"), []byte("key2")},
+			values:   [][]byte{[]byte("value1a"), []byte("value2a")},
+			existing: [][]byte{[]byte("value1"), nil},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := store.Update(ctx, test.keys, func(values [][]byte) ([][]byte, error) {
+				require.Equal(t, test.existing, values)
+				return test.values, nil
+			})
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			for i := range test.keys {
+				expected := test.existing[i]
+				if test.values != nil {
+					expected = test.values[i]
+				}
+				value, err := store.Fetch(ctx, test.keys[i])
+				if expected == nil {
+					require.EqualError(t, err, "not found")
+				} else {
+					require.NoError(t, err)
+					require.Equal(t, expected, value)
+				}
+			}
+		})
+	}
+
+	// Errors from the update function are returned, and nothing is stored.
+	err = store.Update(ctx, [][]byte{[]byte("key3")}, func(values [][]byte) ([][]byte, error) {
+		return nil, errors.New("update failed")
+	})
+	require.EqualError(t, err, "update failed")
+	_, err = store.Fetch(ctx, []byte("key3"))
+	require.EqualError(t, err, "not found")
+}
+
+func TestReadOnlyStore(t *testing.T) {
+	ctx := context.Background()
+	base := t.TempDir()
+
+	_, err := standardrules.NewReadOnlyStore(filepath.Join(base, "missing"))
+	require.Error(t, err)
+
+	store, err := standardrules.NewStore(base)
+	require.NoError(t, err)
+	require.NoError(t, store.Store(ctx, []byte("key"), []byte("value")))
+	require.NoError(t, store.Close(ctx))
+
+	store, err = standardrules.NewReadOnlyStore(base)
+	require.NoError(t, err)
+	value, err := store.Fetch(ctx, []byte("key"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("value"), value)
+	require.Error(t, store.Store(ctx, []byte("key"), []byte("value2")))
+	require.NoError(t, store.Close(ctx))
+}
@@ -0,0 +1,157 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/attestantio/dirk/core"
+	"github.com/attestantio/dirk/rules"
+	"github.com/attestantio/dirk/services/checker"
+	mockchecker "github.com/attestantio/dirk/services/checker/mock"
+	memfetcher "github.com/attestantio/dirk/services/fetcher/mem"
+	syncmaplocker "github.com/attestantio/dirk/services/locker/syncmap"
+	"github.com/attestantio/dirk/services/ruler/golang"
+	standardsigner "github.com/attestantio/dirk/services/signer/standard"
+	localunlocker "github.com/attestantio/dirk/services/unlocker/local"
+	"github.com/stretchr/testify/require"
+	e2types "github.com/wealdtech/go-eth2-types/v2"
+	keystorev4 "github.com/wealdtech/go-eth2-wallet-encryptor-keystorev4"
+	hd "github.com/wealdtech/go-eth2-wallet-hd/v2"
+	scratch "github.com/wealdtech/go-eth2-wallet-store-scratch"
+	e2wtypes "github.com/wealdtech/go-eth2-wallet-types/v2"
+)
+
+// writeFailingStore is a store that fails all writes.
+type writeFailingStore struct {
+	Storage
+}
+
+func (s *writeFailingStore) Update(ctx context.Context, keys [][]byte, update func(values [][]byte) ([][]byte, error)) error {
+	values, err := update(make([][]byte, len(keys)))
+	if err != nil {
+		return err
+	}
+	if values != nil {
+		return errors.New("write failed")
+	}
+	return nil
+}
+
+func (s *writeFailingStore) Store(ctx context.Context, key []byte, value []byte) error {
+	return errors.New("write failed")
+}
+
+func (s *writeFailingStore) BatchStore(ctx context.Context, keys [][]byte, values [][]byte) error {
+	return errors.New("write failed")
+}
+
+// writeFailureMonitor counts slashing protection write failures.
+type writeFailureMonitor struct {
+	mu       sync.Mutex
+	failures map[string]int
+}
+
+func (m *writeFailureMonitor) SlashingProtectionWriteFailed(request string) {
+	m.mu.Lock()
+	m.failures[request]++
+	m.mu.Unlock()
+}
+
+func (m *writeFailureMonitor) ValidatorNotAllowed(request string) {}
+
+func (m *writeFailureMonitor) SlashingProtectionDenied(request string, reason string) {}
+
+func (m *writeFailureMonitor) RulesStoreAvailable(available bool) {}
+
+func TestSlashingProtectionWriteFailure(t *testing.T) {
+	ctx := context.Background()
+	require.NoError(t, e2types.InitBLS())
+
+	base, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(base)
+	rulesSvc, err := New(ctx,
+		WithStoragePath(base),
+	)
+	require.NoError(t, err)
+	monitor := &writeFailureMonitor{
+		failures: make(map[string]int),
+	}
+	rulesSvc.monitor = monitor
+	rulesSvc.store = &writeFailingStore{Storage: rulesSvc.store}
+
+	store := scratch.New()
+	wallet, err := hd.CreateWallet(ctx, "Test wallet", []byte("secret"), store, keystorev4.New(), make([]byte, 64))
+	require.NoError(t, err)
+	require.NoError(t, wallet.(e2wtypes.WalletLocker).Unlock(ctx, []byte("secret")))
+	account, err := wallet.(e2wtypes.WalletAccountCreator).CreateAccount(ctx, "Test account", []byte("pass"))
+	require.NoError(t, err)
+	require.NoError(t, wallet.(e2wtypes.WalletLocker).Lock(ctx))
+
+	lockerSvc, err := syncmaplocker.New(ctx)
+	require.NoError(t, err)
+	fetcherSvc, err := memfetcher.New(ctx, memfetcher.WithStores([]e2wtypes.Store{store}))
+	require.NoError(t, err)
+	rulerSvc, err := golang.New(ctx, golang.WithLocker(lockerSvc), golang.WithRules(rulesSvc))
+	require.NoError(t, err)
+	unlockerSvc, err := localunlocker.New(ctx, localunlocker.WithAccountPassphrases([]string{"pass"}))
+	require.NoError(t, err)
+	checkerSvc, err := mockchecker.New()
+	require.NoError(t, err)
+	signerSvc, err := standardsigner.New(ctx,
+		standardsigner.WithChecker(checkerSvc),
+		standardsigner.WithFetcher(fetcherSvc),
+		standardsigner.WithRuler(rulerSvc),
+		standardsigner.WithUnlocker(unlockerSvc),
+	)
+	require.NoError(t, err)
+
+	credentials := &checker.Credentials{Client: "client1"}
+	root := make([]byte, 32)
+
+	res, signature := signerSvc.SignBeaconProposal(ctx, credentials, "Test wallet/Test account", nil, &rules.SignBeaconProposalData{
+		Domain:     e2types.Domain(e2types.DomainBeaconProposer, e2types.ZeroForkVersion, e2types.ZeroGenesisValidatorsRoot),
+		Slot:       1,
+		ParentRoot: root,
+		StateRoot:  root,
+		BodyRoot:   root,
+	})
+	require.Equal(t, core.ResultFailed, res)
+	require.Nil(t, signature)
+	require.Equal(t, 1, monitor.failures["proposal"])
+
+	attestationData := &rules.SignBeaconAttestationData{
+		Domain:          e2types.Domain(e2types.DomainBeaconAttester, e2types.ZeroForkVersion, e2types.ZeroGenesisValidatorsRoot),
+		Slot:            32,
+		BeaconBlockRoot: root,
+		Source:          &rules.Checkpoint{Epoch: 0, Root: root},
+		Target:          &rules.Checkpoint{Epoch: 1, Root: root},
+	}
+	res, signature = signerSvc.SignBeaconAttestation(ctx, credentials, "Test wallet/Test account", nil, attestationData)
+	require.Equal(t, core.ResultFailed, res)
+	require.Nil(t, signature)
+	require.Equal(t, 1, monitor.failures["attestation"])
+
+	results, signatures := signerSvc.SignBeaconAttestations(ctx, credentials, nil, [][]byte{account.PublicKey().Marshal()}, []*rules.SignBeaconAttestationData{attestationData})
+	require.Len(t, results, 1)
+	require.Equal(t, core.ResultFailed, results[0])
+	require.Nil(t, signatures[0])
+	require.Equal(t, 2, monitor.failures["attestation"])
+}
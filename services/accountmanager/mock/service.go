@@ -57,6 +57,18 @@ func (s *Service) Generate(ctx context.Context,
 		nil
 }
 
+// Delete deletes an account.
+func (s *Service) Delete(ctx context.Context,
+	credentials *checker.Credentials,
+	account string,
+	purgeSlashingProtection bool,
+) (
+	core.Result,
+	error,
+) {
+	return core.ResultSucceeded, nil
+}
+
 // Unlock unlocks an account.
 func (s *Service) Unlock(ctx context.Context,
 	credentials *checker.Credentials,
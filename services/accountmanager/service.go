@@ -15,6 +15,7 @@ package accountmanager
 
 import (
 	"context"
+	"time"
 
 	"github.com/attestantio/dirk/core"
 	"github.com/attestantio/dirk/services/checker"
@@ -36,6 +37,16 @@ type Service interface {
 		error,
 	)
 
+	// Delete deletes an account, optionally purging its slashing protection data.
+	Delete(ctx context.Context,
+		credentials *checker.Credentials,
+		account string,
+		purgeSlashingProtection bool,
+	) (
+		core.Result,
+		error,
+	)
+
 	// Unlock unlocks an account.
 	Unlock(ctx context.Context,
 		credentials *checker.Credentials,
@@ -54,3 +65,84 @@ type Service interface {
 		error,
 	)
 }
+
+// AccountStatus is the status of an account.
+type AccountStatus struct {
+	// PubKey is the public key for which status was requested.
+	PubKey []byte
+	// Found is true if the account exists and the client can access it.
+	Found bool
+	// Unlocked is true if the account is unlocked and so able to sign.
+	Unlocked bool
+}
+
+// StatusProvider is the interface for an account manager that can report the status of accounts.
+type StatusProvider interface {
+	// AccountStatuses provides the status of the accounts with the given public keys,
+	// in the same order as the keys.
+	AccountStatuses(ctx context.Context,
+		credentials *checker.Credentials,
+		pubKeys [][]byte,
+	) (
+		core.Result,
+		[]*AccountStatus,
+	)
+}
+
+// UnlockedAccount is an account that is unlocked and so able to sign.
+type UnlockedAccount struct {
+	// Account is the name of the account, in the form "wallet/account".
+	Account string
+	// PubKey is the public key of the account.
+	PubKey []byte
+	// CompositePubKey is the composite public key of a distributed account.
+	CompositePubKey []byte
+	// Relocks is true if the account will be relocked once it has been idle for RelockAfter.
+	Relocks bool
+	// IdleTime is the time since the account was last used.  It is only set if Relocks is true.
+	IdleTime time.Duration
+	// RelockAfter is the idle time after which the account is relocked.  It is only set if Relocks is true.
+	RelockAfter time.Duration
+}
+
+// UnlockedProvider is the interface for an account manager that can list unlocked accounts.
+type UnlockedProvider interface {
+	// UnlockedAccounts provides the accounts that are unlocked, ordered by name.
+	UnlockedAccounts(ctx context.Context,
+		credentials *checker.Credentials,
+	) (
+		core.Result,
+		[]*UnlockedAccount,
+	)
+}
+
+// GeneratedAccount is an account created by bulk generation.
+type GeneratedAccount struct {
+	// Account is the name of the account, in the form "wallet/account".
+	Account string
+	// PubKey is the public key of the account.  For distributed accounts this is the composite public key.
+	PubKey []byte
+	// Participants are the participants of a distributed account.
+	Participants []*core.Endpoint
+}
+
+// BulkGenerator is the interface for an account manager that can generate multiple accounts in a single request.
+type BulkGenerator interface {
+	// GenerateBulk generates count accounts in the given wallet, named with the given prefix followed
+	// by an index starting at startIndex.  Accounts are returned in index order; if generation fails
+	// part-way through, the accounts that were created are still returned.
+	GenerateBulk(ctx context.Context,
+		credentials *checker.Credentials,
+		wallet string,
+		prefix string,
+		startIndex uint32,
+		count uint32,
+		passphrase []byte,
+		signingThreshold uint32,
+		participants uint32,
+	) (
+		core.Result,
+		[]*GeneratedAccount,
+		error,
+	)
+}
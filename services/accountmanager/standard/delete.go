@@ -0,0 +1,97 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/attestantio/dirk/core"
+	"github.com/attestantio/dirk/services/checker"
+	"github.com/attestantio/dirk/services/fetcher"
+	"github.com/attestantio/dirk/services/ruler"
+	"github.com/pkg/errors"
+)
+
+// Delete deletes an account.
+// The account's key material is removed from its store.  Its slashing
+// protection data is retained unless purgeSlashingProtection is set, so that
+// a later re-import of the same key cannot be used to sign slashable data.
+func (s *Service) Delete(ctx context.Context,
+	credentials *checker.Credentials,
+	accountName string,
+	purgeSlashingProtection bool,
+) (
+	core.Result,
+	error,
+) {
+	started := time.Now()
+
+	if credentials == nil {
+		log.Error().Msg("No credentials supplied")
+		return core.ResultFailed, nil
+	}
+
+	log := log.With().
+		Str("request_id", credentials.RequestID).
+		Str("client", credentials.Client).
+		Str("account", accountName).
+		Str("action", "Delete").
+		Logger()
+	log.Trace().Msg("Request received")
+
+	wallet, account, checkRes := s.preCheck(ctx, credentials, accountName, nil, ruler.ActionDeleteAccount)
+	if checkRes != core.ResultSucceeded {
+		s.monitor.AccountManagerCompleted(started, "delete", checkRes)
+		return checkRes, nil
+	}
+
+	remover, isRemover := s.fetcher.(fetcher.AccountRemover)
+	if !isRemover {
+		s.monitor.AccountManagerCompleted(started, "delete", core.ResultFailed)
+		return core.ResultFailed, errors.New("fetcher does not support account removal")
+	}
+	if purgeSlashingProtection && s.slashingProtectionPurger == nil {
+		s.monitor.AccountManagerCompleted(started, "delete", core.ResultFailed)
+		return core.ResultFailed, errors.New("slashing protection cannot be purged")
+	}
+
+	pubKey := account.PublicKey().Marshal()
+	log = log.With().Str("pubkey", fmt.Sprintf("%#x", pubKey)).Logger()
+	log.Warn().Bool("purge_slashing_protection", purgeSlashingProtection).Msg("Deleting account")
+
+	if err := remover.RemoveAccount(ctx, wallet, account); err != nil {
+		log.Error().Err(err).Str("result", "failed").Msg("Failed to delete account")
+		s.monitor.AccountManagerCompleted(started, "delete", core.ResultFailed)
+		return core.ResultFailed, errors.Wrap(err, "failed to delete account")
+	}
+
+	if !purgeSlashingProtection {
+		log.Warn().Str("result", "succeeded").Msg("Account deleted; slashing protection data retained")
+		s.monitor.AccountManagerCompleted(started, "delete", core.ResultSucceeded)
+		return core.ResultSucceeded, nil
+	}
+
+	log.Warn().Msg("Purging slashing protection data; if this key is imported again it can sign slashable data")
+	if err := s.slashingProtectionPurger.PurgeSlashingProtection(ctx, pubKey); err != nil {
+		log.Error().Err(err).Str("result", "failed").Msg("Account deleted but failed to purge slashing protection data")
+		s.monitor.AccountManagerCompleted(started, "delete", core.ResultFailed)
+		return core.ResultFailed, errors.Wrap(err, "failed to purge slashing protection")
+	}
+	log.Warn().Str("result", "succeeded").Msg("Account deleted; slashing protection data purged")
+
+	s.monitor.AccountManagerCompleted(started, "delete", core.ResultSucceeded)
+	return core.ResultSucceeded, nil
+}
@@ -69,12 +69,29 @@ func (s *Service) Generate(ctx context.Context,
 		return core.ResultDenied, nil, nil, errors.New("invalid signing threshold")
 	}
 
+	rulesRes, err := s.checkCreateRules(ctx, credentials, account)
+	if rulesRes != core.ResultSucceeded {
+		s.monitor.AccountManagerCompleted(started, "generate", rulesRes)
+		return rulesRes, nil, nil, err
+	}
+
+	// Generate it.
+	pubKey, endpoints, err := s.process.OnGenerate(ctx, credentials, account, passphrase, signingThreshold, participants)
+	if err != nil {
+		s.monitor.AccountManagerCompleted(started, "generate", core.ResultSucceeded)
+		return core.ResultFailed, nil, nil, errors.Wrap(err, "failed to generate account")
+	}
+
+	s.monitor.AccountManagerCompleted(started, "generate", core.ResultSucceeded)
+	return core.ResultSucceeded, pubKey, endpoints, nil
+}
+
+// checkCreateRules confirms approval to create the given account via rules.
+func (s *Service) checkCreateRules(ctx context.Context, credentials *checker.Credentials, account string) (core.Result, error) {
 	walletName, accountName, err := e2wallet.WalletAndAccountNames(account)
 	if err != nil {
-		s.monitor.AccountManagerCompleted(started, "generate", core.ResultDenied)
-		return core.ResultDenied, nil, nil, errors.Wrap(err, "invalid account name")
+		return core.ResultDenied, errors.Wrap(err, "invalid account name")
 	}
-	// Confirm approval via rules.
 	rulesData := []*ruler.RulesData{
 		{
 			WalletName:  walletName,
@@ -85,20 +102,9 @@ func (s *Service) Generate(ctx context.Context,
 	results := s.ruler.RunRules(ctx, credentials, ruler.ActionCreateAccount, rulesData)
 	switch results[0] {
 	case rules.DENIED:
-		s.monitor.AccountManagerCompleted(started, "generate", core.ResultDenied)
-		return core.ResultDenied, nil, nil, nil
+		return core.ResultDenied, nil
 	case rules.FAILED:
-		s.monitor.AccountManagerCompleted(started, "generate", core.ResultFailed)
-		return core.ResultFailed, nil, nil, errors.New("rules check failed")
-	}
-
-	// Generate it.
-	pubKey, endpoints, err := s.process.OnGenerate(ctx, credentials, account, passphrase, signingThreshold, participants)
-	if err != nil {
-		s.monitor.AccountManagerCompleted(started, "generate", core.ResultSucceeded)
-		return core.ResultFailed, nil, nil, errors.Wrap(err, "failed to generate account")
+		return core.ResultFailed, errors.New("rules check failed")
 	}
-
-	s.monitor.AccountManagerCompleted(started, "generate", core.ResultSucceeded)
-	return core.ResultSucceeded, pubKey, endpoints, nil
+	return core.ResultSucceeded, nil
 }
@@ -0,0 +1,233 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/attestantio/dirk/core"
+	"github.com/attestantio/dirk/services/accountmanager"
+	"github.com/attestantio/dirk/services/checker"
+	"github.com/attestantio/dirk/services/process"
+	"github.com/attestantio/dirk/services/ruler"
+	"github.com/pkg/errors"
+)
+
+// maxBulkGenerate is the maximum number of accounts that can be generated in a single request.
+const maxBulkGenerate = 1000
+
+// bulkGenerationConcurrency is the number of distributed key generations that run at the same time
+// during bulk generation.  Each generation is dominated by network round trips to peers, so running
+// several at once avoids the batch being serialized on latency.
+const bulkGenerationConcurrency = 8
+
+// GenerateBulk generates multiple accounts.
+func (s *Service) GenerateBulk(ctx context.Context,
+	credentials *checker.Credentials,
+	wallet string,
+	prefix string,
+	startIndex uint32,
+	count uint32,
+	passphrase []byte,
+	signingThreshold uint32,
+	participants uint32,
+) (
+	core.Result,
+	[]*accountmanager.GeneratedAccount,
+	error,
+) {
+	started := time.Now()
+
+	if credentials == nil {
+		log.Error().Msg("No credentials supplied")
+		return core.ResultFailed, nil, nil
+	}
+
+	log := log.With().
+		Str("request_id", credentials.RequestID).
+		Str("client", credentials.Client).
+		Str("wallet", wallet).
+		Uint32("count", count).
+		Str("action", "GenerateBulk").
+		Logger()
+	log.Trace().Msg("Request received")
+
+	// Check parameters.
+	if count == 0 {
+		s.monitor.AccountManagerCompleted(started, "generate bulk", core.ResultDenied)
+		return core.ResultDenied, nil, errors.New("no accounts requested")
+	}
+	if count > maxBulkGenerate {
+		s.monitor.AccountManagerCompleted(started, "generate bulk", core.ResultDenied)
+		return core.ResultDenied, nil, fmt.Errorf("too many accounts requested; maximum is %d", maxBulkGenerate)
+	}
+	if uint64(startIndex)+uint64(count) > uint64(^uint32(0))+1 {
+		s.monitor.AccountManagerCompleted(started, "generate bulk", core.ResultDenied)
+		return core.ResultDenied, nil, errors.New("account indices out of range")
+	}
+	if participants == 0 {
+		s.monitor.AccountManagerCompleted(started, "generate bulk", core.ResultDenied)
+		return core.ResultDenied, nil, errors.New("invalid number of participants")
+	}
+	if participants < signingThreshold {
+		s.monitor.AccountManagerCompleted(started, "generate bulk", core.ResultDenied)
+		return core.ResultDenied, nil, errors.New("invalid signing threshold")
+	}
+
+	accounts, paths, err := s.bulkAccounts(wallet, prefix, startIndex, count, participants)
+	if err != nil {
+		s.monitor.AccountManagerCompleted(started, "generate bulk", core.ResultDenied)
+		return core.ResultDenied, nil, err
+	}
+
+	// Every account must be approved before any are generated, so that a denial
+	// part-way through does not leave a partial batch.
+	for i := range accounts {
+		checkRes := s.checkAccess(ctx, credentials, accounts[i], ruler.ActionCreateAccount)
+		if checkRes != core.ResultSucceeded {
+			log.Debug().Str("account", accounts[i]).Msg("Create refused")
+			s.monitor.AccountManagerCompleted(started, "generate bulk", checkRes)
+			return checkRes, nil, nil
+		}
+		rulesRes, err := s.checkCreateRules(ctx, credentials, accounts[i])
+		if rulesRes != core.ResultSucceeded {
+			log.Debug().Str("account", accounts[i]).Msg("Create refused by rules")
+			s.monitor.AccountManagerCompleted(started, "generate bulk", rulesRes)
+			return rulesRes, nil, err
+		}
+	}
+
+	// Local accounts are generated one at a time, as they share a single wallet.
+	concurrency := 1
+	if participants > 1 {
+		concurrency = bulkGenerationConcurrency
+	}
+	generated, err := s.generateAccounts(ctx, credentials, accounts, paths, passphrase, signingThreshold, participants, concurrency)
+	if err != nil {
+		log.Warn().Err(err).Int("generated", len(generated)).Msg("Bulk generation incomplete")
+		s.monitor.AccountManagerCompleted(started, "generate bulk", core.ResultFailed)
+		return core.ResultFailed, generated, err
+	}
+
+	log.Trace().Msg("Bulk generation complete")
+	s.monitor.AccountManagerCompleted(started, "generate bulk", core.ResultSucceeded)
+	return core.ResultSucceeded, generated, nil
+}
+
+// bulkAccounts returns the names of the accounts to generate in bulk, along with their paths if
+// they are local accounts and a path template is configured.
+func (s *Service) bulkAccounts(wallet string, prefix string, startIndex uint32, count uint32, participants uint32) ([]string, []string, error) {
+	accounts := make([]string, count)
+	var paths []string
+	if s.pathTemplate != nil && participants == 1 {
+		paths = make([]string, count)
+	}
+	names := make(map[string]bool, count)
+	for i := range accounts {
+		index := startIndex + uint32(i)
+		name, err := executeTemplate(s.nameTemplate, wallet, prefix, index)
+		if err != nil {
+			return nil, nil, err
+		}
+		if names[name] {
+			return nil, nil, fmt.Errorf("name template produces duplicate account name %q", name)
+		}
+		names[name] = true
+		accounts[i] = fmt.Sprintf("%s/%s", wallet, name)
+		if paths != nil {
+			paths[i], err = executeTemplate(s.pathTemplate, wallet, prefix, index)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+	return accounts, paths, nil
+}
+
+// generateAccounts generates the given accounts, running up to concurrency generations at a time.
+// If paths are supplied each account is generated at its path.
+// Generation stops at the first failure; the accounts generated are returned in order regardless.
+func (s *Service) generateAccounts(ctx context.Context,
+	credentials *checker.Credentials,
+	accounts []string,
+	paths []string,
+	passphrase []byte,
+	signingThreshold uint32,
+	participants uint32,
+	concurrency int,
+) (
+	[]*accountmanager.GeneratedAccount,
+	error,
+) {
+	results := make([]*accountmanager.GeneratedAccount, len(accounts))
+	var firstErr error
+	var errMu sync.Mutex
+	slots := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i := range accounts {
+		slots <- struct{}{}
+		// Generations already in flight are left to complete rather than cancelled, as
+		// cancelling a generation part-way through could leave peers inconsistent.
+		errMu.Lock()
+		failed := firstErr != nil
+		errMu.Unlock()
+		if failed {
+			break
+		}
+		if err := ctx.Err(); err != nil {
+			errMu.Lock()
+			firstErr = err
+			errMu.Unlock()
+			break
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			var pubKey []byte
+			var endpoints []*core.Endpoint
+			var err error
+			if paths != nil {
+				pubKey, err = s.process.(process.PathedGenerator).OnGeneratePathed(ctx, credentials, accounts[i], passphrase, paths[i])
+			} else {
+				pubKey, endpoints, err = s.process.OnGenerate(ctx, credentials, accounts[i], passphrase, signingThreshold, participants)
+			}
+			if err != nil {
+				errMu.Lock()
+				if firstErr == nil {
+					firstErr = errors.Wrapf(err, "failed to generate account %s", accounts[i])
+				}
+				errMu.Unlock()
+				return
+			}
+			results[i] = &accountmanager.GeneratedAccount{
+				Account:      accounts[i],
+				PubKey:       pubKey,
+				Participants: endpoints,
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	generated := make([]*accountmanager.GeneratedAccount, 0, len(accounts))
+	for _, result := range results {
+		if result != nil {
+			generated = append(generated, result)
+		}
+	}
+	return generated, firstErr
+}
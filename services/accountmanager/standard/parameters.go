@@ -14,6 +14,7 @@
 package standard
 
 import (
+	"github.com/attestantio/dirk/rules"
 	"github.com/attestantio/dirk/services/checker"
 	"github.com/attestantio/dirk/services/fetcher"
 	"github.com/attestantio/dirk/services/metrics"
@@ -32,6 +33,10 @@ type parameters struct {
 	ruler    ruler.Service
 	unlocker unlocker.Service
 	process  process.Service
+	// slashingProtectionPurger is optional.
+	slashingProtectionPurger rules.SlashingProtectionPurger
+	nameTemplate             string
+	pathTemplate             string
 }
 
 // Parameter is the interface for service parameters.
@@ -94,6 +99,29 @@ func WithFetcher(fetcher fetcher.Service) Parameter {
 	})
 }
 
+// WithSlashingProtectionPurger sets the slashing protection purger for this module.
+// If not supplied, requests to purge slashing protection when deleting accounts will fail.
+func WithSlashingProtectionPurger(purger rules.SlashingProtectionPurger) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.slashingProtectionPurger = purger
+	})
+}
+
+// WithGenerationNameTemplate sets the template for the names of accounts generated in bulk.
+func WithGenerationNameTemplate(nameTemplate string) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.nameTemplate = nameTemplate
+	})
+}
+
+// WithGenerationPathTemplate sets the template for the paths of accounts generated in bulk in
+// hierarchical deterministic wallets.
+func WithGenerationPathTemplate(pathTemplate string) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.pathTemplate = pathTemplate
+	})
+}
+
 // parseAndCheckParameters parses and checks parameters to ensure that mandatory parameters are present and correct.
 func parseAndCheckParameters(params ...Parameter) (*parameters, error) {
 	parameters := parameters{
@@ -124,6 +152,11 @@ func parseAndCheckParameters(params ...Parameter) (*parameters, error) {
 	if parameters.fetcher == nil {
 		return nil, errors.New("no fetcher specified")
 	}
+	if parameters.pathTemplate != "" {
+		if _, isPathedGenerator := parameters.process.(process.PathedGenerator); !isPathedGenerator {
+			return nil, errors.New("process does not support generating accounts at a path")
+		}
+	}
 
 	return &parameters, nil
 }
@@ -15,7 +15,9 @@ package standard
 
 import (
 	context "context"
+	"text/template"
 
+	"github.com/attestantio/dirk/rules"
 	"github.com/attestantio/dirk/services/checker"
 	"github.com/attestantio/dirk/services/fetcher"
 	"github.com/attestantio/dirk/services/metrics"
@@ -35,6 +37,12 @@ type Service struct {
 	ruler    ruler.Service
 	unlocker unlocker.Service
 	process  process.Service
+
+	slashingProtectionPurger rules.SlashingProtectionPurger
+
+	nameTemplate *template.Template
+	// pathTemplate is nil if accounts are not generated at templated paths.
+	pathTemplate *template.Template
 }
 
 // module-wide log.
@@ -53,6 +61,22 @@ func New(ctx context.Context, params ...Parameter) (*Service, error) {
 		log = log.Level(parameters.logLevel)
 	}
 
+	nameTemplate := parameters.nameTemplate
+	if nameTemplate == "" {
+		nameTemplate = defaultNameTemplate
+	}
+	nameTmpl, err := parseNameTemplate(nameTemplate)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid generation name template")
+	}
+	var pathTmpl *template.Template
+	if parameters.pathTemplate != "" {
+		pathTmpl, err = parsePathTemplate(parameters.pathTemplate)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid generation path template")
+		}
+	}
+
 	return &Service{
 		monitor:  parameters.monitor,
 		unlocker: parameters.unlocker,
@@ -60,5 +84,10 @@ func New(ctx context.Context, params ...Parameter) (*Service, error) {
 		fetcher:  parameters.fetcher,
 		ruler:    parameters.ruler,
 		process:  parameters.process,
+
+		slashingProtectionPurger: parameters.slashingProtectionPurger,
+
+		nameTemplate: nameTmpl,
+		pathTemplate: pathTmpl,
 	}, nil
 }
@@ -0,0 +1,88 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard
+
+import (
+	context "context"
+	"fmt"
+	"time"
+
+	"github.com/attestantio/dirk/core"
+	"github.com/attestantio/dirk/services/accountmanager"
+	"github.com/attestantio/dirk/services/checker"
+	"github.com/attestantio/dirk/services/ruler"
+	e2wtypes "github.com/wealdtech/go-eth2-wallet-types/v2"
+)
+
+// AccountStatuses provides the status of the accounts with the given public keys.
+// Accounts that the client cannot access are reported as not found, so that
+// the existence of accounts is not revealed to unauthorised clients.
+func (s *Service) AccountStatuses(ctx context.Context,
+	credentials *checker.Credentials,
+	pubKeys [][]byte,
+) (
+	core.Result,
+	[]*accountmanager.AccountStatus,
+) {
+	started := time.Now()
+
+	if credentials == nil {
+		log.Error().Msg("No credentials supplied")
+		return core.ResultFailed, nil
+	}
+
+	log := log.With().
+		Str("request_id", credentials.RequestID).
+		Str("client", credentials.Client).
+		Int("keys", len(pubKeys)).
+		Str("action", "Status").
+		Logger()
+	log.Trace().Msg("Request received")
+
+	statuses := make([]*accountmanager.AccountStatus, len(pubKeys))
+	for i := range pubKeys {
+		statuses[i] = &accountmanager.AccountStatus{
+			PubKey: pubKeys[i],
+		}
+		// A missing account is expected here, so is not logged at warning level.
+		wallet, account, err := s.fetcher.FetchAccountByKey(ctx, pubKeys[i])
+		if err != nil {
+			log.Trace().Str("pubkey", fmt.Sprintf("%#x", pubKeys[i])).Err(err).Msg("Account not found")
+			continue
+		}
+		accountName := fmt.Sprintf("%s/%s", wallet.Name(), account.Name())
+		if s.checkAccess(ctx, credentials, accountName, ruler.ActionAccessAccount) != core.ResultSucceeded {
+			log.Trace().Str("account", accountName).Msg("Access refused")
+			continue
+		}
+		statuses[i].Found = true
+
+		locker, isLocker := account.(e2wtypes.AccountLocker)
+		if !isLocker {
+			// Accounts that cannot be locked are always able to sign.
+			statuses[i].Unlocked = true
+			continue
+		}
+		unlocked, err := locker.IsUnlocked(ctx)
+		if err != nil {
+			log.Warn().Str("account", accountName).Err(err).Msg("Failed to establish if account is unlocked")
+			continue
+		}
+		statuses[i].Unlocked = unlocked
+	}
+
+	log.Trace().Str("result", "succeeded").Msg("Success")
+	s.monitor.AccountManagerCompleted(started, "status", core.ResultSucceeded)
+	return core.ResultSucceeded, statuses
+}
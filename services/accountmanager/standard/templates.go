@@ -0,0 +1,130 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+)
+
+// defaultNameTemplate is the template used to name accounts generated in bulk if none is configured.
+const defaultNameTemplate = "{{.Prefix}}{{.Index}}"
+
+// templateSampleIndices are the indices used to check that a template produces a distinct
+// result for each account.
+var templateSampleIndices = []uint32{0, 1, 2, 9, 10, 11, 99, 100, 101, 1000, 1001, ^uint32(0) - 1, ^uint32(0)}
+
+// templateData is the data available to generation templates.
+type templateData struct {
+	// Wallet is the name of the wallet in which the account is generated.
+	Wallet string
+	// Prefix is the name prefix supplied with the request.
+	Prefix string
+	// Index is the index of the account.
+	Index uint32
+}
+
+// parseNameTemplate parses and validates a template for account names.
+func parseNameTemplate(input string) (*template.Template, error) {
+	tmpl, err := template.New("name").Option("missingkey=error").Parse(input)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid template")
+	}
+	names, err := sampleTemplate(tmpl)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		if name == "" {
+			return nil, errors.New("template produces empty names")
+		}
+		if strings.Contains(name, "/") {
+			return nil, errors.New("template produces names containing /")
+		}
+		if strings.HasPrefix(name, "_") {
+			return nil, errors.New("template produces names starting with _")
+		}
+	}
+	return tmpl, nil
+}
+
+// parsePathTemplate parses and validates a template for hierarchical deterministic paths.
+func parsePathTemplate(input string) (*template.Template, error) {
+	tmpl, err := template.New("path").Option("missingkey=error").Parse(input)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid template")
+	}
+	paths, err := sampleTemplate(tmpl)
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range paths {
+		if err := validatePath(path); err != nil {
+			return nil, errors.Wrapf(err, "template produces invalid path %q", path)
+		}
+	}
+	return tmpl, nil
+}
+
+// sampleTemplate executes a template for a range of indices, and ensures that it produces a
+// different result for each of them.  Without this check a template that does not depend on
+// the index, or that uses only part of it, would give multiple accounts the same name or path.
+func sampleTemplate(tmpl *template.Template) ([]string, error) {
+	results := make([]string, 0, len(templateSampleIndices))
+	seen := make(map[string]uint32, len(templateSampleIndices))
+	for _, index := range templateSampleIndices {
+		result, err := executeTemplate(tmpl, "Wallet", "Prefix", index)
+		if err != nil {
+			return nil, err
+		}
+		if prior, exists := seen[result]; exists {
+			return nil, fmt.Errorf("template is ambiguous: indices %d and %d both produce %q", prior, index, result)
+		}
+		seen[result] = index
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// executeTemplate executes a template for the given account.
+func executeTemplate(tmpl *template.Template, wallet string, prefix string, index uint32) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, &templateData{
+		Wallet: wallet,
+		Prefix: prefix,
+		Index:  index,
+	}); err != nil {
+		return "", errors.Wrap(err, "failed to execute template")
+	}
+	return buf.String(), nil
+}
+
+// validatePath ensures that a path is a valid EIP-2334 path.
+func validatePath(path string) error {
+	components := strings.Split(path, "/")
+	if len(components) < 3 || components[0] != "m" || components[1] != "12381" {
+		return errors.New("path must start with m/12381/")
+	}
+	for _, component := range components[2:] {
+		if _, err := strconv.ParseUint(component, 10, 32); err != nil {
+			return fmt.Errorf("invalid component %q", component)
+		}
+	}
+	return nil
+}
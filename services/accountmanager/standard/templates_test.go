@@ -0,0 +1,202 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard_test
+
+import (
+	context "context"
+	"os"
+	"testing"
+
+	"github.com/attestantio/dirk/core"
+	mockrules "github.com/attestantio/dirk/rules/mock"
+	standardaccountmanager "github.com/attestantio/dirk/services/accountmanager/standard"
+	"github.com/attestantio/dirk/services/checker"
+	mockchecker "github.com/attestantio/dirk/services/checker/mock"
+	memfetcher "github.com/attestantio/dirk/services/fetcher/mem"
+	syncmaplocker "github.com/attestantio/dirk/services/locker/syncmap"
+	staticpeers "github.com/attestantio/dirk/services/peers/static"
+	mockprocess "github.com/attestantio/dirk/services/process/mock"
+	standardprocess "github.com/attestantio/dirk/services/process/standard"
+	"github.com/attestantio/dirk/services/ruler/golang"
+	sendermock "github.com/attestantio/dirk/services/sender/mock"
+	localunlocker "github.com/attestantio/dirk/services/unlocker/local"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+	bip39 "github.com/tyler-smith/go-bip39"
+	e2types "github.com/wealdtech/go-eth2-types/v2"
+	util "github.com/wealdtech/go-eth2-util"
+	keystorev4 "github.com/wealdtech/go-eth2-wallet-encryptor-keystorev4"
+	scratch "github.com/wealdtech/go-eth2-wallet-store-scratch"
+	e2wtypes "github.com/wealdtech/go-eth2-wallet-types/v2"
+)
+
+const testMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon art"
+
+func TestMain(m *testing.M) {
+	if err := e2types.InitBLS(); err != nil {
+		os.Exit(1)
+	}
+	os.Exit(m.Run())
+}
+
+func TestGenerationTemplates(t *testing.T) {
+	ctx := context.Background()
+
+	stores := []e2wtypes.Store{scratch.New()}
+	peers, err := staticpeers.New(ctx,
+		staticpeers.WithPeers(map[uint64]string{1: "signer-test01:8881"}),
+	)
+	require.NoError(t, err)
+	checkerSvc, err := mockchecker.New()
+	require.NoError(t, err)
+	unlockerSvc, err := localunlocker.New(ctx)
+	require.NoError(t, err)
+	fetcherSvc, err := memfetcher.New(ctx,
+		memfetcher.WithStores(stores),
+		memfetcher.WithEncryptor(keystorev4.New()),
+	)
+	require.NoError(t, err)
+	locker, err := syncmaplocker.New(ctx)
+	require.NoError(t, err)
+	rulerSvc, err := golang.New(ctx,
+		golang.WithLocker(locker),
+		golang.WithRules(mockrules.New()),
+	)
+	require.NoError(t, err)
+	processSvc, err := standardprocess.New(ctx,
+		standardprocess.WithChecker(checkerSvc),
+		standardprocess.WithID(1),
+		standardprocess.WithPeers(peers),
+		standardprocess.WithSender(sendermock.New(1)),
+		standardprocess.WithFetcher(fetcherSvc),
+		standardprocess.WithStores(stores),
+		standardprocess.WithUnlocker(unlockerSvc),
+		standardprocess.WithGenerationWalletType("hd"),
+		standardprocess.WithGenerationSeed([]byte(testMnemonic)),
+		standardprocess.WithGenerationWalletPassphrase([]byte("wallet secret")),
+	)
+	require.NoError(t, err)
+
+	params := []standardaccountmanager.Parameter{
+		standardaccountmanager.WithLogLevel(zerolog.Disabled),
+		standardaccountmanager.WithUnlocker(unlockerSvc),
+		standardaccountmanager.WithChecker(checkerSvc),
+		standardaccountmanager.WithFetcher(fetcherSvc),
+		standardaccountmanager.WithRuler(rulerSvc),
+		standardaccountmanager.WithProcess(processSvc),
+	}
+
+	tests := []struct {
+		name         string
+		nameTemplate string
+		pathTemplate string
+		err          string
+	}{
+		{
+			name:         "NameTemplateInvalid",
+			nameTemplate: "{{.Index",
+			err:          `invalid generation name template: invalid template: template: name:1: unclosed action`,
+		},
+		{
+			name:         "NameTemplateUnknownField",
+			nameTemplate: "{{.Account}}",
+			err:          `invalid generation name template: failed to execute template: template: name:1:2: executing "name" at <.Account>: can't evaluate field Account in type *standard.templateData`,
+		},
+		{
+			name:         "NameTemplateNoIndex",
+			nameTemplate: "{{.Wallet}} validator",
+			err:          `invalid generation name template: template is ambiguous: indices 0 and 1 both produce "Wallet validator"`,
+		},
+		{
+			name:         "NameTemplateSlash",
+			nameTemplate: "validators/{{.Index}}",
+			err:          "invalid generation name template: template produces names containing /",
+		},
+		{
+			name:         "NameTemplateUnderscore",
+			nameTemplate: "_{{.Index}}",
+			err:          "invalid generation name template: template produces names starting with _",
+		},
+		{
+			name:         "PathTemplateNoIndex",
+			pathTemplate: "m/12381/3600/0/0/0",
+			err:          `invalid generation path template: template is ambiguous: indices 0 and 1 both produce "m/12381/3600/0/0/0"`,
+		},
+		{
+			name:         "PathTemplateBadPurpose",
+			pathTemplate: "m/44/{{.Index}}",
+			err:          `invalid generation path template: template produces invalid path "m/44/0": path must start with m/12381/`,
+		},
+		{
+			name:         "PathTemplateBadComponent",
+			pathTemplate: "m/12381/3600/{{.Index}}/{{.Wallet}}",
+			err:          `invalid generation path template: template produces invalid path "m/12381/3600/0/Wallet": invalid component "Wallet"`,
+		},
+		{
+			name:         "Good",
+			nameTemplate: "{{.Wallet}}-{{.Prefix}}{{.Index}}",
+			pathTemplate: "m/12381/3600/{{.Index}}/0/0",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := standardaccountmanager.New(ctx, append(params,
+				standardaccountmanager.WithGenerationNameTemplate(test.nameTemplate),
+				standardaccountmanager.WithGenerationPathTemplate(test.pathTemplate),
+			)...)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+
+	// A path template requires a process that can generate at a path.
+	mockProcessSvc, err := mockprocess.New()
+	require.NoError(t, err)
+	_, err = standardaccountmanager.New(ctx, append(params,
+		standardaccountmanager.WithProcess(mockProcessSvc),
+		standardaccountmanager.WithGenerationPathTemplate("m/12381/3600/{{.Index}}/0/0"),
+	)...)
+	require.EqualError(t, err, "problem with parameters: process does not support generating accounts at a path")
+
+	// Accounts generated in bulk follow the templates.
+	accountManager, err := standardaccountmanager.New(ctx, append(params,
+		standardaccountmanager.WithGenerationNameTemplate("{{.Wallet}}-{{.Prefix}}{{.Index}}"),
+		standardaccountmanager.WithGenerationPathTemplate("m/12381/3600/{{.Index}}/0/0"),
+	)...)
+	require.NoError(t, err)
+	credentials := &checker.Credentials{Client: "client1"}
+	res, generated, err := accountManager.GenerateBulk(ctx, credentials, "HD wallet", "validator ", 10, 3, []byte("secret"), 1, 1)
+	require.NoError(t, err)
+	require.Equal(t, core.ResultSucceeded, res)
+	require.Len(t, generated, 3)
+	seed := bip39.NewSeed(testMnemonic, "")
+	accounts := []string{"HD wallet/HD wallet-validator 10", "HD wallet/HD wallet-validator 11", "HD wallet/HD wallet-validator 12"}
+	paths := []string{"m/12381/3600/10/0/0", "m/12381/3600/11/0/0", "m/12381/3600/12/0/0"}
+	for i := range generated {
+		require.Equal(t, accounts[i], generated[i].Account)
+		key, err := util.PrivateKeyFromSeedAndPath(seed, paths[i])
+		require.NoError(t, err)
+		require.Equal(t, key.PublicKey().Marshal(), generated[i].PubKey)
+	}
+
+	// Paths already in use are refused.
+	res, generated, err = accountManager.GenerateBulk(ctx, credentials, "HD wallet", "other ", 12, 2, []byte("secret"), 1, 1)
+	require.EqualError(t, err, "failed to generate account HD wallet/HD wallet-other 12: failed account generation")
+	require.Equal(t, core.ResultFailed, res)
+	require.Empty(t, generated)
+}
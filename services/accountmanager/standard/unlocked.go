@@ -0,0 +1,104 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard
+
+import (
+	context "context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/attestantio/dirk/core"
+	"github.com/attestantio/dirk/services/accountmanager"
+	"github.com/attestantio/dirk/services/checker"
+	"github.com/attestantio/dirk/services/fetcher"
+	"github.com/attestantio/dirk/services/ruler"
+	"github.com/attestantio/dirk/services/unlocker"
+	e2wtypes "github.com/wealdtech/go-eth2-wallet-types/v2"
+)
+
+// UnlockedAccounts provides the accounts that are unlocked, ordered by name.
+// Accounts that the client cannot access are not included, so that the
+// existence of accounts is not revealed to unauthorised clients.
+func (s *Service) UnlockedAccounts(ctx context.Context,
+	credentials *checker.Credentials,
+) (
+	core.Result,
+	[]*accountmanager.UnlockedAccount,
+) {
+	started := time.Now()
+
+	if credentials == nil {
+		log.Error().Msg("No credentials supplied")
+		return core.ResultFailed, nil
+	}
+
+	log := log.With().
+		Str("request_id", credentials.RequestID).
+		Str("client", credentials.Client).
+		Str("action", "ListUnlocked").
+		Logger()
+	log.Trace().Msg("Request received")
+
+	enumerator, isEnumerator := s.fetcher.(fetcher.WalletEnumerator)
+	if !isEnumerator {
+		log.Warn().Msg("Fetcher cannot enumerate wallets")
+		s.monitor.AccountManagerCompleted(started, "list unlocked", core.ResultFailed)
+		return core.ResultFailed, nil
+	}
+
+	relocker, isRelocker := s.unlocker.(unlocker.IdleRelocker)
+	res := make([]*accountmanager.UnlockedAccount, 0)
+	for _, wallet := range enumerator.FetchWallets(ctx) {
+		// The fetcher's accounts are used, as they hold the lock state.
+		accounts, err := s.fetcher.FetchAccounts(ctx, wallet.Name())
+		if err != nil {
+			log.Trace().Str("wallet", wallet.Name()).Err(err).Msg("No accounts for wallet")
+			continue
+		}
+		for _, account := range accounts {
+			accountName := fmt.Sprintf("%s/%s", wallet.Name(), account.Name())
+			if s.checkAccess(ctx, credentials, accountName, ruler.ActionAccessAccount) != core.ResultSucceeded {
+				continue
+			}
+			locker, isLocker := account.(e2wtypes.AccountLocker)
+			if isLocker {
+				unlocked, err := locker.IsUnlocked(ctx)
+				if err != nil {
+					log.Warn().Str("account", accountName).Err(err).Msg("Failed to establish if account is unlocked")
+					continue
+				}
+				if !unlocked {
+					continue
+				}
+			}
+			unlockedAccount := &accountmanager.UnlockedAccount{
+				Account: accountName,
+				PubKey:  account.PublicKey().Marshal(),
+			}
+			if provider, isProvider := account.(e2wtypes.AccountCompositePublicKeyProvider); isProvider {
+				unlockedAccount.CompositePubKey = provider.CompositePublicKey().Marshal()
+			}
+			if isRelocker {
+				unlockedAccount.IdleTime, unlockedAccount.RelockAfter, unlockedAccount.Relocks = relocker.IdleTime(ctx, wallet, account)
+			}
+			res = append(res, unlockedAccount)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Account < res[j].Account })
+
+	log.Trace().Int("accounts", len(res)).Str("result", "succeeded").Msg("Success")
+	s.monitor.AccountManagerCompleted(started, "list unlocked", core.ResultSucceeded)
+	return core.ResultSucceeded, res
+}
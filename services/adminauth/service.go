@@ -0,0 +1,30 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adminauth
+
+import (
+	"context"
+	"time"
+)
+
+// Service is the interface for authorising administrative operations by
+// challenge-response.
+type Service interface {
+	// Challenge issues a single-use nonce, returning the nonce and the time at which it expires.
+	Challenge(ctx context.Context) ([]byte, time.Time, error)
+
+	// Verify verifies the signature of a nonce for an administrative method.  The nonce is
+	// consumed regardless of the result, so cannot be used again.
+	Verify(ctx context.Context, method string, nonce []byte, signature []byte) error
+}
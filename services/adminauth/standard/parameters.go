@@ -0,0 +1,107 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+type parameters struct {
+	logLevel         zerolog.Level
+	publicKeys       []string
+	nonceLifetime    time.Duration
+	maxOutstanding   int
+	parsedPublicKeys []ed25519.PublicKey
+}
+
+// Parameter is the interface for service parameters.
+type Parameter interface {
+	apply(*parameters)
+}
+
+type parameterFunc func(*parameters)
+
+func (f parameterFunc) apply(p *parameters) {
+	f(p)
+}
+
+// WithLogLevel sets the log level for the module.
+func WithLogLevel(logLevel zerolog.Level) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.logLevel = logLevel
+	})
+}
+
+// WithPublicKeys sets the hex-encoded Ed25519 public keys that can sign challenges.
+func WithPublicKeys(publicKeys []string) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.publicKeys = publicKeys
+	})
+}
+
+// WithNonceLifetime sets the time for which an issued nonce remains valid.
+func WithNonceLifetime(lifetime time.Duration) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.nonceLifetime = lifetime
+	})
+}
+
+// WithMaxOutstanding sets the maximum number of unused nonces that can be outstanding.
+func WithMaxOutstanding(maxOutstanding int) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.maxOutstanding = maxOutstanding
+	})
+}
+
+// parseAndCheckParameters parses and checks parameters to ensure that mandatory parameters are present and correct.
+func parseAndCheckParameters(params ...Parameter) (*parameters, error) {
+	parameters := parameters{
+		logLevel:       zerolog.GlobalLevel(),
+		nonceLifetime:  time.Minute,
+		maxOutstanding: 1024,
+	}
+	for _, p := range params {
+		if params != nil {
+			p.apply(&parameters)
+		}
+	}
+
+	if len(parameters.publicKeys) == 0 {
+		return nil, errors.New("no public keys specified")
+	}
+	for _, publicKey := range parameters.publicKeys {
+		key, err := hex.DecodeString(strings.TrimPrefix(publicKey, "0x"))
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid public key %s", publicKey)
+		}
+		if len(key) != ed25519.PublicKeySize {
+			return nil, errors.Errorf("public key %s must be %d bytes", publicKey, ed25519.PublicKeySize)
+		}
+		parameters.parsedPublicKeys = append(parameters.parsedPublicKeys, ed25519.PublicKey(key))
+	}
+	if parameters.nonceLifetime <= 0 {
+		return nil, errors.New("nonce lifetime must be greater than 0")
+	}
+	if parameters.maxOutstanding <= 0 {
+		return nil, errors.New("maximum outstanding nonces must be greater than 0")
+	}
+
+	return &parameters, nil
+}
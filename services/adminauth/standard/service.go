@@ -0,0 +1,137 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	zerologger "github.com/rs/zerolog/log"
+)
+
+// nonceLength is the length of issued nonces, in bytes.
+const nonceLength = 32
+
+// Service is an administrative authorisation service that verifies Ed25519
+// signatures over single-use, time-bounded nonces.
+type Service struct {
+	publicKeys     []ed25519.PublicKey
+	nonceLifetime  time.Duration
+	maxOutstanding int
+
+	noncesMu sync.Mutex
+	nonces   map[[nonceLength]byte]time.Time
+}
+
+// module-wide log.
+var log zerolog.Logger
+
+// New creates a new administrative authorisation service.
+func New(ctx context.Context, params ...Parameter) (*Service, error) {
+	parameters, err := parseAndCheckParameters(params...)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem with parameters")
+	}
+
+	// Set logging.
+	log = zerologger.With().Str("service", "adminauth").Str("impl", "standard").Logger()
+	if parameters.logLevel != log.GetLevel() {
+		log = log.Level(parameters.logLevel)
+	}
+
+	s := &Service{
+		publicKeys:     parameters.parsedPublicKeys,
+		nonceLifetime:  parameters.nonceLifetime,
+		maxOutstanding: parameters.maxOutstanding,
+		nonces:         make(map[[nonceLength]byte]time.Time),
+	}
+
+	return s, nil
+}
+
+// Challenge issues a single-use nonce, returning the nonce and the time at which it expires.
+func (s *Service) Challenge(ctx context.Context) ([]byte, time.Time, error) {
+	var nonce [nonceLength]byte
+	if _, err := rand.Read(nonce[:]); err != nil {
+		return nil, time.Time{}, errors.Wrap(err, "failed to generate nonce")
+	}
+
+	now := time.Now()
+	expiry := now.Add(s.nonceLifetime)
+
+	s.noncesMu.Lock()
+	defer s.noncesMu.Unlock()
+	s.pruneExpired(now)
+	if len(s.nonces) >= s.maxOutstanding {
+		log.Warn().Int("outstanding", len(s.nonces)).Msg("Too many outstanding nonces")
+		return nil, time.Time{}, errors.New("too many outstanding challenges")
+	}
+	s.nonces[nonce] = expiry
+
+	return nonce[:], expiry, nil
+}
+
+// Verify verifies the signature of a nonce for an administrative method.  The nonce is
+// consumed regardless of the result, so cannot be used again.
+func (s *Service) Verify(ctx context.Context, method string, nonce []byte, signature []byte) error {
+	if len(nonce) != nonceLength {
+		return errors.New("invalid nonce")
+	}
+	var key [nonceLength]byte
+	copy(key[:], nonce)
+
+	s.noncesMu.Lock()
+	expiry, exists := s.nonces[key]
+	delete(s.nonces, key)
+	s.noncesMu.Unlock()
+
+	if !exists {
+		return errors.New("unknown nonce")
+	}
+	if time.Now().After(expiry) {
+		return errors.New("nonce expired")
+	}
+
+	msg := challengeMessage(nonce, method)
+	for i := range s.publicKeys {
+		if ed25519.Verify(s.publicKeys[i], msg, signature) {
+			return nil
+		}
+	}
+	return errors.New("invalid signature")
+}
+
+// challengeMessage returns the message that is signed to authorise a method.
+// Binding the method to the nonce stops a signature being replayed against
+// a different method.
+func challengeMessage(nonce []byte, method string) []byte {
+	msg := make([]byte, len(nonce)+len(method))
+	copy(msg, nonce)
+	copy(msg[len(nonce):], method)
+	return msg
+}
+
+// pruneExpired removes expired nonces.  It assumes that the nonces lock is held.
+func (s *Service) pruneExpired(now time.Time) {
+	for nonce, expiry := range s.nonces {
+		if now.After(expiry) {
+			delete(s.nonces, nonce)
+		}
+	}
+}
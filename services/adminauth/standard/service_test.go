@@ -0,0 +1,186 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/attestantio/dirk/services/adminauth/standard"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name   string
+		params []standard.Parameter
+		err    string
+	}{
+		{
+			name: "PublicKeysMissing",
+			params: []standard.Parameter{
+				standard.WithLogLevel(zerolog.Disabled),
+			},
+			err: "problem with parameters: no public keys specified",
+		},
+		{
+			name: "PublicKeyInvalid",
+			params: []standard.Parameter{
+				standard.WithLogLevel(zerolog.Disabled),
+				standard.WithPublicKeys([]string{"invalid"}),
+			},
+			err: "problem with parameters: invalid public key invalid: encoding/hex: invalid byte: U+0069 'i'",
+		},
+		{
+			name: "PublicKeyShort",
+			params: []standard.Parameter{
+				standard.WithLogLevel(zerolog.Disabled),
+				standard.WithPublicKeys([]string{"0x0102"}),
+			},
+			err: "problem with parameters: public key 0x0102 must be 32 bytes",
+		},
+		{
+			name: "NonceLifetimeZero",
+			params: []standard.Parameter{
+				standard.WithLogLevel(zerolog.Disabled),
+				standard.WithPublicKeys([]string{"0x" + hex.EncodeToString(make([]byte, 32))}),
+				standard.WithNonceLifetime(0),
+			},
+			err: "problem with parameters: nonce lifetime must be greater than 0",
+		},
+		{
+			name: "MaxOutstandingZero",
+			params: []standard.Parameter{
+				standard.WithLogLevel(zerolog.Disabled),
+				standard.WithPublicKeys([]string{"0x" + hex.EncodeToString(make([]byte, 32))}),
+				standard.WithMaxOutstanding(0),
+			},
+			err: "problem with parameters: maximum outstanding nonces must be greater than 0",
+		},
+		{
+			name: "Good",
+			params: []standard.Parameter{
+				standard.WithLogLevel(zerolog.Disabled),
+				standard.WithPublicKeys([]string{"0x" + hex.EncodeToString(make([]byte, 32))}),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := standard.New(context.Background(), test.params...)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func sign(privKey ed25519.PrivateKey, nonce []byte, method string) []byte {
+	return ed25519.Sign(privKey, append(append([]byte{}, nonce...), []byte(method)...))
+}
+
+func TestVerify(t *testing.T) {
+	ctx := context.Background()
+	pubKey, privKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	_, otherPrivKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	method := "/dirk.v1.AccountAdmin/Delete"
+
+	s, err := standard.New(ctx,
+		standard.WithLogLevel(zerolog.Disabled),
+		standard.WithPublicKeys([]string{hex.EncodeToString(pubKey)}),
+	)
+	require.NoError(t, err)
+
+	// Good.
+	nonce, expiry, err := s.Challenge(ctx)
+	require.NoError(t, err)
+	require.Len(t, nonce, 32)
+	require.True(t, expiry.After(time.Now()))
+	require.NoError(t, s.Verify(ctx, method, nonce, sign(privKey, nonce, method)))
+
+	// Nonce is single-use.
+	require.EqualError(t, s.Verify(ctx, method, nonce, sign(privKey, nonce, method)), "unknown nonce")
+
+	// Unknown nonce.
+	require.EqualError(t, s.Verify(ctx, method, make([]byte, 32), sign(privKey, make([]byte, 32), method)), "unknown nonce")
+
+	// Invalid nonce.
+	require.EqualError(t, s.Verify(ctx, method, []byte{0x01}, sign(privKey, []byte{0x01}, method)), "invalid nonce")
+
+	// Signature for a different method.
+	nonce, _, err = s.Challenge(ctx)
+	require.NoError(t, err)
+	require.EqualError(t, s.Verify(ctx, method, nonce, sign(privKey, nonce, "/dirk.v1.AccountAdmin/Other")), "invalid signature")
+	// Nonce is consumed by a failed verification.
+	require.EqualError(t, s.Verify(ctx, method, nonce, sign(privKey, nonce, method)), "unknown nonce")
+
+	// Signature by an unknown key.
+	nonce, _, err = s.Challenge(ctx)
+	require.NoError(t, err)
+	require.EqualError(t, s.Verify(ctx, method, nonce, sign(otherPrivKey, nonce, method)), "invalid signature")
+}
+
+func TestVerifyExpired(t *testing.T) {
+	ctx := context.Background()
+	pubKey, privKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	method := "/dirk.v1.AccountAdmin/Delete"
+
+	s, err := standard.New(ctx,
+		standard.WithLogLevel(zerolog.Disabled),
+		standard.WithPublicKeys([]string{hex.EncodeToString(pubKey)}),
+		standard.WithNonceLifetime(10*time.Millisecond),
+	)
+	require.NoError(t, err)
+
+	nonce, _, err := s.Challenge(ctx)
+	require.NoError(t, err)
+	time.Sleep(20 * time.Millisecond)
+	require.EqualError(t, s.Verify(ctx, method, nonce, sign(privKey, nonce, method)), "nonce expired")
+}
+
+func TestChallengeMaxOutstanding(t *testing.T) {
+	ctx := context.Background()
+	pubKey, _, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	s, err := standard.New(ctx,
+		standard.WithLogLevel(zerolog.Disabled),
+		standard.WithPublicKeys([]string{hex.EncodeToString(pubKey)}),
+		standard.WithNonceLifetime(50*time.Millisecond),
+		standard.WithMaxOutstanding(2),
+	)
+	require.NoError(t, err)
+
+	_, _, err = s.Challenge(ctx)
+	require.NoError(t, err)
+	_, _, err = s.Challenge(ctx)
+	require.NoError(t, err)
+	_, _, err = s.Challenge(ctx)
+	require.EqualError(t, err, "too many outstanding challenges")
+
+	// Expired nonces no longer count as outstanding.
+	time.Sleep(60 * time.Millisecond)
+	_, _, err = s.Challenge(ctx)
+	require.NoError(t, err)
+}
@@ -0,0 +1,36 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsAdminMethod(t *testing.T) {
+	require.True(t, isAdminMethod("/dirk.v1.AccountAdmin/Delete"))
+	require.False(t, isAdminMethod("/dirk.v1.AccountAdminExtra/Delete"))
+	require.True(t, isAdminMethod("/dirk.v1.PeerAdmin/UpdatePeers"))
+	require.False(t, isAdminMethod("/dirk.v1.AdminAuth/Challenge"))
+	require.False(t, isAdminMethod("/v1.Signer/Sign"))
+}
+
+func TestIsAdminIPMethod(t *testing.T) {
+	require.True(t, isAdminIPMethod("/dirk.v1.AccountAdmin/Delete"))
+	require.True(t, isAdminIPMethod("/dirk.v1.PeerAdmin/UpdatePeers"))
+	require.True(t, isAdminIPMethod("/dirk.v1.AdminAuth/Challenge"))
+	require.False(t, isAdminIPMethod("/dirk.v1.AdminAuthExtra/Challenge"))
+	require.False(t, isAdminIPMethod("/v1.Signer/Sign"))
+}
@@ -0,0 +1,203 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpc
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/binary"
+	"encoding/pem"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ReloadCertificates replaces the certificates used by the server.
+// If the supplied certificates are invalid the existing certificates
+// continue to be used.
+func (s *Service) ReloadCertificates(ctx context.Context, certPEMBlock []byte, keyPEMBlock []byte, caPEMBlock []byte) error {
+	if err := s.setCertificates(certPEMBlock, keyPEMBlock, caPEMBlock); err != nil {
+		log.Warn().Err(err).Msg("Failed to reload certificates; continuing with existing certificates")
+		s.monitor.CertificateReloadCompleted(false)
+		return errors.Wrap(err, "failed to reload certificates")
+	}
+	s.certsMu.RLock()
+	notAfter := s.serverCert.Leaf.NotAfter
+	s.certsMu.RUnlock()
+	log.Info().Time("not_after", notAfter).Msg("Reloaded certificates")
+	s.monitor.CertificateReloadCompleted(true)
+
+	return nil
+}
+
+// setCertificates parses and sets the certificates used by the server.
+// Existing certificates are only replaced if all new certificates are valid.
+func (s *Service) setCertificates(certPEMBlock []byte, keyPEMBlock []byte, caPEMBlock []byte) error {
+	serverCert, err := tls.X509KeyPair(certPEMBlock, keyPEMBlock)
+	if err != nil {
+		return errors.Wrap(err, "failed to load server keypair")
+	}
+	// Parse the leaf now, both to make its expiry available and to avoid parsing it on each handshake.
+	serverCert.Leaf, err = x509.ParseCertificate(serverCert.Certificate[0])
+	if err != nil {
+		return errors.Wrap(err, "failed to parse server certificate")
+	}
+
+	certPool := x509.NewCertPool()
+	if len(caPEMBlock) > 0 {
+		// Read in the certificate authority certificates; these are required to validate client certificates on incoming connections.
+		certPool, err = caCertPool(caPEMBlock)
+		if err != nil {
+			return err
+		}
+	}
+
+	s.certsMu.Lock()
+	s.serverCert = &serverCert
+	s.clientCAs = certPool
+	s.certsDigest = certificatesDigest(certPEMBlock, keyPEMBlock, caPEMBlock)
+	s.certsMu.Unlock()
+
+	s.reportCertificateExpiry("server", serverCert.Leaf.NotAfter)
+	if caNotAfter, exists := earliestExpiry(caPEMBlock); exists {
+		s.reportCertificateExpiry("ca", caNotAfter)
+	}
+
+	return nil
+}
+
+// caCertPool creates a certificate pool from a PEM block containing one or
+// more CA certificates, allowing clients with certificates issued by any of
+// them to connect.  Unlike x509.CertPool.AppendCertsFromPEM, a certificate
+// in the block that cannot be parsed is an error rather than being skipped,
+// so a damaged bundle does not silently lock out a CA's clients.
+func caCertPool(pemBlock []byte) (*x509.CertPool, error) {
+	certPool := x509.NewCertPool()
+	certs := 0
+	for {
+		var block *pem.Block
+		block, pemBlock = pem.Decode(pemBlock)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse CA certificate %d", certs+1)
+		}
+		certPool.AddCert(cert)
+		certs++
+	}
+	if certs == 0 {
+		return nil, errors.New("could not add CA certificate to pool")
+	}
+	log.Trace().Int("ca_certificates", certs).Msg("Loaded CA certificates")
+
+	return certPool, nil
+}
+
+// reportCertificateExpiry reports the expiry of a certificate, warning if it
+// is close to or past expiry.
+func (s *Service) reportCertificateExpiry(certificate string, notAfter time.Time) {
+	s.monitor.CertificateExpiry(certificate, notAfter)
+	remaining := time.Until(notAfter)
+	switch {
+	case remaining <= 0:
+		log.Warn().Str("certificate", certificate).Time("not_after", notAfter).Msg("Certificate has expired")
+	case remaining < s.certExpiryWarn:
+		log.Warn().Str("certificate", certificate).Time("not_after", notAfter).Str("remaining", remaining.Round(time.Second).String()).Msg("Certificate is close to expiry")
+	}
+}
+
+// earliestExpiry returns the earliest expiry of the certificates in a PEM
+// block, or false if the block contains no certificates.
+func earliestExpiry(pemBlock []byte) (time.Time, bool) {
+	var earliest time.Time
+	found := false
+	for {
+		var block *pem.Block
+		block, pemBlock = pem.Decode(pemBlock)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			continue
+		}
+		if !found || cert.NotAfter.Before(earliest) {
+			earliest = cert.NotAfter
+			found = true
+		}
+	}
+	return earliest, found
+}
+
+// CertificatesChanged returns true if the supplied certificates differ from
+// those currently used by the server.
+func (s *Service) CertificatesChanged(certPEMBlock []byte, keyPEMBlock []byte, caPEMBlock []byte) bool {
+	digest := certificatesDigest(certPEMBlock, keyPEMBlock, caPEMBlock)
+
+	s.certsMu.RLock()
+	defer s.certsMu.RUnlock()
+
+	return digest != s.certsDigest
+}
+
+// certificatesDigest provides a digest of a set of certificates, allowing
+// them to be compared without retaining the key material.
+func certificatesDigest(certPEMBlock []byte, keyPEMBlock []byte, caPEMBlock []byte) [32]byte {
+	hash := sha256.New()
+	for _, block := range [][]byte{certPEMBlock, keyPEMBlock, caPEMBlock} {
+		// Length-prefix each block so that boundaries between them are unambiguous.
+		var length [8]byte
+		binary.BigEndian.PutUint64(length[:], uint64(len(block)))
+		hash.Write(length[:])
+		hash.Write(block)
+	}
+	var digest [32]byte
+	copy(digest[:], hash.Sum(nil))
+	return digest
+}
+
+// tlsConfig provides the TLS configuration for an incoming connection, using the
+// client certificate policy of the listener on which it arrived.
+func (s *Service) tlsConfig(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+	clientAuth := ClientAuthRequire
+	if hello != nil {
+		clientAuth = connClientAuth(hello.Conn)
+	}
+
+	s.certsMu.RLock()
+	defer s.certsMu.RUnlock()
+
+	cfg := &tls.Config{
+		ClientAuth:   clientAuth.tlsClientAuth(),
+		Certificates: []tls.Certificate{*s.serverCert},
+		ClientCAs:    s.clientCAs,
+		MinVersion:   s.tlsMinVersion,
+		CipherSuites: s.tlsCiphers,
+		// Configuration returned here does not pass through the GRPC credentials, so set ALPN explicitly.
+		NextProtos: []string{"h2"},
+	}
+	applyClockSkew(cfg, s.clockSkew)
+
+	return cfg, nil
+}
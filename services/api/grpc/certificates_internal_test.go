@@ -0,0 +1,330 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/attestantio/dirk/testing/resources"
+	"github.com/stretchr/testify/require"
+)
+
+type reloadMonitor struct {
+	succeeded int
+	failed    int
+}
+
+func (m *reloadMonitor) CertificateReloadCompleted(succeeded bool) {
+	if succeeded {
+		m.succeeded++
+	} else {
+		m.failed++
+	}
+}
+
+func (m *reloadMonitor) RequestPanicked(rpc string) {}
+
+func (m *reloadMonitor) RequestRateLimited(client string) {}
+
+func (m *reloadMonitor) CertificateExpiry(certificate string, notAfter time.Time) {}
+
+func TestReloadCertificates(t *testing.T) {
+	ctx := context.Background()
+
+	monitor := &reloadMonitor{}
+	s := &Service{
+		monitor: monitor,
+	}
+	require.NoError(t, s.setCertificates(resources.SignerTest01Crt, resources.SignerTest01Key, resources.CACrt))
+	initialConfig, err := s.tlsConfig(nil)
+	require.NoError(t, err)
+
+	// Mismatched key pair should fail and leave the existing certificate in place.
+	require.EqualError(t, s.ReloadCertificates(ctx, resources.SignerTest02Crt, resources.SignerTest01Key, resources.CACrt),
+		"failed to reload certificates: failed to load server keypair: tls: private key does not match public key")
+	require.Equal(t, 0, monitor.succeeded)
+	require.Equal(t, 1, monitor.failed)
+	config, err := s.tlsConfig(nil)
+	require.NoError(t, err)
+	require.Equal(t, initialConfig.Certificates[0].Certificate, config.Certificates[0].Certificate)
+
+	// Invalid CA certificate should fail and leave the existing certificate in place.
+	require.EqualError(t, s.ReloadCertificates(ctx, resources.SignerTest02Crt, resources.SignerTest02Key, []byte("bad")),
+		"failed to reload certificates: could not add CA certificate to pool")
+	require.Equal(t, 0, monitor.succeeded)
+	require.Equal(t, 2, monitor.failed)
+	config, err = s.tlsConfig(nil)
+	require.NoError(t, err)
+	require.Equal(t, initialConfig.Certificates[0].Certificate, config.Certificates[0].Certificate)
+
+	// Valid key pair should succeed and replace the existing certificate.
+	require.NoError(t, s.ReloadCertificates(ctx, resources.SignerTest02Crt, resources.SignerTest02Key, resources.CACrt))
+	require.Equal(t, 1, monitor.succeeded)
+	require.Equal(t, 2, monitor.failed)
+	config, err = s.tlsConfig(nil)
+	require.NoError(t, err)
+	require.NotEqual(t, initialConfig.Certificates[0].Certificate, config.Certificates[0].Certificate)
+}
+
+func TestCertificatesChanged(t *testing.T) {
+	s := &Service{
+		monitor: &reloadMonitor{},
+	}
+	require.NoError(t, s.setCertificates(resources.SignerTest01Crt, resources.SignerTest01Key, resources.CACrt))
+	require.NotNil(t, s.serverCert.Leaf)
+
+	require.False(t, s.CertificatesChanged(resources.SignerTest01Crt, resources.SignerTest01Key, resources.CACrt))
+	require.True(t, s.CertificatesChanged(resources.SignerTest02Crt, resources.SignerTest02Key, resources.CACrt))
+	require.True(t, s.CertificatesChanged(resources.SignerTest01Crt, resources.SignerTest01Key, nil))
+	// Boundaries between blocks are significant.
+	require.True(t, s.CertificatesChanged(append(append([]byte{}, resources.SignerTest01Crt...), resources.SignerTest01Key...), nil, resources.CACrt))
+
+	require.NoError(t, s.setCertificates(resources.SignerTest02Crt, resources.SignerTest02Key, resources.CACrt))
+	require.False(t, s.CertificatesChanged(resources.SignerTest02Crt, resources.SignerTest02Key, resources.CACrt))
+	require.True(t, s.CertificatesChanged(resources.SignerTest01Crt, resources.SignerTest01Key, resources.CACrt))
+}
+
+func TestEarliestExpiry(t *testing.T) {
+	_, exists := earliestExpiry(nil)
+	require.False(t, exists)
+	_, exists = earliestExpiry([]byte("bad"))
+	require.False(t, exists)
+
+	caNotAfter, exists := earliestExpiry(resources.CACrt)
+	require.True(t, exists)
+	serverNotAfter, exists := earliestExpiry(resources.SignerTest01Crt)
+	require.True(t, exists)
+
+	earliest := caNotAfter
+	if serverNotAfter.Before(earliest) {
+		earliest = serverNotAfter
+	}
+	combined := append(append(append([]byte{}, resources.CACrt...), '\n'), resources.SignerTest01Crt...)
+	notAfter, exists := earliestExpiry(combined)
+	require.True(t, exists)
+	require.Equal(t, earliest, notAfter)
+}
+
+// generateCertificate generates a certificate and PEM-encoded certificate and key
+// signed by the given parent, or self-signed if there is no parent.
+func generateCertificate(t *testing.T, name string, key crypto.Signer, parent *x509.Certificate, parentKey crypto.Signer) (*x509.Certificate, []byte, []byte) {
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		DNSNames:     []string{name},
+	}
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+		parent = template
+		parentKey = key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, key.Public(), parentKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	return cert,
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})
+}
+
+func TestCertificateKeyTypes(t *testing.T) {
+	tests := []struct {
+		name     string
+		generate func() (crypto.Signer, error)
+	}{
+		{
+			name: "Ed25519",
+			generate: func() (crypto.Signer, error) {
+				_, key, err := ed25519.GenerateKey(rand.Reader)
+				return key, err
+			},
+		},
+		{
+			name: "ECDSA",
+			generate: func() (crypto.Signer, error) {
+				return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			},
+		},
+		{
+			name: "RSA",
+			generate: func() (crypto.Signer, error) {
+				return rsa.GenerateKey(rand.Reader, 2048)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			caKey, err := test.generate()
+			require.NoError(t, err)
+			caCert, caPEM, _ := generateCertificate(t, "Test CA", caKey, nil, nil)
+			serverKey, err := test.generate()
+			require.NoError(t, err)
+			_, serverCertPEM, serverKeyPEM := generateCertificate(t, "server", serverKey, caCert, caKey)
+			clientKey, err := test.generate()
+			require.NoError(t, err)
+			_, clientCertPEM, clientKeyPEM := generateCertificate(t, "client", clientKey, caCert, caKey)
+
+			s := &Service{
+				monitor: &reloadMonitor{},
+			}
+			require.NoError(t, s.setCertificates(serverCertPEM, serverKeyPEM, caPEM))
+
+			client, err := handshake(t, s, clientCertPEM, clientKeyPEM, caPEM)
+			require.NoError(t, err)
+			require.Equal(t, "client", client)
+		})
+	}
+}
+
+// handshake carries out a TLS handshake with a server using the service's
+// configuration, returning the common name of the client as seen by the server.
+func handshake(t *testing.T, s *Service, clientCertPEM []byte, clientKeyPEM []byte, caPEM []byte) (string, error) {
+	t.Helper()
+
+	// Serve with the same configuration as the API server.
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		MinVersion:         tls.VersionTLS13,
+		GetConfigForClient: s.tlsConfig,
+	})
+	require.NoError(t, err)
+	defer listener.Close()
+	clientName := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			clientName <- ""
+			return
+		}
+		defer conn.Close()
+		tlsConn := conn.(*tls.Conn)
+		if err := tlsConn.Handshake(); err != nil {
+			clientName <- ""
+			return
+		}
+		clientName <- tlsConn.ConnectionState().PeerCertificates[0].Subject.CommonName
+	}()
+
+	clientCert, err := tls.X509KeyPair(clientCertPEM, clientKeyPEM)
+	require.NoError(t, err)
+	roots := x509.NewCertPool()
+	require.True(t, roots.AppendCertsFromPEM(caPEM))
+	conn, err := tls.Dial("tcp", listener.Addr().String(), &tls.Config{
+		Certificates: []tls.Certificate{clientCert},
+		RootCAs:      roots,
+		ServerName:   "server",
+		MinVersion:   tls.VersionTLS13,
+	})
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	// With TLS 1.3 the client completes its handshake before the server has
+	// verified its certificate, so read to obtain any rejection.
+	if _, err := conn.Read(make([]byte, 1)); err != nil && err != io.EOF {
+		return "", err
+	}
+
+	return <-clientName, nil
+}
+
+func TestCACertificateBundle(t *testing.T) {
+	oldCAKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	oldCACert, oldCAPEM, _ := generateCertificate(t, "Old CA", oldCAKey, nil, nil)
+	newCAKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	newCACert, newCAPEM, _ := generateCertificate(t, "New CA", newCAKey, nil, nil)
+	otherCAKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	otherCACert, _, _ := generateCertificate(t, "Other CA", otherCAKey, nil, nil)
+
+	serverKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	_, serverCertPEM, serverKeyPEM := generateCertificate(t, "server", serverKey, oldCACert, oldCAKey)
+
+	bundle := append(append([]byte{}, oldCAPEM...), newCAPEM...)
+
+	s := &Service{
+		monitor: &reloadMonitor{},
+	}
+	require.NoError(t, s.setCertificates(serverCertPEM, serverKeyPEM, bundle))
+
+	tests := []struct {
+		name   string
+		caCert *x509.Certificate
+		caKey  crypto.Signer
+		err    bool
+	}{
+		{
+			name:   "OldCA",
+			caCert: oldCACert,
+			caKey:  oldCAKey,
+		},
+		{
+			name:   "NewCA",
+			caCert: newCACert,
+			caKey:  newCAKey,
+		},
+		{
+			name:   "OtherCA",
+			caCert: otherCACert,
+			caKey:  otherCAKey,
+			err:    true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			clientKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			require.NoError(t, err)
+			_, clientCertPEM, clientKeyPEM := generateCertificate(t, "client", clientKey, test.caCert, test.caKey)
+			client, err := handshake(t, s, clientCertPEM, clientKeyPEM, oldCAPEM)
+			if test.err {
+				// The precise alert depends on whether the client offers a certificate the server does not trust.
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, "client", client)
+			}
+		})
+	}
+
+	// A bundle with a damaged certificate is rejected.
+	damaged := append(append([]byte{}, oldCAPEM...), pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("bad")})...)
+	require.Error(t, s.setCertificates(serverCertPEM, serverKeyPEM, damaged))
+}
@@ -0,0 +1,73 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpc
+
+import (
+	"crypto/tls"
+	"fmt"
+	"strings"
+)
+
+// ClientAuth is the policy for client certificates on a listener.
+type ClientAuth int
+
+const (
+	// ClientAuthRequire requires clients to present a valid certificate.
+	ClientAuthRequire ClientAuth = iota
+	// ClientAuthRequest requests a certificate from clients, and verifies it if supplied.
+	ClientAuthRequest
+	// ClientAuthNone does not request a certificate from clients.
+	ClientAuthNone
+)
+
+var clientAuthStrings = [...]string{
+	"require",
+	"request",
+	"none",
+}
+
+// String returns the string representation of the client auth policy.
+func (c ClientAuth) String() string {
+	if int(c) < 0 || int(c) >= len(clientAuthStrings) {
+		return "unknown"
+	}
+	return clientAuthStrings[c]
+}
+
+// ParseClientAuth parses a client auth policy from its string representation.
+// An empty string is treated as "require".
+func ParseClientAuth(input string) (ClientAuth, error) {
+	switch strings.ToLower(input) {
+	case "", "require":
+		return ClientAuthRequire, nil
+	case "request":
+		return ClientAuthRequest, nil
+	case "none":
+		return ClientAuthNone, nil
+	default:
+		return ClientAuthRequire, fmt.Errorf("unrecognised client auth policy %q", input)
+	}
+}
+
+// tlsClientAuth returns the TLS client authentication type for the policy.
+func (c ClientAuth) tlsClientAuth() tls.ClientAuthType {
+	switch c {
+	case ClientAuthRequest:
+		return tls.VerifyClientCertIfGiven
+	case ClientAuthNone:
+		return tls.NoClientCert
+	default:
+		return tls.RequireAndVerifyClientCert
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpc_test
+
+import (
+	"testing"
+
+	grpcapi "github.com/attestantio/dirk/services/api/grpc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseClientAuth(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		res   grpcapi.ClientAuth
+		err   string
+	}{
+		{
+			name:  "Empty",
+			input: "",
+			res:   grpcapi.ClientAuthRequire,
+		},
+		{
+			name:  "Require",
+			input: "require",
+			res:   grpcapi.ClientAuthRequire,
+		},
+		{
+			name:  "Request",
+			input: "Request",
+			res:   grpcapi.ClientAuthRequest,
+		},
+		{
+			name:  "None",
+			input: "none",
+			res:   grpcapi.ClientAuthNone,
+		},
+		{
+			name:  "Invalid",
+			input: "optional",
+			err:   `unrecognised client auth policy "optional"`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			res, err := grpcapi.ParseClientAuth(test.input)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, test.res, res)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpc
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// applyClockSkew alters a TLS configuration so that client certificates are
+// verified with a tolerance for clock skew.  The standard library verifies
+// certificates against the current time only, so verification is carried out
+// here instead.
+func applyClockSkew(cfg *tls.Config, clockSkew time.Duration) {
+	if clockSkew <= 0 {
+		return
+	}
+	switch cfg.ClientAuth {
+	case tls.RequireAndVerifyClientCert:
+		cfg.ClientAuth = tls.RequireAnyClientCert
+	case tls.VerifyClientCertIfGiven:
+		cfg.ClientAuth = tls.RequestClientCert
+	default:
+		// Certificates are not verified, so there is nothing to do.
+		return
+	}
+	cfg.VerifyPeerCertificate = verifyClientCertificate(cfg.ClientCAs, clockSkew)
+}
+
+// verifyClientCertificate provides a function that verifies a client certificate
+// against the given certificate authorities.  If the certificate is only invalid
+// due to its validity period it is accepted if it would be valid at any time
+// within the clock skew of the current time.
+func verifyClientCertificate(roots *x509.CertPool, clockSkew time.Duration) func([][]byte, [][]*x509.Certificate) error {
+	return func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+		if len(rawCerts) == 0 {
+			// No certificate supplied; the client authentication type decides if this is allowed.
+			return nil
+		}
+		certs := make([]*x509.Certificate, len(rawCerts))
+		for i := range rawCerts {
+			cert, err := x509.ParseCertificate(rawCerts[i])
+			if err != nil {
+				return errors.Wrap(err, "failed to parse client certificate")
+			}
+			certs[i] = cert
+		}
+		intermediates := x509.NewCertPool()
+		for _, cert := range certs[1:] {
+			intermediates.AddCert(cert)
+		}
+		opts := x509.VerifyOptions{
+			Roots:         roots,
+			Intermediates: intermediates,
+			KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		}
+
+		now := time.Now()
+		opts.CurrentTime = now
+		_, err := certs[0].Verify(opts)
+		if err == nil {
+			return nil
+		}
+		if invalidErr, isInvalidErr := err.(x509.CertificateInvalidError); !isInvalidErr || invalidErr.Reason != x509.Expired {
+			return err
+		}
+
+		for _, skewed := range []time.Time{now.Add(-clockSkew), now.Add(clockSkew)} {
+			opts.CurrentTime = skewed
+			if _, skewedErr := certs[0].Verify(opts); skewedErr == nil {
+				log.Info().Str("client", certs[0].Subject.CommonName).Dur("clock_skew", clockSkew).Msg("Client certificate accepted only due to clock skew tolerance")
+				return nil
+			}
+		}
+		return err
+	}
+}
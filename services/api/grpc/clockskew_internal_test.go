@@ -0,0 +1,183 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpc
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyClientCertificate(t *testing.T) {
+	now := time.Now()
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+	caCert, err := x509.ParseCertificate(caDER)
+	require.NoError(t, err)
+	roots := x509.NewCertPool()
+	roots.AddCert(caCert)
+
+	// A second authority that is not trusted.
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	otherDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &otherKey.PublicKey, otherKey)
+	require.NoError(t, err)
+	otherCert, err := x509.ParseCertificate(otherDER)
+	require.NoError(t, err)
+
+	clientCert := func(notBefore time.Time, notAfter time.Time, parent *x509.Certificate, signer *ecdsa.PrivateKey) [][]byte {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(2),
+			Subject:      pkix.Name{CommonName: "client"},
+			NotBefore:    notBefore,
+			NotAfter:     notAfter,
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, signer)
+		require.NoError(t, err)
+		return [][]byte{der}
+	}
+
+	tests := []struct {
+		name      string
+		rawCerts  [][]byte
+		clockSkew time.Duration
+		err       string
+	}{
+		{
+			name: "None",
+		},
+		{
+			name:     "Garbage",
+			rawCerts: [][]byte{[]byte("bad")},
+			err:      "failed to parse client certificate",
+		},
+		{
+			name:      "Valid",
+			rawCerts:  clientCert(now.Add(-time.Hour), now.Add(time.Hour), caCert, caKey),
+			clockSkew: 30 * time.Second,
+		},
+		{
+			name:      "NotYetValidWithinSkew",
+			rawCerts:  clientCert(now.Add(10*time.Second), now.Add(time.Hour), caCert, caKey),
+			clockSkew: 30 * time.Second,
+		},
+		{
+			name:      "ExpiredWithinSkew",
+			rawCerts:  clientCert(now.Add(-time.Hour), now.Add(-10*time.Second), caCert, caKey),
+			clockSkew: 30 * time.Second,
+		},
+		{
+			name:      "NotYetValidBeyondSkew",
+			rawCerts:  clientCert(now.Add(time.Minute), now.Add(time.Hour), caCert, caKey),
+			clockSkew: 30 * time.Second,
+			err:       "x509: certificate has expired or is not yet valid",
+		},
+		{
+			name:      "ExpiredBeyondSkew",
+			rawCerts:  clientCert(now.Add(-time.Hour), now.Add(-time.Minute), caCert, caKey),
+			clockSkew: 30 * time.Second,
+			err:       "x509: certificate has expired or is not yet valid",
+		},
+		{
+			name:      "UnknownAuthority",
+			rawCerts:  clientCert(now.Add(-time.Hour), now.Add(time.Hour), otherCert, otherKey),
+			clockSkew: 30 * time.Second,
+			err:       "x509: certificate signed by unknown authority",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := verifyClientCertificate(roots, test.clockSkew)(test.rawCerts, nil)
+			if test.err != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), test.err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestApplyClockSkew(t *testing.T) {
+	tests := []struct {
+		name       string
+		clientAuth tls.ClientAuthType
+		clockSkew  time.Duration
+		expected   tls.ClientAuthType
+		verifies   bool
+	}{
+		{
+			name:       "NoSkew",
+			clientAuth: tls.RequireAndVerifyClientCert,
+			expected:   tls.RequireAndVerifyClientCert,
+		},
+		{
+			name:       "Require",
+			clientAuth: tls.RequireAndVerifyClientCert,
+			clockSkew:  30 * time.Second,
+			expected:   tls.RequireAnyClientCert,
+			verifies:   true,
+		},
+		{
+			name:       "Request",
+			clientAuth: tls.VerifyClientCertIfGiven,
+			clockSkew:  30 * time.Second,
+			expected:   tls.RequestClientCert,
+			verifies:   true,
+		},
+		{
+			name:       "None",
+			clientAuth: tls.NoClientCert,
+			clockSkew:  30 * time.Second,
+			expected:   tls.NoClientCert,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := &tls.Config{
+				ClientAuth: test.clientAuth,
+				ClientCAs:  x509.NewCertPool(),
+			}
+			applyClockSkew(cfg, test.clockSkew)
+			require.Equal(t, test.expected, cfg.ClientAuth)
+			require.Equal(t, test.verifies, cfg.VerifyPeerCertificate != nil)
+		})
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpc
+
+import (
+	"context"
+	"sync/atomic"
+
+	"google.golang.org/grpc"
+)
+
+// trackUnary tracks in-flight unary requests, so that they can be drained on shutdown.
+// Health checks are not tracked, as they are not requests that need to complete.
+func (s *Service) trackUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if isHealthMethod(info.FullMethod) {
+		return handler(ctx, req)
+	}
+	atomic.AddInt64(&s.inflight, 1)
+	defer atomic.AddInt64(&s.inflight, -1)
+	return handler(ctx, req)
+}
+
+// trackStream tracks in-flight streaming requests, so that they can be drained on shutdown.
+// Health watches are not tracked, as they only end when the server stops.
+func (s *Service) trackStream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if isHealthMethod(info.FullMethod) {
+		return handler(srv, ss)
+	}
+	atomic.AddInt64(&s.inflight, 1)
+	defer atomic.AddInt64(&s.inflight, -1)
+	return handler(srv, ss)
+}
+
+// Stop stops the API server.  The server stops accepting new connections and
+// requests immediately, and waits for in-flight requests to complete until the
+// context is done, at which point any remaining requests are cancelled.  It
+// returns the number of requests that completed and the number that were
+// cancelled.  Stop can be called more than once; the server is only stopped
+// once, and each call waits for it to stop.
+func (s *Service) Stop(ctx context.Context) (int, int) {
+	s.stopOnce.Do(func() {
+		if s.health != nil {
+			s.health.stop()
+		}
+		s.draining = atomic.LoadInt64(&s.inflight)
+		log.Trace().Int64("inflight", s.draining).Msg("Stopping API server")
+		go func() {
+			s.grpcServer.GracefulStop()
+			close(s.stopped)
+		}()
+	})
+
+	select {
+	case <-s.stopped:
+		return int(s.draining), 0
+	case <-ctx.Done():
+	}
+
+	cancelled := atomic.LoadInt64(&s.inflight)
+	s.grpcServer.Stop()
+	<-s.stopped
+	drained := s.draining - cancelled
+	if drained < 0 {
+		drained = 0
+	}
+	return int(drained), int(cancelled)
+}
@@ -0,0 +1,117 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpc
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	pb "github.com/wealdtech/eth2-signer-api/pb/v1"
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// blockingLister is a lister whose requests block until released or cancelled.
+type blockingLister struct {
+	pb.UnimplementedListerServer
+	started chan struct{}
+	release chan struct{}
+}
+
+func (l *blockingLister) ListAccounts(ctx context.Context, req *pb.ListAccountsRequest) (*pb.ListAccountsResponse, error) {
+	l.started <- struct{}{}
+	select {
+	case <-l.release:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return &pb.ListAccountsResponse{}, nil
+}
+
+func startDrainServer(t *testing.T) (*Service, *blockingLister, *grpc.ClientConn) {
+	s := &Service{
+		stopped: make(chan struct{}),
+		health:  newHealthServer(),
+	}
+	s.grpcServer = grpc.NewServer(grpc.UnaryInterceptor(s.trackUnary), grpc.StreamInterceptor(s.trackStream))
+	lister := &blockingLister{
+		started: make(chan struct{}, 10),
+		release: make(chan struct{}),
+	}
+	pb.RegisterListerServer(s.grpcServer, lister)
+	healthpb.RegisterHealthServer(s.grpcServer, s.health)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() {
+		_ = s.grpcServer.Serve(listener)
+	}()
+
+	conn, err := grpc.Dial(listener.Addr().String(), grpc.WithInsecure())
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return s, lister, conn
+}
+
+func TestStopDrains(t *testing.T) {
+	s, lister, conn := startDrainServer(t)
+	client := pb.NewListerClient(conn)
+
+	results := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			_, err := client.ListAccounts(context.Background(), &pb.ListAccountsRequest{})
+			results <- err
+		}()
+		<-lister.started
+	}
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		close(lister.release)
+	}()
+	drained, cancelled := s.Stop(context.Background())
+	require.Equal(t, 2, drained)
+	require.Equal(t, 0, cancelled)
+	require.NoError(t, <-results)
+	require.NoError(t, <-results)
+
+	// Further calls return immediately.
+	drained, cancelled = s.Stop(context.Background())
+	require.Equal(t, 2, drained)
+	require.Equal(t, 0, cancelled)
+}
+
+func TestStopCancels(t *testing.T) {
+	s, lister, conn := startDrainServer(t)
+	client := pb.NewListerClient(conn)
+
+	results := make(chan error, 1)
+	go func() {
+		_, err := client.ListAccounts(context.Background(), &pb.ListAccountsRequest{})
+		results <- err
+	}()
+	<-lister.started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	drained, cancelled := s.Stop(ctx)
+	require.Equal(t, 0, drained)
+	require.Equal(t, 1, cancelled)
+	require.Error(t, <-results)
+}
@@ -0,0 +1,188 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accountadmin_test
+
+import (
+	context "context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	dirkpb "github.com/attestantio/dirk/pb/v1"
+	"github.com/attestantio/dirk/rules"
+	standardrules "github.com/attestantio/dirk/rules/standard"
+	standardaccountmanager "github.com/attestantio/dirk/services/accountmanager/standard"
+	"github.com/attestantio/dirk/services/api/grpc/handlers/accountadmin"
+	"github.com/attestantio/dirk/services/api/grpc/interceptors"
+	mockchecker "github.com/attestantio/dirk/services/checker/mock"
+	memfetcher "github.com/attestantio/dirk/services/fetcher/mem"
+	syncmaplocker "github.com/attestantio/dirk/services/locker/syncmap"
+	mockprocess "github.com/attestantio/dirk/services/process/mock"
+	"github.com/attestantio/dirk/services/ruler/golang"
+	localunlocker "github.com/attestantio/dirk/services/unlocker/local"
+	"github.com/attestantio/dirk/testing/accounts"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	pb "github.com/wealdtech/eth2-signer-api/pb/v1"
+	e2types "github.com/wealdtech/go-eth2-types/v2"
+	filesystem "github.com/wealdtech/go-eth2-wallet-store-filesystem"
+	e2wtypes "github.com/wealdtech/go-eth2-wallet-types/v2"
+)
+
+func TestMain(m *testing.M) {
+	if err := e2types.InitBLS(); err != nil {
+		os.Exit(1)
+	}
+	os.Exit(m.Run())
+}
+
+func TestDelete(t *testing.T) {
+	ctx := context.Background()
+	base, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(base)
+
+	store, err := accounts.SetupWithStore(ctx, filesystem.New(filesystem.WithLocation(base)))
+	require.NoError(t, err)
+	locker, err := syncmaplocker.New(ctx)
+	require.NoError(t, err)
+	fetcher, err := memfetcher.New(ctx,
+		memfetcher.WithStores([]e2wtypes.Store{store}))
+	require.NoError(t, err)
+	rulesSvc, err := standardrules.New(ctx,
+		standardrules.WithStoragePath(base+"/rules"),
+	)
+	require.NoError(t, err)
+	ruler, err := golang.New(ctx,
+		golang.WithLocker(locker),
+		golang.WithRules(rulesSvc))
+	require.NoError(t, err)
+	checker, err := mockchecker.New()
+	require.NoError(t, err)
+	process, err := mockprocess.New()
+	require.NoError(t, err)
+	unlocker, err := localunlocker.New(ctx)
+	require.NoError(t, err)
+	accountManager, err := standardaccountmanager.New(ctx,
+		standardaccountmanager.WithUnlocker(unlocker),
+		standardaccountmanager.WithChecker(checker),
+		standardaccountmanager.WithFetcher(fetcher),
+		standardaccountmanager.WithRuler(ruler),
+		standardaccountmanager.WithProcess(process),
+		standardaccountmanager.WithSlashingProtectionPurger(rulesSvc),
+	)
+	require.NoError(t, err)
+	handler, err := accountadmin.New(ctx,
+		accountadmin.WithAccountManager(accountManager),
+	)
+	require.NoError(t, err)
+
+	// Set up slashing protection for the accounts to be deleted.
+	protection := make(map[[48]byte]*rules.SlashingProtection)
+	pubKeys := make(map[string][48]byte)
+	for _, name := range []string{"Account 1", "Account 2"} {
+		_, account, err := fetcher.FetchAccount(ctx, "Wallet 1/"+name)
+		require.NoError(t, err)
+		var pubKey [48]byte
+		copy(pubKey[:], account.PublicKey().Marshal())
+		pubKeys[name] = pubKey
+		protection[pubKey] = &rules.SlashingProtection{
+			HighestProposedSlot:        1,
+			HighestAttestedSourceEpoch: 2,
+			HighestAttestedTargetEpoch: 3,
+		}
+	}
+	require.NoError(t, rulesSvc.ImportSlashingProtection(ctx, protection))
+
+	tests := []struct {
+		name   string
+		client string
+		req    *dirkpb.DeleteAccountRequest
+		err    string
+		state  pb.ResponseState
+	}{
+		{
+			name:   "Missing",
+			client: "client1",
+			err:    "no request specified",
+		},
+		{
+			name:   "Empty",
+			client: "client1",
+			req:    &dirkpb.DeleteAccountRequest{},
+			state:  pb.ResponseState_DENIED,
+		},
+		{
+			name:   "AccountUnknown",
+			client: "client1",
+			req:    &dirkpb.DeleteAccountRequest{Account: "Wallet 1/Unknown"},
+			state:  pb.ResponseState_DENIED,
+		},
+		{
+			name:   "DeniedClient",
+			client: "Deny this client",
+			req:    &dirkpb.DeleteAccountRequest{Account: "Wallet 1/Account 1"},
+			state:  pb.ResponseState_DENIED,
+		},
+		{
+			name:   "DeniedAccount",
+			client: "client1",
+			req:    &dirkpb.DeleteAccountRequest{Account: "Wallet 1/Deny this account"},
+			state:  pb.ResponseState_DENIED,
+		},
+		{
+			name:   "Good",
+			client: "client1",
+			req:    &dirkpb.DeleteAccountRequest{Account: "Wallet 1/Account 1"},
+			state:  pb.ResponseState_SUCCEEDED,
+		},
+		{
+			name:   "AlreadyDeleted",
+			client: "client1",
+			req:    &dirkpb.DeleteAccountRequest{Account: "Wallet 1/Account 1"},
+			state:  pb.ResponseState_DENIED,
+		},
+		{
+			name:   "GoodPurge",
+			client: "client1",
+			req:    &dirkpb.DeleteAccountRequest{Account: "Wallet 1/Account 2", PurgeSlashingProtection: true},
+			state:  pb.ResponseState_SUCCEEDED,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.WithValue(context.Background(), &interceptors.ClientName{}, test.client)
+			resp, err := handler.Delete(ctx, test.req)
+			if test.err == "" {
+				// Result expected.
+				require.NoError(t, err)
+				assert.Equal(t, test.state, resp.State)
+			} else {
+				// Error expected.
+				assert.EqualError(t, err, test.err)
+			}
+		})
+	}
+
+	// Slashing protection should be retained for the first account, and purged for the second.
+	export, err := rulesSvc.ExportSlashingProtection(ctx)
+	require.NoError(t, err)
+	require.Contains(t, export, pubKeys["Account 1"])
+	require.NotContains(t, export, pubKeys["Account 2"])
+
+	// Other accounts should be untouched.
+	_, _, err = fetcher.FetchAccount(ctx, "Wallet 1/Account 3")
+	require.NoError(t, err)
+}
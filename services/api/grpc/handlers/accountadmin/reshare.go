@@ -0,0 +1,63 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accountadmin
+
+import (
+	context "context"
+	"errors"
+
+	dirkpb "github.com/attestantio/dirk/pb/v1"
+	"github.com/attestantio/dirk/services/api/grpc/handlers"
+	"github.com/attestantio/dirk/services/process"
+	pb "github.com/wealdtech/eth2-signer-api/pb/v1"
+)
+
+// Reshare reshares a distributed account to a new set of participants and threshold.
+func (h *Handler) Reshare(ctx context.Context, req *dirkpb.ReshareAccountRequest) (*dirkpb.ReshareAccountResponse, error) {
+	if req == nil {
+		log.Warn().Str("result", "denied").Msg("Request not specified")
+		return nil, errors.New("no request specified")
+	}
+
+	log.Trace().Str("account", req.GetAccount()).Uint32("signing_threshold", req.GetSigningThreshold()).Uint32("participants", req.GetParticipants()).Msg("Reshare account received")
+	res := &dirkpb.ReshareAccountResponse{}
+
+	resharer, isResharer := h.process.(process.Resharer)
+	if !isResharer {
+		res.State = pb.ResponseState_FAILED
+		res.Message = "resharing not supported"
+		return res, nil
+	}
+
+	pubKey, participants, err := resharer.OnReshare(ctx, handlers.GenerateCredentials(ctx), req.GetAccount(), req.GetPassphrase(), req.GetSigningThreshold(), req.GetParticipants())
+	if err != nil {
+		log.Error().Err(err).Msg("Reshare attempt resulted in error")
+		res.State = pb.ResponseState_FAILED
+		res.Message = err.Error()
+		return res, nil
+	}
+
+	res.State = pb.ResponseState_SUCCEEDED
+	res.PublicKey = pubKey
+	res.Participants = make([]*pb.Endpoint, len(participants))
+	for i, participant := range participants {
+		res.Participants[i] = &pb.Endpoint{
+			Id:   participant.ID,
+			Name: participant.Name,
+			Port: participant.Port,
+		}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,109 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accountadmin_test
+
+import (
+	context "context"
+	"errors"
+	"testing"
+
+	"github.com/attestantio/dirk/core"
+	dirkpb "github.com/attestantio/dirk/pb/v1"
+	"github.com/attestantio/dirk/services/accountmanager"
+	"github.com/attestantio/dirk/services/api/grpc/handlers/accountadmin"
+	"github.com/attestantio/dirk/services/checker"
+	"github.com/attestantio/dirk/services/process"
+	mockprocess "github.com/attestantio/dirk/services/process/mock"
+	"github.com/stretchr/testify/require"
+	pb "github.com/wealdtech/eth2-signer-api/pb/v1"
+)
+
+type testAccountManager struct {
+	accountmanager.Service
+}
+
+type testResharer struct {
+	process.Service
+	process.Resharer
+	err error
+}
+
+func (r *testResharer) OnReshare(ctx context.Context, credentials *checker.Credentials, account string, passphrase []byte, threshold uint32, numParticipants uint32) ([]byte, []*core.Endpoint, error) {
+	if r.err != nil {
+		return nil, nil, r.err
+	}
+	return []byte{0x01}, []*core.Endpoint{{ID: 1, Name: "server1", Port: 1}, {ID: 2, Name: "server2", Port: 2}}, nil
+}
+
+func TestReshare(t *testing.T) {
+	ctx := context.Background()
+	mockProcess, err := mockprocess.New()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		process      process.Service
+		req          *dirkpb.ReshareAccountRequest
+		err          string
+		state        pb.ResponseState
+		message      string
+		participants int
+	}{
+		{
+			name:    "Missing",
+			process: &testResharer{},
+			err:     "no request specified",
+		},
+		{
+			name:    "NotSupported",
+			process: mockProcess,
+			req:     &dirkpb.ReshareAccountRequest{Account: "Wallet 1/Account 1", SigningThreshold: 3, Participants: 5},
+			state:   pb.ResponseState_FAILED,
+			message: "resharing not supported",
+		},
+		{
+			name:    "Failed",
+			process: &testResharer{err: errors.New("not enough existing participants available")},
+			req:     &dirkpb.ReshareAccountRequest{Account: "Wallet 1/Account 1", SigningThreshold: 3, Participants: 5},
+			state:   pb.ResponseState_FAILED,
+			message: "not enough existing participants available",
+		},
+		{
+			name:         "Good",
+			process:      &testResharer{},
+			req:          &dirkpb.ReshareAccountRequest{Account: "Wallet 1/Account 1", SigningThreshold: 2, Participants: 2},
+			state:        pb.ResponseState_SUCCEEDED,
+			participants: 2,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			handler, err := accountadmin.New(ctx,
+				accountadmin.WithAccountManager(&testAccountManager{}),
+				accountadmin.WithProcess(test.process),
+			)
+			require.NoError(t, err)
+			resp, err := handler.Reshare(ctx, test.req)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.state, resp.State)
+			require.Equal(t, test.message, resp.Message)
+			require.Len(t, resp.Participants, test.participants)
+		})
+	}
+}
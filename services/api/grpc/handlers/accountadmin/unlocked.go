@@ -0,0 +1,72 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accountadmin
+
+import (
+	context "context"
+	"errors"
+
+	"github.com/attestantio/dirk/core"
+	dirkpb "github.com/attestantio/dirk/pb/v1"
+	"github.com/attestantio/dirk/services/api/grpc/handlers"
+	pb "github.com/wealdtech/eth2-signer-api/pb/v1"
+)
+
+// ListUnlocked lists the accounts that are unlocked.  Only public keys are
+// returned; no key material is included in the response.
+func (h *Handler) ListUnlocked(ctx context.Context, req *dirkpb.ListUnlockedAccountsRequest) (*dirkpb.ListUnlockedAccountsResponse, error) {
+	if req == nil {
+		log.Warn().Str("result", "denied").Msg("Request not specified")
+		return nil, errors.New("no request specified")
+	}
+
+	log.Trace().Msg("List unlocked accounts received")
+	res := &dirkpb.ListUnlockedAccountsResponse{
+		Accounts: make([]*dirkpb.UnlockedAccount, 0),
+	}
+
+	if h.unlockedProvider == nil {
+		log.Warn().Msg("Account manager does not support listing unlocked accounts")
+		res.State = pb.ResponseState_FAILED
+		res.Message = "unlocked accounts cannot be listed"
+		return res, nil
+	}
+
+	result, accounts := h.unlockedProvider.UnlockedAccounts(ctx, handlers.GenerateCredentials(ctx))
+	switch result {
+	case core.ResultSucceeded:
+		res.State = pb.ResponseState_SUCCEEDED
+		for _, account := range accounts {
+			unlockedAccount := &dirkpb.UnlockedAccount{
+				Account:            account.Account,
+				PublicKey:          account.PubKey,
+				CompositePublicKey: account.CompositePubKey,
+				Relocks:            account.Relocks,
+			}
+			if account.Relocks {
+				unlockedAccount.IdleSeconds = uint64(account.IdleTime.Seconds())
+				unlockedAccount.RelockAfterSeconds = uint64(account.RelockAfter.Seconds())
+			}
+			res.Accounts = append(res.Accounts, unlockedAccount)
+		}
+	case core.ResultDenied:
+		res.State = pb.ResponseState_DENIED
+	case core.ResultFailed:
+		res.State = pb.ResponseState_FAILED
+	default:
+		res.State = pb.ResponseState_UNKNOWN
+	}
+
+	return res, nil
+}
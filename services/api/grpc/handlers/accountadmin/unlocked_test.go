@@ -0,0 +1,161 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accountadmin_test
+
+import (
+	context "context"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	dirkpb "github.com/attestantio/dirk/pb/v1"
+	standardrules "github.com/attestantio/dirk/rules/standard"
+	standardaccountmanager "github.com/attestantio/dirk/services/accountmanager/standard"
+	"github.com/attestantio/dirk/services/api/grpc/handlers/accountadmin"
+	"github.com/attestantio/dirk/services/api/grpc/interceptors"
+	mockchecker "github.com/attestantio/dirk/services/checker/mock"
+	memfetcher "github.com/attestantio/dirk/services/fetcher/mem"
+	syncmaplocker "github.com/attestantio/dirk/services/locker/syncmap"
+	mockprocess "github.com/attestantio/dirk/services/process/mock"
+	"github.com/attestantio/dirk/services/ruler/golang"
+	localunlocker "github.com/attestantio/dirk/services/unlocker/local"
+	"github.com/attestantio/dirk/testing/accounts"
+	"github.com/stretchr/testify/require"
+	pb "github.com/wealdtech/eth2-signer-api/pb/v1"
+	filesystem "github.com/wealdtech/go-eth2-wallet-store-filesystem"
+	e2wtypes "github.com/wealdtech/go-eth2-wallet-types/v2"
+)
+
+func TestListUnlocked(t *testing.T) {
+	ctx := context.Background()
+	base, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(base)
+
+	store, err := accounts.SetupWithStore(ctx, filesystem.New(filesystem.WithLocation(base)))
+	require.NoError(t, err)
+	locker, err := syncmaplocker.New(ctx)
+	require.NoError(t, err)
+	fetcher, err := memfetcher.New(ctx,
+		memfetcher.WithStores([]e2wtypes.Store{store}))
+	require.NoError(t, err)
+	rulesSvc, err := standardrules.New(ctx,
+		standardrules.WithStoragePath(base+"/rules"),
+	)
+	require.NoError(t, err)
+	ruler, err := golang.New(ctx,
+		golang.WithLocker(locker),
+		golang.WithRules(rulesSvc))
+	require.NoError(t, err)
+	checker, err := mockchecker.New()
+	require.NoError(t, err)
+	process, err := mockprocess.New()
+	require.NoError(t, err)
+	unlocker, err := localunlocker.New(ctx,
+		localunlocker.WithAccountPassphrases([]string{"Account 2 passphrase", "Account 1 passphrase"}),
+		localunlocker.WithRelockAfter(time.Hour),
+	)
+	require.NoError(t, err)
+	accountManager, err := standardaccountmanager.New(ctx,
+		standardaccountmanager.WithUnlocker(unlocker),
+		standardaccountmanager.WithChecker(checker),
+		standardaccountmanager.WithFetcher(fetcher),
+		standardaccountmanager.WithRuler(ruler),
+		standardaccountmanager.WithProcess(process),
+	)
+	require.NoError(t, err)
+	handler, err := accountadmin.New(ctx,
+		accountadmin.WithAccountManager(accountManager),
+	)
+	require.NoError(t, err)
+
+	// Unlock one account directly, and two through the unlocker so that they relock when idle.
+	_, account1, err := fetcher.FetchAccount(ctx, "Wallet 1/Account 1")
+	require.NoError(t, err)
+	require.NoError(t, account1.(e2wtypes.AccountLocker).Unlock(ctx, []byte("Account 1 passphrase")))
+	for _, name := range []string{"Wallet 1/Account 2", "Wallet 2/Account 1"} {
+		wallet, account, err := fetcher.FetchAccount(ctx, name)
+		require.NoError(t, err)
+		unlocked, err := unlocker.UnlockAccount(ctx, wallet, account)
+		require.NoError(t, err)
+		require.True(t, unlocked)
+	}
+
+	tests := []struct {
+		name     string
+		client   string
+		req      *dirkpb.ListUnlockedAccountsRequest
+		err      string
+		state    pb.ResponseState
+		accounts []string
+	}{
+		{
+			name:   "Missing",
+			client: "client1",
+			err:    "no request specified",
+		},
+		{
+			name:     "DeniedClient",
+			client:   "Deny this client",
+			req:      &dirkpb.ListUnlockedAccountsRequest{},
+			state:    pb.ResponseState_SUCCEEDED,
+			accounts: []string{},
+		},
+		{
+			name:     "Good",
+			client:   "client1",
+			req:      &dirkpb.ListUnlockedAccountsRequest{},
+			state:    pb.ResponseState_SUCCEEDED,
+			accounts: []string{"Wallet 1/Account 1", "Wallet 1/Account 2", "Wallet 2/Account 1"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.WithValue(context.Background(), &interceptors.ClientName{}, test.client)
+			resp, err := handler.ListUnlocked(ctx, test.req)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.state, resp.State)
+			names := make([]string, 0, len(resp.Accounts))
+			for _, account := range resp.Accounts {
+				names = append(names, account.Account)
+				require.Len(t, account.PublicKey, 48)
+			}
+			require.Equal(t, test.accounts, names)
+		})
+	}
+
+	// Check the details of the accounts.
+	ctx = context.WithValue(ctx, &interceptors.ClientName{}, "client1")
+	resp, err := handler.ListUnlocked(ctx, &dirkpb.ListUnlockedAccountsRequest{})
+	require.NoError(t, err)
+	require.Len(t, resp.Accounts, 3)
+	// Unlocked directly, so not relocked.
+	require.Equal(t, account1.PublicKey().Marshal(), resp.Accounts[0].PublicKey)
+	require.False(t, resp.Accounts[0].Relocks)
+	require.Zero(t, resp.Accounts[0].RelockAfterSeconds)
+	require.Nil(t, resp.Accounts[0].CompositePublicKey)
+	// Unlocked by the unlocker, so relocked when idle.
+	require.True(t, resp.Accounts[1].Relocks)
+	require.Equal(t, uint64(3600), resp.Accounts[1].RelockAfterSeconds)
+	require.Less(t, resp.Accounts[1].IdleSeconds, uint64(60))
+	// Distributed, so has a composite public key.
+	require.True(t, resp.Accounts[2].Relocks)
+	require.Len(t, resp.Accounts[2].CompositePublicKey, 48)
+}
@@ -0,0 +1,86 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accountmanager
+
+import (
+	context "context"
+	"errors"
+
+	"github.com/attestantio/dirk/core"
+	dirkpb "github.com/attestantio/dirk/pb/v1"
+	"github.com/attestantio/dirk/services/api/grpc/handlers"
+	pb "github.com/wealdtech/eth2-signer-api/pb/v1"
+)
+
+// GenerateBulk generates multiple accounts.
+func (h *Handler) GenerateBulk(ctx context.Context, req *dirkpb.GenerateBulkRequest) (*dirkpb.GenerateBulkResponse, error) {
+	if req == nil {
+		log.Warn().Str("result", "denied").Msg("Request not specified")
+		return nil, errors.New("no request specified")
+	}
+
+	log.Trace().Str("wallet", req.GetWallet()).Uint32("count", req.GetCount()).Msg("Generate bulk received")
+	res := &dirkpb.GenerateBulkResponse{
+		Accounts: make([]*dirkpb.GeneratedAccount, 0),
+	}
+
+	if h.bulkGenerator == nil {
+		log.Warn().Msg("Account manager does not support bulk generation")
+		res.State = pb.ResponseState_FAILED
+		return res, nil
+	}
+
+	result, accounts, err := h.bulkGenerator.GenerateBulk(ctx,
+		handlers.GenerateCredentials(ctx),
+		req.GetWallet(),
+		req.GetNamePrefix(),
+		req.GetStartIndex(),
+		req.GetCount(),
+		req.GetPassphrase(),
+		req.GetSigningThreshold(),
+		req.GetParticipants(),
+	)
+	if err != nil {
+		log.Error().Err(err).Msg("Generate bulk attempt resulted in error")
+		res.Message = err.Error()
+	}
+	switch result {
+	case core.ResultSucceeded:
+		res.State = pb.ResponseState_SUCCEEDED
+	case core.ResultDenied:
+		res.State = pb.ResponseState_DENIED
+	case core.ResultFailed:
+		res.State = pb.ResponseState_FAILED
+	default:
+		res.State = pb.ResponseState_UNKNOWN
+	}
+
+	for _, account := range accounts {
+		generated := &dirkpb.GeneratedAccount{
+			Account:      account.Account,
+			PublicKey:    account.PubKey,
+			Participants: make([]*pb.Endpoint, len(account.Participants)),
+		}
+		for i, participant := range account.Participants {
+			generated.Participants[i] = &pb.Endpoint{
+				Id:   participant.ID,
+				Name: participant.Name,
+				Port: participant.Port,
+			}
+		}
+		res.Accounts = append(res.Accounts, generated)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,163 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accountmanager_test
+
+import (
+	context "context"
+	"testing"
+
+	dirkpb "github.com/attestantio/dirk/pb/v1"
+	"github.com/attestantio/dirk/services/api/grpc/interceptors"
+	"github.com/stretchr/testify/require"
+	pb "github.com/wealdtech/eth2-signer-api/pb/v1"
+)
+
+func TestGenerateBulk(t *testing.T) {
+	tests := []struct {
+		name     string
+		client   string
+		req      *dirkpb.GenerateBulkRequest
+		err      string
+		state    pb.ResponseState
+		message  string
+		accounts []string
+	}{
+		{
+			name:   "Missing",
+			client: "client1",
+			err:    "no request specified",
+		},
+		{
+			name:   "CountZero",
+			client: "client1",
+			req: &dirkpb.GenerateBulkRequest{
+				Wallet:       "Wallet 1",
+				Participants: 1,
+			},
+			state:   pb.ResponseState_DENIED,
+			message: "no accounts requested",
+		},
+		{
+			name:   "CountTooHigh",
+			client: "client1",
+			req: &dirkpb.GenerateBulkRequest{
+				Wallet:       "Wallet 1",
+				Count:        1001,
+				Participants: 1,
+			},
+			state:   pb.ResponseState_DENIED,
+			message: "too many accounts requested; maximum is 1000",
+		},
+		{
+			name:   "IndicesOutOfRange",
+			client: "client1",
+			req: &dirkpb.GenerateBulkRequest{
+				Wallet:       "Wallet 1",
+				StartIndex:   0xfffffffe,
+				Count:        3,
+				Participants: 1,
+			},
+			state:   pb.ResponseState_DENIED,
+			message: "account indices out of range",
+		},
+		{
+			name:   "ThresholdTooHigh",
+			client: "client1",
+			req: &dirkpb.GenerateBulkRequest{
+				Wallet:           "Wallet 1",
+				Count:            2,
+				SigningThreshold: 3,
+				Participants:     2,
+			},
+			state:   pb.ResponseState_DENIED,
+			message: "invalid signing threshold",
+		},
+		{
+			name:   "AccountDenied",
+			client: "client1",
+			req: &dirkpb.GenerateBulkRequest{
+				Wallet:       "Wallet 1",
+				NamePrefix:   "Deny ",
+				Count:        2,
+				Participants: 1,
+			},
+			state: pb.ResponseState_DENIED,
+		},
+		{
+			name:   "ClientDenied",
+			client: "Deny this client",
+			req: &dirkpb.GenerateBulkRequest{
+				Wallet:       "Wallet 1",
+				Count:        2,
+				Participants: 1,
+			},
+			state: pb.ResponseState_DENIED,
+		},
+		{
+			name:   "Good",
+			client: "client1",
+			req: &dirkpb.GenerateBulkRequest{
+				Wallet:       "Wallet 1",
+				NamePrefix:   "Validator ",
+				StartIndex:   5,
+				Count:        3,
+				Passphrase:   []byte("test"),
+				Participants: 1,
+			},
+			state:    pb.ResponseState_SUCCEEDED,
+			accounts: []string{"Wallet 1/Validator 5", "Wallet 1/Validator 6", "Wallet 1/Validator 7"},
+		},
+		{
+			name:   "GoodDistributed",
+			client: "client1",
+			req: &dirkpb.GenerateBulkRequest{
+				Wallet:           "Wallet 1",
+				NamePrefix:       "Distributed ",
+				Count:            20,
+				Passphrase:       []byte("test"),
+				SigningThreshold: 2,
+				Participants:     3,
+			},
+			state: pb.ResponseState_SUCCEEDED,
+		},
+	}
+
+	handler, err := Setup()
+	require.NoError(t, err)
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.WithValue(context.Background(), &interceptors.ClientName{}, test.client)
+			resp, err := handler.GenerateBulk(ctx, test.req)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.state, resp.State)
+			require.Equal(t, test.message, resp.Message)
+			if test.state != pb.ResponseState_SUCCEEDED {
+				require.Empty(t, resp.Accounts)
+				return
+			}
+			require.Len(t, resp.Accounts, int(test.req.Count))
+			for i, account := range test.accounts {
+				require.Equal(t, account, resp.Accounts[i].Account)
+			}
+			for _, account := range resp.Accounts {
+				require.NotEmpty(t, account.Participants)
+			}
+		})
+	}
+}
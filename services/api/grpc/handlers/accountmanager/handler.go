@@ -16,6 +16,7 @@ package accountmanager
 import (
 	context "context"
 
+	dirkpb "github.com/attestantio/dirk/pb/v1"
 	"github.com/attestantio/dirk/services/accountmanager"
 	"github.com/attestantio/dirk/services/process"
 	"github.com/pkg/errors"
@@ -27,7 +28,11 @@ import (
 // Handler is the account manager handler.
 type Handler struct {
 	pb.UnimplementedAccountManagerServer
+	dirkpb.UnimplementedAccountStatusServer
+	dirkpb.UnimplementedBulkGenerateServer
 	accountManager accountmanager.Service
+	statusProvider accountmanager.StatusProvider
+	bulkGenerator  accountmanager.BulkGenerator
 	process        process.Service
 }
 
@@ -50,6 +55,12 @@ func New(ctx context.Context, params ...Parameter) (*Handler, error) {
 		accountManager: parameters.accountManager,
 		process:        parameters.process,
 	}
+	if statusProvider, isStatusProvider := parameters.accountManager.(accountmanager.StatusProvider); isStatusProvider {
+		h.statusProvider = statusProvider
+	}
+	if bulkGenerator, isBulkGenerator := parameters.accountManager.(accountmanager.BulkGenerator); isBulkGenerator {
+		h.bulkGenerator = bulkGenerator
+	}
 
 	return h, nil
 }
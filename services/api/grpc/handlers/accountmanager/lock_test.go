@@ -23,6 +23,7 @@ import (
 	"github.com/attestantio/dirk/services/api/grpc/handlers/accountmanager"
 	"github.com/attestantio/dirk/services/api/grpc/interceptors"
 	mockchecker "github.com/attestantio/dirk/services/checker/mock"
+	"github.com/attestantio/dirk/services/fetcher"
 	memfetcher "github.com/attestantio/dirk/services/fetcher/mem"
 	syncmaplocker "github.com/attestantio/dirk/services/locker/syncmap"
 	mockprocess "github.com/attestantio/dirk/services/process/mock"
@@ -122,41 +123,47 @@ func TestLock(t *testing.T) {
 }
 
 func Setup() (*accountmanager.Handler, error) {
+	handler, _, err := SetupWithFetcher()
+	return handler, err
+}
+
+// SetupWithFetcher sets up a handler, also returning its fetcher.
+func SetupWithFetcher() (*accountmanager.Handler, fetcher.Service, error) {
 	ctx := context.Background()
 	store, err := accounts.Setup(ctx)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to create accounts")
+		return nil, nil, errors.Wrap(err, "failed to create accounts")
 	}
 
 	locker, err := syncmaplocker.New(ctx)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	fetcher, err := memfetcher.New(ctx,
 		memfetcher.WithStores([]e2wtypes.Store{store}))
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to create account fetcher service")
+		return nil, nil, errors.Wrap(err, "failed to create account fetcher service")
 	}
 
 	ruler, err := golang.New(ctx,
 		golang.WithLocker(locker),
 		golang.WithRules(mockrules.New()))
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to create account rules service")
+		return nil, nil, errors.Wrap(err, "failed to create account rules service")
 	}
 
 	checker, err := mockchecker.New()
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to create account checker service")
+		return nil, nil, errors.Wrap(err, "failed to create account checker service")
 	}
 	process, err := mockprocess.New()
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to create process service")
+		return nil, nil, errors.Wrap(err, "failed to create process service")
 	}
 	unlocker, err := localunlocker.New(ctx)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to create unlocker service")
+		return nil, nil, errors.Wrap(err, "failed to create unlocker service")
 	}
 
 	accountManager, err := standardaccountmanager.New(ctx,
@@ -168,9 +175,13 @@ func Setup() (*accountmanager.Handler, error) {
 		standardaccountmanager.WithProcess(process),
 	)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to create account manager service")
+		return nil, nil, errors.Wrap(err, "failed to create account manager service")
 	}
-	return accountmanager.New(ctx,
+	handler, err := accountmanager.New(ctx,
 		accountmanager.WithAccountManager(accountManager),
 		accountmanager.WithProcess(process))
+	if err != nil {
+		return nil, nil, err
+	}
+	return handler, fetcher, nil
 }
@@ -0,0 +1,85 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accountmanager
+
+import (
+	context "context"
+	"errors"
+
+	"github.com/attestantio/dirk/core"
+	dirkpb "github.com/attestantio/dirk/pb/v1"
+	"github.com/attestantio/dirk/services/api/grpc/handlers"
+	pb "github.com/wealdtech/eth2-signer-api/pb/v1"
+)
+
+// maxStatusPageSize is the maximum number of statuses returned in a single response.
+const maxStatusPageSize = 1000
+
+// Status provides the status of multiple accounts.
+func (h *Handler) Status(ctx context.Context, req *dirkpb.AccountStatusRequest) (*dirkpb.AccountStatusResponse, error) {
+	if req == nil {
+		log.Warn().Str("result", "denied").Msg("Request not specified")
+		return nil, errors.New("no request specified")
+	}
+
+	log.Trace().Int("keys", len(req.GetPubKeys())).Uint32("offset", req.GetOffset()).Uint32("limit", req.GetLimit()).Msg("Account status received")
+	res := &dirkpb.AccountStatusResponse{
+		Statuses: make([]*dirkpb.KeyStatus, 0),
+	}
+
+	if h.statusProvider == nil {
+		log.Warn().Msg("Account manager does not support account status")
+		res.State = pb.ResponseState_FAILED
+		return res, nil
+	}
+
+	offset := int(req.GetOffset())
+	if offset > len(req.GetPubKeys()) {
+		log.Debug().Msg("Offset beyond end of keys")
+		res.State = pb.ResponseState_DENIED
+		return res, nil
+	}
+	limit := int(req.GetLimit())
+	if limit == 0 || limit > maxStatusPageSize {
+		limit = maxStatusPageSize
+	}
+	end := offset + limit
+	if end > len(req.GetPubKeys()) {
+		end = len(req.GetPubKeys())
+	}
+
+	result, statuses := h.statusProvider.AccountStatuses(ctx, handlers.GenerateCredentials(ctx), req.GetPubKeys()[offset:end])
+	switch result {
+	case core.ResultSucceeded:
+		res.State = pb.ResponseState_SUCCEEDED
+		for _, status := range statuses {
+			res.Statuses = append(res.Statuses, &dirkpb.KeyStatus{
+				PubKey:   status.PubKey,
+				Found:    status.Found,
+				Unlocked: status.Unlocked,
+			})
+		}
+		if end < len(req.GetPubKeys()) {
+			res.NextOffset = uint32(end)
+		}
+	case core.ResultDenied:
+		res.State = pb.ResponseState_DENIED
+	case core.ResultFailed:
+		res.State = pb.ResponseState_FAILED
+	default:
+		res.State = pb.ResponseState_UNKNOWN
+	}
+
+	return res, nil
+}
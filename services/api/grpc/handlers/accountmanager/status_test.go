@@ -0,0 +1,153 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accountmanager_test
+
+import (
+	context "context"
+	"testing"
+
+	dirkpb "github.com/attestantio/dirk/pb/v1"
+	"github.com/attestantio/dirk/services/api/grpc/interceptors"
+	"github.com/stretchr/testify/require"
+	pb "github.com/wealdtech/eth2-signer-api/pb/v1"
+)
+
+func TestStatus(t *testing.T) {
+	ctx := context.Background()
+	handler, fetcher, err := SetupWithFetcher()
+	require.NoError(t, err)
+
+	pubKeys := make([][]byte, 0)
+	for _, name := range []string{"Wallet 1/Account 1", "Wallet 1/Account 2", "Wallet 1/Account 3"} {
+		_, account, err := fetcher.FetchAccount(ctx, name)
+		require.NoError(t, err)
+		pubKeys = append(pubKeys, account.PublicKey().Marshal())
+	}
+	unknownKey := make([]byte, 48)
+	unknownKey[0] = 0xc0
+
+	// Unlock the first account.
+	clientCtx := context.WithValue(ctx, &interceptors.ClientName{}, "client1")
+	unlockRes, err := handler.Unlock(clientCtx, &pb.UnlockAccountRequest{
+		Account:    "Wallet 1/Account 1",
+		Passphrase: []byte("Account 1 passphrase"),
+	})
+	require.NoError(t, err)
+	require.Equal(t, pb.ResponseState_SUCCEEDED, unlockRes.State)
+
+	tests := []struct {
+		name       string
+		client     string
+		req        *dirkpb.AccountStatusRequest
+		err        string
+		state      pb.ResponseState
+		statuses   []*dirkpb.KeyStatus
+		nextOffset uint32
+	}{
+		{
+			name:   "Missing",
+			client: "client1",
+			err:    "no request specified",
+		},
+		{
+			name:     "Empty",
+			client:   "client1",
+			req:      &dirkpb.AccountStatusRequest{},
+			state:    pb.ResponseState_SUCCEEDED,
+			statuses: []*dirkpb.KeyStatus{},
+		},
+		{
+			name:   "OffsetTooHigh",
+			client: "client1",
+			req: &dirkpb.AccountStatusRequest{
+				PubKeys: pubKeys,
+				Offset:  4,
+			},
+			state:    pb.ResponseState_DENIED,
+			statuses: []*dirkpb.KeyStatus{},
+		},
+		{
+			name:   "Good",
+			client: "client1",
+			req: &dirkpb.AccountStatusRequest{
+				PubKeys: [][]byte{pubKeys[0], pubKeys[1], unknownKey},
+			},
+			state: pb.ResponseState_SUCCEEDED,
+			statuses: []*dirkpb.KeyStatus{
+				{PubKey: pubKeys[0], Found: true, Unlocked: true},
+				{PubKey: pubKeys[1], Found: true, Unlocked: false},
+				{PubKey: unknownKey, Found: false, Unlocked: false},
+			},
+		},
+		{
+			name:   "DeniedClient",
+			client: "Deny this client",
+			req: &dirkpb.AccountStatusRequest{
+				PubKeys: [][]byte{pubKeys[0], pubKeys[1]},
+			},
+			state: pb.ResponseState_SUCCEEDED,
+			statuses: []*dirkpb.KeyStatus{
+				{PubKey: pubKeys[0], Found: false, Unlocked: false},
+				{PubKey: pubKeys[1], Found: false, Unlocked: false},
+			},
+		},
+		{
+			name:   "FirstPage",
+			client: "client1",
+			req: &dirkpb.AccountStatusRequest{
+				PubKeys: pubKeys,
+				Limit:   2,
+			},
+			state: pb.ResponseState_SUCCEEDED,
+			statuses: []*dirkpb.KeyStatus{
+				{PubKey: pubKeys[0], Found: true, Unlocked: true},
+				{PubKey: pubKeys[1], Found: true, Unlocked: false},
+			},
+			nextOffset: 2,
+		},
+		{
+			name:   "LastPage",
+			client: "client1",
+			req: &dirkpb.AccountStatusRequest{
+				PubKeys: pubKeys,
+				Offset:  2,
+				Limit:   2,
+			},
+			state: pb.ResponseState_SUCCEEDED,
+			statuses: []*dirkpb.KeyStatus{
+				{PubKey: pubKeys[2], Found: true, Unlocked: false},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.WithValue(context.Background(), &interceptors.ClientName{}, test.client)
+			resp, err := handler.Status(ctx, test.req)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.state, resp.State)
+			require.Len(t, resp.Statuses, len(test.statuses))
+			for i := range test.statuses {
+				require.Equal(t, test.statuses[i].PubKey, resp.Statuses[i].PubKey)
+				require.Equal(t, test.statuses[i].Found, resp.Statuses[i].Found)
+				require.Equal(t, test.statuses[i].Unlocked, resp.Statuses[i].Unlocked)
+			}
+			require.Equal(t, test.nextOffset, resp.NextOffset)
+		})
+	}
+}
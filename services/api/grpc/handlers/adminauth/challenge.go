@@ -0,0 +1,38 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adminauth
+
+import (
+	context "context"
+
+	dirkpb "github.com/attestantio/dirk/pb/v1"
+	pb "github.com/wealdtech/eth2-signer-api/pb/v1"
+)
+
+// Challenge issues a nonce to be signed for an administrative request.
+func (h *Handler) Challenge(ctx context.Context, req *dirkpb.ChallengeRequest) (*dirkpb.ChallengeResponse, error) {
+	res := &dirkpb.ChallengeResponse{}
+
+	nonce, expiry, err := h.adminAuth.Challenge(ctx)
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to issue challenge")
+		res.State = pb.ResponseState_FAILED
+		return res, nil
+	}
+
+	res.State = pb.ResponseState_SUCCEEDED
+	res.Nonce = nonce
+	res.Expiry = uint64(expiry.Unix())
+	return res, nil
+}
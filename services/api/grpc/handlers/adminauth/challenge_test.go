@@ -0,0 +1,64 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adminauth_test
+
+import (
+	context "context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	dirkpb "github.com/attestantio/dirk/pb/v1"
+	standardadminauth "github.com/attestantio/dirk/services/adminauth/standard"
+	"github.com/attestantio/dirk/services/api/grpc/handlers/adminauth"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+	pb "github.com/wealdtech/eth2-signer-api/pb/v1"
+)
+
+func TestChallenge(t *testing.T) {
+	ctx := context.Background()
+	pubKey, privKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	adminAuth, err := standardadminauth.New(ctx,
+		standardadminauth.WithLogLevel(zerolog.Disabled),
+		standardadminauth.WithPublicKeys([]string{hex.EncodeToString(pubKey)}),
+		standardadminauth.WithMaxOutstanding(1),
+	)
+	require.NoError(t, err)
+
+	_, err = adminauth.New(ctx)
+	require.EqualError(t, err, "problem with parameters: no admin auth specified")
+
+	handler, err := adminauth.New(ctx, adminauth.WithAdminAuth(adminAuth))
+	require.NoError(t, err)
+
+	res, err := handler.Challenge(ctx, &dirkpb.ChallengeRequest{})
+	require.NoError(t, err)
+	require.Equal(t, pb.ResponseState_SUCCEEDED, res.State)
+	require.Len(t, res.Nonce, 32)
+	require.Greater(t, res.Expiry, uint64(time.Now().Unix()-1))
+
+	// The issued nonce can be used.
+	method := "/dirk.v1.AccountAdmin/Delete"
+	signature := ed25519.Sign(privKey, append(append([]byte{}, res.Nonce...), []byte(method)...))
+
+	// Only one challenge can be outstanding.
+	res2, err := handler.Challenge(ctx, &dirkpb.ChallengeRequest{})
+	require.NoError(t, err)
+	require.Equal(t, pb.ResponseState_FAILED, res2.State)
+
+	require.NoError(t, adminAuth.Verify(ctx, method, res.Nonce, signature))
+}
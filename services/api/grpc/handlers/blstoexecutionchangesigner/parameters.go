@@ -0,0 +1,69 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blstoexecutionchangesigner
+
+import (
+	"errors"
+
+	"github.com/attestantio/dirk/services/signer"
+	"github.com/rs/zerolog"
+)
+
+type parameters struct {
+	logLevel                   zerolog.Level
+	blsToExecutionChangeSigner signer.BLSToExecutionChangeSigner
+}
+
+// Parameter is the interface for service parameters.
+type Parameter interface {
+	apply(*parameters)
+}
+
+type parameterFunc func(*parameters)
+
+func (f parameterFunc) apply(p *parameters) {
+	f(p)
+}
+
+// WithLogLevel sets the log level for the module.
+func WithLogLevel(logLevel zerolog.Level) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.logLevel = logLevel
+	})
+}
+
+// WithBLSToExecutionChangeSigner sets the BLS to execution change signer for the module.
+func WithBLSToExecutionChangeSigner(blsToExecutionChangeSigner signer.BLSToExecutionChangeSigner) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.blsToExecutionChangeSigner = blsToExecutionChangeSigner
+	})
+}
+
+// parseAndCheckParameters parses and checks parameters to ensure that mandatory parameters are present and correct.
+func parseAndCheckParameters(params ...Parameter) (*parameters, error) {
+	parameters := parameters{
+		logLevel: zerolog.GlobalLevel(),
+	}
+	for _, p := range params {
+		if params != nil {
+			p.apply(&parameters)
+		}
+	}
+
+	if parameters.blsToExecutionChangeSigner == nil {
+		return nil, errors.New("no BLS to execution change signer specified")
+	}
+
+	return &parameters, nil
+}
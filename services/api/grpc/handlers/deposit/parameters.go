@@ -0,0 +1,69 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deposit
+
+import (
+	"errors"
+
+	"github.com/attestantio/dirk/services/signer"
+	"github.com/rs/zerolog"
+)
+
+type parameters struct {
+	logLevel             zerolog.Level
+	depositDataGenerator signer.DepositDataGenerator
+}
+
+// Parameter is the interface for service parameters.
+type Parameter interface {
+	apply(*parameters)
+}
+
+type parameterFunc func(*parameters)
+
+func (f parameterFunc) apply(p *parameters) {
+	f(p)
+}
+
+// WithLogLevel sets the log level for the module.
+func WithLogLevel(logLevel zerolog.Level) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.logLevel = logLevel
+	})
+}
+
+// WithDepositDataGenerator sets the deposit data generator for the module.
+func WithDepositDataGenerator(depositDataGenerator signer.DepositDataGenerator) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.depositDataGenerator = depositDataGenerator
+	})
+}
+
+// parseAndCheckParameters parses and checks parameters to ensure that mandatory parameters are present and correct.
+func parseAndCheckParameters(params ...Parameter) (*parameters, error) {
+	parameters := parameters{
+		logLevel: zerolog.GlobalLevel(),
+	}
+	for _, p := range params {
+		if params != nil {
+			p.apply(&parameters)
+		}
+	}
+
+	if parameters.depositDataGenerator == nil {
+		return nil, errors.New("no deposit data generator specified")
+	}
+
+	return &parameters, nil
+}
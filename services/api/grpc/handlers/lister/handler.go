@@ -16,6 +16,7 @@ package lister
 import (
 	context "context"
 
+	dirkpb "github.com/attestantio/dirk/pb/v1"
 	"github.com/attestantio/dirk/services/lister"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
@@ -26,7 +27,9 @@ import (
 // Handler is the lister handler.
 type Handler struct {
 	pb.UnimplementedListerServer
-	lister lister.Service
+	dirkpb.UnimplementedWalletListerServer
+	lister       lister.Service
+	walletLister lister.WalletLister
 }
 
 // module-wide log.
@@ -47,6 +50,9 @@ func New(ctx context.Context, params ...Parameter) (*Handler, error) {
 	h := &Handler{
 		lister: parameters.lister,
 	}
+	if walletLister, isWalletLister := parameters.lister.(lister.WalletLister); isWalletLister {
+		h.walletLister = walletLister
+	}
 
 	return h, nil
 }
@@ -48,58 +48,19 @@ func (h *Handler) ListAccounts(ctx context.Context, req *pb.ListAccountsRequest)
 		return res, nil
 	case core.ResultSucceeded:
 		for _, account := range accounts {
-			uuid, err := account.ID().MarshalBinary()
-			if err != nil {
-				log.Error().Str("uuid", account.ID().String()).Err(err).Msg("Failed to marshal UUID")
-				continue
-			}
 			var name string
 			if walletProvider, isWalletProvider := account.(e2wtypes.AccountWalletProvider); isWalletProvider {
 				name = fmt.Sprintf("%s/%s", walletProvider.Wallet().Name(), account.Name())
 			} else {
 				name = account.Name()
 			}
-			pubKeyProvider, isProvider := account.(e2wtypes.AccountPublicKeyProvider)
-			if !isProvider {
-				log.Error().Msg("Account does not provide public keys")
-				continue
-			}
-			if distributedAccount, isDistributedAccount := account.(e2wtypes.DistributedAccount); isDistributedAccount {
-				pbAccount := &pb.DistributedAccount{
-					Uuid:               uuid,
-					Name:               name,
-					PublicKey:          pubKeyProvider.PublicKey().Marshal(),
-					CompositePublicKey: distributedAccount.CompositePublicKey().Marshal(),
-				}
-				pbAccount.Uuid = uuid
-				pbAccount.SigningThreshold = distributedAccount.SigningThreshold()
-				pbAccount.Participants = make([]*pb.Endpoint, 0)
-				for k, v := range distributedAccount.Participants() {
-					parts := strings.Split(v, ":")
-					if len(parts) != 2 {
-						log.Warn().Str("participant", v).Msg("Invalid format for participant")
-						continue
-					}
-					port, err := strconv.ParseUint(parts[1], 10, 32)
-					if err != nil {
-						log.Warn().Str("participant", v).Err(err).Msg("Invalid port for participant")
-						continue
-					}
-					pbAccount.Participants = append(pbAccount.Participants, &pb.Endpoint{
-						Id:   k,
-						Name: parts[0],
-						Port: uint32(port),
-					})
-				}
-				res.DistributedAccounts = append(res.DistributedAccounts, pbAccount)
-			} else {
-				pbAccount := &pb.Account{
-					Uuid:      uuid,
-					Name:      name,
-					PublicKey: pubKeyProvider.PublicKey().Marshal(),
-				}
+			pbAccount, pbDistributedAccount := accountToPB(account, name)
+			if pbAccount != nil {
 				res.Accounts = append(res.Accounts, pbAccount)
 			}
+			if pbDistributedAccount != nil {
+				res.DistributedAccounts = append(res.DistributedAccounts, pbDistributedAccount)
+			}
 		}
 	}
 
@@ -107,3 +68,53 @@ func (h *Handler) ListAccounts(ctx context.Context, req *pb.ListAccountsRequest)
 	log.Trace().Int("accounts", len(res.Accounts)).Int("distributedAccounts", len(res.DistributedAccounts)).Msg("Success")
 	return res, nil
 }
+
+// accountToPB converts an account to its protobuf representation, returning either
+// an account or a distributed account.  Both are nil if the account cannot be converted.
+func accountToPB(account e2wtypes.Account, name string) (*pb.Account, *pb.DistributedAccount) {
+	uuid, err := account.ID().MarshalBinary()
+	if err != nil {
+		log.Error().Str("uuid", account.ID().String()).Err(err).Msg("Failed to marshal UUID")
+		return nil, nil
+	}
+	pubKeyProvider, isProvider := account.(e2wtypes.AccountPublicKeyProvider)
+	if !isProvider {
+		log.Error().Msg("Account does not provide public keys")
+		return nil, nil
+	}
+	distributedAccount, isDistributedAccount := account.(e2wtypes.DistributedAccount)
+	if !isDistributedAccount {
+		return &pb.Account{
+			Uuid:      uuid,
+			Name:      name,
+			PublicKey: pubKeyProvider.PublicKey().Marshal(),
+		}, nil
+	}
+
+	pbAccount := &pb.DistributedAccount{
+		Uuid:               uuid,
+		Name:               name,
+		PublicKey:          pubKeyProvider.PublicKey().Marshal(),
+		CompositePublicKey: distributedAccount.CompositePublicKey().Marshal(),
+		SigningThreshold:   distributedAccount.SigningThreshold(),
+		Participants:       make([]*pb.Endpoint, 0),
+	}
+	for k, v := range distributedAccount.Participants() {
+		parts := strings.Split(v, ":")
+		if len(parts) != 2 {
+			log.Warn().Str("participant", v).Msg("Invalid format for participant")
+			continue
+		}
+		port, err := strconv.ParseUint(parts[1], 10, 32)
+		if err != nil {
+			log.Warn().Str("participant", v).Err(err).Msg("Invalid port for participant")
+			continue
+		}
+		pbAccount.Participants = append(pbAccount.Participants, &pb.Endpoint{
+			Id:   k,
+			Name: parts[0],
+			Port: uint32(port),
+		})
+	}
+	return nil, pbAccount
+}
@@ -0,0 +1,76 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lister
+
+import (
+	context "context"
+	"errors"
+
+	"github.com/attestantio/dirk/core"
+	dirkpb "github.com/attestantio/dirk/pb/v1"
+	"github.com/attestantio/dirk/services/api/grpc/handlers"
+	pb "github.com/wealdtech/eth2-signer-api/pb/v1"
+)
+
+// ListAccountsByWallet lists accounts, grouped by the wallet in which they reside.
+func (h *Handler) ListAccountsByWallet(ctx context.Context, req *pb.ListAccountsRequest) (*dirkpb.ListAccountsByWalletResponse, error) {
+	if req == nil {
+		log.Warn().Str("result", "denied").Msg("Request not specified")
+		return nil, errors.New("no request specified")
+	}
+
+	log.Trace().Strs("paths", req.GetPaths()).Msg("List accounts by wallet request received")
+	res := &dirkpb.ListAccountsByWalletResponse{}
+	res.Wallets = make([]*dirkpb.WalletAccounts, 0)
+
+	if h.walletLister == nil {
+		log.Warn().Msg("Lister does not support listing by wallet")
+		res.State = pb.ResponseState_FAILED
+		return res, nil
+	}
+
+	result, walletAccounts := h.walletLister.ListAccountsByWallet(ctx, handlers.GenerateCredentials(ctx), req.Paths)
+	switch result {
+	case core.ResultDenied:
+		res.State = pb.ResponseState_DENIED
+		return res, nil
+	case core.ResultUnknown, core.ResultFailed:
+		res.State = pb.ResponseState_FAILED
+		return res, nil
+	case core.ResultSucceeded:
+		for _, walletAccount := range walletAccounts {
+			pbWallet := &dirkpb.WalletAccounts{
+				Name:                walletAccount.Wallet.Name(),
+				Type:                walletAccount.Wallet.Type(),
+				AccountCount:        uint32(len(walletAccount.Accounts)),
+				Accounts:            make([]*pb.Account, 0),
+				DistributedAccounts: make([]*pb.DistributedAccount, 0),
+			}
+			for _, account := range walletAccount.Accounts {
+				pbAccount, pbDistributedAccount := accountToPB(account, account.Name())
+				if pbAccount != nil {
+					pbWallet.Accounts = append(pbWallet.Accounts, pbAccount)
+				}
+				if pbDistributedAccount != nil {
+					pbWallet.DistributedAccounts = append(pbWallet.DistributedAccounts, pbDistributedAccount)
+				}
+			}
+			res.Wallets = append(res.Wallets, pbWallet)
+		}
+	}
+
+	res.State = pb.ResponseState_SUCCEEDED
+	log.Trace().Int("wallets", len(res.Wallets)).Msg("Success")
+	return res, nil
+}
@@ -0,0 +1,118 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lister_test
+
+import (
+	context "context"
+	"testing"
+
+	"github.com/attestantio/dirk/services/api/grpc/interceptors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	pb "github.com/wealdtech/eth2-signer-api/pb/v1"
+)
+
+func TestListAccountsByWallet(t *testing.T) {
+	tests := []struct {
+		name    string
+		client  string
+		req     *pb.ListAccountsRequest
+		err     string
+		wallets map[string][]string
+	}{
+		{
+			name:   "Empty",
+			client: "client1",
+			err:    "no request specified",
+		},
+		{
+			name:    "MissingPaths",
+			client:  "client1",
+			req:     &pb.ListAccountsRequest{},
+			wallets: map[string][]string{},
+		},
+		{
+			name:   "UnknownWallet",
+			client: "client1",
+			req: &pb.ListAccountsRequest{
+				Paths: []string{"Unknown/.*"},
+			},
+			wallets: map[string][]string{},
+		},
+		{
+			name:   "All",
+			client: "client1",
+			req: &pb.ListAccountsRequest{
+				Paths: []string{"Wallet 1"},
+			},
+			wallets: map[string][]string{
+				"Wallet 1": {"Account 1", "Account 2", "Account 3", "Account 4", "A different account"},
+			},
+		},
+		{
+			name:   "DeniedClient",
+			client: "Deny this client",
+			req: &pb.ListAccountsRequest{
+				Paths: []string{"Wallet 1", "Wallet 2"},
+			},
+			wallets: map[string][]string{},
+		},
+		{
+			name:   "MultipleWallets",
+			client: "client1",
+			req: &pb.ListAccountsRequest{
+				Paths: []string{"Wallet 1/Account [0-9]+", "Wallet 2"},
+			},
+			wallets: map[string][]string{
+				"Wallet 1": {"Account 1", "Account 2", "Account 3", "Account 4"},
+				"Wallet 2": {"Account 1"},
+			},
+		},
+	}
+
+	handler, err := Setup()
+	require.Nil(t, err)
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.WithValue(context.Background(), &interceptors.ClientName{}, test.client)
+			resp, err := handler.ListAccountsByWallet(ctx, test.req)
+			if test.err == "" {
+				// Result expected.
+				require.NoError(t, err)
+				require.Equal(t, pb.ResponseState_SUCCEEDED, resp.State)
+				wallets := make(map[string][]string)
+				for _, wallet := range resp.Wallets {
+					names := make([]string, 0)
+					for _, account := range wallet.Accounts {
+						names = append(names, account.Name)
+					}
+					for _, account := range wallet.DistributedAccounts {
+						names = append(names, account.Name)
+					}
+					assert.Equal(t, uint32(len(names)), wallet.AccountCount)
+					wallets[wallet.Name] = names
+				}
+				require.Len(t, wallets, len(test.wallets))
+				for name, accounts := range test.wallets {
+					assert.ElementsMatch(t, accounts, wallets[name])
+				}
+			} else {
+				// Error expected.
+				require.NotNil(t, err)
+				assert.Equal(t, test.err, err.Error())
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package peeradmin
+
+import (
+	context "context"
+	"errors"
+	"sort"
+
+	"github.com/attestantio/dirk/core"
+	dirkpb "github.com/attestantio/dirk/pb/v1"
+	"github.com/attestantio/dirk/services/peers"
+	pb "github.com/wealdtech/eth2-signer-api/pb/v1"
+)
+
+// ListPeers lists the current peers.
+func (h *Handler) ListPeers(ctx context.Context, req *dirkpb.ListPeersRequest) (*dirkpb.PeersResponse, error) {
+	return &dirkpb.PeersResponse{
+		State: pb.ResponseState_SUCCEEDED,
+		Peers: peerEndpoints(h.peers.All()),
+	}, nil
+}
+
+// UpdatePeers adds, updates and removes peers.
+func (h *Handler) UpdatePeers(ctx context.Context, req *dirkpb.UpdatePeersRequest) (*dirkpb.PeersResponse, error) {
+	if req == nil {
+		log.Warn().Str("result", "denied").Msg("Request not specified")
+		return nil, errors.New("no request specified")
+	}
+
+	log.Trace().Int("set", len(req.GetSet())).Int("remove", len(req.GetRemove())).Msg("Update peers received")
+	res := &dirkpb.PeersResponse{}
+
+	updater, isUpdater := h.peers.(peers.Updater)
+	if !isUpdater {
+		res.State = pb.ResponseState_FAILED
+		res.Message = "peers cannot be updated"
+		res.Peers = peerEndpoints(h.peers.All())
+		return res, nil
+	}
+
+	set := make(map[uint64]string, len(req.GetSet()))
+	for _, peer := range req.GetSet() {
+		if _, exists := set[peer.GetId()]; exists {
+			res.State = pb.ResponseState_DENIED
+			res.Message = "duplicate peer ID in request"
+			res.Peers = peerEndpoints(h.peers.All())
+			return res, nil
+		}
+		set[peer.GetId()] = peer.GetAddress()
+	}
+
+	updated, err := updater.Update(ctx, set, req.GetRemove())
+	if err != nil {
+		log.Warn().Err(err).Msg("Update peers attempt failed")
+		res.State = pb.ResponseState_FAILED
+		res.Message = err.Error()
+		res.Peers = peerEndpoints(h.peers.All())
+		return res, nil
+	}
+
+	res.State = pb.ResponseState_SUCCEEDED
+	res.Peers = peerEndpoints(updated)
+	return res, nil
+}
+
+// peerEndpoints returns the given peers as protobuf endpoints, ordered by ID.
+func peerEndpoints(peers map[uint64]*core.Endpoint) []*dirkpb.PeerEndpoint {
+	res := make([]*dirkpb.PeerEndpoint, 0, len(peers))
+	for id, peer := range peers {
+		res = append(res, &dirkpb.PeerEndpoint{
+			Id:      id,
+			Address: peer.ConnectAddress(),
+		})
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Id < res[j].Id })
+	return res
+}
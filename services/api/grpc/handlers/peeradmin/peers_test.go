@@ -0,0 +1,121 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package peeradmin_test
+
+import (
+	context "context"
+	"testing"
+
+	dirkpb "github.com/attestantio/dirk/pb/v1"
+	"github.com/attestantio/dirk/services/api/grpc/handlers/peeradmin"
+	staticpeers "github.com/attestantio/dirk/services/peers/static"
+	"github.com/stretchr/testify/require"
+	pb "github.com/wealdtech/eth2-signer-api/pb/v1"
+)
+
+func TestUpdatePeers(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		req     *dirkpb.UpdatePeersRequest
+		state   pb.ResponseState
+		message string
+		peers   []*dirkpb.PeerEndpoint
+	}{
+		{
+			name:    "Nil",
+			message: "no request specified",
+		},
+		{
+			name: "Add",
+			req: &dirkpb.UpdatePeersRequest{
+				Set: []*dirkpb.PeerEndpoint{{Id: 3, Address: "peer3:1003"}},
+			},
+			state: pb.ResponseState_SUCCEEDED,
+			peers: []*dirkpb.PeerEndpoint{
+				{Id: 1, Address: "peer1:1001"},
+				{Id: 2, Address: "peer2:1002"},
+				{Id: 3, Address: "peer3:1003"},
+			},
+		},
+		{
+			name: "Replace",
+			req: &dirkpb.UpdatePeersRequest{
+				Set:    []*dirkpb.PeerEndpoint{{Id: 3, Address: "peer3:1003"}},
+				Remove: []uint64{2},
+			},
+			state: pb.ResponseState_SUCCEEDED,
+			peers: []*dirkpb.PeerEndpoint{
+				{Id: 1, Address: "peer1:1001"},
+				{Id: 3, Address: "peer3:1003"},
+			},
+		},
+		{
+			name: "DuplicateID",
+			req: &dirkpb.UpdatePeersRequest{
+				Set: []*dirkpb.PeerEndpoint{{Id: 3, Address: "peer3:1003"}, {Id: 3, Address: "peer4:1004"}},
+			},
+			state:   pb.ResponseState_DENIED,
+			message: "duplicate peer ID in request",
+			peers: []*dirkpb.PeerEndpoint{
+				{Id: 1, Address: "peer1:1001"},
+				{Id: 2, Address: "peer2:1002"},
+			},
+		},
+		{
+			name: "Invalid",
+			req: &dirkpb.UpdatePeersRequest{
+				Set: []*dirkpb.PeerEndpoint{{Id: 3, Address: "peer3:bad"}},
+			},
+			state:   pb.ResponseState_FAILED,
+			message: "malformed peer port for peer3:bad",
+			peers: []*dirkpb.PeerEndpoint{
+				{Id: 1, Address: "peer1:1001"},
+				{Id: 2, Address: "peer2:1002"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			peers, err := staticpeers.New(ctx, staticpeers.WithPeers(map[uint64]string{
+				1: "peer1:1001",
+				2: "peer2:1002",
+			}))
+			require.NoError(t, err)
+			handler, err := peeradmin.New(ctx, peeradmin.WithPeers(peers))
+			require.NoError(t, err)
+
+			resp, err := handler.UpdatePeers(ctx, test.req)
+			if test.req == nil {
+				require.EqualError(t, err, test.message)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.state, resp.State)
+			require.Equal(t, test.message, resp.Message)
+			require.Len(t, resp.Peers, len(test.peers))
+			for i := range test.peers {
+				require.Equal(t, test.peers[i].Id, resp.Peers[i].Id)
+				require.Equal(t, test.peers[i].Address, resp.Peers[i].Address)
+			}
+
+			// The listed peers match those returned.
+			list, err := handler.ListPeers(ctx, &dirkpb.ListPeersRequest{})
+			require.NoError(t, err)
+			require.Len(t, list.Peers, len(test.peers))
+		})
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -88,6 +89,41 @@ func TestAbortUnknownEndpoint(t *testing.T) {
 	require.True(t, strings.HasPrefix(err.Error(), "Failed to call Abort(): rpc error: code = Unavailable"))
 }
 
+// requestMonitor records the requests made by a sender.
+type requestMonitor struct {
+	mu       sync.Mutex
+	requests []string
+}
+
+func (m *requestMonitor) PeerRequestCompleted(started time.Time, peer uint64, rpc string, succeeded bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requests = append(m.requests, fmt.Sprintf("%d %s %t", peer, rpc, succeeded))
+}
+
+func TestSenderMonitor(t *testing.T) {
+	ctx := context.Background()
+	base, endpoints, _, err := createServers(ctx)
+	require.NoError(t, err)
+	defer os.RemoveAll(base)
+	// #nosec G404
+	accountName := fmt.Sprintf("Test/%d", rand.Int())
+	participants := endpoints[0:3]
+
+	monitor := &requestMonitor{}
+	senderSvc, err := createSender(ctx, endpoints[0].Name, base, grpcsender.WithMonitor(monitor))
+	require.NoError(t, err)
+
+	require.NoError(t, senderSvc.Prepare(ctx, participants[1], accountName, []byte("test"), 2, participants))
+	require.NoError(t, senderSvc.Abort(ctx, participants[1], accountName))
+	require.Error(t, senderSvc.Abort(ctx, participants[1], accountName))
+	require.Equal(t, []string{
+		fmt.Sprintf("%d prepare true", participants[1].ID),
+		fmt.Sprintf("%d abort true", participants[1].ID),
+		fmt.Sprintf("%d abort false", participants[1].ID),
+	}, monitor.requests)
+}
+
 func TestEndToEnd(t *testing.T) {
 	ctx := context.Background()
 	base, endpoints, _, err := createServers(ctx)
@@ -315,7 +351,7 @@ func createServer(ctx context.Context, name string, id uint64, port uint32, base
 	return serverSvc, nil
 }
 
-func createSender(ctx context.Context, name string, base string) (sender.Service, error) {
+func createSender(ctx context.Context, name string, base string, params ...grpcsender.Parameter) (sender.Service, error) {
 	certPEMBlock, err := ioutil.ReadFile(filepath.Join(base, fmt.Sprintf("%s.crt", name)))
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to obtain server certificate")
@@ -329,10 +365,10 @@ func createSender(ctx context.Context, name string, base string) (sender.Service
 		return nil, errors.Wrap(err, "failed to obtain CA certificate")
 	}
 
-	return grpcsender.New(ctx,
+	return grpcsender.New(ctx, append([]grpcsender.Parameter{
 		grpcsender.WithName(name),
 		grpcsender.WithServerCert(certPEMBlock),
 		grpcsender.WithServerKey(keyPEMBlock),
 		grpcsender.WithCACert(caPEMBlock),
-	)
+	}, params...)...)
 }
@@ -0,0 +1,81 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reshare
+
+import (
+	"errors"
+
+	"github.com/attestantio/dirk/services/peers"
+	"github.com/attestantio/dirk/services/process"
+	"github.com/rs/zerolog"
+)
+
+type parameters struct {
+	logLevel zerolog.Level
+	resharer process.Resharer
+	peers    peers.Service
+}
+
+// Parameter is the interface for service parameters.
+type Parameter interface {
+	apply(*parameters)
+}
+
+type parameterFunc func(*parameters)
+
+func (f parameterFunc) apply(p *parameters) {
+	f(p)
+}
+
+// WithLogLevel sets the log level for the module.
+func WithLogLevel(logLevel zerolog.Level) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.logLevel = logLevel
+	})
+}
+
+// WithResharer sets the resharer for this module.
+func WithResharer(resharer process.Resharer) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.resharer = resharer
+	})
+}
+
+// WithPeers sets the peers for this module.
+func WithPeers(peers peers.Service) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.peers = peers
+	})
+}
+
+// parseAndCheckParameters parses and checks parameters to ensure that mandatory parameters are present and correct.
+func parseAndCheckParameters(params ...Parameter) (*parameters, error) {
+	parameters := parameters{
+		logLevel: zerolog.GlobalLevel(),
+	}
+	for _, p := range params {
+		if params != nil {
+			p.apply(&parameters)
+		}
+	}
+
+	if parameters.resharer == nil {
+		return nil, errors.New("no resharer specified")
+	}
+	if parameters.peers == nil {
+		return nil, errors.New("no peers specified")
+	}
+
+	return &parameters, nil
+}
@@ -0,0 +1,185 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reshare
+
+import (
+	context "context"
+
+	"github.com/attestantio/dirk/core"
+	dirkpb "github.com/attestantio/dirk/pb/v1"
+	"github.com/attestantio/dirk/services/api/grpc/interceptors"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/herumi/bls-eth-go-binary/bls"
+	"github.com/pkg/errors"
+)
+
+// Prepare handles the Prepare() grpc call.
+func (h *Handler) Prepare(ctx context.Context, req *dirkpb.PrepareReshareRequest) (*empty.Empty, error) {
+	senderID := h.senderID(ctx)
+	if senderID == 0 {
+		log.Warn().Interface("client", ctx.Value(&interceptors.ClientName{})).Msg("Failed to obtain participant ID of sender")
+		return nil, errors.New("Unknown sender")
+	}
+	log.Trace().Uint64("sender_id", senderID).Msg("Preparing resharing as per request from sender")
+
+	participants := make([]*core.Endpoint, len(req.Participants))
+	for i, participant := range req.Participants {
+		participants[i] = &core.Endpoint{
+			ID:   participant.Id,
+			Name: participant.Name,
+			Port: participant.Port,
+		}
+	}
+	vVec, err := verificationVector(req.VerificationVector)
+	if err != nil {
+		log.Warn().Err(err).Msg("Received verification vector is invalid")
+		return nil, err
+	}
+	if err := h.resharer.OnPrepareReshare(ctx, senderID, req.Account, req.Passphrase, req.Threshold, participants, req.Dealers, vVec); err != nil {
+		log.Error().Err(err).Msg("Failed to prepare for resharing")
+		return nil, errors.Wrap(err, "Failed to prepare")
+	}
+
+	return &empty.Empty{}, nil
+}
+
+// Execute handles the Execute() grpc call.
+func (h *Handler) Execute(ctx context.Context, req *dirkpb.ReshareRequest) (*empty.Empty, error) {
+	senderID := h.senderID(ctx)
+	if senderID == 0 {
+		log.Warn().Interface("client", ctx.Value(&interceptors.ClientName{})).Msg("Failed to obtain participant ID of sender")
+		return nil, errors.New("Unknown sender")
+	}
+	log.Trace().Uint64("sender_id", senderID).Msg("Executing resharing as per request from sender")
+
+	if err := h.resharer.OnExecuteReshare(ctx, senderID, req.Account); err != nil {
+		log.Error().Err(err).Msg("Failed to execute resharing")
+		return nil, errors.Wrap(err, "Failed to execute")
+	}
+
+	return &empty.Empty{}, nil
+}
+
+// Contribute handles the Contribute() grpc call.
+func (h *Handler) Contribute(ctx context.Context, req *dirkpb.ContributeReshareRequest) (*empty.Empty, error) {
+	senderID := h.senderID(ctx)
+	if senderID == 0 {
+		log.Warn().Interface("client", ctx.Value(&interceptors.ClientName{})).Msg("Failed to obtain participant ID of sender")
+		return nil, errors.New("Unknown sender")
+	}
+
+	log := log.With().Str("account", req.Account).Uint64("peer", senderID).Logger()
+
+	secret := bls.SecretKey{}
+	if err := secret.Deserialize(req.Secret); err != nil {
+		log.Warn().Err(err).Msg("Received secret key is invalid")
+		return nil, errors.New("Invalid secret key")
+	}
+	vVec, err := verificationVector(req.VerificationVector)
+	if err != nil {
+		log.Warn().Err(err).Msg("Received verification vector is invalid")
+		return nil, err
+	}
+
+	if err := h.resharer.OnContributeReshare(ctx, senderID, req.Account, secret, vVec); err != nil {
+		log.Error().Err(err).Msg("Failed to handle resharing contribution")
+		return nil, errors.Wrap(err, "Failed to handle contribution")
+	}
+
+	return &empty.Empty{}, nil
+}
+
+// Confirm handles the Confirm() grpc call.
+func (h *Handler) Confirm(ctx context.Context, req *dirkpb.ConfirmReshareRequest) (*dirkpb.ConfirmReshareResponse, error) {
+	senderID := h.senderID(ctx)
+	if senderID == 0 {
+		log.Warn().Interface("client", ctx.Value(&interceptors.ClientName{})).Msg("Failed to obtain participant ID of sender")
+		return nil, errors.New("Unknown sender")
+	}
+	log.Trace().Uint64("sender_id", senderID).Msg("Confirming resharing as per request from sender")
+
+	pubKey, confirmationSig, err := h.resharer.OnConfirmReshare(ctx, senderID, req.Account, req.ConfirmationData)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to confirm resharing")
+		return nil, errors.Wrap(err, "Failed to confirm")
+	}
+
+	return &dirkpb.ConfirmReshareResponse{
+		PublicKey:             pubKey,
+		ConfirmationSignature: confirmationSig,
+	}, nil
+}
+
+// Commit handles the Commit() grpc call.
+func (h *Handler) Commit(ctx context.Context, req *dirkpb.ReshareRequest) (*empty.Empty, error) {
+	senderID := h.senderID(ctx)
+	if senderID == 0 {
+		log.Warn().Interface("client", ctx.Value(&interceptors.ClientName{})).Msg("Failed to obtain participant ID of sender")
+		return nil, errors.New("Unknown sender")
+	}
+	log.Trace().Uint64("sender_id", senderID).Msg("Committing resharing as per request from sender")
+
+	if err := h.resharer.OnCommitReshare(ctx, senderID, req.Account); err != nil {
+		log.Error().Err(err).Msg("Failed to commit resharing")
+		return nil, errors.Wrap(err, "Failed to commit")
+	}
+
+	return &empty.Empty{}, nil
+}
+
+// Complete handles the Complete() grpc call.
+func (h *Handler) Complete(ctx context.Context, req *dirkpb.ReshareRequest) (*empty.Empty, error) {
+	senderID := h.senderID(ctx)
+	if senderID == 0 {
+		log.Warn().Interface("client", ctx.Value(&interceptors.ClientName{})).Msg("Failed to obtain participant ID of sender")
+		return nil, errors.New("Unknown sender")
+	}
+	log.Trace().Uint64("sender_id", senderID).Msg("Completing resharing as per request from sender")
+
+	if err := h.resharer.OnCompleteReshare(ctx, senderID, req.Account); err != nil {
+		log.Error().Err(err).Msg("Failed to complete resharing")
+		return nil, errors.Wrap(err, "Failed to complete")
+	}
+
+	return &empty.Empty{}, nil
+}
+
+// Abort handles the Abort() grpc call.
+func (h *Handler) Abort(ctx context.Context, req *dirkpb.ReshareRequest) (*empty.Empty, error) {
+	senderID := h.senderID(ctx)
+	if senderID == 0 {
+		log.Warn().Interface("client", ctx.Value(&interceptors.ClientName{})).Msg("Failed to obtain participant ID of sender")
+		return nil, errors.New("Unknown sender")
+	}
+	log.Trace().Uint64("sender_id", senderID).Msg("Aborting resharing as per request from sender")
+
+	if err := h.resharer.OnAbortReshare(ctx, senderID, req.Account); err != nil {
+		log.Error().Err(err).Msg("Failed to abort resharing")
+		return nil, errors.Wrap(err, "Failed to abort")
+	}
+
+	return &empty.Empty{}, nil
+}
+
+// verificationVector deserializes a verification vector.
+func verificationVector(data [][]byte) ([]bls.PublicKey, error) {
+	vVec := make([]bls.PublicKey, len(data))
+	for i, key := range data {
+		vVec[i] = bls.PublicKey{}
+		if err := vVec[i].Deserialize(key); err != nil {
+			return nil, errors.Wrap(err, "Invalid verification vector")
+		}
+	}
+	return vVec, nil
+}
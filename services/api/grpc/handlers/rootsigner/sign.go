@@ -0,0 +1,72 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rootsigner
+
+import (
+	context "context"
+	"strings"
+
+	"github.com/attestantio/dirk/core"
+	dirkpb "github.com/attestantio/dirk/pb/v1"
+	"github.com/attestantio/dirk/rules"
+	"github.com/attestantio/dirk/services/api/grpc/handlers"
+	"github.com/attestantio/dirk/services/signer"
+	pb "github.com/wealdtech/eth2-signer-api/pb/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// SignRoot signs a signing root.
+func (h *Handler) SignRoot(ctx context.Context, req *dirkpb.SignRootRequest) (*pb.SignResponse, error) {
+	log.Trace().Msg("Handling request")
+
+	res := &pb.SignResponse{}
+	if req == nil {
+		log.Warn().Str("result", "denied").Msg("Request not specified")
+		res.State = pb.ResponseState_DENIED
+		return res, nil
+	}
+	if req.GetAccount() == "" && req.GetPublicKey() == nil {
+		log.Warn().Str("result", "denied").Msg("Neither account nor public key specified")
+		res.State = pb.ResponseState_DENIED
+		return res, nil
+	}
+	if req.GetAccount() != "" && !strings.Contains(req.GetAccount(), "/") {
+		log.Warn().Str("result", "denied").Msg("Invalid account specified")
+		res.State = pb.ResponseState_DENIED
+		return res, nil
+	}
+
+	data := &rules.SignRootData{
+		SigningRoot: req.SigningRoot,
+	}
+	result, signature := h.rootSigner.SignRoot(ctx, handlers.GenerateCredentials(ctx), req.GetAccount(), req.GetPublicKey(), data)
+	switch result {
+	case core.ResultSucceeded:
+		res.State = pb.ResponseState_SUCCEEDED
+		res.Signature = signature
+	case core.ResultDenied:
+		res.State = pb.ResponseState_DENIED
+	case core.ResultFailed:
+		res.State = pb.ResponseState_FAILED
+	case core.ResultResourceExhausted:
+		// There is no response state for this, so return an error to allow the client to retry.
+		log.Debug().Str("result", "resource_exhausted").Msg("Signing capacity exhausted")
+		return nil, status.Error(codes.ResourceExhausted, signer.ErrCapacityExhausted.Error())
+	default:
+		res.State = pb.ResponseState_UNKNOWN
+	}
+
+	return res, nil
+}
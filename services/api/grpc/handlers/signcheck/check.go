@@ -0,0 +1,141 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signcheck
+
+import (
+	context "context"
+	"strings"
+
+	"github.com/attestantio/dirk/core"
+	dirkpb "github.com/attestantio/dirk/pb/v1"
+	"github.com/attestantio/dirk/rules"
+	"github.com/attestantio/dirk/services/api/grpc/handlers"
+	pb "github.com/wealdtech/eth2-signer-api/pb/v1"
+)
+
+// CheckSign checks if a request to sign generic data would be approved.
+func (h *Handler) CheckSign(ctx context.Context, req *pb.SignRequest) (*dirkpb.CheckResponse, error) {
+	log.Trace().Msg("Handling request")
+
+	if req == nil {
+		return denied("Request not specified"), nil
+	}
+	if reason := checkID(req.GetAccount(), req.GetPublicKey()); reason != "" {
+		return denied(reason), nil
+	}
+
+	data := &rules.SignData{
+		Domain: req.Domain,
+		Data:   req.Data,
+	}
+	return response(h.signChecker.CheckSignGeneric(ctx, handlers.GenerateCredentials(ctx), req.GetAccount(), req.GetPublicKey(), data)), nil
+}
+
+// CheckSignBeaconAttestation checks if a request to sign a beacon attestation would be approved.
+func (h *Handler) CheckSignBeaconAttestation(ctx context.Context, req *pb.SignBeaconAttestationRequest) (*dirkpb.CheckResponse, error) {
+	log.Trace().Msg("Handling request")
+
+	if req == nil {
+		return denied("Request not specified"), nil
+	}
+	if reason := checkID(req.GetAccount(), req.GetPublicKey()); reason != "" {
+		return denied(reason), nil
+	}
+	if req.Data == nil {
+		return denied("Request data not specified"), nil
+	}
+	if req.Data.Source == nil {
+		return denied("Request source checkpoint not specified"), nil
+	}
+	if req.Data.Target == nil {
+		return denied("Request target checkpoint not specified"), nil
+	}
+
+	data := &rules.SignBeaconAttestationData{
+		Domain:          req.Domain,
+		Slot:            req.Data.Slot,
+		CommitteeIndex:  req.Data.CommitteeIndex,
+		BeaconBlockRoot: req.Data.BeaconBlockRoot,
+		Source: &rules.Checkpoint{
+			Epoch: req.Data.Source.Epoch,
+			Root:  req.Data.Source.Root,
+		},
+		Target: &rules.Checkpoint{
+			Epoch: req.Data.Target.Epoch,
+			Root:  req.Data.Target.Root,
+		},
+	}
+	return response(h.signChecker.CheckSignBeaconAttestation(ctx, handlers.GenerateCredentials(ctx), req.GetAccount(), req.GetPublicKey(), data)), nil
+}
+
+// CheckSignBeaconProposal checks if a request to sign a beacon proposal would be approved.
+func (h *Handler) CheckSignBeaconProposal(ctx context.Context, req *pb.SignBeaconProposalRequest) (*dirkpb.CheckResponse, error) {
+	log.Trace().Msg("Handling request")
+
+	if req == nil {
+		return denied("Request not specified"), nil
+	}
+	if req.Data == nil {
+		return denied("Request data not specified"), nil
+	}
+	if reason := checkID(req.GetAccount(), req.GetPublicKey()); reason != "" {
+		return denied(reason), nil
+	}
+
+	data := &rules.SignBeaconProposalData{
+		Domain:        req.Domain,
+		Slot:          req.Data.Slot,
+		ProposerIndex: req.Data.ProposerIndex,
+		ParentRoot:    req.Data.ParentRoot,
+		StateRoot:     req.Data.StateRoot,
+		BodyRoot:      req.Data.BodyRoot,
+	}
+	return response(h.signChecker.CheckSignBeaconProposal(ctx, handlers.GenerateCredentials(ctx), req.GetAccount(), req.GetPublicKey(), data)), nil
+}
+
+// checkID carries out the same checks on the account identifier as the signer handler.
+func checkID(account string, pubKey []byte) string {
+	if account == "" && pubKey == nil {
+		return "Neither account nor public key specified"
+	}
+	if !strings.Contains(account, "/") {
+		return "Invalid account specified"
+	}
+	return ""
+}
+
+func denied(reason string) *dirkpb.CheckResponse {
+	log.Debug().Str("result", "denied").Msg(reason)
+	return &dirkpb.CheckResponse{
+		State:  pb.ResponseState_DENIED,
+		Reason: reason,
+	}
+}
+
+func response(result core.Result, reason string) *dirkpb.CheckResponse {
+	res := &dirkpb.CheckResponse{
+		Reason: reason,
+	}
+	switch result {
+	case core.ResultSucceeded:
+		res.State = pb.ResponseState_SUCCEEDED
+	case core.ResultDenied, core.ResultInProgress:
+		res.State = pb.ResponseState_DENIED
+	case core.ResultFailed:
+		res.State = pb.ResponseState_FAILED
+	default:
+		res.State = pb.ResponseState_UNKNOWN
+	}
+	return res
+}
@@ -0,0 +1,181 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signcheck_test
+
+import (
+	context "context"
+	"os"
+	"testing"
+
+	standardrules "github.com/attestantio/dirk/rules/standard"
+	"github.com/attestantio/dirk/services/api/grpc/handlers/signcheck"
+	"github.com/attestantio/dirk/services/api/grpc/interceptors"
+	mockchecker "github.com/attestantio/dirk/services/checker/mock"
+	memfetcher "github.com/attestantio/dirk/services/fetcher/mem"
+	syncmaplocker "github.com/attestantio/dirk/services/locker/syncmap"
+	"github.com/attestantio/dirk/services/ruler/golang"
+	standardsigner "github.com/attestantio/dirk/services/signer/standard"
+	localunlocker "github.com/attestantio/dirk/services/unlocker/local"
+	"github.com/attestantio/dirk/testing/accounts"
+	"github.com/stretchr/testify/require"
+	pb "github.com/wealdtech/eth2-signer-api/pb/v1"
+	e2types "github.com/wealdtech/go-eth2-types/v2"
+	e2wtypes "github.com/wealdtech/go-eth2-wallet-types/v2"
+)
+
+func TestMain(m *testing.M) {
+	if err := e2types.InitBLS(); err != nil {
+		os.Exit(1)
+	}
+	os.Exit(m.Run())
+}
+
+func TestCheckSignBeaconProposal(t *testing.T) {
+	ctx := context.Background()
+
+	store, err := accounts.Setup(ctx)
+	require.NoError(t, err)
+	locker, err := syncmaplocker.New(ctx)
+	require.NoError(t, err)
+	fetcher, err := memfetcher.New(ctx,
+		memfetcher.WithStores([]e2wtypes.Store{store}))
+	require.NoError(t, err)
+	rulesSvc, err := standardrules.New(ctx,
+		standardrules.WithStoragePath(t.TempDir()),
+	)
+	require.NoError(t, err)
+	ruler, err := golang.New(ctx,
+		golang.WithLocker(locker),
+		golang.WithRules(rulesSvc))
+	require.NoError(t, err)
+	checker, err := mockchecker.New()
+	require.NoError(t, err)
+	unlocker, err := localunlocker.New(ctx)
+	require.NoError(t, err)
+	signer, err := standardsigner.New(ctx,
+		standardsigner.WithChecker(checker),
+		standardsigner.WithFetcher(fetcher),
+		standardsigner.WithRuler(ruler),
+		standardsigner.WithUnlocker(unlocker),
+	)
+	require.NoError(t, err)
+	handler, err := signcheck.New(ctx,
+		signcheck.WithSignChecker(signer),
+	)
+	require.NoError(t, err)
+
+	domain := make([]byte, 32)
+	copy(domain, e2types.DomainBeaconProposer[:])
+	wrongDomain := make([]byte, 32)
+	copy(wrongDomain, e2types.DomainBeaconAttester[:])
+	goodData := &pb.BeaconBlockHeader{
+		Slot:       1,
+		ParentRoot: make([]byte, 32),
+		StateRoot:  make([]byte, 32),
+		BodyRoot:   make([]byte, 32),
+	}
+
+	tests := []struct {
+		name   string
+		client string
+		req    *pb.SignBeaconProposalRequest
+		state  pb.ResponseState
+		reason string
+	}{
+		{
+			name:   "Missing",
+			client: "client1",
+			state:  pb.ResponseState_DENIED,
+			reason: "Request not specified",
+		},
+		{
+			name:   "DataMissing",
+			client: "client1",
+			req: &pb.SignBeaconProposalRequest{
+				Id:     &pb.SignBeaconProposalRequest_Account{Account: "Wallet 1/Account 1"},
+				Domain: domain,
+			},
+			state:  pb.ResponseState_DENIED,
+			reason: "Request data not specified",
+		},
+		{
+			name:   "AccountInvalid",
+			client: "client1",
+			req: &pb.SignBeaconProposalRequest{
+				Id:     &pb.SignBeaconProposalRequest_Account{Account: "Bad"},
+				Domain: domain,
+				Data:   goodData,
+			},
+			state:  pb.ResponseState_DENIED,
+			reason: "Invalid account specified",
+		},
+		{
+			name:   "DeniedClient",
+			client: "Deny this client",
+			req: &pb.SignBeaconProposalRequest{
+				Id:     &pb.SignBeaconProposalRequest_Account{Account: "Wallet 1/Account 1"},
+				Domain: domain,
+				Data:   goodData,
+			},
+			state:  pb.ResponseState_DENIED,
+			reason: "Client not permitted to check requests for this account",
+		},
+		{
+			name:   "WrongDomain",
+			client: "client1",
+			req: &pb.SignBeaconProposalRequest{
+				Id:     &pb.SignBeaconProposalRequest_Account{Account: "Wallet 1/Account 1"},
+				Domain: wrongDomain,
+				Data:   goodData,
+			},
+			state:  pb.ResponseState_DENIED,
+			reason: "Not approving non-beacon proposal due to incorrect domain",
+		},
+		{
+			name:   "Good",
+			client: "client1",
+			req: &pb.SignBeaconProposalRequest{
+				Id:     &pb.SignBeaconProposalRequest_Account{Account: "Wallet 1/Account 1"},
+				Domain: domain,
+				Data:   goodData,
+			},
+			state: pb.ResponseState_SUCCEEDED,
+		},
+		{
+			name:   "GoodRepeated",
+			client: "client1",
+			req: &pb.SignBeaconProposalRequest{
+				Id:     &pb.SignBeaconProposalRequest_Account{Account: "Wallet 1/Account 1"},
+				Domain: domain,
+				Data:   goodData,
+			},
+			state: pb.ResponseState_SUCCEEDED,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.WithValue(context.Background(), &interceptors.ClientName{}, test.client)
+			resp, err := handler.CheckSignBeaconProposal(ctx, test.req)
+			require.NoError(t, err)
+			require.Equal(t, test.state, resp.State)
+			require.Equal(t, test.reason, resp.Reason)
+		})
+	}
+
+	// Checks must not have written slashing protection.
+	export, err := rulesSvc.ExportSlashingProtection(ctx)
+	require.NoError(t, err)
+	require.Empty(t, export)
+}
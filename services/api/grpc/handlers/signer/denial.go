@@ -0,0 +1,53 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signer
+
+import (
+	context "context"
+
+	"github.com/attestantio/dirk/rules"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DenialDomain is the domain of the error details returned for denied requests.
+const DenialDomain = "dirk.attestant.io"
+
+// denialError returns an error for a denied request, with the reason for the
+// denial in its details.  It returns nil if details are not enabled or no reason
+// code was recorded, in which case the denial is returned in the response as usual.
+func (h *Handler) denialError(ctx context.Context) error {
+	reason := rules.ReasonCode(ctx)
+	if reason == "" {
+		return nil
+	}
+	msg := "denied by signing rules"
+	if reason.IsSlashingProtection() {
+		msg = "denied by slashing protection"
+	}
+	log.Debug().Str("reason", string(reason)).Msg(msg)
+	if !h.denialDetails {
+		return nil
+	}
+	st, err := status.New(codes.PermissionDenied, msg).WithDetails(&errdetails.ErrorInfo{
+		Reason: string(reason),
+		Domain: DenialDomain,
+	})
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to add denial details")
+		return nil
+	}
+	return st.Err()
+}
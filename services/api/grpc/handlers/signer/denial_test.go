@@ -0,0 +1,127 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signer_test
+
+import (
+	context "context"
+	"testing"
+
+	"github.com/attestantio/dirk/core"
+	"github.com/attestantio/dirk/rules"
+	"github.com/attestantio/dirk/services/api/grpc/handlers/signer"
+	"github.com/attestantio/dirk/services/checker"
+	mocksigner "github.com/attestantio/dirk/services/signer/mock"
+	"github.com/stretchr/testify/require"
+	pb "github.com/wealdtech/eth2-signer-api/pb/v1"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// denyingSigner denies proposals with the given reason.
+type denyingSigner struct {
+	*mocksigner.Service
+	reason rules.DenialReason
+}
+
+// SignBeaconProposal denies the proposal.
+func (s *denyingSigner) SignBeaconProposal(ctx context.Context,
+	credentials *checker.Credentials,
+	accountName string,
+	pubKey []byte,
+	data *rules.SignBeaconProposalData,
+) (
+	core.Result,
+	[]byte,
+) {
+	rules.SetCodedReason(ctx, s.reason, "Denied")
+	return core.ResultDenied, nil
+}
+
+func TestDenialDetails(t *testing.T) {
+	ctx := context.Background()
+
+	req := &pb.SignBeaconProposalRequest{
+		Id: &pb.SignBeaconProposalRequest_Account{
+			Account: "Wallet 1/Account 1",
+		},
+		Data: &pb.BeaconBlockHeader{
+			Slot:       1,
+			ParentRoot: make([]byte, 32),
+			StateRoot:  make([]byte, 32),
+			BodyRoot:   make([]byte, 32),
+		},
+		Domain: make([]byte, 32),
+	}
+
+	tests := []struct {
+		name          string
+		reason        rules.DenialReason
+		denialDetails bool
+		state         pb.ResponseState
+		code          codes.Code
+		message       string
+	}{
+		{
+			name:   "Disabled",
+			reason: rules.DenialDoubleProposal,
+			state:  pb.ResponseState_DENIED,
+		},
+		{
+			name:          "NoReason",
+			denialDetails: true,
+			state:         pb.ResponseState_DENIED,
+		},
+		{
+			name:          "Enabled",
+			reason:        rules.DenialDoubleProposal,
+			denialDetails: true,
+			code:          codes.PermissionDenied,
+			message:       "denied by slashing protection",
+		},
+		{
+			name:          "NotSlashingProtection",
+			reason:        rules.DenialTargetTooFarAhead,
+			denialDetails: true,
+			code:          codes.PermissionDenied,
+			message:       "denied by signing rules",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			handler, err := signer.New(ctx,
+				signer.WithSigner(&denyingSigner{Service: mocksigner.New(), reason: test.reason}),
+				signer.WithDenialDetails(test.denialDetails),
+			)
+			require.NoError(t, err)
+
+			resp, err := handler.SignBeaconProposal(ctx, req)
+			if test.code == codes.OK {
+				require.NoError(t, err)
+				require.Equal(t, test.state, resp.State)
+				return
+			}
+			require.Error(t, err)
+			st := status.Convert(err)
+			require.Equal(t, test.code, st.Code())
+			require.Equal(t, test.message, st.Message())
+			require.Len(t, st.Details(), 1)
+			info, isInfo := st.Details()[0].(*errdetails.ErrorInfo)
+			require.True(t, isInfo)
+			require.Equal(t, string(test.reason), info.Reason)
+			require.Equal(t, signer.DenialDomain, info.Domain)
+		})
+	}
+}
@@ -26,7 +26,8 @@ import (
 // Handler is the signer handler, allowing access to signer functions through grpc.
 type Handler struct {
 	pb.UnimplementedSignerServer
-	signer signer.Service
+	signer        signer.Service
+	denialDetails bool
 }
 
 // module-wide log.
@@ -45,7 +46,8 @@ func New(ctx context.Context, params ...Parameter) (*Handler, error) {
 	}
 
 	h := &Handler{
-		signer: parameters.signer,
+		signer:        parameters.signer,
+		denialDetails: parameters.denialDetails,
 	}
 
 	return h, nil
@@ -19,7 +19,10 @@ import (
 	"github.com/attestantio/dirk/core"
 	"github.com/attestantio/dirk/rules"
 	"github.com/attestantio/dirk/services/api/grpc/handlers"
+	"github.com/attestantio/dirk/services/signer"
 	pb "github.com/wealdtech/eth2-signer-api/pb/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // Multisign signs generic data.
@@ -45,28 +48,26 @@ func (h *Handler) Multisign(ctx context.Context, req *pb.MultisignRequest) (*pb.
 		res.Responses[i] = &pb.SignResponse{State: pb.ResponseState_UNKNOWN}
 	}
 
+	// Invalid requests are rejected individually, so that they do not prevent
+	// the remainder of the batch from being signed.
+	accountNames := make([]string, len(req.Requests))
+	pubKeys := make([][]byte, len(req.Requests))
+	reqData := make([]*rules.SignData, len(req.Requests))
 	for i := range req.Requests {
-		if req.Requests[i] == nil {
-			log.Warn().Str("result", "denied").Msg("Request nil")
+		switch {
+		case req.Requests[i] == nil:
+			log.Warn().Int("entry", i).Str("result", "denied").Msg("Request nil")
 			res.Responses[i].State = pb.ResponseState_FAILED
-			return res, nil
-		}
-		if req.Requests[i].Data == nil {
-			log.Warn().Str("result", "denied").Msg("Request data not specified")
+			continue
+		case req.Requests[i].Data == nil:
+			log.Warn().Int("entry", i).Str("result", "denied").Msg("Request data not specified")
 			res.Responses[i].State = pb.ResponseState_DENIED
-			return res, nil
-		}
-		if req.Requests[i].Domain == nil {
-			log.Warn().Str("result", "denied").Msg("Request domain not specified")
+			continue
+		case req.Requests[i].Domain == nil:
+			log.Warn().Int("entry", i).Str("result", "denied").Msg("Request domain not specified")
 			res.Responses[i].State = pb.ResponseState_DENIED
-			return res, nil
+			continue
 		}
-	}
-
-	accountNames := make([]string, len(req.Requests))
-	pubKeys := make([][]byte, len(req.Requests))
-	reqData := make([]*rules.SignData, len(req.Requests))
-	for i := range req.Requests {
 		accountNames[i] = req.Requests[i].GetAccount()
 		pubKeys[i] = req.Requests[i].GetPublicKey()
 		reqData[i] = &rules.SignData{
@@ -77,6 +78,10 @@ func (h *Handler) Multisign(ctx context.Context, req *pb.MultisignRequest) (*pb.
 
 	results, signatures := h.signer.Multisign(ctx, handlers.GenerateCredentials(ctx), accountNames, pubKeys, reqData)
 	for i := range results {
+		if reqData[i] == nil {
+			// Already rejected above.
+			continue
+		}
 		switch results[i] {
 		case core.ResultSucceeded:
 			res.Responses[i].State = pb.ResponseState_SUCCEEDED
@@ -85,6 +90,10 @@ func (h *Handler) Multisign(ctx context.Context, req *pb.MultisignRequest) (*pb.
 			res.Responses[i].State = pb.ResponseState_DENIED
 		case core.ResultFailed:
 			res.Responses[i].State = pb.ResponseState_FAILED
+		case core.ResultResourceExhausted:
+			// Capacity is obtained for the request as a whole, so return an error to allow the client to retry.
+			log.Debug().Str("result", "resource_exhausted").Msg("Signing capacity exhausted")
+			return nil, status.Error(codes.ResourceExhausted, signer.ErrCapacityExhausted.Error())
 		default:
 			res.Responses[i].State = pb.ResponseState_UNKNOWN
 		}
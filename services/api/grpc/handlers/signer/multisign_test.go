@@ -213,6 +213,84 @@ func TestMultisign(t *testing.T) {
 			},
 			states: []pb.ResponseState{pb.ResponseState_SUCCEEDED, pb.ResponseState_SUCCEEDED},
 		},
+		{
+			name:   "PartialSuccess",
+			client: "client1",
+			req: &pb.MultisignRequest{
+				Requests: []*pb.SignRequest{
+					{
+						Id: &pb.SignRequest_Account{
+							Account: "Wallet 1/Account 1",
+						},
+						Data: []byte{
+							0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01,
+							0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01,
+							0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01,
+							0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01,
+						},
+						Domain: []byte{
+							0x02, 0x02, 0x02, 0x02, 0x02, 0x02, 0x02, 0x02,
+							0x02, 0x02, 0x02, 0x02, 0x02, 0x02, 0x02, 0x02,
+							0x02, 0x02, 0x02, 0x02, 0x02, 0x02, 0x02, 0x02,
+							0x02, 0x02, 0x02, 0x02, 0x02, 0x02, 0x02, 0x02,
+						},
+					},
+					nil,
+					{
+						Id: &pb.SignRequest_Account{
+							Account: "Wallet 1/Account 2",
+						},
+						Data: []byte{
+							0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01,
+							0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01,
+							0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01,
+							0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01,
+						},
+					},
+					{
+						Id: &pb.SignRequest_Account{
+							Account: "Wallet 1/Unknown",
+						},
+						Data: []byte{
+							0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01,
+							0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01,
+							0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01,
+							0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01,
+						},
+						Domain: []byte{
+							0x02, 0x02, 0x02, 0x02, 0x02, 0x02, 0x02, 0x02,
+							0x02, 0x02, 0x02, 0x02, 0x02, 0x02, 0x02, 0x02,
+							0x02, 0x02, 0x02, 0x02, 0x02, 0x02, 0x02, 0x02,
+							0x02, 0x02, 0x02, 0x02, 0x02, 0x02, 0x02, 0x02,
+						},
+					},
+					{
+						Id: &pb.SignRequest_Account{
+							Account: "Wallet 1/Account 2",
+						},
+						Data: []byte{
+							0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01,
+							0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01,
+							0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01,
+							0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01,
+						},
+						Domain: []byte{
+							0x02, 0x02, 0x02, 0x02, 0x02, 0x02, 0x02, 0x02,
+							0x02, 0x02, 0x02, 0x02, 0x02, 0x02, 0x02, 0x02,
+							0x02, 0x02, 0x02, 0x02, 0x02, 0x02, 0x02, 0x02,
+							0x02, 0x02, 0x02, 0x02, 0x02, 0x02, 0x02, 0x02,
+						},
+					},
+				},
+			},
+			states: []pb.ResponseState{
+				pb.ResponseState_SUCCEEDED,
+				pb.ResponseState_FAILED,
+				pb.ResponseState_DENIED,
+				pb.ResponseState_DENIED,
+				pb.ResponseState_SUCCEEDED,
+			},
+		},
 		{
 			name:   "SameAccountTwice",
 			client: "client1",
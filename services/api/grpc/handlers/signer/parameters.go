@@ -21,8 +21,9 @@ import (
 )
 
 type parameters struct {
-	logLevel zerolog.Level
-	signer   signer.Service
+	logLevel      zerolog.Level
+	signer        signer.Service
+	denialDetails bool
 }
 
 // Parameter is the interface for service parameters.
@@ -50,6 +51,14 @@ func WithSigner(signer signer.Service) Parameter {
 	})
 }
 
+// WithDenialDetails returns requests denied by slashing protection as errors
+// with the reason for the denial in their details.
+func WithDenialDetails(denialDetails bool) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.denialDetails = denialDetails
+	})
+}
+
 // parseAndCheckParameters parses and checks parameters to ensure that mandatory parameters are present and correct.
 func parseAndCheckParameters(params ...Parameter) (*parameters, error) {
 	parameters := parameters{
@@ -19,7 +19,10 @@ import (
 	"github.com/attestantio/dirk/core"
 	"github.com/attestantio/dirk/rules"
 	"github.com/attestantio/dirk/services/api/grpc/handlers"
+	"github.com/attestantio/dirk/services/signer"
 	pb "github.com/wealdtech/eth2-signer-api/pb/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // SignBeaconAttestations signs multiple beacon attestations.
@@ -100,6 +103,10 @@ func (h *Handler) SignBeaconAttestations(ctx context.Context, req *pb.SignBeacon
 			res.Responses[i].State = pb.ResponseState_DENIED
 		case core.ResultFailed:
 			res.Responses[i].State = pb.ResponseState_FAILED
+		case core.ResultResourceExhausted:
+			// Capacity is obtained for the request as a whole, so return an error to allow the client to retry.
+			log.Debug().Str("result", "resource_exhausted").Msg("Signing capacity exhausted")
+			return nil, status.Error(codes.ResourceExhausted, signer.ErrCapacityExhausted.Error())
 		default:
 			res.Responses[i].State = pb.ResponseState_UNKNOWN
 		}
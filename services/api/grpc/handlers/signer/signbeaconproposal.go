@@ -20,7 +20,11 @@ import (
 	"github.com/attestantio/dirk/core"
 	"github.com/attestantio/dirk/rules"
 	"github.com/attestantio/dirk/services/api/grpc/handlers"
+	"github.com/attestantio/dirk/services/ruler"
+	"github.com/attestantio/dirk/services/signer"
 	pb "github.com/wealdtech/eth2-signer-api/pb/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // SignBeaconProposal signs a proposal for a beacon block.
@@ -57,15 +61,27 @@ func (h *Handler) SignBeaconProposal(ctx context.Context, req *pb.SignBeaconProp
 		StateRoot:     req.Data.StateRoot,
 		BodyRoot:      req.Data.BodyRoot,
 	}
+	ctx = rules.WithReason(ctx)
 	result, signature := h.signer.SignBeaconProposal(ctx, handlers.GenerateCredentials(ctx), req.GetAccount(), req.GetPublicKey(), data)
 	switch result {
 	case core.ResultSucceeded:
 		res.State = pb.ResponseState_SUCCEEDED
 		res.Signature = signature
 	case core.ResultDenied:
+		if err := h.denialError(ctx); err != nil {
+			return nil, err
+		}
 		res.State = pb.ResponseState_DENIED
 	case core.ResultFailed:
 		res.State = pb.ResponseState_FAILED
+	case core.ResultResourceExhausted:
+		// There is no response state for this, so return an error to allow the client to retry.
+		log.Debug().Str("result", "resource_exhausted").Msg("Signing capacity exhausted")
+		return nil, status.Error(codes.ResourceExhausted, signer.ErrCapacityExhausted.Error())
+	case core.ResultInProgress:
+		// There is no response state for this, so return an error to make it distinct from a denial.
+		log.Debug().Str("result", "aborted").Msg("Proposal already in progress")
+		return nil, status.Error(codes.Aborted, ruler.ErrProposalInProgress.Error())
 	default:
 		res.State = pb.ResponseState_UNKNOWN
 	}
@@ -0,0 +1,148 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package taggedsigner_test
+
+import (
+	context "context"
+	"os"
+	"testing"
+
+	dirkpb "github.com/attestantio/dirk/pb/v1"
+	mockrules "github.com/attestantio/dirk/rules/mock"
+	"github.com/attestantio/dirk/services/api/grpc/handlers/taggedsigner"
+	"github.com/attestantio/dirk/services/api/grpc/interceptors"
+	mockchecker "github.com/attestantio/dirk/services/checker/mock"
+	memfetcher "github.com/attestantio/dirk/services/fetcher/mem"
+	syncmaplocker "github.com/attestantio/dirk/services/locker/syncmap"
+	"github.com/attestantio/dirk/services/ruler/golang"
+	standardsigner "github.com/attestantio/dirk/services/signer/standard"
+	localunlocker "github.com/attestantio/dirk/services/unlocker/local"
+	"github.com/attestantio/dirk/testing/accounts"
+	"github.com/stretchr/testify/require"
+	pb "github.com/wealdtech/eth2-signer-api/pb/v1"
+	e2types "github.com/wealdtech/go-eth2-types/v2"
+	e2wtypes "github.com/wealdtech/go-eth2-wallet-types/v2"
+)
+
+func TestMain(m *testing.M) {
+	if err := e2types.InitBLS(); err != nil {
+		os.Exit(1)
+	}
+	os.Exit(m.Run())
+}
+
+func TestSignTagged(t *testing.T) {
+	ctx := context.Background()
+
+	store, err := accounts.Setup(ctx)
+	require.NoError(t, err)
+	locker, err := syncmaplocker.New(ctx)
+	require.NoError(t, err)
+	fetcher, err := memfetcher.New(ctx,
+		memfetcher.WithStores([]e2wtypes.Store{store}))
+	require.NoError(t, err)
+	ruler, err := golang.New(ctx,
+		golang.WithLocker(locker),
+		golang.WithRules(mockrules.New()))
+	require.NoError(t, err)
+	checker, err := mockchecker.New()
+	require.NoError(t, err)
+	unlocker, err := localunlocker.New(ctx,
+		localunlocker.WithAccountPassphrases([]string{"Account 1 passphrase"}))
+	require.NoError(t, err)
+	signer, err := standardsigner.New(ctx,
+		standardsigner.WithChecker(checker),
+		standardsigner.WithFetcher(fetcher),
+		standardsigner.WithRuler(ruler),
+		standardsigner.WithUnlocker(unlocker),
+		standardsigner.WithMessageTags(map[string][]string{"client1": {"test-message-v1"}}),
+	)
+	require.NoError(t, err)
+	handler, err := taggedsigner.New(ctx,
+		taggedsigner.WithTaggedSigner(signer),
+	)
+	require.NoError(t, err)
+
+	root := make([]byte, 32)
+	domain := make([]byte, 32)
+
+	tests := []struct {
+		name   string
+		client string
+		req    *dirkpb.SignTaggedRequest
+		err    string
+		state  pb.ResponseState
+	}{
+		{
+			name:   "Missing",
+			client: "client1",
+			state:  pb.ResponseState_DENIED,
+		},
+		{
+			name:   "Empty",
+			client: "client1",
+			req:    &dirkpb.SignTaggedRequest{},
+			state:  pb.ResponseState_DENIED,
+		},
+		{
+			name:   "AccountInvalid",
+			client: "client1",
+			req: &dirkpb.SignTaggedRequest{
+				Id:     &dirkpb.SignTaggedRequest_Account{Account: "Wallet 1"},
+				Tag:    "test-message-v1",
+				Data:   root,
+				Domain: domain,
+			},
+			state: pb.ResponseState_DENIED,
+		},
+		{
+			name:   "TagNotPermitted",
+			client: "client1",
+			req: &dirkpb.SignTaggedRequest{
+				Id:     &dirkpb.SignTaggedRequest_Account{Account: "Wallet 1/Account 1"},
+				Tag:    "other-message-v1",
+				Data:   root,
+				Domain: domain,
+			},
+			err: "rpc error: code = PermissionDenied desc = message type tag not permitted for client",
+		},
+		{
+			name:   "Good",
+			client: "client1",
+			req: &dirkpb.SignTaggedRequest{
+				Id:     &dirkpb.SignTaggedRequest_Account{Account: "Wallet 1/Account 1"},
+				Tag:    "test-message-v1",
+				Data:   root,
+				Domain: domain,
+			},
+			state: pb.ResponseState_SUCCEEDED,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.WithValue(context.Background(), &interceptors.ClientName{}, test.client)
+			resp, err := handler.SignTagged(ctx, test.req)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.state, resp.State)
+			if resp.State == pb.ResponseState_SUCCEEDED {
+				require.Len(t, resp.Signature, 96)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpc
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// healthServer is the standard gRPC health service, with watches that end
+// when the API server stops so that they do not hold up shutdown.
+type healthServer struct {
+	*health.Server
+	stopping chan struct{}
+}
+
+func newHealthServer() *healthServer {
+	s := &healthServer{
+		Server:   health.NewServer(),
+		stopping: make(chan struct{}),
+	}
+	// Not serving until the rest of Dirk is operational.
+	s.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+	return s
+}
+
+// Watch sends the serving status to the client whenever it changes.
+func (s *healthServer) Watch(req *healthpb.HealthCheckRequest, stream healthpb.Health_WatchServer) error {
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+	go func() {
+		select {
+		case <-s.stopping:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return s.Server.Watch(req, &healthWatchStream{Health_WatchServer: stream, ctx: ctx})
+}
+
+// stop marks the service as not serving and ends any watches.
+func (s *healthServer) stop() {
+	s.Shutdown()
+	close(s.stopping)
+}
+
+// healthWatchStream is a watch stream with a context that ends when the API server stops.
+type healthWatchStream struct {
+	healthpb.Health_WatchServer
+	ctx context.Context
+}
+
+// Context returns the context of the stream.
+func (s *healthWatchStream) Context() context.Context {
+	return s.ctx
+}
+
+// SetServing sets the status reported by the health service.  The status is
+// not serving until this is called, and always not serving once the server
+// is stopping.
+func (s *Service) SetServing(serving bool) {
+	status := healthpb.HealthCheckResponse_NOT_SERVING
+	if serving {
+		status = healthpb.HealthCheckResponse_SERVING
+	}
+	s.health.SetServingStatus("", status)
+}
+
+// isHealthMethod returns true if the method is part of the health service.
+func isHealthMethod(method string) bool {
+	return strings.HasPrefix(method, "/"+healthpb.Health_ServiceDesc.ServiceName+"/")
+}
+
+// exceptHealth applies the interceptor to all requests other than health checks,
+// which must be available to load balancers that do not hold bearer tokens.
+func exceptHealth(interceptor grpc.UnaryServerInterceptor) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if isHealthMethod(info.FullMethod) {
+			return handler(ctx, req)
+		}
+		return interceptor(ctx, req, info, handler)
+	}
+}
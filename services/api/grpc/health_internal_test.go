@@ -0,0 +1,80 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestHealth(t *testing.T) {
+	s := &Service{
+		health: newHealthServer(),
+	}
+	ctx := context.Background()
+
+	resp, err := s.health.Check(ctx, &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	require.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, resp.Status)
+
+	s.SetServing(true)
+	resp, err = s.health.Check(ctx, &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	require.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.Status)
+
+	s.SetServing(false)
+	resp, err = s.health.Check(ctx, &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	require.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, resp.Status)
+
+	// Once stopped the status cannot return to serving.
+	s.SetServing(true)
+	s.health.stop()
+	s.SetServing(true)
+	resp, err = s.health.Check(ctx, &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	require.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, resp.Status)
+}
+
+func TestHealthWatch(t *testing.T) {
+	s, _, conn := startDrainServer(t)
+	client := healthpb.NewHealthClient(conn)
+
+	stream, err := client.Watch(context.Background(), &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	resp, err := stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, resp.Status)
+
+	s.SetServing(true)
+	resp, err = stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.Status)
+
+	// Stopping reports not serving and ends the watch, without holding up shutdown.
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	drained, cancelled := s.Stop(ctx)
+	require.Equal(t, 0, drained)
+	require.Equal(t, 0, cancelled)
+	resp, err = stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, resp.Status)
+	_, err = stream.Recv()
+	require.Error(t, err)
+}
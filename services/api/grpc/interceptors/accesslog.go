@@ -0,0 +1,111 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptors
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	pb "github.com/wealdtech/eth2-signer-api/pb/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// AccessLogInterceptor writes an entry to the access log for each request,
+// recording the client, the operation, the accounts that it targets and the
+// result.
+func AccessLogInterceptor(logger zerolog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		started := time.Now()
+		resp, err := handler(ctx, req)
+
+		e := logger.Log().
+			Str("method", info.FullMethod).
+			Str("operation", info.FullMethod[strings.LastIndex(info.FullMethod, "/")+1:]).
+			Dur("duration", time.Since(started))
+		if requestID, ok := ctx.Value(&RequestID{}).(string); ok {
+			e = e.Str("request_id", requestID)
+		}
+		if client, ok := ctx.Value(&ClientName{}).(string); ok {
+			e = e.Str("client", client)
+		}
+		if ip, ok := ctx.Value(&ExternalIP{}).(string); ok {
+			e = e.Str("ip", ip)
+		}
+		if targets := requestTargets(req); len(targets) > 0 {
+			e = e.Strs("targets", targets)
+		}
+		e = e.Str("code", status.Code(err).String())
+		if err == nil {
+			if states := responseStates(resp); len(states) > 0 {
+				e = e.Strs("results", states)
+			}
+		}
+		e.Send()
+
+		return resp, err
+	}
+}
+
+// requestTargets returns the accounts, public keys, wallets or paths that a
+// request targets.
+func requestTargets(req interface{}) []string {
+	switch typedReq := req.(type) {
+	case *pb.MultisignRequest:
+		targets := make([]string, 0, len(typedReq.GetRequests()))
+		for _, subReq := range typedReq.GetRequests() {
+			targets = append(targets, requestTargets(subReq)...)
+		}
+		return targets
+	case *pb.SignBeaconAttestationsRequest:
+		targets := make([]string, 0, len(typedReq.GetRequests()))
+		for _, subReq := range typedReq.GetRequests() {
+			targets = append(targets, requestTargets(subReq)...)
+		}
+		return targets
+	}
+
+	if typedReq, ok := req.(interface{ GetAccount() string }); ok && typedReq.GetAccount() != "" {
+		return []string{typedReq.GetAccount()}
+	}
+	if typedReq, ok := req.(interface{ GetPublicKey() []byte }); ok && len(typedReq.GetPublicKey()) > 0 {
+		return []string{fmt.Sprintf("%#x", typedReq.GetPublicKey())}
+	}
+	if typedReq, ok := req.(interface{ GetWallet() string }); ok && typedReq.GetWallet() != "" {
+		return []string{typedReq.GetWallet()}
+	}
+	if typedReq, ok := req.(interface{ GetPaths() []string }); ok {
+		return typedReq.GetPaths()
+	}
+	return nil
+}
+
+// responseStates returns the states of a response, with one state for each
+// request in a multiple signing response.
+func responseStates(resp interface{}) []string {
+	if typedResp, ok := resp.(*pb.MultisignResponse); ok {
+		states := make([]string, len(typedResp.GetResponses()))
+		for i, subResp := range typedResp.GetResponses() {
+			states[i] = subResp.GetState().String()
+		}
+		return states
+	}
+	if typedResp, ok := resp.(interface{ GetState() pb.ResponseState }); ok {
+		return []string{typedResp.GetState().String()}
+	}
+	return nil
+}
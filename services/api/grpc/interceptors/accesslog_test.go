@@ -0,0 +1,135 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptors_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/attestantio/dirk/services/api/grpc/interceptors"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+	pb "github.com/wealdtech/eth2-signer-api/pb/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestAccessLogInterceptor(t *testing.T) {
+	tests := []struct {
+		name    string
+		method  string
+		req     interface{}
+		resp    interface{}
+		err     error
+		entries map[string]interface{}
+	}{
+		{
+			name:   "SignAccount",
+			method: "/v1.Signer/Sign",
+			req:    &pb.SignRequest{Id: &pb.SignRequest_Account{Account: "Wallet1/Account1"}},
+			resp:   &pb.SignResponse{State: pb.ResponseState_SUCCEEDED},
+			entries: map[string]interface{}{
+				"operation": "Sign",
+				"targets":   []interface{}{"Wallet1/Account1"},
+				"code":      "OK",
+				"results":   []interface{}{"SUCCEEDED"},
+			},
+		},
+		{
+			name:   "SignPublicKey",
+			method: "/v1.Signer/SignBeaconProposal",
+			req:    &pb.SignBeaconProposalRequest{Id: &pb.SignBeaconProposalRequest_PublicKey{PublicKey: []byte{0x01, 0x02}}},
+			resp:   &pb.SignResponse{State: pb.ResponseState_DENIED},
+			entries: map[string]interface{}{
+				"operation": "SignBeaconProposal",
+				"targets":   []interface{}{"0x0102"},
+				"code":      "OK",
+				"results":   []interface{}{"DENIED"},
+			},
+		},
+		{
+			name:   "Multisign",
+			method: "/v1.Signer/Multisign",
+			req: &pb.MultisignRequest{Requests: []*pb.SignRequest{
+				{Id: &pb.SignRequest_Account{Account: "Wallet1/Account1"}},
+				{Id: &pb.SignRequest_Account{Account: "Wallet1/Account2"}},
+			}},
+			resp: &pb.MultisignResponse{Responses: []*pb.SignResponse{
+				{State: pb.ResponseState_SUCCEEDED},
+				{State: pb.ResponseState_DENIED},
+			}},
+			entries: map[string]interface{}{
+				"operation": "Multisign",
+				"targets":   []interface{}{"Wallet1/Account1", "Wallet1/Account2"},
+				"code":      "OK",
+				"results":   []interface{}{"SUCCEEDED", "DENIED"},
+			},
+		},
+		{
+			name:   "Wallet",
+			method: "/v1.WalletManager/Lock",
+			req:    &pb.LockWalletRequest{Wallet: "Wallet1"},
+			resp:   &pb.LockWalletResponse{State: pb.ResponseState_SUCCEEDED},
+			entries: map[string]interface{}{
+				"operation": "Lock",
+				"targets":   []interface{}{"Wallet1"},
+				"code":      "OK",
+				"results":   []interface{}{"SUCCEEDED"},
+			},
+		},
+		{
+			name:   "Refused",
+			method: "/v1.Signer/Sign",
+			req:    &pb.SignRequest{Id: &pb.SignRequest_Account{Account: "Wallet1/Account1"}},
+			err:    status.Error(codes.ResourceExhausted, "Rate limit exceeded"),
+			entries: map[string]interface{}{
+				"operation": "Sign",
+				"targets":   []interface{}{"Wallet1/Account1"},
+				"code":      "ResourceExhausted",
+				"results":   nil,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			output := &bytes.Buffer{}
+			interceptor := interceptors.AccessLogInterceptor(zerolog.New(output))
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				return test.resp, test.err
+			}
+			ctx := context.WithValue(context.Background(), &interceptors.ClientName{}, "client1")
+			ctx = context.WithValue(ctx, &interceptors.ExternalIP{}, "10.0.0.1")
+			resp, err := interceptor(ctx, test.req, &grpc.UnaryServerInfo{FullMethod: test.method}, handler)
+			require.Equal(t, test.err, err)
+			require.Equal(t, test.resp, resp)
+
+			lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+			require.Len(t, lines, 1)
+			entry := make(map[string]interface{})
+			require.NoError(t, json.Unmarshal([]byte(lines[0]), &entry))
+			require.Equal(t, test.method, entry["method"])
+			require.Equal(t, "client1", entry["client"])
+			require.Equal(t, "10.0.0.1", entry["ip"])
+			require.Contains(t, entry, "duration")
+			for k, v := range test.entries {
+				require.Equal(t, v, entry[k], k)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptors
+
+import (
+	"context"
+	"encoding/hex"
+	"strings"
+
+	"github.com/attestantio/dirk/services/adminauth"
+	zerologger "github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// AdminNonceKey is the metadata key for the nonce of an administrative request.
+	AdminNonceKey = "dirk-admin-nonce"
+	// AdminSignatureKey is the metadata key for the signature of an administrative request.
+	AdminSignatureKey = "dirk-admin-signature"
+)
+
+// AdminAuthInterceptor requires requests to administrative methods to present a
+// nonce issued by the admin auth service, signed by an admin key.  Requests to
+// other methods are passed through unchanged.
+func AdminAuthInterceptor(adminAuth adminauth.Service, isAdminMethod func(method string) bool) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !isAdminMethod(info.FullMethod) {
+			return handler(ctx, req)
+		}
+
+		nonce, signature, err := adminChallengeResponse(ctx)
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, "Admin challenge response required")
+		}
+		if err := adminAuth.Verify(ctx, info.FullMethod, nonce, signature); err != nil {
+			zerologger.Warn().Str("rpc", info.FullMethod).Err(err).Msg("Admin challenge response rejected")
+			return nil, status.Error(codes.Unauthenticated, "Invalid admin challenge response")
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// adminChallengeResponse returns the nonce and signature from the request metadata.
+func adminChallengeResponse(ctx context.Context) ([]byte, []byte, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, nil, status.Error(codes.Unauthenticated, "no metadata")
+	}
+	nonce, err := hexMetadata(md, AdminNonceKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	signature, err := hexMetadata(md, AdminSignatureKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return nonce, signature, nil
+}
+
+// hexMetadata returns the single hex-encoded value of a metadata key.
+func hexMetadata(md metadata.MD, key string) ([]byte, error) {
+	values := md.Get(key)
+	if len(values) != 1 {
+		return nil, status.Errorf(codes.Unauthenticated, "expected a single value for %s", key)
+	}
+	return hex.DecodeString(strings.TrimPrefix(values[0], "0x"))
+}
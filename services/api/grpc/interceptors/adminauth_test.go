@@ -0,0 +1,159 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptors_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"testing"
+
+	standardadminauth "github.com/attestantio/dirk/services/adminauth/standard"
+	"github.com/attestantio/dirk/services/api/grpc/interceptors"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestAdminAuthInterceptor(t *testing.T) {
+	ctx := context.Background()
+	pubKey, privKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	adminAuth, err := standardadminauth.New(ctx,
+		standardadminauth.WithLogLevel(zerolog.Disabled),
+		standardadminauth.WithPublicKeys([]string{hex.EncodeToString(pubKey)}),
+	)
+	require.NoError(t, err)
+
+	adminMethod := "/dirk.v1.AccountAdmin/Delete"
+	isAdminMethod := func(method string) bool {
+		return method == adminMethod
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "handled", nil
+	}
+	interceptor := interceptors.AdminAuthInterceptor(adminAuth, isAdminMethod)
+
+	sign := func(nonce []byte, method string) string {
+		return hex.EncodeToString(ed25519.Sign(privKey, append(append([]byte{}, nonce...), []byte(method)...)))
+	}
+	challenge := func() []byte {
+		nonce, _, err := adminAuth.Challenge(ctx)
+		require.NoError(t, err)
+		return nonce
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		md     func() metadata.MD
+		code   codes.Code
+	}{
+		{
+			name:   "NonAdminMethod",
+			method: "/v1.Signer/Sign",
+		},
+		{
+			name:   "NoMetadata",
+			method: adminMethod,
+			code:   codes.Unauthenticated,
+		},
+		{
+			name:   "SignatureMissing",
+			method: adminMethod,
+			md: func() metadata.MD {
+				return metadata.Pairs(interceptors.AdminNonceKey, hex.EncodeToString(challenge()))
+			},
+			code: codes.Unauthenticated,
+		},
+		{
+			name:   "SignatureInvalid",
+			method: adminMethod,
+			md: func() metadata.MD {
+				nonce := challenge()
+				return metadata.Pairs(interceptors.AdminNonceKey, hex.EncodeToString(nonce),
+					interceptors.AdminSignatureKey, sign(nonce, "/dirk.v1.AccountAdmin/Other"))
+			},
+			code: codes.Unauthenticated,
+		},
+		{
+			name:   "UnknownNonce",
+			method: adminMethod,
+			md: func() metadata.MD {
+				nonce := make([]byte, 32)
+				return metadata.Pairs(interceptors.AdminNonceKey, hex.EncodeToString(nonce),
+					interceptors.AdminSignatureKey, sign(nonce, adminMethod))
+			},
+			code: codes.Unauthenticated,
+		},
+		{
+			name:   "Good",
+			method: adminMethod,
+			md: func() metadata.MD {
+				nonce := challenge()
+				return metadata.Pairs(interceptors.AdminNonceKey, hex.EncodeToString(nonce),
+					interceptors.AdminSignatureKey, sign(nonce, adminMethod))
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			if test.md != nil {
+				ctx = metadata.NewIncomingContext(ctx, test.md())
+			}
+			res, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: test.method}, handler)
+			if test.code != codes.OK {
+				require.Equal(t, test.code, status.Code(err))
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, "handled", res)
+			}
+		})
+	}
+}
+
+func TestAdminAuthInterceptorReplay(t *testing.T) {
+	ctx := context.Background()
+	pubKey, privKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	adminAuth, err := standardadminauth.New(ctx,
+		standardadminauth.WithLogLevel(zerolog.Disabled),
+		standardadminauth.WithPublicKeys([]string{hex.EncodeToString(pubKey)}),
+	)
+	require.NoError(t, err)
+
+	method := "/dirk.v1.AccountAdmin/Delete"
+	interceptor := interceptors.AdminAuthInterceptor(adminAuth, func(string) bool { return true })
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, nil
+	}
+
+	nonce, _, err := adminAuth.Challenge(ctx)
+	require.NoError(t, err)
+	signature := ed25519.Sign(privKey, append(append([]byte{}, nonce...), []byte(method)...))
+	reqCtx := metadata.NewIncomingContext(ctx, metadata.Pairs(
+		interceptors.AdminNonceKey, hex.EncodeToString(nonce),
+		interceptors.AdminSignatureKey, hex.EncodeToString(signature),
+	))
+
+	_, err = interceptor(reqCtx, nil, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+	require.NoError(t, err)
+	_, err = interceptor(reqCtx, nil, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+}
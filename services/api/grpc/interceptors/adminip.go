@@ -0,0 +1,48 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptors
+
+import (
+	"context"
+
+	zerologger "github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// AdminIPInterceptor only allows requests to administrative methods from the
+// given IP addresses.  An empty list of addresses denies all administrative
+// requests unless allowAll is set, in which case requests from any address are
+// allowed.  Requests to other methods are passed through unchanged.
+// This must run after SourceIPInterceptor.
+func AdminIPInterceptor(adminIPs []string, allowAll bool, isAdminMethod func(method string) bool) grpc.UnaryServerInterceptor {
+	permitted := make(map[string]bool, len(adminIPs))
+	for _, ip := range adminIPs {
+		permitted[ip] = true
+	}
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !isAdminMethod(info.FullMethod) || allowAll {
+			return handler(ctx, req)
+		}
+
+		ip, ok := ctx.Value(&ExternalIP{}).(string)
+		if !ok || !permitted[ip] {
+			zerologger.Warn().Str("rpc", info.FullMethod).Str("request_ip", ip).Msg("Administrative request from unapproved IP address")
+			return nil, status.Error(codes.PermissionDenied, "Administrative requests not permitted from this address")
+		}
+
+		return handler(ctx, req)
+	}
+}
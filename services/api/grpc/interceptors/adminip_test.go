@@ -0,0 +1,104 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptors_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/attestantio/dirk/services/api/grpc/interceptors"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestAdminIPInterceptor(t *testing.T) {
+	adminMethod := "/dirk.v1.AccountAdmin/Delete"
+	isAdminMethod := func(method string) bool {
+		return method == adminMethod
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "handled", nil
+	}
+	withIP := func(ip string) context.Context {
+		return context.WithValue(context.Background(), &interceptors.ExternalIP{}, ip)
+	}
+
+	tests := []struct {
+		name     string
+		adminIPs []string
+		allowAll bool
+		method   string
+		ctx      context.Context
+		code     codes.Code
+	}{
+		{
+			name:   "NonAdminMethod",
+			method: "/v1.Signer/Sign",
+			ctx:    withIP("1.2.3.4"),
+		},
+		{
+			name:   "EmptyDenied",
+			method: adminMethod,
+			ctx:    withIP("1.2.3.4"),
+			code:   codes.PermissionDenied,
+		},
+		{
+			name:     "PopulatedApproved",
+			adminIPs: []string{"1.2.3.4", "5.6.7.8"},
+			method:   adminMethod,
+			ctx:      withIP("5.6.7.8"),
+		},
+		{
+			name:     "PopulatedUnapproved",
+			adminIPs: []string{"1.2.3.4", "5.6.7.8"},
+			method:   adminMethod,
+			ctx:      withIP("2.3.4.5"),
+			code:     codes.PermissionDenied,
+		},
+		{
+			name:     "PopulatedNoIP",
+			adminIPs: []string{"1.2.3.4"},
+			method:   adminMethod,
+			ctx:      context.Background(),
+			code:     codes.PermissionDenied,
+		},
+		{
+			name:     "AllowAll",
+			allowAll: true,
+			method:   adminMethod,
+			ctx:      withIP("2.3.4.5"),
+		},
+		{
+			name:     "AllowAllNoIP",
+			allowAll: true,
+			method:   adminMethod,
+			ctx:      context.Background(),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			interceptor := interceptors.AdminIPInterceptor(test.adminIPs, test.allowAll, isAdminMethod)
+			res, err := interceptor(test.ctx, nil, &grpc.UnaryServerInfo{FullMethod: test.method}, handler)
+			if test.code == codes.OK {
+				require.NoError(t, err)
+				require.Equal(t, "handled", res)
+			} else {
+				require.Equal(t, test.code, status.Code(err))
+			}
+		})
+	}
+}
@@ -18,8 +18,6 @@ import (
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/credentials"
-	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 )
 
@@ -27,20 +25,25 @@ import (
 type ClientName struct{}
 
 // ClientInfoInterceptor adds the client certificate common name to incoming requests.
+// If the client did not present a certificate and the listener on which the
+// connection arrived has a default client then the default client is used in its
+// place.
 func ClientInfoInterceptor() grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
-		grpcPeer, ok := peer.FromContext(ctx)
+		tlsInfo, policy, ok := peerAuth(ctx)
 		if !ok {
 			return nil, status.Error(codes.Internal, "Failure")
 		}
 
 		newCtx := ctx
-		authState := grpcPeer.AuthInfo.(credentials.TLSInfo).State
+		authState := tlsInfo.State
 		if authState.HandshakeComplete {
 			peerCerts := authState.PeerCertificates
 			if len(peerCerts) > 0 {
 				peerCert := peerCerts[0]
 				newCtx = context.WithValue(ctx, &ClientName{}, peerCert.Subject.CommonName)
+			} else if policy.DefaultClient != "" {
+				newCtx = context.WithValue(ctx, &ClientName{}, policy.DefaultClient)
 			}
 		}
 		return handler(newCtx, req)
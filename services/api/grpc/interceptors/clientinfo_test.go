@@ -0,0 +1,110 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptors_test
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"testing"
+
+	"github.com/attestantio/dirk/services/api/grpc/interceptors"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func TestClientInfoInterceptor(t *testing.T) {
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		client, _ := ctx.Value(&interceptors.ClientName{}).(string)
+		return client, nil
+	}
+	certState := tls.ConnectionState{
+		HandshakeComplete: true,
+		PeerCertificates: []*x509.Certificate{
+			{Subject: pkix.Name{CommonName: "certclient"}},
+		},
+	}
+	noCertState := tls.ConnectionState{
+		HandshakeComplete: true,
+	}
+
+	tests := []struct {
+		name     string
+		authInfo credentials.AuthInfo
+		client   string
+		code     codes.Code
+	}{
+		{
+			name:     "Certificate",
+			authInfo: credentials.TLSInfo{State: certState},
+			client:   "certclient",
+		},
+		{
+			name:     "NoCertificateNoPolicy",
+			authInfo: credentials.TLSInfo{State: noCertState},
+		},
+		{
+			name: "CertificateWithDefault",
+			authInfo: interceptors.ListenerAuthInfo{
+				TLSInfo: credentials.TLSInfo{State: certState},
+				Policy:  &interceptors.ListenerPolicy{DefaultClient: "default"},
+			},
+			client: "certclient",
+		},
+		{
+			name: "NoCertificateWithDefault",
+			authInfo: interceptors.ListenerAuthInfo{
+				TLSInfo: credentials.TLSInfo{State: noCertState},
+				Policy:  &interceptors.ListenerPolicy{DefaultClient: "default"},
+			},
+			client: "default",
+		},
+		{
+			name: "NoCertificateWithoutDefault",
+			authInfo: interceptors.ListenerAuthInfo{
+				TLSInfo: credentials.TLSInfo{State: noCertState},
+				Policy:  &interceptors.ListenerPolicy{},
+			},
+		},
+		{
+			name: "NoCertificateNilPolicy",
+			authInfo: interceptors.ListenerAuthInfo{
+				TLSInfo: credentials.TLSInfo{State: noCertState},
+			},
+		},
+		{
+			name: "NoAuthInfo",
+			code: codes.Internal,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := peer.NewContext(context.Background(), &peer.Peer{AuthInfo: test.authInfo})
+			interceptor := interceptors.ClientInfoInterceptor()
+			res, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/test"}, handler)
+			if test.code != codes.OK {
+				require.Equal(t, test.code, status.Code(err))
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, test.client, res)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptors
+
+import (
+	"context"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+// ListenerPolicy is the authentication policy of the listener on which a
+// connection arrived.
+type ListenerPolicy struct {
+	// DefaultClient is the client name given to requests that do not present
+	// a client certificate.
+	DefaultClient string
+	// TokenAuth is true if bearer tokens are checked.
+	TokenAuth bool
+	// TokenRequired is true if requests must present a valid bearer token.
+	TokenRequired bool
+}
+
+// ListenerAuthInfo is the authentication information for a connection, along
+// with the policy of the listener on which it arrived.
+type ListenerAuthInfo struct {
+	credentials.TLSInfo
+	Policy *ListenerPolicy
+}
+
+// peerAuth returns the TLS information and listener policy of the peer for the
+// request.  Connections without a listener policy are given an empty policy,
+// so that they receive neither a default client nor token authentication.
+func peerAuth(ctx context.Context) (credentials.TLSInfo, *ListenerPolicy, bool) {
+	grpcPeer, ok := peer.FromContext(ctx)
+	if !ok {
+		return credentials.TLSInfo{}, nil, false
+	}
+	switch authInfo := grpcPeer.AuthInfo.(type) {
+	case ListenerAuthInfo:
+		if authInfo.Policy == nil {
+			return authInfo.TLSInfo, &ListenerPolicy{}, true
+		}
+		return authInfo.TLSInfo, authInfo.Policy, true
+	case credentials.TLSInfo:
+		return authInfo, &ListenerPolicy{}, true
+	default:
+		return credentials.TLSInfo{}, nil, false
+	}
+}
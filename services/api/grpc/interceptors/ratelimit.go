@@ -0,0 +1,112 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptors
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/attestantio/dirk/services/metrics"
+	zerologger "github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// tokenBucket holds the state of the rate limit for a single client.
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// rateLimiter holds token buckets for clients.
+type rateLimiter struct {
+	mu          sync.Mutex
+	defaultRate float64
+	clientRates map[string]float64
+	buckets     map[string]*tokenBucket
+}
+
+// allow returns true if the client is within its rate limit, consuming a
+// token if so.
+func (r *rateLimiter) allow(client string, now time.Time) bool {
+	rate, exists := r.clientRates[strings.ToLower(client)]
+	if !exists {
+		rate = r.defaultRate
+	}
+	if rate <= 0 {
+		// No limit.
+		return true
+	}
+	// Allow bursts of up to a second's worth of requests.
+	burst := rate
+	if burst < 1 {
+		burst = 1
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	bucket, exists := r.buckets[client]
+	if !exists {
+		bucket = &tokenBucket{
+			tokens: burst,
+			last:   now,
+		}
+		r.buckets[client] = bucket
+	}
+	bucket.tokens += now.Sub(bucket.last).Seconds() * rate
+	if bucket.tokens > burst {
+		bucket.tokens = burst
+	}
+	bucket.last = now
+	if bucket.tokens < 1 {
+		return false
+	}
+	bucket.tokens--
+	return true
+}
+
+// RateLimitInterceptor limits the rate of requests from each client, as
+// identified by the name in its certificate.  Limits are in requests per
+// second; clients without a specific limit in clientRates use defaultRate,
+// and a limit of 0 means no limit.  Requests over the limit are rejected with
+// ResourceExhausted.  Requests to exempt methods are passed through unchanged.
+// This must run after ClientInfoInterceptor.
+func RateLimitInterceptor(defaultRate float64, clientRates map[string]float64, isExempt func(method string) bool, monitor metrics.APIMonitor) grpc.UnaryServerInterceptor {
+	limiter := &rateLimiter{
+		defaultRate: defaultRate,
+		clientRates: make(map[string]float64, len(clientRates)),
+		buckets:     make(map[string]*tokenBucket),
+	}
+	for client, rate := range clientRates {
+		// Client names are matched case-insensitively, as configuration keys are.
+		limiter.clientRates[strings.ToLower(client)] = rate
+	}
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if isExempt(info.FullMethod) {
+			return handler(ctx, req)
+		}
+
+		client, _ := ctx.Value(&ClientName{}).(string)
+		if !limiter.allow(client, time.Now()) {
+			zerologger.Debug().Str("rpc", info.FullMethod).Str("client", client).Msg("Request rate limit exceeded")
+			monitor.RequestRateLimited(client)
+			return nil, status.Error(codes.ResourceExhausted, "Request rate limit exceeded")
+		}
+
+		return handler(ctx, req)
+	}
+}
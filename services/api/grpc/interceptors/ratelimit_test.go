@@ -0,0 +1,87 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptors_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/attestantio/dirk/services/api/grpc/interceptors"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type rateLimitMonitor struct {
+	limited map[string]int
+}
+
+func (m *rateLimitMonitor) CertificateReloadCompleted(succeeded bool) {}
+
+func (m *rateLimitMonitor) RequestPanicked(rpc string) {}
+
+func (m *rateLimitMonitor) RequestRateLimited(client string) {
+	m.limited[client]++
+}
+
+func (m *rateLimitMonitor) CertificateExpiry(certificate string, notAfter time.Time) {}
+
+func TestRateLimitInterceptor(t *testing.T) {
+	monitor := &rateLimitMonitor{
+		limited: make(map[string]int),
+	}
+	isExempt := func(method string) bool {
+		return strings.HasPrefix(method, "/v1.DKG/")
+	}
+	// Limits are low enough that tokens are not replenished during the test.
+	interceptor := interceptors.RateLimitInterceptor(2.0/3600, map[string]float64{
+		"Client2": 3.0 / 3600,
+		"client3": 0,
+	}, isExempt, monitor)
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+	signInfo := &grpc.UnaryServerInfo{FullMethod: "/v1.Signer/Sign"}
+	dkgInfo := &grpc.UnaryServerInfo{FullMethod: "/v1.DKG/Prepare"}
+
+	call := func(client string, info *grpc.UnaryServerInfo) error {
+		ctx := context.WithValue(context.Background(), &interceptors.ClientName{}, client)
+		_, err := interceptor(ctx, nil, info, handler)
+		return err
+	}
+
+	// Default limit allows a burst of a single request.
+	require.NoError(t, call("client1", signInfo))
+	require.Equal(t, codes.ResourceExhausted, status.Code(call("client1", signInfo)))
+	require.Equal(t, 1, monitor.limited["client1"])
+
+	// Exempt methods are not limited.
+	for i := 0; i < 5; i++ {
+		require.NoError(t, call("client1", dkgInfo))
+	}
+
+	// Client-specific limits override the default, and are case-insensitive.
+	require.NoError(t, call("client2", signInfo))
+	require.Equal(t, codes.ResourceExhausted, status.Code(call("client2", signInfo)))
+	require.Equal(t, 1, monitor.limited["client2"])
+
+	// A limit of 0 means no limit.
+	for i := 0; i < 5; i++ {
+		require.NoError(t, call("client3", signInfo))
+	}
+	require.Zero(t, monitor.limited["client3"])
+}
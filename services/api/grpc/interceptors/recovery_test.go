@@ -0,0 +1,64 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptors_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/attestantio/dirk/services/api/grpc/interceptors"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type panicMonitor struct {
+	panics map[string]int
+}
+
+func (m *panicMonitor) CertificateReloadCompleted(succeeded bool) {}
+
+func (m *panicMonitor) RequestPanicked(rpc string) {
+	m.panics[rpc]++
+}
+
+func (m *panicMonitor) RequestRateLimited(client string) {}
+
+func (m *panicMonitor) CertificateExpiry(certificate string, notAfter time.Time) {}
+
+func TestRecoveryInterceptor(t *testing.T) {
+	monitor := &panicMonitor{
+		panics: make(map[string]int),
+	}
+	interceptor := interceptors.RecoveryInterceptor(monitor)
+	info := &grpc.UnaryServerInfo{FullMethod: "/v1.Signer/Sign"}
+
+	// A handler that does not panic is unaffected.
+	resp, err := interceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, "ok", resp)
+	require.Empty(t, monitor.panics)
+
+	// A handler that panics returns an internal error.
+	resp, err = interceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		panic("handler failure")
+	})
+	require.Nil(t, resp)
+	require.Equal(t, codes.Internal, status.Code(err))
+	require.Equal(t, 1, monitor.panics["/v1.Signer/Sign"])
+}
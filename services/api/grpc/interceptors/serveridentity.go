@@ -0,0 +1,45 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptors
+
+import (
+	"context"
+	"strconv"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// ServerNameKey is the metadata key for the name of the server that handled a request.
+	ServerNameKey = "dirk-server-name"
+	// ServerIDKey is the metadata key for the ID of the server that handled a request.
+	ServerIDKey = "dirk-server-id"
+)
+
+// ServerIdentityInterceptor adds the name and ID of the server to the header and
+// trailer of every response, including those for failed requests.
+func ServerIdentityInterceptor(name string, id uint64) grpc.UnaryServerInterceptor {
+	md := metadata.Pairs(
+		ServerNameKey, name,
+		ServerIDKey, strconv.FormatUint(id, 10),
+	)
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		// Errors are ignored, as they only occur if there is no active stream, for
+		// example when the handler is called directly.
+		_ = grpc.SetHeader(ctx, md)
+		_ = grpc.SetTrailer(ctx, md)
+		return handler(ctx, req)
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptors_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/attestantio/dirk/services/api/grpc/interceptors"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// transportStream is a server transport stream that records metadata.
+type transportStream struct {
+	header  metadata.MD
+	trailer metadata.MD
+}
+
+func (s *transportStream) Method() string {
+	return "/test"
+}
+
+func (s *transportStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func (s *transportStream) SendHeader(md metadata.MD) error {
+	return s.SetHeader(md)
+}
+
+func (s *transportStream) SetTrailer(md metadata.MD) error {
+	s.trailer = metadata.Join(s.trailer, md)
+	return nil
+}
+
+func TestServerIdentityInterceptor(t *testing.T) {
+	interceptor := interceptors.ServerIdentityInterceptor("server1.example.com", 12345)
+
+	tests := []struct {
+		name    string
+		handler grpc.UnaryHandler
+	}{
+		{
+			name: "Succeeded",
+			handler: func(ctx context.Context, req interface{}) (interface{}, error) {
+				return "ok", nil
+			},
+		},
+		{
+			name: "Failed",
+			handler: func(ctx context.Context, req interface{}) (interface{}, error) {
+				return nil, errors.New("failed")
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			stream := &transportStream{}
+			ctx := grpc.NewContextWithServerTransportStream(context.Background(), stream)
+			_, _ = interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/test"}, test.handler)
+			for _, md := range []metadata.MD{stream.header, stream.trailer} {
+				require.Equal(t, []string{"server1.example.com"}, md.Get(interceptors.ServerNameKey))
+				require.Equal(t, []string{"12345"}, md.Get(interceptors.ServerIDKey))
+			}
+		})
+	}
+}
+
+func TestServerIdentityInterceptorNoStream(t *testing.T) {
+	interceptor := interceptors.ServerIdentityInterceptor("server1.example.com", 12345)
+	res, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/test"}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, "ok", res)
+}
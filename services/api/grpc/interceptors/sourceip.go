@@ -27,18 +27,22 @@ import (
 type ExternalIP struct{}
 
 // SourceIPInterceptor adds the source IP address to incoming requests.
+// Requests over Unix sockets have no source IP address, so are passed through
+// unchanged.
 func SourceIPInterceptor() grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
 		grpcPeer, ok := peer.FromContext(ctx)
 		if !ok {
 			return nil, status.Error(codes.Internal, "Failure")
 		}
-		tcpAddr, ok := grpcPeer.Addr.(*net.TCPAddr)
-		if !ok {
+		switch addr := grpcPeer.Addr.(type) {
+		case *net.TCPAddr:
+			newCtx := context.WithValue(ctx, &ExternalIP{}, addr.IP.String())
+			return handler(newCtx, req)
+		case *net.UnixAddr:
+			return handler(ctx, req)
+		default:
 			return nil, status.Error(codes.Internal, "Failure")
 		}
-
-		newCtx := context.WithValue(ctx, &ExternalIP{}, tcpAddr.IP.String())
-		return handler(newCtx, req)
 	}
 }
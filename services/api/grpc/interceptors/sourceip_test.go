@@ -0,0 +1,69 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptors_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/attestantio/dirk/services/api/grpc/interceptors"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func TestSourceIPInterceptor(t *testing.T) {
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		ip, _ := ctx.Value(&interceptors.ExternalIP{}).(string)
+		return ip, nil
+	}
+
+	tests := []struct {
+		name string
+		addr net.Addr
+		ip   string
+		code codes.Code
+	}{
+		{
+			name: "TCP",
+			addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 12345},
+			ip:   "10.0.0.1",
+		},
+		{
+			name: "Unix",
+			addr: &net.UnixAddr{Net: "unix"},
+		},
+		{
+			name: "Unknown",
+			code: codes.Internal,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: test.addr})
+			interceptor := interceptors.SourceIPInterceptor()
+			res, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/test"}, handler)
+			if test.code != codes.OK {
+				require.Equal(t, test.code, status.Code(err))
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, test.ip, res)
+			}
+		})
+	}
+}
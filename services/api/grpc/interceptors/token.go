@@ -0,0 +1,71 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptors
+
+import (
+	"context"
+	"strings"
+
+	"github.com/attestantio/dirk/services/authenticator"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// TokenInterceptor authenticates bearer tokens on incoming requests that arrived
+// on a listener with token authentication.  If a valid token is presented then
+// the client name to which it maps replaces that obtained from the client
+// certificate.  If the listener requires tokens then requests without a valid
+// token are rejected.
+func TokenInterceptor(authenticator authenticator.Service) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		_, policy, ok := peerAuth(ctx)
+		if !ok {
+			return nil, status.Error(codes.Internal, "Failure")
+		}
+		if !policy.TokenAuth {
+			return handler(ctx, req)
+		}
+
+		token := bearerToken(ctx)
+		if token == "" {
+			if policy.TokenRequired {
+				return nil, status.Error(codes.Unauthenticated, "Bearer token required")
+			}
+			return handler(ctx, req)
+		}
+
+		client, err := authenticator.Authenticate(ctx, token)
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, "Invalid bearer token")
+		}
+
+		return handler(context.WithValue(ctx, &ClientName{}, client), req)
+	}
+}
+
+// bearerToken returns the bearer token from the request metadata, if present.
+func bearerToken(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	for _, value := range md.Get("authorization") {
+		if len(value) > 7 && strings.EqualFold(value[:7], "bearer ") {
+			return strings.TrimSpace(value[7:])
+		}
+	}
+	return ""
+}
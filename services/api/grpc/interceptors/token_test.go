@@ -0,0 +1,118 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptors_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/attestantio/dirk/services/api/grpc/interceptors"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+type authenticator struct{}
+
+func (a *authenticator) Authenticate(ctx context.Context, token string) (string, error) {
+	if token == "good" {
+		return "client1", nil
+	}
+	return "", errors.New("bad token")
+}
+
+func TestTokenInterceptor(t *testing.T) {
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		client, _ := ctx.Value(&interceptors.ClientName{}).(string)
+		return client, nil
+	}
+	baseCtx := context.WithValue(context.Background(), &interceptors.ClientName{}, "certclient")
+
+	tests := []struct {
+		name          string
+		authorization string
+		disabled      bool
+		required      bool
+		client        string
+		code          codes.Code
+	}{
+		{
+			name:          "Disabled",
+			authorization: "Bearer bad",
+			disabled:      true,
+			client:        "certclient",
+		},
+		{
+			name:   "NoTokenOptional",
+			client: "certclient",
+		},
+		{
+			name:     "NoTokenRequired",
+			required: true,
+			code:     codes.Unauthenticated,
+		},
+		{
+			name:          "NotBearer",
+			authorization: "Basic good",
+			required:      true,
+			code:          codes.Unauthenticated,
+		},
+		{
+			name:          "BadToken",
+			authorization: "Bearer bad",
+			code:          codes.Unauthenticated,
+		},
+		{
+			name:          "GoodToken",
+			authorization: "Bearer good",
+			client:        "client1",
+		},
+		{
+			name:          "GoodTokenRequired",
+			authorization: "bearer good",
+			required:      true,
+			client:        "client1",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := peer.NewContext(baseCtx, &peer.Peer{
+				AuthInfo: interceptors.ListenerAuthInfo{
+					TLSInfo: credentials.TLSInfo{},
+					Policy: &interceptors.ListenerPolicy{
+						TokenAuth:     !test.disabled,
+						TokenRequired: test.required,
+					},
+				},
+			})
+			if test.authorization != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", test.authorization))
+			}
+			interceptor := interceptors.TokenInterceptor(&authenticator{})
+			res, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/test"}, handler)
+			if test.code != codes.OK {
+				require.Equal(t, test.code, status.Code(err))
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, test.client, res)
+			}
+		})
+	}
+}
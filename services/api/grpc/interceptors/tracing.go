@@ -0,0 +1,54 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptors
+
+import (
+	"context"
+
+	"github.com/attestantio/dirk/services/tracing"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	otlog "github.com/opentracing/opentracing-go/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// TracingInterceptor starts a span for each incoming request if a tracer has
+// been configured.  If the caller supplied a span context in the request
+// metadata the span continues the caller's trace, so that requests between
+// Dirk instances appear in the trace of the request that caused them.
+func TracingInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !opentracing.IsGlobalTracerRegistered() {
+			return handler(ctx, req)
+		}
+
+		tracer := opentracing.GlobalTracer()
+		opt := opentracing.StartSpanOption(ext.SpanKindRPCServer)
+		if md, exists := metadata.FromIncomingContext(ctx); exists {
+			if parent, err := tracer.Extract(opentracing.HTTPHeaders, tracing.MetadataCarrier(md)); err == nil {
+				opt = ext.RPCServerOption(parent)
+			}
+		}
+		span := tracer.StartSpan(info.FullMethod, opt)
+		defer span.Finish()
+
+		res, err := handler(opentracing.ContextWithSpan(ctx, span), req)
+		if err != nil {
+			ext.Error.Set(span, true)
+			span.LogFields(otlog.Error(err))
+		}
+		return res, err
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptors_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/attestantio/dirk/services/api/grpc/interceptors"
+	"github.com/attestantio/dirk/services/tracing"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/mocktracer"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestTracingInterceptor(t *testing.T) {
+	tracer := mocktracer.New()
+	opentracing.SetGlobalTracer(tracer)
+	defer opentracing.SetGlobalTracer(opentracing.NoopTracer{})
+
+	interceptor := interceptors.TracingInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/v1.DKG/Prepare"}
+
+	// A span context supplied by the caller.
+	parent := tracer.StartSpan("caller").(*mocktracer.MockSpan)
+	md := metadata.MD{}
+	require.NoError(t, tracer.Inject(parent.Context(), opentracing.HTTPHeaders, tracing.MetadataCarrier(md)))
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		err      error
+		parentID int
+	}{
+		{
+			name: "NoParent",
+			ctx:  context.Background(),
+		},
+		{
+			name:     "Parent",
+			ctx:      metadata.NewIncomingContext(context.Background(), md),
+			parentID: parent.SpanContext.SpanID,
+		},
+		{
+			name:     "Error",
+			ctx:      metadata.NewIncomingContext(context.Background(), md),
+			err:      errors.New("failed"),
+			parentID: parent.SpanContext.SpanID,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tracer.Reset()
+			var handlerSpan opentracing.Span
+			_, err := interceptor(test.ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+				handlerSpan = opentracing.SpanFromContext(ctx)
+				return nil, test.err
+			})
+			require.Equal(t, test.err, err)
+
+			spans := tracer.FinishedSpans()
+			require.Len(t, spans, 1)
+			require.Equal(t, handlerSpan, spans[0])
+			require.Equal(t, "/v1.DKG/Prepare", spans[0].OperationName)
+			require.Equal(t, test.parentID, spans[0].ParentID)
+			if test.parentID != 0 {
+				require.Equal(t, parent.SpanContext.TraceID, spans[0].SpanContext.TraceID)
+			}
+			require.Equal(t, test.err != nil, spans[0].Tag("error") == true)
+		})
+	}
+}
@@ -0,0 +1,157 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpc
+
+import (
+	"context"
+	"net"
+	"os"
+	"strings"
+	"syscall"
+	"time"
+
+	proxyproto "github.com/pires/go-proxyproto"
+	"github.com/pkg/errors"
+)
+
+// defaultReusePortListeners is the maximum number of listeners opened for each
+// address with SO_REUSEPORT if the number is not configured.
+const defaultReusePortListeners = 4
+
+// maxReusePortListeners is the maximum number of listeners that can be
+// configured for each address with SO_REUSEPORT.
+const maxReusePortListeners = 64
+
+// unixAddressPrefix is the prefix of listen addresses that are Unix sockets.
+const unixAddressPrefix = "unix://"
+
+// defaultSocketMode is the file mode of Unix sockets if not configured.
+const defaultSocketMode os.FileMode = 0600
+
+// isUnixAddress returns true if the listen address is a Unix socket.
+func isUnixAddress(listenAddress string) bool {
+	return strings.HasPrefix(listenAddress, unixAddressPrefix)
+}
+
+// unixSocketPath returns the path of the Unix socket for a listen address.
+func unixSocketPath(listenAddress string) string {
+	return strings.TrimPrefix(listenAddress, unixAddressPrefix)
+}
+
+// listenUnix creates a Unix socket listener for the server.  The socket is
+// removed when the listener is closed.
+func (s *Service) listenUnix(path string, mode os.FileMode) (net.Listener, error) {
+	// Remove a socket left behind by a previous instance, but not one that
+	// is in use or a file that is not a socket.
+	if info, err := os.Lstat(path); err == nil {
+		if info.Mode()&os.ModeSocket == 0 {
+			return nil, errors.New("file exists and is not a socket")
+		}
+		if conn, err := net.DialTimeout("unix", path, time.Second); err == nil {
+			conn.Close()
+			return nil, errors.New("socket is in use")
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, errors.Wrap(err, "failed to remove stale socket")
+		}
+	}
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if mode == 0 {
+		mode = defaultSocketMode
+	}
+	if err := os.Chmod(path, mode); err != nil {
+		listener.Close()
+		return nil, errors.Wrap(err, "failed to set socket mode")
+	}
+	return listener, nil
+}
+
+// listen creates the listener for the server.
+func (s *Service) listen(listenAddress string) (net.Listener, error) {
+	listenConfig := &net.ListenConfig{}
+	if s.reusePort {
+		listenConfig.Control = func(network string, address string, c syscall.RawConn) error {
+			var sockErr error
+			if err := c.Control(func(fd uintptr) {
+				sockErr = setReusePort(fd)
+			}); err != nil {
+				return err
+			}
+			return sockErr
+		}
+	}
+	listener, err := listenConfig.Listen(context.Background(), "tcp", listenAddress)
+	if err != nil {
+		return nil, err
+	}
+	if s.listenBacklog > 0 {
+		s.setListenBacklog(listener)
+	}
+	if !s.proxyProtocol {
+		return listener, nil
+	}
+
+	// The PROXY header is read before the TLS handshake, so the address it
+	// contains is the one seen by the interceptors and rules.  Connections
+	// from trusted proxies without a header are rejected rather than falling
+	// back to the socket address, so requests are never attributed to the
+	// proxy itself.  Connections from elsewhere that send a header are
+	// rejected, so clients cannot choose the address that they appear to
+	// come from.
+	return &proxyproto.Listener{
+		Listener: listener,
+		Policy:   s.proxyPolicy,
+	}, nil
+}
+
+// proxyPolicy returns the PROXY protocol policy for a connection.
+func (s *Service) proxyPolicy(upstream net.Addr) (proxyproto.Policy, error) {
+	tcpAddr, isTCPAddr := upstream.(*net.TCPAddr)
+	if !isTCPAddr {
+		return proxyproto.REJECT, nil
+	}
+	for _, network := range s.trustedProxies {
+		if network.Contains(tcpAddr.IP) {
+			return proxyproto.REQUIRE, nil
+		}
+	}
+	return proxyproto.REJECT, nil
+}
+
+// setListenBacklog sets the backlog for the listener.  Failure is not fatal,
+// as the listener continues to work with the system default backlog.
+func (s *Service) setListenBacklog(listener net.Listener) {
+	tcpListener, isTCPListener := listener.(*net.TCPListener)
+	if !isTCPListener {
+		return
+	}
+	rawConn, err := tcpListener.SyscallConn()
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to access listener socket; using default listen backlog")
+		return
+	}
+	var sockErr error
+	if err := rawConn.Control(func(fd uintptr) {
+		sockErr = setBacklog(fd, s.listenBacklog)
+	}); err != nil {
+		sockErr = err
+	}
+	if sockErr != nil {
+		log.Warn().Err(sockErr).Msg("Failed to set listen backlog; using default listen backlog")
+	}
+}
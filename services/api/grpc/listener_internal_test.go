@@ -0,0 +1,374 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpc
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+func TestListenProxyProtocol(t *testing.T) {
+	tests := []struct {
+		name           string
+		proxyProtocol  bool
+		trustedProxies []string
+		header         string
+		remoteIP       string
+		err            bool
+	}{
+		{
+			name:     "Disabled",
+			remoteIP: "127.0.0.1",
+		},
+		{
+			name:           "V1",
+			proxyProtocol:  true,
+			trustedProxies: []string{"127.0.0.1"},
+			header:         "PROXY TCP4 192.0.2.1 192.0.2.2 12345 443\r\n",
+			remoteIP:       "192.0.2.1",
+		},
+		{
+			name:           "V2",
+			proxyProtocol:  true,
+			trustedProxies: []string{"127.0.0.1"},
+			header: string([]byte{
+				// Signature.
+				0x0d, 0x0a, 0x0d, 0x0a, 0x00, 0x0d, 0x0a, 0x51, 0x55, 0x49, 0x54, 0x0a,
+				// Version 2, PROXY command; TCP over IPv4; 12 bytes of addresses.
+				0x21, 0x11, 0x00, 0x0c,
+				// Source and destination addresses.
+				0xc6, 0x33, 0x64, 0x01, 0xc6, 0x33, 0x64, 0x02,
+				// Source and destination ports.
+				0x30, 0x39, 0x01, 0xbb,
+			}),
+			remoteIP: "198.51.100.1",
+		},
+		{
+			name:           "Missing",
+			proxyProtocol:  true,
+			trustedProxies: []string{"127.0.0.1"},
+			header:         "GET / HTTP/1.1\r\n",
+			err:            true,
+		},
+		{
+			name:           "Untrusted",
+			proxyProtocol:  true,
+			trustedProxies: []string{"192.0.2.0/24"},
+			header:         "PROXY TCP4 192.0.2.1 192.0.2.2 12345 443\r\n",
+			err:            true,
+		},
+		{
+			name:           "UntrustedNoHeader",
+			proxyProtocol:  true,
+			trustedProxies: []string{"192.0.2.0/24"},
+			remoteIP:       "127.0.0.1",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			trustedProxies := make([]*net.IPNet, len(test.trustedProxies))
+			for i := range test.trustedProxies {
+				var err error
+				trustedProxies[i], err = parseNetwork(test.trustedProxies[i])
+				require.NoError(t, err)
+			}
+			s := &Service{
+				proxyProtocol:  test.proxyProtocol,
+				trustedProxies: trustedProxies,
+			}
+			listener, err := s.listen("127.0.0.1:0")
+			require.NoError(t, err)
+			defer listener.Close()
+
+			client, err := net.Dial("tcp", listener.Addr().String())
+			require.NoError(t, err)
+			defer client.Close()
+			_, err = client.Write([]byte(test.header + "data"))
+			require.NoError(t, err)
+
+			conn, err := listener.Accept()
+			require.NoError(t, err)
+			defer conn.Close()
+
+			buf := make([]byte, 4)
+			_, err = conn.Read(buf)
+			if test.err {
+				require.Error(t, err)
+				// Ensure that subsequent reads also fail.
+				_, err = conn.Read(buf)
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, "data", string(buf))
+			require.Equal(t, test.remoteIP, conn.RemoteAddr().(*net.TCPAddr).IP.String())
+		})
+	}
+}
+
+func TestListenReusePort(t *testing.T) {
+	s := &Service{
+		listenBacklog: 1024,
+	}
+	listener, err := s.listen("127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	// Without SO_REUSEPORT the port cannot be shared.
+	_, err = s.listen(listener.Addr().String())
+	require.Error(t, err)
+
+	if !reusePortSupported {
+		t.Skip("SO_REUSEPORT not supported on this platform")
+	}
+	s.reusePort = true
+	listener1, err := s.listen("127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener1.Close()
+	listener2, err := s.listen(listener1.Addr().String())
+	require.NoError(t, err)
+	defer listener2.Close()
+
+	client, err := net.Dial("tcp", listener1.Addr().String())
+	require.NoError(t, err)
+	require.NoError(t, client.Close())
+}
+
+func TestOpenListenersReusePort(t *testing.T) {
+	if !reusePortSupported {
+		t.Skip("SO_REUSEPORT not supported on this platform")
+	}
+
+	// The default number of listeners is bounded.
+	s := &Service{
+		reusePort: true,
+	}
+	require.LessOrEqual(t, s.reusePortListeners(), defaultReusePortListeners)
+	require.GreaterOrEqual(t, s.reusePortListeners(), 1)
+
+	// Listeners for an address with port 0 share the port chosen for the first.
+	s.reuseListeners = 3
+	conns, listeners, err := s.openListeners([]*Listener{{Address: "127.0.0.1:0"}})
+	require.NoError(t, err)
+	require.Equal(t, 3, listeners)
+	require.Len(t, conns, 3)
+	for _, conn := range conns {
+		defer conn.Close()
+		require.Equal(t, conns[0].Addr().String(), conn.Addr().String())
+	}
+	require.NotEqual(t, 0, conns[0].Addr().(*net.TCPAddr).Port)
+}
+
+// freeAddress returns a local address that is not currently in use.
+func freeAddress(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	address := listener.Addr().String()
+	require.NoError(t, listener.Close())
+	return address
+}
+
+func TestServeMultipleAddresses(t *testing.T) {
+	s := &Service{
+		grpcServer: grpc.NewServer(),
+	}
+	addresses := []string{freeAddress(t), freeAddress(t)}
+	require.NoError(t, s.serve([]*Listener{{Address: addresses[0]}, {Address: addresses[1]}}))
+
+	for _, address := range addresses {
+		client, err := net.Dial("tcp", address)
+		require.NoError(t, err)
+		require.NoError(t, client.Close())
+	}
+
+	// Stopping the server tears down all listeners.
+	s.grpcServer.Stop()
+	for _, address := range addresses {
+		require.Eventually(t, func() bool {
+			listener, err := net.Listen("tcp", address)
+			if err != nil {
+				return false
+			}
+			return listener.Close() == nil
+		}, time.Second, 10*time.Millisecond)
+	}
+}
+
+func TestServeAddressInUse(t *testing.T) {
+	s := &Service{
+		grpcServer: grpc.NewServer(),
+	}
+	defer s.grpcServer.Stop()
+
+	inUse, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer inUse.Close()
+
+	address := freeAddress(t)
+	require.Error(t, s.serve([]*Listener{{Address: address}, {Address: inUse.Addr().String()}}))
+
+	// The listener opened before the failure has been closed.
+	listener, err := net.Listen("tcp", address)
+	require.NoError(t, err)
+	require.NoError(t, listener.Close())
+}
+
+func TestListenUnix(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Unix socket file modes not supported on this platform")
+	}
+	base, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(base)
+	s := &Service{}
+
+	// Default mode.
+	path := filepath.Join(base, "default.sock")
+	listener, err := s.listenUnix(path, 0)
+	require.NoError(t, err)
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, defaultSocketMode, info.Mode().Perm())
+
+	// In use.
+	_, err = s.listenUnix(path, 0)
+	require.EqualError(t, err, "socket is in use")
+
+	// Closing the listener removes the socket.
+	require.NoError(t, listener.Close())
+	_, err = os.Stat(path)
+	require.True(t, os.IsNotExist(err))
+
+	// Configured mode, replacing a stale socket.
+	path = filepath.Join(base, "stale.sock")
+	stale, err := net.ListenUnix("unix", &net.UnixAddr{Name: path, Net: "unix"})
+	require.NoError(t, err)
+	stale.SetUnlinkOnClose(false)
+	require.NoError(t, stale.Close())
+	listener, err = s.listenUnix(path, 0660)
+	require.NoError(t, err)
+	defer listener.Close()
+	info, err = os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0660), info.Mode().Perm())
+	client, err := net.Dial("unix", path)
+	require.NoError(t, err)
+	require.NoError(t, client.Close())
+
+	// Not a socket.
+	path = filepath.Join(base, "file")
+	require.NoError(t, ioutil.WriteFile(path, []byte("data"), 0600))
+	_, err = s.listenUnix(path, 0)
+	require.EqualError(t, err, "file exists and is not a socket")
+}
+
+func TestServeUnixSocket(t *testing.T) {
+	base, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(base)
+	path := filepath.Join(base, "dirk.sock")
+
+	// Unix sockets have a single listener regardless of SO_REUSEPORT.
+	s := &Service{
+		grpcServer:     grpc.NewServer(),
+		reusePort:      reusePortSupported,
+		reuseListeners: 2,
+	}
+	conns, _, err := s.openListeners([]*Listener{{Address: "unix://" + path}})
+	require.NoError(t, err)
+	require.Len(t, conns, 1)
+	require.NoError(t, conns[0].Close())
+
+	require.NoError(t, s.serve([]*Listener{{Address: "unix://" + path}}))
+	client, err := net.Dial("unix", path)
+	require.NoError(t, err)
+	require.NoError(t, client.Close())
+
+	// Stopping the server removes the socket.
+	s.grpcServer.Stop()
+	require.Eventually(t, func() bool {
+		_, err := os.Stat(path)
+		return os.IsNotExist(err)
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestCheckListeners(t *testing.T) {
+	tests := []struct {
+		name      string
+		listeners []*Listener
+		err       string
+	}{
+		{
+			name:      "NoneTCP",
+			listeners: []*Listener{{Address: "127.0.0.1:13141", ClientAuth: ClientAuthNone, DefaultClient: "local"}},
+			err:       "client auth policy none can only be used with Unix sockets, not 127.0.0.1:13141",
+		},
+		{
+			name:      "NoneUnix",
+			listeners: []*Listener{{Address: "unix:///run/dirk/dirk.sock", ClientAuth: ClientAuthNone, DefaultClient: "local"}},
+		},
+		{
+			name:      "NoneUnixNoDefaultClient",
+			listeners: []*Listener{{Address: "unix:///run/dirk/dirk.sock", ClientAuth: ClientAuthNone}},
+			err:       "no default client specified for client auth policy none on unix:///run/dirk/dirk.sock",
+		},
+		{
+			name:      "UnixNoPath",
+			listeners: []*Listener{{Address: "unix://"}},
+			err:       "no socket path specified for unix://",
+		},
+		{
+			name:      "UnixSocketMode",
+			listeners: []*Listener{{Address: "unix:///run/dirk/dirk.sock", SocketMode: 0660}},
+		},
+		{
+			name:      "UnixSocketModeInvalid",
+			listeners: []*Listener{{Address: "unix:///run/dirk/dirk.sock", SocketMode: os.ModeSetuid | 0600}},
+			err:       "invalid socket mode specified for unix:///run/dirk/dirk.sock",
+		},
+		{
+			name:      "TCPSocketMode",
+			listeners: []*Listener{{Address: "127.0.0.1:13141", SocketMode: 0600}},
+			err:       "socket mode cannot be specified for non-Unix socket 127.0.0.1:13141",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := checkListeners(&parameters{listeners: test.listeners})
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+
+	// Listen addresses with the server-wide policy are also checked.
+	err := checkListeners(&parameters{
+		listenAddresses: []string{"0.0.0.0:13141"},
+		clientAuth:      ClientAuthNone,
+		defaultClient:   "local",
+	})
+	require.EqualError(t, err, "client auth policy none can only be used with Unix sockets, not 0.0.0.0:13141")
+}
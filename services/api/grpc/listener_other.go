@@ -0,0 +1,34 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd
+
+package grpc
+
+import (
+	"github.com/pkg/errors"
+)
+
+// reusePortSupported is true if SO_REUSEPORT is supported on this platform.
+const reusePortSupported = false
+
+// setReusePort sets SO_REUSEPORT on the socket.
+func setReusePort(fd uintptr) error {
+	return errors.New("SO_REUSEPORT not supported on this platform")
+}
+
+// setBacklog sets the backlog of a listening socket.
+func setBacklog(fd uintptr, backlog int) error {
+	return errors.New("setting the listen backlog is not supported on this platform")
+}
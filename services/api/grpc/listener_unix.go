@@ -0,0 +1,36 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+// +build darwin dragonfly freebsd linux netbsd openbsd
+
+package grpc
+
+import (
+	"golang.org/x/sys/unix"
+)
+
+// reusePortSupported is true if SO_REUSEPORT is supported on this platform.
+const reusePortSupported = true
+
+// setReusePort sets SO_REUSEPORT on the socket.
+func setReusePort(fd uintptr) error {
+	return unix.SetsockoptInt(int(fd), unix.SOL_SOCKET, unix.SO_REUSEPORT, 1)
+}
+
+// setBacklog sets the backlog of a listening socket.  Calling listen() on a
+// socket that is already listening updates its backlog; the kernel caps the
+// value at its own maximum (for example net.core.somaxconn on Linux).
+func setBacklog(fd uintptr, backlog int) error {
+	return unix.Listen(int(fd), backlog)
+}
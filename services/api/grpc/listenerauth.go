@@ -0,0 +1,126 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpc
+
+import (
+	"net"
+	"os"
+
+	"github.com/attestantio/dirk/services/api/grpc/interceptors"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/credentials"
+)
+
+// Listener is a listen address along with the authentication policy for
+// connections that arrive on it.
+type Listener struct {
+	// Address is the address on which to listen, either host:port for TCP or
+	// unix:///path/to/socket for a Unix socket.
+	Address string
+	// SocketMode is the file mode of a Unix socket.  If not set the socket can
+	// only be accessed by its owner.
+	SocketMode os.FileMode
+	// ClientAuth is the client certificate policy for the listener.  The
+	// policy none can only be used with Unix sockets.
+	ClientAuth ClientAuth
+	// TokenAuth is the bearer token policy for the listener.
+	TokenAuth TokenAuth
+	// DefaultClient is the client name used for requests that do not present
+	// a client certificate.
+	DefaultClient string
+}
+
+// policy returns the policy applied by the interceptors to requests that
+// arrive on the listener.
+func (l *Listener) policy() *interceptors.ListenerPolicy {
+	return &interceptors.ListenerPolicy{
+		DefaultClient: l.DefaultClient,
+		TokenAuth:     l.TokenAuth != TokenAuthNone,
+		TokenRequired: l.TokenAuth == TokenAuthRequire,
+	}
+}
+
+// policyListener tags accepted connections with the listener on which they
+// arrived, so that its policy can be applied to them.
+type policyListener struct {
+	net.Listener
+	config *Listener
+	policy *interceptors.ListenerPolicy
+}
+
+// newPolicyListener wraps a listener with the policy of its configuration.
+func newPolicyListener(listener net.Listener, config *Listener) *policyListener {
+	return &policyListener{
+		Listener: listener,
+		config:   config,
+		policy:   config.policy(),
+	}
+}
+
+// Accept waits for and returns the next connection to the listener.
+func (l *policyListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return &policyConn{Conn: conn, listener: l}, nil
+}
+
+// policyConn is a connection accepted by a policy listener.
+type policyConn struct {
+	net.Conn
+	listener *policyListener
+}
+
+// connClientAuth returns the client certificate policy for the connection.
+// Connections that did not arrive on a policy listener require a client
+// certificate.
+func connClientAuth(conn net.Conn) ClientAuth {
+	if conn, isPolicyConn := conn.(*policyConn); isPolicyConn {
+		return conn.listener.config.ClientAuth
+	}
+	return ClientAuthRequire
+}
+
+// listenerCredentials wraps the server transport credentials to add the policy
+// of the listener on which a connection arrived to its authentication
+// information, for use by the interceptors.
+type listenerCredentials struct {
+	credentials.TransportCredentials
+}
+
+// ServerHandshake carries out the server handshake for the connection.
+func (c *listenerCredentials) ServerHandshake(rawConn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	conn, authInfo, err := c.TransportCredentials.ServerHandshake(rawConn)
+	if err != nil {
+		return nil, nil, err
+	}
+	pConn, isPolicyConn := rawConn.(*policyConn)
+	tlsInfo, isTLSInfo := authInfo.(credentials.TLSInfo)
+	if !isPolicyConn || !isTLSInfo {
+		conn.Close()
+		return nil, nil, errors.New("connection did not arrive on a known listener")
+	}
+	return conn, interceptors.ListenerAuthInfo{
+		TLSInfo: tlsInfo,
+		Policy:  pConn.listener.policy,
+	}, nil
+}
+
+// Clone makes a copy of the credentials.
+func (c *listenerCredentials) Clone() credentials.TransportCredentials {
+	return &listenerCredentials{
+		TransportCredentials: c.TransportCredentials.Clone(),
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpc
+
+import (
+	"crypto/tls"
+	"net"
+	"testing"
+
+	"github.com/attestantio/dirk/services/api/grpc/interceptors"
+	"github.com/attestantio/dirk/testing/resources"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/credentials"
+)
+
+func TestListenerCredentials(t *testing.T) {
+	s := &Service{
+		monitor:       &reloadMonitor{},
+		tlsMinVersion: tls.VersionTLS13,
+	}
+	require.NoError(t, s.setCertificates(resources.SignerTest01Crt, resources.SignerTest01Key, resources.CACrt))
+	creds := &listenerCredentials{
+		TransportCredentials: credentials.NewTLS(&tls.Config{
+			MinVersion:         s.tlsMinVersion,
+			GetConfigForClient: s.tlsConfig,
+		}),
+	}
+
+	tests := []struct {
+		name   string
+		config *Listener
+		policy *interceptors.ListenerPolicy
+		err    bool
+	}{
+		{
+			name:   "Require",
+			config: &Listener{ClientAuth: ClientAuthRequire, DefaultClient: "local"},
+			err:    true,
+		},
+		{
+			name:   "Request",
+			config: &Listener{ClientAuth: ClientAuthRequest, DefaultClient: "local"},
+			policy: &interceptors.ListenerPolicy{DefaultClient: "local"},
+		},
+		{
+			name:   "NoneTokenOptional",
+			config: &Listener{ClientAuth: ClientAuthNone, TokenAuth: TokenAuthOptional, DefaultClient: "local"},
+			policy: &interceptors.ListenerPolicy{DefaultClient: "local", TokenAuth: true},
+		},
+		{
+			name:   "NoneTokenRequired",
+			config: &Listener{ClientAuth: ClientAuthNone, TokenAuth: TokenAuthRequire},
+			policy: &interceptors.ListenerPolicy{TokenAuth: true, TokenRequired: true},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tcpListener, err := net.Listen("tcp", "127.0.0.1:0")
+			require.NoError(t, err)
+			listener := newPolicyListener(tcpListener, test.config)
+			defer listener.Close()
+
+			// The client does not present a certificate.
+			go func() {
+				conn, err := tls.Dial("tcp", listener.Addr().String(), &tls.Config{
+					MinVersion:         tls.VersionTLS13,
+					InsecureSkipVerify: true,
+					NextProtos:         []string{"h2"},
+				})
+				if err != nil {
+					return
+				}
+				_, _ = conn.Read(make([]byte, 1))
+				conn.Close()
+			}()
+
+			rawConn, err := listener.Accept()
+			require.NoError(t, err)
+			conn, authInfo, err := creds.ServerHandshake(rawConn)
+			if test.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			defer conn.Close()
+			listenerAuthInfo, isListenerAuthInfo := authInfo.(interceptors.ListenerAuthInfo)
+			require.True(t, isListenerAuthInfo)
+			require.True(t, listenerAuthInfo.State.HandshakeComplete)
+			require.Empty(t, listenerAuthInfo.State.PeerCertificates)
+			require.Equal(t, test.policy, listenerAuthInfo.Policy)
+		})
+	}
+}
+
+func TestListenerCredentialsUnknownListener(t *testing.T) {
+	s := &Service{
+		monitor:       &reloadMonitor{},
+		tlsMinVersion: tls.VersionTLS13,
+	}
+	require.NoError(t, s.setCertificates(resources.SignerTest01Crt, resources.SignerTest01Key, resources.CACrt))
+	creds := &listenerCredentials{
+		TransportCredentials: credentials.NewTLS(&tls.Config{
+			MinVersion:         s.tlsMinVersion,
+			GetConfigForClient: s.tlsConfig,
+		}),
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	// Connections that do not arrive on a policy listener must present a
+	// certificate.
+	go func() {
+		conn, err := tls.Dial("tcp", listener.Addr().String(), &tls.Config{
+			MinVersion:         tls.VersionTLS13,
+			InsecureSkipVerify: true,
+			NextProtos:         []string{"h2"},
+		})
+		if err != nil {
+			return
+		}
+		_, _ = conn.Read(make([]byte, 1))
+		conn.Close()
+	}()
+
+	rawConn, err := listener.Accept()
+	require.NoError(t, err)
+	_, _, err = creds.ServerHandshake(rawConn)
+	require.Error(t, err)
+}
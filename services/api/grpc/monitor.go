@@ -13,6 +13,22 @@
 
 package grpc
 
+import (
+	"time"
+)
+
 // noopMonitor is a monitor that does nothing, used in place of nil if an
 // external monitor is not supplied.
 type noopMonitor struct{}
+
+// CertificateReloadCompleted is called when an attempt to reload the server certificates has completed.
+func (n *noopMonitor) CertificateReloadCompleted(succeeded bool) {}
+
+// RequestPanicked is called when a request handler panics.
+func (n *noopMonitor) RequestPanicked(rpc string) {}
+
+// RequestRateLimited is called when a request is rejected for exceeding the client's rate limit.
+func (n *noopMonitor) RequestRateLimited(client string) {}
+
+// CertificateExpiry is called when a certificate is loaded, with the time at which it expires.
+func (n *noopMonitor) CertificateExpiry(certificate string, notAfter time.Time) {}
@@ -14,7 +14,16 @@
 package grpc
 
 import (
+	"crypto/tls"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"time"
+
 	"github.com/attestantio/dirk/services/accountmanager"
+	"github.com/attestantio/dirk/services/adminauth"
+	"github.com/attestantio/dirk/services/authenticator"
 	"github.com/attestantio/dirk/services/lister"
 	"github.com/attestantio/dirk/services/metrics"
 	"github.com/attestantio/dirk/services/peers"
@@ -26,20 +35,42 @@ import (
 )
 
 type parameters struct {
-	logLevel       zerolog.Level
-	monitor        metrics.APIMonitor
-	peers          peers.Service
-	process        process.Service
-	accountManager accountmanager.Service
-	walletManager  walletmanager.Service
-	lister         lister.Service
-	signer         signer.Service
-	name           string
-	listenAddress  string
-	id             uint64
-	serverCert     []byte
-	serverKey      []byte
-	caCert         []byte
+	logLevel        zerolog.Level
+	monitor         metrics.APIMonitor
+	peers           peers.Service
+	process         process.Service
+	accountManager  accountmanager.Service
+	walletManager   walletmanager.Service
+	lister          lister.Service
+	signer          signer.Service
+	name            string
+	listenAddresses []string
+	listeners       []*Listener
+	id              uint64
+	serverCert      []byte
+	serverKey       []byte
+	caCert          []byte
+	clientAuth      ClientAuth
+	defaultClient   string
+	proxyProtocol   bool
+	trustedProxies  []string
+	trustedNetworks []*net.IPNet
+	listenBacklog   int
+	reusePort       bool
+	reuseListeners  int
+	clockSkew       time.Duration
+	certExpiryWarn  time.Duration
+	tlsMinVersion   uint16
+	tlsCiphers      []uint16
+	tokenAuth       TokenAuth
+	authenticator   authenticator.Service
+	adminAuth       adminauth.Service
+	adminIPs        []string
+	adminAllowAll   bool
+	rateLimit       float64
+	clientLimits    map[string]float64
+	accessLog       io.Writer
+	denialDetails   bool
 }
 
 // Parameter is the interface for service parameters.
@@ -126,7 +157,24 @@ func WithID(id uint64) Parameter {
 // WithListenAddress sets the listen address for the server.
 func WithListenAddress(listenAddress string) Parameter {
 	return parameterFunc(func(p *parameters) {
-		p.listenAddress = listenAddress
+		p.listenAddresses = []string{listenAddress}
+	})
+}
+
+// WithListenAddresses sets the listen addresses for the server.
+func WithListenAddresses(listenAddresses []string) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.listenAddresses = listenAddresses
+	})
+}
+
+// WithListeners sets listen addresses for the server, each with its own
+// authentication policy.  These are in addition to any set with
+// WithListenAddresses, which use the policy set by WithClientAuth,
+// WithTokenAuth and WithDefaultClient.
+func WithListeners(listeners []*Listener) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.listeners = listeners
 	})
 }
 
@@ -151,10 +199,162 @@ func WithCACert(caCert []byte) Parameter {
 	})
 }
 
+// WithClientAuth sets the client certificate policy for the listen addresses.
+func WithClientAuth(clientAuth ClientAuth) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.clientAuth = clientAuth
+	})
+}
+
+// WithDefaultClient sets the client name used for requests to the listen addresses that do not present a client certificate.
+func WithDefaultClient(defaultClient string) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.defaultClient = defaultClient
+	})
+}
+
+// WithProxyProtocol requires connections to the listener to start with a PROXY protocol header.
+func WithProxyProtocol(proxyProtocol bool) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.proxyProtocol = proxyProtocol
+	})
+}
+
+// WithTrustedProxies sets the addresses, as IP addresses or ranges in CIDR
+// notation, of the proxies that are trusted to send a PROXY protocol header.
+func WithTrustedProxies(trustedProxies []string) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.trustedProxies = trustedProxies
+	})
+}
+
+// WithListenBacklog sets the backlog of pending connections for the listener.  If not set the system default is used.
+func WithListenBacklog(listenBacklog int) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.listenBacklog = listenBacklog
+	})
+}
+
+// WithReusePort opens the listener with SO_REUSEPORT, using multiple listeners to accept connections.
+func WithReusePort(reusePort bool) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.reusePort = reusePort
+	})
+}
+
+// WithReusePortListeners sets the number of listeners opened for each address with SO_REUSEPORT.
+// If not set a small default is used.
+func WithReusePortListeners(reuseListeners int) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.reuseListeners = reuseListeners
+	})
+}
+
+// WithTokenAuth sets the bearer token policy for the listen addresses.
+func WithTokenAuth(tokenAuth TokenAuth) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.tokenAuth = tokenAuth
+	})
+}
+
+// WithAuthenticator sets the authenticator for bearer tokens.
+func WithAuthenticator(authenticator authenticator.Service) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.authenticator = authenticator
+	})
+}
+
+// WithAdminAuth sets the service that authorises administrative requests by challenge-response.
+// If not set, administrative requests do not require a challenge-response.
+func WithAdminAuth(adminAuth adminauth.Service) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.adminAuth = adminAuth
+	})
+}
+
+// WithAdminIPs sets the IP addresses from which administrative requests are accepted.
+// If empty, administrative requests are refused unless WithAdminAllowAll is set.
+func WithAdminIPs(adminIPs []string) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.adminIPs = adminIPs
+	})
+}
+
+// WithAdminAllowAll sets whether administrative requests are accepted from any IP address.
+func WithAdminAllowAll(adminAllowAll bool) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.adminAllowAll = adminAllowAll
+	})
+}
+
+// WithRateLimit sets the default limit on requests per second from each client.
+// If not set, or set to 0, requests are not limited.
+func WithRateLimit(rateLimit float64) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.rateLimit = rateLimit
+	})
+}
+
+// WithClientRateLimits sets limits on requests per second for individual clients,
+// overriding the default limit.  A limit of 0 means that the client is not limited.
+func WithClientRateLimits(clientLimits map[string]float64) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.clientLimits = clientLimits
+	})
+}
+
+// WithClockSkew sets the tolerance for clock skew when verifying the validity period of client certificates.
+func WithClockSkew(clockSkew time.Duration) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.clockSkew = clockSkew
+	})
+}
+
+// WithCertificateExpiryWarning sets the time before expiry of a certificate at which warnings are logged.
+func WithCertificateExpiryWarning(threshold time.Duration) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.certExpiryWarn = threshold
+	})
+}
+
+// WithTLSMinVersion sets the minimum TLS version for connections.
+func WithTLSMinVersion(version uint16) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.tlsMinVersion = version
+	})
+}
+
+// WithTLSCipherSuites sets the cipher suites permitted for TLS 1.2 connections.
+// If not supplied the Go TLS library defaults are used.
+func WithTLSCipherSuites(suites []uint16) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.tlsCiphers = suites
+	})
+}
+
+// WithAccessLog sets the output for the access log, which records each request.
+// If nil no access log is written.
+func WithAccessLog(output io.Writer) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.accessLog = output
+	})
+}
+
+// WithDenialDetails returns signing requests denied by slashing protection as
+// errors with the reason for the denial in their details.
+func WithDenialDetails(denialDetails bool) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.denialDetails = denialDetails
+	})
+}
+
 // parseAndCheckParameters parses and checks parameters to ensure that mandatory parameters are present and correct.
 func parseAndCheckParameters(params ...Parameter) (*parameters, error) {
 	parameters := parameters{
-		logLevel: zerolog.GlobalLevel(),
+		logLevel:       zerolog.GlobalLevel(),
+		clientAuth:     ClientAuthRequire,
+		certExpiryWarn: 14 * 24 * time.Hour,
+		tlsMinVersion:  tls.VersionTLS13,
 	}
 	for _, p := range params {
 		if params != nil {
@@ -190,15 +390,138 @@ func parseAndCheckParameters(params ...Parameter) (*parameters, error) {
 	if parameters.id == 0 {
 		return nil, errors.New("no ID specified")
 	}
-	if parameters.listenAddress == "" {
-		return nil, errors.New("no listen address specified")
-	}
 	if len(parameters.serverCert) == 0 {
 		return nil, errors.New("no server certificate specified")
 	}
 	if len(parameters.serverKey) == 0 {
 		return nil, errors.New("no server key specified")
 	}
+	if parameters.proxyProtocol && len(parameters.trustedProxies) == 0 {
+		return nil, errors.New("no trusted proxies specified for proxy protocol")
+	}
+	if !parameters.proxyProtocol && len(parameters.trustedProxies) > 0 {
+		return nil, errors.New("trusted proxies cannot be specified without proxy protocol")
+	}
+	for _, trustedProxy := range parameters.trustedProxies {
+		network, err := parseNetwork(trustedProxy)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid trusted proxy %q", trustedProxy)
+		}
+		parameters.trustedNetworks = append(parameters.trustedNetworks, network)
+	}
+	if parameters.listenBacklog < 0 {
+		return nil, errors.New("listen backlog cannot be negative")
+	}
+	if parameters.reuseListeners < 0 {
+		return nil, errors.New("reuse port listeners cannot be negative")
+	}
+	if parameters.reuseListeners > maxReusePortListeners {
+		return nil, errors.Errorf("reuse port listeners cannot be more than %d", maxReusePortListeners)
+	}
+	if parameters.adminAllowAll && len(parameters.adminIPs) > 0 {
+		return nil, errors.New("admin IPs cannot be specified when all admin IPs are allowed")
+	}
+	if parameters.rateLimit < 0 {
+		return nil, errors.New("rate limit cannot be negative")
+	}
+	for client, limit := range parameters.clientLimits {
+		if limit < 0 {
+			return nil, errors.Errorf("rate limit for client %s cannot be negative", client)
+		}
+	}
+	if parameters.clockSkew < 0 {
+		return nil, errors.New("clock skew cannot be negative")
+	}
+	if parameters.certExpiryWarn < 0 {
+		return nil, errors.New("certificate expiry warning threshold cannot be negative")
+	}
+	if parameters.tlsMinVersion != tls.VersionTLS12 && parameters.tlsMinVersion != tls.VersionTLS13 {
+		return nil, errors.New("TLS minimum version must be 1.2 or 1.3")
+	}
+	if len(parameters.tlsCiphers) > 0 && parameters.tlsMinVersion == tls.VersionTLS13 {
+		return nil, errors.New("TLS cipher suites cannot be configured when the minimum TLS version is 1.3")
+	}
+	if err := checkListeners(&parameters); err != nil {
+		return nil, err
+	}
 
 	return &parameters, nil
 }
+
+// checkListeners combines the listen addresses with the listeners, and checks
+// the policy of each.
+func checkListeners(parameters *parameters) error {
+	listeners := make([]*Listener, 0, len(parameters.listenAddresses)+len(parameters.listeners))
+	for _, listenAddress := range parameters.listenAddresses {
+		listeners = append(listeners, &Listener{
+			Address:       listenAddress,
+			ClientAuth:    parameters.clientAuth,
+			TokenAuth:     parameters.tokenAuth,
+			DefaultClient: parameters.defaultClient,
+		})
+	}
+	for _, listener := range parameters.listeners {
+		if listener == nil {
+			return errors.New("nil listener specified")
+		}
+		listeners = append(listeners, listener)
+	}
+	if len(listeners) == 0 {
+		return errors.New("no listen address specified")
+	}
+
+	for _, listener := range listeners {
+		if listener.Address == "" {
+			return errors.New("empty listen address specified")
+		}
+		if isUnixAddress(listener.Address) {
+			if unixSocketPath(listener.Address) == "" {
+				return errors.Errorf("no socket path specified for %s", listener.Address)
+			}
+			if listener.SocketMode&^os.ModePerm != 0 {
+				return errors.Errorf("invalid socket mode specified for %s", listener.Address)
+			}
+		} else if listener.SocketMode != 0 {
+			return errors.Errorf("socket mode cannot be specified for non-Unix socket %s", listener.Address)
+		}
+		if listener.ClientAuth < ClientAuthRequire || listener.ClientAuth > ClientAuthNone {
+			return errors.Errorf("invalid client auth policy specified for %s", listener.Address)
+		}
+		// Without client certificates any process that can connect to the listener acts as the
+		// default client, so this is only allowed where the filesystem restricts access.
+		if listener.ClientAuth == ClientAuthNone && !isUnixAddress(listener.Address) {
+			return errors.Errorf("client auth policy none can only be used with Unix sockets, not %s", listener.Address)
+		}
+		if listener.TokenAuth < TokenAuthNone || listener.TokenAuth > TokenAuthRequire {
+			return errors.Errorf("invalid token auth policy specified for %s", listener.Address)
+		}
+		if listener.TokenAuth != TokenAuthNone && parameters.authenticator == nil {
+			return errors.Errorf("no authenticator specified for token auth on %s", listener.Address)
+		}
+		if listener.ClientAuth == ClientAuthNone && listener.TokenAuth != TokenAuthRequire && listener.DefaultClient == "" {
+			return errors.Errorf("no default client specified for client auth policy none on %s", listener.Address)
+		}
+	}
+	parameters.listeners = listeners
+
+	return nil
+}
+
+// parseNetwork parses an IP range in CIDR notation.  A bare IP address is
+// treated as a range containing only that address.
+func parseNetwork(input string) (*net.IPNet, error) {
+	if !strings.Contains(input, "/") {
+		ip := net.ParseIP(input)
+		if ip == nil {
+			return nil, errors.New("invalid IP address")
+		}
+		bits := 8 * net.IPv6len
+		if ip.To4() != nil {
+			ip = ip.To4()
+			bits = 8 * net.IPv4len
+		}
+		return &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}, nil
+	}
+	_, network, err := net.ParseCIDR(input)
+	return network, err
+}
@@ -0,0 +1,68 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpc
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	dirkpb "github.com/attestantio/dirk/pb/v1"
+	pb "github.com/wealdtech/eth2-signer-api/pb/v1"
+)
+
+// ParseRateLimit parses a rate limit of the form "<requests>/<unit>", where
+// unit is one of "s", "m" or "h", returning the limit in requests per second.
+// A value without a unit is treated as requests per second, and an empty
+// string or a value of 0 is treated as no limit.
+func ParseRateLimit(input string) (float64, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return 0, nil
+	}
+
+	value := input
+	period := 1.0
+	if i := strings.Index(input, "/"); i != -1 {
+		value = strings.TrimSpace(input[:i])
+		switch strings.ToLower(strings.TrimSpace(input[i+1:])) {
+		case "s":
+			period = 1
+		case "m":
+			period = 60
+		case "h":
+			period = 3600
+		default:
+			return 0, fmt.Errorf("unrecognised rate limit period in %q", input)
+		}
+	}
+	requests, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid rate limit %q", input)
+	}
+	if requests < 0 {
+		return 0, fmt.Errorf("rate limit %q cannot be negative", input)
+	}
+
+	return requests / period, nil
+}
+
+// isRateLimitExempt returns true if the method is not subject to rate limits.
+// Health checks and peer traffic for distributed key generation and resharing are exempt, so
+// that a busy client cannot interfere with either.
+func isRateLimitExempt(method string) bool {
+	return isHealthMethod(method) ||
+		strings.HasPrefix(method, "/"+pb.DKG_ServiceDesc.ServiceName+"/") ||
+		strings.HasPrefix(method, "/"+dirkpb.Reshare_ServiceDesc.ServiceName+"/")
+}
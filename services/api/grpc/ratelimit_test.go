@@ -0,0 +1,88 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpc_test
+
+import (
+	"testing"
+
+	grpcapi "github.com/attestantio/dirk/services/api/grpc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRateLimit(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		res   float64
+		err   string
+	}{
+		{
+			name:  "Empty",
+			input: "",
+			res:   0,
+		},
+		{
+			name:  "Zero",
+			input: "0",
+			res:   0,
+		},
+		{
+			name:  "NoUnit",
+			input: "50",
+			res:   50,
+		},
+		{
+			name:  "PerSecond",
+			input: "200/s",
+			res:   200,
+		},
+		{
+			name:  "PerMinute",
+			input: "120/m",
+			res:   2,
+		},
+		{
+			name:  "PerHour",
+			input: " 1800 / H ",
+			res:   0.5,
+		},
+		{
+			name:  "BadPeriod",
+			input: "200/d",
+			err:   `unrecognised rate limit period in "200/d"`,
+		},
+		{
+			name:  "BadValue",
+			input: "lots/s",
+			err:   `invalid rate limit "lots/s"`,
+		},
+		{
+			name:  "Negative",
+			input: "-1/s",
+			err:   `rate limit "-1/s" cannot be negative`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			res, err := grpcapi.ParseRateLimit(test.input)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, test.res, res)
+			}
+		})
+	}
+}
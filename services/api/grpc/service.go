@@ -17,15 +17,38 @@ import (
 	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"io"
 	"net"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
 
+	dirkpb "github.com/attestantio/dirk/pb/v1"
+	"github.com/attestantio/dirk/services/accountmanager"
+	"github.com/attestantio/dirk/services/adminauth"
+	accountadminhandler "github.com/attestantio/dirk/services/api/grpc/handlers/accountadmin"
 	accountmanagerhandler "github.com/attestantio/dirk/services/api/grpc/handlers/accountmanager"
+	adminauthhandler "github.com/attestantio/dirk/services/api/grpc/handlers/adminauth"
+	blstoexecutionchangesignerhandler "github.com/attestantio/dirk/services/api/grpc/handlers/blstoexecutionchangesigner"
+	deposithandler "github.com/attestantio/dirk/services/api/grpc/handlers/deposit"
 	listerhandler "github.com/attestantio/dirk/services/api/grpc/handlers/lister"
+	peeradminhandler "github.com/attestantio/dirk/services/api/grpc/handlers/peeradmin"
 	receiverhandler "github.com/attestantio/dirk/services/api/grpc/handlers/receiver"
+	resharehandler "github.com/attestantio/dirk/services/api/grpc/handlers/reshare"
+	rootsignerhandler "github.com/attestantio/dirk/services/api/grpc/handlers/rootsigner"
+	signcheckhandler "github.com/attestantio/dirk/services/api/grpc/handlers/signcheck"
 	signerhandler "github.com/attestantio/dirk/services/api/grpc/handlers/signer"
+	taggedsignerhandler "github.com/attestantio/dirk/services/api/grpc/handlers/taggedsigner"
+	validatorregistrationsignerhandler "github.com/attestantio/dirk/services/api/grpc/handlers/validatorregistrationsigner"
 	walletmanagerhandler "github.com/attestantio/dirk/services/api/grpc/handlers/walletmanager"
 	"github.com/attestantio/dirk/services/api/grpc/interceptors"
+	"github.com/attestantio/dirk/services/authenticator"
+	"github.com/attestantio/dirk/services/lister"
 	"github.com/attestantio/dirk/services/metrics"
+	"github.com/attestantio/dirk/services/peers"
+	"github.com/attestantio/dirk/services/process"
+	"github.com/attestantio/dirk/services/signer"
 	"github.com/attestantio/dirk/util/loggers"
 	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	grpc_ctxtags "github.com/grpc-ecosystem/go-grpc-middleware/tags"
@@ -36,12 +59,40 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/grpclog"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
 // Service provides the features and functions for the GRPC daemon.
 type Service struct {
 	monitor    metrics.APIMonitor
 	grpcServer *grpc.Server
+	health     *healthServer
+	stopped    chan struct{}
+	stopOnce   sync.Once
+	inflight   int64
+	draining   int64
+
+	listeners      []*Listener
+	adminAuth      adminauth.Service
+	adminIPs       []string
+	adminAllowAll  bool
+	proxyProtocol  bool
+	trustedProxies []*net.IPNet
+	listenBacklog  int
+	reusePort      bool
+	reuseListeners int
+	clockSkew      time.Duration
+	certExpiryWarn time.Duration
+	tlsMinVersion  uint16
+	tlsCiphers     []uint16
+	rateLimit      float64
+	clientLimits   map[string]float64
+	accessLog      io.Writer
+
+	certsMu     sync.RWMutex
+	serverCert  *tls.Certificate
+	clientCAs   *x509.CertPool
+	certsDigest [32]byte
 }
 
 // module-wide log.
@@ -61,10 +112,37 @@ func New(ctx context.Context, params ...Parameter) (*Service, error) {
 	}
 
 	s := &Service{
-		monitor: parameters.monitor,
+		monitor:        parameters.monitor,
+		listeners:      parameters.listeners,
+		adminAuth:      parameters.adminAuth,
+		adminIPs:       parameters.adminIPs,
+		adminAllowAll:  parameters.adminAllowAll,
+		proxyProtocol:  parameters.proxyProtocol,
+		trustedProxies: parameters.trustedNetworks,
+		listenBacklog:  parameters.listenBacklog,
+		reusePort:      parameters.reusePort,
+		reuseListeners: parameters.reuseListeners,
+		clockSkew:      parameters.clockSkew,
+		certExpiryWarn: parameters.certExpiryWarn,
+		tlsMinVersion:  parameters.tlsMinVersion,
+		tlsCiphers:     parameters.tlsCiphers,
+		rateLimit:      parameters.rateLimit,
+		clientLimits:   parameters.clientLimits,
+		accessLog:      parameters.accessLog,
+		stopped:        make(chan struct{}),
+	}
+	switch {
+	case s.adminAllowAll:
+		log.Warn().Msg("Administrative requests are accepted from any IP address")
+	case len(s.adminIPs) == 0:
+		log.Warn().Msg("No admin IPs configured; administrative requests will be refused")
+	}
+	if s.reusePort && !reusePortSupported {
+		log.Warn().Msg("SO_REUSEPORT is not supported on this platform; using a single listener")
+		s.reusePort = false
 	}
 
-	if err := s.createServer(parameters.name, parameters.serverCert, parameters.serverKey, parameters.caCert); err != nil {
+	if err := s.createServer(parameters.name, parameters.id, parameters.serverCert, parameters.serverKey, parameters.caCert, parameters.authenticator); err != nil {
 		return nil, errors.Wrap(err, "failed to create API server")
 	}
 
@@ -87,6 +165,44 @@ func New(ctx context.Context, params ...Parameter) (*Service, error) {
 		return nil, errors.Wrap(err, "failed to create account manager handler")
 	}
 	pb.RegisterAccountManagerServer(s.grpcServer, accountManagerHandler)
+	if _, isStatusProvider := parameters.accountManager.(accountmanager.StatusProvider); isStatusProvider {
+		dirkpb.RegisterAccountStatusServer(s.grpcServer, accountManagerHandler)
+	}
+	if _, isBulkGenerator := parameters.accountManager.(accountmanager.BulkGenerator); isBulkGenerator {
+		dirkpb.RegisterBulkGenerateServer(s.grpcServer, accountManagerHandler)
+	}
+
+	accountAdminHandler, err := accountadminhandler.New(ctx,
+		accountadminhandler.WithLogLevel(parameters.logLevel),
+		accountadminhandler.WithAccountManager(parameters.accountManager),
+		accountadminhandler.WithProcess(parameters.process),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create account admin handler")
+	}
+	dirkpb.RegisterAccountAdminServer(s.grpcServer, accountAdminHandler)
+
+	if _, isUpdater := parameters.peers.(peers.Updater); isUpdater {
+		peerAdminHandler, err := peeradminhandler.New(ctx,
+			peeradminhandler.WithLogLevel(parameters.logLevel),
+			peeradminhandler.WithPeers(parameters.peers),
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create peer admin handler")
+		}
+		dirkpb.RegisterPeerAdminServer(s.grpcServer, peerAdminHandler)
+	}
+
+	if parameters.adminAuth != nil {
+		adminAuthHandler, err := adminauthhandler.New(ctx,
+			adminauthhandler.WithLogLevel(parameters.logLevel),
+			adminauthhandler.WithAdminAuth(parameters.adminAuth),
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create admin auth handler")
+		}
+		dirkpb.RegisterAdminAuthServer(s.grpcServer, adminAuthHandler)
+	}
 
 	listerHandler, err := listerhandler.New(ctx,
 		listerhandler.WithLister(parameters.lister),
@@ -96,9 +212,13 @@ func New(ctx context.Context, params ...Parameter) (*Service, error) {
 		return nil, errors.Wrap(err, "failed to create lister handler")
 	}
 	pb.RegisterListerServer(s.grpcServer, listerHandler)
+	if _, isWalletLister := parameters.lister.(lister.WalletLister); isWalletLister {
+		dirkpb.RegisterWalletListerServer(s.grpcServer, listerHandler)
+	}
 
 	signerHandler, err := signerhandler.New(ctx,
 		signerhandler.WithSigner(parameters.signer),
+		signerhandler.WithDenialDetails(parameters.denialDetails),
 		signerhandler.WithLogLevel(parameters.logLevel),
 	)
 	if err != nil {
@@ -106,6 +226,72 @@ func New(ctx context.Context, params ...Parameter) (*Service, error) {
 	}
 	pb.RegisterSignerServer(s.grpcServer, signerHandler)
 
+	if signChecker, isSignChecker := parameters.signer.(signer.SignChecker); isSignChecker {
+		signCheckHandler, err := signcheckhandler.New(ctx,
+			signcheckhandler.WithSignChecker(signChecker),
+			signcheckhandler.WithLogLevel(parameters.logLevel),
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create sign check handler")
+		}
+		dirkpb.RegisterSignCheckServer(s.grpcServer, signCheckHandler)
+	}
+
+	if depositDataGenerator, isDepositDataGenerator := parameters.signer.(signer.DepositDataGenerator); isDepositDataGenerator {
+		depositHandler, err := deposithandler.New(ctx,
+			deposithandler.WithDepositDataGenerator(depositDataGenerator),
+			deposithandler.WithLogLevel(parameters.logLevel),
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create deposit handler")
+		}
+		dirkpb.RegisterDepositServer(s.grpcServer, depositHandler)
+	}
+
+	if taggedSigner, isTaggedSigner := parameters.signer.(signer.TaggedSigner); isTaggedSigner {
+		taggedSignerHandler, err := taggedsignerhandler.New(ctx,
+			taggedsignerhandler.WithTaggedSigner(taggedSigner),
+			taggedsignerhandler.WithLogLevel(parameters.logLevel),
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create tagged signer handler")
+		}
+		dirkpb.RegisterTaggedSignerServer(s.grpcServer, taggedSignerHandler)
+	}
+
+	if rootSigner, isRootSigner := parameters.signer.(signer.RootSigner); isRootSigner {
+		rootSignerHandler, err := rootsignerhandler.New(ctx,
+			rootsignerhandler.WithRootSigner(rootSigner),
+			rootsignerhandler.WithLogLevel(parameters.logLevel),
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create root signer handler")
+		}
+		dirkpb.RegisterRootSignerServer(s.grpcServer, rootSignerHandler)
+	}
+
+	if blsToExecutionChangeSigner, isBLSToExecutionChangeSigner := parameters.signer.(signer.BLSToExecutionChangeSigner); isBLSToExecutionChangeSigner {
+		blsToExecutionChangeSignerHandler, err := blstoexecutionchangesignerhandler.New(ctx,
+			blstoexecutionchangesignerhandler.WithBLSToExecutionChangeSigner(blsToExecutionChangeSigner),
+			blstoexecutionchangesignerhandler.WithLogLevel(parameters.logLevel),
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create BLS to execution change signer handler")
+		}
+		dirkpb.RegisterBLSToExecutionChangeSignerServer(s.grpcServer, blsToExecutionChangeSignerHandler)
+	}
+
+	if validatorRegistrationSigner, isValidatorRegistrationSigner := parameters.signer.(signer.ValidatorRegistrationSigner); isValidatorRegistrationSigner {
+		validatorRegistrationSignerHandler, err := validatorregistrationsignerhandler.New(ctx,
+			validatorregistrationsignerhandler.WithValidatorRegistrationSigner(validatorRegistrationSigner),
+			validatorregistrationsignerhandler.WithLogLevel(parameters.logLevel),
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create validator registration signer handler")
+		}
+		dirkpb.RegisterValidatorRegistrationSignerServer(s.grpcServer, validatorRegistrationSignerHandler)
+	}
+
 	receiverHandler, err := receiverhandler.New(ctx,
 		receiverhandler.WithLogLevel(parameters.logLevel),
 		receiverhandler.WithProcess(parameters.process),
@@ -116,75 +302,204 @@ func New(ctx context.Context, params ...Parameter) (*Service, error) {
 	}
 	pb.RegisterDKGServer(s.grpcServer, receiverHandler)
 
-	err = s.serve(parameters.listenAddress)
+	if resharer, isResharer := parameters.process.(process.Resharer); isResharer {
+		reshareHandler, err := resharehandler.New(ctx,
+			resharehandler.WithLogLevel(parameters.logLevel),
+			resharehandler.WithResharer(resharer),
+			resharehandler.WithPeers(parameters.peers),
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create reshare handler")
+		}
+		dirkpb.RegisterReshareServer(s.grpcServer, reshareHandler)
+	}
+
+	s.health = newHealthServer()
+	healthpb.RegisterHealthServer(s.grpcServer, s.health)
+
+	err = s.serve(s.listeners)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to start API server")
 	}
 
-	// Cancel service on context done.
+	// Stop service on context done, waiting for in-flight requests to
+	// complete; a call to Stop with a deadline bounds the wait.
 	go func() {
 		<-ctx.Done()
-		s.grpcServer.GracefulStop()
+		s.Stop(context.Background())
 	}()
 
 	return s, nil
 }
 
+// Stopped returns a channel that is closed once the API server has stopped
+// and all in-flight requests have completed.
+func (s *Service) Stopped() <-chan struct{} {
+	return s.stopped
+}
+
+// isAdminMethod returns true if the method is an administrative method, which
+// requires a challenge-response if admin auth is configured.
+func isAdminMethod(method string) bool {
+	return strings.HasPrefix(method, "/"+dirkpb.AccountAdmin_ServiceDesc.ServiceName+"/") ||
+		strings.HasPrefix(method, "/"+dirkpb.PeerAdmin_ServiceDesc.ServiceName+"/")
+}
+
+// isAdminIPMethod returns true if the method is restricted to the administrative
+// IP addresses.  This covers the administrative methods and the admin auth
+// challenge, so that clients elsewhere cannot exhaust the outstanding nonces.
+func isAdminIPMethod(method string) bool {
+	return isAdminMethod(method) ||
+		strings.HasPrefix(method, "/"+dirkpb.AdminAuth_ServiceDesc.ServiceName+"/")
+}
+
 // createServer creates the GRPC server.
-func (s *Service) createServer(name string, certPEMBlock []byte, keyPEMBlock []byte, caPEMBlock []byte) error {
+func (s *Service) createServer(name string, id uint64, certPEMBlock []byte, keyPEMBlock []byte, caPEMBlock []byte, authenticator authenticator.Service) error {
 	grpclog.SetLoggerV2(loggers.NewGRPCLoggerV2(log.With().Str("service", "grpc").Logger()))
 
+	unaryInterceptors := []grpc.UnaryServerInterceptor{
+		s.trackUnary,
+		interceptors.RecoveryInterceptor(s.monitor),
+		interceptors.ServerIdentityInterceptor(name, id),
+		grpc_ctxtags.UnaryServerInterceptor(grpc_ctxtags.WithFieldExtractor(grpc_ctxtags.CodeGenRequestFieldExtractor)),
+		interceptors.RequestIDInterceptor(),
+		interceptors.TracingInterceptor(),
+		interceptors.SourceIPInterceptor(),
+		interceptors.ClientInfoInterceptor(),
+	}
+	if s.accessLog != nil {
+		// Log before access controls are applied, so that refused requests are also logged.
+		unaryInterceptors = append(unaryInterceptors, interceptors.AccessLogInterceptor(zerolog.New(s.accessLog).With().Timestamp().Str("log", "access").Logger()))
+	}
+	// Always restrict administrative requests by address, so that an empty list fails closed.
+	unaryInterceptors = append(unaryInterceptors, interceptors.AdminIPInterceptor(s.adminIPs, s.adminAllowAll, isAdminIPMethod))
+	if s.tokenAuthEnabled() {
+		// Token policy is per-listener, so is applied by the interceptor.
+		unaryInterceptors = append(unaryInterceptors, exceptHealth(interceptors.TokenInterceptor(authenticator)))
+	}
+	if s.rateLimit > 0 || len(s.clientLimits) > 0 {
+		unaryInterceptors = append(unaryInterceptors, interceptors.RateLimitInterceptor(s.rateLimit, s.clientLimits, isRateLimitExempt, s.monitor))
+	}
+	if s.adminAuth != nil {
+		unaryInterceptors = append(unaryInterceptors, interceptors.AdminAuthInterceptor(s.adminAuth, isAdminMethod))
+	}
 	grpcOpts := []grpc.ServerOption{
-		grpc.UnaryInterceptor(
-			grpc_middleware.ChainUnaryServer(
-				grpc_ctxtags.UnaryServerInterceptor(grpc_ctxtags.WithFieldExtractor(grpc_ctxtags.CodeGenRequestFieldExtractor)),
-				interceptors.RequestIDInterceptor(),
-				interceptors.SourceIPInterceptor(),
-				interceptors.ClientInfoInterceptor(),
-			)),
+		grpc.UnaryInterceptor(grpc_middleware.ChainUnaryServer(unaryInterceptors...)),
+		grpc.StreamInterceptor(s.trackStream),
 	}
 
 	if name == "" {
 		return errors.New("no server name provided; cannot proceed")
 	}
 
-	serverCert, err := tls.X509KeyPair(certPEMBlock, keyPEMBlock)
-	if err != nil {
-		return errors.Wrap(err, "failed to load server keypair")
+	if err := s.setCertificates(certPEMBlock, keyPEMBlock, caPEMBlock); err != nil {
+		return err
 	}
+	log.Info().Time("not_after", s.serverCert.Leaf.NotAfter).Msg("Loaded certificates")
 
-	certPool := x509.NewCertPool()
-	if len(caPEMBlock) > 0 {
-		// Read in the certificate authority certificate; this is required to validate client certificates on incoming connections.
-		if ok := certPool.AppendCertsFromPEM(caPEMBlock); !ok {
-			return errors.New("could not add CA certificate to pool")
-		}
+	// The TLS configuration is obtained per-connection to allow certificates to be
+	// reloaded, and to apply the client certificate policy of the connection's listener.
+	serverCreds := &listenerCredentials{
+		TransportCredentials: credentials.NewTLS(&tls.Config{
+			MinVersion:         s.tlsMinVersion,
+			GetConfigForClient: s.tlsConfig,
+		}),
 	}
-
-	serverCreds := credentials.NewTLS(&tls.Config{
-		ClientAuth:   tls.RequireAndVerifyClientCert,
-		Certificates: []tls.Certificate{serverCert},
-		ClientCAs:    certPool,
-		MinVersion:   tls.VersionTLS13,
-	})
 	grpcOpts = append(grpcOpts, grpc.Creds(serverCreds))
 	s.grpcServer = grpc.NewServer(grpcOpts...)
 
 	return nil
 }
 
+// tokenAuthEnabled returns true if any listener checks bearer tokens.
+func (s *Service) tokenAuthEnabled() bool {
+	for _, listener := range s.listeners {
+		if listener.TokenAuth != TokenAuthNone {
+			return true
+		}
+	}
+	return false
+}
+
+// reusePortListeners returns the number of listeners to open for each address
+// with SO_REUSEPORT.  If not configured this is one per available CPU, up to
+// defaultReusePortListeners.
+func (s *Service) reusePortListeners() int {
+	if s.reuseListeners > 0 {
+		return s.reuseListeners
+	}
+	listeners := runtime.GOMAXPROCS(0)
+	if listeners > defaultReusePortListeners {
+		listeners = defaultReusePortListeners
+	}
+	return listeners
+}
+
 // Serve serves the GRPC server.
-func (s *Service) serve(listenAddress string) error {
-	conn, err := net.Listen("tcp", listenAddress)
+// All listeners are opened before any are served, so a failure to bind any
+// one of the addresses leaves nothing listening.
+func (s *Service) serve(configs []*Listener) error {
+	conns, listeners, err := s.openListeners(configs)
 	if err != nil {
 		return err
 	}
-	log.Info().Str("address", listenAddress).Msg("Listening")
-
-	go func() {
-		if err := s.grpcServer.Serve(conn); err != nil {
-			log.Error().Err(err).Msg("Could not start GRPC server")
+	for _, config := range configs {
+		if isUnixAddress(config.Address) {
+			log.Info().Str("address", config.Address).Str("client_auth", config.ClientAuth.String()).Str("token_auth", config.TokenAuth.String()).Msg("Listening")
+			continue
 		}
-	}()
+		log.Info().Str("address", config.Address).Str("client_auth", config.ClientAuth.String()).Str("token_auth", config.TokenAuth.String()).Bool("proxy_protocol", s.proxyProtocol).Int("listeners", listeners).Msg("Listening")
+	}
+
+	for _, conn := range conns {
+		go func(conn net.Listener) {
+			if err := s.grpcServer.Serve(conn); err != nil {
+				log.Error().Err(err).Msg("Could not start GRPC server")
+			}
+		}(conn)
+	}
 	return nil
 }
+
+// openListeners opens the listeners for the configurations, returning them
+// along with the number of listeners opened for each TCP address; Unix sockets
+// have a single listener.  If any address cannot be listened on then all
+// listeners opened so far are closed.
+func (s *Service) openListeners(configs []*Listener) ([]net.Listener, int, error) {
+	// With SO_REUSEPORT the kernel spreads incoming connections across
+	// multiple listeners on the same port, each with its own acceptor.
+	listeners := 1
+	if s.reusePort {
+		listeners = s.reusePortListeners()
+	}
+	conns := make([]net.Listener, 0, listeners*len(configs))
+	for _, config := range configs {
+		address := config.Address
+		count := listeners
+		if isUnixAddress(address) {
+			count = 1
+		}
+		for i := 0; i < count; i++ {
+			var conn net.Listener
+			var err error
+			if isUnixAddress(address) {
+				conn, err = s.listenUnix(unixSocketPath(address), config.SocketMode)
+			} else {
+				conn, err = s.listen(address)
+			}
+			if err != nil {
+				for _, conn := range conns {
+					if err := conn.Close(); err != nil {
+						log.Warn().Err(err).Msg("Failed to close listener")
+					}
+				}
+				return nil, 0, errors.Wrapf(err, "failed to listen on %s", config.Address)
+			}
+			conns = append(conns, newPolicyListener(conn, config))
+			// Later listeners bind to the port of the first, which is chosen by the
+			// system if the address has port 0.
+			address = conn.Addr().String()
+		}
+	}
+	return conns, listeners, nil
+}
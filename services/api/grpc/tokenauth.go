@@ -0,0 +1,60 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpc
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TokenAuth is the policy for bearer tokens on a listener.
+type TokenAuth int
+
+const (
+	// TokenAuthNone ignores bearer tokens.
+	TokenAuthNone TokenAuth = iota
+	// TokenAuthOptional verifies a bearer token if supplied, alongside client certificates.
+	TokenAuthOptional
+	// TokenAuthRequire requires clients to present a valid bearer token.
+	TokenAuthRequire
+)
+
+var tokenAuthStrings = [...]string{
+	"none",
+	"optional",
+	"require",
+}
+
+// String returns the string representation of the token auth policy.
+func (t TokenAuth) String() string {
+	if int(t) < 0 || int(t) >= len(tokenAuthStrings) {
+		return "unknown"
+	}
+	return tokenAuthStrings[t]
+}
+
+// ParseTokenAuth parses a token auth policy from its string representation.
+// An empty string is treated as "none".
+func ParseTokenAuth(input string) (TokenAuth, error) {
+	switch strings.ToLower(input) {
+	case "", "none":
+		return TokenAuthNone, nil
+	case "optional":
+		return TokenAuthOptional, nil
+	case "require":
+		return TokenAuthRequire, nil
+	default:
+		return TokenAuthNone, fmt.Errorf("unrecognised token auth policy %q", input)
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/pkg/errors"
+)
+
+// jsonWebKey is the subset of a JSON web key used for signature verification.
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+type jsonWebKeySet struct {
+	Keys []*jsonWebKey `json:"keys"`
+}
+
+// parseJWKS parses a JSON web key set, returning the signing keys it contains keyed by ID.
+func parseJWKS(data []byte) (map[string]interface{}, error) {
+	var keySet jsonWebKeySet
+	if err := json.Unmarshal(data, &keySet); err != nil {
+		return nil, errors.Wrap(err, "invalid JSON")
+	}
+
+	keys := make(map[string]interface{})
+	for i, key := range keySet.Keys {
+		if key.Use != "" && key.Use != "sig" {
+			// Not a signing key.
+			continue
+		}
+		if _, exists := keys[key.Kid]; exists {
+			return nil, fmt.Errorf("duplicate key ID %q", key.Kid)
+		}
+		var err error
+		switch key.Kty {
+		case "RSA":
+			keys[key.Kid], err = rsaPublicKey(key)
+		case "EC":
+			keys[key.Kid], err = ecdsaPublicKey(key)
+		default:
+			err = fmt.Errorf("unsupported key type %q", key.Kty)
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf("invalid key %d", i))
+		}
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("no signing keys")
+	}
+
+	return keys, nil
+}
+
+func rsaPublicKey(key *jsonWebKey) (*rsa.PublicKey, error) {
+	n, err := decodeBigInt(key.N)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid modulus")
+	}
+	e, err := decodeBigInt(key.E)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid exponent")
+	}
+	if !e.IsInt64() || e.Int64() < 3 || e.Int64() > 1<<31-1 {
+		return nil, errors.New("invalid exponent")
+	}
+	return &rsa.PublicKey{
+		N: n,
+		E: int(e.Int64()),
+	}, nil
+}
+
+func ecdsaPublicKey(key *jsonWebKey) (*ecdsa.PublicKey, error) {
+	var curve elliptic.Curve
+	switch key.Crv {
+	case "P-256":
+		curve = elliptic.P256()
+	case "P-384":
+		curve = elliptic.P384()
+	case "P-521":
+		curve = elliptic.P521()
+	default:
+		return nil, fmt.Errorf("unsupported curve %q", key.Crv)
+	}
+	x, err := decodeBigInt(key.X)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid x coordinate")
+	}
+	y, err := decodeBigInt(key.Y)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid y coordinate")
+	}
+	if !curve.IsOnCurve(x, y) {
+		return nil, errors.New("point not on curve")
+	}
+	return &ecdsa.PublicKey{
+		Curve: curve,
+		X:     x,
+		Y:     y,
+	}, nil
+}
+
+func decodeBigInt(input string) (*big.Int, error) {
+	if input == "" {
+		return nil, errors.New("missing")
+	}
+	data, err := base64.RawURLEncoding.DecodeString(input)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(data), nil
+}
@@ -0,0 +1,130 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwt
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+type parameters struct {
+	logLevel   zerolog.Level
+	issuer     string
+	audience   string
+	jwks       []byte
+	claim      string
+	identities map[string]string
+	clockSkew  time.Duration
+}
+
+// Parameter is the interface for service parameters.
+type Parameter interface {
+	apply(*parameters)
+}
+
+type parameterFunc func(*parameters)
+
+func (f parameterFunc) apply(p *parameters) {
+	f(p)
+}
+
+// WithLogLevel sets the log level for the module.
+func WithLogLevel(logLevel zerolog.Level) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.logLevel = logLevel
+	})
+}
+
+// WithIssuer sets the required issuer of tokens.
+func WithIssuer(issuer string) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.issuer = issuer
+	})
+}
+
+// WithAudience sets the required audience of tokens.
+func WithAudience(audience string) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.audience = audience
+	})
+}
+
+// WithJWKS sets the JSON web key set used to verify token signatures.
+func WithJWKS(jwks []byte) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.jwks = jwks
+	})
+}
+
+// WithClaim sets the claim used to identify the client.
+func WithClaim(claim string) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.claim = claim
+	})
+}
+
+// WithIdentities sets the mapping from claim values to client names.
+func WithIdentities(identities map[string]string) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.identities = identities
+	})
+}
+
+// WithClockSkew sets the tolerance applied to the time-based claims of tokens.
+func WithClockSkew(clockSkew time.Duration) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.clockSkew = clockSkew
+	})
+}
+
+// parseAndCheckParameters parses and checks parameters to ensure that mandatory parameters are present and correct.
+func parseAndCheckParameters(params ...Parameter) (*parameters, error) {
+	parameters := parameters{
+		logLevel: zerolog.GlobalLevel(),
+		claim:    "sub",
+	}
+	for _, p := range params {
+		if params != nil {
+			p.apply(&parameters)
+		}
+	}
+
+	if parameters.issuer == "" {
+		return nil, errors.New("no issuer specified")
+	}
+	if parameters.audience == "" {
+		return nil, errors.New("no audience specified")
+	}
+	if len(parameters.jwks) == 0 {
+		return nil, errors.New("no JWKS specified")
+	}
+	if parameters.claim == "" {
+		return nil, errors.New("no claim specified")
+	}
+	if parameters.clockSkew < 0 {
+		return nil, errors.New("clock skew cannot be negative")
+	}
+	if len(parameters.identities) == 0 {
+		return nil, errors.New("no identities specified")
+	}
+	for value, identity := range parameters.identities {
+		if identity == "" {
+			return nil, errors.Errorf("empty identity for claim value %q", value)
+		}
+	}
+
+	return &parameters, nil
+}
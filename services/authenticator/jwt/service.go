@@ -0,0 +1,159 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwt
+
+import (
+	"context"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	zerologger "github.com/rs/zerolog/log"
+)
+
+// Service authenticates clients using JSON web tokens.
+type Service struct {
+	issuer     string
+	audience   string
+	keys       map[string]interface{}
+	claim      string
+	identities map[string]string
+	clockSkew  time.Duration
+	parser     *jwt.Parser
+}
+
+// module-wide log.
+var log zerolog.Logger
+
+// New creates a new JWT authenticator.
+func New(ctx context.Context, params ...Parameter) (*Service, error) {
+	parameters, err := parseAndCheckParameters(params...)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem with parameters")
+	}
+
+	// Set logging.
+	log = zerologger.With().Str("service", "authenticator").Str("impl", "jwt").Logger()
+	if parameters.logLevel != log.GetLevel() {
+		log = log.Level(parameters.logLevel)
+	}
+
+	keys, err := parseJWKS(parameters.jwks)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse JWKS")
+	}
+	log.Trace().Int("keys", len(keys)).Msg("Obtained signing keys")
+
+	return &Service{
+		issuer:     parameters.issuer,
+		audience:   parameters.audience,
+		keys:       keys,
+		claim:      parameters.claim,
+		identities: parameters.identities,
+		clockSkew:  parameters.clockSkew,
+		parser: &jwt.Parser{
+			// Only asymmetric methods are allowed, as the keys come from a JWKS.
+			ValidMethods: []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"},
+			// Time-based claims are checked separately, to allow for clock skew.
+			SkipClaimsValidation: true,
+		},
+	}, nil
+}
+
+// Authenticate authenticates a token, returning the client name to which it maps.
+func (s *Service) Authenticate(ctx context.Context, token string) (string, error) {
+	claims := jwt.MapClaims{}
+	if _, err := s.parser.ParseWithClaims(token, claims, s.key); err != nil {
+		return "", errors.Wrap(err, "invalid token")
+	}
+
+	if err := s.verifyTimes(claims, time.Now()); err != nil {
+		return "", err
+	}
+	if !claims.VerifyIssuer(s.issuer, true) {
+		return "", errors.New("token has incorrect issuer")
+	}
+	if !claims.VerifyAudience(s.audience, true) {
+		return "", errors.New("token has incorrect audience")
+	}
+
+	value, ok := claims[s.claim].(string)
+	if !ok || value == "" {
+		return "", errors.New("token has no identity claim")
+	}
+	// Identities are only obtained from the explicit mapping, so a token
+	// cannot assert an arbitrary client name.
+	identity, exists := s.identities[value]
+	if !exists {
+		log.Debug().Str("claim", s.claim).Str("value", value).Msg("No identity for claim")
+		return "", errors.New("token identity not recognised")
+	}
+
+	return identity, nil
+}
+
+// verifyTimes verifies the time-based claims of a token, allowing for clock skew.
+// The expiry claim must be present; the not before and issued at claims are
+// checked if present.
+func (s *Service) verifyTimes(claims jwt.MapClaims, now time.Time) error {
+	if _, exists := claims["exp"]; !exists {
+		return errors.New("token has no expiry")
+	}
+
+	strict := now.Unix()
+	// Claims are compared as if it were at either end of the tolerated skew.
+	early := now.Add(-s.clockSkew).Unix()
+	late := now.Add(s.clockSkew).Unix()
+	skewed := false
+
+	if !claims.VerifyExpiresAt(strict, true) {
+		if !claims.VerifyExpiresAt(early, true) {
+			return errors.New("invalid token: Token is expired")
+		}
+		skewed = true
+	}
+	if !claims.VerifyNotBefore(strict, false) {
+		if !claims.VerifyNotBefore(late, false) {
+			return errors.New("invalid token: Token is not valid yet")
+		}
+		skewed = true
+	}
+	if !claims.VerifyIssuedAt(strict, false) {
+		if !claims.VerifyIssuedAt(late, false) {
+			return errors.New("invalid token: Token used before issued")
+		}
+		skewed = true
+	}
+
+	if skewed {
+		log.Info().Dur("clock_skew", s.clockSkew).Msg("Token accepted only due to clock skew tolerance")
+	}
+	return nil
+}
+
+// key provides the key with which to verify a token.
+func (s *Service) key(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" && len(s.keys) == 1 {
+		for _, key := range s.keys {
+			return key, nil
+		}
+	}
+	key, exists := s.keys[kid]
+	if !exists {
+		return nil, errors.New("unknown key")
+	}
+	return key, nil
+}
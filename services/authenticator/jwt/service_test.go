@@ -0,0 +1,416 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwt_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/attestantio/dirk/services/authenticator/jwt"
+	jwtlib "github.com/golang-jwt/jwt/v4"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func encode(i *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(i.Bytes())
+}
+
+func TestService(t *testing.T) {
+	ctx := context.Background()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	jwks := []byte(fmt.Sprintf(`{"keys":[{"kty":"RSA","kid":"rsa","use":"sig","n":"%s","e":"%s"},{"kty":"EC","kid":"ec","crv":"P-256","x":"%s","y":"%s"}]}`,
+		encode(rsaKey.N), encode(big.NewInt(int64(rsaKey.E))), encode(ecKey.X), encode(ecKey.Y)))
+
+	service, err := jwt.New(ctx,
+		jwt.WithLogLevel(zerolog.Disabled),
+		jwt.WithIssuer("https://issuer.example.com/"),
+		jwt.WithAudience("dirk"),
+		jwt.WithJWKS(jwks),
+		jwt.WithIdentities(map[string]string{
+			"user-1": "client1",
+		}),
+	)
+	require.NoError(t, err)
+
+	validClaims := func() jwtlib.MapClaims {
+		return jwtlib.MapClaims{
+			"iss": "https://issuer.example.com/",
+			"aud": "dirk",
+			"sub": "user-1",
+			"exp": time.Now().Add(time.Hour).Unix(),
+		}
+	}
+	sign := func(method jwtlib.SigningMethod, kid string, key interface{}, claims jwtlib.MapClaims) string {
+		token := jwtlib.NewWithClaims(method, claims)
+		if kid != "" {
+			token.Header["kid"] = kid
+		}
+		res, err := token.SignedString(key)
+		require.NoError(t, err)
+		return res
+	}
+
+	tests := []struct {
+		name     string
+		token    func() string
+		identity string
+		err      string
+	}{
+		{
+			name: "Garbage",
+			token: func() string {
+				return "not a token"
+			},
+			err: "invalid token: token contains an invalid number of segments",
+		},
+		{
+			name: "RSA",
+			token: func() string {
+				return sign(jwtlib.SigningMethodRS256, "rsa", rsaKey, validClaims())
+			},
+			identity: "client1",
+		},
+		{
+			name: "EC",
+			token: func() string {
+				return sign(jwtlib.SigningMethodES256, "ec", ecKey, validClaims())
+			},
+			identity: "client1",
+		},
+		{
+			name: "UnknownKey",
+			token: func() string {
+				return sign(jwtlib.SigningMethodRS256, "unknown", rsaKey, validClaims())
+			},
+			err: "invalid token: unknown key",
+		},
+		{
+			name: "WrongKey",
+			token: func() string {
+				return sign(jwtlib.SigningMethodRS256, "rsa", otherKey, validClaims())
+			},
+			err: "invalid token: crypto/rsa: verification error",
+		},
+		{
+			name: "Symmetric",
+			token: func() string {
+				return sign(jwtlib.SigningMethodHS256, "rsa", []byte("secret"), validClaims())
+			},
+			err: "invalid token: signing method HS256 is invalid",
+		},
+		{
+			name: "Expired",
+			token: func() string {
+				claims := validClaims()
+				claims["exp"] = time.Now().Add(-time.Minute).Unix()
+				return sign(jwtlib.SigningMethodRS256, "rsa", rsaKey, claims)
+			},
+			err: "invalid token: Token is expired",
+		},
+		{
+			name: "NoExpiry",
+			token: func() string {
+				claims := validClaims()
+				delete(claims, "exp")
+				return sign(jwtlib.SigningMethodRS256, "rsa", rsaKey, claims)
+			},
+			err: "token has no expiry",
+		},
+		{
+			name: "NotYetValid",
+			token: func() string {
+				claims := validClaims()
+				claims["nbf"] = time.Now().Add(time.Minute).Unix()
+				return sign(jwtlib.SigningMethodRS256, "rsa", rsaKey, claims)
+			},
+			err: "invalid token: Token is not valid yet",
+		},
+		{
+			name: "WrongIssuer",
+			token: func() string {
+				claims := validClaims()
+				claims["iss"] = "https://other.example.com/"
+				return sign(jwtlib.SigningMethodRS256, "rsa", rsaKey, claims)
+			},
+			err: "token has incorrect issuer",
+		},
+		{
+			name: "WrongAudience",
+			token: func() string {
+				claims := validClaims()
+				claims["aud"] = "other"
+				return sign(jwtlib.SigningMethodRS256, "rsa", rsaKey, claims)
+			},
+			err: "token has incorrect audience",
+		},
+		{
+			name: "AudienceList",
+			token: func() string {
+				claims := validClaims()
+				claims["aud"] = []string{"other", "dirk"}
+				return sign(jwtlib.SigningMethodRS256, "rsa", rsaKey, claims)
+			},
+			identity: "client1",
+		},
+		{
+			name: "NoClaim",
+			token: func() string {
+				claims := validClaims()
+				delete(claims, "sub")
+				return sign(jwtlib.SigningMethodRS256, "rsa", rsaKey, claims)
+			},
+			err: "token has no identity claim",
+		},
+		{
+			name: "UnmappedClaim",
+			token: func() string {
+				claims := validClaims()
+				claims["sub"] = "client1"
+				return sign(jwtlib.SigningMethodRS256, "rsa", rsaKey, claims)
+			},
+			err: "token identity not recognised",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			identity, err := service.Authenticate(ctx, test.token())
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, test.identity, identity)
+			}
+		})
+	}
+}
+
+func TestClockSkew(t *testing.T) {
+	ctx := context.Background()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	jwks := []byte(fmt.Sprintf(`{"keys":[{"kty":"RSA","kid":"rsa","use":"sig","n":"%s","e":"%s"}]}`,
+		encode(rsaKey.N), encode(big.NewInt(int64(rsaKey.E)))))
+
+	service, err := jwt.New(ctx,
+		jwt.WithLogLevel(zerolog.Disabled),
+		jwt.WithIssuer("https://issuer.example.com/"),
+		jwt.WithAudience("dirk"),
+		jwt.WithJWKS(jwks),
+		jwt.WithIdentities(map[string]string{
+			"user-1": "client1",
+		}),
+		jwt.WithClockSkew(30*time.Second),
+	)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		claims map[string]interface{}
+		err    string
+	}{
+		{
+			name: "ExpiredWithinSkew",
+			claims: map[string]interface{}{
+				"exp": time.Now().Add(-10 * time.Second).Unix(),
+			},
+		},
+		{
+			name: "ExpiredBeyondSkew",
+			claims: map[string]interface{}{
+				"exp": time.Now().Add(-time.Minute).Unix(),
+			},
+			err: "invalid token: Token is expired",
+		},
+		{
+			name: "NotBeforeWithinSkew",
+			claims: map[string]interface{}{
+				"exp": time.Now().Add(time.Hour).Unix(),
+				"nbf": time.Now().Add(10 * time.Second).Unix(),
+			},
+		},
+		{
+			name: "NotBeforeBeyondSkew",
+			claims: map[string]interface{}{
+				"exp": time.Now().Add(time.Hour).Unix(),
+				"nbf": time.Now().Add(time.Minute).Unix(),
+			},
+			err: "invalid token: Token is not valid yet",
+		},
+		{
+			name: "IssuedAtWithinSkew",
+			claims: map[string]interface{}{
+				"exp": time.Now().Add(time.Hour).Unix(),
+				"iat": time.Now().Add(10 * time.Second).Unix(),
+			},
+		},
+		{
+			name: "IssuedAtBeyondSkew",
+			claims: map[string]interface{}{
+				"exp": time.Now().Add(time.Hour).Unix(),
+				"iat": time.Now().Add(time.Minute).Unix(),
+			},
+			err: "invalid token: Token used before issued",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			claims := jwtlib.MapClaims{
+				"iss": "https://issuer.example.com/",
+				"aud": "dirk",
+				"sub": "user-1",
+			}
+			for k, v := range test.claims {
+				claims[k] = v
+			}
+			token, err := jwtlib.NewWithClaims(jwtlib.SigningMethodRS256, claims).SignedString(rsaKey)
+			require.NoError(t, err)
+			identity, err := service.Authenticate(ctx, token)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, "client1", identity)
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	ctx := context.Background()
+
+	jwks := []byte(`{"keys":[{"kty":"EC","kid":"ec","crv":"P-256","x":"MKBCTNIcKUSDii11ySs3526iDZ8AiTo7Tu6KPAqv7D4","y":"4Etl6SRW2YiLUrN5vfvVHuhp7x8PxltmWWlbbM4IFyM"}]}`)
+
+	tests := []struct {
+		name   string
+		params []jwt.Parameter
+		err    string
+	}{
+		{
+			name: "IssuerMissing",
+			params: []jwt.Parameter{
+				jwt.WithAudience("dirk"),
+				jwt.WithJWKS(jwks),
+				jwt.WithIdentities(map[string]string{"a": "b"}),
+			},
+			err: "problem with parameters: no issuer specified",
+		},
+		{
+			name: "AudienceMissing",
+			params: []jwt.Parameter{
+				jwt.WithIssuer("issuer"),
+				jwt.WithJWKS(jwks),
+				jwt.WithIdentities(map[string]string{"a": "b"}),
+			},
+			err: "problem with parameters: no audience specified",
+		},
+		{
+			name: "JWKSMissing",
+			params: []jwt.Parameter{
+				jwt.WithIssuer("issuer"),
+				jwt.WithAudience("dirk"),
+				jwt.WithIdentities(map[string]string{"a": "b"}),
+			},
+			err: "problem with parameters: no JWKS specified",
+		},
+		{
+			name: "IdentitiesMissing",
+			params: []jwt.Parameter{
+				jwt.WithIssuer("issuer"),
+				jwt.WithAudience("dirk"),
+				jwt.WithJWKS(jwks),
+			},
+			err: "problem with parameters: no identities specified",
+		},
+		{
+			name: "IdentityEmpty",
+			params: []jwt.Parameter{
+				jwt.WithIssuer("issuer"),
+				jwt.WithAudience("dirk"),
+				jwt.WithJWKS(jwks),
+				jwt.WithIdentities(map[string]string{"a": ""}),
+			},
+			err: `problem with parameters: empty identity for claim value "a"`,
+		},
+		{
+			name: "JWKSInvalid",
+			params: []jwt.Parameter{
+				jwt.WithIssuer("issuer"),
+				jwt.WithAudience("dirk"),
+				jwt.WithJWKS([]byte("bad")),
+				jwt.WithIdentities(map[string]string{"a": "b"}),
+			},
+			err: "failed to parse JWKS: invalid JSON: invalid character 'b' looking for beginning of value",
+		},
+		{
+			name: "JWKSNoSigningKeys",
+			params: []jwt.Parameter{
+				jwt.WithIssuer("issuer"),
+				jwt.WithAudience("dirk"),
+				jwt.WithJWKS([]byte(`{"keys":[{"kty":"RSA","use":"enc","n":"AQAB","e":"AQAB"}]}`)),
+				jwt.WithIdentities(map[string]string{"a": "b"}),
+			},
+			err: "failed to parse JWKS: no signing keys",
+		},
+		{
+			name: "JWKSBadPoint",
+			params: []jwt.Parameter{
+				jwt.WithIssuer("issuer"),
+				jwt.WithAudience("dirk"),
+				jwt.WithJWKS([]byte(`{"keys":[{"kty":"EC","crv":"P-256","x":"AQ","y":"AQ"}]}`)),
+				jwt.WithIdentities(map[string]string{"a": "b"}),
+			},
+			err: "failed to parse JWKS: invalid key 0: point not on curve",
+		},
+		{
+			name: "Good",
+			params: []jwt.Parameter{
+				jwt.WithLogLevel(zerolog.Disabled),
+				jwt.WithIssuer("issuer"),
+				jwt.WithAudience("dirk"),
+				jwt.WithJWKS(jwks),
+				jwt.WithIdentities(map[string]string{"a": "b"}),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := jwt.New(ctx, test.params...)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authenticator
+
+import "context"
+
+// Service is the interface for authenticating clients by token.
+type Service interface {
+	// Authenticate authenticates a token, returning the client name to which it maps.
+	Authenticate(ctx context.Context, token string) (string, error)
+}
@@ -0,0 +1,42 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard
+
+import "time"
+
+// network contains the chain time configuration of a well-known network.
+type network struct {
+	genesisTime   time.Time
+	slotDuration  time.Duration
+	slotsPerEpoch uint64
+}
+
+// networks are the well-known networks, keyed by lower-case name.
+var networks = map[string]*network{
+	"mainnet": {
+		genesisTime:   time.Unix(1606824023, 0),
+		slotDuration:  12 * time.Second,
+		slotsPerEpoch: 32,
+	},
+	"prater": {
+		genesisTime:   time.Unix(1616508000, 0),
+		slotDuration:  12 * time.Second,
+		slotsPerEpoch: 32,
+	},
+	"pyrmont": {
+		genesisTime:   time.Unix(1605700807, 0),
+		slotDuration:  12 * time.Second,
+		slotsPerEpoch: 32,
+	},
+}
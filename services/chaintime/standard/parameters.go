@@ -0,0 +1,119 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+type parameters struct {
+	logLevel      zerolog.Level
+	network       string
+	genesisTime   time.Time
+	slotDuration  time.Duration
+	slotsPerEpoch uint64
+}
+
+// Parameter is the interface for service parameters.
+type Parameter interface {
+	apply(*parameters)
+}
+
+type parameterFunc func(*parameters)
+
+func (f parameterFunc) apply(p *parameters) {
+	f(p)
+}
+
+// WithLogLevel sets the log level for the module.
+func WithLogLevel(logLevel zerolog.Level) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.logLevel = logLevel
+	})
+}
+
+// WithNetwork sets the network for the module.
+// Values for a known network are used for any of genesis time, slot duration
+// and slots per epoch that are not supplied explicitly.
+func WithNetwork(network string) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.network = network
+	})
+}
+
+// WithGenesisTime sets the genesis time of the chain.
+func WithGenesisTime(genesisTime time.Time) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.genesisTime = genesisTime
+	})
+}
+
+// WithSlotDuration sets the duration of a slot.
+func WithSlotDuration(slotDuration time.Duration) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.slotDuration = slotDuration
+	})
+}
+
+// WithSlotsPerEpoch sets the number of slots in an epoch.
+func WithSlotsPerEpoch(slotsPerEpoch uint64) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.slotsPerEpoch = slotsPerEpoch
+	})
+}
+
+// parseAndCheckParameters parses and checks parameters to ensure that mandatory parameters are present and correct.
+func parseAndCheckParameters(params ...Parameter) (*parameters, error) {
+	parameters := parameters{
+		logLevel: zerolog.GlobalLevel(),
+	}
+	for _, p := range params {
+		if params != nil {
+			p.apply(&parameters)
+		}
+	}
+
+	if parameters.network != "" {
+		network, exists := networks[strings.ToLower(parameters.network)]
+		if !exists {
+			return nil, fmt.Errorf("unknown network %q", parameters.network)
+		}
+		if parameters.genesisTime.IsZero() {
+			parameters.genesisTime = network.genesisTime
+		}
+		if parameters.slotDuration == 0 {
+			parameters.slotDuration = network.slotDuration
+		}
+		if parameters.slotsPerEpoch == 0 {
+			parameters.slotsPerEpoch = network.slotsPerEpoch
+		}
+	}
+
+	if parameters.genesisTime.IsZero() {
+		return nil, errors.New("no genesis time specified")
+	}
+	if parameters.slotDuration <= 0 {
+		return nil, errors.New("slot duration must be positive")
+	}
+	if parameters.slotsPerEpoch == 0 {
+		return nil, errors.New("no slots per epoch specified")
+	}
+
+	return &parameters, nil
+}
@@ -0,0 +1,102 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	zerologger "github.com/rs/zerolog/log"
+)
+
+// Service provides chain time information from static configuration.
+type Service struct {
+	genesisTime   time.Time
+	slotDuration  time.Duration
+	slotsPerEpoch uint64
+}
+
+// module-wide log.
+var log zerolog.Logger
+
+// New creates a new chain time service.
+func New(ctx context.Context, params ...Parameter) (*Service, error) {
+	parameters, err := parseAndCheckParameters(params...)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem with parameters")
+	}
+
+	// Set logging.
+	log = zerologger.With().Str("service", "chaintime").Str("impl", "standard").Logger()
+	if parameters.logLevel != log.GetLevel() {
+		log = log.Level(parameters.logLevel)
+	}
+
+	s := &Service{
+		genesisTime:   parameters.genesisTime,
+		slotDuration:  parameters.slotDuration,
+		slotsPerEpoch: parameters.slotsPerEpoch,
+	}
+	log.Trace().Time("genesis_time", s.genesisTime).Dur("slot_duration", s.slotDuration).Uint64("slots_per_epoch", s.slotsPerEpoch).Msg("Chain time configured")
+
+	return s, nil
+}
+
+// GenesisTime provides the time of the chain's genesis.
+func (s *Service) GenesisTime() time.Time {
+	return s.genesisTime
+}
+
+// SlotDuration provides the duration of a single slot.
+func (s *Service) SlotDuration() time.Duration {
+	return s.slotDuration
+}
+
+// SlotsPerEpoch provides the number of slots in an epoch.
+func (s *Service) SlotsPerEpoch() uint64 {
+	return s.slotsPerEpoch
+}
+
+// StartOfSlot provides the time at which the given slot starts.
+func (s *Service) StartOfSlot(slot uint64) time.Time {
+	return s.genesisTime.Add(time.Duration(slot) * s.slotDuration)
+}
+
+// StartOfEpoch provides the time at which the given epoch starts.
+func (s *Service) StartOfEpoch(epoch uint64) time.Time {
+	return s.StartOfSlot(epoch * s.slotsPerEpoch)
+}
+
+// CurrentSlot provides the current slot.
+// Prior to genesis this is 0.
+func (s *Service) CurrentSlot() uint64 {
+	elapsed := time.Since(s.genesisTime)
+	if elapsed < 0 {
+		return 0
+	}
+	return uint64(elapsed / s.slotDuration)
+}
+
+// CurrentEpoch provides the current epoch.
+// Prior to genesis this is 0.
+func (s *Service) CurrentEpoch() uint64 {
+	return s.SlotToEpoch(s.CurrentSlot())
+}
+
+// SlotToEpoch provides the epoch of the given slot.
+func (s *Service) SlotToEpoch(slot uint64) uint64 {
+	return slot / s.slotsPerEpoch
+}
@@ -0,0 +1,172 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/attestantio/dirk/services/chaintime/standard"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	ctx := context.Background()
+	genesisTime := time.Unix(1600000000, 0)
+
+	tests := []struct {
+		name          string
+		params        []standard.Parameter
+		err           string
+		genesisTime   time.Time
+		slotDuration  time.Duration
+		slotsPerEpoch uint64
+	}{
+		{
+			name: "Empty",
+			err:  "problem with parameters: no genesis time specified",
+		},
+		{
+			name: "SlotDurationMissing",
+			params: []standard.Parameter{
+				standard.WithLogLevel(zerolog.Disabled),
+				standard.WithGenesisTime(genesisTime),
+				standard.WithSlotsPerEpoch(32),
+			},
+			err: "problem with parameters: slot duration must be positive",
+		},
+		{
+			name: "SlotDurationNegative",
+			params: []standard.Parameter{
+				standard.WithLogLevel(zerolog.Disabled),
+				standard.WithGenesisTime(genesisTime),
+				standard.WithSlotDuration(-12 * time.Second),
+				standard.WithSlotsPerEpoch(32),
+			},
+			err: "problem with parameters: slot duration must be positive",
+		},
+		{
+			name: "SlotsPerEpochMissing",
+			params: []standard.Parameter{
+				standard.WithLogLevel(zerolog.Disabled),
+				standard.WithGenesisTime(genesisTime),
+				standard.WithSlotDuration(12 * time.Second),
+			},
+			err: "problem with parameters: no slots per epoch specified",
+		},
+		{
+			name: "NetworkUnknown",
+			params: []standard.Parameter{
+				standard.WithLogLevel(zerolog.Disabled),
+				standard.WithNetwork("unknown"),
+			},
+			err: `problem with parameters: unknown network "unknown"`,
+		},
+		{
+			name: "Good",
+			params: []standard.Parameter{
+				standard.WithLogLevel(zerolog.Disabled),
+				standard.WithGenesisTime(genesisTime),
+				standard.WithSlotDuration(12 * time.Second),
+				standard.WithSlotsPerEpoch(32),
+			},
+			genesisTime:   genesisTime,
+			slotDuration:  12 * time.Second,
+			slotsPerEpoch: 32,
+		},
+		{
+			name: "Network",
+			params: []standard.Parameter{
+				standard.WithLogLevel(zerolog.Disabled),
+				standard.WithNetwork("Mainnet"),
+			},
+			genesisTime:   time.Unix(1606824023, 0),
+			slotDuration:  12 * time.Second,
+			slotsPerEpoch: 32,
+		},
+		{
+			name: "NetworkOverride",
+			params: []standard.Parameter{
+				standard.WithLogLevel(zerolog.Disabled),
+				standard.WithNetwork("mainnet"),
+				standard.WithGenesisTime(genesisTime),
+				standard.WithSlotDuration(6 * time.Second),
+			},
+			genesisTime:   genesisTime,
+			slotDuration:  6 * time.Second,
+			slotsPerEpoch: 32,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s, err := standard.New(ctx, test.params...)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, test.genesisTime, s.GenesisTime())
+				require.Equal(t, test.slotDuration, s.SlotDuration())
+				require.Equal(t, test.slotsPerEpoch, s.SlotsPerEpoch())
+			}
+		})
+	}
+}
+
+func TestTimes(t *testing.T) {
+	ctx := context.Background()
+	genesisTime := time.Unix(1600000000, 0)
+
+	s, err := standard.New(ctx,
+		standard.WithLogLevel(zerolog.Disabled),
+		standard.WithGenesisTime(genesisTime),
+		standard.WithSlotDuration(12*time.Second),
+		standard.WithSlotsPerEpoch(32),
+	)
+	require.NoError(t, err)
+
+	require.Equal(t, genesisTime, s.StartOfSlot(0))
+	require.Equal(t, genesisTime.Add(12*time.Second), s.StartOfSlot(1))
+	require.Equal(t, genesisTime.Add(384*time.Second), s.StartOfEpoch(1))
+	require.Equal(t, uint64(0), s.SlotToEpoch(31))
+	require.Equal(t, uint64(1), s.SlotToEpoch(32))
+}
+
+func TestCurrent(t *testing.T) {
+	ctx := context.Background()
+
+	// Genesis 10 epochs and a bit ago.
+	s, err := standard.New(ctx,
+		standard.WithLogLevel(zerolog.Disabled),
+		standard.WithGenesisTime(time.Now().Add(-(320*12+6)*time.Second)),
+		standard.WithSlotDuration(12*time.Second),
+		standard.WithSlotsPerEpoch(32),
+	)
+	require.NoError(t, err)
+	require.Equal(t, uint64(320), s.CurrentSlot())
+	require.Equal(t, uint64(10), s.CurrentEpoch())
+
+	// Genesis in the future.
+	s, err = standard.New(ctx,
+		standard.WithLogLevel(zerolog.Disabled),
+		standard.WithGenesisTime(time.Now().Add(time.Hour)),
+		standard.WithSlotDuration(12*time.Second),
+		standard.WithSlotsPerEpoch(32),
+	)
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), s.CurrentSlot())
+	require.Equal(t, uint64(0), s.CurrentEpoch())
+}
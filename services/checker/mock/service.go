@@ -36,7 +36,7 @@ func New() (checker.Service, error) {
 			},
 			{
 				Path:       ".*",
-				Operations: []string{"All"},
+				Operations: []string{"All", "Delete account", "Reshare account", "Check sign", "Generate deposit data", "Sign tagged", "Sign root", "Sign BLS to execution change"},
 			},
 		},
 	}
@@ -1,4 +1,4 @@
-// Copyright © 2020 Attestant Limited.
+// Copyright © 2020, 2021 Attestant Limited.
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
 // You may obtain a copy of the License at
@@ -14,14 +14,39 @@
 package checker
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
+	"sort"
 	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// RolePrefix is the prefix that marks an operation as a reference to a role.
+const RolePrefix = "@"
+
+// Effects of permissions.
+const (
+	// EffectAllow allows the operations of a permission.
+	EffectAllow = "allow"
+	// EffectDeny denies the operations of a permission.
+	EffectDeny = "deny"
 )
 
 // Permissions contains information about the operations allowed by the client.
+// If Effect is EffectDeny the operations are instead denied; an empty Effect
+// is the same as EffectAllow.
 type Permissions struct {
 	Path       string   `mapstructure:"path"`
 	Operations []string `mapstructure:"operations"`
+	Effect     string   `mapstructure:"effect"`
+}
+
+// IsDeny returns true if the permission denies its operations.
+func (p *Permissions) IsDeny() bool {
+	return strings.EqualFold(p.Effect, EffectDeny)
 }
 
 // DumpPermissions dumps permissions for our clients to stdout.
@@ -45,8 +70,169 @@ func DumpPermissions(perms map[string][]*Permissions) {
 				} else {
 					opDescriptor = fmt.Sprintf("operations %s\n", strings.Join(perm.Operations, ", "))
 				}
-				fmt.Printf(" - %s can carry out %s\n", pathDescriptor, opDescriptor)
+				if perm.IsDeny() {
+					fmt.Printf(" - DENY: %s cannot carry out %s\n", pathDescriptor, opDescriptor)
+				} else {
+					fmt.Printf(" - %s can carry out %s\n", pathDescriptor, opDescriptor)
+				}
+			}
+		}
+	}
+}
+
+// ExpandRoles expands references to roles in the operations of the supplied
+// permissions, returning permissions that contain only concrete operations.
+// A role reference is the name of the role prefixed with RolePrefix, and is
+// replaced in place by the role's operations so that ordering, and hence
+// explicit denials, are preserved.  Role names are not case sensitive.
+func ExpandRoles(perms map[string][]*Permissions, roles map[string][]string) (map[string][]*Permissions, error) {
+	lcRoles := make(map[string][]string, len(roles))
+	for name, operations := range roles {
+		if name == "" {
+			return nil, errors.New("role does not have a name")
+		}
+		if len(operations) == 0 {
+			return nil, fmt.Errorf("role %q has no operations", name)
+		}
+		for _, operation := range operations {
+			if strings.HasPrefix(operation, RolePrefix) {
+				return nil, fmt.Errorf("role %q references another role", name)
+			}
+		}
+		lcRoles[strings.ToLower(name)] = operations
+	}
+
+	res := make(map[string][]*Permissions, len(perms))
+	for client, clientPerms := range perms {
+		res[client] = make([]*Permissions, 0, len(clientPerms))
+		for _, perm := range clientPerms {
+			operations := make([]string, 0, len(perm.Operations))
+			for _, operation := range perm.Operations {
+				if !strings.HasPrefix(operation, RolePrefix) {
+					operations = append(operations, operation)
+					continue
+				}
+				name := strings.TrimPrefix(operation, RolePrefix)
+				roleOperations, exists := lcRoles[strings.ToLower(name)]
+				if !exists {
+					return nil, fmt.Errorf("unknown role %q for client %q", name, client)
+				}
+				operations = append(operations, roleOperations...)
+			}
+			res[client] = append(res[client], &Permissions{
+				Path:       perm.Path,
+				Operations: operations,
+				Effect:     perm.Effect,
+			})
+		}
+	}
+
+	return res, nil
+}
+
+// UnknownOperations returns the operations in the permissions that are not in
+// the list of known operations, in the form "client: path: operation".  The
+// "All" and "None" qualifiers are always known, operations prefixed with "~"
+// are checked without the prefix, and comparisons are case-insensitive.
+// Roles should be expanded before calling this.
+func UnknownOperations(perms map[string][]*Permissions, known []string) []string {
+	lcKnown := map[string]bool{
+		"all":  true,
+		"none": true,
+	}
+	for _, operation := range known {
+		lcKnown[strings.ToLower(operation)] = true
+	}
+
+	res := make([]string, 0)
+	for client, clientPerms := range perms {
+		for _, perm := range clientPerms {
+			for _, operation := range perm.Operations {
+				if !lcKnown[strings.ToLower(strings.TrimPrefix(operation, "~"))] {
+					res = append(res, fmt.Sprintf("%s: %s: %s", client, perm.Path, operation))
+				}
+			}
+		}
+	}
+	sort.Strings(res)
+	return res
+}
+
+// PermissionRule is a single rule in the structured representation of permissions.
+type PermissionRule struct {
+	Operation string `json:"operation" yaml:"operation"`
+	Effect    string `json:"effect" yaml:"effect"`
+}
+
+// PathPermissions is the structured representation of the permissions for a path.
+type PathPermissions struct {
+	Path   string            `json:"path" yaml:"path"`
+	Effect string            `json:"effect,omitempty" yaml:"effect,omitempty"`
+	Rules  []*PermissionRule `json:"rules" yaml:"rules"`
+}
+
+// StructurePermissions provides a structured representation of permissions, suitable
+// for machine consumption.  Paths for each client are sorted, and each operation is
+// marked with an effect of either "allow" or "deny"; the "None" qualifier and operations
+// prefixed with "~" are marked as "deny", with the prefix removed.  Paths with a deny
+// effect are marked as such, and all of their operations are marked as "deny".
+func StructurePermissions(perms map[string][]*Permissions) map[string][]*PathPermissions {
+	res := make(map[string][]*PathPermissions, len(perms))
+	for client, clientPerms := range perms {
+		pathPerms := make([]*PathPermissions, 0, len(clientPerms))
+		for _, perm := range clientPerms {
+			rules := make([]*PermissionRule, 0, len(perm.Operations))
+			for _, operation := range perm.Operations {
+				rule := &PermissionRule{
+					Operation: operation,
+					Effect:    EffectAllow,
+				}
+				switch {
+				case perm.IsDeny():
+					rule.Effect = EffectDeny
+				case strings.EqualFold(operation, "none"):
+					rule.Effect = EffectDeny
+				case strings.HasPrefix(operation, "~"):
+					rule.Operation = strings.TrimPrefix(operation, "~")
+					rule.Effect = EffectDeny
+				}
+				rules = append(rules, rule)
 			}
+			pathPerm := &PathPermissions{
+				Path:  perm.Path,
+				Rules: rules,
+			}
+			if perm.IsDeny() {
+				pathPerm.Effect = EffectDeny
+			}
+			pathPerms = append(pathPerms, pathPerm)
 		}
+		sort.Slice(pathPerms, func(i int, j int) bool {
+			return pathPerms[i].Path < pathPerms[j].Path
+		})
+		res[client] = pathPerms
+	}
+	return res
+}
+
+// WritePermissions writes permissions for our clients in the given format, which
+// can be "json" or "yaml".
+func WritePermissions(w io.Writer, perms map[string][]*Permissions, format string) error {
+	structured := StructurePermissions(perms)
+	var data []byte
+	var err error
+	switch strings.ToLower(format) {
+	case "json":
+		data, err = json.MarshalIndent(structured, "", "  ")
+		data = append(data, '\n')
+	case "yaml":
+		data, err = yaml.Marshal(structured)
+	default:
+		return fmt.Errorf("unsupported permissions format %q", format)
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal permissions")
 	}
+	_, err = w.Write(data)
+	return err
 }
@@ -0,0 +1,224 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checker_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/attestantio/dirk/services/checker"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandRoles(t *testing.T) {
+	tests := []struct {
+		name  string
+		perms map[string][]*checker.Permissions
+		roles map[string][]string
+		res   map[string][]*checker.Permissions
+		err   string
+	}{
+		{
+			name: "Empty",
+			res:  map[string][]*checker.Permissions{},
+		},
+		{
+			name: "NoRoles",
+			perms: map[string][]*checker.Permissions{
+				"client1": {{Path: "Wallet1", Operations: []string{"All"}}},
+			},
+			res: map[string][]*checker.Permissions{
+				"client1": {{Path: "Wallet1", Operations: []string{"All"}}},
+			},
+		},
+		{
+			name: "Expanded",
+			perms: map[string][]*checker.Permissions{
+				"client1": {{Path: "Wallet1", Operations: []string{"~Sign", "@Validator", "Access account"}}},
+				"client2": {{Path: "Wallet2", Operations: []string{"@validator"}}},
+			},
+			roles: map[string][]string{
+				"validator": {"Sign beacon attestation", "Sign beacon proposal", "Sign"},
+			},
+			res: map[string][]*checker.Permissions{
+				"client1": {{Path: "Wallet1", Operations: []string{"~Sign", "Sign beacon attestation", "Sign beacon proposal", "Sign", "Access account"}}},
+				"client2": {{Path: "Wallet2", Operations: []string{"Sign beacon attestation", "Sign beacon proposal", "Sign"}}},
+			},
+		},
+		{
+			name: "DenyEffect",
+			perms: map[string][]*checker.Permissions{
+				"client1": {{Path: "Wallet1/cold-*", Operations: []string{"@validator"}, Effect: checker.EffectDeny}},
+			},
+			roles: map[string][]string{
+				"validator": {"Sign beacon attestation", "Sign beacon proposal"},
+			},
+			res: map[string][]*checker.Permissions{
+				"client1": {{Path: "Wallet1/cold-*", Operations: []string{"Sign beacon attestation", "Sign beacon proposal"}, Effect: checker.EffectDeny}},
+			},
+		},
+		{
+			name: "UnknownRole",
+			perms: map[string][]*checker.Permissions{
+				"client1": {{Path: "Wallet1", Operations: []string{"@admin"}}},
+			},
+			roles: map[string][]string{
+				"validator": {"Sign"},
+			},
+			err: `unknown role "admin" for client "client1"`,
+		},
+		{
+			name: "EmptyRole",
+			roles: map[string][]string{
+				"validator": {},
+			},
+			err: `role "validator" has no operations`,
+		},
+		{
+			name: "NestedRole",
+			roles: map[string][]string{
+				"validator": {"Sign"},
+				"admin":     {"@validator"},
+			},
+			err: `role "admin" references another role`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			res, err := checker.ExpandRoles(test.perms, test.roles)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, test.res, res)
+			}
+		})
+	}
+}
+
+func TestUnknownOperations(t *testing.T) {
+	known := []string{"Sign", "Access account"}
+	tests := []struct {
+		name  string
+		perms map[string][]*checker.Permissions
+		res   []string
+	}{
+		{
+			name: "Empty",
+			res:  []string{},
+		},
+		{
+			name: "Known",
+			perms: map[string][]*checker.Permissions{
+				"client1": {{Path: "Wallet1", Operations: []string{"All", "~sign", "access Account"}}},
+				"client2": {{Path: "Wallet2", Operations: []string{"None"}}},
+			},
+			res: []string{},
+		},
+		{
+			name: "Unknown",
+			perms: map[string][]*checker.Permissions{
+				"client1": {
+					{Path: "Wallet1", Operations: []string{"Sign", "Sign attestation"}},
+					{Path: "Wallet2", Operations: []string{"~Acess account"}},
+				},
+				"client2": {{Path: "Wallet1", Operations: []string{"Everything"}}},
+			},
+			res: []string{
+				"client1: Wallet1: Sign attestation",
+				"client1: Wallet2: ~Acess account",
+				"client2: Wallet1: Everything",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.res, checker.UnknownOperations(test.perms, known))
+		})
+	}
+}
+
+func TestWritePermissions(t *testing.T) {
+	perms := map[string][]*checker.Permissions{
+		"client1": {
+			{Path: "Wallet2/.*", Operations: []string{"None"}},
+			{Path: "Wallet1", Operations: []string{"~Sign", "All"}},
+			{Path: "Wallet1/cold-*", Operations: []string{"Sign"}, Effect: checker.EffectDeny},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, checker.WritePermissions(&buf, perms, "json"))
+	require.Equal(t, `{
+  "client1": [
+    {
+      "path": "Wallet1",
+      "rules": [
+        {
+          "operation": "Sign",
+          "effect": "deny"
+        },
+        {
+          "operation": "All",
+          "effect": "allow"
+        }
+      ]
+    },
+    {
+      "path": "Wallet1/cold-*",
+      "effect": "deny",
+      "rules": [
+        {
+          "operation": "Sign",
+          "effect": "deny"
+        }
+      ]
+    },
+    {
+      "path": "Wallet2/.*",
+      "rules": [
+        {
+          "operation": "None",
+          "effect": "deny"
+        }
+      ]
+    }
+  ]
+}
+`, buf.String())
+
+	buf.Reset()
+	require.NoError(t, checker.WritePermissions(&buf, perms, "YAML"))
+	require.Equal(t, `client1:
+- path: Wallet1
+  rules:
+  - operation: Sign
+    effect: deny
+  - operation: All
+    effect: allow
+- path: Wallet1/cold-*
+  effect: deny
+  rules:
+  - operation: Sign
+    effect: deny
+- path: Wallet2/.*
+  rules:
+  - operation: None
+    effect: deny
+`, buf.String())
+
+	require.EqualError(t, checker.WritePermissions(&buf, perms, "xml"), `unsupported permissions format "xml"`)
+}
@@ -29,3 +29,11 @@ type Credentials struct {
 type Service interface {
 	Check(ctx context.Context, credentials *Credentials, account string, operation string) bool
 }
+
+// PermissionsReloader is the interface for checkers that can replace their
+// permissions without a restart.
+type PermissionsReloader interface {
+	// ReloadPermissions replaces the permissions used by the checker.  If the
+	// permissions are invalid the existing permissions remain in place.
+	ReloadPermissions(ctx context.Context, permissions map[string][]*Permissions) error
+}
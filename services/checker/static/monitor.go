@@ -16,3 +16,13 @@ package static
 // noopMonitor is a monitor that does nothing, used in plae of nil if an
 // external monitor is not supplied.
 type noopMonitor struct{}
+
+// PermissionChecked is called when a client's permission for an operation has been checked.
+func (n *noopMonitor) PermissionChecked(client string, operation string, allowed bool) {}
+
+// PermissionDenied is called when a client is denied permission for an operation.
+func (n *noopMonitor) PermissionDenied(client string, operation string) {}
+
+// AddressDenied is called when a client is denied because its request
+// came from an address outside of its allowed IP ranges.
+func (n *noopMonitor) AddressDenied(client string) {}
@@ -15,6 +15,7 @@ package static
 
 import (
 	"fmt"
+	"net"
 	"regexp"
 	"strings"
 
@@ -22,7 +23,6 @@ import (
 	"github.com/attestantio/dirk/services/metrics"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
-	e2wallet "github.com/wealdtech/go-eth2-wallet"
 )
 
 type parameters struct {
@@ -30,6 +30,8 @@ type parameters struct {
 	monitor     metrics.CheckerMonitor
 	permissions map[string][]*checker.Permissions
 	access      map[string][]*path
+	allowedIPs  map[string][]string
+	networks    map[string][]*net.IPNet
 }
 
 // Parameter is the interface for service parameters.
@@ -64,6 +66,15 @@ func WithPermissions(permissions map[string][]*checker.Permissions) Parameter {
 	})
 }
 
+// WithAllowedIPs sets the IP ranges, in CIDR notation, from which each client
+// can make requests.  Clients that are not present can make requests from any
+// address.
+func WithAllowedIPs(allowedIPs map[string][]string) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.allowedIPs = allowedIPs
+	})
+}
+
 // parseAndCheckParameters parses and checks parameters to ensure that mandatory parameters are present and correct.
 func parseAndCheckParameters(params ...Parameter) (*parameters, error) {
 	parameters := parameters{
@@ -80,44 +91,77 @@ func parseAndCheckParameters(params ...Parameter) (*parameters, error) {
 		parameters.monitor = &noopMonitor{}
 	}
 
-	parameters.access = make(map[string][]*path, len(parameters.permissions))
-	for client, permissions := range parameters.permissions {
+	var err error
+	parameters.access, err = compileAccess(parameters.permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	parameters.networks = make(map[string][]*net.IPNet, len(parameters.allowedIPs))
+	for client, allowedIPs := range parameters.allowedIPs {
+		if _, exists := parameters.access[client]; !exists {
+			return nil, fmt.Errorf("allowed IPs supplied for client %s which has no permissions", client)
+		}
+		if len(allowedIPs) == 0 {
+			return nil, fmt.Errorf("client %s requires at least one allowed IP range", client)
+		}
+		networks := make([]*net.IPNet, len(allowedIPs))
+		for i, allowedIP := range allowedIPs {
+			network, err := parseNetwork(allowedIP)
+			if err != nil {
+				return nil, fmt.Errorf("invalid allowed IP range %s for client %s", allowedIP, client)
+			}
+			networks[i] = network
+		}
+		parameters.networks[client] = networks
+	}
+
+	return &parameters, nil
+}
+
+// compileAccess compiles permissions in to the paths checked for each client.
+func compileAccess(permissions map[string][]*checker.Permissions) (map[string][]*path, error) {
+	access := make(map[string][]*path, len(permissions))
+	for client, clientPermissions := range permissions {
 		if client == "" {
 			return nil, errors.New("invalid client name for permission")
 		}
 
-		if len(permissions) == 0 {
+		if len(clientPermissions) == 0 {
 			return nil, fmt.Errorf("client %s requires at least one permission", client)
 		}
 
-		paths := make([]*path, len(permissions))
-		for i, permission := range permissions {
-			walletName, accountName, err := e2wallet.WalletAndAccountNames(permission.Path)
-			if err != nil {
-				return nil, fmt.Errorf("invalid account path %s", permission.Path)
-			}
-			if walletName == "" {
-				return nil, errors.New("wallet cannot be blank")
-			}
-			walletRegex, err := regexify(walletName)
-			if err != nil {
-				return nil, fmt.Errorf("invalid wallet regex %s", walletName)
-			}
-			accountRegex, err := regexify(accountName)
+		paths := make([]*path, len(clientPermissions))
+		for i, permission := range clientPermissions {
+			var err error
+			paths[i], err = compilePath(permission)
 			if err != nil {
-				return nil, fmt.Errorf("invalid account regex %s", accountName)
-			}
-			log.Trace().Str("wallet", walletRegex.String()).Str("account", accountRegex.String()).Strs("operations", permission.Operations).Msg("Adding permission")
-			paths[i] = &path{
-				wallet:     walletRegex,
-				account:    accountRegex,
-				operations: permission.Operations,
+				return nil, err
 			}
 		}
-		parameters.access[client] = paths
+		sortPaths(paths)
+		access[client] = paths
 	}
+	return access, nil
+}
 
-	return &parameters, nil
+// parseNetwork parses an IP range in CIDR notation.  A bare IP address is
+// treated as a range containing only that address.
+func parseNetwork(input string) (*net.IPNet, error) {
+	if !strings.Contains(input, "/") {
+		ip := net.ParseIP(input)
+		if ip == nil {
+			return nil, errors.New("invalid IP address")
+		}
+		bits := 8 * net.IPv6len
+		if ip.To4() != nil {
+			ip = ip.To4()
+			bits = 8 * net.IPv4len
+		}
+		return &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}, nil
+	}
+	_, network, err := net.ParseCIDR(input)
+	return network, err
 }
 
 // regexify turns a name in to a regex.  It attaches anchors if required, and also makes the regex case-insensitive.
@@ -0,0 +1,258 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package static
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/attestantio/dirk/services/checker"
+	"github.com/pkg/errors"
+	e2wallet "github.com/wealdtech/go-eth2-wallet"
+)
+
+// path is a compiled permission path.
+type path struct {
+	pattern string
+	// glob is set for paths using wildcard syntax, and matches the full
+	// wallet/account name.
+	glob *regexp.Regexp
+	// wallet and account are set for paths using regular expression syntax.
+	wallet     *regexp.Regexp
+	account    *regexp.Regexp
+	rank       specificity
+	deny       bool
+	operations []string
+}
+
+// specificity ranks paths so that more specific paths are evaluated first.
+type specificity struct {
+	literalSegments int
+	literalChars    int
+	multiWildcards  int
+}
+
+// regexMetachars are the characters that mark a path as a regular expression
+// rather than a wildcard pattern.
+const regexMetachars = `\^$()[]{}|+?`
+
+// isGlob returns true if the path uses wildcard syntax: it contains a "*" that
+// does not follow "." and no other regular expression syntax.  Paths such as
+// "Wallet1/Acc.*" continue to be treated as regular expressions.
+func isGlob(pattern string) bool {
+	return strings.Contains(pattern, "*") &&
+		!strings.ContainsAny(pattern, regexMetachars) &&
+		!strings.Contains(pattern, ".*")
+}
+
+// compilePath compiles a permission.
+func compilePath(permission *checker.Permissions) (*path, error) {
+	pattern := permission.Path
+	operations, err := effectiveOperations(permission)
+	if err != nil {
+		return nil, err
+	}
+
+	walletName, accountName, err := e2wallet.WalletAndAccountNames(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid account path %s", pattern)
+	}
+	if walletName == "" {
+		return nil, errors.New("wallet cannot be blank")
+	}
+
+	if isGlob(pattern) {
+		glob, err := globify(pattern)
+		if err != nil {
+			return nil, err
+		}
+		log.Trace().Str("glob", glob.String()).Strs("operations", operations).Msg("Adding permission")
+		return &path{
+			pattern:    pattern,
+			glob:       glob,
+			rank:       rank(strings.Split(pattern, "/"), true),
+			deny:       permission.IsDeny(),
+			operations: operations,
+		}, nil
+	}
+
+	walletRegex, err := regexify(walletName)
+	if err != nil {
+		return nil, fmt.Errorf("invalid wallet regex %s", walletName)
+	}
+	accountRegex, err := regexify(accountName)
+	if err != nil {
+		return nil, fmt.Errorf("invalid account regex %s", accountName)
+	}
+	log.Trace().Str("wallet", walletRegex.String()).Str("account", accountRegex.String()).Strs("operations", operations).Msg("Adding permission")
+	segments := []string{walletName}
+	if accountName != "" {
+		segments = append(segments, strings.Split(accountName, "/")...)
+	}
+	return &path{
+		pattern:    pattern,
+		wallet:     walletRegex,
+		account:    accountRegex,
+		rank:       rank(segments, false),
+		deny:       permission.IsDeny(),
+		operations: operations,
+	}, nil
+}
+
+// effectiveOperations returns the operations of a permission in the form
+// used by the checker.  The operations of a deny permission are turned in to
+// explicit denials, with "All" denying every operation.
+func effectiveOperations(permission *checker.Permissions) ([]string, error) {
+	if permission.Effect != "" && !strings.EqualFold(permission.Effect, checker.EffectAllow) && !permission.IsDeny() {
+		return nil, fmt.Errorf("invalid effect %q for path %s", permission.Effect, permission.Path)
+	}
+	if !permission.IsDeny() {
+		return permission.Operations, nil
+	}
+
+	operations := make([]string, len(permission.Operations))
+	for i, operation := range permission.Operations {
+		switch {
+		case strings.EqualFold(operation, "all"):
+			operations[i] = "None"
+		case strings.EqualFold(operation, "none") || strings.HasPrefix(operation, "~"):
+			return nil, fmt.Errorf("invalid operation %q for deny path %s", operation, permission.Path)
+		default:
+			operations[i] = fmt.Sprintf("~%s", operation)
+		}
+	}
+	return operations, nil
+}
+
+// matches returns true if the path matches the given wallet and account.
+func (p *path) matches(walletName string, accountName string) bool {
+	if p.glob != nil {
+		return p.glob.MatchString(fmt.Sprintf("%s/%s", walletName, accountName))
+	}
+	return p.wallet.MatchString(walletName) && p.account.MatchString(accountName)
+}
+
+// globify turns a wildcard pattern in to a regex.  "*" matches any characters
+// within a single segment of the path, and "**" as a complete segment matches
+// any number of segments, including none.  A pattern with only a wallet
+// matches all accounts in the matching wallets, as with regular expressions.
+// Matching is case-insensitive.
+func globify(pattern string) (*regexp.Regexp, error) {
+	segments := make([]string, 0)
+	for _, segment := range strings.Split(pattern, "/") {
+		// Consecutive "**" segments are equivalent to one.
+		if segment == "**" && len(segments) > 0 && segments[len(segments)-1] == "**" {
+			continue
+		}
+		segments = append(segments, segment)
+	}
+	if len(segments) == 1 && segments[0] != "**" {
+		segments = append(segments, "**")
+	}
+
+	var res strings.Builder
+	res.WriteString("(?i)^")
+	separate := false
+	for i, segment := range segments {
+		if segment == "**" {
+			switch {
+			case len(segments) == 1:
+				res.WriteString(".*")
+			case i == len(segments)-1:
+				res.WriteString("(?:/.*)?")
+			case i == 0:
+				res.WriteString("(?:.*/)?")
+			default:
+				res.WriteString("/(?:.*/)?")
+			}
+			separate = false
+			continue
+		}
+		if strings.Contains(segment, "**") {
+			return nil, fmt.Errorf("invalid wildcard %s: ** must be a complete path segment", pattern)
+		}
+		if separate {
+			res.WriteString("/")
+		}
+		parts := strings.Split(segment, "*")
+		for j := range parts {
+			parts[j] = regexp.QuoteMeta(parts[j])
+		}
+		res.WriteString(strings.Join(parts, "[^/]*"))
+		separate = true
+	}
+	res.WriteString("$")
+
+	return regexp.Compile(res.String())
+}
+
+// rank calculates the specificity of a path from its segments.  Wildcards and
+// regular expression syntax are not counted as literal.
+func rank(segments []string, glob bool) specificity {
+	res := specificity{}
+	for _, segment := range segments {
+		if segment == "**" {
+			res.multiWildcards++
+			continue
+		}
+		literal := true
+		for _, c := range segment {
+			if c == '*' || c == '.' && !glob || strings.ContainsRune(regexMetachars, c) {
+				literal = false
+				continue
+			}
+			res.literalChars++
+		}
+		if literal && segment != "" {
+			res.literalSegments++
+		}
+	}
+	return res
+}
+
+// sortPaths sorts paths in to the order in which they are evaluated.  When a
+// wallet and account match more than one path, the most specific path takes
+// precedence:
+//
+//   - the path with the most segments without wildcards, for example
+//     "Wallet1/Account1" over "Wallet1/*";
+//   - then the path with the most literal characters, for example
+//     "Wallet1/batch-1*" over "Wallet1/batch-*";
+//   - then the path with the fewest "**" segments, for example
+//     "*/Account1" over "**/Account1";
+//   - then a path with a deny effect over one with an allow effect.
+//
+// Paths of equal specificity are ordered by their pattern so that
+// evaluation is deterministic.  Regular expression paths are ranked in the
+// same way, with their regular expression syntax counted as wildcards.
+func sortPaths(paths []*path) {
+	sort.SliceStable(paths, func(i, j int) bool {
+		a, b := paths[i].rank, paths[j].rank
+		if a.literalSegments != b.literalSegments {
+			return a.literalSegments > b.literalSegments
+		}
+		if a.literalChars != b.literalChars {
+			return a.literalChars > b.literalChars
+		}
+		if a.multiWildcards != b.multiWildcards {
+			return a.multiWildcards < b.multiWildcards
+		}
+		if paths[i].deny != paths[j].deny {
+			return paths[i].deny
+		}
+		return strings.ToLower(paths[i].pattern) < strings.ToLower(paths[j].pattern)
+	})
+}
@@ -16,11 +16,14 @@ package static
 import (
 	"context"
 	"fmt"
-	"regexp"
+	"net"
+	"sort"
 	"strings"
+	"sync"
 
 	"github.com/attestantio/dirk/services/checker"
 	"github.com/attestantio/dirk/services/metrics"
+	"github.com/attestantio/dirk/services/ruler"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
 	zerologger "github.com/rs/zerolog/log"
@@ -29,14 +32,22 @@ import (
 
 // Service checks access against a static list.
 type Service struct {
-	monitor metrics.CheckerMonitor
-	access  map[string][]*path
+	monitor  metrics.CheckerMonitor
+	accessMu sync.RWMutex
+	access   map[string][]*path
+	networks map[string][]*net.IPNet
 }
 
-type path struct {
-	wallet     *regexp.Regexp
-	account    *regexp.Regexp
-	operations []string
+// adminOperations are operations that are not covered by the "All"
+// qualifier, and so must be granted explicitly.
+var adminOperations = map[string]bool{
+	"delete account":               true,
+	"reshare account":              true,
+	"check sign":                   true,
+	"generate deposit data":        true,
+	"sign tagged":                  true,
+	"sign root":                    true,
+	"sign bls to execution change": true,
 }
 
 // module-wide log.
@@ -56,8 +67,9 @@ func New(ctx context.Context, params ...Parameter) (*Service, error) {
 	}
 
 	s := &Service{
-		monitor: parameters.monitor,
-		access:  parameters.access,
+		monitor:  parameters.monitor,
+		access:   parameters.access,
+		networks: parameters.networks,
 	}
 
 	return s, nil
@@ -65,6 +77,112 @@ func New(ctx context.Context, params ...Parameter) (*Service, error) {
 
 // Check checks the client to see if the account is allowed.
 func (s *Service) Check(ctx context.Context, credentials *checker.Credentials, account string, operation string) bool {
+	if !s.addressAllowed(credentials) {
+		s.monitor.AddressDenied(credentials.Client)
+		s.permissionChecked(credentials, operation, false)
+		return false
+	}
+	allowed := s.check(ctx, credentials, account, operation)
+	// Access checks are used to filter the accounts returned by listing, so denials are expected.
+	if !allowed && operation != ruler.ActionAccessAccount {
+		s.monitor.PermissionDenied(s.clientLabel(credentials, "unknown"), operation)
+	}
+	s.permissionChecked(credentials, operation, allowed)
+	return allowed
+}
+
+// permissionChecked reports the result of a check.  Access checks are made
+// for every account when a client lists accounts, so are not reported.
+func (s *Service) permissionChecked(credentials *checker.Credentials, operation string, allowed bool) {
+	if operation == ruler.ActionAccessAccount {
+		return
+	}
+	s.monitor.PermissionChecked(s.clientLabel(credentials, "other"), operation, allowed)
+}
+
+// addressAllowed returns false if the client has allowed IP ranges and the
+// request did not come from within them.  This applies in addition to the
+// client's permissions, so a client certificate cannot be used from
+// elsewhere even for operations that the client is permitted to carry out.
+func (s *Service) addressAllowed(credentials *checker.Credentials) bool {
+	if credentials == nil {
+		// Handled, and reported, by the permission check.
+		return true
+	}
+	networks, exists := s.networks[credentials.Client]
+	if !exists {
+		return true
+	}
+	ip := net.ParseIP(credentials.IP)
+	if ip == nil {
+		log.Warn().Str("client", credentials.Client).Str("ip", credentials.IP).Str("result", "denied").Msg("No valid address for client with allowed IP ranges")
+		return false
+	}
+	for _, network := range networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	log.Warn().Str("client", credentials.Client).Str("ip", credentials.IP).Str("result", "denied").Msg("Request from address outside of allowed IP ranges")
+	return false
+}
+
+// clientLabel returns the client to report to the monitor.  Clients without
+// rules are reported as the supplied label, so that the number of clients
+// reported is bounded by the permissions.
+func (s *Service) clientLabel(credentials *checker.Credentials, unknownLabel string) string {
+	if credentials == nil {
+		return unknownLabel
+	}
+	if _, exists := s.clientPaths(credentials.Client); !exists {
+		return unknownLabel
+	}
+	return credentials.Client
+}
+
+// clientPaths returns the paths for the client from the current permissions.
+// Paths are never modified once compiled, so can be used after the lock is released.
+func (s *Service) clientPaths(client string) ([]*path, bool) {
+	s.accessMu.RLock()
+	defer s.accessMu.RUnlock()
+	paths, exists := s.access[client]
+	return paths, exists
+}
+
+// ReloadPermissions replaces the permissions used by the checker.  If the
+// permissions are invalid the existing permissions remain in place.
+func (s *Service) ReloadPermissions(ctx context.Context, permissions map[string][]*checker.Permissions) error {
+	access, err := compileAccess(permissions)
+	if err != nil {
+		return errors.Wrap(err, "invalid permissions")
+	}
+
+	s.accessMu.Lock()
+	previous := s.access
+	s.access = access
+	s.accessMu.Unlock()
+
+	added := make([]string, 0)
+	for client := range access {
+		if _, exists := previous[client]; !exists {
+			added = append(added, client)
+		}
+	}
+	removed := make([]string, 0)
+	for client := range previous {
+		if _, exists := access[client]; !exists {
+			removed = append(removed, client)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	log.Info().Int("clients", len(access)).Strs("added", added).Strs("removed", removed).Msg("Reloaded permissions")
+
+	return nil
+}
+
+// check carries out the check for Check.
+func (s *Service) check(ctx context.Context, credentials *checker.Credentials, account string, operation string) bool {
 	log.Trace().Str("account", account).Str("operation", operation).Msg("Checking permissions for operation")
 
 	if credentials == nil {
@@ -87,21 +205,24 @@ func (s *Service) Check(ctx context.Context, credentials *checker.Credentials, a
 		return false
 	}
 
-	paths, exists := s.access[credentials.Client]
+	paths, exists := s.clientPaths(credentials.Client)
 	if !exists {
 		log.Warn().Str("result", "denied").Msg("No rules for client")
 		return false
 	}
 
+	// Paths are held most specific first, so the most specific matching path
+	// that mentions the operation decides; see sortPaths for the precedence.
 	antiOperation := fmt.Sprintf("~%s", operation)
+	adminOperation := adminOperations[strings.ToLower(operation)]
 	for _, path := range paths {
-		if path.wallet.Match([]byte(walletName)) && path.account.Match([]byte(accountName)) {
+		if path.matches(walletName, accountName) {
 			for i := range path.operations {
 				if strings.EqualFold(path.operations[i], "none") || strings.EqualFold(path.operations[i], antiOperation) {
 					log.Trace().Str("result", "denied").Msg("Negative permission matched")
 					return false
 				}
-				if strings.EqualFold(path.operations[i], "all") || strings.EqualFold(path.operations[i], operation) {
+				if (strings.EqualFold(path.operations[i], "all") && !adminOperation) || strings.EqualFold(path.operations[i], operation) {
 					log.Trace().Str("result", "succeeded").Msg("Positive permission matched")
 					return true
 				}
@@ -66,16 +66,43 @@ func TestNew(t *testing.T) {
 		{
 			name: "CertInfoInvalidWallet",
 			permissions: map[string][]*checker.Permissions{
-				"client-01": {{Path: "**/foo"}},
+				"client-01": {{Path: "[/foo"}},
 			},
-			err: "problem with parameters: invalid wallet regex **",
+			err: "problem with parameters: invalid wallet regex [",
 		},
 		{
 			name: "CertInfoInvalidAccount",
 			permissions: map[string][]*checker.Permissions{
-				"client-01": {{Path: "foo/**"}},
+				"client-01": {{Path: "foo/("}},
+			},
+			err: "problem with parameters: invalid account regex (",
+		},
+		{
+			name: "CertInfoInvalidWildcard",
+			permissions: map[string][]*checker.Permissions{
+				"client-01": {{Path: "foo/batch-**"}},
+			},
+			err: "problem with parameters: invalid wildcard foo/batch-**: ** must be a complete path segment",
+		},
+		{
+			name: "EffectInvalid",
+			permissions: map[string][]*checker.Permissions{
+				"client-01": {{Path: "foo", Operations: []string{"Sign"}, Effect: "maybe"}},
+			},
+			err: `problem with parameters: invalid effect "maybe" for path foo`,
+		},
+		{
+			name: "DenyNegated",
+			permissions: map[string][]*checker.Permissions{
+				"client-01": {{Path: "foo", Operations: []string{"~Sign"}, Effect: checker.EffectDeny}},
+			},
+			err: `problem with parameters: invalid operation "~Sign" for deny path foo`,
+		},
+		{
+			name: "CertInfoWildcard",
+			permissions: map[string][]*checker.Permissions{
+				"client-01": {{Path: "**/foo"}, {Path: "foo/**"}, {Path: "foo/batch-*"}},
 			},
-			err: "problem with parameters: invalid account regex **",
 		},
 		{
 			name:    "Good",
@@ -300,3 +327,540 @@ func TestLists(t *testing.T) {
 		})
 	}
 }
+
+func TestAdminOperations(t *testing.T) {
+	service, err := static.New(context.Background(),
+		static.WithLogLevel(zerolog.Disabled),
+		static.WithPermissions(map[string][]*checker.Permissions{
+			// client1 allows everything for Wallet1.
+			"client1": {
+				{
+					Path:       "Wallet1",
+					Operations: []string{"All"},
+				},
+			},
+			// client2 explicitly allows deletion for Wallet1.
+			"client2": {
+				{
+					Path:       "Wallet1",
+					Operations: []string{"Delete account"},
+				},
+			},
+		}),
+	)
+	require.Nil(t, err)
+
+	tests := []struct {
+		name      string
+		client    string
+		operation string
+		result    bool
+	}{
+		{
+			name:      "AllSign",
+			client:    "client1",
+			operation: ruler.ActionSign,
+			result:    true,
+		},
+		{
+			name:      "AllDelete",
+			client:    "client1",
+			operation: ruler.ActionDeleteAccount,
+			result:    false,
+		},
+		{
+			name:      "ExplicitDelete",
+			client:    "client2",
+			operation: ruler.ActionDeleteAccount,
+			result:    true,
+		},
+		{
+			name:      "ExplicitDeleteSign",
+			client:    "client2",
+			operation: ruler.ActionSign,
+			result:    false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			credentials := &checker.Credentials{
+				Client: test.client,
+			}
+			result := service.Check(context.Background(), credentials, "Wallet1/Account1", test.operation)
+			assert.Equal(t, test.result, result)
+		})
+	}
+}
+
+// denialMonitor records permission denials.
+type denialMonitor struct {
+	denials map[string]int
+	checks  map[string]int
+}
+
+func (m *denialMonitor) PermissionChecked(client string, operation string, allowed bool) {
+	if m.checks != nil {
+		m.checks[fmt.Sprintf("%s/%s/%t", client, operation, allowed)]++
+	}
+}
+
+func (m *denialMonitor) PermissionDenied(client string, operation string) {
+	m.denials[fmt.Sprintf("%s/%s", client, operation)]++
+}
+
+func (m *denialMonitor) AddressDenied(client string) {
+	m.denials[fmt.Sprintf("%s/address", client)]++
+}
+
+func TestPermissionDenied(t *testing.T) {
+	monitor := &denialMonitor{denials: make(map[string]int)}
+	service, err := static.New(context.Background(),
+		static.WithLogLevel(zerolog.Disabled),
+		static.WithMonitor(monitor),
+		static.WithPermissions(map[string][]*checker.Permissions{
+			"client1": {
+				{
+					Path:       "Wallet1",
+					Operations: []string{"Sign"},
+				},
+			},
+		}),
+	)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	client1 := &checker.Credentials{Client: "client1"}
+	require.True(t, service.Check(ctx, client1, "Wallet1/Account1", ruler.ActionSign))
+	require.False(t, service.Check(ctx, client1, "Wallet2/Account1", ruler.ActionSign))
+	require.False(t, service.Check(ctx, client1, "Wallet1/Account1", ruler.ActionDeleteAccount))
+	require.False(t, service.Check(ctx, &checker.Credentials{Client: "client2"}, "Wallet1/Account1", ruler.ActionSign))
+	require.False(t, service.Check(ctx, nil, "Wallet1/Account1", ruler.ActionSign))
+	// Access denials are not reported.
+	require.False(t, service.Check(ctx, client1, "Wallet2/Account1", ruler.ActionAccessAccount))
+
+	require.Equal(t, map[string]int{
+		"client1/Sign":           1,
+		"client1/Delete account": 1,
+		"unknown/Sign":           2,
+	}, monitor.denials)
+}
+
+func TestPermissionChecked(t *testing.T) {
+	monitor := &denialMonitor{
+		denials: make(map[string]int),
+		checks:  make(map[string]int),
+	}
+	service, err := static.New(context.Background(),
+		static.WithLogLevel(zerolog.Disabled),
+		static.WithMonitor(monitor),
+		static.WithPermissions(map[string][]*checker.Permissions{
+			"client1": {
+				{
+					Path:       "Wallet1",
+					Operations: []string{"Sign"},
+				},
+			},
+		}),
+		static.WithAllowedIPs(map[string][]string{
+			"client1": {"10.0.0.0/8"},
+		}),
+	)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	client1 := &checker.Credentials{Client: "client1", IP: "10.1.2.3"}
+	require.True(t, service.Check(ctx, client1, "Wallet1/Account1", ruler.ActionSign))
+	require.True(t, service.Check(ctx, client1, "Wallet1/Account2", ruler.ActionSign))
+	require.False(t, service.Check(ctx, client1, "Wallet2/Account1", ruler.ActionSign))
+	require.False(t, service.Check(ctx, client1, "Wallet1/Account1", ruler.ActionDeleteAccount))
+	require.False(t, service.Check(ctx, &checker.Credentials{Client: "client1", IP: "192.168.1.1"}, "Wallet1/Account1", ruler.ActionSign))
+	// Unknown clients share a single label.
+	require.False(t, service.Check(ctx, &checker.Credentials{Client: "client2"}, "Wallet1/Account1", ruler.ActionSign))
+	require.False(t, service.Check(ctx, &checker.Credentials{Client: "client3"}, "Wallet1/Account1", ruler.ActionSign))
+	require.False(t, service.Check(ctx, nil, "Wallet1/Account1", ruler.ActionSign))
+	// Access checks are not reported.
+	require.False(t, service.Check(ctx, client1, "Wallet1/Account1", ruler.ActionAccessAccount))
+
+	require.Equal(t, map[string]int{
+		"client1/Sign/true":            2,
+		"client1/Sign/false":           2,
+		"client1/Delete account/false": 1,
+		"other/Sign/false":             3,
+	}, monitor.checks)
+}
+
+func TestWildcards(t *testing.T) {
+	service, err := static.New(context.Background(),
+		static.WithLogLevel(zerolog.Disabled),
+		static.WithPermissions(map[string][]*checker.Permissions{
+			// client1 signs for batch accounts in Wallet1.
+			"client1": {
+				{
+					Path:       "Wallet1/batch-*",
+					Operations: []string{"Sign"},
+				},
+			},
+			// client2 signs for all accounts in Wallet1, at any depth.
+			"client2": {
+				{
+					Path:       "Wallet1/**",
+					Operations: []string{"Sign"},
+				},
+			},
+			// client3 signs for Account1 in any wallet, at any depth.
+			"client3": {
+				{
+					Path:       "**/Account1",
+					Operations: []string{"Sign"},
+				},
+			},
+			// client4 signs for accounts a single level below any group in Wallet1.
+			"client4": {
+				{
+					Path:       "Wallet1/*/*",
+					Operations: []string{"Sign"},
+				},
+			},
+			// client5 has overlapping patterns; the most specific matching pattern wins.
+			"client5": {
+				{
+					Path:       "Wallet1/**",
+					Operations: []string{"Sign"},
+				},
+				{
+					Path:       "Wallet1/batch-*",
+					Operations: []string{"~Sign"},
+				},
+				{
+					Path:       "Wallet1/batch-1*",
+					Operations: []string{"Sign"},
+				},
+				{
+					Path:       "Wallet1/batch-10",
+					Operations: []string{"None"},
+				},
+			},
+			// client6 mixes wildcards and regular expressions.
+			"client6": {
+				{
+					Path:       "Wallet.*",
+					Operations: []string{"~Sign"},
+				},
+				{
+					Path:       "Wallet1/Account*",
+					Operations: []string{"Sign"},
+				},
+			},
+		}),
+	)
+	require.Nil(t, err)
+
+	tests := []struct {
+		name    string
+		account string
+		results []bool
+	}{
+		{
+			name:    "Batch",
+			account: "Wallet1/batch-5",
+			results: []bool{true, true, false, false, false, false},
+		},
+		{
+			name:    "BatchUpperCase",
+			account: "WALLET1/BATCH-5",
+			results: []bool{true, true, false, false, false, false},
+		},
+		{
+			name:    "BatchMoreSpecific",
+			account: "Wallet1/batch-12",
+			results: []bool{true, true, false, false, true, false},
+		},
+		{
+			name:    "BatchMostSpecific",
+			account: "Wallet1/batch-10",
+			results: []bool{true, true, false, false, false, false},
+		},
+		{
+			name:    "BatchNested",
+			account: "Wallet1/batch-5/sub",
+			results: []bool{false, true, false, true, true, false},
+		},
+		{
+			name:    "Account1",
+			account: "Wallet1/Account1",
+			results: []bool{false, true, true, false, true, true},
+		},
+		{
+			name:    "Account1OtherWallet",
+			account: "Wallet2/Account1",
+			results: []bool{false, false, true, false, false, false},
+		},
+		{
+			name:    "Account1Nested",
+			account: "Wallet2/group/Account1",
+			results: []bool{false, false, true, false, false, false},
+		},
+		{
+			name:    "OtherWallet",
+			account: "Wallet2/batch-5",
+			results: []bool{false, false, false, false, false, false},
+		},
+		{
+			name:    "LiteralNotRegex",
+			account: "Wallet1/batch-",
+			results: []bool{true, true, false, false, false, false},
+		},
+	}
+
+	clients := []string{"client1", "client2", "client3", "client4", "client5", "client6"}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for i := range clients {
+				t.Run(clients[i], func(t *testing.T) {
+					credentials := &checker.Credentials{
+						Client: clients[i],
+					}
+					result := service.Check(context.Background(), credentials, test.account, ruler.ActionSign)
+					assert.Equal(t, test.results[i], result)
+				})
+			}
+		})
+	}
+}
+
+func TestDeny(t *testing.T) {
+	service, err := static.New(context.Background(),
+		static.WithLogLevel(zerolog.Disabled),
+		static.WithPermissions(map[string][]*checker.Permissions{
+			// client1 signs for Wallet1 other than its cold storage accounts.
+			"client1": {
+				{
+					Path:       "Wallet1/**",
+					Operations: []string{"Sign", "Sign beacon attestation"},
+				},
+				{
+					Path:       "Wallet1/cold-storage-*",
+					Operations: []string{"Sign"},
+					Effect:     checker.EffectDeny,
+				},
+			},
+			// client2 has an allow and a deny of equal specificity; the deny wins.
+			"client2": {
+				{
+					Path:       "Wallet1/*",
+					Operations: []string{"Sign"},
+					Effect:     checker.EffectAllow,
+				},
+				{
+					Path:       "wallet1/*",
+					Operations: []string{"Sign"},
+					Effect:     "Deny",
+				},
+			},
+			// client3 denies everything, including administrative operations, for cold storage.
+			"client3": {
+				{
+					Path:       "Wallet1",
+					Operations: []string{"All", "Delete account"},
+				},
+				{
+					Path:       "Wallet1/cold-storage-*",
+					Operations: []string{"All"},
+					Effect:     checker.EffectDeny,
+				},
+			},
+		}),
+	)
+	require.Nil(t, err)
+
+	tests := []struct {
+		name      string
+		account   string
+		operation string
+		results   []bool
+	}{
+		{
+			name:      "SignHot",
+			account:   "Wallet1/hot-1",
+			operation: ruler.ActionSign,
+			results:   []bool{true, false, true},
+		},
+		{
+			name:      "SignCold",
+			account:   "Wallet1/cold-storage-1",
+			operation: ruler.ActionSign,
+			results:   []bool{false, false, false},
+		},
+		{
+			name:      "AttestCold",
+			account:   "Wallet1/cold-storage-1",
+			operation: ruler.ActionSignBeaconAttestation,
+			results:   []bool{true, false, false},
+		},
+		{
+			name:      "DeleteHot",
+			account:   "Wallet1/hot-1",
+			operation: "Delete account",
+			results:   []bool{false, false, true},
+		},
+		{
+			name:      "DeleteCold",
+			account:   "Wallet1/cold-storage-1",
+			operation: "Delete account",
+			results:   []bool{false, false, false},
+		},
+	}
+
+	clients := []string{"client1", "client2", "client3"}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for i := range clients {
+				t.Run(clients[i], func(t *testing.T) {
+					credentials := &checker.Credentials{
+						Client: clients[i],
+					}
+					result := service.Check(context.Background(), credentials, test.account, test.operation)
+					assert.Equal(t, test.results[i], result)
+				})
+			}
+		})
+	}
+}
+
+func TestAllowedIPs(t *testing.T) {
+	permissions := map[string][]*checker.Permissions{
+		"client1": {
+			{
+				Path:       "Wallet1",
+				Operations: []string{"Sign"},
+			},
+		},
+		"client2": {
+			{
+				Path:       "Wallet1",
+				Operations: []string{"Sign"},
+			},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		allowedIPs map[string][]string
+		err        string
+	}{
+		{
+			name:       "UnknownClient",
+			allowedIPs: map[string][]string{"client3": {"10.0.0.0/8"}},
+			err:        "problem with parameters: allowed IPs supplied for client client3 which has no permissions",
+		},
+		{
+			name:       "Empty",
+			allowedIPs: map[string][]string{"client1": {}},
+			err:        "problem with parameters: client client1 requires at least one allowed IP range",
+		},
+		{
+			name:       "Invalid",
+			allowedIPs: map[string][]string{"client1": {"10.0.0.0/33"}},
+			err:        "problem with parameters: invalid allowed IP range 10.0.0.0/33 for client client1",
+		},
+		{
+			name:       "InvalidAddress",
+			allowedIPs: map[string][]string{"client1": {"bad"}},
+			err:        "problem with parameters: invalid allowed IP range bad for client client1",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := static.New(context.Background(),
+				static.WithLogLevel(zerolog.Disabled),
+				static.WithPermissions(permissions),
+				static.WithAllowedIPs(test.allowedIPs),
+			)
+			require.EqualError(t, err, test.err)
+		})
+	}
+
+	monitor := &denialMonitor{denials: make(map[string]int)}
+	service, err := static.New(context.Background(),
+		static.WithLogLevel(zerolog.Disabled),
+		static.WithMonitor(monitor),
+		static.WithPermissions(permissions),
+		static.WithAllowedIPs(map[string][]string{
+			"client1": {"10.0.0.0/8", "192.168.1.1", "fd00::/8"},
+		}),
+	)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	checks := []struct {
+		client  string
+		ip      string
+		account string
+		result  bool
+	}{
+		{client: "client1", ip: "10.1.2.3", account: "Wallet1/Account1", result: true},
+		{client: "client1", ip: "192.168.1.1", account: "Wallet1/Account1", result: true},
+		{client: "client1", ip: "fd00::1", account: "Wallet1/Account1", result: true},
+		{client: "client1", ip: "192.168.1.2", account: "Wallet1/Account1", result: false},
+		{client: "client1", ip: "", account: "Wallet1/Account1", result: false},
+		// Allowed address but no permission.
+		{client: "client1", ip: "10.1.2.3", account: "Wallet2/Account1", result: false},
+		// Clients without allowed IPs are not restricted.
+		{client: "client2", ip: "192.168.1.2", account: "Wallet1/Account1", result: true},
+	}
+	for _, check := range checks {
+		credentials := &checker.Credentials{Client: check.client, IP: check.ip}
+		assert.Equal(t, check.result, service.Check(ctx, credentials, check.account, ruler.ActionSign), "%s from %s", check.client, check.ip)
+	}
+	// Listing is denied from a disallowed address, and reported as an address denial.
+	require.False(t, service.Check(ctx, &checker.Credentials{Client: "client1", IP: "192.168.1.2"}, "Wallet1/Account1", ruler.ActionAccessAccount))
+
+	require.Equal(t, map[string]int{
+		"client1/address": 3,
+		"client1/Sign":    1,
+	}, monitor.denials)
+}
+
+func TestReloadPermissions(t *testing.T) {
+	ctx := context.Background()
+	service, err := static.New(ctx,
+		static.WithLogLevel(zerolog.Disabled),
+		static.WithPermissions(map[string][]*checker.Permissions{
+			"client1": {
+				{
+					Path:       "Wallet1",
+					Operations: []string{"Sign"},
+				},
+			},
+		}),
+	)
+	require.NoError(t, err)
+
+	client1 := &checker.Credentials{Client: "client1"}
+	client2 := &checker.Credentials{Client: "client2"}
+	require.True(t, service.Check(ctx, client1, "Wallet1/Account1", ruler.ActionSign))
+	require.False(t, service.Check(ctx, client2, "Wallet1/Account1", ruler.ActionSign))
+
+	// Invalid permissions leave the existing permissions in place.
+	err = service.ReloadPermissions(ctx, map[string][]*checker.Permissions{
+		"client2": nil,
+	})
+	require.EqualError(t, err, "invalid permissions: client client2 requires at least one permission")
+	require.True(t, service.Check(ctx, client1, "Wallet1/Account1", ruler.ActionSign))
+	require.False(t, service.Check(ctx, client2, "Wallet1/Account1", ruler.ActionSign))
+
+	// Valid permissions replace the existing permissions.
+	require.NoError(t, service.ReloadPermissions(ctx, map[string][]*checker.Permissions{
+		"client2": {
+			{
+				Path:       "Wallet1",
+				Operations: []string{"Sign"},
+			},
+		},
+	}))
+	require.False(t, service.Check(ctx, client1, "Wallet1/Account1", ruler.ActionSign))
+	require.True(t, service.Check(ctx, client2, "Wallet1/Account1", ruler.ActionSign))
+}
@@ -0,0 +1,86 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mem
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	e2wtypes "github.com/wealdtech/go-eth2-wallet-types/v2"
+	indexer "github.com/wealdtech/go-indexer"
+)
+
+// accountDeleter is the interface for stores that can delete accounts.
+type accountDeleter interface {
+	// DeleteAccount deletes account data.
+	DeleteAccount(walletID uuid.UUID, accountID uuid.UUID) error
+}
+
+// deleteAccount deletes an account's data from its store, and removes it from the wallet's index.
+func deleteAccount(store e2wtypes.Store, walletID uuid.UUID, accountID uuid.UUID, accountName string) error {
+	switch {
+	case isAccountDeleter(store):
+		if err := store.(accountDeleter).DeleteAccount(walletID, accountID); err != nil {
+			return err
+		}
+	case store.Name() == "filesystem" && isLocationProvider(store):
+		// The filesystem store holds each account in a file named after its ID, inside the directory of its wallet.
+		location := store.(e2wtypes.StoreLocationProvider).Location()
+		path := filepath.Join(location, walletID.String(), accountID.String())
+		if err := os.Remove(path); err != nil {
+			return errors.Wrap(err, "failed to remove account file")
+		}
+	default:
+		return fmt.Errorf("store %s does not support account deletion", store.Name())
+	}
+
+	// The key material has now gone, so failure to update the index is not fatal.
+	if err := removeFromIndex(store, walletID, accountID, accountName); err != nil {
+		log.Warn().Err(err).Str("account", accountName).Msg("Failed to remove deleted account from wallet index")
+	}
+
+	return nil
+}
+
+// removeFromIndex removes an account from its wallet's index.
+func removeFromIndex(store e2wtypes.Store, walletID uuid.UUID, accountID uuid.UUID, accountName string) error {
+	data, err := store.RetrieveAccountsIndex(walletID)
+	if err != nil {
+		// No index, so nothing to remove.
+		return nil
+	}
+	index, err := indexer.Deserialize(data)
+	if err != nil {
+		return errors.Wrap(err, "failed to decode index")
+	}
+	index.Remove(accountID, accountName)
+	data, err = index.Serialize()
+	if err != nil {
+		return errors.Wrap(err, "failed to encode index")
+	}
+	return store.StoreAccountsIndex(walletID, data)
+}
+
+func isAccountDeleter(store e2wtypes.Store) bool {
+	_, isDeleter := store.(accountDeleter)
+	return isDeleter
+}
+
+func isLocationProvider(store e2wtypes.Store) bool {
+	_, isProvider := store.(e2wtypes.StoreLocationProvider)
+	return isProvider
+}
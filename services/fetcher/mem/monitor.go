@@ -16,3 +16,9 @@ package mem
 // noopMonitor is a monitor that does nothing, used in place of nil if an
 // external monitor is not supplied.
 type noopMonitor struct{}
+
+// FetcherRefreshCompleted is called when the fetcher has completed a scan of its stores.
+func (m *noopMonitor) FetcherRefreshCompleted(added int, removed int, failures int) {}
+
+// FetcherAccounts is called with the number of accounts loaded from each wallet in each store.
+func (m *noopMonitor) FetcherAccounts(accounts map[string]map[string]int) {}
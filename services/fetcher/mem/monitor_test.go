@@ -0,0 +1,128 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mem_test
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/attestantio/dirk/services/fetcher/mem"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+	e2wallet "github.com/wealdtech/go-eth2-wallet"
+	filesystem "github.com/wealdtech/go-eth2-wallet-store-filesystem"
+	e2wtypes "github.com/wealdtech/go-eth2-wallet-types/v2"
+)
+
+type refreshMonitor struct {
+	refreshes int
+	added     int
+	removed   int
+	failures  int
+}
+
+func (m *refreshMonitor) FetcherRefreshCompleted(added int, removed int, failures int) {
+	m.refreshes++
+	m.added += added
+	m.removed += removed
+	m.failures += failures
+}
+
+func (m *refreshMonitor) FetcherAccounts(accounts map[string]map[string]int) {}
+
+type accountsMonitor struct {
+	refreshMonitor
+	accounts map[string]map[string]int
+}
+
+func (m *accountsMonitor) FetcherAccounts(accounts map[string]map[string]int) {
+	m.accounts = accounts
+}
+
+func TestRefreshMetrics(t *testing.T) {
+	ctx := context.Background()
+
+	stores, err := createTestStores()
+	require.NoError(t, err)
+	monitor := &refreshMonitor{}
+	_, err = mem.New(ctx,
+		mem.WithLogLevel(zerolog.Disabled),
+		mem.WithMonitor(monitor),
+		mem.WithStores(stores))
+	require.NoError(t, err)
+	require.Equal(t, &refreshMonitor{refreshes: 1, added: 2}, monitor)
+
+	// Add an undecodable wallet; the remaining wallets should still be loaded.
+	require.NoError(t, stores[0].StoreWallet(uuid.New(), "Bad wallet", []byte(`{"type":"unknown"}`)))
+	monitor = &refreshMonitor{}
+	fetcher, err := mem.New(ctx,
+		mem.WithLogLevel(zerolog.Disabled),
+		mem.WithMonitor(monitor),
+		mem.WithStores(stores))
+	require.NoError(t, err)
+	require.Equal(t, &refreshMonitor{refreshes: 1, added: 2, failures: 1}, monitor)
+	_, _, err = fetcher.FetchAccount(ctx, "Test wallet/Test account")
+	require.NoError(t, err)
+}
+
+func TestAccountsMetrics(t *testing.T) {
+	ctx := context.Background()
+
+	stores, err := createTestStores()
+	require.NoError(t, err)
+	base, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(base)
+	emptyStore := filesystem.New(filesystem.WithLocation(base))
+	emptyStoreName := fmt.Sprintf("filesystem:%s", base)
+
+	monitor := &accountsMonitor{}
+	fetcher, err := mem.New(ctx,
+		mem.WithLogLevel(zerolog.Disabled),
+		mem.WithMonitor(monitor),
+		mem.WithStores(append(stores, emptyStore)))
+	require.NoError(t, err)
+	require.Equal(t, map[string]map[string]int{
+		"scratch": {
+			"Test wallet":    1,
+			"Test HD wallet": 1,
+		},
+		emptyStoreName: {},
+	}, monitor.accounts)
+
+	// Add a wallet with accounts to the empty store; the refresh should report them.
+	walletID := uuid.New()
+	require.NoError(t, emptyStore.StoreWallet(walletID, "New wallet", []byte(fmt.Sprintf(`{"uuid":"%s","version":1,"name":"New wallet","type":"non-deterministic"}`, walletID.String()))))
+	wallet, err := e2wallet.OpenWallet("New wallet", e2wallet.WithStore(emptyStore))
+	require.NoError(t, err)
+	require.NoError(t, wallet.(e2wtypes.WalletLocker).Unlock(ctx, nil))
+	for _, name := range []string{"Account 1", "Account 2"} {
+		_, err = wallet.(e2wtypes.WalletAccountCreator).CreateAccount(ctx, name, []byte("pass"))
+		require.NoError(t, err)
+	}
+	require.NoError(t, fetcher.Refresh(ctx))
+	require.Equal(t, map[string]map[string]int{
+		"scratch": {
+			"Test wallet":    1,
+			"Test HD wallet": 1,
+		},
+		emptyStoreName: {
+			"New wallet": 2,
+		},
+	}, monitor.accounts)
+}
@@ -14,6 +14,8 @@
 package mem
 
 import (
+	"time"
+
 	"github.com/attestantio/dirk/services/metrics"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
@@ -26,6 +28,8 @@ type parameters struct {
 	monitor   metrics.FetcherMonitor
 	encryptor e2wtypes.Encryptor
 	stores    []e2wtypes.Store
+	// refreshInterval is the interval between refreshes of the stores.
+	refreshInterval time.Duration
 }
 
 // Parameter is the interface for service parameters.
@@ -67,6 +71,15 @@ func WithStores(stores []e2wtypes.Store) Parameter {
 	})
 }
 
+// WithRefreshInterval sets the interval at which the stores are rescanned for
+// accounts that have been added or removed.  If not set, or set to 0, the
+// stores are only scanned at startup.
+func WithRefreshInterval(interval time.Duration) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.refreshInterval = interval
+	})
+}
+
 // parseAndCheckParameters parses and checks parameters to ensure that mandatory parameters are present and correct.
 func parseAndCheckParameters(params ...Parameter) (*parameters, error) {
 	parameters := parameters{
@@ -89,6 +102,9 @@ func parseAndCheckParameters(params ...Parameter) (*parameters, error) {
 	if len(parameters.stores) == 0 {
 		return nil, errors.New("no stores specified")
 	}
+	if parameters.refreshInterval < 0 {
+		return nil, errors.New("refresh interval cannot be negative")
+	}
 
 	return &parameters, nil
 }
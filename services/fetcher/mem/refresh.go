@@ -0,0 +1,178 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mem
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/wealdtech/go-bytesutil"
+	e2wtypes "github.com/wealdtech/go-eth2-wallet-types/v2"
+)
+
+// refreshPeriodically refreshes the stores at the given interval.
+func (s *Service) refreshPeriodically(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Refresh(ctx); err != nil {
+				log.Error().Err(err).Msg("Failed to refresh stores; retaining existing accounts")
+			}
+		}
+	}
+}
+
+// Refresh rescans the stores, making accounts that have been added to them
+// available and evicting accounts that have been removed from them.
+// Wallets and accounts that are already known are retained, along with
+// their lock state, so signing is not disturbed.
+func (s *Service) Refresh(ctx context.Context) error {
+	s.refreshMu.Lock()
+	defer s.refreshMu.Unlock()
+
+	version := s.Version()
+	wallets, walletAccounts, walletStores, _, failures, err := populateCaches(ctx, s.stores, s.encryptor)
+	if err != nil {
+		return errors.Wrap(err, "failed to scan stores")
+	}
+
+	s.rwMu.Lock()
+	current := s.readCache()
+	removed := s.removed.Load().(map[string]struct{})
+	// If the accounts have changed during the scan then the scan may not
+	// reflect the changes, so the read-write copy is retained.
+	unchanged := s.Version() == version
+
+	// Build the current view of the wallets and accounts.
+	currentWallets := make(map[string]e2wtypes.Wallet, len(current.wallets)+len(s.rwWallets))
+	currentAccounts := make(map[string]map[string]e2wtypes.Account, len(current.walletAccounts)+len(s.rwWalletAccounts))
+	for name, wallet := range current.wallets {
+		currentWallets[name] = wallet
+	}
+	for name, wallet := range s.rwWallets {
+		currentWallets[name] = wallet
+	}
+	for _, source := range []map[string]map[string]e2wtypes.Account{current.walletAccounts, s.rwWalletAccounts} {
+		for walletName, accounts := range source {
+			for accountName, account := range accounts {
+				if _, isRemoved := removed[fmt.Sprintf("%s/%s", walletName, accountName)]; isRemoved {
+					continue
+				}
+				if _, exists := currentAccounts[walletName]; !exists {
+					currentAccounts[walletName] = make(map[string]e2wtypes.Account)
+				}
+				currentAccounts[walletName][accountName] = account
+			}
+		}
+	}
+
+	if failures > 0 {
+		// A wallet that could not be decoded may be one that is already
+		// known, so known wallets that were not found are retained rather
+		// than evicted.
+		for name, wallet := range currentWallets {
+			if _, exists := wallets[name]; !exists {
+				wallets[name] = wallet
+				walletAccounts[name] = currentAccounts[name]
+			}
+		}
+	}
+
+	// Build the new view of the wallets and accounts.
+	newWallets := make(map[string]e2wtypes.Wallet, len(wallets))
+	newWalletAccounts := make(map[string]map[string]e2wtypes.Account, len(wallets))
+	newPubKeyPaths := make(map[[48]byte]string)
+	added := 0
+	for walletName, wallet := range wallets {
+		if existing, exists := currentWallets[walletName]; exists {
+			// Retain the existing wallet, which may have been unlocked.
+			wallet = existing
+		}
+		newWallets[walletName] = wallet
+		accounts := make(map[string]e2wtypes.Account, len(walletAccounts[walletName]))
+		for accountName, account := range walletAccounts[walletName] {
+			path := fmt.Sprintf("%s/%s", walletName, accountName)
+			if _, isRemoved := removed[path]; isRemoved {
+				continue
+			}
+			existing, exists := currentAccounts[walletName][accountName]
+			if exists && bytes.Equal(existing.PublicKey().Marshal(), account.PublicKey().Marshal()) {
+				// Retain the existing account, which may have been unlocked.
+				account = existing
+			}
+			if !exists {
+				added++
+			}
+			accounts[accountName] = account
+			newPubKeyPaths[bytesutil.ToBytes48(account.PublicKey().Marshal())] = path
+		}
+		newWalletAccounts[walletName] = accounts
+	}
+	evicted := 0
+	for walletName, accounts := range currentAccounts {
+		for accountName := range accounts {
+			if _, exists := newWalletAccounts[walletName][accountName]; !exists {
+				evicted++
+			}
+		}
+	}
+
+	s.cache.Store(&cache{
+		pubKeyPaths:    newPubKeyPaths,
+		wallets:        newWallets,
+		walletAccounts: newWalletAccounts,
+	})
+	for name, store := range walletStores {
+		s.walletStores[name] = store
+	}
+	if unchanged {
+		// The new cache contains everything in the read-write copy.
+		s.rwPubKeyPaths = make(map[[48]byte]string)
+		s.rwWalletAccounts = make(map[string]map[string]e2wtypes.Account)
+		s.rwWallets = make(map[string]e2wtypes.Wallet)
+		s.removed.Store(make(map[string]struct{}))
+	}
+	for name := range s.walletStores {
+		if _, exists := newWallets[name]; exists {
+			continue
+		}
+		if _, exists := s.rwWallets[name]; exists {
+			continue
+		}
+		delete(s.walletStores, name)
+	}
+	if added > 0 || evicted > 0 {
+		atomic.AddUint64(&s.version, 1)
+	}
+	accounts := storeAccounts(s.stores, s.walletStores, newWalletAccounts)
+	s.rwMu.Unlock()
+
+	s.monitor.FetcherRefreshCompleted(added, evicted, failures)
+	s.monitor.FetcherAccounts(accounts)
+	if added > 0 || evicted > 0 {
+		log.Info().Int("added", added).Int("removed", evicted).Int("failures", failures).Msg("Refreshed stores")
+	} else {
+		log.Trace().Int("failures", failures).Msg("Refreshed stores; no changes")
+	}
+
+	return nil
+}
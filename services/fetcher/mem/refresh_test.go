@@ -0,0 +1,102 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mem_test
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/attestantio/dirk/services/fetcher/mem"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+	e2wallet "github.com/wealdtech/go-eth2-wallet"
+	filesystem "github.com/wealdtech/go-eth2-wallet-store-filesystem"
+	e2wtypes "github.com/wealdtech/go-eth2-wallet-types/v2"
+)
+
+func TestRefresh(t *testing.T) {
+	ctx := context.Background()
+
+	base, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(base)
+	store := filesystem.New(filesystem.WithLocation(base))
+	walletID := uuid.New()
+	require.NoError(t, store.StoreWallet(walletID, "Test wallet", []byte(fmt.Sprintf(`{"uuid":"%s","version":1,"name":"Test wallet","type":"non-deterministic"}`, walletID.String()))))
+	wallet, err := e2wallet.OpenWallet("Test wallet", e2wallet.WithStore(store))
+	require.NoError(t, err)
+	require.NoError(t, wallet.(e2wtypes.WalletLocker).Unlock(ctx, nil))
+	_, err = wallet.(e2wtypes.WalletAccountCreator).CreateAccount(ctx, "Account 1", []byte("pass"))
+	require.NoError(t, err)
+
+	monitor := &refreshMonitor{}
+	fetcher, err := mem.New(ctx,
+		mem.WithLogLevel(zerolog.Disabled),
+		mem.WithMonitor(monitor),
+		mem.WithStores([]e2wtypes.Store{store}))
+	require.NoError(t, err)
+
+	_, account1, err := fetcher.FetchAccount(ctx, "Test wallet/Account 1")
+	require.NoError(t, err)
+	require.NoError(t, account1.(e2wtypes.AccountLocker).Unlock(ctx, []byte("pass")))
+	_, _, err = fetcher.FetchAccount(ctx, "Test wallet/Account 2")
+	require.EqualError(t, err, "failed to find account")
+
+	// Add an account to the store outside of the fetcher.
+	account2, err := wallet.(e2wtypes.WalletAccountCreator).CreateAccount(ctx, "Account 2", []byte("pass"))
+	require.NoError(t, err)
+	version := fetcher.Version()
+	require.NoError(t, fetcher.Refresh(ctx))
+	require.Greater(t, fetcher.Version(), version)
+	require.Equal(t, &refreshMonitor{refreshes: 2, added: 2}, monitor)
+
+	_, account, err := fetcher.FetchAccount(ctx, "Test wallet/Account 2")
+	require.NoError(t, err)
+	require.Equal(t, account2.PublicKey().Marshal(), account.PublicKey().Marshal())
+	_, account, err = fetcher.FetchAccountByKey(ctx, account2.PublicKey().Marshal())
+	require.NoError(t, err)
+	require.Equal(t, "Account 2", account.Name())
+
+	// Existing accounts are retained, along with their lock state.
+	_, account, err = fetcher.FetchAccount(ctx, "Test wallet/Account 1")
+	require.NoError(t, err)
+	unlocked, err := account.(e2wtypes.AccountLocker).IsUnlocked(ctx)
+	require.NoError(t, err)
+	require.True(t, unlocked)
+
+	// Remove an account from the store outside of the fetcher.
+	require.NoError(t, os.Remove(filepath.Join(base, walletID.String(), account1.ID().String())))
+	version = fetcher.Version()
+	require.NoError(t, fetcher.Refresh(ctx))
+	require.Greater(t, fetcher.Version(), version)
+	require.Equal(t, &refreshMonitor{refreshes: 3, added: 2, removed: 1}, monitor)
+
+	_, _, err = fetcher.FetchAccount(ctx, "Test wallet/Account 1")
+	require.EqualError(t, err, "failed to find account")
+	_, _, err = fetcher.FetchAccountByKey(ctx, account1.PublicKey().Marshal())
+	require.EqualError(t, err, "public key not known")
+	accounts, err := fetcher.FetchAccounts(ctx, "Test wallet")
+	require.NoError(t, err)
+	require.Len(t, accounts, 1)
+
+	// A refresh without changes does not change the version.
+	version = fetcher.Version()
+	require.NoError(t, fetcher.Refresh(ctx))
+	require.Equal(t, version, fetcher.Version())
+}
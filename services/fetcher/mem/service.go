@@ -18,6 +18,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"sync"
+	"sync/atomic"
 
 	"github.com/attestantio/dirk/services/metrics"
 	"github.com/google/uuid"
@@ -34,16 +35,37 @@ import (
 
 // Service contains an in-memory cache of wallets and accounts.
 type Service struct {
-	monitor        metrics.FetcherMonitor
-	pubKeyPaths    map[[48]byte]string
-	wallets        map[string]e2wtypes.Wallet
-	walletAccounts map[string]map[string]e2wtypes.Account
+	monitor   metrics.FetcherMonitor
+	stores    []e2wtypes.Store
+	encryptor e2wtypes.Encryptor
+	// cache holds the wallets and accounts found in the stores, as a
+	// *cache.  It is replaced rather than updated when the stores are
+	// refreshed, so that it can be read without taking the mutex.
+	cache        atomic.Value
+	walletStores map[string]e2wtypes.Store
+	// refreshMu serialises refreshes of the stores.
+	refreshMu sync.Mutex
 	// Read-write copy of some information to allow for
 	// dynamic addition of accounts without requiring mutexes
 	// for normal access.
 	rwPubKeyPaths    map[[48]byte]string
 	rwWalletAccounts map[string]map[string]e2wtypes.Account
+	rwWallets        map[string]e2wtypes.Wallet
 	rwMu             sync.RWMutex
+	// removed holds the paths of accounts that have been removed, as a
+	// map[string]struct{}.  It is replaced rather than updated, so that
+	// it can be read without taking the mutex.
+	removed atomic.Value
+	// version is incremented whenever the accounts change.
+	version uint64
+}
+
+// cache contains the wallets and accounts found in the stores.
+// It is not modified once created.
+type cache struct {
+	pubKeyPaths    map[[48]byte]string
+	wallets        map[string]e2wtypes.Wallet
+	walletAccounts map[string]map[string]e2wtypes.Account
 }
 
 // module-wide log.
@@ -62,18 +84,44 @@ func New(ctx context.Context, params ...Parameter) (*Service, error) {
 		log = log.Level(parameters.logLevel)
 	}
 
-	wallets, walletAccounts, pubKeyPaths, err := populateCaches(ctx, parameters.stores, parameters.encryptor)
+	wallets, walletAccounts, walletStores, pubKeyPaths, failures, err := populateCaches(ctx, parameters.stores, parameters.encryptor)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to populate caches")
 	}
+	// The initial population is treated as a refresh from an empty cache.
+	parameters.monitor.FetcherRefreshCompleted(len(pubKeyPaths), 0, failures)
+	accounts := storeAccounts(parameters.stores, walletStores, walletAccounts)
+	parameters.monitor.FetcherAccounts(accounts)
+	for store, walletCounts := range accounts {
+		total := 0
+		for _, count := range walletCounts {
+			total += count
+		}
+		if total == 0 {
+			log.Warn().Str("store", store).Int("wallets", len(walletCounts)).Msg("No accounts loaded from store")
+			continue
+		}
+		log.Info().Str("store", store).Int("wallets", len(walletCounts)).Int("accounts", total).Msg("Loaded accounts from store")
+	}
 
 	s := &Service{
 		monitor:          parameters.monitor,
-		pubKeyPaths:      pubKeyPaths,
-		wallets:          wallets,
-		walletAccounts:   walletAccounts,
+		stores:           parameters.stores,
+		encryptor:        parameters.encryptor,
+		walletStores:     walletStores,
 		rwPubKeyPaths:    make(map[[48]byte]string),
 		rwWalletAccounts: make(map[string]map[string]e2wtypes.Account),
+		rwWallets:        make(map[string]e2wtypes.Wallet),
+	}
+	s.cache.Store(&cache{
+		pubKeyPaths:    pubKeyPaths,
+		wallets:        wallets,
+		walletAccounts: walletAccounts,
+	})
+	s.removed.Store(make(map[string]struct{}))
+
+	if parameters.refreshInterval > 0 {
+		go s.refreshPeriodically(ctx, parameters.refreshInterval)
 	}
 
 	return s, nil
@@ -87,7 +135,7 @@ func (s *Service) FetchWallet(ctx context.Context, path string) (e2wtypes.Wallet
 		return nil, errors.Wrap(err, "invalid path")
 	}
 
-	wallet, exists := s.wallets[walletName]
+	wallet, exists := s.wallet(walletName)
 	if exists {
 		log.Trace().Str("wallet", walletName).Msg("Wallet found in cache")
 		return wallet, nil
@@ -106,12 +154,16 @@ func (s *Service) FetchAccount(ctx context.Context, path string) (e2wtypes.Walle
 		return nil, nil, errors.Wrap(err, "invalid path")
 	}
 
-	wallet, exists := s.wallets[walletName]
+	wallet, exists := s.wallet(walletName)
 	if !exists {
 		return nil, nil, errors.New("failed to find wallet")
 	}
 
-	walletAccounts, exists := s.walletAccounts[walletName]
+	if s.isRemoved(walletName, accountName) {
+		return nil, nil, errors.New("failed to find account")
+	}
+
+	walletAccounts, exists := s.readCache().walletAccounts[walletName]
 	if exists {
 		account, exists := walletAccounts[accountName]
 		if exists {
@@ -122,6 +174,14 @@ func (s *Service) FetchAccount(ctx context.Context, path string) (e2wtypes.Walle
 	// Try the rw cache.
 	s.rwMu.RLock()
 	defer s.rwMu.RUnlock()
+	// The cache may have been replaced by a refresh since it was checked above.
+	walletAccounts, exists = s.readCache().walletAccounts[walletName]
+	if exists {
+		account, exists := walletAccounts[accountName]
+		if exists {
+			return wallet, account, nil
+		}
+	}
 	walletAccounts, exists = s.rwWalletAccounts[walletName]
 	if exists {
 		account, exists := walletAccounts[accountName]
@@ -135,10 +195,13 @@ func (s *Service) FetchAccount(ctx context.Context, path string) (e2wtypes.Walle
 
 // FetchAccountByKey fetches the account given its public key.
 func (s *Service) FetchAccountByKey(ctx context.Context, pubKey []byte) (e2wtypes.Wallet, e2wtypes.Account, error) {
-	path, exists := s.pubKeyPaths[bytesutil.ToBytes48(pubKey)]
+	path, exists := s.readCache().pubKeyPaths[bytesutil.ToBytes48(pubKey)]
 	if !exists {
 		s.rwMu.RLock()
-		path, exists = s.rwPubKeyPaths[bytesutil.ToBytes48(pubKey)]
+		path, exists = s.readCache().pubKeyPaths[bytesutil.ToBytes48(pubKey)]
+		if !exists {
+			path, exists = s.rwPubKeyPaths[bytesutil.ToBytes48(pubKey)]
+		}
 		s.rwMu.RUnlock()
 		if !exists {
 			return nil, nil, errors.New("public key not known")
@@ -157,17 +220,20 @@ func (s *Service) FetchAccounts(ctx context.Context, path string) (map[string]e2
 		return nil, errors.Wrap(err, "invalid path")
 	}
 
-	walletAccounts, exists := s.walletAccounts[walletName]
 	s.rwMu.RLock()
 	defer s.rwMu.RUnlock()
+	walletAccounts, exists := s.readCache().walletAccounts[walletName]
 	rwWalletAccounts, rwExists := s.rwWalletAccounts[walletName]
 	if !exists && !rwExists {
 		return nil, errors.New("found no accounts for wallet")
 	}
-	if rwExists {
+	removed := s.removed.Load().(map[string]struct{})
+	if rwExists || len(removed) > 0 {
 		allWalletAccounts := make(map[string]e2wtypes.Account, len(walletAccounts)+len(rwWalletAccounts))
 		for k, v := range walletAccounts {
-			allWalletAccounts[k] = v
+			if _, isRemoved := removed[fmt.Sprintf("%s/%s", walletName, k)]; !isRemoved {
+				allWalletAccounts[k] = v
+			}
 		}
 		for k, v := range rwWalletAccounts {
 			allWalletAccounts[k] = v
@@ -178,12 +244,63 @@ func (s *Service) FetchAccounts(ctx context.Context, path string) (map[string]e2
 	return walletAccounts, nil
 }
 
+// wallet returns the named wallet from the fetcher's internal stores.
+func (s *Service) wallet(name string) (e2wtypes.Wallet, bool) {
+	wallet, exists := s.readCache().wallets[name]
+	if exists {
+		return wallet, true
+	}
+
+	s.rwMu.RLock()
+	defer s.rwMu.RUnlock()
+	wallet, exists = s.readCache().wallets[name]
+	if exists {
+		return wallet, true
+	}
+	wallet, exists = s.rwWallets[name]
+	return wallet, exists
+}
+
+// FetchWallets fetches all wallets known to the fetcher.
+func (s *Service) FetchWallets(ctx context.Context) []e2wtypes.Wallet {
+	s.rwMu.RLock()
+	defer s.rwMu.RUnlock()
+	cachedWallets := s.readCache().wallets
+	wallets := make([]e2wtypes.Wallet, 0, len(cachedWallets)+len(s.rwWallets))
+	for _, wallet := range cachedWallets {
+		wallets = append(wallets, wallet)
+	}
+	for _, wallet := range s.rwWallets {
+		wallets = append(wallets, wallet)
+	}
+	return wallets
+}
+
+// AddWallet adds a wallet to the fetcher's internal stores.
+func (s *Service) AddWallet(ctx context.Context, wallet e2wtypes.Wallet, store e2wtypes.Store) error {
+	s.rwMu.Lock()
+	defer s.rwMu.Unlock()
+	if _, exists := s.readCache().wallets[wallet.Name()]; exists {
+		return errors.New("wallet already exists")
+	}
+	if _, exists := s.rwWallets[wallet.Name()]; exists {
+		return errors.New("wallet already exists")
+	}
+	s.rwWallets[wallet.Name()] = wallet
+	s.walletStores[wallet.Name()] = store
+	atomic.AddUint64(&s.version, 1)
+
+	return nil
+}
+
 // AddAccount adds an account to the fetcher's internal stores.
 func (s *Service) AddAccount(ctx context.Context, wallet e2wtypes.Wallet, account e2wtypes.Account) error {
 	s.rwMu.Lock()
 	defer s.rwMu.Unlock()
-	if _, exists := s.wallets[wallet.Name()]; !exists {
-		return errors.New("failed to find wallet")
+	if _, exists := s.readCache().wallets[wallet.Name()]; !exists {
+		if _, exists := s.rwWallets[wallet.Name()]; !exists {
+			return errors.New("failed to find wallet")
+		}
 	}
 	if _, exists := s.rwWalletAccounts[wallet.Name()]; !exists {
 		s.rwWalletAccounts[wallet.Name()] = make(map[string]e2wtypes.Account)
@@ -192,17 +309,117 @@ func (s *Service) AddAccount(ctx context.Context, wallet e2wtypes.Wallet, accoun
 
 	path := fmt.Sprintf("%s/%s", wallet.Name(), account.Name())
 	s.rwPubKeyPaths[bytesutil.ToBytes48(account.PublicKey().Marshal())] = path
+	if cached, exists := s.readCache().walletAccounts[wallet.Name()][account.Name()]; exists && cached != account {
+		// The read cache is checked first, so an account replacing one in it must also replace it there.
+		s.replaceCachedAccount(wallet.Name(), cached, account)
+	}
+	s.setRemoved(path, false)
+	atomic.AddUint64(&s.version, 1)
 
 	return nil
 }
 
+// replaceCachedAccount replaces an account in the read cache.
+// This must be called with the write lock held.
+func (s *Service) replaceCachedAccount(walletName string, existing e2wtypes.Account, account e2wtypes.Account) {
+	current := s.readCache()
+	walletAccounts := make(map[string]map[string]e2wtypes.Account, len(current.walletAccounts))
+	for name, accounts := range current.walletAccounts {
+		walletAccounts[name] = accounts
+	}
+	accounts := make(map[string]e2wtypes.Account, len(current.walletAccounts[walletName]))
+	for name, cached := range current.walletAccounts[walletName] {
+		accounts[name] = cached
+	}
+	accounts[account.Name()] = account
+	walletAccounts[walletName] = accounts
+	pubKeyPaths := make(map[[48]byte]string, len(current.pubKeyPaths))
+	for pubKey, path := range current.pubKeyPaths {
+		pubKeyPaths[pubKey] = path
+	}
+	delete(pubKeyPaths, bytesutil.ToBytes48(existing.PublicKey().Marshal()))
+	pubKeyPaths[bytesutil.ToBytes48(account.PublicKey().Marshal())] = fmt.Sprintf("%s/%s", walletName, account.Name())
+
+	s.cache.Store(&cache{
+		pubKeyPaths:    pubKeyPaths,
+		wallets:        current.wallets,
+		walletAccounts: walletAccounts,
+	})
+}
+
+// RemoveAccount removes an account from the fetcher's internal stores,
+// and deletes its key material from the store that holds its wallet.
+func (s *Service) RemoveAccount(ctx context.Context, wallet e2wtypes.Wallet, account e2wtypes.Account) error {
+	s.rwMu.Lock()
+	defer s.rwMu.Unlock()
+	store, exists := s.walletStores[wallet.Name()]
+	if !exists {
+		return errors.New("failed to find wallet")
+	}
+
+	if err := deleteAccount(store, wallet.ID(), account.ID(), account.Name()); err != nil {
+		return errors.Wrap(err, "failed to delete account from store")
+	}
+
+	path := fmt.Sprintf("%s/%s", wallet.Name(), account.Name())
+	delete(s.rwWalletAccounts[wallet.Name()], account.Name())
+	delete(s.rwPubKeyPaths, bytesutil.ToBytes48(account.PublicKey().Marshal()))
+	s.setRemoved(path, true)
+	atomic.AddUint64(&s.version, 1)
+
+	return nil
+}
+
+// readCache returns the current cache of wallets and accounts found in the stores.
+func (s *Service) readCache() *cache {
+	return s.cache.Load().(*cache)
+}
+
+// isRemoved returns true if the account has been removed.
+func (s *Service) isRemoved(walletName string, accountName string) bool {
+	removed := s.removed.Load().(map[string]struct{})
+	if len(removed) == 0 {
+		return false
+	}
+	_, isRemoved := removed[fmt.Sprintf("%s/%s", walletName, accountName)]
+	return isRemoved
+}
+
+// setRemoved marks or unmarks an account path as removed.
+// This must be called with the write lock held.
+func (s *Service) setRemoved(path string, isRemoved bool) {
+	old := s.removed.Load().(map[string]struct{})
+	if _, exists := old[path]; exists == isRemoved {
+		return
+	}
+	removed := make(map[string]struct{}, len(old)+1)
+	for k := range old {
+		removed[k] = struct{}{}
+	}
+	if isRemoved {
+		removed[path] = struct{}{}
+	} else {
+		delete(removed, path)
+	}
+	s.removed.Store(removed)
+}
+
+// Version provides the current version of the fetcher's accounts.
+func (s *Service) Version() uint64 {
+	return atomic.LoadUint64(&s.version)
+}
+
 // populateCaches populates wallet and account caches for the service.
-func populateCaches(ctx context.Context, stores []e2wtypes.Store, encryptor e2wtypes.Encryptor) (map[string]e2wtypes.Wallet, map[string]map[string]e2wtypes.Account, map[[48]byte]string, error) {
+// It also returns the number of wallets that could not be loaded; these
+// are skipped rather than failing the population.
+func populateCaches(ctx context.Context, stores []e2wtypes.Store, encryptor e2wtypes.Encryptor) (map[string]e2wtypes.Wallet, map[string]map[string]e2wtypes.Account, map[string]e2wtypes.Store, map[[48]byte]string, int, error) {
 	log.Trace().Msg("Populating fetcher caches")
 
 	wallets := make(map[string]e2wtypes.Wallet)
 	walletAccounts := make(map[string]map[string]e2wtypes.Account)
+	walletStores := make(map[string]e2wtypes.Store)
 	pubKeyPaths := make(map[[48]byte]string)
+	failures := 0
 	var mu sync.Mutex
 
 	var wg sync.WaitGroup
@@ -211,6 +428,7 @@ func populateCaches(ctx context.Context, stores []e2wtypes.Store, encryptor e2wt
 			wg.Add(1)
 			go func(
 				mu *sync.Mutex,
+				store e2wtypes.Store,
 				walletBytes []byte,
 				wallets map[string]e2wtypes.Wallet,
 				walletAccounts map[string]map[string]e2wtypes.Account,
@@ -220,10 +438,14 @@ func populateCaches(ctx context.Context, stores []e2wtypes.Store, encryptor e2wt
 				wallet, err := walletFromBytes(ctx, walletBytes, store, encryptor)
 				if err != nil {
 					log.Error().Err(err).Msg("failed to decode wallet")
+					mu.Lock()
+					failures++
+					mu.Unlock()
 					return
 				}
 				mu.Lock()
 				wallets[wallet.Name()] = wallet
+				walletStores[wallet.Name()] = store
 				mu.Unlock()
 				log.Trace().Str("wallet", wallet.Name()).Msg("Found wallet")
 
@@ -240,12 +462,46 @@ func populateCaches(ctx context.Context, stores []e2wtypes.Store, encryptor e2wt
 				mu.Lock()
 				walletAccounts[wallet.Name()] = accounts
 				mu.Unlock()
-			}(&mu, walletBytes, wallets, walletAccounts, pubKeyPaths, &wg)
+			}(&mu, store, walletBytes, wallets, walletAccounts, pubKeyPaths, &wg)
 		}
 	}
 	wg.Wait()
 
-	return wallets, walletAccounts, pubKeyPaths, nil
+	return wallets, walletAccounts, walletStores, pubKeyPaths, failures, nil
+}
+
+// storeAccounts returns the number of accounts in each wallet, keyed by
+// store and then wallet.  Every store is present, even if it holds no wallets.
+func storeAccounts(stores []e2wtypes.Store, walletStores map[string]e2wtypes.Store, walletAccounts map[string]map[string]e2wtypes.Account) map[string]map[string]int {
+	res := make(map[string]map[string]int, len(stores))
+	names := make(map[e2wtypes.Store]string, len(stores))
+	for _, store := range stores {
+		names[store] = storeName(store)
+		res[names[store]] = make(map[string]int)
+	}
+	for walletName, accounts := range walletAccounts {
+		store, exists := walletStores[walletName]
+		if !exists {
+			continue
+		}
+		name, exists := names[store]
+		if !exists {
+			name = storeName(store)
+			res[name] = make(map[string]int)
+		}
+		res[name][walletName] = len(accounts)
+	}
+	return res
+}
+
+// storeName returns a name for the store suitable for logs and metrics.
+// The location is included where available, to distinguish between
+// stores of the same type.
+func storeName(store e2wtypes.Store) string {
+	if provider, isProvider := store.(e2wtypes.StoreLocationProvider); isProvider {
+		return fmt.Sprintf("%s:%s", store.Name(), provider.Location())
+	}
+	return store.Name()
 }
 
 func walletFromBytes(ctx context.Context, data []byte, store e2wtypes.Store, encryptor e2wtypes.Encryptor) (e2wtypes.Wallet, error) {
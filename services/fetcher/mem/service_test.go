@@ -16,6 +16,7 @@ package mem_test
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"testing"
 
@@ -28,6 +29,7 @@ import (
 	"github.com/stretchr/testify/require"
 	e2types "github.com/wealdtech/go-eth2-types/v2"
 	e2wallet "github.com/wealdtech/go-eth2-wallet"
+	filesystem "github.com/wealdtech/go-eth2-wallet-store-filesystem"
 	scratch "github.com/wealdtech/go-eth2-wallet-store-scratch"
 	e2wtypes "github.com/wealdtech/go-eth2-wallet-types/v2"
 )
@@ -46,7 +48,7 @@ func TestNew(t *testing.T) {
 
 	tests := []struct {
 		name    string
-		monitor metrics.Service
+		monitor metrics.FetcherMonitor
 		stores  []e2wtypes.Store
 		err     string
 	}{
@@ -145,6 +147,35 @@ func TestFetchWallet(t *testing.T) {
 	}
 }
 
+func TestFetchWallets(t *testing.T) {
+	ctx := context.Background()
+
+	stores, err := createTestStores()
+	require.Nil(t, err)
+	fetcher, err := mem.New(context.Background(),
+		mem.WithLogLevel(zerolog.Disabled),
+		mem.WithStores(stores))
+	require.Nil(t, err)
+
+	walletNames := func() []string {
+		names := make([]string, 0)
+		for _, wallet := range fetcher.FetchWallets(ctx) {
+			names = append(names, wallet.Name())
+		}
+		return names
+	}
+	require.ElementsMatch(t, []string{"Test wallet", "Test HD wallet"}, walletNames())
+
+	// Added wallets are included.
+	store := scratch.New()
+	walletID := uuid.New()
+	require.NoError(t, store.StoreWallet(walletID, "Added wallet", []byte(fmt.Sprintf(`{"uuid":"%s","version":1,"name":"Added wallet","type":"non-deterministic"}`, walletID.String()))))
+	wallet, err := e2wallet.OpenWallet("Added wallet", e2wallet.WithStore(store))
+	require.NoError(t, err)
+	require.NoError(t, fetcher.AddWallet(ctx, wallet, store))
+	require.ElementsMatch(t, []string{"Test wallet", "Test HD wallet", "Added wallet"}, walletNames())
+}
+
 func TestFetchAccount(t *testing.T) {
 	ctx := context.Background()
 
@@ -393,3 +424,110 @@ func createTestStores() ([]e2wtypes.Store, error) {
 
 	return []e2wtypes.Store{store}, nil
 }
+
+func TestRemoveAccount(t *testing.T) {
+	ctx := context.Background()
+
+	base, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(base)
+	store := filesystem.New(filesystem.WithLocation(base))
+	walletID := uuid.New()
+	require.NoError(t, store.StoreWallet(walletID, "Test wallet", []byte(fmt.Sprintf(`{"uuid":"%s","version":1,"name":"Test wallet","type":"non-deterministic"}`, walletID.String()))))
+	wallet, err := e2wallet.OpenWallet("Test wallet", e2wallet.WithStore(store))
+	require.NoError(t, err)
+	require.NoError(t, wallet.(e2wtypes.WalletLocker).Unlock(ctx, nil))
+	_, err = wallet.(e2wtypes.WalletAccountCreator).CreateAccount(ctx, "Account 1", []byte{})
+	require.NoError(t, err)
+	_, err = wallet.(e2wtypes.WalletAccountCreator).CreateAccount(ctx, "Account 2", []byte{})
+	require.NoError(t, err)
+
+	fetcher, err := mem.New(ctx,
+		mem.WithStores([]e2wtypes.Store{store}))
+	require.NoError(t, err)
+
+	wallet, account, err := fetcher.FetchAccount(ctx, "Test wallet/Account 1")
+	require.NoError(t, err)
+	version := fetcher.Version()
+
+	require.NoError(t, fetcher.RemoveAccount(ctx, wallet, account))
+	require.Greater(t, fetcher.Version(), version)
+
+	_, _, err = fetcher.FetchAccount(ctx, "Test wallet/Account 1")
+	require.EqualError(t, err, "failed to find account")
+	_, _, err = fetcher.FetchAccountByKey(ctx, account.PublicKey().Marshal())
+	require.EqualError(t, err, "failed to find account")
+	accounts, err := fetcher.FetchAccounts(ctx, "Test wallet")
+	require.NoError(t, err)
+	require.Len(t, accounts, 1)
+	require.Contains(t, accounts, "Account 2")
+
+	// Ensure the key material has gone from the store.
+	_, err = store.RetrieveAccount(wallet.ID(), account.ID())
+	require.Error(t, err)
+
+	// Ensure the account does not return when the store is reloaded.
+	fetcher, err = mem.New(ctx,
+		mem.WithStores([]e2wtypes.Store{store}))
+	require.NoError(t, err)
+	_, _, err = fetcher.FetchAccount(ctx, "Test wallet/Account 1")
+	require.EqualError(t, err, "failed to find account")
+	_, _, err = fetcher.FetchAccount(ctx, "Test wallet/Account 2")
+	require.NoError(t, err)
+}
+
+func TestReplaceAccount(t *testing.T) {
+	ctx := context.Background()
+
+	base, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(base)
+	store := filesystem.New(filesystem.WithLocation(base))
+	walletID := uuid.New()
+	require.NoError(t, store.StoreWallet(walletID, "Test wallet", []byte(fmt.Sprintf(`{"uuid":"%s","version":1,"name":"Test wallet","type":"non-deterministic"}`, walletID.String()))))
+	wallet, err := e2wallet.OpenWallet("Test wallet", e2wallet.WithStore(store))
+	require.NoError(t, err)
+	require.NoError(t, wallet.(e2wtypes.WalletLocker).Unlock(ctx, nil))
+	_, err = wallet.(e2wtypes.WalletAccountCreator).CreateAccount(ctx, "Account 1", []byte{})
+	require.NoError(t, err)
+
+	// The account is in the cache populated from the store.
+	fetcher, err := mem.New(ctx,
+		mem.WithStores([]e2wtypes.Store{store}))
+	require.NoError(t, err)
+	_, existing, err := fetcher.FetchAccount(ctx, "Test wallet/Account 1")
+	require.NoError(t, err)
+
+	// Replace it with a new account of the same name.
+	require.NoError(t, fetcher.RemoveAccount(ctx, wallet, existing))
+	replacement, err := wallet.(e2wtypes.WalletAccountCreator).CreateAccount(ctx, "Account 1", []byte{})
+	require.NoError(t, err)
+	require.NoError(t, fetcher.AddAccount(ctx, wallet, replacement))
+
+	_, account, err := fetcher.FetchAccount(ctx, "Test wallet/Account 1")
+	require.NoError(t, err)
+	require.Equal(t, replacement.ID(), account.ID())
+	_, _, err = fetcher.FetchAccountByKey(ctx, existing.PublicKey().Marshal())
+	require.EqualError(t, err, "public key not known")
+	_, account, err = fetcher.FetchAccountByKey(ctx, replacement.PublicKey().Marshal())
+	require.NoError(t, err)
+	require.Equal(t, replacement.ID(), account.ID())
+}
+
+func TestRemoveAccountUnsupportedStore(t *testing.T) {
+	ctx := context.Background()
+
+	stores, err := createTestStores()
+	require.Nil(t, err)
+	fetcher, err := mem.New(ctx,
+		mem.WithStores(stores))
+	require.Nil(t, err)
+
+	wallet, account, err := fetcher.FetchAccount(ctx, "Test wallet/Test account")
+	require.NoError(t, err)
+	require.EqualError(t, fetcher.RemoveAccount(ctx, wallet, account), "failed to delete account from store: store scratch does not support account deletion")
+
+	// Account should still be present.
+	_, _, err = fetcher.FetchAccount(ctx, "Test wallet/Test account")
+	require.NoError(t, err)
+}
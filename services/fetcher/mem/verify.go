@@ -0,0 +1,194 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mem
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	e2wtypes "github.com/wealdtech/go-eth2-wallet-types/v2"
+)
+
+// VerifiedAccount is an account that was successfully loaded from a store.
+type VerifiedAccount struct {
+	Store   string
+	Wallet  e2wtypes.Wallet
+	Account e2wtypes.Account
+}
+
+// StoreProblem is a wallet or account that could not be loaded from a store.
+// Account is empty if the problem is with the wallet itself.
+type StoreProblem struct {
+	Store   string
+	Wallet  string
+	Account string
+	Err     error
+}
+
+// accountEntry is the subset of account data, or of an index entry, required to identify an account.
+type accountEntry struct {
+	ID   uuid.UUID `json:"uuid"`
+	Name string    `json:"name"`
+}
+
+// VerifyStores loads every wallet and account in the supplied stores using the same
+// logic as the fetcher, and returns the accounts that loaded along with any wallets
+// or accounts that did not.
+// An account is considered to have failed to load if it is present in its wallet's
+// index or in the store's account data but is not returned by its wallet.
+// Nothing is written to the stores.
+func VerifyStores(ctx context.Context, stores []e2wtypes.Store, encryptor e2wtypes.Encryptor) ([]*VerifiedAccount, []*StoreProblem) {
+	accounts := make([]*VerifiedAccount, 0)
+	problems := make([]*StoreProblem, 0)
+	for _, underlying := range stores {
+		// Opening a wallet can rebuild a missing index, so ensure that this goes nowhere.
+		store := &readOnlyStore{Store: underlying}
+		for walletBytes := range store.RetrieveWallets() {
+			wallet, err := walletFromBytes(ctx, walletBytes, store, encryptor)
+			if err != nil {
+				problems = append(problems, &StoreProblem{
+					Store:  store.Name(),
+					Wallet: walletNameFromBytes(walletBytes),
+					Err:    errors.Wrap(err, "failed to decode wallet"),
+				})
+				continue
+			}
+			walletAccounts, walletProblems := verifyWallet(ctx, store, wallet)
+			accounts = append(accounts, walletAccounts...)
+			problems = append(problems, walletProblems...)
+		}
+	}
+
+	return accounts, problems
+}
+
+// readOnlyStore is a store that silently discards writes.
+type readOnlyStore struct {
+	e2wtypes.Store
+}
+
+// StoreWallet discards wallet data.
+func (s *readOnlyStore) StoreWallet(walletID uuid.UUID, walletName string, data []byte) error {
+	return nil
+}
+
+// StoreAccount discards account data.
+func (s *readOnlyStore) StoreAccount(walletID uuid.UUID, accountID uuid.UUID, data []byte) error {
+	return nil
+}
+
+// StoreAccountsIndex discards the index of accounts.
+func (s *readOnlyStore) StoreAccountsIndex(walletID uuid.UUID, data []byte) error {
+	return nil
+}
+
+// verifyWallet verifies the accounts of a single wallet.
+func verifyWallet(ctx context.Context, store *readOnlyStore, wallet e2wtypes.Wallet) ([]*VerifiedAccount, []*StoreProblem) {
+	accounts := make([]*VerifiedAccount, 0)
+	problems := make([]*StoreProblem, 0)
+
+	loaded := make(map[uuid.UUID]bool)
+	for account := range wallet.Accounts(ctx) {
+		loaded[account.ID()] = true
+		accounts = append(accounts, &VerifiedAccount{
+			Store:   store.Name(),
+			Wallet:  wallet,
+			Account: account,
+		})
+	}
+
+	// Build the set of accounts that the store believes the wallet holds.
+	expected := make(map[uuid.UUID]string)
+	listed := false
+	if data, err := store.RetrieveAccountsIndex(wallet.ID()); err == nil {
+		entries := make([]*accountEntry, 0)
+		if err := json.Unmarshal(data, &entries); err != nil {
+			problems = append(problems, &StoreProblem{
+				Store:  store.Name(),
+				Wallet: wallet.Name(),
+				Err:    errors.Wrap(err, "failed to decode account index"),
+			})
+		} else {
+			listed = true
+			for _, entry := range entries {
+				expected[entry.ID] = entry.Name
+			}
+		}
+	}
+	// Filesystem stores silently skip account files that they cannot read, so list them directly.
+	if store.Name() == "filesystem" && isLocationProvider(store.Store) {
+		location := store.Store.(e2wtypes.StoreLocationProvider).Location()
+		files, err := ioutil.ReadDir(filepath.Join(location, wallet.ID().String()))
+		if err == nil {
+			listed = true
+			for _, file := range files {
+				id, err := uuid.Parse(file.Name())
+				if err != nil || id == wallet.ID() {
+					continue
+				}
+				if _, exists := expected[id]; !exists {
+					expected[id] = ""
+				}
+			}
+		}
+	}
+	for accountBytes := range store.RetrieveAccounts(wallet.ID()) {
+		entry := &accountEntry{}
+		if err := json.Unmarshal(accountBytes, entry); err != nil {
+			// Without an index or listing there is no other way of knowing this account exists.
+			if !listed {
+				problems = append(problems, &StoreProblem{
+					Store:  store.Name(),
+					Wallet: wallet.Name(),
+					Err:    errors.Wrap(err, "failed to decode account data"),
+				})
+			}
+			continue
+		}
+		if name, exists := expected[entry.ID]; !exists || name == "" {
+			expected[entry.ID] = entry.Name
+		}
+	}
+
+	for id, name := range expected {
+		if loaded[id] {
+			continue
+		}
+		if name == "" {
+			name = id.String()
+		}
+		problems = append(problems, &StoreProblem{
+			Store:   store.Name(),
+			Wallet:  wallet.Name(),
+			Account: name,
+			Err:     fmt.Errorf("account %s is known to the store but could not be loaded", id),
+		})
+	}
+
+	return accounts, problems
+}
+
+// walletNameFromBytes attempts to obtain a wallet's name from its raw data, for reporting.
+func walletNameFromBytes(data []byte) string {
+	info := &accountEntry{}
+	if err := json.Unmarshal(data, info); err != nil || info.Name == "" {
+		return "<unknown>"
+	}
+	return info.Name
+}
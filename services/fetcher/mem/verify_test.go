@@ -0,0 +1,82 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mem_test
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/attestantio/dirk/services/fetcher/mem"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	e2wallet "github.com/wealdtech/go-eth2-wallet"
+	keystorev4 "github.com/wealdtech/go-eth2-wallet-encryptor-keystorev4"
+	filesystem "github.com/wealdtech/go-eth2-wallet-store-filesystem"
+	e2wtypes "github.com/wealdtech/go-eth2-wallet-types/v2"
+)
+
+func TestVerifyStores(t *testing.T) {
+	ctx := context.Background()
+
+	base, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(base)
+	store := filesystem.New(filesystem.WithLocation(base))
+	walletID := uuid.New()
+	require.NoError(t, store.StoreWallet(walletID, "Test wallet", []byte(fmt.Sprintf(`{"uuid":"%s","version":1,"name":"Test wallet","type":"non-deterministic"}`, walletID.String()))))
+	wallet, err := e2wallet.OpenWallet("Test wallet", e2wallet.WithStore(store))
+	require.NoError(t, err)
+	require.NoError(t, wallet.(e2wtypes.WalletLocker).Unlock(ctx, nil))
+	_, err = wallet.(e2wtypes.WalletAccountCreator).CreateAccount(ctx, "Account 1", []byte{})
+	require.NoError(t, err)
+	account2, err := wallet.(e2wtypes.WalletAccountCreator).CreateAccount(ctx, "Account 2", []byte{})
+	require.NoError(t, err)
+	badWalletID := uuid.New()
+	require.NoError(t, store.StoreWallet(badWalletID, "Bad wallet", []byte(fmt.Sprintf(`{"uuid":"%s","version":1,"name":"Bad wallet","type":"unknown"}`, badWalletID.String()))))
+
+	// All good to start with (other than the bad wallet).
+	accounts, problems := mem.VerifyStores(ctx, []e2wtypes.Store{store}, keystorev4.New())
+	require.Len(t, accounts, 2)
+	require.Len(t, problems, 1)
+	require.Equal(t, "Bad wallet", problems[0].Wallet)
+	require.Equal(t, "", problems[0].Account)
+
+	// Corrupt the second account.
+	accountPath := filepath.Join(base, walletID.String(), account2.ID().String())
+	require.NoError(t, ioutil.WriteFile(accountPath, []byte("corrupt"), 0600))
+	accounts, problems = mem.VerifyStores(ctx, []e2wtypes.Store{store}, keystorev4.New())
+	require.Len(t, accounts, 1)
+	require.Equal(t, "Account 1", accounts[0].Account.Name())
+	require.Len(t, problems, 2)
+	found := false
+	for _, problem := range problems {
+		if problem.Account == "Account 2" {
+			found = true
+			require.Equal(t, "Test wallet", problem.Wallet)
+		}
+	}
+	require.True(t, found)
+
+	// Remove the index; the corrupt account should still be reported, and the index not recreated.
+	indexPath := filepath.Join(base, walletID.String(), "index")
+	require.NoError(t, os.Remove(indexPath))
+	_, problems = mem.VerifyStores(ctx, []e2wtypes.Store{store}, keystorev4.New())
+	require.Len(t, problems, 2)
+	_, err = os.Stat(indexPath)
+	require.True(t, os.IsNotExist(err))
+}
@@ -27,3 +27,34 @@ type Service interface {
 	FetchAccounts(ctx context.Context, path string) (map[string]types.Account, error)
 	AddAccount(ctx context.Context, wallet types.Wallet, account types.Account) error
 }
+
+// VersionProvider is the interface for a fetcher that provides a version for its accounts.
+// The version changes whenever accounts are added to or removed from the fetcher.
+type VersionProvider interface {
+	// Version provides the current version of the fetcher's accounts.
+	Version() uint64
+}
+
+// AccountRemover is the interface for a fetcher that can remove accounts.
+type AccountRemover interface {
+	// RemoveAccount removes an account from the fetcher and deletes its key material from the underlying store.
+	RemoveAccount(ctx context.Context, wallet types.Wallet, account types.Account) error
+}
+
+// WalletAdder is the interface for a fetcher that can add wallets.
+type WalletAdder interface {
+	// AddWallet adds a wallet, held in the given store, to the fetcher.
+	AddWallet(ctx context.Context, wallet types.Wallet, store types.Store) error
+}
+
+// WalletEnumerator is the interface for a fetcher that can enumerate its wallets.
+type WalletEnumerator interface {
+	// FetchWallets fetches all wallets known to the fetcher.
+	FetchWallets(ctx context.Context) []types.Wallet
+}
+
+// Refresher is the interface for a fetcher that can rescan its stores.
+type Refresher interface {
+	// Refresh rescans the stores for accounts that have been added or removed.
+	Refresh(ctx context.Context) error
+}
@@ -0,0 +1,116 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	zerologger "github.com/rs/zerolog/log"
+)
+
+// Service serves liveness and readiness endpoints over HTTP.
+type Service struct {
+	ready int32
+	mux   *http.ServeMux
+}
+
+// module-wide log.
+var log zerolog.Logger
+
+// New creates a new health service.
+func New(ctx context.Context, params ...Parameter) (*Service, error) {
+	parameters, err := parseAndCheckParameters(params...)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem with parameters")
+	}
+
+	// Set logging.
+	log = zerologger.With().Str("service", "health").Str("impl", "standard").Logger()
+	if parameters.logLevel != log.GetLevel() {
+		log = log.Level(parameters.logLevel)
+	}
+
+	s := &Service{
+		mux: http.NewServeMux(),
+	}
+	s.mux.HandleFunc("/healthz", s.healthz)
+	s.mux.HandleFunc("/readyz", s.readyz)
+
+	if parameters.address == "" {
+		// Share the default server with the metrics service.
+		http.Handle("/healthz", s.mux)
+		http.Handle("/readyz", s.mux)
+		return s, nil
+	}
+
+	// Listen synchronously so that a bad address is reported at startup.
+	listener, err := net.Listen("tcp", parameters.address)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to listen for health requests")
+	}
+	// The server is not stopped when the context is cancelled, so that readiness
+	// continues to be reported whilst Dirk shuts down.
+	server := &http.Server{
+		Handler: s.mux,
+	}
+	go func() {
+		if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			log.Warn().Str("health_listen_address", parameters.address).Err(err).Msg("Failed to run health server")
+		}
+	}()
+
+	return s, nil
+}
+
+// SetReady sets whether the process is ready to serve requests.
+func (s *Service) SetReady(ready bool) {
+	if ready {
+		atomic.StoreInt32(&s.ready, 1)
+	} else {
+		atomic.StoreInt32(&s.ready, 0)
+	}
+}
+
+// ServeHTTP serves the health endpoints.
+func (s *Service) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// healthz reports that the process is alive.
+func (s *Service) healthz(w http.ResponseWriter, _ *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write([]byte("ok\n")); err != nil {
+		log.Trace().Err(err).Msg("Failed to write liveness response")
+	}
+}
+
+// readyz reports whether the process is ready to serve requests.
+func (s *Service) readyz(w http.ResponseWriter, _ *http.Request) {
+	if atomic.LoadInt32(&s.ready) == 0 {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		if _, err := w.Write([]byte("not ready\n")); err != nil {
+			log.Trace().Err(err).Msg("Failed to write readiness response")
+		}
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write([]byte("ready\n")); err != nil {
+		log.Trace().Err(err).Msg("Failed to write readiness response")
+	}
+}
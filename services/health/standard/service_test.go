@@ -0,0 +1,74 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/attestantio/dirk/services/health/standard"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, err := standard.New(ctx,
+		standard.WithLogLevel(zerolog.Disabled),
+		standard.WithAddress("invalid"),
+	)
+	require.EqualError(t, err, "failed to listen for health requests: listen tcp: address invalid: missing port in address")
+
+	_, err = standard.New(ctx,
+		standard.WithLogLevel(zerolog.Disabled),
+		standard.WithAddress("127.0.0.1:0"),
+	)
+	require.NoError(t, err)
+}
+
+func TestEndpoints(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s, err := standard.New(ctx,
+		standard.WithLogLevel(zerolog.Disabled),
+		standard.WithAddress("127.0.0.1:0"),
+	)
+	require.NoError(t, err)
+
+	status := func(path string) int {
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec.Code
+	}
+
+	// Not ready until told otherwise.
+	require.Equal(t, http.StatusOK, status("/healthz"))
+	require.Equal(t, http.StatusServiceUnavailable, status("/readyz"))
+
+	s.SetReady(true)
+	require.Equal(t, http.StatusOK, status("/healthz"))
+	require.Equal(t, http.StatusOK, status("/readyz"))
+
+	// Shutting down.
+	s.SetReady(false)
+	require.Equal(t, http.StatusOK, status("/healthz"))
+	require.Equal(t, http.StatusServiceUnavailable, status("/readyz"))
+
+	require.Equal(t, http.StatusNotFound, status("/metrics"))
+}
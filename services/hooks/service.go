@@ -0,0 +1,28 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hooks
+
+import (
+	"context"
+
+	e2wtypes "github.com/wealdtech/go-eth2-wallet-types/v2"
+)
+
+// Service is the interface for notifying external systems of events.
+type Service interface {
+	// AccountCreated is called when an account has been created.
+	// It does not return an error, as a failure to notify must not affect
+	// the creation of the account.
+	AccountCreated(ctx context.Context, wallet e2wtypes.Wallet, account e2wtypes.Account)
+}
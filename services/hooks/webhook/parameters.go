@@ -0,0 +1,146 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+type parameters struct {
+	logLevel       zerolog.Level
+	url            string
+	authorization  string
+	timeout        time.Duration
+	maxAttempts    int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	queueSize      int
+}
+
+// Parameter is the interface for service parameters.
+type Parameter interface {
+	apply(*parameters)
+}
+
+type parameterFunc func(*parameters)
+
+func (f parameterFunc) apply(p *parameters) {
+	f(p)
+}
+
+// WithLogLevel sets the log level for the module.
+func WithLogLevel(logLevel zerolog.Level) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.logLevel = logLevel
+	})
+}
+
+// WithURL sets the URL to which notifications are sent.
+func WithURL(url string) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.url = url
+	})
+}
+
+// WithAuthorization sets the value of the Authorization header sent with notifications.
+func WithAuthorization(authorization string) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.authorization = authorization
+	})
+}
+
+// WithTimeout sets the timeout for each attempt to send a notification.
+func WithTimeout(timeout time.Duration) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.timeout = timeout
+	})
+}
+
+// WithMaxAttempts sets the maximum number of attempts to send each notification.
+func WithMaxAttempts(maxAttempts int) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.maxAttempts = maxAttempts
+	})
+}
+
+// WithInitialBackoff sets the time to wait before the first retry of a notification.
+// The time doubles with each subsequent retry, up to the maximum backoff.
+func WithInitialBackoff(backoff time.Duration) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.initialBackoff = backoff
+	})
+}
+
+// WithMaxBackoff sets the maximum time to wait between retries of a notification.
+func WithMaxBackoff(backoff time.Duration) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.maxBackoff = backoff
+	})
+}
+
+// WithQueueSize sets the number of notifications that can wait to be sent.
+func WithQueueSize(queueSize int) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.queueSize = queueSize
+	})
+}
+
+// parseAndCheckParameters parses and checks parameters to ensure that mandatory parameters are present and correct.
+func parseAndCheckParameters(params ...Parameter) (*parameters, error) {
+	parameters := parameters{
+		logLevel:       zerolog.GlobalLevel(),
+		timeout:        10 * time.Second,
+		maxAttempts:    5,
+		initialBackoff: time.Second,
+		maxBackoff:     time.Minute,
+		queueSize:      1024,
+	}
+	for _, p := range params {
+		if params != nil {
+			p.apply(&parameters)
+		}
+	}
+
+	if parameters.url == "" {
+		return nil, errors.New("no URL specified")
+	}
+	parsedURL, err := url.Parse(parameters.url)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid URL")
+	}
+	if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" {
+		return nil, errors.New("URL must be http or https")
+	}
+	if parameters.timeout <= 0 {
+		return nil, errors.New("timeout must be positive")
+	}
+	if parameters.maxAttempts < 1 {
+		return nil, errors.New("max attempts must be at least 1")
+	}
+	if parameters.initialBackoff <= 0 {
+		return nil, errors.New("initial backoff must be positive")
+	}
+	if parameters.maxBackoff < parameters.initialBackoff {
+		return nil, errors.New("max backoff cannot be less than initial backoff")
+	}
+	if parameters.queueSize < 1 {
+		return nil, errors.New("queue size must be at least 1")
+	}
+
+	return &parameters, nil
+}
@@ -0,0 +1,205 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	zerologger "github.com/rs/zerolog/log"
+	e2wtypes "github.com/wealdtech/go-eth2-wallet-types/v2"
+)
+
+// Service sends notifications of events to a webhook.
+type Service struct {
+	url            string
+	authorization  string
+	maxAttempts    int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	client         *http.Client
+	queue          chan *accountCreatedEvent
+}
+
+// participant is a participant in a distributed account.
+type participant struct {
+	ID      uint64 `json:"id"`
+	Address string `json:"address"`
+}
+
+// accountCreatedEvent is the body of an account creation notification.
+// It contains only public information about the account.
+type accountCreatedEvent struct {
+	Event              string         `json:"event"`
+	Wallet             string         `json:"wallet"`
+	Account            string         `json:"account"`
+	Path               string         `json:"path"`
+	PublicKey          string         `json:"public_key"`
+	CompositePublicKey string         `json:"composite_public_key,omitempty"`
+	SigningThreshold   uint32         `json:"signing_threshold,omitempty"`
+	Participants       []*participant `json:"participants,omitempty"`
+}
+
+// module-wide log.
+var log zerolog.Logger
+
+// New creates a new webhook service.
+func New(ctx context.Context, params ...Parameter) (*Service, error) {
+	parameters, err := parseAndCheckParameters(params...)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem with parameters")
+	}
+
+	// Set logging.
+	log = zerologger.With().Str("service", "hooks").Str("impl", "webhook").Logger()
+	if parameters.logLevel != log.GetLevel() {
+		log = log.Level(parameters.logLevel)
+	}
+
+	s := &Service{
+		url:            parameters.url,
+		authorization:  parameters.authorization,
+		maxAttempts:    parameters.maxAttempts,
+		initialBackoff: parameters.initialBackoff,
+		maxBackoff:     parameters.maxBackoff,
+		client: &http.Client{
+			Timeout: parameters.timeout,
+		},
+		queue: make(chan *accountCreatedEvent, parameters.queueSize),
+	}
+
+	go s.run(ctx)
+
+	return s, nil
+}
+
+// AccountCreated queues a notification that an account has been created.
+func (s *Service) AccountCreated(ctx context.Context, wallet e2wtypes.Wallet, account e2wtypes.Account) {
+	event := &accountCreatedEvent{
+		Event:     "account_created",
+		Wallet:    wallet.Name(),
+		Account:   account.Name(),
+		Path:      fmt.Sprintf("%s/%s", wallet.Name(), account.Name()),
+		PublicKey: fmt.Sprintf("%#x", account.PublicKey().Marshal()),
+	}
+	if provider, isProvider := account.(e2wtypes.AccountCompositePublicKeyProvider); isProvider {
+		event.CompositePublicKey = fmt.Sprintf("%#x", provider.CompositePublicKey().Marshal())
+	}
+	if provider, isProvider := account.(e2wtypes.AccountSigningThresholdProvider); isProvider {
+		event.SigningThreshold = provider.SigningThreshold()
+	}
+	if provider, isProvider := account.(e2wtypes.AccountParticipantsProvider); isProvider {
+		participants := provider.Participants()
+		event.Participants = make([]*participant, 0, len(participants))
+		for id, address := range participants {
+			event.Participants = append(event.Participants, &participant{
+				ID:      id,
+				Address: address,
+			})
+		}
+		sort.Slice(event.Participants, func(i, j int) bool {
+			return event.Participants[i].ID < event.Participants[j].ID
+		})
+	}
+
+	select {
+	case s.queue <- event:
+	default:
+		log.Error().Str("account", event.Path).Msg("Notification queue full; account creation not notified")
+	}
+}
+
+// run sends queued notifications until the context is done.
+func (s *Service) run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event := <-s.queue:
+			s.deliver(ctx, event)
+		}
+	}
+}
+
+// deliver sends a notification, retrying with backoff on failure.
+func (s *Service) deliver(ctx context.Context, event *accountCreatedEvent) {
+	log := log.With().Str("account", event.Path).Logger()
+	body, err := json.Marshal(event)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to encode notification")
+		return
+	}
+
+	backoff := s.initialBackoff
+	for attempt := 1; ; attempt++ {
+		retry, err := s.send(ctx, body)
+		if err == nil {
+			log.Trace().Int("attempt", attempt).Msg("Notified account creation")
+			return
+		}
+		if !retry || attempt == s.maxAttempts {
+			log.Error().Err(err).Int("attempt", attempt).Msg("Failed to notify account creation; giving up")
+			return
+		}
+		log.Warn().Err(err).Int("attempt", attempt).Dur("backoff", backoff).Msg("Failed to notify account creation; will retry")
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if backoff > s.maxBackoff {
+			backoff = s.maxBackoff
+		}
+	}
+}
+
+// send sends a notification body to the webhook.  It returns true if a
+// failure is worth retrying.
+func (s *Service) send(ctx context.Context, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return false, errors.Wrap(err, "failed to create request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.authorization != "" {
+		req.Header.Set("Authorization", s.authorization)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return true, errors.Wrap(err, "failed to send request")
+	}
+	defer resp.Body.Close()
+	// Drain the body to allow the connection to be reused.
+	if _, err := io.Copy(ioutil.Discard, resp.Body); err != nil {
+		log.Trace().Err(err).Msg("Failed to read response body")
+	}
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	// Client errors other than rate limiting will not succeed on retry.
+	retry := resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+	return retry, fmt.Errorf("webhook returned status %d", resp.StatusCode)
+}
@@ -0,0 +1,196 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/attestantio/dirk/services/hooks/webhook"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+	e2types "github.com/wealdtech/go-eth2-types/v2"
+	e2wallet "github.com/wealdtech/go-eth2-wallet"
+	scratch "github.com/wealdtech/go-eth2-wallet-store-scratch"
+	e2wtypes "github.com/wealdtech/go-eth2-wallet-types/v2"
+)
+
+func TestMain(m *testing.M) {
+	if err := e2types.InitBLS(); err != nil {
+		os.Exit(1)
+	}
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	os.Exit(m.Run())
+}
+
+func TestNew(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	tests := []struct {
+		name   string
+		params []webhook.Parameter
+		err    string
+	}{
+		{
+			name: "URLMissing",
+			err:  "problem with parameters: no URL specified",
+		},
+		{
+			name:   "URLBadScheme",
+			params: []webhook.Parameter{webhook.WithURL("ftp://localhost/")},
+			err:    "problem with parameters: URL must be http or https",
+		},
+		{
+			name:   "TimeoutZero",
+			params: []webhook.Parameter{webhook.WithURL("http://localhost/"), webhook.WithTimeout(0)},
+			err:    "problem with parameters: timeout must be positive",
+		},
+		{
+			name:   "MaxAttemptsZero",
+			params: []webhook.Parameter{webhook.WithURL("http://localhost/"), webhook.WithMaxAttempts(0)},
+			err:    "problem with parameters: max attempts must be at least 1",
+		},
+		{
+			name:   "MaxBackoffLow",
+			params: []webhook.Parameter{webhook.WithURL("http://localhost/"), webhook.WithInitialBackoff(time.Second), webhook.WithMaxBackoff(time.Millisecond)},
+			err:    "problem with parameters: max backoff cannot be less than initial backoff",
+		},
+		{
+			name:   "Good",
+			params: []webhook.Parameter{webhook.WithURL("https://localhost/")},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := webhook.New(ctx, test.params...)
+			if test.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, test.err)
+			}
+		})
+	}
+}
+
+func TestAccountCreated(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	store := scratch.New()
+	wallet, err := e2wallet.CreateWallet("Test wallet", e2wallet.WithStore(store), e2wallet.WithType("nd"))
+	require.NoError(t, err)
+	require.NoError(t, wallet.(e2wtypes.WalletLocker).Unlock(ctx, nil))
+	account, err := wallet.(e2wtypes.WalletAccountCreator).CreateAccount(ctx, "Test account", []byte("secret"))
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	attempts := 0
+	bodies := make([]map[string]interface{}, 0)
+	authorizations := make([]string, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts++
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		data := make(map[string]interface{})
+		require.NoError(t, json.Unmarshal(body, &data))
+		bodies = append(bodies, data)
+		authorizations = append(authorizations, r.Header.Get("Authorization"))
+		// Fail the first attempt.
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	s, err := webhook.New(ctx,
+		webhook.WithURL(server.URL),
+		webhook.WithAuthorization("Bearer token"),
+		webhook.WithInitialBackoff(10*time.Millisecond),
+	)
+	require.NoError(t, err)
+	s.AccountCreated(ctx, wallet, account)
+
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return attempts == 2
+	}, time.Second, 5*time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Equal(t, []string{"Bearer token", "Bearer token"}, authorizations)
+	require.Equal(t, bodies[0], bodies[1])
+	require.Equal(t, map[string]interface{}{
+		"event":      "account_created",
+		"wallet":     "Test wallet",
+		"account":    "Test account",
+		"path":       "Test wallet/Test account",
+		"public_key": fmt.Sprintf("%#x", account.PublicKey().Marshal()),
+	}, bodies[0])
+}
+
+func TestAccountCreatedNoRetry(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	store := scratch.New()
+	wallet, err := e2wallet.CreateWallet("Test wallet", e2wallet.WithStore(store), e2wallet.WithType("nd"))
+	require.NoError(t, err)
+	require.NoError(t, wallet.(e2wtypes.WalletLocker).Unlock(ctx, nil))
+	account, err := wallet.(e2wtypes.WalletAccountCreator).CreateAccount(ctx, "Test account", []byte("secret"))
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		attempts++
+		mu.Unlock()
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	s, err := webhook.New(ctx,
+		webhook.WithURL(server.URL),
+		webhook.WithInitialBackoff(time.Millisecond),
+	)
+	require.NoError(t, err)
+	s.AccountCreated(ctx, wallet, account)
+	s.AccountCreated(ctx, wallet, account)
+
+	// Each notification is attempted once, as client errors are not retried.
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return attempts == 2
+	}, time.Second, 5*time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	mu.Lock()
+	require.Equal(t, 2, attempts)
+	mu.Unlock()
+}
@@ -28,3 +28,23 @@ type Service interface {
 		credentials *checker.Credentials,
 		paths []string) (core.Result, []e2wtypes.Account)
 }
+
+// CacheInvalidator is the interface for a lister that caches account lists.
+type CacheInvalidator interface {
+	// InvalidateCache removes all cached account lists.
+	InvalidateCache(ctx context.Context)
+}
+
+// WalletAccounts are the listed accounts for a wallet.
+type WalletAccounts struct {
+	Wallet   e2wtypes.Wallet
+	Accounts []e2wtypes.Account
+}
+
+// WalletLister is the interface for a lister that can list accounts grouped by wallet.
+type WalletLister interface {
+	// ListAccountsByWallet lists accessible accounts given by the paths, grouped by wallet.
+	ListAccountsByWallet(ctx context.Context,
+		credentials *checker.Credentials,
+		paths []string) (core.Result, []*WalletAccounts)
+}
@@ -0,0 +1,132 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/attestantio/dirk/services/fetcher"
+)
+
+// listCache is a cache of account lists, keyed by client, address and paths.
+type listCache struct {
+	ttl             time.Duration
+	versionProvider fetcher.VersionProvider
+	mu              sync.Mutex
+	entries         map[string]*listCacheEntry
+	// generation is incremented whenever the cache is cleared.
+	generation uint64
+}
+
+type listCacheEntry struct {
+	accounts []*listedAccount
+	version  uint64
+	expiry   time.Time
+}
+
+func newListCache(ttl time.Duration, versionProvider fetcher.VersionProvider) *listCache {
+	return &listCache{
+		ttl:             ttl,
+		versionProvider: versionProvider,
+		entries:         make(map[string]*listCacheEntry),
+	}
+}
+
+// listCacheKey generates the cache key for a client, address and paths.
+// The address is included because access to accounts can depend on it.
+func listCacheKey(client string, ip string, paths []string) string {
+	// Quote each component so that keys are unambiguous.
+	var key strings.Builder
+	key.WriteString(strconv.Quote(client))
+	key.WriteString(",")
+	key.WriteString(strconv.Quote(ip))
+	for _, path := range paths {
+		key.WriteString(",")
+		key.WriteString(strconv.Quote(path))
+	}
+	return key.String()
+}
+
+// get obtains the cached accounts for a key, if present and current.
+func (c *listCache) get(key string) ([]*listedAccount, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, exists := c.entries[key]
+	if !exists {
+		return nil, false
+	}
+	if time.Now().After(entry.expiry) || entry.version != c.versionProvider.Version() {
+		delete(c.entries, key)
+		return nil, false
+	}
+
+	return entry.accounts, true
+}
+
+// state provides the current fetcher version and cache generation.
+// This should be obtained before accounts are fetched and passed to set, to
+// ensure that any change while fetching results in the entry not being used.
+func (c *listCache) state() (uint64, uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.versionProvider.Version(), c.generation
+}
+
+// set caches the accounts for a key.
+func (c *listCache) set(key string, version uint64, generation uint64, accounts []*listedAccount) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if generation != c.generation {
+		// Cache has been cleared since the accounts were obtained.
+		return
+	}
+
+	// Remove any expired entries to stop the cache growing without bound.
+	now := time.Now()
+	for k, entry := range c.entries {
+		if now.After(entry.expiry) {
+			delete(c.entries, k)
+		}
+	}
+
+	c.entries[key] = &listCacheEntry{
+		accounts: accounts,
+		version:  version,
+		expiry:   now.Add(c.ttl),
+	}
+}
+
+// clear removes all entries from the cache.
+func (c *listCache) clear() {
+	c.mu.Lock()
+	c.entries = make(map[string]*listCacheEntry)
+	c.generation++
+	c.mu.Unlock()
+}
+
+// InvalidateCache removes all cached account lists.  This should be called
+// whenever the permissions that control access to accounts change.
+func (s *Service) InvalidateCache(ctx context.Context) {
+	if s.cache == nil {
+		return
+	}
+	s.cache.clear()
+	log.Trace().Msg("Account list cache invalidated")
+}
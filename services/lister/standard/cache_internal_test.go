@@ -0,0 +1,92 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type versionProvider struct {
+	version uint64
+}
+
+func (v *versionProvider) Version() uint64 {
+	return v.version
+}
+
+func TestListCache(t *testing.T) {
+	provider := &versionProvider{}
+	cache := newListCache(time.Hour, provider)
+	accounts := make([]*listedAccount, 0)
+
+	key1 := listCacheKey("client1", "10.0.0.1", []string{"Wallet1"})
+	key2 := listCacheKey("client2", "10.0.0.1", []string{"Wallet1"})
+	require.NotEqual(t, key1, key2)
+	require.NotEqual(t, key1, listCacheKey("client1", "10.0.0.2", []string{"Wallet1"}))
+	// Different splits of the same characters must not collide.
+	require.NotEqual(t, listCacheKey("client1", "10.0.0.1", []string{"a", "b"}), listCacheKey("client1", "10.0.0.1", []string{"a\",\"b"}))
+
+	_, exists := cache.get(key1)
+	require.False(t, exists)
+
+	version, generation := cache.state()
+	cache.set(key1, version, generation, accounts)
+	_, exists = cache.get(key1)
+	require.True(t, exists)
+	_, exists = cache.get(key2)
+	require.False(t, exists)
+
+	// Accounts changing invalidates the entry.
+	provider.version++
+	_, exists = cache.get(key1)
+	require.False(t, exists)
+
+	// Entry obtained before accounts changed is not used.
+	version, generation = cache.state()
+	provider.version++
+	cache.set(key1, version, generation, accounts)
+	_, exists = cache.get(key1)
+	require.False(t, exists)
+
+	// Clearing invalidates the entry.
+	version, generation = cache.state()
+	cache.set(key1, version, generation, accounts)
+	cache.clear()
+	_, exists = cache.get(key1)
+	require.False(t, exists)
+
+	// Entry obtained before clearing is not stored.
+	version, generation = cache.state()
+	cache.clear()
+	cache.set(key1, version, generation, accounts)
+	_, exists = cache.get(key1)
+	require.False(t, exists)
+}
+
+func TestListCacheExpiry(t *testing.T) {
+	cache := newListCache(10*time.Millisecond, &versionProvider{})
+	key := listCacheKey("client1", "10.0.0.1", []string{"Wallet1"})
+
+	version, generation := cache.state()
+	cache.set(key, version, generation, make([]*listedAccount, 0))
+	_, exists := cache.get(key)
+	require.True(t, exists)
+
+	time.Sleep(20 * time.Millisecond)
+	_, exists = cache.get(key)
+	require.False(t, exists)
+}
@@ -23,13 +23,59 @@ import (
 	"github.com/attestantio/dirk/core"
 	"github.com/attestantio/dirk/rules"
 	"github.com/attestantio/dirk/services/checker"
+	"github.com/attestantio/dirk/services/lister"
 	"github.com/attestantio/dirk/services/ruler"
 	wallet "github.com/wealdtech/go-eth2-wallet"
 	e2wtypes "github.com/wealdtech/go-eth2-wallet-types/v2"
 )
 
+// listedAccount is an account that has been listed, along with its wallet.
+type listedAccount struct {
+	wallet  e2wtypes.Wallet
+	account e2wtypes.Account
+}
+
 // ListAccounts lists accounts.
 func (s *Service) ListAccounts(ctx context.Context, credentials *checker.Credentials, paths []string) (core.Result, []e2wtypes.Account) {
+	result, listed := s.listAccounts(ctx, credentials, paths)
+	if result != core.ResultSucceeded {
+		return result, nil
+	}
+
+	accounts := make([]e2wtypes.Account, len(listed))
+	for i := range listed {
+		accounts[i] = listed[i].account
+	}
+	return core.ResultSucceeded, accounts
+}
+
+// ListAccountsByWallet lists accounts, grouped by wallet.
+// Wallets are returned in the order in which they are first matched by the paths.
+func (s *Service) ListAccountsByWallet(ctx context.Context, credentials *checker.Credentials, paths []string) (core.Result, []*lister.WalletAccounts) {
+	result, listed := s.listAccounts(ctx, credentials, paths)
+	if result != core.ResultSucceeded {
+		return result, nil
+	}
+
+	walletAccounts := make([]*lister.WalletAccounts, 0)
+	groups := make(map[string]*lister.WalletAccounts)
+	for i := range listed {
+		group, exists := groups[listed[i].wallet.Name()]
+		if !exists {
+			group = &lister.WalletAccounts{
+				Wallet:   listed[i].wallet,
+				Accounts: make([]e2wtypes.Account, 0),
+			}
+			groups[listed[i].wallet.Name()] = group
+			walletAccounts = append(walletAccounts, group)
+		}
+		group.Accounts = append(group.Accounts, listed[i].account)
+	}
+	return core.ResultSucceeded, walletAccounts
+}
+
+// listAccounts lists accounts along with their wallets.
+func (s *Service) listAccounts(ctx context.Context, credentials *checker.Credentials, paths []string) (core.Result, []*listedAccount) {
 	started := time.Now()
 
 	if credentials == nil {
@@ -44,7 +90,22 @@ func (s *Service) ListAccounts(ctx context.Context, credentials *checker.Credent
 		Logger()
 	log.Trace().Msg("Request received")
 
-	accounts := make([]e2wtypes.Account, 0)
+	var cacheKey string
+	var cacheVersion uint64
+	var cacheGeneration uint64
+	if s.cache != nil {
+		cacheKey = listCacheKey(credentials.Client, credentials.IP, paths)
+		if accounts, exists := s.cache.get(cacheKey); exists {
+			s.monitor.ListAccountsCacheLookup(true)
+			log.Trace().Str("result", "succeeded").Dur("elapsed", time.Since(started)).Int("accounts", len(accounts)).Msg("Success (cached)")
+			s.monitor.ListAccountsCompleted(started)
+			return core.ResultSucceeded, accounts
+		}
+		s.monitor.ListAccountsCacheLookup(false)
+		cacheVersion, cacheGeneration = s.cache.state()
+	}
+
+	accounts := make([]*listedAccount, 0)
 	for _, path := range paths {
 		log := log.With().Str("path", path).Logger()
 		walletName, accountPath, err := wallet.WalletAndAccountNames(path)
@@ -121,12 +182,19 @@ func (s *Service) ListAccounts(ctx context.Context, credentials *checker.Credent
 				}
 				results := s.ruler.RunRules(ctx, credentials, ruler.ActionAccessAccount, rulesData)
 				if results[0] == rules.APPROVED {
-					accounts = append(accounts, walletAccount)
+					accounts = append(accounts, &listedAccount{
+						wallet:  wallet,
+						account: walletAccount,
+					})
 				}
 			}
 		}
 	}
 
+	if s.cache != nil {
+		s.cache.set(cacheKey, cacheVersion, cacheGeneration, accounts)
+	}
+
 	log.Trace().Str("result", "succeeded").Dur("elapsed", time.Since(started)).Int("accounts", len(accounts)).Msg("Success")
 	s.monitor.ListAccountsCompleted(started)
 	return core.ResultSucceeded, accounts
@@ -21,3 +21,6 @@ type noopMonitor struct{}
 
 // ListAccountsCompleted is called when a request for accounts has completed.
 func (n *noopMonitor) ListAccountsCompleted(started time.Time) {}
+
+// ListAccountsCacheLookup is called when the account list cache is checked for a request.
+func (n *noopMonitor) ListAccountsCacheLookup(hit bool) {}
@@ -14,6 +14,8 @@
 package standard
 
 import (
+	"time"
+
 	"github.com/attestantio/dirk/services/checker"
 	"github.com/attestantio/dirk/services/fetcher"
 	"github.com/attestantio/dirk/services/metrics"
@@ -28,6 +30,7 @@ type parameters struct {
 	checker  checker.Service
 	fetcher  fetcher.Service
 	ruler    ruler.Service
+	cacheTTL time.Duration
 }
 
 // Parameter is the interface for service parameters.
@@ -76,6 +79,13 @@ func WithFetcher(fetcher fetcher.Service) Parameter {
 	})
 }
 
+// WithCacheTTL sets the time for which account lists are cached.  A value of 0 disables caching.
+func WithCacheTTL(cacheTTL time.Duration) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.cacheTTL = cacheTTL
+	})
+}
+
 // parseAndCheckParameters parses and checks parameters to ensure that mandatory parameters are present and correct.
 func parseAndCheckParameters(params ...Parameter) (*parameters, error) {
 	parameters := parameters{
@@ -100,6 +110,9 @@ func parseAndCheckParameters(params ...Parameter) (*parameters, error) {
 	if parameters.fetcher == nil {
 		return nil, errors.New("no fetcher specified")
 	}
+	if parameters.cacheTTL < 0 {
+		return nil, errors.New("cache TTL cannot be negative")
+	}
 
 	return &parameters, nil
 }
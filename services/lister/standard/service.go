@@ -31,6 +31,7 @@ type Service struct {
 	checker checker.Service
 	fetcher fetcher.Service
 	ruler   ruler.Service
+	cache   *listCache
 }
 
 // module-wide log.
@@ -56,5 +57,14 @@ func New(ctx context.Context, params ...Parameter) (*Service, error) {
 		ruler:   parameters.ruler,
 	}
 
+	if parameters.cacheTTL > 0 {
+		// The cache relies on the fetcher to tell it when accounts change.
+		if versionProvider, isProvider := parameters.fetcher.(fetcher.VersionProvider); isProvider {
+			s.cache = newListCache(parameters.cacheTTL, versionProvider)
+		} else {
+			log.Warn().Msg("Fetcher does not provide account versions; account list caching disabled")
+		}
+	}
+
 	return s, nil
 }
@@ -0,0 +1,83 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitored
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/attestantio/dirk/services/metrics"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	zerologger "github.com/rs/zerolog/log"
+	"github.com/wealdtech/go-majordomo"
+)
+
+// Service is a majordomo service that passes fetches to an underlying
+// majordomo, reporting the time taken and result of each fetch.
+type Service struct {
+	monitor   metrics.MajordomoMonitor
+	majordomo majordomo.Service
+}
+
+// module-wide log.
+var log zerolog.Logger
+
+// New creates a new monitored majordomo service.
+func New(ctx context.Context, params ...Parameter) (*Service, error) {
+	parameters, err := parseAndCheckParameters(params...)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem with parameters")
+	}
+
+	// Set logging.
+	log = zerologger.With().Str("service", "majordomo").Str("impl", "monitored").Logger()
+	if parameters.logLevel != log.GetLevel() {
+		log = log.Level(parameters.logLevel)
+	}
+
+	s := &Service{
+		monitor:   parameters.monitor,
+		majordomo: parameters.majordomo,
+	}
+
+	return s, nil
+}
+
+// Fetch fetches a value given its key.
+func (s *Service) Fetch(ctx context.Context, key string) ([]byte, error) {
+	started := time.Now()
+	value, err := s.majordomo.Fetch(ctx, key)
+	scheme := keyScheme(key)
+	s.monitor.MajordomoFetchCompleted(started, scheme, err == nil)
+	if err != nil {
+		// The key is not logged, as it can contain credentials.
+		log.Debug().Str("scheme", scheme).Dur("elapsed", time.Since(started)).Err(err).Msg("Failed to fetch value")
+	} else {
+		log.Trace().Str("scheme", scheme).Dur("elapsed", time.Since(started)).Msg("Fetched value")
+	}
+	return value, err
+}
+
+// keyScheme returns the confidant scheme of a key, or "unknown" if it
+// cannot be obtained.
+func keyScheme(key string) string {
+	parsed, err := url.Parse(key)
+	if err != nil || parsed.Scheme == "" {
+		return "unknown"
+	}
+	return strings.ToLower(parsed.Scheme)
+}
@@ -0,0 +1,118 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitored_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/attestantio/dirk/services/majordomo/monitored"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fetchRecord struct {
+	scheme    string
+	succeeded bool
+}
+
+type recordingMonitor struct {
+	fetches []*fetchRecord
+}
+
+func (m *recordingMonitor) MajordomoFetchCompleted(started time.Time, scheme string, succeeded bool) {
+	m.fetches = append(m.fetches, &fetchRecord{scheme: scheme, succeeded: succeeded})
+}
+
+type mockMajordomo struct{}
+
+func (m *mockMajordomo) Fetch(ctx context.Context, key string) ([]byte, error) {
+	if key == "file:///missing" {
+		return nil, errors.New("not found")
+	}
+	return []byte("value"), nil
+}
+
+func TestNew(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := monitored.New(ctx)
+	require.EqualError(t, err, "problem with parameters: no majordomo specified")
+
+	s, err := monitored.New(ctx, monitored.WithMajordomo(&mockMajordomo{}))
+	require.NoError(t, err)
+	value, err := s.Fetch(ctx, "direct:///value")
+	require.NoError(t, err)
+	require.Equal(t, []byte("value"), value)
+}
+
+func TestFetch(t *testing.T) {
+	ctx := context.Background()
+	monitor := &recordingMonitor{}
+	s, err := monitored.New(ctx,
+		monitored.WithMonitor(monitor),
+		monitored.WithMajordomo(&mockMajordomo{}),
+	)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		key    string
+		err    string
+		record *fetchRecord
+	}{
+		{
+			name:   "Succeeded",
+			key:    "gsm://project/secret",
+			record: &fetchRecord{scheme: "gsm", succeeded: true},
+		},
+		{
+			name:   "Failed",
+			key:    "file:///missing",
+			err:    "not found",
+			record: &fetchRecord{scheme: "file", succeeded: false},
+		},
+		{
+			name:   "UpperCaseScheme",
+			key:    "GSM://project/secret",
+			record: &fetchRecord{scheme: "gsm", succeeded: true},
+		},
+		{
+			name:   "NoScheme",
+			key:    "secret",
+			record: &fetchRecord{scheme: "unknown", succeeded: true},
+		},
+		{
+			name:   "Unparseable",
+			key:    "%gh&%ij",
+			record: &fetchRecord{scheme: "unknown", succeeded: true},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			monitor.fetches = nil
+			_, err := s.Fetch(ctx, test.key)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Len(t, monitor.fetches, 1)
+			assert.Equal(t, test.record, monitor.fetches[0])
+		})
+	}
+}
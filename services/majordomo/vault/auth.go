@@ -0,0 +1,146 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// tokenLease is a Vault token along with its lifetime.
+type tokenLease struct {
+	token     string
+	ttl       time.Duration
+	renewable bool
+}
+
+// authenticator obtains a token with which to access Vault.
+type authenticator interface {
+	// login obtains a token.  It is called at startup, and again if the
+	// current token can no longer be renewed.
+	login(ctx context.Context, s *Service) (*tokenLease, error)
+	// canRelogin returns true if login can obtain a fresh token.
+	canRelogin() bool
+}
+
+// tokenAuthenticator uses a pre-issued token.
+type tokenAuthenticator struct {
+	token string
+}
+
+// login checks the supplied token and obtains its lifetime.
+func (a *tokenAuthenticator) login(ctx context.Context, s *Service) (*tokenLease, error) {
+	resp := &secretResponse{}
+	if err := s.callWithToken(ctx, a.token, http.MethodGet, "auth/token/lookup-self", nil, resp); err != nil {
+		return nil, errors.Wrap(err, "failed to look up token")
+	}
+	lease := &tokenLease{token: a.token}
+	if ttl, isNumber := resp.Data["ttl"].(float64); isNumber {
+		lease.ttl = time.Duration(ttl) * time.Second
+	}
+	if renewable, isBool := resp.Data["renewable"].(bool); isBool {
+		lease.renewable = renewable
+	}
+	if lease.ttl > 0 && !lease.renewable {
+		log.Warn().Dur("ttl", lease.ttl).Msg("Vault token is not renewable; secrets will become unavailable when it expires")
+	}
+	return lease, nil
+}
+
+// canRelogin returns false, as a pre-issued token cannot be replaced.
+func (a *tokenAuthenticator) canRelogin() bool {
+	return false
+}
+
+// appRoleAuthenticator logs in with the AppRole method.
+type appRoleAuthenticator struct {
+	mount    string
+	roleID   string
+	secretID string
+}
+
+// login obtains a new token from the AppRole method.
+func (a *appRoleAuthenticator) login(ctx context.Context, s *Service) (*tokenLease, error) {
+	body := map[string]string{
+		"role_id":   a.roleID,
+		"secret_id": a.secretID,
+	}
+	resp := &authResponse{}
+	if err := s.callWithToken(ctx, "", http.MethodPost, fmt.Sprintf("auth/%s/login", a.mount), body, resp); err != nil {
+		return nil, errors.Wrap(err, "failed to log in with AppRole")
+	}
+	if resp.Auth == nil || resp.Auth.ClientToken == "" {
+		return nil, errors.New("no token in AppRole login response")
+	}
+	return &tokenLease{
+		token:     resp.Auth.ClientToken,
+		ttl:       time.Duration(resp.Auth.LeaseDuration) * time.Second,
+		renewable: resp.Auth.Renewable,
+	}, nil
+}
+
+// canRelogin returns true, as AppRole can always issue a new token.
+func (a *appRoleAuthenticator) canRelogin() bool {
+	return true
+}
+
+// kubernetesAuthenticator logs in with the Kubernetes method, using the
+// service account token of the pod in which Dirk is running.
+type kubernetesAuthenticator struct {
+	mount     string
+	role      string
+	tokenPath string
+}
+
+// login obtains a new token from the Kubernetes method.
+// The service account token is read on each login, as Kubernetes rotates
+// projected service account tokens.
+func (a *kubernetesAuthenticator) login(ctx context.Context, s *Service) (*tokenLease, error) {
+	jwt, err := ioutil.ReadFile(a.tokenPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read Kubernetes service account token")
+	}
+	if len(strings.TrimSpace(string(jwt))) == 0 {
+		return nil, fmt.Errorf("Kubernetes service account token %s is empty", a.tokenPath)
+	}
+
+	body := map[string]string{
+		"role": a.role,
+		"jwt":  strings.TrimSpace(string(jwt)),
+	}
+	resp := &authResponse{}
+	if err := s.callWithToken(ctx, "", http.MethodPost, fmt.Sprintf("auth/%s/login", a.mount), body, resp); err != nil {
+		return nil, errors.Wrap(err, "failed to log in with Kubernetes")
+	}
+	if resp.Auth == nil || resp.Auth.ClientToken == "" {
+		return nil, errors.New("no token in Kubernetes login response")
+	}
+	return &tokenLease{
+		token:     resp.Auth.ClientToken,
+		ttl:       time.Duration(resp.Auth.LeaseDuration) * time.Second,
+		renewable: resp.Auth.Renewable,
+	}, nil
+}
+
+// canRelogin returns true, as the Kubernetes method can issue a new token
+// for as long as the service account token is valid.
+func (a *kubernetesAuthenticator) canRelogin() bool {
+	return true
+}
@@ -0,0 +1,189 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"crypto/x509"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+type parameters struct {
+	logLevel     zerolog.Level
+	address      string
+	namespace    string
+	token        string
+	roleID       string
+	secretID     string
+	appRoleMount string
+	k8sRole      string
+	k8sMount     string
+	k8sTokenPath string
+	caCert       []byte
+	timeout      time.Duration
+}
+
+// Parameter is the interface for service parameters.
+type Parameter interface {
+	apply(*parameters)
+}
+
+type parameterFunc func(*parameters)
+
+func (f parameterFunc) apply(p *parameters) {
+	f(p)
+}
+
+// WithLogLevel sets the log level for the module.
+func WithLogLevel(logLevel zerolog.Level) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.logLevel = logLevel
+	})
+}
+
+// WithAddress sets the address of the Vault server, for example https://vault.example.com:8200/.
+func WithAddress(address string) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.address = address
+	})
+}
+
+// WithNamespace sets the Vault namespace in which secrets are held.
+func WithNamespace(namespace string) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.namespace = namespace
+	})
+}
+
+// WithToken sets the token used to authenticate with Vault.
+func WithToken(token string) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.token = token
+	})
+}
+
+// WithAppRole sets the role ID and secret ID used to authenticate with Vault's AppRole method.
+func WithAppRole(roleID string, secretID string) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.roleID = roleID
+		p.secretID = secretID
+	})
+}
+
+// WithAppRoleMount sets the path at which the AppRole authentication method is mounted.
+func WithAppRoleMount(mount string) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.appRoleMount = mount
+	})
+}
+
+// WithKubernetesRole sets the Vault role used to authenticate with Vault's Kubernetes method.
+func WithKubernetesRole(role string) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.k8sRole = role
+	})
+}
+
+// WithKubernetesMount sets the path at which the Kubernetes authentication method is mounted.
+func WithKubernetesMount(mount string) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.k8sMount = mount
+	})
+}
+
+// WithKubernetesTokenPath sets the path of the service account token used with the Kubernetes authentication method.
+func WithKubernetesTokenPath(path string) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.k8sTokenPath = path
+	})
+}
+
+// WithCACert sets the certificate authority used to verify the Vault server's certificate, in PEM format.
+// If not set the system certificate authorities are used.
+func WithCACert(caCert []byte) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.caCert = caCert
+	})
+}
+
+// WithTimeout sets the timeout for requests to Vault.
+func WithTimeout(timeout time.Duration) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.timeout = timeout
+	})
+}
+
+// parseAndCheckParameters parses and checks parameters to ensure that mandatory parameters are present and correct.
+func parseAndCheckParameters(params ...Parameter) (*parameters, error) {
+	parameters := parameters{
+		logLevel:     zerolog.GlobalLevel(),
+		appRoleMount: "approle",
+		k8sMount:     "kubernetes",
+		k8sTokenPath: "/var/run/secrets/kubernetes.io/serviceaccount/token",
+		timeout:      10 * time.Second,
+	}
+	for _, p := range params {
+		if params != nil {
+			p.apply(&parameters)
+		}
+	}
+
+	if parameters.address == "" {
+		return nil, errors.New("no address specified")
+	}
+	address, err := url.Parse(parameters.address)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid address")
+	}
+	if address.Scheme != "http" && address.Scheme != "https" {
+		return nil, errors.New("address must be http or https")
+	}
+	methods := 0
+	for _, configured := range []bool{parameters.token != "", parameters.roleID != "", parameters.k8sRole != ""} {
+		if configured {
+			methods++
+		}
+	}
+	if methods == 0 {
+		return nil, errors.New("no token, AppRole or Kubernetes role specified")
+	}
+	if methods > 1 {
+		return nil, errors.New("only one of token, AppRole and Kubernetes role can be specified")
+	}
+	if parameters.roleID != "" && parameters.secretID == "" {
+		return nil, errors.New("no AppRole secret ID specified")
+	}
+	if parameters.appRoleMount == "" {
+		return nil, errors.New("no AppRole mount specified")
+	}
+	if parameters.k8sMount == "" {
+		return nil, errors.New("no Kubernetes mount specified")
+	}
+	if parameters.k8sTokenPath == "" {
+		return nil, errors.New("no Kubernetes token path specified")
+	}
+	if len(parameters.caCert) > 0 {
+		if !x509.NewCertPool().AppendCertsFromPEM(parameters.caCert) {
+			return nil, errors.New("invalid CA certificate")
+		}
+	}
+	if parameters.timeout <= 0 {
+		return nil, errors.New("timeout must be positive")
+	}
+
+	return &parameters, nil
+}
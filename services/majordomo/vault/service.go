@@ -0,0 +1,330 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	zerologger "github.com/rs/zerolog/log"
+	"github.com/wealdtech/go-majordomo"
+)
+
+// Service returns values from a HashiCorp Vault server.
+// This service handles URLs with the scheme "vault".
+// A full URL is of the form "vault:///mount/data/path#key", where the path
+// is the full API path of the secret and the optional fragment selects a
+// single key from the secret's data.  If no key is supplied the secret must
+// contain exactly one key.
+// Both KV version 1 and version 2 secrets engines are supported.
+type Service struct {
+	address   string
+	namespace string
+	client    *http.Client
+	auth      authenticator
+
+	tokenMu sync.RWMutex
+	token   string
+}
+
+// module-wide log.
+var log zerolog.Logger
+
+// New creates a new Vault confidant.
+// The confidant authenticates with Vault before returning, so that an
+// incorrect address or credentials are reported at startup.
+func New(ctx context.Context, params ...Parameter) (*Service, error) {
+	parameters, err := parseAndCheckParameters(params...)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem with parameters")
+	}
+
+	// Set logging.
+	log = zerologger.With().Str("service", "confidant").Str("impl", "vault").Logger()
+	if parameters.logLevel != log.GetLevel() {
+		log = log.Level(parameters.logLevel)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if len(parameters.caCert) > 0 {
+		certPool := x509.NewCertPool()
+		certPool.AppendCertsFromPEM(parameters.caCert)
+		transport.TLSClientConfig = &tls.Config{
+			RootCAs:    certPool,
+			MinVersion: tls.VersionTLS12,
+		}
+	}
+
+	s := &Service{
+		address:   strings.TrimSuffix(parameters.address, "/"),
+		namespace: parameters.namespace,
+		client: &http.Client{
+			Timeout:   parameters.timeout,
+			Transport: transport,
+		},
+	}
+	switch {
+	case parameters.token != "":
+		s.auth = &tokenAuthenticator{token: parameters.token}
+	case parameters.k8sRole != "":
+		s.auth = &kubernetesAuthenticator{
+			mount:     parameters.k8sMount,
+			role:      parameters.k8sRole,
+			tokenPath: parameters.k8sTokenPath,
+		}
+	default:
+		s.auth = &appRoleAuthenticator{
+			mount:    parameters.appRoleMount,
+			roleID:   parameters.roleID,
+			secretID: parameters.secretID,
+		}
+	}
+
+	lease, err := s.auth.login(ctx, s)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to authenticate with Vault")
+	}
+	s.setToken(lease.token)
+	log.Trace().Dur("ttl", lease.ttl).Bool("renewable", lease.renewable).Msg("Authenticated with Vault")
+
+	if lease.ttl > 0 && (lease.renewable || s.auth.canRelogin()) {
+		go s.maintainToken(ctx, lease)
+	}
+
+	return s, nil
+}
+
+// SupportedURLSchemes provides the list of schemes supported by this confidant.
+func (s *Service) SupportedURLSchemes(ctx context.Context) ([]string, error) {
+	return []string{"vault"}, nil
+}
+
+// Fetch fetches a value given its key.
+func (s *Service) Fetch(ctx context.Context, url *url.URL) ([]byte, error) {
+	if url.Host != "" {
+		return nil, errors.New("vault URLs must not contain a host; the address is supplied in configuration")
+	}
+	path := strings.Trim(url.Path, "/")
+	if path == "" {
+		return nil, errors.New("no secret specified")
+	}
+
+	resp := &secretResponse{}
+	if err := s.call(ctx, http.MethodGet, path, nil, resp); err != nil {
+		return nil, err
+	}
+	if resp.Data == nil {
+		return nil, majordomo.ErrNotFound
+	}
+
+	// KV version 2 nests the secret's data inside a further data object,
+	// alongside its metadata.
+	data := resp.Data
+	if nested, isNested := data["data"].(map[string]interface{}); isNested {
+		if _, hasMetadata := data["metadata"]; hasMetadata {
+			data = nested
+		}
+	}
+
+	key := url.Fragment
+	if key == "" {
+		if len(data) != 1 {
+			return nil, fmt.Errorf("secret at %s has %d keys; a key must be specified", path, len(data))
+		}
+		for k := range data {
+			key = k
+		}
+	}
+	value, exists := data[key]
+	if !exists {
+		return nil, fmt.Errorf("secret at %s has no key %q", path, key)
+	}
+	log.Debug().Str("path", path).Str("key", key).Msg("Resolved Vault secret")
+
+	if str, isString := value.(string); isString {
+		return []byte(str), nil
+	}
+	res, err := json.Marshal(value)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal secret value")
+	}
+	return res, nil
+}
+
+type secretResponse struct {
+	Data map[string]interface{} `json:"data"`
+}
+
+type authResponse struct {
+	Auth *struct {
+		ClientToken   string `json:"client_token"`
+		LeaseDuration int64  `json:"lease_duration"`
+		Renewable     bool   `json:"renewable"`
+	} `json:"auth"`
+}
+
+type errorResponse struct {
+	Errors []string `json:"errors"`
+}
+
+// call makes a call to the Vault API with the current token, decoding the response in to res if supplied.
+func (s *Service) call(ctx context.Context, method string, path string, body interface{}, res interface{}) error {
+	return s.callWithToken(ctx, s.currentToken(), method, path, body, res)
+}
+
+// callWithToken makes a call to the Vault API with the given token, decoding the response in to res if supplied.
+func (s *Service) callWithToken(ctx context.Context, token string, method string, path string, body interface{}, res interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal request")
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, fmt.Sprintf("%s/v1/%s", s.address, path), reqBody)
+	if err != nil {
+		return errors.Wrap(err, "failed to create request")
+	}
+	if token != "" {
+		req.Header.Set("X-Vault-Token", token)
+	}
+	if s.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", s.namespace)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "failed to call Vault")
+	}
+	defer resp.Body.Close()
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Wrap(err, "failed to read Vault response")
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		errResp := &errorResponse{}
+		if json.Unmarshal(respBody, errResp) == nil {
+			for _, msg := range errResp.Errors {
+				if strings.Contains(msg, "no handler for route") {
+					return fmt.Errorf("no secrets engine mounted at %s", path)
+				}
+			}
+		}
+		return majordomo.ErrNotFound
+	case resp.StatusCode == http.StatusForbidden:
+		return fmt.Errorf("permission denied accessing %s", path)
+	case resp.StatusCode >= 300:
+		errResp := &errorResponse{}
+		if json.Unmarshal(respBody, errResp) == nil && len(errResp.Errors) > 0 {
+			return fmt.Errorf("Vault returned %d for %s: %s", resp.StatusCode, path, strings.Join(errResp.Errors, "; "))
+		}
+		return fmt.Errorf("Vault returned %d for %s", resp.StatusCode, path)
+	}
+
+	if res != nil && len(respBody) > 0 {
+		if err := json.Unmarshal(respBody, res); err != nil {
+			return errors.Wrap(err, "failed to parse Vault response")
+		}
+	}
+	return nil
+}
+
+func (s *Service) currentToken() string {
+	s.tokenMu.RLock()
+	defer s.tokenMu.RUnlock()
+	return s.token
+}
+
+func (s *Service) setToken(token string) {
+	s.tokenMu.Lock()
+	s.token = token
+	s.tokenMu.Unlock()
+}
+
+// minRenewInterval is the shortest time between attempts to renew the token.
+var minRenewInterval = 5 * time.Second
+
+// maintainToken keeps the token valid for the lifetime of the context,
+// renewing it at half of its TTL and logging in again if renewal fails.
+func (s *Service) maintainToken(ctx context.Context, lease *tokenLease) {
+	for {
+		interval := lease.ttl / 2
+		if interval < minRenewInterval {
+			interval = minRenewInterval
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+
+		if lease.renewable {
+			renewed, err := s.renewToken(ctx)
+			if err == nil {
+				log.Trace().Dur("ttl", renewed.ttl).Msg("Renewed Vault token")
+				lease = renewed
+				continue
+			}
+			log.Warn().Err(err).Msg("Failed to renew Vault token")
+		}
+
+		if !s.auth.canRelogin() {
+			log.Error().Msg("Vault token can no longer be renewed; secrets will become unavailable when it expires")
+			return
+		}
+		relogin, err := s.auth.login(ctx, s)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to obtain new Vault token; secrets may become unavailable")
+			continue
+		}
+		s.setToken(relogin.token)
+		log.Trace().Dur("ttl", relogin.ttl).Msg("Obtained new Vault token")
+		lease = relogin
+	}
+}
+
+func (s *Service) renewToken(ctx context.Context) (*tokenLease, error) {
+	resp := &authResponse{}
+	if err := s.call(ctx, http.MethodPost, "auth/token/renew-self", struct{}{}, resp); err != nil {
+		return nil, err
+	}
+	if resp.Auth == nil {
+		return nil, errors.New("no auth information in renewal response")
+	}
+	return &tokenLease{
+		token:     s.currentToken(),
+		ttl:       time.Duration(resp.Auth.LeaseDuration) * time.Second,
+		renewable: resp.Auth.Renewable,
+	}, nil
+}
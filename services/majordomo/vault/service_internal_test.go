@@ -0,0 +1,62 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenRenewal(t *testing.T) {
+	defer func(interval time.Duration) { minRenewInterval = interval }(minRenewInterval)
+	minRenewInterval = 10 * time.Millisecond
+
+	var renewals int32
+	var logins int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/auth/approle/login":
+			atomic.AddInt32(&logins, 1)
+			_, _ = w.Write([]byte(`{"auth":{"client_token":"token","lease_duration":1,"renewable":true}}`))
+		case "/v1/auth/token/renew-self":
+			// Succeed the first time, then fail to force a new login.
+			if atomic.AddInt32(&renewals, 1) > 1 {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			_, _ = w.Write([]byte(`{"auth":{"client_token":"token","lease_duration":0,"renewable":true}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	_, err := New(ctx,
+		WithAddress(server.URL),
+		WithAppRole("role", "secret"),
+	)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt32(&renewals) >= 2 && atomic.LoadInt32(&logins) >= 2
+	}, 5*time.Second, 10*time.Millisecond)
+}
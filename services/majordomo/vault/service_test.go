@@ -0,0 +1,357 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/attestantio/dirk/services/majordomo/vault"
+	"github.com/stretchr/testify/require"
+	"github.com/wealdtech/go-majordomo"
+)
+
+// mockVault is a minimal Vault server holding a single KV v2 mount at "secret".
+type mockVault struct {
+	mu         sync.Mutex
+	token      string
+	namespaces []string
+}
+
+func (m *mockVault) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	m.namespaces = append(m.namespaces, r.Header.Get("X-Vault-Namespace"))
+	m.mu.Unlock()
+
+	if r.URL.Path == "/v1/auth/approle/login" {
+		body := make(map[string]string)
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body["role_id"] != "role" || body["secret_id"] != "secret" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"errors":["invalid role or secret ID"]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"auth":{"client_token":"approle-token","lease_duration":3600,"renewable":true}}`))
+		return
+	}
+
+	if r.URL.Path == "/v1/auth/kubernetes/login" {
+		body := make(map[string]string)
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body["role"] != "dirk" || body["jwt"] != "service-account-jwt" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"errors":["invalid role or service account token"]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"auth":{"client_token":"kubernetes-token","lease_duration":3600,"renewable":true}}`))
+		return
+	}
+
+	if r.Header.Get("X-Vault-Token") != m.token && r.Header.Get("X-Vault-Token") != "approle-token" && r.Header.Get("X-Vault-Token") != "kubernetes-token" {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"errors":["permission denied"]}`))
+		return
+	}
+
+	switch {
+	case r.URL.Path == "/v1/auth/token/lookup-self":
+		_, _ = w.Write([]byte(`{"data":{"ttl":0,"renewable":false}}`))
+	case r.URL.Path == "/v1/secret/data/dirk/wallet-passphrase":
+		_, _ = w.Write([]byte(`{"data":{"data":{"value":"secret passphrase","other":"x"},"metadata":{"version":1}}}`))
+	case r.URL.Path == "/v1/secret/data/dirk/single":
+		_, _ = w.Write([]byte(`{"data":{"data":{"value":"single value"},"metadata":{"version":1}}}`))
+	case r.URL.Path == "/v1/secret/data/dirk/number":
+		_, _ = w.Write([]byte(`{"data":{"data":{"value":12345},"metadata":{"version":1}}}`))
+	case strings.HasPrefix(r.URL.Path, "/v1/secret/"):
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"errors":[]}`))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"errors":["no handler for route \"` + strings.TrimPrefix(r.URL.Path, "/v1/") + `\". route entry not found."]}`))
+	}
+}
+
+func TestNew(t *testing.T) {
+	ctx := context.Background()
+	server := httptest.NewServer(&mockVault{token: "token"})
+	defer server.Close()
+
+	base, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(base)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(base, "token"), []byte("service-account-jwt\n"), 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(base, "empty"), []byte("\n"), 0600))
+
+	tests := []struct {
+		name   string
+		params []vault.Parameter
+		err    string
+	}{
+		{
+			name: "AddressMissing",
+			params: []vault.Parameter{
+				vault.WithToken("token"),
+			},
+			err: "problem with parameters: no address specified",
+		},
+		{
+			name: "AddressBadScheme",
+			params: []vault.Parameter{
+				vault.WithAddress("ftp://localhost"),
+				vault.WithToken("token"),
+			},
+			err: "problem with parameters: address must be http or https",
+		},
+		{
+			name: "AuthMissing",
+			params: []vault.Parameter{
+				vault.WithAddress(server.URL),
+			},
+			err: "problem with parameters: no token, AppRole or Kubernetes role specified",
+		},
+		{
+			name: "AuthBoth",
+			params: []vault.Parameter{
+				vault.WithAddress(server.URL),
+				vault.WithToken("token"),
+				vault.WithAppRole("role", "secret"),
+			},
+			err: "problem with parameters: only one of token, AppRole and Kubernetes role can be specified",
+		},
+		{
+			name: "SecretIDMissing",
+			params: []vault.Parameter{
+				vault.WithAddress(server.URL),
+				vault.WithAppRole("role", ""),
+			},
+			err: "problem with parameters: no AppRole secret ID specified",
+		},
+		{
+			name: "AuthTokenAndKubernetes",
+			params: []vault.Parameter{
+				vault.WithAddress(server.URL),
+				vault.WithToken("token"),
+				vault.WithKubernetesRole("dirk"),
+			},
+			err: "problem with parameters: only one of token, AppRole and Kubernetes role can be specified",
+		},
+		{
+			name: "KubernetesMountMissing",
+			params: []vault.Parameter{
+				vault.WithAddress(server.URL),
+				vault.WithKubernetesRole("dirk"),
+				vault.WithKubernetesMount(""),
+			},
+			err: "problem with parameters: no Kubernetes mount specified",
+		},
+		{
+			name: "CACertInvalid",
+			params: []vault.Parameter{
+				vault.WithAddress(server.URL),
+				vault.WithToken("token"),
+				vault.WithCACert([]byte("bad")),
+			},
+			err: "problem with parameters: invalid CA certificate",
+		},
+		{
+			name: "TokenBad",
+			params: []vault.Parameter{
+				vault.WithAddress(server.URL),
+				vault.WithToken("bad"),
+			},
+			err: "failed to authenticate with Vault: failed to look up token: permission denied accessing auth/token/lookup-self",
+		},
+		{
+			name: "AppRoleBad",
+			params: []vault.Parameter{
+				vault.WithAddress(server.URL),
+				vault.WithAppRole("role", "bad"),
+			},
+			err: "failed to authenticate with Vault: failed to log in with AppRole: Vault returned 400 for auth/approle/login: invalid role or secret ID",
+		},
+		{
+			name: "AppRoleMountBad",
+			params: []vault.Parameter{
+				vault.WithAddress(server.URL),
+				vault.WithAppRole("role", "secret"),
+				vault.WithAppRoleMount("other"),
+			},
+			err: "failed to authenticate with Vault: failed to log in with AppRole: permission denied accessing auth/other/login",
+		},
+		{
+			name: "KubernetesTokenMissing",
+			params: []vault.Parameter{
+				vault.WithAddress(server.URL),
+				vault.WithKubernetesRole("dirk"),
+				vault.WithKubernetesTokenPath(filepath.Join(base, "missing")),
+			},
+			err: fmt.Sprintf("failed to authenticate with Vault: failed to read Kubernetes service account token: open %s: no such file or directory", filepath.Join(base, "missing")),
+		},
+		{
+			name: "KubernetesTokenEmpty",
+			params: []vault.Parameter{
+				vault.WithAddress(server.URL),
+				vault.WithKubernetesRole("dirk"),
+				vault.WithKubernetesTokenPath(filepath.Join(base, "empty")),
+			},
+			err: fmt.Sprintf("failed to authenticate with Vault: Kubernetes service account token %s is empty", filepath.Join(base, "empty")),
+		},
+		{
+			name: "KubernetesRoleBad",
+			params: []vault.Parameter{
+				vault.WithAddress(server.URL),
+				vault.WithKubernetesRole("other"),
+				vault.WithKubernetesTokenPath(filepath.Join(base, "token")),
+			},
+			err: "failed to authenticate with Vault: failed to log in with Kubernetes: Vault returned 400 for auth/kubernetes/login: invalid role or service account token",
+		},
+		{
+			name: "Token",
+			params: []vault.Parameter{
+				vault.WithAddress(server.URL),
+				vault.WithToken("token"),
+			},
+		},
+		{
+			name: "AppRole",
+			params: []vault.Parameter{
+				vault.WithAddress(server.URL + "/"),
+				vault.WithAppRole("role", "secret"),
+			},
+		},
+		{
+			name: "Kubernetes",
+			params: []vault.Parameter{
+				vault.WithAddress(server.URL),
+				vault.WithKubernetesRole("dirk"),
+				vault.WithKubernetesTokenPath(filepath.Join(base, "token")),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := vault.New(ctx, test.params...)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestFetch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	mock := &mockVault{token: "token"}
+	server := httptest.NewServer(mock)
+	defer server.Close()
+
+	s, err := vault.New(ctx,
+		vault.WithAddress(server.URL),
+		vault.WithAppRole("role", "secret"),
+		vault.WithNamespace("dirk"),
+	)
+	require.NoError(t, err)
+
+	schemes, err := s.SupportedURLSchemes(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"vault"}, schemes)
+
+	tests := []struct {
+		name  string
+		url   string
+		value []byte
+		err   string
+		errIs error
+	}{
+		{
+			name: "HostPresent",
+			url:  "vault://host/secret/data/dirk/wallet-passphrase#value",
+			err:  "vault URLs must not contain a host; the address is supplied in configuration",
+		},
+		{
+			name: "PathMissing",
+			url:  "vault:///",
+			err:  "no secret specified",
+		},
+		{
+			name:  "Value",
+			url:   "vault:///secret/data/dirk/wallet-passphrase#value",
+			value: []byte("secret passphrase"),
+		},
+		{
+			name: "KeyMissing",
+			url:  "vault:///secret/data/dirk/wallet-passphrase#missing",
+			err:  `secret at secret/data/dirk/wallet-passphrase has no key "missing"`,
+		},
+		{
+			name: "KeyAmbiguous",
+			url:  "vault:///secret/data/dirk/wallet-passphrase",
+			err:  "secret at secret/data/dirk/wallet-passphrase has 2 keys; a key must be specified",
+		},
+		{
+			name:  "SingleKey",
+			url:   "vault:///secret/data/dirk/single",
+			value: []byte("single value"),
+		},
+		{
+			name:  "NonString",
+			url:   "vault:///secret/data/dirk/number#value",
+			value: []byte("12345"),
+		},
+		{
+			name:  "SecretMissing",
+			url:   "vault:///secret/data/dirk/missing#value",
+			errIs: majordomo.ErrNotFound,
+		},
+		{
+			name: "MountMissing",
+			url:  "vault:///kv/data/dirk/wallet-passphrase#value",
+			err:  "no secrets engine mounted at kv/data/dirk/wallet-passphrase",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			u, err := url.Parse(test.url)
+			require.NoError(t, err)
+			value, err := s.Fetch(ctx, u)
+			switch {
+			case test.err != "":
+				require.EqualError(t, err, test.err)
+			case test.errIs != nil:
+				require.ErrorIs(t, err, test.errIs)
+			default:
+				require.NoError(t, err)
+				require.Equal(t, test.value, value)
+			}
+		})
+	}
+
+	mock.mu.Lock()
+	defer mock.mu.Unlock()
+	for _, namespace := range mock.namespaces {
+		require.Equal(t, "dirk", namespace)
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func (s *Service) setupAPIMetrics() error {
+	s.certificateReloadAttempts = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "dirk",
+		Subsystem: "certificates",
+		Name:      "reload_attempts_total",
+		Help:      "The number of attempts to reload the server certificates.",
+	})
+	if err := prometheus.Register(s.certificateReloadAttempts); err != nil {
+		return err
+	}
+
+	s.certificateReloads = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "dirk",
+		Subsystem: "certificates",
+		Name:      "reloads_total",
+		Help:      "The number of completed server certificate reloads.",
+	}, []string{"result"})
+	if err := prometheus.Register(s.certificateReloads); err != nil {
+		return err
+	}
+
+	s.certificateLastReload = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "dirk",
+		Subsystem: "certificates",
+		Name:      "last_reload_time_secs",
+		Help:      "The timestamp of the last successful server certificate reload.",
+	})
+	if err := prometheus.Register(s.certificateLastReload); err != nil {
+		return err
+	}
+
+	s.certificateExpiry = newCertificateExpiryCollector()
+	if err := prometheus.Register(s.certificateExpiry); err != nil {
+		return err
+	}
+
+	s.apiRequestPanics = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "dirk",
+		Subsystem: "api",
+		Name:      "request_panics_total",
+		Help:      "The number of API requests that panicked.",
+	}, []string{"rpc"})
+	if err := prometheus.Register(s.apiRequestPanics); err != nil {
+		return err
+	}
+
+	s.apiRateLimitedRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "dirk",
+		Subsystem: "api",
+		Name:      "rate_limited_requests_total",
+		Help:      "The number of API requests rejected for exceeding the client's rate limit.",
+	}, []string{"client"})
+	return prometheus.Register(s.apiRateLimitedRequests)
+}
+
+// CertificateReloadCompleted is called when an attempt to reload the server certificates has completed.
+func (s *Service) CertificateReloadCompleted(succeeded bool) {
+	s.certificateReloadAttempts.Inc()
+	if !succeeded {
+		s.certificateReloads.WithLabelValues("failed").Inc()
+		return
+	}
+	s.certificateReloads.WithLabelValues("succeeded").Inc()
+	s.certificateLastReload.Set(float64(time.Now().Unix()))
+}
+
+// RequestPanicked is called when a request handler panics.
+func (s *Service) RequestPanicked(rpc string) {
+	s.apiRequestPanics.WithLabelValues(rpc).Inc()
+}
+
+// RequestRateLimited is called when a request is rejected for exceeding the client's rate limit.
+func (s *Service) RequestRateLimited(client string) {
+	s.apiRateLimitedRequests.WithLabelValues(client).Inc()
+}
+
+// CertificateExpiry is called when a certificate is loaded, with the time at which it expires.
+func (s *Service) CertificateExpiry(certificate string, notAfter time.Time) {
+	s.certificateExpiry.set(certificate, notAfter)
+}
+
+// certificateExpiryCollector reports the time remaining until each certificate
+// expires, calculated when metrics are gathered so that it does not go stale
+// between certificate loads.
+type certificateExpiryCollector struct {
+	desc      *prometheus.Desc
+	mu        sync.RWMutex
+	notAfters map[string]time.Time
+}
+
+func newCertificateExpiryCollector() *certificateExpiryCollector {
+	return &certificateExpiryCollector{
+		desc: prometheus.NewDesc(prometheus.BuildFQName("dirk", "certificate", "expiry_seconds"),
+			"The number of seconds until the certificate expires; negative if it has expired.",
+			[]string{"certificate"},
+			nil,
+		),
+		notAfters: make(map[string]time.Time),
+	}
+}
+
+func (c *certificateExpiryCollector) set(certificate string, notAfter time.Time) {
+	c.mu.Lock()
+	c.notAfters[certificate] = notAfter
+	c.mu.Unlock()
+}
+
+// Describe implements prometheus.Collector.
+func (c *certificateExpiryCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+// Collect implements prometheus.Collector.
+func (c *certificateExpiryCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for certificate, notAfter := range c.notAfters {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, time.Until(notAfter).Seconds(), certificate)
+	}
+}
@@ -0,0 +1,33 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func (s *Service) setupBuildMetrics() error {
+	s.buildInfo = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "dirk",
+		Name:      "build_info",
+		Help:      "Build information for this instance; always 1.",
+	}, []string{"version", "goversion", "commit"})
+	return prometheus.Register(s.buildInfo)
+}
+
+// BuildInfo is called with details of the build of the running binary.
+func (s *Service) BuildInfo(version string, goVersion string, commit string) {
+	s.buildInfo.Reset()
+	s.buildInfo.WithLabelValues(version, goVersion, commit).Set(1)
+}
@@ -0,0 +1,122 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// seriesActivity tracks the activity of a single series in a capped metric.
+type seriesActivity struct {
+	labelValues []string
+	count       uint64
+	// lastSeen is a logical clock used to break ties between series with equal counts.
+	lastSeen uint64
+}
+
+// cappedCounterVec is a counter vector with a limit on the number of series it
+// holds.  When a new series would take it over the limit the series with the
+// lowest activity is dropped.  It is intended for metrics with per-validator
+// labels, where the number of series grows with the number of validators.
+type cappedCounterVec struct {
+	name      string
+	vec       *prometheus.CounterVec
+	dropped   prometheus.Counter
+	maxSeries int
+
+	mu       sync.Mutex
+	clock    uint64
+	activity map[string]*seriesActivity
+}
+
+func (s *Service) setupCardinalityMetrics() error {
+	s.seriesDropped = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "dirk",
+		Subsystem: "metrics",
+		Name:      "series_dropped_total",
+		Help:      "The number of series dropped due to the per-metric series limit.",
+	}, []string{"metric"})
+	return prometheus.Register(s.seriesDropped)
+}
+
+// newCappedCounterVec wraps a counter vector with the service's series limit.
+func (s *Service) newCappedCounterVec(name string, vec *prometheus.CounterVec) *cappedCounterVec {
+	return newCappedCounterVec(name, vec, s.seriesDropped.WithLabelValues(name), s.maxSeriesPerMetric)
+}
+
+func newCappedCounterVec(name string, vec *prometheus.CounterVec, dropped prometheus.Counter, maxSeries int) *cappedCounterVec {
+	return &cappedCounterVec{
+		name:      name,
+		vec:       vec,
+		dropped:   dropped,
+		maxSeries: maxSeries,
+		activity:  make(map[string]*seriesActivity),
+	}
+}
+
+// Inc increments the series with the given label values.
+func (c *cappedCounterVec) Inc(labelValues ...string) {
+	if c.maxSeries == 0 {
+		// No limit, so no need to track activity.
+		c.vec.WithLabelValues(labelValues...).Inc()
+		return
+	}
+
+	key := strings.Join(labelValues, "\xff")
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.clock++
+	activity, exists := c.activity[key]
+	if !exists {
+		if len(c.activity) >= c.maxSeries {
+			c.dropLowestActivity()
+		}
+		activity = &seriesActivity{
+			labelValues: append([]string(nil), labelValues...),
+		}
+		c.activity[key] = activity
+	}
+	activity.count++
+	activity.lastSeen = c.clock
+	c.vec.WithLabelValues(labelValues...).Inc()
+}
+
+// dropLowestActivity drops the series with the lowest activity, preferring
+// the least recently seen series if there is a tie.
+// This is linear in the number of series, but only runs when a new series
+// arrives at the limit.
+// Must be called with the lock held.
+func (c *cappedCounterVec) dropLowestActivity() {
+	var lowestKey string
+	var lowest *seriesActivity
+	for key, activity := range c.activity {
+		if lowest == nil ||
+			activity.count < lowest.count ||
+			(activity.count == lowest.count && activity.lastSeen < lowest.lastSeen) {
+			lowestKey = key
+			lowest = activity
+		}
+	}
+	if lowest == nil {
+		return
+	}
+	c.vec.DeleteLabelValues(lowest.labelValues...)
+	delete(c.activity, lowestKey)
+	c.dropped.Inc()
+	log.Trace().Str("metric", c.name).Strs("labels", lowest.labelValues).Msg("Dropped series due to series limit")
+}
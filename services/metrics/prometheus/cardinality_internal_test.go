@@ -0,0 +1,79 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCappedCounterVec(maxSeries int) (*cappedCounterVec, prometheus.Counter) {
+	vec := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "test_total",
+	}, []string{"validator"})
+	dropped := prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "dropped_total",
+	})
+	return newCappedCounterVec("test", vec, dropped, maxSeries), dropped
+}
+
+func TestCappedCounterVecUnlimited(t *testing.T) {
+	c, dropped := newTestCappedCounterVec(0)
+	for _, validator := range []string{"a", "b", "c", "d", "e"} {
+		c.Inc(validator)
+	}
+	require.Equal(t, 5, testutil.CollectAndCount(c.vec))
+	require.Equal(t, float64(0), testutil.ToFloat64(dropped))
+	// No tracking when unlimited.
+	require.Len(t, c.activity, 0)
+}
+
+func TestCappedCounterVecDropsLowestActivity(t *testing.T) {
+	c, dropped := newTestCappedCounterVec(3)
+
+	// a is the busiest, then c, then b.
+	for i := 0; i < 3; i++ {
+		c.Inc("a")
+	}
+	c.Inc("b")
+	c.Inc("c")
+	c.Inc("c")
+	require.Equal(t, 3, testutil.CollectAndCount(c.vec))
+
+	// Adding d should drop b.
+	c.Inc("d")
+	require.Equal(t, 3, testutil.CollectAndCount(c.vec))
+	require.Equal(t, float64(1), testutil.ToFloat64(dropped))
+	require.Equal(t, float64(3), testutil.ToFloat64(c.vec.WithLabelValues("a")))
+	require.Equal(t, float64(2), testutil.ToFloat64(c.vec.WithLabelValues("c")))
+	require.Equal(t, float64(1), testutil.ToFloat64(c.vec.WithLabelValues("d")))
+	_, exists := c.activity["b"]
+	require.False(t, exists)
+
+	// Existing series do not cause drops.
+	c.Inc("a")
+	c.Inc("d")
+	require.Equal(t, float64(1), testutil.ToFloat64(dropped))
+
+	// c and d are now tied on activity; c was seen least recently so is dropped.
+	c.Inc("e")
+	require.Equal(t, float64(2), testutil.ToFloat64(dropped))
+	_, exists = c.activity["c"]
+	require.False(t, exists)
+	_, exists = c.activity["d"]
+	require.True(t, exists)
+}
@@ -0,0 +1,74 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func (s *Service) setupCheckerMetrics() error {
+	s.checkerChecks = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "dirk",
+		Subsystem: "checker",
+		Name:      "checks_total",
+		Help:      "The number of permission checks for operations.",
+	}, []string{"client", "operation", "result"})
+	if err := prometheus.Register(s.checkerChecks); err != nil {
+		return err
+	}
+	s.checkerChecksCapped = s.newCappedCounterVec("dirk_checker_checks_total", s.checkerChecks)
+
+	s.checkerDenials = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "dirk",
+		Subsystem: "checker",
+		Name:      "denials_total",
+		Help:      "The number of operations denied by permissions.",
+	}, []string{"client", "operation"})
+	if err := prometheus.Register(s.checkerDenials); err != nil {
+		return err
+	}
+	s.checkerDenialsCapped = s.newCappedCounterVec("dirk_checker_denials_total", s.checkerDenials)
+
+	s.checkerAddressDenials = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "dirk",
+		Subsystem: "checker",
+		Name:      "address_denials_total",
+		Help:      "The number of operations denied because the request came from an address not allowed for the client.",
+	}, []string{"client"})
+	if err := prometheus.Register(s.checkerAddressDenials); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// PermissionChecked is called when a client's permission for an operation has been checked.
+func (s *Service) PermissionChecked(client string, operation string, allowed bool) {
+	if allowed {
+		s.checkerChecksCapped.Inc(client, operation, "allowed")
+	} else {
+		s.checkerChecksCapped.Inc(client, operation, "denied")
+	}
+}
+
+// PermissionDenied is called when a client is denied permission for an operation.
+func (s *Service) PermissionDenied(client string, operation string) {
+	s.checkerDenialsCapped.Inc(client, operation)
+}
+
+// AddressDenied is called when a client is denied because its request
+// came from an address outside of its allowed IP ranges.
+func (s *Service) AddressDenied(client string) {
+	s.checkerAddressDenials.WithLabelValues(client).Inc()
+}
@@ -0,0 +1,109 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func (s *Service) setupFetcherMetrics() error {
+	s.fetcherRefreshes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "dirk",
+		Subsystem: "fetcher",
+		Name:      "refreshes_total",
+		Help:      "The number of completed scans of the account stores.",
+	}, []string{"result"})
+	if err := prometheus.Register(s.fetcherRefreshes); err != nil {
+		return err
+	}
+
+	s.fetcherAccountsAdded = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "dirk",
+		Subsystem: "fetcher",
+		Name:      "accounts_added_total",
+		Help:      "The number of accounts added by scans of the account stores.",
+	})
+	if err := prometheus.Register(s.fetcherAccountsAdded); err != nil {
+		return err
+	}
+
+	s.fetcherAccountsRemoved = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "dirk",
+		Subsystem: "fetcher",
+		Name:      "accounts_removed_total",
+		Help:      "The number of accounts removed by scans of the account stores.",
+	})
+	if err := prometheus.Register(s.fetcherAccountsRemoved); err != nil {
+		return err
+	}
+
+	s.fetcherRefreshErrors = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "dirk",
+		Subsystem: "fetcher",
+		Name:      "refresh_errors_total",
+		Help:      "The number of errors encountered by scans of the account stores.",
+	})
+	if err := prometheus.Register(s.fetcherRefreshErrors); err != nil {
+		return err
+	}
+
+	s.fetcherLastRefresh = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "dirk",
+		Subsystem: "fetcher",
+		Name:      "last_refresh_time_secs",
+		Help:      "The timestamp of the last successful scan of the account stores.",
+	})
+	if err := prometheus.Register(s.fetcherLastRefresh); err != nil {
+		return err
+	}
+
+	s.fetcherAccounts = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "dirk",
+		Subsystem: "fetcher",
+		Name:      "accounts",
+		Help:      "The number of accounts loaded from each wallet in each store.",
+	}, []string{"store", "wallet"})
+	return prometheus.Register(s.fetcherAccounts)
+}
+
+// FetcherRefreshCompleted is called when the fetcher has completed a scan of its stores.
+func (s *Service) FetcherRefreshCompleted(added int, removed int, failures int) {
+	s.fetcherAccountsAdded.Add(float64(added))
+	s.fetcherAccountsRemoved.Add(float64(removed))
+	if failures > 0 {
+		s.fetcherRefreshes.WithLabelValues("failed").Inc()
+		s.fetcherRefreshErrors.Add(float64(failures))
+		return
+	}
+	s.fetcherRefreshes.WithLabelValues("succeeded").Inc()
+	s.fetcherLastRefresh.Set(float64(time.Now().Unix()))
+}
+
+// FetcherAccounts is called with the number of accounts loaded from each wallet in each store.
+func (s *Service) FetcherAccounts(accounts map[string]map[string]int) {
+	// Reset so that wallets no longer present are not reported.
+	s.fetcherAccounts.Reset()
+	for store, wallets := range accounts {
+		if len(wallets) == 0 {
+			// Report empty stores, so that they can be alerted on.
+			s.fetcherAccounts.WithLabelValues(store, "").Set(0)
+			continue
+		}
+		for wallet, count := range wallets {
+			s.fetcherAccounts.WithLabelValues(store, wallet).Set(float64(count))
+		}
+	}
+}
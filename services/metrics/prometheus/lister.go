@@ -41,7 +41,17 @@ func (s *Service) setupListerMetrics() error {
 		Name:      "requests_total",
 		Help:      "The number of account list requests.",
 	}, []string{"result"})
-	return prometheus.Register(s.listerRequests)
+	if err := prometheus.Register(s.listerRequests); err != nil {
+		return err
+	}
+
+	s.listerCacheLookups = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "dirk",
+		Subsystem: "lister_cache",
+		Name:      "lookups_total",
+		Help:      "The number of account list cache lookups.",
+	}, []string{"result"})
+	return prometheus.Register(s.listerCacheLookups)
 }
 
 // ListAccountsCompleted is called when an account list process has completed.
@@ -49,3 +59,12 @@ func (s *Service) ListAccountsCompleted(started time.Time) {
 	s.listerProcessTimer.Observe(time.Since(started).Seconds())
 	s.listerRequests.WithLabelValues("succeeded").Inc()
 }
+
+// ListAccountsCacheLookup is called when the account list cache is checked for a request.
+func (s *Service) ListAccountsCacheLookup(hit bool) {
+	if hit {
+		s.listerCacheLookups.WithLabelValues("hit").Inc()
+	} else {
+		s.listerCacheLookups.WithLabelValues("miss").Inc()
+	}
+}
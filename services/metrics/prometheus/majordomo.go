@@ -0,0 +1,51 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func (s *Service) setupMajordomoMetrics() error {
+	s.majordomoFetchTimer = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "dirk",
+		Subsystem: "majordomo_fetch",
+		Name:      "duration_seconds",
+		Help:      "The time dirk spends fetching secrets from majordomo confidants.",
+		Buckets: []float64{
+			0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1.0, 2.5, 5.0, 10.0,
+		},
+	}, []string{"scheme"})
+	if err := prometheus.Register(s.majordomoFetchTimer); err != nil {
+		return err
+	}
+
+	s.majordomoFetchErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "dirk",
+		Subsystem: "majordomo_fetch",
+		Name:      "errors_total",
+		Help:      "The number of failed fetches of secrets from majordomo confidants.",
+	}, []string{"scheme"})
+	return prometheus.Register(s.majordomoFetchErrors)
+}
+
+// MajordomoFetchCompleted is called when a majordomo fetch has completed.
+func (s *Service) MajordomoFetchCompleted(started time.Time, scheme string, succeeded bool) {
+	s.majordomoFetchTimer.WithLabelValues(scheme).Observe(time.Since(started).Seconds())
+	if !succeeded {
+		s.majordomoFetchErrors.WithLabelValues(scheme).Inc()
+	}
+}
@@ -15,13 +15,17 @@ package prometheus
 
 import (
 	"errors"
+	"time"
 
 	"github.com/rs/zerolog"
 )
 
 type parameters struct {
-	logLevel zerolog.Level
-	address  string
+	logLevel           zerolog.Level
+	address            string
+	maxScrapesInFlight int
+	scrapeTimeout      time.Duration
+	maxSeriesPerMetric int
 }
 
 // Parameter is the interface for service parameters.
@@ -49,6 +53,27 @@ func WithAddress(address string) Parameter {
 	})
 }
 
+// WithMaxScrapesInFlight sets the maximum number of concurrent scrapes; 0 is unlimited.
+func WithMaxScrapesInFlight(maxScrapesInFlight int) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.maxScrapesInFlight = maxScrapesInFlight
+	})
+}
+
+// WithScrapeTimeout sets the maximum time to serve a scrape; 0 is unlimited.
+func WithScrapeTimeout(timeout time.Duration) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.scrapeTimeout = timeout
+	})
+}
+
+// WithMaxSeriesPerMetric sets the maximum number of series for each capped metric; 0 is unlimited.
+func WithMaxSeriesPerMetric(maxSeries int) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.maxSeriesPerMetric = maxSeries
+	})
+}
+
 // parseAndCheckParameters parses and checks parameters to ensure that mandatory parameters are present and correct.
 func parseAndCheckParameters(params ...Parameter) (*parameters, error) {
 	parameters := parameters{
@@ -63,6 +88,15 @@ func parseAndCheckParameters(params ...Parameter) (*parameters, error) {
 	if parameters.address == "" {
 		return nil, errors.New("no address specified")
 	}
+	if parameters.maxScrapesInFlight < 0 {
+		return nil, errors.New("max scrapes in flight cannot be negative")
+	}
+	if parameters.scrapeTimeout < 0 {
+		return nil, errors.New("scrape timeout cannot be negative")
+	}
+	if parameters.maxSeriesPerMetric < 0 {
+		return nil, errors.New("max series per metric cannot be negative")
+	}
 
 	return &parameters, nil
 }
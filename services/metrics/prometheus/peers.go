@@ -0,0 +1,39 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func (s *Service) setupPeersMetrics() error {
+	s.peersUp = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "dirk",
+		Subsystem: "peer",
+		Name:      "up",
+		Help:      "1 if the peer passed its most recent health probe, otherwise 0.",
+	}, []string{"peer"})
+	return prometheus.Register(s.peersUp)
+}
+
+// PeerProbed is called when a health probe of a peer has completed.
+func (s *Service) PeerProbed(id uint64, up bool) {
+	value := 0.0
+	if up {
+		value = 1
+	}
+	s.peersUp.WithLabelValues(fmt.Sprintf("%d", id)).Set(value)
+}
@@ -0,0 +1,88 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func (s *Service) setupProcessMetrics() error {
+	s.processTimeouts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "dirk",
+		Subsystem: "process",
+		Name:      "timeouts_total",
+		Help:      "The number of distributed operations that timed out waiting for a peer.",
+	}, []string{"operation", "peer"})
+	if err := prometheus.Register(s.processTimeouts); err != nil {
+		return err
+	}
+
+	s.processShareVerificationFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "dirk",
+		Subsystem: "process",
+		Name:      "share_verification_failures_total",
+		Help:      "The number of shares received from a peer that failed verification against its commitments.",
+	}, []string{"peer"})
+	if err := prometheus.Register(s.processShareVerificationFailures); err != nil {
+		return err
+	}
+
+	s.processAggregationVerificationFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "dirk",
+		Subsystem: "process",
+		Name:      "aggregation_verification_failures_total",
+		Help:      "The number of composite signatures aggregated from peers that failed verification against the composite public key.",
+	}, []string{"operation"})
+	if err := prometheus.Register(s.processAggregationVerificationFailures); err != nil {
+		return err
+	}
+
+	s.processGenerationPhaseTimer = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "dirk",
+		Subsystem: "process_generation_phase",
+		Name:      "duration_seconds",
+		Help:      "The time taken by each phase of distributed key generation.",
+		Buckets: []float64{
+			0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1.0, 2.5, 5.0, 10.0, 30.0,
+		},
+	}, []string{"phase", "result"})
+	return prometheus.Register(s.processGenerationPhaseTimer)
+}
+
+// DistributedOperationTimedOut is called when a distributed operation times out waiting for a peer.
+func (s *Service) DistributedOperationTimedOut(operation string, peer string) {
+	s.processTimeouts.WithLabelValues(operation, peer).Inc()
+}
+
+// DistributedShareVerificationFailed is called when a share received from a peer fails verification against its commitments.
+func (s *Service) DistributedShareVerificationFailed(peer uint64) {
+	s.processShareVerificationFailures.WithLabelValues(fmt.Sprintf("%d", peer)).Inc()
+}
+
+// DistributedAggregationVerificationFailed is called when a composite signature aggregated from peers' partial signatures fails verification.
+func (s *Service) DistributedAggregationVerificationFailed(operation string) {
+	s.processAggregationVerificationFailures.WithLabelValues(operation).Inc()
+}
+
+// DistributedGenerationPhaseCompleted is called when a phase of distributed key generation has completed.
+func (s *Service) DistributedGenerationPhaseCompleted(started time.Time, phase string, succeeded bool) {
+	result := "succeeded"
+	if !succeeded {
+		result = "failed"
+	}
+	s.processGenerationPhaseTimer.WithLabelValues(phase, result).Observe(time.Since(started).Seconds())
+}
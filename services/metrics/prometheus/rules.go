@@ -0,0 +1,82 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func (s *Service) setupRulesMetrics() error {
+	s.slashingProtectionWriteFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "dirk",
+		Subsystem: "rules",
+		Name:      "slashing_protection_write_failures_total",
+		Help:      "The number of failures to write slashing protection information.",
+	}, []string{"request"})
+	if err := prometheus.Register(s.slashingProtectionWriteFailures); err != nil {
+		return err
+	}
+
+	s.rulesStoreAvailable = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "dirk",
+		Subsystem: "rules",
+		Name:      "store_available",
+		Help:      "1 if the rules store is available, otherwise 0.",
+	})
+	if err := prometheus.Register(s.rulesStoreAvailable); err != nil {
+		return err
+	}
+
+	s.validatorNotAllowed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "dirk",
+		Subsystem: "rules",
+		Name:      "validator_not_allowed_total",
+		Help:      "The number of signing requests denied because the validator is not in the allow-list.",
+	}, []string{"request"})
+	if err := prometheus.Register(s.validatorNotAllowed); err != nil {
+		return err
+	}
+
+	s.slashingProtectionDenials = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "dirk",
+		Subsystem: "rules",
+		Name:      "slashing_protection_denials_total",
+		Help:      "The number of signing requests denied by slashing protection.",
+	}, []string{"request", "reason"})
+	return prometheus.Register(s.slashingProtectionDenials)
+}
+
+// SlashingProtectionWriteFailed is called when slashing protection information fails to be written.
+func (s *Service) SlashingProtectionWriteFailed(request string) {
+	s.slashingProtectionWriteFailures.WithLabelValues(request).Inc()
+}
+
+// RulesStoreAvailable is called when the availability of the rules store changes.
+func (s *Service) RulesStoreAvailable(available bool) {
+	if available {
+		s.rulesStoreAvailable.Set(1)
+	} else {
+		s.rulesStoreAvailable.Set(0)
+	}
+}
+
+// ValidatorNotAllowed is called when a signing request is denied because its validator is not in the allow-list.
+func (s *Service) ValidatorNotAllowed(request string) {
+	s.validatorNotAllowed.WithLabelValues(request).Inc()
+}
+
+// SlashingProtectionDenied is called when a signing request is denied by slashing protection.
+func (s *Service) SlashingProtectionDenied(request string, reason string) {
+	s.slashingProtectionDenials.WithLabelValues(request, reason).Inc()
+}
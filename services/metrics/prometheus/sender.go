@@ -0,0 +1,53 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func (s *Service) setupSenderMetrics() error {
+	s.senderRequestTimer = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "dirk",
+		Subsystem: "sender_request",
+		Name:      "duration_seconds",
+		Help:      "The time taken for requests to peers to complete.",
+		Buckets: []float64{
+			0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1.0, 2.5, 5.0, 10.0,
+		},
+	}, []string{"peer", "rpc"})
+	if err := prometheus.Register(s.senderRequestTimer); err != nil {
+		return err
+	}
+
+	s.senderRequestErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "dirk",
+		Subsystem: "sender_request",
+		Name:      "errors_total",
+		Help:      "The number of failed requests to peers.",
+	}, []string{"peer", "rpc"})
+	return prometheus.Register(s.senderRequestErrors)
+}
+
+// PeerRequestCompleted is called when a request to a peer has completed.
+func (s *Service) PeerRequestCompleted(started time.Time, peer uint64, rpc string, succeeded bool) {
+	peerLabel := fmt.Sprintf("%d", peer)
+	s.senderRequestTimer.WithLabelValues(peerLabel, rpc).Observe(time.Since(started).Seconds())
+	if !succeeded {
+		s.senderRequestErrors.WithLabelValues(peerLabel, rpc).Inc()
+	}
+}
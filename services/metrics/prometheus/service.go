@@ -26,6 +26,8 @@ import (
 
 // Service is a metrics service exposing metrics via prometheus.
 type Service struct {
+	buildInfo *prometheus.GaugeVec
+
 	accountManagerProcessTimer *prometheus.HistogramVec
 	accountManagerRequests     *prometheus.CounterVec
 
@@ -34,9 +36,59 @@ type Service struct {
 
 	listerProcessTimer prometheus.Histogram
 	listerRequests     *prometheus.CounterVec
+	listerCacheLookups *prometheus.CounterVec
+
+	checkerChecks         *prometheus.CounterVec
+	checkerChecksCapped   *cappedCounterVec
+	checkerDenials        *prometheus.CounterVec
+	checkerDenialsCapped  *cappedCounterVec
+	checkerAddressDenials *prometheus.CounterVec
+
+	signerProcessTimer   *prometheus.HistogramVec
+	signerRequests       *prometheus.CounterVec
+	signerQueueTimer     *prometheus.HistogramVec
+	signerDuration       *prometheus.HistogramVec
+	signerTaggedRequests *prometheus.CounterVec
+	signerCoalesced      *prometheus.CounterVec
+	signerInflight       prometheus.Gauge
+	signerQueued         prometheus.Gauge
+
+	slashingProtectionWriteFailures *prometheus.CounterVec
+	rulesStoreAvailable             prometheus.Gauge
+	validatorNotAllowed             *prometheus.CounterVec
+	slashingProtectionDenials       *prometheus.CounterVec
+
+	certificateReloadAttempts prometheus.Counter
+	certificateReloads        *prometheus.CounterVec
+	apiRequestPanics          *prometheus.CounterVec
+	apiRateLimitedRequests    *prometheus.CounterVec
+	certificateLastReload     prometheus.Gauge
+	certificateExpiry         *certificateExpiryCollector
+
+	fetcherRefreshes       *prometheus.CounterVec
+	fetcherAccountsAdded   prometheus.Counter
+	fetcherAccountsRemoved prometheus.Counter
+	fetcherRefreshErrors   prometheus.Counter
+	fetcherLastRefresh     prometheus.Gauge
+	fetcherAccounts        *prometheus.GaugeVec
 
-	signerProcessTimer *prometheus.HistogramVec
-	signerRequests     *prometheus.CounterVec
+	processTimeouts                        *prometheus.CounterVec
+	processShareVerificationFailures       *prometheus.CounterVec
+	processAggregationVerificationFailures *prometheus.CounterVec
+	processGenerationPhaseTimer            *prometheus.HistogramVec
+
+	peersUp *prometheus.GaugeVec
+
+	unlockerUnlockedAccounts prometheus.Gauge
+
+	senderRequestTimer  *prometheus.HistogramVec
+	senderRequestErrors *prometheus.CounterVec
+
+	majordomoFetchTimer  *prometheus.HistogramVec
+	majordomoFetchErrors *prometheus.CounterVec
+
+	maxSeriesPerMetric int
+	seriesDropped      *prometheus.CounterVec
 }
 
 // module-wide log.
@@ -55,8 +107,19 @@ func New(ctx context.Context, params ...Parameter) (*Service, error) {
 		log = log.Level(parameters.logLevel)
 	}
 
-	s := &Service{}
+	s := &Service{
+		maxSeriesPerMetric: parameters.maxSeriesPerMetric,
+	}
 
+	if err := s.setupBuildMetrics(); err != nil {
+		return nil, errors.Wrap(err, "failed to set up build metrics")
+	}
+	if err := s.setupCardinalityMetrics(); err != nil {
+		return nil, errors.Wrap(err, "failed to set up cardinality metrics")
+	}
+	if err := s.setupCheckerMetrics(); err != nil {
+		return nil, errors.Wrap(err, "failed to set up checker metrics")
+	}
 	if err := s.setupAccountManagerMetrics(); err != nil {
 		return nil, errors.Wrap(err, "failed to set up account manager metrics")
 	}
@@ -69,9 +132,41 @@ func New(ctx context.Context, params ...Parameter) (*Service, error) {
 	if err := s.setupSignerMetrics(); err != nil {
 		return nil, errors.Wrap(err, "failed to set up signer metrics")
 	}
+	if err := s.setupRulesMetrics(); err != nil {
+		return nil, errors.Wrap(err, "failed to set up rules metrics")
+	}
+	if err := s.setupAPIMetrics(); err != nil {
+		return nil, errors.Wrap(err, "failed to set up API metrics")
+	}
+	if err := s.setupFetcherMetrics(); err != nil {
+		return nil, errors.Wrap(err, "failed to set up fetcher metrics")
+	}
+	if err := s.setupProcessMetrics(); err != nil {
+		return nil, errors.Wrap(err, "failed to set up process metrics")
+	}
+	if err := s.setupMajordomoMetrics(); err != nil {
+		return nil, errors.Wrap(err, "failed to set up majordomo metrics")
+	}
+	if err := s.setupPeersMetrics(); err != nil {
+		return nil, errors.Wrap(err, "failed to set up peers metrics")
+	}
+	if err := s.setupSenderMetrics(); err != nil {
+		return nil, errors.Wrap(err, "failed to set up sender metrics")
+	}
+	if err := s.setupUnlockerMetrics(); err != nil {
+		return nil, errors.Wrap(err, "failed to set up unlocker metrics")
+	}
 
 	go func() {
-		http.Handle("/metrics", promhttp.Handler())
+		// Equivalent to promhttp.Handler(), but with operator-supplied limits so that
+		// scrapes of large registries cannot pile up.
+		handler := promhttp.InstrumentMetricHandler(prometheus.DefaultRegisterer,
+			promhttp.HandlerFor(prometheus.DefaultGatherer, promhttp.HandlerOpts{
+				MaxRequestsInFlight: parameters.maxScrapesInFlight,
+				Timeout:             parameters.scrapeTimeout,
+			}),
+		)
+		http.Handle("/metrics", handler)
 		if err := http.ListenAndServe(parameters.address, nil); err != nil {
 			log.Warn().Str("metrics_listen_address", parameters.address).Err(err).Msg("Failed to run metrics server")
 		}
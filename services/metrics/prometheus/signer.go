@@ -37,17 +37,110 @@ func (s *Service) setupSignerMetrics() error {
 		return err
 	}
 
+	s.signerDuration =
+		prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "dirk",
+			Subsystem: "signer",
+			Name:      "duration_seconds",
+			Help:      "The time taken to complete sign requests, by operation and result.",
+			// Spans requests answered from memory to those that involve distributed accounts.
+			Buckets: []float64{
+				0.0001, 0.00025, 0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1.0, 2.5, 5.0,
+			},
+		}, []string{"operation", "result"})
+	if err := prometheus.Register(s.signerDuration); err != nil {
+		return err
+	}
+
 	s.signerRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
 		Namespace: "dirk",
 		Subsystem: "signer_process",
 		Name:      "requests_total",
 		Help:      "The number of sign requests.",
 	}, []string{"request", "result"})
-	return prometheus.Register(s.signerRequests)
+	if err := prometheus.Register(s.signerRequests); err != nil {
+		return err
+	}
+
+	s.signerQueueTimer =
+		prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "dirk",
+			Subsystem: "signer_queue",
+			Name:      "wait_duration_seconds",
+			Help:      "The time sign requests spend waiting for signing capacity.",
+			Buckets: []float64{
+				0.001, 0.002, 0.005, 0.01, 0.02, 0.05, 0.1, 0.2, 0.5, 1.0, 2.0, 5.0,
+			},
+		}, []string{"request"})
+	if err := prometheus.Register(s.signerQueueTimer); err != nil {
+		return err
+	}
+
+	s.signerInflight = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "dirk",
+		Subsystem: "signer",
+		Name:      "inflight_requests",
+		Help:      "The number of sign requests holding signing capacity.",
+	})
+	if err := prometheus.Register(s.signerInflight); err != nil {
+		return err
+	}
+
+	s.signerQueued = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "dirk",
+		Subsystem: "signer",
+		Name:      "queued_requests",
+		Help:      "The number of sign requests waiting for signing capacity.",
+	})
+	if err := prometheus.Register(s.signerQueued); err != nil {
+		return err
+	}
+
+	s.signerCoalesced = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "dirk",
+		Subsystem: "signer_process",
+		Name:      "coalesced_requests_total",
+		Help:      "The number of sign requests that shared the result of an identical in-progress request.",
+	}, []string{"request"})
+	if err := prometheus.Register(s.signerCoalesced); err != nil {
+		return err
+	}
+
+	s.signerTaggedRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "dirk",
+		Subsystem: "signer_process",
+		Name:      "tagged_requests_total",
+		Help:      "The number of sign requests for tagged messages.",
+	}, []string{"tag", "result"})
+	return prometheus.Register(s.signerTaggedRequests)
 }
 
 // SignCompleted is called when a signing process is complete.
 func (s *Service) SignCompleted(started time.Time, request string, result core.Result) {
-	s.signerProcessTimer.WithLabelValues(request).Observe(time.Since(started).Seconds())
-	s.signerRequests.WithLabelValues(request, strings.ToLower(result.String())).Inc()
+	duration := time.Since(started).Seconds()
+	resultLabel := strings.ToLower(result.String())
+	s.signerProcessTimer.WithLabelValues(request).Observe(duration)
+	s.signerDuration.WithLabelValues(request, resultLabel).Observe(duration)
+	s.signerRequests.WithLabelValues(request, resultLabel).Inc()
+}
+
+// SignQueueCompleted is called when a signing request has finished waiting for capacity.
+func (s *Service) SignQueueCompleted(started time.Time, request string) {
+	s.signerQueueTimer.WithLabelValues(request).Observe(time.Since(started).Seconds())
+}
+
+// SignCapacity is called when the number of signing operations in progress or waiting for capacity changes.
+func (s *Service) SignCapacity(inflight int, queued int) {
+	s.signerInflight.Set(float64(inflight))
+	s.signerQueued.Set(float64(queued))
+}
+
+// SignCoalesced is called when a signing request shares the result of an identical in-progress request.
+func (s *Service) SignCoalesced(request string) {
+	s.signerCoalesced.WithLabelValues(request).Inc()
+}
+
+// TaggedSignCompleted is called when a signing process for a permitted tagged message has completed.
+func (s *Service) TaggedSignCompleted(tag string, result core.Result) {
+	s.signerTaggedRequests.WithLabelValues(tag, strings.ToLower(result.String())).Inc()
 }
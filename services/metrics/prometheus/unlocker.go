@@ -0,0 +1,33 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func (s *Service) setupUnlockerMetrics() error {
+	s.unlockerUnlockedAccounts = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "dirk",
+		Subsystem: "unlocker",
+		Name:      "unlocked_accounts",
+		Help:      "The number of accounts currently unlocked by the unlocker.",
+	})
+	return prometheus.Register(s.unlockerUnlockedAccounts)
+}
+
+// UnlockedAccounts is called with the number of accounts that are currently unlocked by the unlocker.
+func (s *Service) UnlockedAccounts(count int) {
+	s.unlockerUnlockedAccounts.Set(float64(count))
+}
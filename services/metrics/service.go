@@ -26,22 +26,50 @@ type Service interface {
 	Presenter() string
 }
 
+// BuildMonitor monitors the build of the running binary.
+type BuildMonitor interface {
+	// BuildInfo is called with details of the build of the running binary.
+	BuildInfo(version string, goVersion string, commit string)
+}
+
 // UnlockerMonitor monitors the unlocker service.
 type UnlockerMonitor interface {
+	// UnlockedAccounts is called with the number of accounts that are currently unlocked by the unlocker.
+	UnlockedAccounts(count int)
 }
 
 // CheckerMonitor monitors the checker service.
 type CheckerMonitor interface {
+	// PermissionChecked is called when a client's permission for an operation has been checked.
+	PermissionChecked(client string, operation string, allowed bool)
+	// PermissionDenied is called when a client is denied permission for an operation.
+	PermissionDenied(client string, operation string)
+	// AddressDenied is called when a client is denied because its request
+	// came from an address outside of its allowed IP ranges.
+	AddressDenied(client string)
 }
 
 // SignerMonitor monitors the signer service.
 type SignerMonitor interface {
 	// SignCompleted is called when a siging process has completed.
 	SignCompleted(started time.Time, request string, result core.Result)
+	// SignQueueCompleted is called when a signing request has finished waiting for capacity.
+	SignQueueCompleted(started time.Time, request string)
+	// SignCapacity is called when the number of signing operations in progress or waiting for capacity changes.
+	SignCapacity(inflight int, queued int)
+	// SignCoalesced is called when a signing request shares the result of an identical in-progress request.
+	SignCoalesced(request string)
+	// TaggedSignCompleted is called when a signing process for a permitted tagged message has completed.
+	TaggedSignCompleted(tag string, result core.Result)
 }
 
 // FetcherMonitor monitors the fetcher service.
 type FetcherMonitor interface {
+	// FetcherRefreshCompleted is called when the fetcher has completed a scan of its stores.
+	FetcherRefreshCompleted(added int, removed int, failures int)
+	// FetcherAccounts is called with the number of accounts loaded from each
+	// wallet in each store, keyed by store then wallet, whenever the stores are scanned.
+	FetcherAccounts(accounts map[string]map[string]int)
 }
 
 // LockerMonitor monitors the locker service.
@@ -52,20 +80,52 @@ type LockerMonitor interface {
 type RulerMonitor interface {
 }
 
+// RulesMonitor monitors the rules service.
+type RulesMonitor interface {
+	// SlashingProtectionWriteFailed is called when slashing protection information fails to be written.
+	SlashingProtectionWriteFailed(request string)
+	// RulesStoreAvailable is called when the availability of the rules store changes.
+	RulesStoreAvailable(available bool)
+	// ValidatorNotAllowed is called when a signing request is denied because its validator is not in the allow-list.
+	ValidatorNotAllowed(request string)
+	// SlashingProtectionDenied is called when a signing request is denied by slashing protection.
+	SlashingProtectionDenied(request string, reason string)
+}
+
 // APIMonitor monitors the API service.
 type APIMonitor interface {
+	// CertificateReloadCompleted is called when an attempt to reload the server certificates has completed.
+	CertificateReloadCompleted(succeeded bool)
+	// RequestPanicked is called when a request handler panics.
+	RequestPanicked(rpc string)
+	// RequestRateLimited is called when a request is rejected for exceeding the client's rate limit.
+	RequestRateLimited(client string)
+	// CertificateExpiry is called when a certificate is loaded, with the time at which it expires.
+	CertificateExpiry(certificate string, notAfter time.Time)
 }
 
 // PeersMonitor monitors the dirk peers service.
 type PeersMonitor interface {
+	// PeerProbed is called when a health probe of a peer has completed.
+	PeerProbed(id uint64, up bool)
 }
 
 // ProcessMonitor monitors the process service.
 type ProcessMonitor interface {
+	// DistributedOperationTimedOut is called when a distributed operation times out waiting for a peer.
+	DistributedOperationTimedOut(operation string, peer string)
+	// DistributedShareVerificationFailed is called when a share received from a peer fails verification against its commitments.
+	DistributedShareVerificationFailed(peer uint64)
+	// DistributedAggregationVerificationFailed is called when a composite signature aggregated from peers' partial signatures fails verification.
+	DistributedAggregationVerificationFailed(operation string)
+	// DistributedGenerationPhaseCompleted is called when a phase of distributed key generation has completed.
+	DistributedGenerationPhaseCompleted(started time.Time, phase string, succeeded bool)
 }
 
 // SenderMonitor monitors the sender service.
 type SenderMonitor interface {
+	// PeerRequestCompleted is called when a request to a peer has completed.
+	PeerRequestCompleted(started time.Time, peer uint64, rpc string, succeeded bool)
 }
 
 // ReceiverMonitor monitors the receiver service.
@@ -76,6 +136,8 @@ type ReceiverMonitor interface {
 type ListerMonitor interface {
 	// ListAccountsCompleted is called when a request for accounts has completed.
 	ListAccountsCompleted(started time.Time)
+	// ListAccountsCacheLookup is called when the account list cache is checked for a request.
+	ListAccountsCacheLookup(hit bool)
 }
 
 // AccountManagerMonitor monitors the account manager service.
@@ -90,6 +152,12 @@ type WalletManagerMonitor interface {
 	WalletManagerCompleted(started time.Time, request string, result core.Result)
 }
 
+// MajordomoMonitor monitors the majordomo service.
+type MajordomoMonitor interface {
+	// MajordomoFetchCompleted is called when a majordomo fetch has completed.
+	MajordomoFetchCompleted(started time.Time, scheme string, succeeded bool)
+}
+
 // ConfidantMonitor monitors the confidant service.
 type ConfidantMonitor interface {
 }
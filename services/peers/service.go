@@ -14,6 +14,8 @@
 package peers
 
 import (
+	"context"
+
 	"github.com/attestantio/dirk/core"
 )
 
@@ -27,4 +29,15 @@ type Service interface {
 
 	// Suitable returns peers that are suitable given the supplied requirements.
 	Suitable(threshold uint32) ([]*core.Endpoint, error)
+
+	// Healthy returns false if the peer with the given ID failed its most recent health probe.
+	Healthy(id uint64) bool
+}
+
+// Updater is the interface for a service that can update its peers at runtime.
+type Updater interface {
+	// Update adds or updates the peers in set, keyed by ID with addresses in
+	// the form name:port, and removes the peers with IDs in remove.  The
+	// change is applied in full or not at all.  It returns the resulting peers.
+	Update(ctx context.Context, set map[uint64]string, remove []uint64) (map[uint64]*core.Endpoint, error)
 }
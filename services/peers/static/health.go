@@ -0,0 +1,87 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package static
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/attestantio/dirk/core"
+	"github.com/attestantio/dirk/services/sender"
+)
+
+// probePeriodically probes the health of all peers at the given interval.
+func (s *Service) probePeriodically(ctx context.Context, prober sender.Prober, interval time.Duration) {
+	s.probe(ctx, prober, interval)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.probe(ctx, prober, interval)
+		}
+	}
+}
+
+// probe probes the health of all peers concurrently, waiting at most the given timeout.
+func (s *Service) probe(ctx context.Context, prober sender.Prober, timeout time.Duration) {
+	peers := s.All()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	unhealthy := make(map[uint64]bool)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, peer := range peers {
+		wg.Add(1)
+		go func(peer *core.Endpoint) {
+			defer wg.Done()
+			err := prober.Probe(ctx, peer)
+			if err != nil {
+				log.Debug().Err(err).Str("peer", peer.String()).Msg("Peer failed health probe")
+				mu.Lock()
+				unhealthy[peer.ID] = true
+				mu.Unlock()
+			}
+			s.monitor.PeerProbed(peer.ID, err == nil)
+		}(peer)
+	}
+	wg.Wait()
+
+	s.unhealthyMu.Lock()
+	for id := range unhealthy {
+		if !s.unhealthy[id] {
+			log.Warn().Uint64("peer_id", id).Msg("Peer is down")
+		}
+	}
+	for id := range s.unhealthy {
+		if _, exists := peers[id]; exists && !unhealthy[id] {
+			log.Info().Uint64("peer_id", id).Msg("Peer is up")
+		}
+	}
+	// Peers that have been removed are dropped here.
+	s.unhealthy = unhealthy
+	s.unhealthyMu.Unlock()
+}
+
+// Healthy returns false if the peer with the given ID failed its most recent
+// health probe.  Peers that have not been probed are considered healthy.
+func (s *Service) Healthy(id uint64) bool {
+	s.unhealthyMu.RLock()
+	defer s.unhealthyMu.RUnlock()
+	return !s.unhealthy[id]
+}
@@ -0,0 +1,119 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package static_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/attestantio/dirk/core"
+	staticpeers "github.com/attestantio/dirk/services/peers/static"
+	"github.com/stretchr/testify/require"
+)
+
+type testProber struct {
+	mu   sync.Mutex
+	down map[uint64]bool
+}
+
+func (p *testProber) setDown(id uint64, down bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.down[id] = down
+}
+
+func (p *testProber) Probe(ctx context.Context, recipient *core.Endpoint) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.down[recipient.ID] {
+		return errors.New("unreachable")
+	}
+	return nil
+}
+
+type probeMonitor struct {
+	mu sync.Mutex
+	up map[uint64]bool
+}
+
+func (m *probeMonitor) PeerProbed(id uint64, up bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.up[id] = up
+}
+
+func (m *probeMonitor) isUp(id uint64) (bool, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	up, exists := m.up[id]
+	return up, exists
+}
+
+func TestHealth(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	prober := &testProber{down: map[uint64]bool{2: true}}
+	monitor := &probeMonitor{up: make(map[uint64]bool)}
+	service, err := staticpeers.New(ctx,
+		staticpeers.WithMonitor(monitor),
+		staticpeers.WithPeers(map[uint64]string{
+			1: "peer1:1001",
+			2: "peer2:1002",
+			3: "peer3:1003",
+		}),
+		staticpeers.WithProber(prober),
+		staticpeers.WithProbeInterval(10*time.Millisecond),
+	)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		return !service.Healthy(2)
+	}, time.Second, 5*time.Millisecond)
+	require.True(t, service.Healthy(1))
+	require.True(t, service.Healthy(3))
+	up, exists := monitor.isUp(2)
+	require.True(t, exists)
+	require.False(t, up)
+
+	// The unhealthy peer is not suitable.
+	_, err = service.Suitable(3)
+	require.EqualError(t, err, "not enough suitable peers")
+	suitable, err := service.Suitable(2)
+	require.NoError(t, err)
+	for _, peer := range suitable {
+		require.NotEqual(t, uint64(2), peer.ID)
+	}
+
+	// The peer recovers.
+	prober.setDown(2, false)
+	require.Eventually(t, func() bool {
+		return service.Healthy(2)
+	}, time.Second, 5*time.Millisecond)
+	_, err = service.Suitable(3)
+	require.NoError(t, err)
+}
+
+func TestHealthNoProber(t *testing.T) {
+	service, err := staticpeers.New(context.Background(),
+		staticpeers.WithPeers(map[uint64]string{
+			1: "peer1:1001",
+		}),
+	)
+	require.NoError(t, err)
+	require.True(t, service.Healthy(1))
+}
@@ -16,3 +16,6 @@ package static
 // noopMonitor is a monitor that does nothing, used in place of nil if an
 // external monitor is not supplied.
 type noopMonitor struct{}
+
+// PeerProbed is called when a health probe of a peer has completed.
+func (n *noopMonitor) PeerProbed(id uint64, up bool) {}
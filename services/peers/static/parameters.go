@@ -14,15 +14,22 @@
 package static
 
 import (
+	"time"
+
 	"github.com/attestantio/dirk/services/metrics"
+	"github.com/attestantio/dirk/services/sender"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
 )
 
 type parameters struct {
-	logLevel zerolog.Level
-	monitor  metrics.PeersMonitor
-	peers    map[uint64]string
+	logLevel      zerolog.Level
+	monitor       metrics.PeersMonitor
+	peers         map[uint64]string
+	file          string
+	interval      time.Duration
+	prober        sender.Prober
+	probeInterval time.Duration
 }
 
 // Parameter is the interface for service parameters.
@@ -57,10 +64,42 @@ func WithPeers(peers map[uint64]string) Parameter {
 	})
 }
 
+// WithPeersFile sets a file from which to read the peers, in place of WithPeers.
+// The file is checked for changes, and the peers reloaded, every reload interval.
+func WithPeersFile(file string) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.file = file
+	})
+}
+
+// WithReloadInterval sets the interval at which the peers file is checked for changes.
+func WithReloadInterval(interval time.Duration) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.interval = interval
+	})
+}
+
+// WithProber sets the prober used to check the health of peers.
+// If not set, peers are not probed and are always considered healthy.
+func WithProber(prober sender.Prober) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.prober = prober
+	})
+}
+
+// WithProbeInterval sets the interval at which the health of peers is probed.
+func WithProbeInterval(interval time.Duration) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.probeInterval = interval
+	})
+}
+
 // parseAndCheckParameters parses and checks parameters to ensure that mandatory parameters are present and correct.
 func parseAndCheckParameters(params ...Parameter) (*parameters, error) {
 	parameters := parameters{
-		logLevel: zerolog.GlobalLevel(),
+		logLevel:      zerolog.GlobalLevel(),
+		interval:      10 * time.Second,
+		probeInterval: 30 * time.Second,
 	}
 	for _, p := range params {
 		if params != nil {
@@ -73,9 +112,18 @@ func parseAndCheckParameters(params ...Parameter) (*parameters, error) {
 		parameters.monitor = &noopMonitor{}
 	}
 
-	if len(parameters.peers) == 0 {
+	if len(parameters.peers) == 0 && parameters.file == "" {
 		return nil, errors.New("no peers specified")
 	}
+	if len(parameters.peers) > 0 && parameters.file != "" {
+		return nil, errors.New("peers and peers file cannot both be specified")
+	}
+	if parameters.interval <= 0 {
+		return nil, errors.New("reload interval must be positive")
+	}
+	if parameters.probeInterval <= 0 {
+		return nil, errors.New("probe interval must be positive")
+	}
 
 	return &parameters, nil
 }
@@ -0,0 +1,136 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package static
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/attestantio/dirk/core"
+	"github.com/fsnotify/fsnotify"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// parsePeersFile parses the contents of a peers file, which is a YAML or JSON
+// map of peer IDs to addresses in the same form as the peers configuration.
+// IDs are parsed from strings, as JSON keys are always strings.
+func parsePeersFile(data []byte) (map[uint64]*core.Endpoint, error) {
+	entries := make(map[string]string)
+	if err := yaml.UnmarshalStrict(data, &entries); err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, errors.New("no peers specified")
+	}
+	peers := make(map[uint64]string, len(entries))
+	for k, v := range entries {
+		id, err := strconv.ParseUint(k, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid peer ID %q", k)
+		}
+		peers[id] = v
+	}
+	return parsePeers(peers)
+}
+
+// watch checks the peers file for changes, and reloads the peers when it
+// changes.  The file is checked when notified of a change by the watcher, if
+// present, and also at the given interval in case notifications are not
+// available or are missed.  If the file cannot be read or parsed the existing
+// peers are retained.
+func (s *Service) watch(ctx context.Context, file string, interval time.Duration, current []byte, watcher *fsnotify.Watcher) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var events chan fsnotify.Event
+	if watcher != nil {
+		defer watcher.Close()
+		events = watcher.Events
+		go func() {
+			for err := range watcher.Errors {
+				log.Warn().Err(err).Str("file", file).Msg("Error watching peers file")
+			}
+		}()
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case event, ok := <-events:
+			if !ok {
+				events = nil
+				continue
+			}
+			if filepath.Clean(event.Name) != filepath.Clean(file) {
+				continue
+			}
+		}
+		current = s.check(file, current)
+	}
+}
+
+// watchDir returns a watcher for the directory containing the file.  The
+// directory is watched rather than the file itself so that changes made by
+// replacing the file, as is common for generated files, are seen.
+func watchDir(file string) (*fsnotify.Watcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	if err := watcher.Add(filepath.Dir(file)); err != nil {
+		watcher.Close()
+		return nil, err
+	}
+	return watcher, nil
+}
+
+// check reloads the peers if the contents of the file differ from those
+// supplied, returning the contents of the file as last read.
+func (s *Service) check(file string, current []byte) []byte {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		log.Error().Err(err).Str("file", file).Msg("Failed to read peers file; retaining existing peers")
+		return current
+	}
+	if bytes.Equal(data, current) {
+		return current
+	}
+	// Only retry a bad file once it changes again.
+	if err := s.reload(data); err != nil {
+		log.Error().Err(err).Str("file", file).Msg("Failed to parse peers file; retaining existing peers")
+		return data
+	}
+	log.Info().Str("file", file).Int("peers", len(s.All())).Msg("Reloaded peers")
+	return data
+}
+
+// reload replaces the peers with those in the supplied peers file data.
+func (s *Service) reload(data []byte) error {
+	peers, err := parsePeersFile(data)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.peers = peers
+	s.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,114 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package static_test
+
+import (
+	context "context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	staticpeers "github.com/attestantio/dirk/services/peers/static"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeersFile(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	base, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(base)
+	file := filepath.Join(base, "peers.yml")
+
+	// Missing file.
+	_, err = staticpeers.New(ctx, staticpeers.WithPeersFile(file))
+	require.Contains(t, err.Error(), "failed to read peers file")
+
+	// Bad initial file.
+	require.NoError(t, ioutil.WriteFile(file, []byte("1: malformed\n"), 0600))
+	_, err = staticpeers.New(ctx, staticpeers.WithPeersFile(file))
+	require.EqualError(t, err, "failed to parse peers file: malformed peer malformed")
+
+	// Both peers and file.
+	_, err = staticpeers.New(ctx, staticpeers.WithPeersFile(file), staticpeers.WithPeers(map[uint64]string{1: "server1:1"}))
+	require.EqualError(t, err, "problem with parameters: peers and peers file cannot both be specified")
+
+	require.NoError(t, ioutil.WriteFile(file, []byte("1: server1:8881\n2: server2:8882\n"), 0600))
+	s, err := staticpeers.New(ctx,
+		staticpeers.WithPeersFile(file),
+		staticpeers.WithReloadInterval(10*time.Millisecond),
+	)
+	require.NoError(t, err)
+	require.Len(t, s.All(), 2)
+
+	// Change the peers.
+	require.NoError(t, ioutil.WriteFile(file, []byte("1: server1:8881\n2: server2:8882\n3: server3:8883\n"), 0600))
+	require.Eventually(t, func() bool { return len(s.All()) == 3 }, time.Second, 5*time.Millisecond)
+	peer, err := s.Peer(3)
+	require.NoError(t, err)
+	require.Equal(t, "server3", peer.Name)
+
+	// Bad and empty files retain the existing peers.
+	for _, data := range []string{"1: server1:bad\n", "not: [valid\n", ""} {
+		require.NoError(t, ioutil.WriteFile(file, []byte(data), 0600))
+		time.Sleep(50 * time.Millisecond)
+		require.Len(t, s.All(), 3)
+	}
+
+	// Removed file retains the existing peers.
+	require.NoError(t, os.Remove(file))
+	time.Sleep(50 * time.Millisecond)
+	require.Len(t, s.All(), 3)
+
+	// Good file again.
+	require.NoError(t, ioutil.WriteFile(file, []byte("1: server1:8881\n"), 0600))
+	require.Eventually(t, func() bool { return len(s.All()) == 1 }, time.Second, 5*time.Millisecond)
+	_, err = s.Peer(2)
+	require.Equal(t, staticpeers.ErrNotFound, err)
+}
+
+func TestPeersFileNotify(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	base, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(base)
+	file := filepath.Join(base, "peers.json")
+
+	require.NoError(t, ioutil.WriteFile(file, []byte(`{"1": "server1:8881"}`), 0600))
+	// The reload interval is long enough that only notifications result in reloads.
+	s, err := staticpeers.New(ctx,
+		staticpeers.WithPeersFile(file),
+		staticpeers.WithReloadInterval(time.Hour),
+	)
+	require.NoError(t, err)
+	require.Len(t, s.All(), 1)
+
+	// Replace the file, as a generator would.
+	tmpFile := filepath.Join(base, "peers.json.tmp")
+	require.NoError(t, ioutil.WriteFile(tmpFile, []byte(`{"1": "server1:8881", "2": "server2:8882"}`), 0600))
+	require.NoError(t, os.Rename(tmpFile, file))
+	require.Eventually(t, func() bool { return len(s.All()) == 2 }, time.Second, 5*time.Millisecond)
+
+	// Write the file in place.
+	require.NoError(t, ioutil.WriteFile(file, []byte(`{"3": "server3:8883"}`), 0600))
+	require.Eventually(t, func() bool {
+		_, err := s.Peer(3)
+		return err == nil && len(s.All()) == 1
+	}, time.Second, 5*time.Millisecond)
+}
@@ -16,10 +16,14 @@ package static
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/attestantio/dirk/core"
+	"github.com/attestantio/dirk/services/metrics"
+	"github.com/attestantio/dirk/services/sender"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
 	zerologger "github.com/rs/zerolog/log"
@@ -30,7 +34,16 @@ var ErrNotFound = errors.New("not found")
 
 // Service provides a static list of peers.
 type Service struct {
-	peers map[uint64]*core.Endpoint
+	monitor metrics.PeersMonitor
+	prober  sender.Prober
+	// fromFile is true if the peers are managed by a peers file.
+	fromFile bool
+	updateMu sync.Mutex
+	mu       sync.RWMutex
+	peers    map[uint64]*core.Endpoint
+	// unhealthy holds the IDs of peers that failed their most recent health probe.
+	unhealthyMu sync.RWMutex
+	unhealthy   map[uint64]bool
 }
 
 // module-wide log.
@@ -49,42 +62,87 @@ func New(ctx context.Context, params ...Parameter) (*Service, error) {
 		log = log.Level(parameters.logLevel)
 	}
 
-	peerNames := make(map[string]bool)
-	servicePeers := make(map[uint64]*core.Endpoint, len(parameters.peers))
-	if parameters.peers != nil {
-		for id, v := range parameters.peers {
-			peerInfo := strings.Split(v, ":")
-			if len(peerInfo) != 2 {
-				return nil, fmt.Errorf("malformed peer %s", v)
-			}
-			if _, exists := peerNames[peerInfo[0]]; exists {
-				return nil, fmt.Errorf("duplicate peer name %s", peerInfo[0])
-			}
-			peerNames[peerInfo[0]] = true
-			port, err := strconv.ParseUint(peerInfo[1], 10, 32)
-			if err != nil {
-				return nil, fmt.Errorf("malformed peer port for %s", v)
-			}
-			if port == 0 {
-				return nil, fmt.Errorf("invalid peer port for %s", v)
-			}
-			servicePeers[id] = &core.Endpoint{
-				ID:   id,
-				Name: peerInfo[0],
-				Port: uint32(port),
-			}
+	if parameters.file != "" {
+		data, err := ioutil.ReadFile(parameters.file)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read peers file")
+		}
+		servicePeers, err := parsePeersFile(data)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse peers file")
+		}
+		s := &Service{
+			monitor:   parameters.monitor,
+			prober:    parameters.prober,
+			fromFile:  true,
+			peers:     servicePeers,
+			unhealthy: make(map[uint64]bool),
+		}
+		// The watcher is started before returning so that no changes are missed.
+		watcher, err := watchDir(parameters.file)
+		if err != nil {
+			log.Warn().Err(err).Str("file", parameters.file).Msg("Failed to watch peers file for changes; checking periodically only")
+			watcher = nil
+		}
+		go s.watch(ctx, parameters.file, parameters.interval, data, watcher)
+		if parameters.prober != nil {
+			go s.probePeriodically(ctx, parameters.prober, parameters.probeInterval)
 		}
+		return s, nil
+	}
+
+	servicePeers, err := parsePeers(parameters.peers)
+	if err != nil {
+		return nil, err
 	}
 
 	s := &Service{
-		peers: servicePeers,
+		monitor:   parameters.monitor,
+		prober:    parameters.prober,
+		peers:     servicePeers,
+		unhealthy: make(map[uint64]bool),
+	}
+	if parameters.prober != nil {
+		go s.probePeriodically(ctx, parameters.prober, parameters.probeInterval)
 	}
 
 	return s, nil
 }
 
+// parsePeers parses a map of peer IDs to addresses in the form name:port.
+func parsePeers(peers map[uint64]string) (map[uint64]*core.Endpoint, error) {
+	peerNames := make(map[string]bool)
+	servicePeers := make(map[uint64]*core.Endpoint, len(peers))
+	for id, v := range peers {
+		peerInfo := strings.Split(v, ":")
+		if len(peerInfo) != 2 {
+			return nil, fmt.Errorf("malformed peer %s", v)
+		}
+		if _, exists := peerNames[peerInfo[0]]; exists {
+			return nil, fmt.Errorf("duplicate peer name %s", peerInfo[0])
+		}
+		peerNames[peerInfo[0]] = true
+		port, err := strconv.ParseUint(peerInfo[1], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("malformed peer port for %s", v)
+		}
+		if port == 0 {
+			return nil, fmt.Errorf("invalid peer port for %s", v)
+		}
+		servicePeers[id] = &core.Endpoint{
+			ID:   id,
+			Name: peerInfo[0],
+			Port: uint32(port),
+		}
+	}
+
+	return servicePeers, nil
+}
+
 // Peer returns the peer with the given ID.
 func (s *Service) Peer(id uint64) (*core.Endpoint, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 	peer, exists := s.peers[id]
 	if !exists {
 		return nil, ErrNotFound
@@ -98,6 +156,8 @@ func (s *Service) Peer(id uint64) (*core.Endpoint, error) {
 
 // All returns all peers.
 func (s *Service) All() map[uint64]*core.Endpoint {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 	res := make(map[uint64]*core.Endpoint, len(s.peers))
 	for id, peer := range s.peers {
 		res[id] = &core.Endpoint{
@@ -110,11 +170,17 @@ func (s *Service) All() map[uint64]*core.Endpoint {
 }
 
 // Suitable returns peers that are suitable given the supplied requirements.
-// At current any peer that is present is considered suitable.
+// Any peer that is present is considered suitable, unless it failed its
+// most recent health probe.
 func (s *Service) Suitable(threshold uint32) ([]*core.Endpoint, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 	suitable := uint32(0)
 	res := make([]*core.Endpoint, threshold)
 	for _, peer := range s.peers {
+		if !s.Healthy(peer.ID) {
+			continue
+		}
 		res[suitable] = &core.Endpoint{
 			ID:   peer.ID,
 			Name: peer.Name,
@@ -0,0 +1,97 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package static
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/attestantio/dirk/core"
+	"github.com/pkg/errors"
+)
+
+// updateProbeTimeout is the time allowed for new endpoints to respond to a probe.
+var updateProbeTimeout = 5 * time.Second
+
+// Update adds or updates the peers in set and removes the peers in remove.
+// The resulting peer set is validated, and any new or changed endpoints must
+// respond to a health probe if a prober is configured, before the change is
+// applied.  Either the whole change is applied or none of it is.
+func (s *Service) Update(ctx context.Context, set map[uint64]string, remove []uint64) (map[uint64]*core.Endpoint, error) {
+	if s.fromFile {
+		return nil, errors.New("peers are managed by a peers file")
+	}
+	if len(set) == 0 && len(remove) == 0 {
+		return nil, errors.New("no changes specified")
+	}
+
+	// Updates are serialised so that each is validated against the peers it replaces.
+	s.updateMu.Lock()
+	defer s.updateMu.Unlock()
+
+	current := s.All()
+	addresses := make(map[uint64]string, len(current)+len(set))
+	for id, peer := range current {
+		addresses[id] = peer.ConnectAddress()
+	}
+	for _, id := range remove {
+		if _, exists := set[id]; exists {
+			return nil, fmt.Errorf("peer %d cannot be both set and removed", id)
+		}
+		if _, exists := addresses[id]; !exists {
+			return nil, fmt.Errorf("peer %d is not known", id)
+		}
+		delete(addresses, id)
+	}
+	for id, address := range set {
+		addresses[id] = address
+	}
+	if len(addresses) == 0 {
+		return nil, errors.New("no peers would remain")
+	}
+	peers, err := parsePeers(addresses)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.prober != nil {
+		probeCtx, cancel := context.WithTimeout(ctx, updateProbeTimeout)
+		defer cancel()
+		for id := range set {
+			peer := peers[id]
+			if existing, exists := current[id]; exists && existing.Name == peer.Name && existing.Port == peer.Port {
+				continue
+			}
+			if err := s.prober.Probe(probeCtx, peer); err != nil {
+				return nil, errors.Wrapf(err, "peer %s is not reachable", peer.String())
+			}
+		}
+	}
+
+	s.mu.Lock()
+	s.peers = peers
+	s.mu.Unlock()
+
+	// Peers that have been set are treated as healthy until their next probe.
+	s.unhealthyMu.Lock()
+	for id := range set {
+		delete(s.unhealthy, id)
+	}
+	s.unhealthyMu.Unlock()
+
+	log.Info().Int("set", len(set)).Int("removed", len(remove)).Int("peers", len(peers)).Msg("Updated peers")
+
+	return s.All(), nil
+}
@@ -0,0 +1,159 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package static_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/attestantio/dirk/core"
+	staticpeers "github.com/attestantio/dirk/services/peers/static"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdate(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		set    map[uint64]string
+		remove []uint64
+		peers  map[uint64]string
+		err    string
+	}{
+		{
+			name: "Empty",
+			err:  "no changes specified",
+		},
+		{
+			name: "Add",
+			set:  map[uint64]string{4: "peer4:1004"},
+			peers: map[uint64]string{
+				1: "peer1:1001",
+				2: "peer2:1002",
+				3: "peer3:1003",
+				4: "peer4:1004",
+			},
+		},
+		{
+			name: "Replace",
+			set:  map[uint64]string{3: "peer3b:1003"},
+			peers: map[uint64]string{
+				1: "peer1:1001",
+				2: "peer2:1002",
+				3: "peer3b:1003",
+			},
+		},
+		{
+			name:   "Remove",
+			remove: []uint64{2},
+			peers: map[uint64]string{
+				1: "peer1:1001",
+				3: "peer3:1003",
+			},
+		},
+		{
+			name:   "RemoveUnknown",
+			remove: []uint64{5},
+			err:    "peer 5 is not known",
+		},
+		{
+			name:   "SetAndRemove",
+			set:    map[uint64]string{2: "peer2:1002"},
+			remove: []uint64{2},
+			err:    "peer 2 cannot be both set and removed",
+		},
+		{
+			name:   "RemoveAll",
+			remove: []uint64{1, 2, 3},
+			err:    "no peers would remain",
+		},
+		{
+			name: "Malformed",
+			set:  map[uint64]string{4: "peer4"},
+			err:  "malformed peer peer4",
+		},
+		{
+			name: "DuplicateName",
+			set:  map[uint64]string{4: "peer1:1004"},
+			err:  "duplicate peer name peer1",
+		},
+		{
+			name: "Unreachable",
+			set:  map[uint64]string{9: "peer9:1009"},
+			err:  "peer peer9:1009 is not reachable: unreachable",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			original := map[uint64]string{
+				1: "peer1:1001",
+				2: "peer2:1002",
+				3: "peer3:1003",
+			}
+			service, err := staticpeers.New(ctx,
+				staticpeers.WithPeers(original),
+				staticpeers.WithProber(&testProber{down: map[uint64]bool{9: true}}),
+			)
+			require.NoError(t, err)
+
+			peers, err := service.Update(ctx, test.set, test.remove)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+				// Peers are unchanged on failure.
+				require.Equal(t, endpoints(original), service.All())
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, endpoints(test.peers), peers)
+			require.Equal(t, endpoints(test.peers), service.All())
+		})
+	}
+}
+
+func TestUpdatePeersFile(t *testing.T) {
+	base, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(base)
+	file := filepath.Join(base, "peers.yml")
+	require.NoError(t, ioutil.WriteFile(file, []byte("1: peer1:1001\n"), 0600))
+
+	service, err := staticpeers.New(context.Background(), staticpeers.WithPeersFile(file))
+	require.NoError(t, err)
+	_, err = service.Update(context.Background(), map[uint64]string{2: "peer2:1002"}, nil)
+	require.EqualError(t, err, "peers are managed by a peers file")
+}
+
+func endpoints(peers map[uint64]string) map[uint64]*core.Endpoint {
+	res := make(map[uint64]*core.Endpoint, len(peers))
+	for id, address := range peers {
+		parts := strings.Split(address, ":")
+		port, err := strconv.ParseUint(parts[1], 10, 32)
+		if err != nil {
+			panic(err)
+		}
+		res[id] = &core.Endpoint{
+			ID:   id,
+			Name: parts[0],
+			Port: uint32(port),
+		}
+	}
+	return res
+}
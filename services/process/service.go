@@ -41,3 +41,40 @@ type Service interface {
 	// OnContribute is is called when we need to swap contributions with another participant.
 	OnContribute(ctx context.Context, sender uint64, account string, secret bls.SecretKey, vVec []bls.PublicKey) (bls.SecretKey, []bls.PublicKey, error)
 }
+
+// Resharer is the interface for a DKG process that can reshare existing
+// distributed accounts to a new set of participants and threshold, retaining
+// the composite public key.
+type Resharer interface {
+	// OnReshare is called when a request to reshare an existing distributed account is received.
+	OnReshare(ctx context.Context, credentials *checker.Credentials, account string, passphrase []byte, threshold uint32, numParticipants uint32) ([]byte, []*core.Endpoint, error)
+
+	// OnPrepareReshare is called when we receive a request from the given participant to prepare for resharing.
+	OnPrepareReshare(ctx context.Context, sender uint64, account string, passphrase []byte, threshold uint32, participants []*core.Endpoint, dealers []uint64, vVec []bls.PublicKey) error
+
+	// OnExecuteReshare is called when we receive a request from the given participant to send our contributions for resharing.
+	OnExecuteReshare(ctx context.Context, sender uint64, account string) error
+
+	// OnContributeReshare is called when we receive a contribution for resharing from the given dealer.
+	OnContributeReshare(ctx context.Context, sender uint64, account string, secret bls.SecretKey, vVec []bls.PublicKey) error
+
+	// OnConfirmReshare is called when we receive a request from the given participant to confirm our new share.
+	OnConfirmReshare(ctx context.Context, sender uint64, account string, confirmationData []byte) ([]byte, []byte, error)
+
+	// OnCommitReshare is called when we receive a request from the given participant to store our new share.
+	OnCommitReshare(ctx context.Context, sender uint64, account string) error
+
+	// OnCompleteReshare is called when we receive a request from the given participant to complete resharing.
+	OnCompleteReshare(ctx context.Context, sender uint64, account string) error
+
+	// OnAbortReshare is called when we receive a request from the given participant to abort resharing,
+	// restoring our existing share if it has already been replaced.
+	OnAbortReshare(ctx context.Context, sender uint64, account string) error
+}
+
+// PathedGenerator is the interface for a DKG process that can generate local
+// accounts at a given hierarchical deterministic path.
+type PathedGenerator interface {
+	// OnGeneratePathed is called when a request to generate a new key at the given path is received.
+	OnGeneratePathed(ctx context.Context, credentials *checker.Credentials, account string, passphrase []byte, path string) ([]byte, error)
+}
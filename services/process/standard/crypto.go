@@ -14,8 +14,12 @@
 package standard
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"strings"
 
+	"github.com/attestantio/dirk/core"
 	"github.com/attestantio/dirk/util"
 	"github.com/herumi/bls-eth-go-binary/bls"
 	"github.com/pkg/errors"
@@ -107,6 +111,20 @@ func (s *Service) contribution(ctx context.Context, generation *generation) erro
 // 	return nil
 // }
 
+// verifyShare verifies a share received from a peer against the commitments
+// in the verification vector that the peer supplied with it.  The verification
+// vector must commit to a polynomial of the expected degree, and the share must
+// be the evaluation of that polynomial at our ID.  A failure is reported as
+// coming from the peer.
+func (s *Service) verifyShare(id uint64, threshold uint32, peer uint64, secretShare bls.SecretKey, vVec []bls.PublicKey) error {
+	if len(vVec) == int(threshold) && verifyContribution(id, secretShare, vVec) {
+		return nil
+	}
+	s.monitor.DistributedShareVerificationFailed(peer)
+	log.Warn().Uint64("peer_id", peer).Int("commitments", len(vVec)).Uint32("threshold", threshold).Msg("Share from peer failed verification against its commitments")
+	return fmt.Errorf("share from peer %d failed verification against its commitments", peer)
+}
+
 // verifyContribution verifies another participant's contribution.
 func verifyContribution(id uint64, secretShare bls.SecretKey, vVec []bls.PublicKey) bool {
 	var vVecKey bls.PublicKey
@@ -115,3 +133,57 @@ func verifyContribution(id uint64, secretShare bls.SecretKey, vVec []bls.PublicK
 	}
 	return secretShare.GetPublicKey().IsEqual(&vVecKey)
 }
+
+// verifyConfirmations verifies that all participants hold shares of the composite public key,
+// and that every consecutive group of threshold participants can create a composite signature
+// that verifies against it.  A failure to aggregate is reported against the operation, and
+// the returned error identifies the participants in the group that failed.
+func (s *Service) verifyConfirmations(operation string, pubKey []byte, pubKeys [][]byte, confirmationSigs [][]byte, participants []*core.Endpoint, threshold uint32, confirmationData []byte) error {
+	err := verifyConfirmations(pubKey, pubKeys, confirmationSigs, participants, threshold, confirmationData)
+	if err != nil {
+		s.monitor.DistributedAggregationVerificationFailed(operation)
+	}
+	return err
+}
+
+func verifyConfirmations(pubKey []byte, pubKeys [][]byte, confirmationSigs [][]byte, participants []*core.Endpoint, threshold uint32, confirmationData []byte) error {
+	for i := range pubKeys {
+		if !bytes.Equal(pubKeys[i], pubKey) {
+			return fmt.Errorf("participant %d returned a different public key", participants[i].ID)
+		}
+	}
+
+	compositeKey := bls.PublicKey{}
+	if err := compositeKey.Deserialize(pubKey); err != nil {
+		return errors.Wrap(err, "failed to deserialize public key")
+	}
+	ids := make([]bls.ID, threshold)
+	sigs := make([]bls.Sign, threshold)
+	compositeSig := bls.Sign{}
+	for i := 0; i < len(participants)+1-int(threshold); i++ {
+		quorum := participants[i : i+int(threshold)]
+		for j := range quorum {
+			ids[j] = *util.BLSID(quorum[j].ID)
+			sigs[j] = bls.Sign{}
+			if err := sigs[j].Deserialize(confirmationSigs[i+j]); err != nil {
+				return errors.Wrapf(err, "failed to deserialize confirmation signature from participant %d", quorum[j].ID)
+			}
+		}
+		if err := compositeSig.Recover(sigs, ids); err != nil {
+			return errors.Wrapf(err, "failed to recover composite signature from participants %s", participantIDs(quorum))
+		}
+		if !compositeSig.VerifyByte(&compositeKey, confirmationData) {
+			return fmt.Errorf("composite signature from participants %s failed verification", participantIDs(quorum))
+		}
+	}
+	return nil
+}
+
+// participantIDs returns a printable list of the IDs of the given participants.
+func participantIDs(participants []*core.Endpoint) string {
+	ids := make([]string, len(participants))
+	for i := range participants {
+		ids[i] = fmt.Sprintf("%d", participants[i].ID)
+	}
+	return strings.Join(ids, ", ")
+}
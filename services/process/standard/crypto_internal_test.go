@@ -0,0 +1,112 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard
+
+import (
+	"testing"
+	"time"
+
+	"github.com/attestantio/dirk/core"
+	"github.com/attestantio/dirk/util"
+	"github.com/herumi/bls-eth-go-binary/bls"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyConfirmations(t *testing.T) {
+	participants := []*core.Endpoint{
+		{ID: 1, Name: "signer-test01", Port: 8881},
+		{ID: 2, Name: "signer-test02", Port: 8882},
+		{ID: 3, Name: "signer-test03", Port: 8883},
+	}
+	threshold := uint32(2)
+	confirmationData := []byte("confirmation data")
+
+	// Create shares of a composite key.
+	msk := make([]bls.SecretKey, threshold)
+	for i := range msk {
+		msk[i].SetByCSPRNG()
+	}
+	pubKey := msk[0].GetPublicKey().Serialize()
+	pubKeys := make([][]byte, len(participants))
+	confirmationSigs := make([][]byte, len(participants))
+	for i := range participants {
+		var share bls.SecretKey
+		require.NoError(t, share.Set(msk, util.BLSID(participants[i].ID)))
+		pubKeys[i] = pubKey
+		confirmationSigs[i] = share.SignByte(confirmationData).Serialize()
+	}
+
+	// A share that is not part of the composite key.
+	var badShare bls.SecretKey
+	badShare.SetByCSPRNG()
+	badSigs := [][]byte{confirmationSigs[0], confirmationSigs[1], badShare.SignByte(confirmationData).Serialize()}
+
+	tests := []struct {
+		name             string
+		pubKeys          [][]byte
+		confirmationSigs [][]byte
+		err              string
+	}{
+		{
+			name:             "Good",
+			pubKeys:          pubKeys,
+			confirmationSigs: confirmationSigs,
+		},
+		{
+			name:             "PubKeyMismatch",
+			pubKeys:          [][]byte{pubKey, pubKey, badShare.GetPublicKey().Serialize()},
+			confirmationSigs: confirmationSigs,
+			err:              "participant 3 returned a different public key",
+		},
+		{
+			name:             "SignatureInvalid",
+			pubKeys:          pubKeys,
+			confirmationSigs: [][]byte{confirmationSigs[0], []byte{0x01}, confirmationSigs[2]},
+			err:              "failed to deserialize confirmation signature from participant 2: err blsSignatureDeserialize 01",
+		},
+		{
+			name:             "BadShare",
+			pubKeys:          pubKeys,
+			confirmationSigs: badSigs,
+			err:              "composite signature from participants 2, 3 failed verification",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			monitor := &aggregationMonitor{}
+			s := &Service{monitor: monitor}
+			err := s.verifyConfirmations("generate", pubKey, test.pubKeys, test.confirmationSigs, participants, threshold, confirmationData)
+			if test.err == "" {
+				require.NoError(t, err)
+				require.Empty(t, monitor.failures)
+			} else {
+				require.EqualError(t, err, test.err)
+				require.Equal(t, []string{"generate"}, monitor.failures)
+			}
+		})
+	}
+}
+
+type aggregationMonitor struct {
+	noopMonitor
+	failures []string
+}
+
+func (m *aggregationMonitor) DistributedAggregationVerificationFailed(operation string) {
+	m.failures = append(m.failures, operation)
+}
+
+func (m *aggregationMonitor) DistributedGenerationPhaseCompleted(started time.Time, phase string, succeeded bool) {
+}
@@ -14,19 +14,17 @@
 package standard
 
 import (
-	"bytes"
 	"context"
 	"crypto/rand"
 	"fmt"
+	"sort"
 
 	"github.com/attestantio/dirk/core"
 	"github.com/attestantio/dirk/services/checker"
 	"github.com/attestantio/dirk/services/ruler"
-	"github.com/attestantio/dirk/util"
-	"github.com/herumi/bls-eth-go-binary/bls"
 	"github.com/opentracing/opentracing-go"
 	"github.com/pkg/errors"
-	wallet "github.com/wealdtech/go-eth2-wallet"
+	e2wallet "github.com/wealdtech/go-eth2-wallet"
 	e2wtypes "github.com/wealdtech/go-eth2-wallet-types/v2"
 )
 
@@ -52,41 +50,16 @@ func (s *Service) OnGenerate(ctx context.Context,
 		return nil, nil, errors.New("signing threshold too low")
 	}
 
-	log := log.With().Str("account", account).Logger()
-	// Ensure we don't already have this account.
-	walletName, accountName, err := wallet.WalletAndAccountNames(account)
-	if err != nil {
-		log.Warn().Msg("Invalid account supplied")
-		return nil, nil, errors.Wrap(err, "invalid account")
-	}
-	wallet, err := wallet.OpenWallet(walletName, wallet.WithStore(s.stores[0]))
+	wallet, accountName, err := s.walletForGeneration(ctx, credentials, account, numParticipants == 1)
 	if err != nil {
-		log.Warn().Err(err).Msg("Unknown wallet supplied")
-		return nil, nil, errors.Wrap(err, "unknown wallet")
-	}
-	accountByNameProvider, isProvider := wallet.(e2wtypes.WalletAccountByNameProvider)
-	if !isProvider {
-		log.Error().Msg("Wallet does not support fetching accounts by name")
-		return nil, nil, errors.New("wallet does not support fetching accounts by name")
-	}
-	_, err = accountByNameProvider.AccountByName(ctx, accountName)
-	if err == nil {
-		log.Error().Err(err).Msg("Account already exists")
-		return nil, nil, errors.New("account already exists")
+		return nil, nil, err
 	}
 
-	checkRes := s.checkAccess(ctx, credentials, account, ruler.ActionCreateAccount)
-	if checkRes != core.ResultSucceeded {
-		log.Debug().Msg("Create refused")
-		return nil, nil, errors.New("failed rules check")
-	}
-	log.Trace().Msg("Create allowed")
-
 	if numParticipants == 1 {
 		// Only 1 participant means we are generating a standard account.
-		pubKey, err := s.generate(ctx, credentials, wallet, accountName, passphrase)
+		pubKey, err := s.generate(ctx, credentials, wallet, accountName, passphrase, "")
 		if err != nil {
-			log.Error().Err(err).Msg("Failed to generate account")
+			log.Error().Err(err).Str("account", account).Msg("Failed to generate account")
 			return nil, nil, errors.New("failed account generation")
 		}
 		return pubKey, nil, err
@@ -94,7 +67,9 @@ func (s *Service) OnGenerate(ctx context.Context,
 	return s.generateDistributed(ctx, credentials, wallet, account, passphrase, signingThreshold, numParticipants)
 }
 
-func (s *Service) generate(ctx context.Context, credentials *checker.Credentials, wallet e2wtypes.Wallet, accountName string, passphrase []byte) ([]byte, error) {
+// generate generates a local account.  If path is supplied the account is created at that path,
+// which requires a hierarchical deterministic wallet.
+func (s *Service) generate(ctx context.Context, credentials *checker.Credentials, wallet e2wtypes.Wallet, accountName string, passphrase []byte, path string) ([]byte, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "services.process.generate")
 	defer span.Finish()
 
@@ -109,6 +84,10 @@ func (s *Service) generate(ctx context.Context, credentials *checker.Credentials
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to unlock wallet")
 		}
+		if !unlocked && len(s.generationWalletPassphrase) > 0 {
+			// Wallets created for generation use the generation wallet passphrase.
+			unlocked = locker.Unlock(ctx, s.generationWalletPassphrase) == nil
+		}
 		if !unlocked {
 			return nil, errors.New("failed to unlock wallet with known passphrases")
 		}
@@ -119,7 +98,16 @@ func (s *Service) generate(ctx context.Context, credentials *checker.Credentials
 		}()
 	}
 
-	createdAccount, err := wallet.(e2wtypes.WalletAccountCreator).CreateAccount(ctx, accountName, passphrase)
+	var createdAccount e2wtypes.Account
+	var err error
+	switch {
+	case path != "":
+		createdAccount, err = s.createAccountAtPath(ctx, wallet, path, accountName, passphrase)
+	case wallet.Type() == walletTypeHD && s.generationPathTemplate != "":
+		createdAccount, err = s.createPathedAccount(ctx, wallet, accountName, passphrase)
+	default:
+		createdAccount, err = wallet.(e2wtypes.WalletAccountCreator).CreateAccount(ctx, accountName, passphrase)
+	}
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create account")
 	}
@@ -128,6 +116,7 @@ func (s *Service) generate(ctx context.Context, credentials *checker.Credentials
 		// Warn but do not propagate this error.
 		log.Warn().Err(err).Msg("Failed to add account to internal cache, will be unavailable until restart")
 	}
+	s.notifyAccountCreated(ctx, wallet, createdAccount)
 
 	return createdAccount.PublicKey().Marshal(), nil
 }
@@ -135,35 +124,79 @@ func (s *Service) generate(ctx context.Context, credentials *checker.Credentials
 func (s *Service) generateDistributed(ctx context.Context, credentials *checker.Credentials, wallet e2wtypes.Wallet, account string, passphrase []byte, signingThreshold uint32, numParticipants uint32) ([]byte, []*core.Endpoint, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "services.process.generateDistributed")
 	defer span.Finish()
+	ctx, cancel := context.WithTimeout(ctx, s.operationTimeout)
+	defer cancel()
 
 	if wallet.Type() != "distributed" {
 		log.Error().Msg("Incorrect wallet type to generate distributed key")
 		return nil, nil, errors.New("wallet does not support distributed generation")
 	}
 
-	participants, err := s.peersSvc.Suitable(numParticipants)
-	if err != nil {
-		log.Error().Err(err).Msg("Failed to select suitable participants")
-		return nil, nil, errors.New("no suitable participants")
+	// Peers that fail to respond in a round are excluded from any retry.
+	unresponsive := make([]*core.Endpoint, 0)
+	excluded := make(map[uint64]bool)
+	for attempt := uint32(0); ; attempt++ {
+		participants, err := s.generationParticipants(numParticipants, excluded)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to select suitable participants")
+			if len(unresponsive) > 0 {
+				return nil, nil, fmt.Errorf("no suitable participants (peers that failed to respond: %s)", participantIDs(unresponsive))
+			}
+			return nil, nil, errors.New("no suitable participants")
+		}
+
+		pubKey, peer, err := s.generateDistributedAttempt(ctx, account, passphrase, signingThreshold, participants)
+		if err == nil {
+			return pubKey, participants, nil
+		}
+		if peer == nil {
+			return nil, nil, err
+		}
+		unresponsive = append(unresponsive, peer)
+		if attempt == s.dkgMaxRetries || ctx.Err() != nil {
+			return nil, nil, fmt.Errorf("%v (peers that failed to respond: %s)", err, participantIDs(unresponsive))
+		}
+		excluded[peer.ID] = true
+		log.Warn().Uint64("peer_id", peer.ID).Uint32("attempt", attempt+1).Msg("Retrying generation without peer that failed to respond")
 	}
+}
 
+// generateDistributedAttempt makes a single attempt to generate a distributed account with
+// the given participants.  Each round of the generation is bounded by the round timeout; if
+// a participant fails to respond within it the generation is aborted on all participants and
+// the participant is returned along with the error.
+func (s *Service) generateDistributedAttempt(ctx context.Context, account string, passphrase []byte, signingThreshold uint32, participants []*core.Endpoint) ([]byte, *core.Endpoint, error) {
 	// Send prepare request to all participants.
+	roundCtx, cancel := s.roundContext(ctx)
+	defer cancel()
+	phaseCtx, finishPhase := s.startPhase(roundCtx, phasePrepare)
 	for _, participant := range participants {
 		log.Trace().Str("endpoint", participant.String()).Msg("Sending prepare request to endpoint")
-		if err := s.senderSvc.Prepare(ctx, participant, account, passphrase, signingThreshold, participants); err != nil {
+		if err := s.senderSvc.Prepare(phaseCtx, participant, account, passphrase, signingThreshold, participants); err != nil {
+			finishPhase(err)
+			s.peerCallFailed(roundCtx, "prepare", participant, err)
+			s.abortPeers(roundCtx, account, participants)
 			log.Error().Err(err).Str("endpoint", participant.String()).Msg("Failed to prepare on endpoint")
-			return nil, nil, errors.Wrap(err, "failed to prepare endpoints")
+			return nil, unresponsivePeer(roundCtx, participant), errors.Wrap(err, "failed to prepare endpoints")
 		}
 	}
+	finishPhase(nil)
 
 	// Send execute request to all participants.
+	roundCtx, cancel = s.roundContext(ctx)
+	defer cancel()
+	phaseCtx, finishPhase = s.startPhase(roundCtx, phaseExecute)
 	for _, participant := range participants {
 		log.Trace().Str("endpoint", participant.String()).Msg("Sending execute request to endpoint")
-		if err := s.senderSvc.Execute(ctx, participant, account); err != nil {
+		if err := s.senderSvc.Execute(phaseCtx, participant, account); err != nil {
+			finishPhase(err)
+			s.peerCallFailed(roundCtx, "execute", participant, err)
+			s.abortPeers(roundCtx, account, participants)
 			log.Error().Err(err).Str("endpoint", participant.String()).Msg("Failed to execute on endpoint")
-			return nil, nil, errors.Wrap(err, "failed to execute generation")
+			return nil, unresponsivePeer(roundCtx, participant), errors.Wrap(err, "failed to execute generation")
 		}
 	}
+	finishPhase(nil)
 
 	// Send commit request to all endpoints.
 	pubKeys := make([][]byte, len(participants))
@@ -177,58 +210,140 @@ func (s *Service) generateDistributed(ctx context.Context, credentials *checker.
 		return nil, nil, errors.New("failed to generate enough commit data")
 	}
 	confirmationSigs := make([][]byte, len(participants))
+	roundCtx, cancel = s.roundContext(ctx)
+	defer cancel()
+	phaseCtx, finishPhase = s.startPhase(roundCtx, phaseCommit)
 	for i, participant := range participants {
 		log.Trace().Str("endpoint", participant.String()).Msg("Sending commit request to endpoint")
-		pubKeys[i], confirmationSigs[i], err = s.senderSvc.Commit(ctx, participant, account, confirmationData)
+		pubKeys[i], confirmationSigs[i], err = s.senderSvc.Commit(phaseCtx, participant, account, confirmationData)
 		if err != nil {
+			finishPhase(err)
+			s.peerCallFailed(roundCtx, "commit", participant, err)
+			s.abortPeers(roundCtx, account, participants)
 			log.Error().Err(err).Str("endpoint", participant.String()).Msg("Failed to commit on endpoint")
-			return nil, nil, errors.Wrap(err, "failed to complete generation")
+			return nil, unresponsivePeer(roundCtx, participant), errors.Wrap(err, "failed to complete generation")
 		}
 		if len(pubKeys[i]) == 0 {
 			log.Error().Uint64("participant", participant.ID).Msg("Received empty public key from participant on commit")
+			finishPhase(errors.New("empty public key"))
 			return nil, nil, errors.New("failed to complete generation")
 		}
 		if len(confirmationSigs[i]) == 0 {
 			log.Error().Uint64("participant", participant.ID).Msg("Received empty confirmation signature from participant on commit")
+			finishPhase(errors.New("empty confirmation signature"))
 			return nil, nil, errors.New("failed to complete generation")
 		}
 	}
+	finishPhase(nil)
+
+	_, finishPhase = s.startPhase(ctx, phaseConfirmation)
+	err = s.verifyConfirmations("generate", pubKeys[0], pubKeys, confirmationSigs, participants, signingThreshold, confirmationData)
+	finishPhase(err)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to verify generation")
+		return nil, nil, errors.New("Invalid generation")
+	}
+
+	log.Trace().Str("account", account).Str("pubKey", fmt.Sprintf("%x", pubKeys[0])).Msg("Generated account")
+	return pubKeys[0], nil, nil
+}
 
-	for i := range pubKeys {
-		if !bytes.Equal(pubKeys[i], pubKeys[(i+1)%len(pubKeys)]) {
-			log.Error().Msg("pubkey mismatch")
-			return nil, nil, errors.New("Invalid generation")
+// generationParticipants selects the participants for a distributed generation.  Peers that
+// have been excluded, having failed to respond to an earlier attempt, are not selected.
+func (s *Service) generationParticipants(numParticipants uint32, excluded map[uint64]bool) ([]*core.Endpoint, error) {
+	if len(excluded) == 0 {
+		return s.peersSvc.Suitable(numParticipants)
+	}
+	peers := s.peersSvc.All()
+	ids := make([]uint64, 0, len(peers))
+	for id := range peers {
+		if !excluded[id] && s.peersSvc.Healthy(id) {
+			ids = append(ids, id)
 		}
 	}
+	if len(ids) < int(numParticipants) {
+		return nil, errors.New("not enough suitable peers")
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	participants := make([]*core.Endpoint, numParticipants)
+	for i := range participants {
+		participants[i] = peers[ids[i]]
+	}
+	return participants, nil
+}
 
-	// Check composite signatures.
-	ids := make([]bls.ID, signingThreshold)
-	sigs := make([]bls.Sign, signingThreshold)
-	pubKey := bls.PublicKey{}
-	if err := pubKey.Deserialize(pubKeys[0]); err != nil {
-		log.Error().Err(err).Msg("Failed to deserialize public key")
-		return nil, nil, errors.New("Invalid generation")
+// walletForGeneration checks that the given account can be generated, returning the wallet in which
+// to generate it and the name of the account within the wallet.  If local is true and the wallet does
+// not exist it is created, if generation wallets are configured.
+func (s *Service) walletForGeneration(ctx context.Context, credentials *checker.Credentials, account string, local bool) (e2wtypes.Wallet, string, error) {
+	log := log.With().Str("account", account).Logger()
+	// Ensure we don't already have this account.
+	walletName, accountName, err := e2wallet.WalletAndAccountNames(account)
+	if err != nil {
+		log.Warn().Msg("Invalid account supplied")
+		return nil, "", errors.Wrap(err, "invalid account")
 	}
-	compositeSig := bls.Sign{}
-	for i := 0; i < len(participants)+1-int(signingThreshold); i++ {
-		for j := 0; j < int(signingThreshold); j++ {
-			ids[j] = *util.BLSID(participants[i+j].ID)
-			sigs[j] = bls.Sign{}
-			if err := sigs[j].Deserialize(confirmationSigs[i+j]); err != nil {
-				log.Error().Err(err).Msg("Failed to deserialize confirmation signature")
-				return nil, nil, errors.New("Invalid generation")
-			}
+	wallet, err := e2wallet.OpenWallet(walletName, e2wallet.WithStore(s.stores[0]))
+	if err != nil {
+		if s.generationWalletType == "" || !local {
+			log.Warn().Err(err).Msg("Unknown wallet supplied")
+			return nil, "", errors.Wrap(err, "unknown wallet")
 		}
-		if err := compositeSig.Recover(sigs, ids); err != nil {
-			log.Error().Err(err).Msg("Failed to recover composite signature")
-			return nil, nil, errors.New("Invalid generation")
+		// The wallet will be created once access has been checked.
+		log.Trace().Msg("Wallet not found; will create")
+		wallet = nil
+	} else {
+		accountByNameProvider, isProvider := wallet.(e2wtypes.WalletAccountByNameProvider)
+		if !isProvider {
+			log.Error().Msg("Wallet does not support fetching accounts by name")
+			return nil, "", errors.New("wallet does not support fetching accounts by name")
 		}
-		if !compositeSig.VerifyByte(&pubKey, confirmationData) {
-			log.Error().Err(err).Msg("Failed to confirm composite signature")
-			return nil, nil, errors.New("Invalid generation")
+		_, err = accountByNameProvider.AccountByName(ctx, accountName)
+		if err == nil {
+			log.Error().Err(err).Msg("Account already exists")
+			return nil, "", errors.New("account already exists")
 		}
 	}
 
-	log.Trace().Str("account", account).Str("pubKey", fmt.Sprintf("%x", pubKeys[0])).Msg("Generated account")
-	return pubKeys[0], participants, nil
+	checkRes := s.checkAccess(ctx, credentials, account, ruler.ActionCreateAccount)
+	if checkRes != core.ResultSucceeded {
+		log.Debug().Msg("Create refused")
+		return nil, "", errors.New("failed rules check")
+	}
+	log.Trace().Msg("Create allowed")
+
+	if wallet == nil {
+		wallet, err = s.createWallet(ctx, walletName)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to create wallet")
+			return nil, "", errors.New("failed wallet creation")
+		}
+	}
+
+	return wallet, accountName, nil
+
+}
+
+// OnGeneratePathed is called when a request to generate a new key at a given path is received.
+func (s *Service) OnGeneratePathed(ctx context.Context,
+	credentials *checker.Credentials,
+	account string,
+	passphrase []byte,
+	path string,
+) ([]byte, error) {
+	if path == "" {
+		return nil, errors.New("no path supplied")
+	}
+
+	wallet, accountName, err := s.walletForGeneration(ctx, credentials, account, true)
+	if err != nil {
+		return nil, err
+	}
+
+	pubKey, err := s.generate(ctx, credentials, wallet, accountName, passphrase, path)
+	if err != nil {
+		log.Error().Err(err).Str("account", account).Str("path", path).Msg("Failed to generate account")
+		return nil, errors.New("failed account generation")
+	}
+	return pubKey, nil
 }
@@ -0,0 +1,72 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard_test
+
+import (
+	context "context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/attestantio/dirk/services/checker"
+	"github.com/attestantio/dirk/testing/mock"
+	"github.com/stretchr/testify/require"
+	distributed "github.com/wealdtech/go-eth2-wallet-distributed"
+	keystorev4 "github.com/wealdtech/go-eth2-wallet-encryptor-keystorev4"
+	filesystem "github.com/wealdtech/go-eth2-wallet-store-filesystem"
+	indexer "github.com/wealdtech/go-indexer"
+)
+
+// TestConcurrentGeneration ensures that concurrent distributed generations in the same wallet
+// are all stored.
+func TestConcurrentGeneration(t *testing.T) {
+	ctx := context.Background()
+	base, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(base)
+	createResharingServices(ctx, t, base)
+
+	credentials := &checker.Credentials{Client: "client1"}
+	accounts := 8
+	var wg sync.WaitGroup
+	errs := make([]error, accounts)
+	for i := 0; i < accounts; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, _, errs[i] = mock.Processes[1].OnGenerate(ctx, credentials, fmt.Sprintf("Test/Account %d", i), []byte(reshareAccountPassphrase), 3, 5)
+		}(i)
+	}
+	wg.Wait()
+	for i := range errs {
+		require.NoError(t, errs[i])
+	}
+
+	// Read the wallet indices from their stores, to confirm that every account made it in to each.
+	for _, endpoint := range reshareEndpoints {
+		store := filesystem.New(filesystem.WithLocation(filepath.Join(base, fmt.Sprintf("%d", endpoint.ID))))
+		wallet, err := distributed.OpenWallet(ctx, "Test", store, keystorev4.New())
+		require.NoError(t, err)
+		data, err := store.RetrieveAccountsIndex(wallet.ID())
+		require.NoError(t, err)
+		index, err := indexer.Deserialize(data)
+		require.NoError(t, err)
+		for i := 0; i < accounts; i++ {
+			require.True(t, index.NameKnown(fmt.Sprintf("Account %d", i)), fmt.Sprintf("account %d missing from index of peer %d", i, endpoint.ID))
+		}
+	}
+}
@@ -0,0 +1,188 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/attestantio/dirk/services/fetcher"
+	"github.com/pkg/errors"
+	bip39 "github.com/tyler-smith/go-bip39"
+	hd "github.com/wealdtech/go-eth2-wallet-hd/v2"
+	nd "github.com/wealdtech/go-eth2-wallet-nd/v2"
+	e2wtypes "github.com/wealdtech/go-eth2-wallet-types/v2"
+)
+
+const (
+	// walletTypeND is the type of non-deterministic wallets.
+	walletTypeND = "non-deterministic"
+	// walletTypeHD is the type of hierarchical deterministic wallets.
+	walletTypeHD = "hierarchical deterministic"
+
+	// pathIndex is the placeholder for the account index in a path template.
+	pathIndex = "{index}"
+	// defaultPathTemplate is the EIP-2334 path for validator signing keys.
+	defaultPathTemplate = "m/12381/3600/{index}/0/0"
+)
+
+// parseWalletType parses a wallet type, accepting common abbreviations.
+func parseWalletType(input string) (string, error) {
+	switch strings.ToLower(input) {
+	case "":
+		return "", nil
+	case "nd", "non-deterministic":
+		return walletTypeND, nil
+	case "hd", "hierarchical deterministic":
+		return walletTypeHD, nil
+	default:
+		return "", fmt.Errorf("unsupported wallet type %q", input)
+	}
+}
+
+// validatePathTemplate ensures that a path template generates valid and distinct EIP-2334 paths.
+func validatePathTemplate(template string) error {
+	components := strings.Split(template, "/")
+	if len(components) < 3 || components[0] != "m" || components[1] != "12381" {
+		return errors.New("path must start with m/12381/")
+	}
+	indices := 0
+	for i := 1; i < len(components); i++ {
+		if components[i] == pathIndex {
+			indices++
+			continue
+		}
+		if _, err := strconv.ParseUint(components[i], 10, 32); err != nil {
+			return fmt.Errorf("invalid component %q", components[i])
+		}
+	}
+	if indices != 1 {
+		return fmt.Errorf("path must contain %s exactly once as a component", pathIndex)
+	}
+	return nil
+}
+
+// pathForIndex returns the path for the account at the given index.
+func pathForIndex(template string, index uint32) string {
+	return strings.Replace(template, pathIndex, strconv.FormatUint(uint64(index), 10), 1)
+}
+
+// parseSeed parses a seed, supplied either as a BIP-39 mnemonic or as a hex string.
+func parseSeed(input []byte) ([]byte, error) {
+	input = bytes.TrimSpace(input)
+	if len(input) == 0 {
+		return nil, errors.New("no seed supplied")
+	}
+	if bytes.HasPrefix(input, []byte("0x")) {
+		seed, err := hex.DecodeString(string(input[2:]))
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid hex seed")
+		}
+		if len(seed) != 64 {
+			return nil, errors.New("hex seed must be 64 bytes")
+		}
+		return seed, nil
+	}
+	// Normalise whitespace in the mnemonic.
+	mnemonic := strings.Join(strings.Fields(string(input)), " ")
+	seed, err := bip39.NewSeedWithErrorChecking(mnemonic, "")
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid mnemonic")
+	}
+	return seed, nil
+}
+
+// createWallet creates a wallet of the configured generation type.
+func (s *Service) createWallet(ctx context.Context, name string) (e2wtypes.Wallet, error) {
+	var wallet e2wtypes.Wallet
+	var err error
+	switch s.generationWalletType {
+	case walletTypeND:
+		wallet, err = nd.CreateWallet(ctx, name, s.stores[0], s.encryptor)
+	case walletTypeHD:
+		wallet, err = hd.CreateWallet(ctx, name, s.generationWalletPassphrase, s.stores[0], s.encryptor, s.generationSeed)
+	default:
+		return nil, errors.New("no wallet type for generation")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if adder, isAdder := s.fetcherSvc.(fetcher.WalletAdder); isAdder {
+		if err := adder.AddWallet(ctx, wallet, s.stores[0]); err != nil {
+			return nil, errors.Wrap(err, "failed to add wallet to fetcher")
+		}
+	}
+	log.Info().Str("wallet", name).Str("type", wallet.Type()).Msg("Created wallet for generation")
+
+	return wallet, nil
+}
+
+// createPathedAccount creates an account in a hierarchical deterministic wallet at the
+// first index of the path template that is not already in use.
+func (s *Service) createPathedAccount(ctx context.Context, wallet e2wtypes.Wallet, accountName string, passphrase []byte) (e2wtypes.Account, error) {
+	creator, isCreator := wallet.(e2wtypes.WalletPathedAccountCreator)
+	if !isCreator {
+		return nil, errors.New("wallet does not support creating accounts with paths")
+	}
+
+	// Serialise creation, to avoid concurrent requests selecting the same index.
+	s.pathedAccountMu.Lock()
+	defer s.pathedAccountMu.Unlock()
+
+	usedPaths := make(map[string]bool)
+	for account := range wallet.Accounts(ctx) {
+		if pathProvider, isProvider := account.(e2wtypes.AccountPathProvider); isProvider {
+			usedPaths[pathProvider.Path()] = true
+		}
+	}
+	var path string
+	for index := uint32(0); ; index++ {
+		path = pathForIndex(s.generationPathTemplate, index)
+		if !usedPaths[path] {
+			break
+		}
+		if index == ^uint32(0) {
+			return nil, errors.New("no free index available in path")
+		}
+	}
+	log.Trace().Str("path", path).Msg("Selected path for account")
+
+	return creator.CreatePathedAccount(ctx, path, accountName, passphrase)
+}
+
+// createAccountAtPath creates an account in a hierarchical deterministic wallet at the given path.
+func (s *Service) createAccountAtPath(ctx context.Context, wallet e2wtypes.Wallet, path string, accountName string, passphrase []byte) (e2wtypes.Account, error) {
+	creator, isCreator := wallet.(e2wtypes.WalletPathedAccountCreator)
+	if !isCreator {
+		return nil, errors.New("wallet does not support creating accounts with paths")
+	}
+
+	// Serialise creation, to avoid concurrent requests using the same path.
+	s.pathedAccountMu.Lock()
+	defer s.pathedAccountMu.Unlock()
+
+	for account := range wallet.Accounts(ctx) {
+		if pathProvider, isProvider := account.(e2wtypes.AccountPathProvider); isProvider && pathProvider.Path() == path {
+			return nil, fmt.Errorf("path %s already in use", path)
+		}
+	}
+	log.Trace().Str("path", path).Msg("Using supplied path for account")
+
+	return creator.CreatePathedAccount(ctx, path, accountName, passphrase)
+}
@@ -0,0 +1,179 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard_test
+
+import (
+	context "context"
+	"testing"
+
+	"github.com/attestantio/dirk/services/checker"
+	mockchecker "github.com/attestantio/dirk/services/checker/mock"
+	memfetcher "github.com/attestantio/dirk/services/fetcher/mem"
+	staticpeers "github.com/attestantio/dirk/services/peers/static"
+	standardprocess "github.com/attestantio/dirk/services/process/standard"
+	sendermock "github.com/attestantio/dirk/services/sender/mock"
+	localunlocker "github.com/attestantio/dirk/services/unlocker/local"
+	"github.com/stretchr/testify/require"
+	bip39 "github.com/tyler-smith/go-bip39"
+	util "github.com/wealdtech/go-eth2-util"
+	keystorev4 "github.com/wealdtech/go-eth2-wallet-encryptor-keystorev4"
+	scratch "github.com/wealdtech/go-eth2-wallet-store-scratch"
+	e2wtypes "github.com/wealdtech/go-eth2-wallet-types/v2"
+)
+
+const testMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon art"
+
+func TestGenerateHD(t *testing.T) {
+	ctx := context.Background()
+
+	stores := []e2wtypes.Store{scratch.New()}
+	peers, err := staticpeers.New(ctx,
+		staticpeers.WithPeers(map[uint64]string{1: "signer-test01:8881"}),
+	)
+	require.NoError(t, err)
+	checkerSvc, err := mockchecker.New()
+	require.NoError(t, err)
+	unlockerSvc, err := localunlocker.New(ctx)
+	require.NoError(t, err)
+	fetcherSvc, err := memfetcher.New(ctx,
+		memfetcher.WithStores(stores),
+		memfetcher.WithEncryptor(keystorev4.New()),
+	)
+	require.NoError(t, err)
+
+	params := []standardprocess.Parameter{
+		standardprocess.WithChecker(checkerSvc),
+		standardprocess.WithID(1),
+		standardprocess.WithPeers(peers),
+		standardprocess.WithSender(sendermock.New(1)),
+		standardprocess.WithFetcher(fetcherSvc),
+		standardprocess.WithStores(stores),
+		standardprocess.WithUnlocker(unlockerSvc),
+	}
+
+	// Check parameters.
+	_, err = standardprocess.New(ctx, append(params,
+		standardprocess.WithGenerationWalletType("bad"),
+	)...)
+	require.EqualError(t, err, `problem with parameters: unsupported wallet type "bad"`)
+	_, err = standardprocess.New(ctx, append(params,
+		standardprocess.WithGenerationWalletType("hd"),
+		standardprocess.WithGenerationWalletPassphrase([]byte("wallet secret")),
+	)...)
+	require.EqualError(t, err, "problem with parameters: no generation seed specified for hierarchical deterministic wallets")
+	_, err = standardprocess.New(ctx, append(params,
+		standardprocess.WithGenerationWalletType("hd"),
+		standardprocess.WithGenerationSeed([]byte(testMnemonic)),
+	)...)
+	require.EqualError(t, err, "problem with parameters: no generation wallet passphrase specified for hierarchical deterministic wallets")
+	_, err = standardprocess.New(ctx, append(params,
+		standardprocess.WithGenerationPathTemplate("m/12381/3600/0/0"),
+	)...)
+	require.EqualError(t, err, "problem with parameters: invalid generation path template: path must contain {index} exactly once as a component")
+	_, err = standardprocess.New(ctx, append(params,
+		standardprocess.WithGenerationPathTemplate("m/44/{index}"),
+	)...)
+	require.EqualError(t, err, "problem with parameters: invalid generation path template: path must start with m/12381/")
+	_, err = standardprocess.New(ctx, append(params,
+		standardprocess.WithGenerationWalletType("hd"),
+		standardprocess.WithGenerationSeed([]byte("not a mnemonic")),
+		standardprocess.WithGenerationWalletPassphrase([]byte("wallet secret")),
+	)...)
+	require.EqualError(t, err, "invalid generation seed: invalid mnemonic: Invalid mnenomic")
+
+	service, err := standardprocess.New(ctx, append(params,
+		standardprocess.WithGenerationWalletType("hd"),
+		standardprocess.WithGenerationSeed([]byte(testMnemonic)),
+		standardprocess.WithGenerationWalletPassphrase([]byte("wallet secret")),
+		standardprocess.WithGenerationPathTemplate("m/12381/3600/{index}/0/0"),
+	)...)
+	require.NoError(t, err)
+
+	credentials := &checker.Credentials{Client: "client1"}
+	seed := bip39.NewSeed(testMnemonic, "")
+
+	// Generate accounts in a new wallet; they should be created at consecutive indices.
+	for i, path := range []string{"m/12381/3600/0/0/0", "m/12381/3600/1/0/0"} {
+		pubKey, _, err := service.OnGenerate(ctx, credentials, "HD wallet/Account "+string(rune('1'+i)), []byte("secret"), 1, 1)
+		require.NoError(t, err)
+		key, err := util.PrivateKeyFromSeedAndPath(seed, path)
+		require.NoError(t, err)
+		require.Equal(t, key.PublicKey().Marshal(), pubKey)
+	}
+
+	// The accounts should be available from the fetcher.
+	_, account, err := fetcherSvc.FetchAccount(ctx, "HD wallet/Account 2")
+	require.NoError(t, err)
+	require.Equal(t, "m/12381/3600/1/0/0", account.(e2wtypes.AccountPathProvider).Path())
+
+	// Duplicate account names are refused.
+	_, _, err = service.OnGenerate(ctx, credentials, "HD wallet/Account 1", []byte("secret"), 1, 1)
+	require.EqualError(t, err, "account already exists")
+}
+
+func TestGeneratePathed(t *testing.T) {
+	ctx := context.Background()
+
+	stores := []e2wtypes.Store{scratch.New()}
+	peers, err := staticpeers.New(ctx,
+		staticpeers.WithPeers(map[uint64]string{1: "signer-test01:8881"}),
+	)
+	require.NoError(t, err)
+	checkerSvc, err := mockchecker.New()
+	require.NoError(t, err)
+	unlockerSvc, err := localunlocker.New(ctx)
+	require.NoError(t, err)
+	fetcherSvc, err := memfetcher.New(ctx,
+		memfetcher.WithStores(stores),
+		memfetcher.WithEncryptor(keystorev4.New()),
+	)
+	require.NoError(t, err)
+
+	service, err := standardprocess.New(ctx,
+		standardprocess.WithChecker(checkerSvc),
+		standardprocess.WithID(1),
+		standardprocess.WithPeers(peers),
+		standardprocess.WithSender(sendermock.New(1)),
+		standardprocess.WithFetcher(fetcherSvc),
+		standardprocess.WithStores(stores),
+		standardprocess.WithUnlocker(unlockerSvc),
+		standardprocess.WithGenerationWalletType("hd"),
+		standardprocess.WithGenerationSeed([]byte(testMnemonic)),
+		standardprocess.WithGenerationWalletPassphrase([]byte("wallet secret")),
+	)
+	require.NoError(t, err)
+
+	credentials := &checker.Credentials{Client: "client1"}
+	seed := bip39.NewSeed(testMnemonic, "")
+
+	_, err = service.OnGeneratePathed(ctx, credentials, "HD wallet/Account 1", []byte("secret"), "")
+	require.EqualError(t, err, "no path supplied")
+
+	// Accounts are created at the supplied path, regardless of the order of creation.
+	for i, path := range []string{"m/12381/3600/5/0/0", "m/12381/3600/2/0/0"} {
+		pubKey, err := service.OnGeneratePathed(ctx, credentials, "HD wallet/Account "+string(rune('1'+i)), []byte("secret"), path)
+		require.NoError(t, err)
+		key, err := util.PrivateKeyFromSeedAndPath(seed, path)
+		require.NoError(t, err)
+		require.Equal(t, key.PublicKey().Marshal(), pubKey)
+	}
+
+	// Paths cannot be reused.
+	_, err = service.OnGeneratePathed(ctx, credentials, "HD wallet/Account 3", []byte("secret"), "m/12381/3600/5/0/0")
+	require.EqualError(t, err, "failed account generation")
+
+	// Access is checked.
+	_, err = service.OnGeneratePathed(ctx, credentials, "HD wallet/Deny account", []byte("secret"), "m/12381/3600/6/0/0")
+	require.EqualError(t, err, "failed rules check")
+}
@@ -13,6 +13,21 @@
 
 package standard
 
+import "time"
+
 // noopMonitor is a monitor that does nothing, used in place of nil if an
 // external monitor is not supplied.
 type noopMonitor struct{}
+
+// DistributedOperationTimedOut is called when a distributed operation times out waiting for a peer.
+func (m *noopMonitor) DistributedOperationTimedOut(operation string, peer string) {}
+
+// DistributedShareVerificationFailed is called when a share received from a peer fails verification against its commitments.
+func (m *noopMonitor) DistributedShareVerificationFailed(peer uint64) {}
+
+// DistributedAggregationVerificationFailed is called when a composite signature aggregated from peers' partial signatures fails verification.
+func (m *noopMonitor) DistributedAggregationVerificationFailed(operation string) {}
+
+// DistributedGenerationPhaseCompleted is called when a phase of distributed key generation has completed.
+func (m *noopMonitor) DistributedGenerationPhaseCompleted(started time.Time, phase string, succeeded bool) {
+}
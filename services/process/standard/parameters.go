@@ -14,8 +14,11 @@
 package standard
 
 import (
+	"time"
+
 	"github.com/attestantio/dirk/services/checker"
 	"github.com/attestantio/dirk/services/fetcher"
+	"github.com/attestantio/dirk/services/hooks"
 	"github.com/attestantio/dirk/services/metrics"
 	"github.com/attestantio/dirk/services/peers"
 	"github.com/attestantio/dirk/services/sender"
@@ -27,17 +30,26 @@ import (
 )
 
 type parameters struct {
-	logLevel             zerolog.Level
-	monitor              metrics.ProcessMonitor
-	checker              checker.Service
-	fetcher              fetcher.Service
-	sender               sender.Service
-	unlocker             unlocker.Service
-	encryptor            e2wtypes.Encryptor
-	id                   uint64
-	peers                peers.Service
-	stores               []e2wtypes.Store
-	generationPassphrase []byte
+	logLevel                   zerolog.Level
+	monitor                    metrics.ProcessMonitor
+	checker                    checker.Service
+	fetcher                    fetcher.Service
+	sender                     sender.Service
+	unlocker                   unlocker.Service
+	encryptor                  e2wtypes.Encryptor
+	id                         uint64
+	peers                      peers.Service
+	stores                     []e2wtypes.Store
+	generationPassphrase       []byte
+	generationWalletType       string
+	generationPathTemplate     string
+	generationSeed             []byte
+	generationWalletPassphrase []byte
+	operationTimeout           time.Duration
+	dkgRoundTimeout            time.Duration
+	dkgMaxRetries              uint32
+	accountHook                hooks.Service
+	resharingStatePath         string
 }
 
 // Parameter is the interface for service parameters.
@@ -128,11 +140,81 @@ func WithGenerationPassphrase(generationPassphrase []byte) Parameter {
 	})
 }
 
+// WithGenerationWalletType sets the type of wallet created for generation if the requested wallet does not exist.
+// It can be "non-deterministic", "hierarchical deterministic", or empty to require wallets to exist.
+func WithGenerationWalletType(walletType string) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.generationWalletType = walletType
+	})
+}
+
+// WithGenerationPathTemplate sets the template of the derivation path for accounts generated in
+// hierarchical deterministic wallets, for example "m/12381/3600/{index}/0/0".
+func WithGenerationPathTemplate(pathTemplate string) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.generationPathTemplate = pathTemplate
+	})
+}
+
+// WithGenerationSeed sets the seed used when creating hierarchical deterministic wallets for generation.
+// It can be a BIP-39 mnemonic or a 0x-prefixed hex string.
+func WithGenerationSeed(seed []byte) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.generationSeed = seed
+	})
+}
+
+// WithGenerationWalletPassphrase sets the passphrase used to encrypt wallets created for generation.
+func WithGenerationWalletPassphrase(passphrase []byte) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.generationWalletPassphrase = passphrase
+	})
+}
+
+// WithOperationTimeout sets the maximum time for a distributed operation, regardless of any client deadline.
+func WithOperationTimeout(timeout time.Duration) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.operationTimeout = timeout
+	})
+}
+
+// WithAccountHook sets the hook to notify when an account is created.
+func WithAccountHook(hook hooks.Service) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.accountHook = hook
+	})
+}
+
+// WithResharingStatePath sets the directory in which the state of committed resharings is kept
+// until they complete.  If this is not set then accounts cannot be reshared.
+func WithResharingStatePath(path string) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.resharingStatePath = path
+	})
+}
+
+// WithDKGRoundTimeout sets the maximum time for each round of a distributed key generation.
+// If this is 0 then rounds are bounded only by the operation timeout.
+func WithDKGRoundTimeout(timeout time.Duration) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.dkgRoundTimeout = timeout
+	})
+}
+
+// WithDKGMaxRetries sets the maximum number of times that a distributed key generation
+// is retried without a peer that failed to respond within the round timeout.
+func WithDKGMaxRetries(retries uint32) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.dkgMaxRetries = retries
+	})
+}
+
 // parseAndCheckParameters parses and checks parameters to ensure that mandatory parameters are present and correct.
 func parseAndCheckParameters(params ...Parameter) (*parameters, error) {
 	parameters := parameters{
-		logLevel:  zerolog.GlobalLevel(),
-		encryptor: keystorev4.New(),
+		logLevel:         zerolog.GlobalLevel(),
+		encryptor:        keystorev4.New(),
+		operationTimeout: defaultOperationTimeout,
 	}
 	for _, p := range params {
 		if params != nil {
@@ -147,6 +229,12 @@ func parseAndCheckParameters(params ...Parameter) (*parameters, error) {
 	if parameters.checker == nil {
 		return nil, errors.New("no checker specified")
 	}
+	if parameters.operationTimeout <= 0 {
+		return nil, errors.New("operation timeout must be greater than 0")
+	}
+	if parameters.dkgRoundTimeout < 0 {
+		return nil, errors.New("DKG round timeout must not be negative")
+	}
 	if parameters.fetcher == nil {
 		return nil, errors.New("no fetcher specified")
 	}
@@ -168,6 +256,27 @@ func parseAndCheckParameters(params ...Parameter) (*parameters, error) {
 	if parameters.stores == nil {
 		return nil, errors.New("no stores specified")
 	}
+	walletType, err := parseWalletType(parameters.generationWalletType)
+	if err != nil {
+		return nil, err
+	}
+	parameters.generationWalletType = walletType
+	if parameters.generationPathTemplate != "" {
+		if err := validatePathTemplate(parameters.generationPathTemplate); err != nil {
+			return nil, errors.Wrap(err, "invalid generation path template")
+		}
+	}
+	if parameters.generationWalletType == walletTypeHD {
+		if len(parameters.generationSeed) == 0 {
+			return nil, errors.New("no generation seed specified for hierarchical deterministic wallets")
+		}
+		if len(parameters.generationWalletPassphrase) == 0 {
+			return nil, errors.New("no generation wallet passphrase specified for hierarchical deterministic wallets")
+		}
+		if parameters.generationPathTemplate == "" {
+			parameters.generationPathTemplate = defaultPathTemplate
+		}
+	}
 
 	return &parameters, nil
 }
@@ -0,0 +1,53 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard
+
+import (
+	"context"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	otlog "github.com/opentracing/opentracing-go/log"
+)
+
+// Phases of distributed key generation.  The prepare, execute, commit and
+// confirmation phases are the rounds run by the participant that initiates the
+// generation; the others are run by every participant.
+const (
+	phasePrepare       = "prepare"
+	phaseExecute       = "execute"
+	phaseCommit        = "commit"
+	phaseConfirmation  = "confirmation"
+	phaseCommitment    = "commitment"
+	phaseShareExchange = "share_exchange"
+	phaseVerification  = "verification"
+	phaseAggregation   = "aggregation"
+)
+
+// startPhase starts a phase of distributed key generation within a span.  The
+// returned function finishes the span and records the duration of the phase,
+// marking it as failed if the supplied error is not nil.
+func (s *Service) startPhase(ctx context.Context, phase string) (context.Context, func(err error)) {
+	started := time.Now()
+	span, ctx := opentracing.StartSpanFromContext(ctx, "services.process.generation."+phase)
+	return ctx, func(err error) {
+		if err != nil {
+			ext.Error.Set(span, true)
+			span.LogFields(otlog.Error(err))
+		}
+		span.Finish()
+		s.monitor.DistributedGenerationPhaseCompleted(started, phase, err == nil)
+	}
+}
@@ -0,0 +1,232 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard
+
+import (
+	"context"
+	"crypto/rand"
+	"sort"
+	"sync"
+
+	"github.com/attestantio/dirk/core"
+	"github.com/attestantio/dirk/services/checker"
+	"github.com/attestantio/dirk/services/ruler"
+	"github.com/attestantio/dirk/services/sender"
+	"github.com/herumi/bls-eth-go-binary/bls"
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	e2wtypes "github.com/wealdtech/go-eth2-wallet-types/v2"
+)
+
+// resharer returns the sender as a resharer, if it supports resharing.
+func (s *Service) resharer() (sender.Resharer, error) {
+	resharer, isResharer := s.senderSvc.(sender.Resharer)
+	if !isResharer {
+		return nil, errors.New("sender does not support resharing")
+	}
+	return resharer, nil
+}
+
+// OnReshare is called when a request to reshare an existing distributed account is received.
+// The existing participants that are available act as dealers, each sharing its existing share
+// between the new participants, so that the new shares combine to the same composite key.
+// New shares are confirmed before any are stored, and existing shares are only replaced once
+// every participant has stored its new share; if storing fails on any participant the new
+// shares are dropped.
+func (s *Service) OnReshare(ctx context.Context,
+	credentials *checker.Credentials,
+	account string,
+	passphrase []byte,
+	signingThreshold uint32,
+	numParticipants uint32,
+) ([]byte, []*core.Endpoint, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "services.process.OnReshare")
+	defer span.Finish()
+
+	// Check parameters.
+	if numParticipants < 2 {
+		log.Warn().Uint32("participants", numParticipants).Msg("Too few participants")
+		return nil, nil, errors.New("too few participants")
+	}
+	if signingThreshold > numParticipants {
+		log.Warn().Uint32("participants", numParticipants).Uint32("signing_threshold", signingThreshold).Msg("Signing threshold too high")
+		return nil, nil, errors.New("signing threshold too high")
+	}
+	if signingThreshold <= numParticipants/2 {
+		log.Warn().Uint32("participants", numParticipants).Uint32("signing_threshold", signingThreshold).Msg("Signing threshold too low")
+		return nil, nil, errors.New("signing threshold too low")
+	}
+	resharer, err := s.resharer()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	log := log.With().Str("account", account).Logger()
+	_, existing, err := s.fetcherSvc.FetchAccount(ctx, account)
+	if err != nil {
+		log.Warn().Err(err).Msg("Unknown account supplied")
+		return nil, nil, errors.New("unknown account")
+	}
+	distributedAccount, isDistributed := existing.(e2wtypes.DistributedAccount)
+	if !isDistributed {
+		log.Warn().Msg("Account is not distributed")
+		return nil, nil, errors.New("account is not distributed")
+	}
+	vVecProvider, isProvider := existing.(e2wtypes.AccountVerificationVectorProvider)
+	if !isProvider {
+		log.Warn().Msg("Account does not provide a verification vector")
+		return nil, nil, errors.New("account does not provide a verification vector")
+	}
+
+	checkRes := s.checkAccess(ctx, credentials, account, ruler.ActionReshareAccount)
+	if checkRes != core.ResultSucceeded {
+		log.Debug().Msg("Reshare refused")
+		return nil, nil, errors.New("failed rules check")
+	}
+	log.Trace().Msg("Reshare allowed")
+
+	ctx, cancel := context.WithTimeout(ctx, s.operationTimeout)
+	defer cancel()
+
+	vVec := make([]bls.PublicKey, len(vVecProvider.VerificationVector()))
+	for i, key := range vVecProvider.VerificationVector() {
+		if err := vVec[i].Deserialize(key.Marshal()); err != nil {
+			log.Error().Err(err).Msg("Invalid verification vector")
+			return nil, nil, errors.New("invalid verification vector")
+		}
+	}
+
+	// Dealers are the existing participants that are available.  Enough of
+	// them are required to reconstruct the composite key.
+	dealerIDs := make([]uint64, 0)
+	dealers := make([]*core.Endpoint, 0)
+	for id := range distributedAccount.Participants() {
+		peer, err := s.peersSvc.Peer(id)
+		if err != nil || !s.peersSvc.Healthy(id) {
+			log.Warn().Uint64("participant", id).Msg("Existing participant unavailable; its share will not be invalidated")
+			continue
+		}
+		dealerIDs = append(dealerIDs, id)
+		dealers = append(dealers, peer)
+	}
+	if len(dealers) < int(distributedAccount.SigningThreshold()) {
+		log.Error().Int("available", len(dealers)).Uint32("required", distributedAccount.SigningThreshold()).Msg("Not enough existing participants available")
+		return nil, nil, errors.New("not enough existing participants available")
+	}
+	sort.Slice(dealerIDs, func(i, j int) bool { return dealerIDs[i] < dealerIDs[j] })
+
+	participants, err := s.peersSvc.Suitable(numParticipants)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to select suitable participants")
+		return nil, nil, errors.New("no suitable participants")
+	}
+
+	// All peers involved, dealers first.
+	involved := make([]*core.Endpoint, 0, len(dealers)+len(participants))
+	involvedIDs := make(map[uint64]bool)
+	for _, peer := range append(dealers, participants...) {
+		if !involvedIDs[peer.ID] {
+			involvedIDs[peer.ID] = true
+			involved = append(involved, peer)
+		}
+	}
+
+	for _, peer := range involved {
+		log.Trace().Str("endpoint", peer.String()).Msg("Sending prepare reshare request to endpoint")
+		if err := resharer.PrepareReshare(ctx, peer, account, passphrase, signingThreshold, participants, dealerIDs, vVec); err != nil {
+			s.peerCallFailed(ctx, "prepare reshare", peer, err)
+			s.abortResharePeers(account, involved)
+			log.Error().Err(err).Str("endpoint", peer.String()).Msg("Failed to prepare resharing on endpoint")
+			return nil, nil, errors.Wrap(err, "failed to prepare endpoints")
+		}
+	}
+
+	for _, dealer := range dealers {
+		log.Trace().Str("endpoint", dealer.String()).Msg("Sending execute reshare request to endpoint")
+		if err := resharer.ExecuteReshare(ctx, dealer, account); err != nil {
+			s.peerCallFailed(ctx, "execute reshare", dealer, err)
+			s.abortResharePeers(account, involved)
+			log.Error().Err(err).Str("endpoint", dealer.String()).Msg("Failed to execute resharing on endpoint")
+			return nil, nil, errors.Wrap(err, "failed to execute resharing")
+		}
+	}
+
+	// Confirm the new shares before any are stored.
+	confirmationData := make([]byte, 32)
+	if _, err := rand.Read(confirmationData); err != nil {
+		s.abortResharePeers(account, involved)
+		return nil, nil, errors.Wrap(err, "failed to generate confirmation data")
+	}
+	pubKeys := make([][]byte, len(participants))
+	confirmationSigs := make([][]byte, len(participants))
+	for i, participant := range participants {
+		log.Trace().Str("endpoint", participant.String()).Msg("Sending confirm reshare request to endpoint")
+		pubKeys[i], confirmationSigs[i], err = resharer.ConfirmReshare(ctx, participant, account, confirmationData)
+		if err != nil {
+			s.peerCallFailed(ctx, "confirm reshare", participant, err)
+			s.abortResharePeers(account, involved)
+			log.Error().Err(err).Str("endpoint", participant.String()).Msg("Failed to confirm resharing on endpoint")
+			return nil, nil, errors.Wrap(err, "failed to confirm resharing")
+		}
+	}
+	compositePubKey := vVec[0].Serialize()
+	if err := s.verifyConfirmations("reshare", compositePubKey, pubKeys, confirmationSigs, participants, signingThreshold, confirmationData); err != nil {
+		s.abortResharePeers(account, involved)
+		log.Error().Err(err).Msg("Failed to verify resharing")
+		return nil, nil, errors.New("invalid resharing")
+	}
+
+	for _, peer := range involved {
+		log.Trace().Str("endpoint", peer.String()).Msg("Sending commit reshare request to endpoint")
+		if err := resharer.CommitReshare(ctx, peer, account); err != nil {
+			s.peerCallFailed(ctx, "commit reshare", peer, err)
+			// Aborting drops the new shares of those that have committed.
+			s.abortResharePeers(account, involved)
+			log.Error().Err(err).Str("endpoint", peer.String()).Msg("Failed to commit resharing on endpoint")
+			return nil, nil, errors.Wrap(err, "failed to commit resharing")
+		}
+	}
+
+	for _, peer := range involved {
+		if err := resharer.CompleteReshare(ctx, peer, account); err != nil {
+			// The new share is stored, and can be put in place by completing the resharing later.
+			log.Error().Err(err).Str("endpoint", peer.String()).Msg("Failed to complete resharing on endpoint; its new share is pending")
+		}
+	}
+
+	log.Info().Int("dealers", len(dealers)).Int("participants", len(participants)).Uint32("signing_threshold", signingThreshold).Msg("Reshared account")
+	return compositePubKey, participants, nil
+}
+
+// abortResharePeers sends abort requests to all peers involved in a resharing,
+// so that any that have stored their new share drop it.
+func (s *Service) abortResharePeers(account string, peers []*core.Endpoint) {
+	resharer, err := s.resharer()
+	if err != nil {
+		return
+	}
+	var wg sync.WaitGroup
+	for _, peer := range peers {
+		wg.Add(1)
+		go func(peer *core.Endpoint) {
+			defer wg.Done()
+			abortCtx, cancel := context.WithTimeout(context.Background(), abortTimeout)
+			defer cancel()
+			if err := resharer.AbortReshare(abortCtx, peer, account); err != nil {
+				log.Debug().Err(err).Str("peer", peer.String()).Msg("Failed to abort resharing on peer")
+			}
+		}(peer)
+	}
+	wg.Wait()
+}
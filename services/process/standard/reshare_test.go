@@ -0,0 +1,284 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard_test
+
+import (
+	context "context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/attestantio/dirk/core"
+	"github.com/attestantio/dirk/services/checker"
+	mockchecker "github.com/attestantio/dirk/services/checker/mock"
+	"github.com/attestantio/dirk/services/fetcher"
+	memfetcher "github.com/attestantio/dirk/services/fetcher/mem"
+	staticpeers "github.com/attestantio/dirk/services/peers/static"
+	standardprocess "github.com/attestantio/dirk/services/process/standard"
+	sendermock "github.com/attestantio/dirk/services/sender/mock"
+	localunlocker "github.com/attestantio/dirk/services/unlocker/local"
+	"github.com/attestantio/dirk/testing/mock"
+	"github.com/attestantio/dirk/util"
+	"github.com/herumi/bls-eth-go-binary/bls"
+	"github.com/stretchr/testify/require"
+	distributed "github.com/wealdtech/go-eth2-wallet-distributed"
+	keystorev4 "github.com/wealdtech/go-eth2-wallet-encryptor-keystorev4"
+	filesystem "github.com/wealdtech/go-eth2-wallet-store-filesystem"
+	e2wtypes "github.com/wealdtech/go-eth2-wallet-types/v2"
+)
+
+const reshareAccountPassphrase = "Test account 1 passphrase"
+
+var reshareEndpoints = []*core.Endpoint{
+	{ID: 1, Name: "signer-test01", Port: 8881},
+	{ID: 2, Name: "signer-test02", Port: 8882},
+	{ID: 3, Name: "signer-test03", Port: 8883},
+	{ID: 4, Name: "signer-test04", Port: 8884},
+	{ID: 5, Name: "signer-test05", Port: 8885},
+}
+
+// failingCommit is a process that fails to commit resharing.
+type failingCommit struct {
+	*standardprocess.Service
+}
+
+func (f *failingCommit) OnCommitReshare(ctx context.Context, sender uint64, account string) error {
+	return errors.New("mock failure")
+}
+
+// failingAbort is a process that fails to abort resharing.
+type failingAbort struct {
+	*standardprocess.Service
+}
+
+func (f *failingAbort) OnAbortReshare(ctx context.Context, sender uint64, account string) error {
+	return errors.New("mock failure")
+}
+
+// failingComplete is a process that fails to complete resharing.
+type failingComplete struct {
+	*standardprocess.Service
+}
+
+func (f *failingComplete) OnCompleteReshare(ctx context.Context, sender uint64, account string) error {
+	return errors.New("mock failure")
+}
+
+// createResharingServices creates process services for all of the resharing endpoints,
+// returning their fetchers.
+func createResharingServices(ctx context.Context, t *testing.T, base string) map[uint64]fetcher.Service {
+	fetchers := make(map[uint64]fetcher.Service)
+	for _, endpoint := range reshareEndpoints {
+		stores := []e2wtypes.Store{filesystem.New(filesystem.WithLocation(filepath.Join(base, fmt.Sprintf("%d", endpoint.ID))))}
+		_, err := distributed.CreateWallet(ctx, "Test", stores[0], keystorev4.New())
+		require.NoError(t, err)
+		fetchers[endpoint.ID] = createResharingService(ctx, t, base, endpoint)
+	}
+	return fetchers
+}
+
+// createResharingService creates the process service for a resharing endpoint from its
+// existing stores, returning its fetcher.
+func createResharingService(ctx context.Context, t *testing.T, base string, endpoint *core.Endpoint) fetcher.Service {
+	peersMap := make(map[uint64]string)
+	for _, endpoint := range reshareEndpoints {
+		peersMap[endpoint.ID] = endpoint.ConnectAddress()
+	}
+	stores := []e2wtypes.Store{filesystem.New(filesystem.WithLocation(filepath.Join(base, fmt.Sprintf("%d", endpoint.ID))))}
+	peers, err := staticpeers.New(ctx, staticpeers.WithPeers(peersMap))
+	require.NoError(t, err)
+	checkerSvc, err := mockchecker.New()
+	require.NoError(t, err)
+	unlockerSvc, err := localunlocker.New(ctx, localunlocker.WithAccountPassphrases([]string{reshareAccountPassphrase}))
+	require.NoError(t, err)
+	fetcherSvc, err := memfetcher.New(ctx,
+		memfetcher.WithStores(stores),
+		memfetcher.WithEncryptor(keystorev4.New()),
+	)
+	require.NoError(t, err)
+	service, err := standardprocess.New(ctx,
+		standardprocess.WithChecker(checkerSvc),
+		standardprocess.WithGenerationPassphrase([]byte(reshareAccountPassphrase)),
+		standardprocess.WithID(endpoint.ID),
+		standardprocess.WithPeers(peers),
+		standardprocess.WithSender(sendermock.New(endpoint.ID)),
+		standardprocess.WithFetcher(fetcherSvc),
+		standardprocess.WithStores(stores),
+		standardprocess.WithUnlocker(unlockerSvc),
+		standardprocess.WithEncryptor(keystorev4.New()),
+		standardprocess.WithResharingStatePath(filepath.Join(base, fmt.Sprintf("resharing-%d", endpoint.ID))),
+	)
+	require.NoError(t, err)
+	mock.Processes[endpoint.ID] = service
+	return fetcherSvc
+}
+
+// generateDistributed generates a distributed account across the given participants.
+func generateDistributed(ctx context.Context, t *testing.T, threshold uint32, participants []*core.Endpoint) []byte {
+	for _, participant := range participants {
+		require.NoError(t, mock.Processes[participant.ID].OnPrepare(ctx, 1, "Test/Test", []byte(reshareAccountPassphrase), threshold, participants))
+	}
+	for _, participant := range participants {
+		require.NoError(t, mock.Processes[participant.ID].OnExecute(ctx, 1, "Test/Test"))
+	}
+	var pubKey []byte
+	for _, participant := range participants {
+		key, _, err := mock.Processes[participant.ID].OnCommit(ctx, 1, "Test/Test", []byte("Confirmation data"))
+		require.NoError(t, err)
+		pubKey = key
+	}
+	return pubKey
+}
+
+// requireShares checks that exactly the given participants hold shares of the account with the
+// given public key and threshold, and that the first threshold of them can sign for the key.
+func requireShares(ctx context.Context, t *testing.T, fetchers map[uint64]fetcher.Service, pubKey []byte, threshold uint32, participants []*core.Endpoint) {
+	holders := make(map[uint64]bool)
+	for _, participant := range participants {
+		holders[participant.ID] = true
+	}
+	ids := make([]bls.ID, 0)
+	sigs := make([]bls.Sign, 0)
+	for _, endpoint := range reshareEndpoints {
+		_, account, err := fetchers[endpoint.ID].FetchAccount(ctx, "Test/Test")
+		if !holders[endpoint.ID] {
+			require.Error(t, err, fmt.Sprintf("%d holds a share", endpoint.ID))
+			continue
+		}
+		require.NoError(t, err, fmt.Sprintf("%d does not hold a share", endpoint.ID))
+		distributedAccount := account.(e2wtypes.DistributedAccount)
+		require.Equal(t, pubKey, distributedAccount.CompositePublicKey().Marshal())
+		require.Equal(t, threshold, distributedAccount.SigningThreshold())
+		require.Len(t, distributedAccount.Participants(), len(participants))
+
+		if len(sigs) < int(threshold) {
+			require.NoError(t, account.(e2wtypes.AccountLocker).Unlock(ctx, []byte(reshareAccountPassphrase)))
+			sig, err := account.(e2wtypes.AccountSigner).Sign(ctx, []byte("data"))
+			require.NoError(t, err)
+			blsSig := bls.Sign{}
+			require.NoError(t, blsSig.Deserialize(sig.Marshal()))
+			ids = append(ids, *util.BLSID(endpoint.ID))
+			sigs = append(sigs, blsSig)
+		}
+	}
+	compositeKey := bls.PublicKey{}
+	require.NoError(t, compositeKey.Deserialize(pubKey))
+	compositeSig := bls.Sign{}
+	require.NoError(t, compositeSig.Recover(sigs, ids))
+	require.True(t, compositeSig.VerifyByte(&compositeKey, []byte("data")))
+}
+
+func TestReshare(t *testing.T) {
+	ctx := context.Background()
+	base, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(base)
+	fetchers := createResharingServices(ctx, t, base)
+	pubKey := generateDistributed(ctx, t, 2, reshareEndpoints[:3])
+	requireShares(ctx, t, fetchers, pubKey, 2, reshareEndpoints[:3])
+
+	credentials := &checker.Credentials{Client: "client1"}
+	resharer := mock.Processes[1].(*standardprocess.Service)
+
+	// Bad parameters.
+	_, _, err = resharer.OnReshare(ctx, credentials, "Test/Test", nil, 3, 1)
+	require.EqualError(t, err, "too few participants")
+	_, _, err = resharer.OnReshare(ctx, credentials, "Test/Test", nil, 6, 5)
+	require.EqualError(t, err, "signing threshold too high")
+	_, _, err = resharer.OnReshare(ctx, credentials, "Test/Test", nil, 2, 5)
+	require.EqualError(t, err, "signing threshold too low")
+	_, _, err = resharer.OnReshare(ctx, credentials, "Test/Unknown", nil, 3, 5)
+	require.EqualError(t, err, "unknown account")
+
+	// 2-of-3 to 3-of-5.
+	reshared, participants, err := resharer.OnReshare(ctx, credentials, "Test/Test", nil, 3, 5)
+	require.NoError(t, err)
+	require.Equal(t, pubKey, reshared)
+	require.Len(t, participants, 5)
+	requireShares(ctx, t, fetchers, pubKey, 3, participants)
+
+	// 3-of-5 to 2-of-3; the shares of those no longer participating are removed.
+	reshared, participants, err = resharer.OnReshare(ctx, credentials, "Test/Test", nil, 2, 3)
+	require.NoError(t, err)
+	require.Equal(t, pubKey, reshared)
+	require.Len(t, participants, 3)
+	requireShares(ctx, t, fetchers, pubKey, 2, participants)
+}
+
+func TestReshareRollback(t *testing.T) {
+	ctx := context.Background()
+	base, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(base)
+	fetchers := createResharingServices(ctx, t, base)
+	pubKey := generateDistributed(ctx, t, 2, reshareEndpoints[:3])
+
+	// The last peer to commit fails, after the others have replaced their shares.
+	mock.Processes[5] = &failingCommit{mock.Processes[5].(*standardprocess.Service)}
+
+	_, _, err = mock.Processes[1].(*standardprocess.Service).OnReshare(ctx, &checker.Credentials{Client: "client1"}, "Test/Test", nil, 3, 5)
+	require.EqualError(t, err, "failed to commit resharing: mock failure")
+
+	// The existing shares are restored.
+	requireShares(ctx, t, fetchers, pubKey, 2, reshareEndpoints[:3])
+}
+
+func TestReshareRestart(t *testing.T) {
+	ctx := context.Background()
+	base, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(base)
+	fetchers := createResharingServices(ctx, t, base)
+	pubKey := generateDistributed(ctx, t, 2, reshareEndpoints[:3])
+
+	// An existing participant does not complete the resharing, and then restarts.
+	mock.Processes[2] = &failingComplete{mock.Processes[2].(*standardprocess.Service)}
+	_, participants, err := mock.Processes[1].(*standardprocess.Service).OnReshare(ctx, &checker.Credentials{Client: "client1"}, "Test/Test", nil, 3, 5)
+	require.NoError(t, err)
+	fetchers[2] = createResharingService(ctx, t, base, reshareEndpoints[1])
+
+	// The existing share is retained across the restart.
+	_, account, err := fetchers[2].FetchAccount(ctx, "Test/Test")
+	require.NoError(t, err)
+	require.Equal(t, uint32(2), account.(e2wtypes.DistributedAccount).SigningThreshold())
+
+	// Completing after the restart puts the new share in place.
+	require.NoError(t, mock.Processes[2].(*standardprocess.Service).OnCompleteReshare(ctx, 1, "Test/Test"))
+	requireShares(ctx, t, fetchers, pubKey, 3, participants)
+	require.Equal(t, standardprocess.ErrNotInProgress, mock.Processes[2].(*standardprocess.Service).OnCompleteReshare(ctx, 1, "Test/Test"))
+}
+
+func TestReshareRestartAbort(t *testing.T) {
+	ctx := context.Background()
+	base, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(base)
+	fetchers := createResharingServices(ctx, t, base)
+	pubKey := generateDistributed(ctx, t, 2, reshareEndpoints[:3])
+
+	// The last peer to commit fails, and one that has committed restarts before being told to abort.
+	mock.Processes[5] = &failingCommit{mock.Processes[5].(*standardprocess.Service)}
+	mock.Processes[2] = &failingAbort{mock.Processes[2].(*standardprocess.Service)}
+	_, _, err = mock.Processes[1].(*standardprocess.Service).OnReshare(ctx, &checker.Credentials{Client: "client1"}, "Test/Test", nil, 3, 5)
+	require.EqualError(t, err, "failed to commit resharing: mock failure")
+	fetchers[2] = createResharingService(ctx, t, base, reshareEndpoints[1])
+
+	// Aborting after the restart drops the new share, leaving the existing shares in place.
+	require.NoError(t, mock.Processes[2].(*standardprocess.Service).OnAbortReshare(ctx, 1, "Test/Test"))
+	require.Equal(t, standardprocess.ErrNotInProgress, mock.Processes[2].(*standardprocess.Service).OnCompleteReshare(ctx, 1, "Test/Test"))
+	requireShares(ctx, t, fetchers, pubKey, 2, reshareEndpoints[:3])
+}
@@ -0,0 +1,601 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/attestantio/dirk/core"
+	"github.com/attestantio/dirk/services/fetcher"
+	"github.com/attestantio/dirk/util"
+	"github.com/google/uuid"
+	"github.com/herumi/bls-eth-go-binary/bls"
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	distributed "github.com/wealdtech/go-eth2-wallet-distributed"
+	e2wtypes "github.com/wealdtech/go-eth2-wallet-types/v2"
+	indexer "github.com/wealdtech/go-indexer"
+)
+
+// resharing holds information about a resharing activity.
+type resharing struct {
+	// Metadata.
+	processStarted time.Time
+
+	// Information about the resharing.
+	account      string
+	passphrase   []byte
+	threshold    uint32
+	participants []*core.Endpoint
+	dealers      []uint64
+	oldVVec      []bls.PublicKey
+
+	// Contributions to distribute to each participant, if we are a dealer.
+	distributionSecrets map[uint64]bls.SecretKey
+	distributionVVec    []bls.PublicKey
+
+	// Contributions from each dealer, and the resulting share, if we are a participant.
+	contributions     map[uint64]bls.SecretKey
+	contributionVVecs map[uint64][]bls.PublicKey
+	share             *bls.SecretKey
+	vVec              []bls.PublicKey
+
+	// State once committed.
+	committed bool
+}
+
+// storedAccount holds the stored data of an account, so that it can be
+// written to a store.
+type storedAccount struct {
+	store       e2wtypes.Store
+	walletID    uuid.UUID
+	walletName  string
+	accountID   uuid.UUID
+	accountName string
+	data        []byte
+}
+
+// isDealer returns true if the given ID is a dealer in the resharing.
+func (r *resharing) isDealer(id uint64) bool {
+	for _, dealer := range r.dealers {
+		if dealer == id {
+			return true
+		}
+	}
+	return false
+}
+
+// isParticipant returns true if the given ID is a participant in the resharing.
+func (r *resharing) isParticipant(id uint64) bool {
+	for _, participant := range r.participants {
+		if participant.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// getResharing fetches an active resharing.
+// This assumes that a lock is already held on resharingsMu.
+func (s *Service) getResharing(ctx context.Context, account string) (*resharing, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "services.process.getResharing")
+	defer span.Finish()
+
+	resharing, exists := s.resharings[account]
+	if !exists {
+		return nil, ErrNotFound
+	}
+
+	// Resharings are bounded by the operation timeout of the coordinator; allow
+	// some leeway before removing them.  A committed resharing that expires
+	// can still be completed or aborted from its persisted state.
+	if time.Since(resharing.processStarted) > 2*s.operationTimeout {
+		log.Debug().Str("account", account).Msg("Resharing been active too long; invalidating")
+		delete(s.resharings, account)
+		return nil, ErrNotFound
+	}
+
+	return resharing, nil
+}
+
+// OnPrepareReshare is called when we receive a request from the given participant to prepare for resharing.
+func (s *Service) OnPrepareReshare(ctx context.Context,
+	sender uint64,
+	account string,
+	passphrase []byte,
+	threshold uint32,
+	participants []*core.Endpoint,
+	dealers []uint64,
+	vVec []bls.PublicKey,
+) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "services.process.OnPrepareReshare")
+	defer span.Finish()
+	log.Trace().Uint64("sender_id", sender).Str("account", account).Msg("Preparing for resharing")
+
+	if threshold == 0 || int(threshold) > len(participants) {
+		return errors.New("invalid threshold")
+	}
+	if len(vVec) == 0 || len(dealers) < len(vVec) {
+		return errors.New("not enough dealers")
+	}
+	if s.resharingStatePath == "" {
+		return errors.New("no resharing state path configured")
+	}
+
+	s.resharingsMu.Lock()
+	defer s.resharingsMu.Unlock()
+
+	if _, err := s.getResharing(ctx, account); err == nil {
+		log.Debug().Uint64("sender_id", sender).Str("account", account).Msg("Already in progress")
+		return ErrInProgress
+	}
+
+	r := &resharing{
+		processStarted: time.Now(),
+		account:        account,
+		passphrase:     passphrase,
+		threshold:      threshold,
+		participants:   participants,
+		dealers:        dealers,
+		oldVVec:        vVec,
+	}
+	if !r.isDealer(s.id) && !r.isParticipant(s.id) {
+		return errors.New("not involved in resharing")
+	}
+	if r.isDealer(s.id) {
+		if err := s.reshareContribution(ctx, r); err != nil {
+			log.Debug().Err(err).Uint64("sender_id", sender).Str("account", account).Msg("Failed to generate our own contribution")
+			return errors.Wrap(err, "failed to generate own contribution")
+		}
+	}
+	if r.isParticipant(s.id) {
+		r.contributions = make(map[uint64]bls.SecretKey, len(dealers))
+		r.contributionVVecs = make(map[uint64][]bls.PublicKey, len(dealers))
+	}
+	s.resharings[account] = r
+
+	return nil
+}
+
+// reshareContribution generates our contribution as a dealer, which shares our
+// existing share of the account between the new participants.
+func (s *Service) reshareContribution(ctx context.Context, r *resharing) error {
+	if _, isRemover := s.fetcherSvc.(fetcher.AccountRemover); !isRemover {
+		return errors.New("fetcher does not support account removal")
+	}
+	wallet, account, err := s.fetcherSvc.FetchAccount(ctx, r.account)
+	if err != nil {
+		return errors.Wrap(err, "failed to obtain account")
+	}
+	distributedAccount, isDistributed := account.(e2wtypes.DistributedAccount)
+	if !isDistributed {
+		return errors.New("account is not distributed")
+	}
+	vVecProvider, isProvider := account.(e2wtypes.AccountVerificationVectorProvider)
+	if !isProvider {
+		return errors.New("account does not provide a verification vector")
+	}
+	if _, exists := distributedAccount.Participants()[s.id]; !exists {
+		return errors.New("not a participant in the account")
+	}
+	accountVVec := vVecProvider.VerificationVector()
+	if len(accountVVec) != len(r.oldVVec) {
+		return errors.New("verification vector does not match account")
+	}
+	for i := range accountVVec {
+		key := bls.PublicKey{}
+		if err := key.Deserialize(accountVVec[i].Marshal()); err != nil {
+			return errors.Wrap(err, "invalid account verification vector")
+		}
+		if !key.IsEqual(&r.oldVVec[i]) {
+			return errors.New("verification vector does not match account")
+		}
+	}
+
+	share, err := s.accountShare(ctx, wallet, account)
+	if err != nil {
+		return err
+	}
+
+	// Our contribution shares our existing share: it is the constant term
+	// of a new polynomial of degree one less than the new threshold.
+	sks := make([]bls.SecretKey, r.threshold)
+	verificationKeys := make([]bls.PublicKey, r.threshold)
+	sks[0] = *share
+	verificationKeys[0] = *sks[0].GetPublicKey()
+	for i := uint32(1); i < r.threshold; i++ {
+		sks[i] = bls.SecretKey{}
+		sks[i].SetByCSPRNG()
+		verificationKeys[i] = *sks[i].GetPublicKey()
+	}
+	secrets := make(map[uint64]bls.SecretKey, len(r.participants))
+	for _, participant := range r.participants {
+		secret := bls.SecretKey{}
+		if err := secret.Set(sks, util.BLSID(participant.ID)); err != nil {
+			return errors.Wrap(err, "failed to set contribution")
+		}
+		secrets[participant.ID] = secret
+	}
+
+	r.distributionSecrets = secrets
+	r.distributionVVec = verificationKeys
+
+	return nil
+}
+
+// accountShare returns our existing share of a distributed account, unlocking
+// the account for the duration of the call if required.
+func (s *Service) accountShare(ctx context.Context, wallet e2wtypes.Wallet, account e2wtypes.Account) (*bls.SecretKey, error) {
+	if locker, isLocker := account.(e2wtypes.AccountLocker); isLocker {
+		unlocked, err := locker.IsUnlocked(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to establish if account is unlocked")
+		}
+		if !unlocked {
+			unlocked, err = s.unlockerSvc.UnlockAccount(ctx, wallet, account)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to unlock account")
+			}
+			if !unlocked {
+				return nil, errors.New("account is locked")
+			}
+			defer func() {
+				if err := locker.Lock(ctx); err != nil {
+					log.Warn().Err(err).Msg("Failed to lock account")
+				}
+			}()
+		}
+	}
+	provider, isProvider := account.(e2wtypes.AccountPrivateKeyProvider)
+	if !isProvider {
+		return nil, errors.New("account does not provide its private key")
+	}
+	privateKey, err := provider.PrivateKey(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to obtain private key")
+	}
+	share := &bls.SecretKey{}
+	if err := share.Deserialize(privateKey.Marshal()); err != nil {
+		return nil, errors.Wrap(err, "invalid private key")
+	}
+	return share, nil
+}
+
+// OnExecuteReshare is called when we receive a request from the given participant to send our contributions for resharing.
+func (s *Service) OnExecuteReshare(ctx context.Context, sender uint64, account string) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "services.process.OnExecuteReshare")
+	defer span.Finish()
+	log.Trace().Uint64("sender", sender).Str("account", account).Msg("Executing resharing")
+
+	s.resharingsMu.Lock()
+	defer s.resharingsMu.Unlock()
+
+	r, err := s.getResharing(ctx, account)
+	if err != nil {
+		if err == ErrNotFound {
+			return ErrNotInProgress
+		}
+		return err
+	}
+	if !r.isDealer(s.id) {
+		return errors.New("not a dealer")
+	}
+	resharer, err := s.resharer()
+	if err != nil {
+		return err
+	}
+
+	// The lock is held throughout, so bound the time that we wait for peers.
+	ctx, cancel := context.WithTimeout(ctx, s.operationTimeout)
+	defer cancel()
+	for id, secret := range r.distributionSecrets {
+		if id == s.id {
+			if err := s.addReshareContribution(r, s.id, secret, r.distributionVVec); err != nil {
+				return err
+			}
+			continue
+		}
+		peer, err := s.peersSvc.Peer(id)
+		if err != nil {
+			return errors.Wrap(err, "failed to obtain peer")
+		}
+		log.Trace().Uint64("id", s.id).Uint64("peer", id).Msg("Sending resharing contribution")
+		if err := resharer.SendReshareContribution(ctx, peer, account, secret, r.distributionVVec); err != nil {
+			s.peerCallFailed(ctx, "reshare contribute", peer, err)
+			return errors.Wrap(err, "failed to send contribution")
+		}
+	}
+	return nil
+}
+
+// OnContributeReshare is called when we receive a contribution for resharing from the given dealer.
+func (s *Service) OnContributeReshare(ctx context.Context, sender uint64, account string, secret bls.SecretKey, vVec []bls.PublicKey) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "services.process.OnContributeReshare")
+	defer span.Finish()
+
+	s.resharingsMu.Lock()
+	defer s.resharingsMu.Unlock()
+
+	r, err := s.getResharing(ctx, account)
+	if err != nil {
+		return err
+	}
+	if err := s.addReshareContribution(r, sender, secret, vVec); err != nil {
+		log.Warn().Err(err).Uint64("sender", sender).Str("account", account).Msg("Received invalid resharing contribution")
+		return err
+	}
+	return nil
+}
+
+// addReshareContribution verifies and stores a contribution from a dealer.
+// A contribution is valid if it is consistent with its verification vector,
+// and the verification vector shares the dealer's existing public share.
+func (s *Service) addReshareContribution(r *resharing, dealer uint64, secret bls.SecretKey, vVec []bls.PublicKey) error {
+	if !r.isParticipant(s.id) {
+		return errors.New("not a participant")
+	}
+	if !r.isDealer(dealer) {
+		return fmt.Errorf("%d is not a dealer", dealer)
+	}
+	if _, exists := r.contributions[dealer]; exists {
+		return fmt.Errorf("duplicate contribution from %d", dealer)
+	}
+	if err := s.verifyShare(s.id, r.threshold, dealer, secret, vVec); err != nil {
+		return err
+	}
+	var publicShare bls.PublicKey
+	if err := publicShare.Set(r.oldVVec, util.BLSID(dealer)); err != nil {
+		return errors.Wrap(err, "failed to obtain public share")
+	}
+	if !publicShare.IsEqual(&vVec[0]) {
+		s.monitor.DistributedShareVerificationFailed(dealer)
+		return fmt.Errorf("contribution from %d does not share its existing key", dealer)
+	}
+
+	r.contributions[dealer] = secret
+	r.contributionVVecs[dealer] = vVec
+	return nil
+}
+
+// OnConfirmReshare is called when we receive a request from the given participant to confirm our new share.
+func (s *Service) OnConfirmReshare(ctx context.Context, sender uint64, account string, confirmationData []byte) ([]byte, []byte, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "services.process.OnConfirmReshare")
+	defer span.Finish()
+
+	s.resharingsMu.Lock()
+	defer s.resharingsMu.Unlock()
+
+	r, err := s.getResharing(ctx, account)
+	if err != nil {
+		if err == ErrNotFound {
+			return nil, nil, ErrNotInProgress
+		}
+		return nil, nil, err
+	}
+	if !r.isParticipant(s.id) {
+		return nil, nil, errors.New("not a participant")
+	}
+	if len(r.contributions) != len(r.dealers) {
+		return nil, nil, fmt.Errorf("have %d contributions, need %d, aborting", len(r.contributions), len(r.dealers))
+	}
+
+	// Our new share, and the new verification vector, are obtained by
+	// interpolating the dealers' contributions at zero.
+	dealers := make([]uint64, len(r.dealers))
+	copy(dealers, r.dealers)
+	sort.Slice(dealers, func(i, j int) bool { return dealers[i] < dealers[j] })
+	ids := make([]bls.ID, len(dealers))
+	secrets := make([]bls.SecretKey, len(dealers))
+	for i, dealer := range dealers {
+		ids[i] = *util.BLSID(dealer)
+		secrets[i] = r.contributions[dealer]
+	}
+	share := bls.SecretKey{}
+	if err := share.Recover(secrets, ids); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to recover share")
+	}
+	vVec := make([]bls.PublicKey, r.threshold)
+	keys := make([]bls.PublicKey, len(dealers))
+	for i := range vVec {
+		for j, dealer := range dealers {
+			keys[j] = r.contributionVVecs[dealer][i]
+		}
+		if err := vVec[i].Recover(keys, ids); err != nil {
+			return nil, nil, errors.Wrap(err, "failed to recover verification vector")
+		}
+	}
+	if !vVec[0].IsEqual(&r.oldVVec[0]) {
+		return nil, nil, errors.New("resharing does not retain the composite public key")
+	}
+	if !verifyContribution(s.id, share, vVec) {
+		return nil, nil, errors.New("new share does not match verification vector")
+	}
+
+	r.share = &share
+	r.vVec = vVec
+	sig := share.SignByte(confirmationData)
+
+	return vVec[0].Serialize(), sig.Serialize(), nil
+}
+
+// OnCommitReshare is called when we receive a request from the given participant to store our new share.
+// Our new share is persisted alongside our existing share, which remains in use until the resharing
+// completes, so that either can be kept if the resharing does not complete.
+func (s *Service) OnCommitReshare(ctx context.Context, sender uint64, account string) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "services.process.OnCommitReshare")
+	defer span.Finish()
+
+	s.resharingsMu.Lock()
+	defer s.resharingsMu.Unlock()
+
+	r, err := s.getResharing(ctx, account)
+	if err != nil {
+		if err == ErrNotFound {
+			return ErrNotInProgress
+		}
+		return err
+	}
+	if r.committed {
+		return nil
+	}
+	if r.isParticipant(s.id) && r.share == nil {
+		return errors.New("new share not confirmed")
+	}
+
+	pending := &pendingReshare{
+		Account: account,
+		Dealer:  r.isDealer(s.id),
+	}
+	if r.isParticipant(s.id) {
+		passphrase := r.passphrase
+		if passphrase == nil {
+			passphrase = s.generationPassphrase
+		}
+		pending.AccountID, pending.Data, err = s.encryptShare(ctx, account, passphrase, *r.share, r.threshold, r.vVec, r.participants)
+		if err != nil {
+			log.Warn().Err(err).Str("account", account).Msg("Failed to encrypt new share")
+			return errors.Wrap(err, ErrNotCreated.Error())
+		}
+	}
+	if err := s.storePendingReshare(pending); err != nil {
+		log.Warn().Err(err).Str("account", account).Msg("Failed to store new share")
+		return errors.Wrap(err, ErrNotCreated.Error())
+	}
+
+	r.committed = true
+	log.Info().Str("account", account).Bool("dealer", r.isDealer(s.id)).Bool("participant", r.isParticipant(s.id)).Msg("Committed resharing")
+	return nil
+}
+
+// OnCompleteReshare is called when we receive a request from the given participant to complete resharing,
+// replacing our existing share with our new share.  A resharing that is no longer active, for example
+// because we have restarted since it was committed, is completed from its persisted state.
+func (s *Service) OnCompleteReshare(ctx context.Context, sender uint64, account string) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "services.process.OnCompleteReshare")
+	defer span.Finish()
+
+	s.resharingsMu.Lock()
+	defer s.resharingsMu.Unlock()
+
+	r, err := s.getResharing(ctx, account)
+	if err != nil && err != ErrNotFound {
+		return err
+	}
+	if r != nil && !r.committed {
+		return errors.New("not committed")
+	}
+	pending, err := s.fetchPendingReshare(account)
+	if err != nil {
+		if err == ErrNotFound {
+			return ErrNotInProgress
+		}
+		return err
+	}
+	if err := s.completePendingReshare(ctx, pending); err != nil {
+		return err
+	}
+
+	delete(s.resharings, account)
+	log.Info().Str("account", account).Msg("Completed resharing")
+	return nil
+}
+
+// OnAbortReshare is called when we receive a request from the given participant to abort resharing,
+// dropping our new share if it has already been committed.
+func (s *Service) OnAbortReshare(ctx context.Context, sender uint64, account string) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "services.process.OnAbortReshare")
+	defer span.Finish()
+
+	s.resharingsMu.Lock()
+	defer s.resharingsMu.Unlock()
+
+	_, err := s.getResharing(ctx, account)
+	if err != nil && err != ErrNotFound {
+		return err
+	}
+	active := err == nil
+	delete(s.resharings, account)
+
+	if _, err := s.fetchPendingReshare(account); err == ErrNotFound {
+		if !active {
+			return ErrNotInProgress
+		}
+		// Not committed, so there is nothing to roll back.
+		return nil
+	}
+	if err := s.removePendingReshare(account); err != nil {
+		return errors.Wrap(err, "failed to remove new share")
+	}
+	log.Info().Str("account", account).Msg("Rolled back resharing")
+
+	return nil
+}
+
+// removeShare removes our share of the given account.
+func (s *Service) removeShare(ctx context.Context, accountPath string) error {
+	s.storeMu.Lock()
+	defer s.storeMu.Unlock()
+
+	wallet, account, err := s.fetcherSvc.FetchAccount(ctx, accountPath)
+	if err != nil {
+		return errors.Wrap(err, "failed to obtain account")
+	}
+	remover, isRemover := s.fetcherSvc.(fetcher.AccountRemover)
+	if !isRemover {
+		return errors.New("fetcher does not support account removal")
+	}
+	return remover.RemoveAccount(ctx, wallet, account)
+}
+
+// storeShare writes a share of an account to its store, and makes it available.
+func (s *Service) storeShare(ctx context.Context, stored *storedAccount) error {
+	s.storeMu.Lock()
+	defer s.storeMu.Unlock()
+
+	if err := stored.store.StoreAccount(stored.walletID, stored.accountID, stored.data); err != nil {
+		return errors.Wrap(err, "failed to store account")
+	}
+	index := indexer.New()
+	if data, err := stored.store.RetrieveAccountsIndex(stored.walletID); err == nil {
+		index, err = indexer.Deserialize(data)
+		if err != nil {
+			return errors.Wrap(err, "failed to decode index")
+		}
+	}
+	index.Add(stored.accountID, stored.accountName)
+	data, err := index.Serialize()
+	if err != nil {
+		return errors.Wrap(err, "failed to encode index")
+	}
+	if err := stored.store.StoreAccountsIndex(stored.walletID, data); err != nil {
+		return errors.Wrap(err, "failed to store index")
+	}
+
+	wallet, err := distributed.OpenWallet(ctx, stored.walletName, stored.store, s.encryptor)
+	if err != nil {
+		return errors.Wrap(err, "failed to open wallet")
+	}
+	account, err := wallet.(e2wtypes.WalletAccountByNameProvider).AccountByName(ctx, stored.accountName)
+	if err != nil {
+		return errors.Wrap(err, "failed to obtain stored account")
+	}
+	if err := s.fetcherSvc.AddAccount(ctx, wallet, account); err != nil {
+		return errors.Wrap(err, "failed to add stored account")
+	}
+
+	return nil
+}
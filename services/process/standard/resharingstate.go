@@ -0,0 +1,218 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/attestantio/dirk/core"
+	"github.com/google/uuid"
+	"github.com/herumi/bls-eth-go-binary/bls"
+	"github.com/pkg/errors"
+	e2wallet "github.com/wealdtech/go-eth2-wallet"
+	distributed "github.com/wealdtech/go-eth2-wallet-distributed"
+	scratch "github.com/wealdtech/go-eth2-wallet-store-scratch"
+	e2wtypes "github.com/wealdtech/go-eth2-wallet-types/v2"
+)
+
+// pendingReshare is a resharing that has been committed but not completed.
+// It is persisted so that neither our existing share nor our new share is
+// lost if the resharing is interrupted; our existing share remains in use
+// until the resharing is completed.
+type pendingReshare struct {
+	Account string `json:"account"`
+	// Dealer is true if our existing share is removed on completion.
+	Dealer bool `json:"dealer"`
+	// AccountID and Data are the stored data of our new share, if we are a participant.
+	AccountID uuid.UUID `json:"account_id"`
+	Data      []byte    `json:"data,omitempty"`
+}
+
+// pendingResharePath returns the path of the file holding the pending resharing for an account.
+func (s *Service) pendingResharePath(account string) string {
+	return filepath.Join(s.resharingStatePath, fmt.Sprintf("%x.json", sha256.Sum256([]byte(account))))
+}
+
+// storePendingReshare persists a pending resharing, replacing any existing one for the account.
+func (s *Service) storePendingReshare(pending *pendingReshare) error {
+	data, err := json.Marshal(pending)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode pending resharing")
+	}
+	if err := os.MkdirAll(s.resharingStatePath, 0700); err != nil {
+		return errors.Wrap(err, "failed to create resharing state directory")
+	}
+	// Write to a temporary file and rename it, so that the state is never partially written.
+	path := s.pendingResharePath(pending.Account)
+	if err := ioutil.WriteFile(path+".tmp", data, 0600); err != nil {
+		return errors.Wrap(err, "failed to write pending resharing")
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		return errors.Wrap(err, "failed to store pending resharing")
+	}
+	return nil
+}
+
+// fetchPendingReshare fetches the pending resharing for an account.
+func (s *Service) fetchPendingReshare(account string) (*pendingReshare, error) {
+	if s.resharingStatePath == "" {
+		return nil, ErrNotFound
+	}
+	data, err := ioutil.ReadFile(s.pendingResharePath(account))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNotFound
+		}
+		return nil, errors.Wrap(err, "failed to read pending resharing")
+	}
+	pending := &pendingReshare{}
+	if err := json.Unmarshal(data, pending); err != nil {
+		return nil, errors.Wrap(err, "failed to decode pending resharing")
+	}
+	return pending, nil
+}
+
+// removePendingReshare removes the pending resharing for an account, if present.
+func (s *Service) removePendingReshare(account string) error {
+	if s.resharingStatePath == "" {
+		return nil
+	}
+	if err := os.Remove(s.pendingResharePath(account)); err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, "failed to remove pending resharing")
+	}
+	return nil
+}
+
+// logPendingReshares warns about resharings that were committed but not completed
+// before the process last stopped.
+func (s *Service) logPendingReshares() {
+	if s.resharingStatePath == "" {
+		return
+	}
+	files, err := ioutil.ReadDir(s.resharingStatePath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Warn().Err(err).Msg("Failed to read resharing state directory")
+		}
+		return
+	}
+	for _, file := range files {
+		if !strings.HasSuffix(file.Name(), ".json") {
+			continue
+		}
+		data, err := ioutil.ReadFile(filepath.Join(s.resharingStatePath, file.Name()))
+		if err != nil {
+			log.Warn().Err(err).Str("file", file.Name()).Msg("Failed to read pending resharing")
+			continue
+		}
+		pending := &pendingReshare{}
+		if err := json.Unmarshal(data, pending); err != nil {
+			log.Warn().Err(err).Str("file", file.Name()).Msg("Failed to decode pending resharing")
+			continue
+		}
+		log.Warn().Str("account", pending.Account).Msg("Resharing committed but not completed; existing share retained until it is completed or aborted")
+	}
+}
+
+// encryptShare encrypts a new share of an account, returning its ID and the
+// data to store.  The share is created in a scratch store, so that it does not
+// replace our existing share until the resharing completes.
+func (s *Service) encryptShare(ctx context.Context,
+	account string,
+	passphrase []byte,
+	share bls.SecretKey,
+	threshold uint32,
+	verificationVector []bls.PublicKey,
+	participants []*core.Endpoint,
+) (uuid.UUID, []byte, error) {
+	walletName, accountName, err := e2wallet.WalletAndAccountNames(account)
+	if err != nil {
+		return uuid.Nil, nil, errors.Wrap(err, "failed to parse account")
+	}
+	// The new share is stored in this wallet on completion, so it must exist.
+	if _, err := distributed.OpenWallet(ctx, walletName, s.stores[0], s.encryptor); err != nil {
+		return uuid.Nil, nil, errors.Wrap(err, "failed to open wallet")
+	}
+	store := scratch.New()
+	wallet, err := distributed.CreateWallet(ctx, walletName, store, s.encryptor)
+	if err != nil {
+		return uuid.Nil, nil, errors.Wrap(err, "failed to create wallet")
+	}
+	if err := wallet.(e2wtypes.WalletLocker).Unlock(ctx, nil); err != nil {
+		return uuid.Nil, nil, errors.Wrap(err, "failed to unlock wallet")
+	}
+
+	vVec := make([][]byte, len(verificationVector))
+	for i := range verificationVector {
+		vVec[i] = verificationVector[i].Serialize()
+	}
+	walletParticipants := make(map[uint64]string, len(participants))
+	for i := range participants {
+		walletParticipants[participants[i].ID] = participants[i].ConnectAddress()
+	}
+	newAccount, err := wallet.(e2wtypes.WalletDistributedAccountImporter).ImportDistributedAccount(ctx, accountName, share.Serialize(), threshold, vVec, walletParticipants, passphrase)
+	if err != nil {
+		return uuid.Nil, nil, errors.Wrap(err, "failed to import account")
+	}
+	data, err := store.RetrieveAccount(wallet.ID(), newAccount.ID())
+	if err != nil {
+		return uuid.Nil, nil, errors.Wrap(err, "failed to obtain account data")
+	}
+
+	return newAccount.ID(), data, nil
+}
+
+// completePendingReshare replaces our existing share, if any, with our new share, if any.
+func (s *Service) completePendingReshare(ctx context.Context, pending *pendingReshare) error {
+	if pending.Dealer {
+		_, account, err := s.fetcherSvc.FetchAccount(ctx, pending.Account)
+		// The existing share may already have been replaced by an earlier attempt to complete.
+		if err == nil && account.ID() != pending.AccountID {
+			if err := s.removeShare(ctx, pending.Account); err != nil {
+				return errors.Wrap(err, "failed to remove existing share")
+			}
+		}
+	}
+
+	if len(pending.Data) > 0 {
+		walletName, accountName, err := e2wallet.WalletAndAccountNames(pending.Account)
+		if err != nil {
+			return errors.Wrap(err, "failed to parse account")
+		}
+		store := s.stores[0]
+		wallet, err := distributed.OpenWallet(ctx, walletName, store, s.encryptor)
+		if err != nil {
+			return errors.Wrap(err, "failed to open wallet")
+		}
+		if err := s.storeShare(ctx, &storedAccount{
+			store:       store,
+			walletID:    wallet.ID(),
+			walletName:  walletName,
+			accountID:   pending.AccountID,
+			accountName: accountName,
+			data:        pending.Data,
+		}); err != nil {
+			return errors.Wrap(err, "failed to store new share")
+		}
+	}
+
+	return s.removePendingReshare(pending.Account)
+}
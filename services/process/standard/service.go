@@ -22,6 +22,8 @@ import (
 	"github.com/attestantio/dirk/core"
 	"github.com/attestantio/dirk/services/checker"
 	"github.com/attestantio/dirk/services/fetcher"
+	"github.com/attestantio/dirk/services/hooks"
+	"github.com/attestantio/dirk/services/metrics"
 	"github.com/attestantio/dirk/services/peers"
 	"github.com/attestantio/dirk/services/sender"
 	"github.com/attestantio/dirk/services/unlocker"
@@ -37,6 +39,7 @@ import (
 
 // Service is used to manage the process of distributed key generation operations.
 type Service struct {
+	monitor              metrics.ProcessMonitor
 	checkerSvc           checker.Service
 	fetcherSvc           fetcher.Service
 	senderSvc            sender.Service
@@ -46,9 +49,30 @@ type Service struct {
 	id                   uint64
 	stores               []e2wtypes.Store
 	generationPassphrase []byte
+	operationTimeout     time.Duration
+	dkgRoundTimeout      time.Duration
+	dkgMaxRetries        uint32
+	// accountHook is notified of created accounts; nil if not configured.
+	accountHook hooks.Service
+	// resharingStatePath holds committed resharings; empty if not configured.
+	resharingStatePath string
+
+	generationWalletType       string
+	generationPathTemplate     string
+	generationSeed             []byte
+	generationWalletPassphrase []byte
+	pathedAccountMu            sync.Mutex
 
 	generations   map[string]*generation
 	generationsMu sync.RWMutex
+
+	resharings   map[string]*resharing
+	resharingsMu sync.Mutex
+
+	// storeMu serialises changes to wallets by generation and resharing, which are guarded by
+	// separate locks, as concurrent changes to the same wallet would otherwise overwrite each
+	// other's updates to the wallet index.
+	storeMu sync.Mutex
 }
 
 // module-wide log.
@@ -67,22 +91,50 @@ func New(ctx context.Context, params ...Parameter) (*Service, error) {
 		log = log.Level(parameters.logLevel)
 	}
 
+	var generationSeed []byte
+	if parameters.generationWalletType == walletTypeHD {
+		generationSeed, err = parseSeed(parameters.generationSeed)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid generation seed")
+		}
+	}
+
 	s := &Service{
-		checkerSvc:           parameters.checker,
-		fetcherSvc:           parameters.fetcher,
-		unlockerSvc:          parameters.unlocker,
-		senderSvc:            parameters.sender,
-		peersSvc:             parameters.peers,
-		id:                   parameters.id,
-		stores:               parameters.stores,
-		encryptor:            parameters.encryptor,
-		generationPassphrase: parameters.generationPassphrase,
-		generations:          make(map[string]*generation),
+		monitor:                    parameters.monitor,
+		checkerSvc:                 parameters.checker,
+		fetcherSvc:                 parameters.fetcher,
+		unlockerSvc:                parameters.unlocker,
+		senderSvc:                  parameters.sender,
+		peersSvc:                   parameters.peers,
+		id:                         parameters.id,
+		stores:                     parameters.stores,
+		encryptor:                  parameters.encryptor,
+		generationPassphrase:       parameters.generationPassphrase,
+		operationTimeout:           parameters.operationTimeout,
+		dkgRoundTimeout:            parameters.dkgRoundTimeout,
+		dkgMaxRetries:              parameters.dkgMaxRetries,
+		generationWalletType:       parameters.generationWalletType,
+		generationPathTemplate:     parameters.generationPathTemplate,
+		generationSeed:             generationSeed,
+		generationWalletPassphrase: parameters.generationWalletPassphrase,
+		generations:                make(map[string]*generation),
+		resharings:                 make(map[string]*resharing),
+		accountHook:                parameters.accountHook,
+		resharingStatePath:         parameters.resharingStatePath,
 	}
+	s.logPendingReshares()
 
 	return s, nil
 }
 
+// notifyAccountCreated notifies the account hook, if configured, that an account has been created.
+func (s *Service) notifyAccountCreated(ctx context.Context, wallet e2wtypes.Wallet, account e2wtypes.Account) {
+	if s.accountHook == nil {
+		return
+	}
+	s.accountHook.AccountCreated(ctx, wallet, account)
+}
+
 // OnPrepare is called when we receive a request from the given participant to prepare for DKG.
 func (s *Service) OnPrepare(ctx context.Context,
 	sender uint64,
@@ -113,7 +165,10 @@ func (s *Service) OnPrepare(ctx context.Context,
 		sharedVVecs:    make(map[uint64][]bls.PublicKey),
 	}
 
-	if err := s.contribution(ctx, s.generations[account]); err != nil {
+	_, finishPhase := s.startPhase(ctx, phaseCommitment)
+	err := s.contribution(ctx, s.generations[account])
+	finishPhase(err)
+	if err != nil {
 		log.Debug().Uint64("sender_id", sender).Str("account", account).Msg("Failed to generate our own contribution")
 		return errors.Wrap(err, "failed to generate own contribution")
 	}
@@ -140,6 +195,18 @@ func (s *Service) OnExecute(ctx context.Context, sender uint64, account string)
 
 	// We need to swap secrets with all the other participants.
 	// Initiate calls to any participants with a higher ID than us.
+	// The generation lock is held throughout, so bound the time that we wait for peers.
+	ctx, cancel := context.WithTimeout(ctx, s.operationTimeout)
+	defer cancel()
+	ctx, finishPhase := s.startPhase(ctx, phaseShareExchange)
+	err = s.exchangeShares(ctx, generation)
+	finishPhase(err)
+	return err
+}
+
+// exchangeShares swaps secret shares and verification vectors with the
+// participants in the generation that have a higher ID than us.
+func (s *Service) exchangeShares(ctx context.Context, generation *generation) error {
 	verificationVector := generation.sharedVVecs[generation.id]
 	for id, distributionSecret := range generation.distributionSecrets {
 		if id > s.id {
@@ -148,13 +215,18 @@ func (s *Service) OnExecute(ctx context.Context, sender uint64, account string)
 				return errors.Wrap(err, "failed to obtain peer")
 			}
 			log.Trace().Uint64("id", s.id).Uint64("peer", id).Msg("Initiating contribution swap")
-			recipientSecret, recipientVVec, err := s.senderSvc.SendContribution(ctx, peer, account, distributionSecret, verificationVector)
+			recipientSecret, recipientVVec, err := s.senderSvc.SendContribution(ctx, peer, generation.account, distributionSecret, verificationVector)
 			if err != nil {
+				s.peerCallFailed(ctx, "contribute", peer, err)
 				return errors.Wrap(err, "failed to send contribution")
 			}
-			if !verifyContribution(generation.id, recipientSecret, recipientVVec) {
-				log.Warn().Msg("Contribution invalid")
-				return fmt.Errorf("invalid contribution from %d", id)
+			_, finishPhase := s.startPhase(ctx, phaseVerification)
+			err = s.verifyShare(generation.id, generation.threshold, id, recipientSecret, recipientVVec)
+			finishPhase(err)
+			if err != nil {
+				// The generation cannot succeed, so abort it.
+				delete(s.generations, generation.account)
+				return err
 			}
 			if _, exists := generation.sharedSecrets[id]; exists {
 				return fmt.Errorf("duplicate contribution from %d", id)
@@ -195,6 +267,7 @@ func (s *Service) OnCommit(ctx context.Context, sender uint64, account string, c
 		return nil, nil, fmt.Errorf("have %d contributions, need %d, aborting", len(generation.sharedVVecs), len(generation.participants))
 	}
 
+	_, finishPhase := s.startPhase(ctx, phaseAggregation)
 	privateKey := bls.SecretKey{}
 	for k := range generation.sharedSecrets {
 		sharedSecret := generation.sharedSecrets[k]
@@ -206,16 +279,18 @@ func (s *Service) OnCommit(ctx context.Context, sender uint64, account string, c
 			aggregateVVec[i].Add(&sharedVVec[i])
 		}
 	}
+	finishPhase(nil)
 
 	passphrase := generation.passphrase
 	if passphrase == nil {
 		passphrase = s.generationPassphrase
 	}
-	err = s.storeDistributedKey(ctx, generation.account, passphrase, privateKey, generation.threshold, aggregateVVec, generation.participants)
+	wallet, createdAccount, err := s.storeDistributedKey(ctx, generation.account, passphrase, privateKey, generation.threshold, aggregateVVec, generation.participants)
 	if err != nil {
 		log.Warn().Err(err).Msg("Failed to create key")
 		return nil, nil, errors.Wrap(err, ErrNotCreated.Error())
 	}
+	s.notifyAccountCreated(ctx, wallet, createdAccount)
 
 	// Attempt to retrieve the key to ensure it has been stored properly.
 	walletName, accountName, err := e2wallet.WalletAndAccountNames(account)
@@ -276,9 +351,13 @@ func (s *Service) OnContribute(ctx context.Context, sender uint64, account strin
 		return bls.SecretKey{}, nil, err
 	}
 
-	if !verifyContribution(generation.id, secret, vVec) {
-		log.Warn().Uint64("sender", sender).Str("account", account).Msg("Received invalid contribution")
-		return bls.SecretKey{}, nil, fmt.Errorf("invalid contribution from %d", sender)
+	_, finishPhase := s.startPhase(ctx, phaseVerification)
+	err = s.verifyShare(generation.id, generation.threshold, sender, secret, vVec)
+	finishPhase(err)
+	if err != nil {
+		// The generation cannot succeed, so abort it.
+		delete(s.generations, account)
+		return bls.SecretKey{}, nil, err
 	}
 
 	// Store the contributed information.
@@ -290,18 +369,22 @@ func (s *Service) OnContribute(ctx context.Context, sender uint64, account strin
 
 }
 
+// storeDistributedKey stores a share of a distributed account, returning the wallet and the stored account.
 func (s *Service) storeDistributedKey(ctx context.Context,
 	account string,
 	passphrase []byte,
 	privateKey bls.SecretKey,
 	threshold uint32,
 	verificationVector []bls.PublicKey,
-	participants []*core.Endpoint) error {
+	participants []*core.Endpoint) (e2wtypes.Wallet, e2wtypes.Account, error) {
+	s.storeMu.Lock()
+	defer s.storeMu.Unlock()
+
 	store := s.stores[0]
 
 	walletName, accountName, err := e2wallet.WalletAndAccountNames(account)
 	if err != nil {
-		return errors.Wrap(err, "failed to parse account")
+		return nil, nil, errors.Wrap(err, "failed to parse account")
 	}
 
 	vVec := make([][]byte, len(verificationVector))
@@ -310,13 +393,13 @@ func (s *Service) storeDistributedKey(ctx context.Context,
 	}
 	wallet, err := distributed.OpenWallet(ctx, walletName, store, s.encryptor)
 	if err != nil {
-		return errors.Wrap(err, "failed to open wallet")
+		return nil, nil, errors.Wrap(err, "failed to open wallet")
 	}
 
 	locker, isLocker := wallet.(e2wtypes.WalletLocker)
 	if isLocker {
 		if err := locker.Unlock(ctx, []byte{}); err != nil {
-			return errors.Wrap(err, "failed to unlock wallet")
+			return nil, nil, errors.Wrap(err, "failed to unlock wallet")
 		}
 		defer func() {
 			if err := locker.Lock(ctx); err != nil {
@@ -332,12 +415,12 @@ func (s *Service) storeDistributedKey(ctx context.Context,
 	}
 	generatedAccount, err := wallet.(e2wtypes.WalletDistributedAccountImporter).ImportDistributedAccount(ctx, accountName, privateKey.Serialize(), threshold, vVec, walletParticipants, passphrase)
 	if err != nil {
-		return errors.Wrap(err, "failed to import account")
+		return nil, nil, errors.Wrap(err, "failed to import account")
 	}
 	if err := s.fetcherSvc.AddAccount(ctx, wallet, generatedAccount); err != nil {
 		// Warn but do not propagate this error.
 		log.Warn().Err(err).Msg("Failed to add account to internal cache, will be unavailable until restart")
 	}
 
-	return nil
+	return wallet, generatedAccount, nil
 }
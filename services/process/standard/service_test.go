@@ -145,7 +145,7 @@ func TestNew(t *testing.T) {
 
 	tests := []struct {
 		name                 string
-		monitor              metrics.Service
+		monitor              metrics.ProcessMonitor
 		peers                peers.Service
 		checker              checker.Service
 		stores               []e2wtypes.Store
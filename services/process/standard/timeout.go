@@ -0,0 +1,85 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/attestantio/dirk/core"
+)
+
+// defaultOperationTimeout is the default maximum time for a distributed operation.
+const defaultOperationTimeout = 30 * time.Second
+
+// abortTimeout is the maximum time to wait for a peer to abort a generation.
+const abortTimeout = 2 * time.Second
+
+// peerCallFailed is called when a call to a peer fails.  If the failure was
+// due to the operation timing out then the peer is reported.
+func (s *Service) peerCallFailed(ctx context.Context, operation string, peer *core.Endpoint, err error) {
+	if ctx.Err() != context.DeadlineExceeded {
+		return
+	}
+	log.Warn().
+		Err(err).
+		Str("operation", operation).
+		Uint64("peer_id", peer.ID).
+		Str("peer", peer.String()).
+		Dur("timeout", s.operationTimeout).
+		Msg("Distributed operation timed out waiting for peer")
+	s.monitor.DistributedOperationTimedOut(operation, peer.String())
+}
+
+// roundContext returns the context for a single round of a distributed generation,
+// bounded by the round timeout if one is configured.
+func (s *Service) roundContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if s.dkgRoundTimeout == 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, s.dkgRoundTimeout)
+}
+
+// unresponsivePeer returns the peer if the call to it failed due to the round timing out,
+// otherwise nil.
+func unresponsivePeer(ctx context.Context, peer *core.Endpoint) *core.Endpoint {
+	if ctx.Err() != context.DeadlineExceeded {
+		return nil
+	}
+	return peer
+}
+
+// abortPeers sends abort requests to the participants of a generation that has
+// timed out, so that they do not wait for it to complete.  The aborts are sent
+// in parallel with their own short timeout, as the operation context has already
+// expired and some participants may be unresponsive.
+func (s *Service) abortPeers(ctx context.Context, account string, participants []*core.Endpoint) {
+	if ctx.Err() != context.DeadlineExceeded {
+		return
+	}
+	var wg sync.WaitGroup
+	for _, participant := range participants {
+		wg.Add(1)
+		go func(participant *core.Endpoint) {
+			defer wg.Done()
+			abortCtx, cancel := context.WithTimeout(context.Background(), abortTimeout)
+			defer cancel()
+			if err := s.senderSvc.Abort(abortCtx, participant, account); err != nil {
+				log.Debug().Err(err).Str("peer", participant.String()).Msg("Failed to abort generation on peer")
+			}
+		}(participant)
+	}
+	wg.Wait()
+}
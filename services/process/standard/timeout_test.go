@@ -0,0 +1,285 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard_test
+
+import (
+	context "context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/attestantio/dirk/core"
+	"github.com/attestantio/dirk/services/checker"
+	mockchecker "github.com/attestantio/dirk/services/checker/mock"
+	memfetcher "github.com/attestantio/dirk/services/fetcher/mem"
+	staticpeers "github.com/attestantio/dirk/services/peers/static"
+	standardprocess "github.com/attestantio/dirk/services/process/standard"
+	sendermock "github.com/attestantio/dirk/services/sender/mock"
+	localunlocker "github.com/attestantio/dirk/services/unlocker/local"
+	"github.com/attestantio/dirk/testing/mock"
+	"github.com/herumi/bls-eth-go-binary/bls"
+	"github.com/stretchr/testify/require"
+	distributed "github.com/wealdtech/go-eth2-wallet-distributed"
+	keystorev4 "github.com/wealdtech/go-eth2-wallet-encryptor-keystorev4"
+	filesystem "github.com/wealdtech/go-eth2-wallet-store-filesystem"
+	scratch "github.com/wealdtech/go-eth2-wallet-store-scratch"
+	e2wtypes "github.com/wealdtech/go-eth2-wallet-types/v2"
+)
+
+// hangingSender is a sender that never receives a response from one peer.
+type hangingSender struct {
+	*sendermock.Service
+	peer uint64
+}
+
+func (s *hangingSender) SendContribution(ctx context.Context, recipient *core.Endpoint, account string, distributionSecret bls.SecretKey, verificationVector []bls.PublicKey) (bls.SecretKey, []bls.PublicKey, error) {
+	if recipient.ID == s.peer {
+		<-ctx.Done()
+		return bls.SecretKey{}, nil, ctx.Err()
+	}
+	return s.Service.SendContribution(ctx, recipient, account, distributionSecret, verificationVector)
+}
+
+// hangingPrepareSender is a sender that never receives a response from one peer when preparing.
+type hangingPrepareSender struct {
+	*sendermock.Service
+	peer uint64
+}
+
+func (s *hangingPrepareSender) Prepare(ctx context.Context, recipient *core.Endpoint, account string, passphrase []byte, threshold uint32, participants []*core.Endpoint) error {
+	if recipient.ID == s.peer {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	return s.Service.Prepare(ctx, recipient, account, passphrase, threshold, participants)
+}
+
+// orderedPeers is a peers provider that selects suitable peers in order of their ID.
+type orderedPeers struct {
+	*staticpeers.Service
+}
+
+func (p *orderedPeers) Suitable(threshold uint32) ([]*core.Endpoint, error) {
+	res := make([]*core.Endpoint, 0, threshold)
+	for i := uint64(1); len(res) < int(threshold); i++ {
+		peer, err := p.Peer(i)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, peer)
+	}
+	return res, nil
+}
+
+type timeoutMonitor struct {
+	mu       sync.Mutex
+	timeouts []string
+	phases   map[string]bool
+}
+
+func (m *timeoutMonitor) DistributedOperationTimedOut(operation string, peer string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.timeouts = append(m.timeouts, operation+" "+peer)
+}
+
+func (m *timeoutMonitor) DistributedShareVerificationFailed(peer uint64) {}
+
+func (m *timeoutMonitor) DistributedAggregationVerificationFailed(operation string) {}
+
+func (m *timeoutMonitor) DistributedGenerationPhaseCompleted(started time.Time, phase string, succeeded bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.phases == nil {
+		m.phases = make(map[string]bool)
+	}
+	m.phases[fmt.Sprintf("%s %v", phase, succeeded)] = true
+}
+
+func TestOperationTimeout(t *testing.T) {
+	ctx := context.Background()
+
+	stores := []e2wtypes.Store{scratch.New()}
+	_, err := distributed.CreateWallet(ctx, "Test", stores[0], keystorev4.New())
+	require.NoError(t, err)
+	peers, err := staticpeers.New(ctx,
+		staticpeers.WithPeers(map[uint64]string{
+			1: "signer-test01:8881",
+			2: "signer-test02:8882",
+			3: "signer-test03:8883",
+		}),
+	)
+	require.NoError(t, err)
+	checkerSvc, err := mockchecker.New()
+	require.NoError(t, err)
+	unlockerSvc, err := localunlocker.New(ctx)
+	require.NoError(t, err)
+	fetcherSvc, err := memfetcher.New(ctx,
+		memfetcher.WithStores(stores),
+	)
+	require.NoError(t, err)
+
+	params := []standardprocess.Parameter{
+		standardprocess.WithChecker(checkerSvc),
+		standardprocess.WithID(1),
+		standardprocess.WithPeers(peers),
+		standardprocess.WithSender(&hangingSender{Service: sendermock.New(1), peer: 3}),
+		standardprocess.WithFetcher(fetcherSvc),
+		standardprocess.WithStores(stores),
+		standardprocess.WithUnlocker(unlockerSvc),
+	}
+	_, err = standardprocess.New(ctx, append(params, standardprocess.WithOperationTimeout(0))...)
+	require.EqualError(t, err, "problem with parameters: operation timeout must be greater than 0")
+
+	monitor := &timeoutMonitor{}
+	service1, err := standardprocess.New(ctx, append(params,
+		standardprocess.WithMonitor(monitor),
+		standardprocess.WithOperationTimeout(100*time.Millisecond),
+	)...)
+	require.NoError(t, err)
+	mock.Processes[1] = service1
+	service2, err := createProcessService(ctx, 2)
+	require.NoError(t, err)
+	service3, err := createProcessService(ctx, 3)
+	require.NoError(t, err)
+
+	endpoints := []*core.Endpoint{
+		{ID: 1, Name: "signer-test01", Port: 8881},
+		{ID: 2, Name: "signer-test02", Port: 8882},
+		{ID: 3, Name: "signer-test03", Port: 8883},
+	}
+	require.NoError(t, service1.OnPrepare(ctx, 1, "Test/Test", []byte("test"), 2, endpoints))
+	require.NoError(t, service2.OnPrepare(ctx, 1, "Test/Test", []byte("test"), 2, endpoints))
+	require.NoError(t, service3.OnPrepare(ctx, 1, "Test/Test", []byte("test"), 2, endpoints))
+
+	// Execution should fail promptly, despite the client context having no deadline.
+	started := time.Now()
+	err = service1.OnExecute(ctx, 1, "Test/Test")
+	require.EqualError(t, err, "failed to send contribution: context deadline exceeded")
+	require.Less(t, time.Since(started), 5*time.Second)
+	require.Equal(t, []string{"contribute signer-test03:8883"}, monitor.timeouts)
+
+	// The generation lock should have been released.
+	require.NoError(t, service1.OnAbort(ctx, 1, "Test/Test"))
+}
+
+func TestDKGRoundTimeout(t *testing.T) {
+	ctx := context.Background()
+	credentials := &checker.Credentials{Client: "client1"}
+
+	tests := []struct {
+		name            string
+		retries         uint32
+		numParticipants uint32
+		participants    []uint64
+		phases          []string
+		err             string
+	}{
+		{
+			name:            "NoRetries",
+			numParticipants: 3,
+			phases:          []string{"commitment true", "prepare false"},
+			err:             "failed to prepare endpoints: context deadline exceeded (peers that failed to respond: 2)",
+		},
+		{
+			name:            "Retry",
+			retries:         1,
+			numParticipants: 3,
+			participants:    []uint64{1, 3, 4},
+			phases: []string{
+				"commitment true",
+				"prepare false",
+				"prepare true",
+				"execute true",
+				"share_exchange true",
+				"verification true",
+				"commit true",
+				"aggregation true",
+				"confirmation true",
+			},
+		},
+		{
+			name:            "NoRemainingPeers",
+			retries:         1,
+			numParticipants: 5,
+			phases:          []string{"commitment true", "prepare false"},
+			err:             "no suitable participants (peers that failed to respond: 2)",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			base, err := ioutil.TempDir("", "")
+			require.NoError(t, err)
+			defer os.RemoveAll(base)
+			fetchers := createResharingServices(ctx, t, base)
+
+			// Replace the first service with one whose prepare requests to peer 2 never complete.
+			peersMap := make(map[uint64]string)
+			for _, endpoint := range reshareEndpoints {
+				peersMap[endpoint.ID] = endpoint.ConnectAddress()
+			}
+			peers, err := staticpeers.New(ctx, staticpeers.WithPeers(peersMap))
+			require.NoError(t, err)
+			checkerSvc, err := mockchecker.New()
+			require.NoError(t, err)
+			unlockerSvc, err := localunlocker.New(ctx, localunlocker.WithAccountPassphrases([]string{reshareAccountPassphrase}))
+			require.NoError(t, err)
+			monitor := &timeoutMonitor{}
+			service, err := standardprocess.New(ctx,
+				standardprocess.WithMonitor(monitor),
+				standardprocess.WithChecker(checkerSvc),
+				standardprocess.WithGenerationPassphrase([]byte(reshareAccountPassphrase)),
+				standardprocess.WithID(1),
+				standardprocess.WithPeers(&orderedPeers{Service: peers}),
+				standardprocess.WithSender(&hangingPrepareSender{Service: sendermock.New(1), peer: 2}),
+				standardprocess.WithFetcher(fetchers[1]),
+				standardprocess.WithStores([]e2wtypes.Store{filesystem.New(filesystem.WithLocation(filepath.Join(base, "1")))}),
+				standardprocess.WithUnlocker(unlockerSvc),
+				standardprocess.WithEncryptor(keystorev4.New()),
+				standardprocess.WithDKGRoundTimeout(100*time.Millisecond),
+				standardprocess.WithDKGMaxRetries(test.retries),
+			)
+			require.NoError(t, err)
+			mock.Processes[1] = service
+
+			started := time.Now()
+			_, participants, err := service.OnGenerate(ctx, credentials, "Test/Test", []byte(reshareAccountPassphrase), 3, test.numParticipants)
+			require.Less(t, time.Since(started), 5*time.Second)
+			require.Equal(t, []string{"prepare signer-test02:8882"}, monitor.timeouts)
+			phases := make([]string, 0, len(monitor.phases))
+			for phase := range monitor.phases {
+				phases = append(phases, phase)
+			}
+			require.ElementsMatch(t, test.phases, phases)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+			} else {
+				require.NoError(t, err)
+				ids := make([]uint64, len(participants))
+				for i := range participants {
+					ids[i] = participants[i].ID
+				}
+				require.Equal(t, test.participants, ids)
+			}
+
+			// The first attempt should have been cleaned up on all participants.
+			require.Equal(t, standardprocess.ErrNotInProgress, service.OnAbort(ctx, 1, "Test/Test"))
+		})
+	}
+}
@@ -0,0 +1,150 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard_test
+
+import (
+	context "context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/attestantio/dirk/core"
+	mockchecker "github.com/attestantio/dirk/services/checker/mock"
+	memfetcher "github.com/attestantio/dirk/services/fetcher/mem"
+	staticpeers "github.com/attestantio/dirk/services/peers/static"
+	standardprocess "github.com/attestantio/dirk/services/process/standard"
+	sendermock "github.com/attestantio/dirk/services/sender/mock"
+	localunlocker "github.com/attestantio/dirk/services/unlocker/local"
+	"github.com/herumi/bls-eth-go-binary/bls"
+	"github.com/stretchr/testify/require"
+	distributed "github.com/wealdtech/go-eth2-wallet-distributed"
+	keystorev4 "github.com/wealdtech/go-eth2-wallet-encryptor-keystorev4"
+	scratch "github.com/wealdtech/go-eth2-wallet-store-scratch"
+	e2wtypes "github.com/wealdtech/go-eth2-wallet-types/v2"
+)
+
+type verificationMonitor struct {
+	mu       sync.Mutex
+	failures []uint64
+}
+
+func (m *verificationMonitor) DistributedOperationTimedOut(operation string, peer string) {}
+
+func (m *verificationMonitor) DistributedShareVerificationFailed(peer uint64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.failures = append(m.failures, peer)
+}
+
+func (m *verificationMonitor) DistributedAggregationVerificationFailed(operation string) {}
+
+func (m *verificationMonitor) DistributedGenerationPhaseCompleted(started time.Time, phase string, succeeded bool) {
+}
+
+func TestShareVerification(t *testing.T) {
+	ctx := context.Background()
+
+	endpoints := []*core.Endpoint{
+		{ID: 1, Name: "signer-test01", Port: 8881},
+		{ID: 2, Name: "signer-test02", Port: 8882},
+		{ID: 3, Name: "signer-test03", Port: 8883},
+	}
+
+	// Commitments that do not match the share.
+	var commitSK1, commitSK2, secret bls.SecretKey
+	commitSK1.SetByCSPRNG()
+	commitSK2.SetByCSPRNG()
+	secret.SetByCSPRNG()
+	vVec := []bls.PublicKey{*commitSK1.GetPublicKey(), *commitSK2.GetPublicKey()}
+
+	tests := []struct {
+		name   string
+		secret bls.SecretKey
+		vVec   []bls.PublicKey
+	}{
+		{
+			name:   "Mismatch",
+			secret: secret,
+			vVec:   vVec,
+		},
+		{
+			name:   "CommitmentsMissing",
+			secret: secret,
+			vVec:   vVec[:1],
+		},
+		{
+			name:   "CommitmentsExtra",
+			secret: secret,
+			vVec:   append([]bls.PublicKey{*secret.GetPublicKey()}, vVec...),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			monitor := &verificationMonitor{}
+			service, err := createVerificationService(ctx, monitor)
+			require.NoError(t, err)
+			require.NoError(t, service.OnPrepare(ctx, 1, "Test/Test", []byte("test"), 2, endpoints))
+
+			_, _, err = service.OnContribute(ctx, 2, "Test/Test", test.secret, test.vVec)
+			require.EqualError(t, err, "share from peer 2 failed verification against its commitments")
+			require.Equal(t, []uint64{2}, monitor.failures)
+
+			// The generation should have been aborted.
+			require.Equal(t, standardprocess.ErrNotInProgress, service.OnAbort(ctx, 1, "Test/Test"))
+		})
+	}
+}
+
+func createVerificationService(ctx context.Context, monitor *verificationMonitor) (*standardprocess.Service, error) {
+	stores := []e2wtypes.Store{scratch.New()}
+	if _, err := distributed.CreateWallet(ctx, "Test", stores[0], keystorev4.New()); err != nil {
+		return nil, err
+	}
+	peers, err := staticpeers.New(ctx,
+		staticpeers.WithPeers(map[uint64]string{
+			1: "signer-test01:8881",
+			2: "signer-test02:8882",
+			3: "signer-test03:8883",
+		}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	checkerSvc, err := mockchecker.New()
+	if err != nil {
+		return nil, err
+	}
+	unlockerSvc, err := localunlocker.New(ctx)
+	if err != nil {
+		return nil, err
+	}
+	fetcherSvc, err := memfetcher.New(ctx,
+		memfetcher.WithStores(stores),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return standardprocess.New(ctx,
+		standardprocess.WithMonitor(monitor),
+		standardprocess.WithChecker(checkerSvc),
+		standardprocess.WithID(1),
+		standardprocess.WithPeers(peers),
+		standardprocess.WithSender(sendermock.New(1)),
+		standardprocess.WithFetcher(fetcherSvc),
+		standardprocess.WithStores(stores),
+		standardprocess.WithUnlocker(unlockerSvc),
+	)
+}
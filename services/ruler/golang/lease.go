@@ -0,0 +1,87 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package golang
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/attestantio/dirk/rules"
+	"github.com/attestantio/dirk/services/checker"
+	"github.com/attestantio/dirk/services/ruler"
+)
+
+// maxProposalLease is the maximum duration of a proposal lease.  Leases must expire well within a slot, so that a
+// client that fails part-way through a proposal does not block the next legitimate proposal.
+const maxProposalLease = 6 * time.Second
+
+type proposalLeaseKey struct {
+	pubKey [48]byte
+	slot   uint64
+}
+
+type proposalLeaseHolder struct {
+	client  string
+	expires time.Time
+}
+
+// AcquireProposalLease acquires the lease for the public key and slot on behalf of the client.
+// It returns ruler.ErrProposalInProgress if another client holds a current lease.
+// If the context is check-only the lease is checked but not acquired.
+func (s *Service) AcquireProposalLease(ctx context.Context, credentials *checker.Credentials, pubKey []byte, slot uint64) error {
+	if s.proposalLease == 0 {
+		return nil
+	}
+
+	key := proposalLeaseKey{
+		slot: slot,
+	}
+	copy(key.pubKey[:], pubKey)
+
+	s.proposalLeasesMu.Lock()
+	defer s.proposalLeasesMu.Unlock()
+
+	now := time.Now()
+	// Remove expired leases.  Proposals are infrequent so the number of leases is small.
+	for k, holder := range s.proposalLeases {
+		if !holder.expires.After(now) {
+			delete(s.proposalLeases, k)
+		}
+	}
+
+	if holder, exists := s.proposalLeases[key]; exists && holder.client != credentials.Client {
+		log.Debug().
+			Str("pubkey", fmt.Sprintf("%#x", pubKey)).
+			Uint64("slot", slot).
+			Str("client", credentials.Client).
+			Str("holder", holder.client).
+			Msg("Proposal lease held by another client")
+		return ruler.ErrProposalInProgress
+	}
+
+	if rules.IsCheckOnly(ctx) {
+		return nil
+	}
+
+	// The lease is not extended if the holder requests again, so that it always expires shortly after the first request.
+	if _, exists := s.proposalLeases[key]; !exists {
+		s.proposalLeases[key] = &proposalLeaseHolder{
+			client:  credentials.Client,
+			expires: now.Add(s.proposalLease),
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package golang_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/attestantio/dirk/rules"
+	mockrules "github.com/attestantio/dirk/rules/mock"
+	"github.com/attestantio/dirk/services/checker"
+	"github.com/attestantio/dirk/services/locker/syncmap"
+	"github.com/attestantio/dirk/services/ruler"
+	"github.com/attestantio/dirk/services/ruler/golang"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAcquireProposalLease(t *testing.T) {
+	ctx := context.Background()
+	locker, err := syncmap.New(ctx)
+	require.NoError(t, err)
+
+	client1 := &checker.Credentials{Client: "client1"}
+	client2 := &checker.Credentials{Client: "client2"}
+	pubKey1 := []byte{0x01}
+	pubKey2 := []byte{0x02}
+
+	// Leases are disabled by default.
+	service, err := golang.New(ctx,
+		golang.WithLogLevel(zerolog.Disabled),
+		golang.WithLocker(locker),
+		golang.WithRules(mockrules.New()),
+	)
+	require.NoError(t, err)
+	require.NoError(t, service.AcquireProposalLease(ctx, client1, pubKey1, 1))
+	require.NoError(t, service.AcquireProposalLease(ctx, client2, pubKey1, 1))
+
+	service, err = golang.New(ctx,
+		golang.WithLogLevel(zerolog.Disabled),
+		golang.WithLocker(locker),
+		golang.WithRules(mockrules.New()),
+		golang.WithProposalLease(100*time.Millisecond),
+	)
+	require.NoError(t, err)
+
+	// A check does not acquire the lease.
+	require.NoError(t, service.AcquireProposalLease(rules.WithCheckOnly(ctx), client1, pubKey1, 1))
+	require.NoError(t, service.AcquireProposalLease(ctx, client2, pubKey1, 1))
+	// The holder can request again, and other clients are refused.
+	require.NoError(t, service.AcquireProposalLease(ctx, client2, pubKey1, 1))
+	require.Equal(t, ruler.ErrProposalInProgress, service.AcquireProposalLease(ctx, client1, pubKey1, 1))
+	require.Equal(t, ruler.ErrProposalInProgress, service.AcquireProposalLease(rules.WithCheckOnly(ctx), client1, pubKey1, 1))
+	// Leases are per public key and slot.
+	require.NoError(t, service.AcquireProposalLease(ctx, client1, pubKey1, 2))
+	require.NoError(t, service.AcquireProposalLease(ctx, client1, pubKey2, 1))
+	// The lease expires.
+	time.Sleep(150 * time.Millisecond)
+	require.NoError(t, service.AcquireProposalLease(ctx, client1, pubKey1, 1))
+	require.Equal(t, ruler.ErrProposalInProgress, service.AcquireProposalLease(ctx, client2, pubKey1, 1))
+}
@@ -0,0 +1,62 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package golang
+
+import (
+	"github.com/attestantio/dirk/rules"
+	"github.com/attestantio/dirk/services/ruler"
+	e2types "github.com/wealdtech/go-eth2-types/v2"
+)
+
+// nonSlashableDomainTypes are the domain types of generic signing requests that
+// are known to be non-slashable and are signed at high rates, so can bypass locking.
+// This list is deliberately explicit.  Beacon proposals and attestations are slashable
+// so never present, and voluntary exits are excluded as they are rare and subject to
+// additional checks.
+var nonSlashableDomainTypes = map[e2types.DomainType]bool{
+	// RANDAO reveal.
+	e2types.DomainRANDAO: true,
+	// Aggregation selection proof.
+	e2types.DomainSelectionProof: true,
+	// Aggregate and proof.
+	e2types.DomainAggregateAndProof: true,
+	// Sync committee message.
+	{0x07, 0x00, 0x00, 0x00}: true,
+	// Sync committee selection proof.
+	{0x08, 0x00, 0x00, 0x00}: true,
+	// Sync committee contribution and proof.
+	{0x09, 0x00, 0x00, 0x00}: true,
+	// Builder validator registration.
+	{0x00, 0x00, 0x00, 0x01}: true,
+}
+
+// isNonSlashable returns true if all of the requests are for generic signing of
+// non-slashable data.
+func isNonSlashable(action string, rulesData []*ruler.RulesData) bool {
+	if action != ruler.ActionSign {
+		return false
+	}
+	for i := range rulesData {
+		data, isSignData := rulesData[i].Data.(*rules.SignData)
+		if !isSignData || len(data.Domain) < 4 {
+			return false
+		}
+		var domainType e2types.DomainType
+		copy(domainType[:], data.Domain[0:4])
+		if !nonSlashableDomainTypes[domainType] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,73 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package golang_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/attestantio/dirk/rules"
+	mockrules "github.com/attestantio/dirk/rules/mock"
+	"github.com/attestantio/dirk/services/checker"
+	"github.com/attestantio/dirk/services/locker/syncmap"
+	"github.com/attestantio/dirk/services/ruler"
+	"github.com/attestantio/dirk/services/ruler/golang"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+// benchmarkNonSlashable runs concurrent RANDAO signing requests for a single
+// validator, which contend for the same lock unless the fast path is enabled.
+func benchmarkNonSlashable(b *testing.B, fastPath bool) {
+	ctx := context.Background()
+	locker, err := syncmap.New(ctx)
+	require.NoError(b, err)
+	service, err := golang.New(ctx,
+		golang.WithLogLevel(zerolog.Disabled),
+		golang.WithLocker(locker),
+		golang.WithRules(mockrules.New()),
+		golang.WithNonSlashableFastPath(fastPath),
+	)
+	require.NoError(b, err)
+
+	credentials := &checker.Credentials{Client: "client1"}
+	domain := make([]byte, 32)
+	domain[0] = 0x02
+	rulesData := []*ruler.RulesData{
+		{
+			WalletName:  "Test wallet",
+			AccountName: "Test account",
+			PubKey:      make([]byte, 48),
+			Data: &rules.SignData{
+				Domain: domain,
+				Data:   make([]byte, 32),
+			},
+		},
+	}
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			service.RunRules(ctx, credentials, ruler.ActionSign, rulesData)
+		}
+	})
+}
+
+func BenchmarkNonSlashableLocked(b *testing.B) {
+	benchmarkNonSlashable(b, false)
+}
+
+func BenchmarkNonSlashableFastPath(b *testing.B) {
+	benchmarkNonSlashable(b, true)
+}
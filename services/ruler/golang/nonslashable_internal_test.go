@@ -0,0 +1,207 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package golang
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/attestantio/dirk/rules"
+	mockrules "github.com/attestantio/dirk/rules/mock"
+	"github.com/attestantio/dirk/services/checker"
+	syncmaplocker "github.com/attestantio/dirk/services/locker/syncmap"
+	"github.com/attestantio/dirk/services/ruler"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsNonSlashable(t *testing.T) {
+	tests := []struct {
+		name    string
+		action  string
+		domains [][]byte
+		res     bool
+	}{
+		{
+			name:    "BeaconProposer",
+			action:  ruler.ActionSign,
+			domains: [][]byte{{0x00, 0x00, 0x00, 0x00}},
+		},
+		{
+			name:    "BeaconAttester",
+			action:  ruler.ActionSign,
+			domains: [][]byte{{0x01, 0x00, 0x00, 0x00}},
+		},
+		{
+			name:    "RANDAO",
+			action:  ruler.ActionSign,
+			domains: [][]byte{{0x02, 0x00, 0x00, 0x00}},
+			res:     true,
+		},
+		{
+			name:    "Deposit",
+			action:  ruler.ActionSign,
+			domains: [][]byte{{0x03, 0x00, 0x00, 0x00}},
+		},
+		{
+			name:    "VoluntaryExit",
+			action:  ruler.ActionSign,
+			domains: [][]byte{{0x04, 0x00, 0x00, 0x00}},
+		},
+		{
+			name:    "SelectionProof",
+			action:  ruler.ActionSign,
+			domains: [][]byte{{0x05, 0x00, 0x00, 0x00}},
+			res:     true,
+		},
+		{
+			name:    "AggregateAndProof",
+			action:  ruler.ActionSign,
+			domains: [][]byte{{0x06, 0x00, 0x00, 0x00}},
+			res:     true,
+		},
+		{
+			name:    "SyncCommittee",
+			action:  ruler.ActionSign,
+			domains: [][]byte{{0x07, 0x00, 0x00, 0x00}},
+			res:     true,
+		},
+		{
+			name:    "SyncCommitteeSelectionProof",
+			action:  ruler.ActionSign,
+			domains: [][]byte{{0x08, 0x00, 0x00, 0x00}},
+			res:     true,
+		},
+		{
+			name:    "ContributionAndProof",
+			action:  ruler.ActionSign,
+			domains: [][]byte{{0x09, 0x00, 0x00, 0x00}},
+			res:     true,
+		},
+		{
+			name:    "ApplicationBuilder",
+			action:  ruler.ActionSign,
+			domains: [][]byte{{0x00, 0x00, 0x00, 0x01}},
+			res:     true,
+		},
+		{
+			name:    "UnknownDomain",
+			action:  ruler.ActionSign,
+			domains: [][]byte{{0x0a, 0x00, 0x00, 0x00}},
+		},
+		{
+			name:    "ShortDomain",
+			action:  ruler.ActionSign,
+			domains: [][]byte{{0x02, 0x00}},
+		},
+		{
+			name:    "Mixed",
+			action:  ruler.ActionSign,
+			domains: [][]byte{{0x02, 0x00, 0x00, 0x00}, {0x01, 0x00, 0x00, 0x00}},
+		},
+		{
+			name:    "Multiple",
+			action:  ruler.ActionSign,
+			domains: [][]byte{{0x02, 0x00, 0x00, 0x00}, {0x05, 0x00, 0x00, 0x00}},
+			res:     true,
+		},
+		{
+			name:    "OtherAction",
+			action:  ruler.ActionSignBeaconAttestation,
+			domains: [][]byte{{0x02, 0x00, 0x00, 0x00}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rulesData := make([]*ruler.RulesData, len(test.domains))
+			for i := range test.domains {
+				rulesData[i] = &ruler.RulesData{
+					Data: &rules.SignData{Domain: test.domains[i]},
+				}
+			}
+			require.Equal(t, test.res, isNonSlashable(test.action, rulesData))
+		})
+	}
+}
+
+// countingLocker is a locker that counts the number of locks obtained.
+type countingLocker struct {
+	*syncmaplocker.Service
+	locks int32
+}
+
+func (l *countingLocker) Lock(key [48]byte) {
+	atomic.AddInt32(&l.locks, 1)
+	l.Service.Lock(key)
+}
+
+func TestNonSlashableFastPath(t *testing.T) {
+	ctx := context.Background()
+	credentials := &checker.Credentials{Client: "client1"}
+	pubKey := make([]byte, 48)
+
+	tests := []struct {
+		name     string
+		fastPath bool
+		domain   []byte
+		locks    int32
+	}{
+		{
+			name:   "Disabled",
+			domain: []byte{0x02, 0x00, 0x00, 0x00},
+			locks:  1,
+		},
+		{
+			name:     "Enabled",
+			fastPath: true,
+			domain:   []byte{0x02, 0x00, 0x00, 0x00},
+			locks:    0,
+		},
+		{
+			name:     "EnabledSlashableDomain",
+			fastPath: true,
+			domain:   []byte{0x01, 0x00, 0x00, 0x00},
+			locks:    1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			syncmapLocker, err := syncmaplocker.New(ctx)
+			require.NoError(t, err)
+			locker := &countingLocker{Service: syncmapLocker}
+			s, err := New(ctx,
+				WithLocker(locker),
+				WithRules(mockrules.New()),
+				WithNonSlashableFastPath(test.fastPath),
+			)
+			require.NoError(t, err)
+
+			results := s.RunRules(ctx, credentials, ruler.ActionSign, []*ruler.RulesData{
+				{
+					WalletName:  "Test wallet",
+					AccountName: "Test account",
+					PubKey:      pubKey,
+					Data: &rules.SignData{
+						Domain: append(test.domain, make([]byte, 28)...),
+						Data:   make([]byte, 32),
+					},
+				},
+			})
+			require.Equal(t, []rules.Result{rules.APPROVED}, results)
+			require.Equal(t, test.locks, atomic.LoadInt32(&locker.locks))
+		})
+	}
+}
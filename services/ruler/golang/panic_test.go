@@ -0,0 +1,82 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package golang_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/attestantio/dirk/rules"
+	mockrules "github.com/attestantio/dirk/rules/mock"
+	"github.com/attestantio/dirk/services/checker"
+	"github.com/attestantio/dirk/services/locker/syncmap"
+	"github.com/attestantio/dirk/services/ruler"
+	"github.com/attestantio/dirk/services/ruler/golang"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+// panickingRules is a rules service that panics on the first beacon proposal.
+type panickingRules struct {
+	*mockrules.Service
+	once sync.Once
+}
+
+func (r *panickingRules) OnSignBeaconProposal(ctx context.Context, metadata *rules.ReqMetadata, req *rules.SignBeaconProposalData) rules.Result {
+	r.once.Do(func() {
+		panic("rules failure")
+	})
+	return r.Service.OnSignBeaconProposal(ctx, metadata, req)
+}
+
+func TestRunRulesPanicReleasesLock(t *testing.T) {
+	ctx := context.Background()
+	locker, err := syncmap.New(ctx)
+	require.NoError(t, err)
+	service, err := golang.New(ctx,
+		golang.WithLogLevel(zerolog.Disabled),
+		golang.WithLocker(locker),
+		golang.WithRules(&panickingRules{Service: mockrules.New()}),
+	)
+	require.NoError(t, err)
+
+	credentials := &checker.Credentials{Client: "client1"}
+	rulesData := []*ruler.RulesData{
+		{
+			WalletName:  "Test wallet",
+			AccountName: "Test account",
+			PubKey:      make([]byte, 48),
+			Data:        &rules.SignBeaconProposalData{Slot: 1},
+		},
+	}
+
+	// The first request panics.
+	require.Panics(t, func() {
+		service.RunRules(ctx, credentials, ruler.ActionSignBeaconProposal, rulesData)
+	})
+
+	// A subsequent request for the same key must not be blocked by the panicked request.
+	done := make(chan []rules.Result)
+	go func() {
+		done <- service.RunRules(ctx, credentials, ruler.ActionSignBeaconProposal, rulesData)
+	}()
+	select {
+	case results := <-done:
+		require.Equal(t, []rules.Result{rules.APPROVED}, results)
+	case <-time.After(time.Second):
+		require.Fail(t, "lock leaked by panicked request")
+	}
+}
@@ -14,6 +14,8 @@
 package golang
 
 import (
+	"time"
+
 	"github.com/attestantio/dirk/rules"
 	"github.com/attestantio/dirk/services/locker"
 	"github.com/attestantio/dirk/services/metrics"
@@ -26,6 +28,10 @@ type parameters struct {
 	monitor  metrics.RulerMonitor
 	rules    rules.Service
 	locker   locker.Service
+	// proposalLease is the duration of proposal leases; 0 if disabled.
+	proposalLease time.Duration
+	// nonSlashableFastPath is true if non-slashable requests bypass locking.
+	nonSlashableFastPath bool
 }
 
 // Parameter is the interface for service parameters.
@@ -67,6 +73,20 @@ func WithRules(rules rules.Service) Parameter {
 	})
 }
 
+// WithProposalLease sets the duration of proposal leases.  If not set, or set to 0, proposal leases are disabled.
+func WithProposalLease(duration time.Duration) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.proposalLease = duration
+	})
+}
+
+// WithNonSlashableFastPath sets whether generic signing requests for non-slashable domains bypass locking.
+func WithNonSlashableFastPath(enabled bool) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.nonSlashableFastPath = enabled
+	})
+}
+
 // parseAndCheckParameters parses and checks parameters to ensure that mandatory parameters are present and correct.
 func parseAndCheckParameters(params ...Parameter) (*parameters, error) {
 	parameters := parameters{
@@ -88,6 +108,12 @@ func parseAndCheckParameters(params ...Parameter) (*parameters, error) {
 	if parameters.rules == nil {
 		return nil, errors.New("no rules specified")
 	}
+	if parameters.proposalLease < 0 {
+		return nil, errors.New("proposal lease cannot be negative")
+	}
+	if parameters.proposalLease > maxProposalLease {
+		return nil, errors.Errorf("proposal lease cannot be more than %v", maxProposalLease)
+	}
 
 	return &parameters, nil
 }
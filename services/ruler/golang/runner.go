@@ -57,8 +57,9 @@ func (s *Service) RunRules(ctx context.Context,
 		}
 	}
 
-	// Only some actions require locking.
-	if action == ruler.ActionSign ||
+	// Only some actions require locking.  Non-slashable requests do not update any
+	// state, so can bypass locking if the fast path is enabled.
+	if (action == ruler.ActionSign && !(s.nonSlashableFastPath && isNonSlashable(action, rulesData))) ||
 		action == ruler.ActionSignBeaconProposal ||
 		action == ruler.ActionSignBeaconAttestation {
 		// We cannot allow multiple requests for the same public key.
@@ -79,23 +80,46 @@ func (s *Service) RunRules(ctx context.Context,
 			pubKeyMap[key] = true
 		}
 
-		// Throw a lock around the entire locking process.  This avoids situations where two concurrent
-		// goroutines try locking (a,b) and (b,a), respectively, and cause a deadlock.
-		s.locker.PreLock()
-		// Lock each public key as we come to it, to ensure that there can only be a single active rule
-		// (and hence data update) for a given public key at any time.
+		lockKeys := make([][48]byte, len(rulesData))
 		for i := range rulesData {
-			var lockKey [48]byte
-			copy(lockKey[:], rulesData[i].PubKey)
-			s.locker.Lock(lockKey)
-			defer s.locker.Unlock(lockKey)
+			copy(lockKeys[i][:], rulesData[i].PubKey)
 		}
-		s.locker.PostLock()
+		s.lock(lockKeys)
+		// Unlocking is deferred so that the locks are released even if the rules panic.
+		defer s.unlock(lockKeys)
 	}
 
 	return s.runRules(ctx, credentials, action, rulesData)
 }
 
+// lock locks each of the supplied keys, to ensure that there can only be a single active rule
+// (and hence data update) for a given public key at any time.  If locking panics then any
+// locks already obtained are released.
+func (s *Service) lock(keys [][48]byte) {
+	// Throw a lock around the entire locking process.  This avoids situations where two concurrent
+	// goroutines try locking (a,b) and (b,a), respectively, and cause a deadlock.
+	s.locker.PreLock()
+	defer s.locker.PostLock()
+
+	locked := 0
+	defer func() {
+		if locked != len(keys) {
+			s.unlock(keys[:locked])
+		}
+	}()
+	for i := range keys {
+		s.locker.Lock(keys[i])
+		locked++
+	}
+}
+
+// unlock unlocks each of the supplied keys.
+func (s *Service) unlock(keys [][48]byte) {
+	for i := range keys {
+		s.locker.Unlock(keys[i])
+	}
+}
+
 // runRules runs a number of rules and returns a result.
 // It assumes that validation checks have already been carried out against the data, and that
 // suitable locks are held against the relevant public keys.
@@ -204,6 +228,61 @@ func (s *Service) runRules(ctx context.Context,
 					continue
 				}
 				results[i] = s.rules.OnCreateAccount(ctx, metadata, reqData)
+			case ruler.ActionSignTagged:
+				// Tagged messages are not slashable, so there is nothing for the rules to check.
+				if _, isExpectedType := rulesData[i].Data.(*rules.SignTaggedData); !isExpectedType {
+					log.Warn().Msg("Data not of expected type")
+					results[i] = rules.FAILED
+					continue
+				}
+				results[i] = rules.APPROVED
+			case ruler.ActionSignRoot:
+				reqData, isExpectedType := rulesData[i].Data.(*rules.SignRootData)
+				if !isExpectedType {
+					log.Warn().Msg("Data not of expected type")
+					results[i] = rules.FAILED
+					continue
+				}
+				// Access to root signing is controlled by permissions, so rules that do not check it approve it.
+				if approver, isApprover := s.rules.(rules.RootSigningApprover); isApprover {
+					results[i] = approver.OnSignRoot(ctx, metadata, reqData)
+				} else {
+					results[i] = rules.APPROVED
+				}
+			case ruler.ActionSignBLSToExecutionChange:
+				reqData, isExpectedType := rulesData[i].Data.(*rules.SignBLSToExecutionChangeData)
+				if !isExpectedType {
+					log.Warn().Msg("Data not of expected type")
+					results[i] = rules.FAILED
+					continue
+				}
+				// BLS to execution changes are not slashable, so rules that do not check them approve them.
+				if approver, isApprover := s.rules.(rules.BLSToExecutionChangeApprover); isApprover {
+					results[i] = approver.OnSignBLSToExecutionChange(ctx, metadata, reqData)
+				} else {
+					results[i] = rules.APPROVED
+				}
+			case ruler.ActionSignValidatorRegistration:
+				reqData, isExpectedType := rulesData[i].Data.(*rules.SignValidatorRegistrationData)
+				if !isExpectedType {
+					log.Warn().Msg("Data not of expected type")
+					results[i] = rules.FAILED
+					continue
+				}
+				// Validator registrations are not slashable, so rules that do not check them approve them.
+				if approver, isApprover := s.rules.(rules.ValidatorRegistrationApprover); isApprover {
+					results[i] = approver.OnSignValidatorRegistration(ctx, metadata, reqData)
+				} else {
+					results[i] = rules.APPROVED
+				}
+			case ruler.ActionGenerateDepositData:
+				// Deposits are not slashable, so there is nothing for the rules to check.
+				if _, isExpectedType := rulesData[i].Data.(*rules.GenerateDepositData); !isExpectedType {
+					log.Warn().Msg("Data not of expected type")
+					results[i] = rules.FAILED
+					continue
+				}
+				results[i] = rules.APPROVED
 			default:
 				log.Warn().Str("action", action).Msg("Unknown action")
 				results[i] = rules.FAILED
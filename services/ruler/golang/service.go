@@ -15,6 +15,8 @@ package golang
 
 import (
 	"context"
+	"sync"
+	"time"
 
 	"github.com/attestantio/dirk/rules"
 	"github.com/attestantio/dirk/services/locker"
@@ -29,6 +31,12 @@ type Service struct {
 	monitor metrics.RulerMonitor
 	locker  locker.Service
 	rules   rules.Service
+
+	proposalLease    time.Duration
+	proposalLeases   map[proposalLeaseKey]*proposalLeaseHolder
+	proposalLeasesMu sync.Mutex
+
+	nonSlashableFastPath bool
 }
 
 // module-wide log.
@@ -51,6 +59,11 @@ func New(ctx context.Context, params ...Parameter) (*Service, error) {
 		monitor: parameters.monitor,
 		locker:  parameters.locker,
 		rules:   parameters.rules,
+
+		proposalLease:  parameters.proposalLease,
+		proposalLeases: make(map[proposalLeaseKey]*proposalLeaseHolder),
+
+		nonSlashableFastPath: parameters.nonSlashableFastPath,
 	}
 
 	return s, nil
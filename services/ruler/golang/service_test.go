@@ -16,6 +16,7 @@ package golang_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	mockrules "github.com/attestantio/dirk/rules/mock"
 	"github.com/attestantio/dirk/services/locker/syncmap"
@@ -60,6 +61,28 @@ func TestNew(t *testing.T) {
 			},
 			err: "problem with parameters: no rules specified",
 		},
+		{
+			name: "ProposalLeaseNegative",
+			parameters: []golang.Parameter{
+				golang.WithLogLevel(zerolog.Disabled),
+				golang.WithMonitor(monitor),
+				golang.WithLocker(locker),
+				golang.WithRules(rules),
+				golang.WithProposalLease(-time.Second),
+			},
+			err: "problem with parameters: proposal lease cannot be negative",
+		},
+		{
+			name: "ProposalLeaseTooLong",
+			parameters: []golang.Parameter{
+				golang.WithLogLevel(zerolog.Disabled),
+				golang.WithMonitor(monitor),
+				golang.WithLocker(locker),
+				golang.WithRules(rules),
+				golang.WithProposalLease(time.Minute),
+			},
+			err: "problem with parameters: proposal lease cannot be more than 6s",
+		},
 		{
 			name: "Good",
 			parameters: []golang.Parameter{
@@ -18,6 +18,7 @@ import (
 
 	"github.com/attestantio/dirk/rules"
 	"github.com/attestantio/dirk/services/checker"
+	"github.com/pkg/errors"
 )
 
 var (
@@ -27,10 +28,26 @@ var (
 	ActionSignBeaconAttestation = "Sign beacon attestation"
 	// ActionSignBeaconProposal is the action of signing a beacon proposal.
 	ActionSignBeaconProposal = "Sign beacon proposal"
+	// ActionSignTagged is the action of signing a tagged message.
+	ActionSignTagged = "Sign tagged"
+	// ActionSignRoot is the action of signing a client-supplied signing root.
+	ActionSignRoot = "Sign root"
+	// ActionSignBLSToExecutionChange is the action of signing a BLS to execution change.
+	ActionSignBLSToExecutionChange = "Sign BLS to execution change"
+	// ActionSignValidatorRegistration is the action of signing a validator registration.
+	ActionSignValidatorRegistration = "Sign validator registration"
+	// ActionCheckSign is the action of checking if a signing request would be approved.
+	ActionCheckSign = "Check sign"
+	// ActionGenerateDepositData is the action of generating deposit data.
+	ActionGenerateDepositData = "Generate deposit data"
 	// ActionAccessAccount is the action of accessing an account.
 	ActionAccessAccount = "Access account"
 	// ActionCreateAccount is the action of creating an account.
 	ActionCreateAccount = "Create account"
+	// ActionDeleteAccount is the action of deleting an account.
+	ActionDeleteAccount = "Delete account"
+	// ActionReshareAccount is the action of resharing a distributed account.
+	ActionReshareAccount = "Reshare account"
 	// ActionLockWallet is the action of locking a wallet.
 	ActionLockWallet = "Lock wallet"
 	// ActionUnlockWallet is the action of unlocking a wallet.
@@ -41,6 +58,27 @@ var (
 	ActionUnlockAccount = "Unlock account"
 )
 
+// Actions are all of the actions that can be named in permissions.
+var Actions = []string{
+	ActionSign,
+	ActionSignBeaconAttestation,
+	ActionSignBeaconProposal,
+	ActionSignTagged,
+	ActionSignRoot,
+	ActionSignBLSToExecutionChange,
+	ActionSignValidatorRegistration,
+	ActionCheckSign,
+	ActionGenerateDepositData,
+	ActionAccessAccount,
+	ActionCreateAccount,
+	ActionDeleteAccount,
+	ActionReshareAccount,
+	ActionLockWallet,
+	ActionUnlockWallet,
+	ActionLockAccount,
+	ActionUnlockAccount,
+}
+
 // RulesData contains data for the rules.
 type RulesData struct {
 	WalletName  string
@@ -54,3 +92,15 @@ type Service interface {
 	// RunRules runs a set of rules for the given information.
 	RunRules(context.Context, *checker.Credentials, string, []*RulesData) []rules.Result
 }
+
+// ErrProposalInProgress is returned when a proposal lease is held by another client.
+var ErrProposalInProgress = errors.New("proposal already in progress")
+
+// ProposalLeaser is the interface for a ruler that provides short-lived leases to
+// ensure that only a single client proposes for a given validator and slot.
+type ProposalLeaser interface {
+	// AcquireProposalLease acquires the lease for the public key and slot on behalf of the client.
+	// It returns ErrProposalInProgress if another client holds a current lease.
+	// If the context is check-only the lease is checked but not acquired.
+	AcquireProposalLease(ctx context.Context, credentials *checker.Credentials, pubKey []byte, slot uint64) error
+}
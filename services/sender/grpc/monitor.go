@@ -13,6 +13,12 @@
 
 package grpc
 
+import "time"
+
 // noopMonitor is a monitor that does nothing, used in place of nil if an
 // external monitor is not supplied.
 type noopMonitor struct{}
+
+// PeerRequestCompleted is called when a request to a peer has completed.
+func (m *noopMonitor) PeerRequestCompleted(started time.Time, peer uint64, rpc string, succeeded bool) {
+}
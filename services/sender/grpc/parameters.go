@@ -14,6 +14,8 @@
 package grpc
 
 import (
+	"crypto/tls"
+
 	"github.com/attestantio/dirk/services/metrics"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
@@ -26,6 +28,9 @@ type parameters struct {
 	serverCert []byte
 	serverKey  []byte
 	caCert     []byte
+
+	tlsMinVersion   uint16
+	tlsCipherSuites []uint16
 }
 
 // Parameter is the interface for service parameters.
@@ -81,10 +86,26 @@ func WithCACert(caCert []byte) Parameter {
 	})
 }
 
+// WithTLSMinVersion sets the minimum TLS version for connections.
+func WithTLSMinVersion(version uint16) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.tlsMinVersion = version
+	})
+}
+
+// WithTLSCipherSuites sets the cipher suites permitted for TLS 1.2 connections.
+// If not supplied the Go TLS library defaults are used.
+func WithTLSCipherSuites(suites []uint16) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.tlsCipherSuites = suites
+	})
+}
+
 // parseAndCheckParameters parses and checks parameters to ensure that mandatory parameters are present and correct.
 func parseAndCheckParameters(params ...Parameter) (*parameters, error) {
 	parameters := parameters{
-		logLevel: zerolog.GlobalLevel(),
+		logLevel:      zerolog.GlobalLevel(),
+		tlsMinVersion: tls.VersionTLS13,
 	}
 	for _, p := range params {
 		if params != nil {
@@ -105,6 +126,12 @@ func parseAndCheckParameters(params ...Parameter) (*parameters, error) {
 	if len(parameters.serverKey) == 0 {
 		return nil, errors.New("no server key specified")
 	}
+	if parameters.tlsMinVersion != tls.VersionTLS12 && parameters.tlsMinVersion != tls.VersionTLS13 {
+		return nil, errors.New("TLS minimum version must be 1.2 or 1.3")
+	}
+	if len(parameters.tlsCipherSuites) > 0 && parameters.tlsMinVersion == tls.VersionTLS13 {
+		return nil, errors.New("TLS cipher suites cannot be configured when the minimum TLS version is 1.3")
+	}
 
 	return &parameters, nil
 }
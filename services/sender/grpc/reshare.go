@@ -0,0 +1,179 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/attestantio/dirk/core"
+	dirkpb "github.com/attestantio/dirk/pb/v1"
+	"github.com/herumi/bls-eth-go-binary/bls"
+	"github.com/pkg/errors"
+	pb "github.com/wealdtech/eth2-signer-api/pb/v1"
+	"google.golang.org/grpc"
+)
+
+// PrepareReshare sends a request to the given participant to prepare for resharing.
+func (s *Service) PrepareReshare(ctx context.Context,
+	peer *core.Endpoint,
+	account string,
+	passphrase []byte,
+	threshold uint32,
+	participants []*core.Endpoint,
+	dealers []uint64,
+	verificationVector []bls.PublicKey) (err error) {
+	defer s.requestCompleted(time.Now(), peer, "prepare reshare", &err)
+	connResource, err := s.obtainConnection(ctx, peer.ConnectAddress())
+	if err != nil {
+		return errors.Wrap(err, "Failed to obtain connection for PrepareReshare()")
+	}
+	defer connResource.Release()
+	client := dirkpb.NewReshareClient(connResource.Value().(*grpc.ClientConn))
+
+	pbParticipants := make([]*pb.Endpoint, len(participants))
+	for i, participant := range participants {
+		pbParticipants[i] = &pb.Endpoint{
+			Id:   participant.ID,
+			Name: participant.Name,
+			Port: participant.Port,
+		}
+	}
+	vVec := make([][]byte, len(verificationVector))
+	for i, key := range verificationVector {
+		vVec[i] = key.Serialize()
+	}
+	req := &dirkpb.PrepareReshareRequest{
+		Account:            account,
+		Passphrase:         passphrase,
+		Threshold:          threshold,
+		Participants:       pbParticipants,
+		Dealers:            dealers,
+		VerificationVector: vVec,
+	}
+	if _, err := client.Prepare(ctx, req); err != nil {
+		return errors.Wrap(err, "Failed to call PrepareReshare()")
+	}
+	return nil
+}
+
+// ExecuteReshare sends a request to the given dealer to send its contributions for resharing.
+func (s *Service) ExecuteReshare(ctx context.Context, peer *core.Endpoint, account string) (err error) {
+	defer s.requestCompleted(time.Now(), peer, "execute reshare", &err)
+	connResource, err := s.obtainConnection(ctx, peer.ConnectAddress())
+	if err != nil {
+		return errors.Wrap(err, "Failed to obtain connection for ExecuteReshare()")
+	}
+	defer connResource.Release()
+	client := dirkpb.NewReshareClient(connResource.Value().(*grpc.ClientConn))
+
+	if _, err := client.Execute(ctx, &dirkpb.ReshareRequest{Account: account}); err != nil {
+		return errors.Wrap(err, "Failed to call ExecuteReshare()")
+	}
+	return nil
+}
+
+// SendReshareContribution sends a contribution for resharing to a recipient.
+func (s *Service) SendReshareContribution(ctx context.Context, peer *core.Endpoint, account string, secret bls.SecretKey, verificationVector []bls.PublicKey) (err error) {
+	defer s.requestCompleted(time.Now(), peer, "reshare contribute", &err)
+	connResource, err := s.obtainConnection(ctx, peer.ConnectAddress())
+	if err != nil {
+		return errors.Wrap(err, "Failed to obtain connection for SendReshareContribution()")
+	}
+	defer connResource.Release()
+	client := dirkpb.NewReshareClient(connResource.Value().(*grpc.ClientConn))
+
+	vVec := make([][]byte, len(verificationVector))
+	for i, key := range verificationVector {
+		vVec[i] = key.Serialize()
+	}
+	req := &dirkpb.ContributeReshareRequest{
+		Account:            account,
+		Secret:             secret.Serialize(),
+		VerificationVector: vVec,
+	}
+	if _, err := client.Contribute(ctx, req); err != nil {
+		return errors.Wrap(err, "Failed to call ContributeReshare()")
+	}
+	return nil
+}
+
+// ConfirmReshare sends a request to the given participant to confirm its new share.
+func (s *Service) ConfirmReshare(ctx context.Context, peer *core.Endpoint, account string, confirmationData []byte) (pubKey []byte, confirmationSig []byte, err error) {
+	defer s.requestCompleted(time.Now(), peer, "confirm reshare", &err)
+	connResource, err := s.obtainConnection(ctx, peer.ConnectAddress())
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Failed to obtain connection for ConfirmReshare()")
+	}
+	defer connResource.Release()
+	client := dirkpb.NewReshareClient(connResource.Value().(*grpc.ClientConn))
+
+	req := &dirkpb.ConfirmReshareRequest{
+		Account:          account,
+		ConfirmationData: confirmationData,
+	}
+	res, err := client.Confirm(ctx, req)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Failed to call ConfirmReshare()")
+	}
+	return res.PublicKey, res.ConfirmationSignature, nil
+}
+
+// CommitReshare sends a request to the given participant to store its new share.
+func (s *Service) CommitReshare(ctx context.Context, peer *core.Endpoint, account string) (err error) {
+	defer s.requestCompleted(time.Now(), peer, "commit reshare", &err)
+	connResource, err := s.obtainConnection(ctx, peer.ConnectAddress())
+	if err != nil {
+		return errors.Wrap(err, "Failed to obtain connection for CommitReshare()")
+	}
+	defer connResource.Release()
+	client := dirkpb.NewReshareClient(connResource.Value().(*grpc.ClientConn))
+
+	if _, err := client.Commit(ctx, &dirkpb.ReshareRequest{Account: account}); err != nil {
+		return errors.Wrap(err, "Failed to call CommitReshare()")
+	}
+	return nil
+}
+
+// CompleteReshare sends a request to the given participant to complete resharing.
+func (s *Service) CompleteReshare(ctx context.Context, peer *core.Endpoint, account string) (err error) {
+	defer s.requestCompleted(time.Now(), peer, "complete reshare", &err)
+	connResource, err := s.obtainConnection(ctx, peer.ConnectAddress())
+	if err != nil {
+		return errors.Wrap(err, "Failed to obtain connection for CompleteReshare()")
+	}
+	defer connResource.Release()
+	client := dirkpb.NewReshareClient(connResource.Value().(*grpc.ClientConn))
+
+	if _, err := client.Complete(ctx, &dirkpb.ReshareRequest{Account: account}); err != nil {
+		return errors.Wrap(err, "Failed to call CompleteReshare()")
+	}
+	return nil
+}
+
+// AbortReshare sends a request to the given participant to abort resharing.
+func (s *Service) AbortReshare(ctx context.Context, peer *core.Endpoint, account string) (err error) {
+	defer s.requestCompleted(time.Now(), peer, "abort reshare", &err)
+	connResource, err := s.obtainConnection(ctx, peer.ConnectAddress())
+	if err != nil {
+		return errors.Wrap(err, "Failed to obtain connection for AbortReshare()")
+	}
+	defer connResource.Release()
+	client := dirkpb.NewReshareClient(connResource.Value().(*grpc.ClientConn))
+
+	if _, err := client.Abort(ctx, &dirkpb.ReshareRequest{Account: account}); err != nil {
+		return errors.Wrap(err, "Failed to call AbortReshare()")
+	}
+	return nil
+}
@@ -17,9 +17,12 @@ import (
 	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"fmt"
 	"sync"
+	"time"
 
 	"github.com/attestantio/dirk/core"
+	"github.com/attestantio/dirk/services/metrics"
 	"github.com/herumi/bls-eth-go-binary/bls"
 	"github.com/jackc/puddle"
 	"github.com/pkg/errors"
@@ -28,11 +31,13 @@ import (
 	pb "github.com/wealdtech/eth2-signer-api/pb/v1"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
 // Service is used to manage the sender piece of distributed key generation operations.
 type Service struct {
 	name                 string
+	monitor              metrics.SenderMonitor
 	credentials          credentials.TransportCredentials
 	connectionPoolsMutex sync.Mutex
 	connectionPools      map[string]*puddle.Pool
@@ -54,13 +59,14 @@ func New(ctx context.Context, params ...Parameter) (*Service, error) {
 		log = log.Level(parameters.logLevel)
 	}
 
-	credentials, err := composeCredentials(ctx, parameters.serverCert, parameters.serverKey, parameters.caCert)
+	credentials, err := composeCredentials(ctx, parameters.serverCert, parameters.serverKey, parameters.caCert, parameters.tlsMinVersion, parameters.tlsCipherSuites)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to compose client credentials")
 	}
 
 	service := &Service{
 		name:            parameters.name,
+		monitor:         parameters.monitor,
 		credentials:     credentials,
 		connectionPools: make(map[string]*puddle.Pool),
 	}
@@ -73,7 +79,8 @@ func (s *Service) Prepare(ctx context.Context,
 	account string,
 	passphrase []byte,
 	threshold uint32,
-	participants []*core.Endpoint) error {
+	participants []*core.Endpoint) (err error) {
+	defer s.requestCompleted(time.Now(), peer, "prepare", &err)
 	connResource, err := s.obtainConnection(ctx, peer.ConnectAddress())
 	if err != nil {
 		return errors.Wrap(err, "Failed to obtain connection for Prepare()")
@@ -102,7 +109,8 @@ func (s *Service) Prepare(ctx context.Context,
 }
 
 // Execute sends a request to the given participant to execute the given DKG.
-func (s *Service) Execute(ctx context.Context, peer *core.Endpoint, account string) error {
+func (s *Service) Execute(ctx context.Context, peer *core.Endpoint, account string) (err error) {
+	defer s.requestCompleted(time.Now(), peer, "execute", &err)
 	connResource, err := s.obtainConnection(ctx, peer.ConnectAddress())
 	if err != nil {
 		return errors.Wrap(err, "Failed to obtain connection for Execute()")
@@ -120,7 +128,8 @@ func (s *Service) Execute(ctx context.Context, peer *core.Endpoint, account stri
 }
 
 // Commit sends a request to the given participant to commit the given DKG.
-func (s *Service) Commit(ctx context.Context, peer *core.Endpoint, account string, confirmationData []byte) ([]byte, []byte, error) {
+func (s *Service) Commit(ctx context.Context, peer *core.Endpoint, account string, confirmationData []byte) (pubKey []byte, confirmationSig []byte, err error) {
+	defer s.requestCompleted(time.Now(), peer, "commit", &err)
 	connResource, err := s.obtainConnection(ctx, peer.ConnectAddress())
 	if err != nil {
 		return nil, nil, errors.Wrap(err, "Failed to obtain connection for Commit()")
@@ -140,7 +149,8 @@ func (s *Service) Commit(ctx context.Context, peer *core.Endpoint, account strin
 }
 
 // Abort sends a request to the given participant to abort the given DKG.
-func (s *Service) Abort(ctx context.Context, peer *core.Endpoint, account string) error {
+func (s *Service) Abort(ctx context.Context, peer *core.Endpoint, account string) (err error) {
+	defer s.requestCompleted(time.Now(), peer, "abort", &err)
 	connResource, err := s.obtainConnection(ctx, peer.ConnectAddress())
 	if err != nil {
 		return errors.Wrap(err, "Failed to obtain connection for Execute()")
@@ -158,7 +168,8 @@ func (s *Service) Abort(ctx context.Context, peer *core.Endpoint, account string
 }
 
 // SendContribution sends a contribution to a recipient.
-func (s *Service) SendContribution(ctx context.Context, peer *core.Endpoint, account string, distributionSecret bls.SecretKey, verificationVector []bls.PublicKey) (bls.SecretKey, []bls.PublicKey, error) {
+func (s *Service) SendContribution(ctx context.Context, peer *core.Endpoint, account string, distributionSecret bls.SecretKey, verificationVector []bls.PublicKey) (contributionSecret bls.SecretKey, contributionVVec []bls.PublicKey, err error) {
+	defer s.requestCompleted(time.Now(), peer, "contribute", &err)
 	connResource, err := s.obtainConnection(ctx, peer.ConnectAddress())
 	if err != nil {
 		return bls.SecretKey{}, nil, errors.Wrap(err, "Failed to obtain connection for SendContribution()")
@@ -195,7 +206,33 @@ func (s *Service) SendContribution(ctx context.Context, peer *core.Endpoint, acc
 	return resSecret, resVVec, nil
 }
 
-func composeCredentials(ctx context.Context, certPEMBlock []byte, keyPEMBlock []byte, caPEMBlock []byte) (credentials.TransportCredentials, error) {
+// Probe checks that the given peer is reachable and ready to serve requests,
+// using the standard gRPC health service.
+func (s *Service) Probe(ctx context.Context, peer *core.Endpoint) (err error) {
+	defer s.requestCompleted(time.Now(), peer, "probe", &err)
+	connResource, err := s.obtainConnection(ctx, peer.ConnectAddress())
+	if err != nil {
+		return errors.Wrap(err, "Failed to obtain connection for Probe()")
+	}
+	defer connResource.Release()
+	client := healthpb.NewHealthClient(connResource.Value().(*grpc.ClientConn))
+
+	res, err := client.Check(ctx, &healthpb.HealthCheckRequest{})
+	if err != nil {
+		return errors.Wrap(err, "Failed to call Check()")
+	}
+	if res.Status != healthpb.HealthCheckResponse_SERVING {
+		return fmt.Errorf("peer is %s", res.Status)
+	}
+	return nil
+}
+
+func composeCredentials(ctx context.Context,
+	certPEMBlock []byte,
+	keyPEMBlock []byte,
+	caPEMBlock []byte,
+	minVersion uint16,
+	cipherSuites []uint16) (credentials.TransportCredentials, error) {
 	clientCert, err := tls.X509KeyPair(certPEMBlock, keyPEMBlock)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to access client certificate/key")
@@ -203,7 +240,8 @@ func composeCredentials(ctx context.Context, certPEMBlock []byte, keyPEMBlock []
 
 	tlsCfg := &tls.Config{
 		Certificates: []tls.Certificate{clientCert},
-		MinVersion:   tls.VersionTLS13,
+		MinVersion:   minVersion,
+		CipherSuites: cipherSuites,
 	}
 	if len(caPEMBlock) > 0 {
 		cp := x509.NewCertPool()
@@ -216,6 +254,12 @@ func composeCredentials(ctx context.Context, certPEMBlock []byte, keyPEMBlock []
 	return credentials.NewTLS(tlsCfg), nil
 }
 
+// requestCompleted is called when a request to a peer has completed, with the error
+// returned from the request.
+func (s *Service) requestCompleted(started time.Time, peer *core.Endpoint, rpc string, err *error) {
+	s.monitor.PeerRequestCompleted(started, peer.ID, rpc, *err == nil)
+}
+
 // obtainConnection obtains a connection to the required address via GRPC.
 func (s *Service) obtainConnection(ctx context.Context, address string) (*puddle.Resource, error) {
 	s.connectionPoolsMutex.Lock()
@@ -224,6 +268,7 @@ func (s *Service) obtainConnection(ctx context.Context, address string) (*puddle
 		constructor := func(ctx context.Context) (interface{}, error) {
 			return grpc.Dial(address, []grpc.DialOption{
 				grpc.WithTransportCredentials(s.credentials),
+				grpc.WithUnaryInterceptor(tracingInterceptor),
 			}...)
 		}
 		destructor := func(val interface{}) {
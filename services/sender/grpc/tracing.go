@@ -0,0 +1,62 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpc
+
+import (
+	"context"
+
+	"github.com/attestantio/dirk/services/tracing"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	otlog "github.com/opentracing/opentracing-go/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// tracingInterceptor starts a span for each request to a peer if a tracer has
+// been configured, and passes the span context to the peer in the request
+// metadata so that the peer's spans join the same trace.
+func tracingInterceptor(ctx context.Context,
+	method string,
+	req interface{},
+	reply interface{},
+	cc *grpc.ClientConn,
+	invoker grpc.UnaryInvoker,
+	opts ...grpc.CallOption,
+) error {
+	if !opentracing.IsGlobalTracerRegistered() {
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, method, ext.SpanKindRPCClient)
+	defer span.Finish()
+	ext.PeerAddress.Set(span, cc.Target())
+
+	md, exists := metadata.FromOutgoingContext(ctx)
+	if exists {
+		md = md.Copy()
+	} else {
+		md = metadata.MD{}
+	}
+	if err := span.Tracer().Inject(span.Context(), opentracing.HTTPHeaders, tracing.MetadataCarrier(md)); err != nil {
+		log.Trace().Err(err).Msg("Failed to add span context to request")
+	}
+
+	err := invoker(metadata.NewOutgoingContext(ctx, md), method, req, reply, cc, opts...)
+	if err != nil {
+		ext.Error.Set(span, true)
+		span.LogFields(otlog.Error(err))
+	}
+	return err
+}
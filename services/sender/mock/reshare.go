@@ -0,0 +1,100 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mock
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/attestantio/dirk/core"
+	"github.com/attestantio/dirk/services/process"
+	"github.com/attestantio/dirk/testing/mock"
+	"github.com/herumi/bls-eth-go-binary/bls"
+)
+
+// resharer returns the resharing process for the given recipient.
+func resharer(recipient *core.Endpoint) (process.Resharer, error) {
+	p, exists := mock.Processes[recipient.ID]
+	if !exists {
+		return nil, fmt.Errorf("unknown mock process %d", recipient.ID)
+	}
+	resharer, isResharer := p.(process.Resharer)
+	if !isResharer {
+		return nil, fmt.Errorf("mock process %d does not support resharing", recipient.ID)
+	}
+	return resharer, nil
+}
+
+// PrepareReshare sends a request to the given participant to prepare for resharing.
+func (s *Service) PrepareReshare(ctx context.Context, recipient *core.Endpoint, account string, passphrase []byte, threshold uint32, participants []*core.Endpoint, dealers []uint64, verificationVector []bls.PublicKey) error {
+	process, err := resharer(recipient)
+	if err != nil {
+		return err
+	}
+	return process.OnPrepareReshare(ctx, s.id, account, passphrase, threshold, participants, dealers, verificationVector)
+}
+
+// ExecuteReshare sends a request to the given dealer to send its contributions for resharing.
+func (s *Service) ExecuteReshare(ctx context.Context, recipient *core.Endpoint, account string) error {
+	process, err := resharer(recipient)
+	if err != nil {
+		return err
+	}
+	return process.OnExecuteReshare(ctx, s.id, account)
+}
+
+// SendReshareContribution sends a contribution for resharing to a recipient.
+func (s *Service) SendReshareContribution(ctx context.Context, recipient *core.Endpoint, account string, secret bls.SecretKey, verificationVector []bls.PublicKey) error {
+	process, err := resharer(recipient)
+	if err != nil {
+		return err
+	}
+	return process.OnContributeReshare(ctx, s.id, account, secret, verificationVector)
+}
+
+// ConfirmReshare sends a request to the given participant to confirm its new share.
+func (s *Service) ConfirmReshare(ctx context.Context, recipient *core.Endpoint, account string, confirmationData []byte) ([]byte, []byte, error) {
+	process, err := resharer(recipient)
+	if err != nil {
+		return nil, nil, err
+	}
+	return process.OnConfirmReshare(ctx, s.id, account, confirmationData)
+}
+
+// CommitReshare sends a request to the given participant to store its new share.
+func (s *Service) CommitReshare(ctx context.Context, recipient *core.Endpoint, account string) error {
+	process, err := resharer(recipient)
+	if err != nil {
+		return err
+	}
+	return process.OnCommitReshare(ctx, s.id, account)
+}
+
+// CompleteReshare sends a request to the given participant to complete resharing.
+func (s *Service) CompleteReshare(ctx context.Context, recipient *core.Endpoint, account string) error {
+	process, err := resharer(recipient)
+	if err != nil {
+		return err
+	}
+	return process.OnCompleteReshare(ctx, s.id, account)
+}
+
+// AbortReshare sends a request to the given participant to abort resharing.
+func (s *Service) AbortReshare(ctx context.Context, recipient *core.Endpoint, account string) error {
+	process, err := resharer(recipient)
+	if err != nil {
+		return err
+	}
+	return process.OnAbortReshare(ctx, s.id, account)
+}
@@ -20,6 +20,12 @@ import (
 	"github.com/herumi/bls-eth-go-binary/bls"
 )
 
+// Prober is the interface for a sender that can check the health of peers.
+type Prober interface {
+	// Probe checks that the given peer is reachable and ready to serve requests.
+	Probe(ctx context.Context, recipient *core.Endpoint) error
+}
+
 // Service is the interface for a DKG sender.
 type Service interface {
 	// Prepare sends a request to the given participant to prepare for DKG.
@@ -38,3 +44,28 @@ type Service interface {
 	// SendContribution sends a contribution to a recipient.
 	SendContribution(ctx context.Context, recipient *core.Endpoint, account string, distributionSecret bls.SecretKey, verificationVector []bls.PublicKey) (bls.SecretKey, []bls.PublicKey, error)
 }
+
+// Resharer is the interface for a sender that can reshare distributed accounts.
+type Resharer interface {
+	// PrepareReshare sends a request to the given participant to prepare for resharing.
+	PrepareReshare(ctx context.Context,
+		recipient *core.Endpoint,
+		account string,
+		passphrase []byte,
+		threshold uint32,
+		participants []*core.Endpoint,
+		dealers []uint64,
+		verificationVector []bls.PublicKey) error
+	// ExecuteReshare sends a request to the given dealer to send its contributions for resharing.
+	ExecuteReshare(ctx context.Context, recipient *core.Endpoint, account string) error
+	// SendReshareContribution sends a contribution for resharing to a recipient.
+	SendReshareContribution(ctx context.Context, recipient *core.Endpoint, account string, secret bls.SecretKey, verificationVector []bls.PublicKey) error
+	// ConfirmReshare sends a request to the given participant to confirm its new share.
+	ConfirmReshare(ctx context.Context, recipient *core.Endpoint, account string, confirmationData []byte) ([]byte, []byte, error)
+	// CommitReshare sends a request to the given participant to store its new share.
+	CommitReshare(ctx context.Context, recipient *core.Endpoint, account string) error
+	// CompleteReshare sends a request to the given participant to complete resharing.
+	CompleteReshare(ctx context.Context, recipient *core.Endpoint, account string) error
+	// AbortReshare sends a request to the given participant to abort resharing.
+	AbortReshare(ctx context.Context, recipient *core.Endpoint, account string) error
+}
@@ -19,8 +19,17 @@ import (
 	"github.com/attestantio/dirk/core"
 	"github.com/attestantio/dirk/rules"
 	"github.com/attestantio/dirk/services/checker"
+	"github.com/pkg/errors"
 )
 
+// ErrTagNotPermitted is returned when a client requests signing of a tagged
+// message with a tag that it is not permitted to sign.
+var ErrTagNotPermitted = errors.New("message type tag not permitted for client")
+
+// ErrCapacityExhausted is returned when a signing request cannot obtain
+// signing capacity within the queue timeout.
+var ErrCapacityExhausted = errors.New("signing capacity exhausted")
+
 // Service is the signer service.
 type Service interface {
 	// SignGeneric signs generic data.
@@ -58,3 +67,89 @@ type Service interface {
 		pubKey []byte,
 		data *rules.SignBeaconProposalData) (core.Result, []byte)
 }
+
+// SignChecker is the interface for a signer that can check if requests would
+// be signed without signing them.
+type SignChecker interface {
+	// CheckSignGeneric checks if a request to sign generic data would be approved.
+	CheckSignGeneric(ctx context.Context,
+		credentials *checker.Credentials,
+		accountName string,
+		pubKey []byte,
+		data *rules.SignData) (core.Result, string)
+
+	// CheckSignBeaconAttestation checks if a request to sign a beacon attestation would be approved.
+	CheckSignBeaconAttestation(ctx context.Context,
+		credentials *checker.Credentials,
+		accountName string,
+		pubKey []byte,
+		data *rules.SignBeaconAttestationData) (core.Result, string)
+
+	// CheckSignBeaconProposal checks if a request to sign a proposal for a beacon block would be approved.
+	CheckSignBeaconProposal(ctx context.Context,
+		credentials *checker.Credentials,
+		accountName string,
+		pubKey []byte,
+		data *rules.SignBeaconProposalData) (core.Result, string)
+}
+
+// DepositData is the deposit data for an account.
+type DepositData struct {
+	PublicKey             []byte
+	WithdrawalCredentials []byte
+	Amount                uint64
+	Signature             []byte
+	DepositMessageRoot    []byte
+	DepositDataRoot       []byte
+	ForkVersion           []byte
+}
+
+// DepositDataGenerator is the interface for a signer that can generate deposit data.
+type DepositDataGenerator interface {
+	// GenerateDepositData generates deposit data for an account.
+	GenerateDepositData(ctx context.Context,
+		credentials *checker.Credentials,
+		accountName string,
+		pubKey []byte,
+		data *rules.GenerateDepositData) (core.Result, *DepositData)
+}
+
+// TaggedSigner is the interface for a signer that can sign tagged messages.
+type TaggedSigner interface {
+	// SignTagged signs a tagged message.
+	SignTagged(ctx context.Context,
+		credentials *checker.Credentials,
+		accountName string,
+		pubKey []byte,
+		data *rules.SignTaggedData) (core.Result, []byte)
+}
+
+// RootSigner is the interface for a signer that can sign client-supplied signing roots.
+type RootSigner interface {
+	// SignRoot signs a signing root.
+	SignRoot(ctx context.Context,
+		credentials *checker.Credentials,
+		accountName string,
+		pubKey []byte,
+		data *rules.SignRootData) (core.Result, []byte)
+}
+
+// BLSToExecutionChangeSigner is the interface for a signer that can sign BLS to execution changes.
+type BLSToExecutionChangeSigner interface {
+	// SignBLSToExecutionChange signs a BLS to execution change.
+	SignBLSToExecutionChange(ctx context.Context,
+		credentials *checker.Credentials,
+		accountName string,
+		pubKey []byte,
+		data *rules.SignBLSToExecutionChangeData) (core.Result, []byte)
+}
+
+// ValidatorRegistrationSigner is the interface for a signer that can sign validator registrations.
+type ValidatorRegistrationSigner interface {
+	// SignValidatorRegistration signs a validator registration.
+	SignValidatorRegistration(ctx context.Context,
+		credentials *checker.Credentials,
+		accountName string,
+		pubKey []byte,
+		data *rules.SignValidatorRegistrationData) (core.Result, []byte)
+}
@@ -0,0 +1,68 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/attestantio/dirk/core"
+)
+
+// admit admits a signing request, waiting for capacity if the number of
+// concurrent signing operations is limited.  If the request is admitted it
+// returns core.ResultSucceeded and a function that must be called to release
+// the capacity once the request has completed.  If capacity does not become
+// available within the queue timeout it returns core.ResultResourceExhausted.
+func (s *Service) admit(ctx context.Context, request string) (func(), core.Result) {
+	if s.slots == nil {
+		return func() {}, core.ResultSucceeded
+	}
+
+	admitted := time.Now()
+	s.monitor.SignCapacity(len(s.slots), int(atomic.AddInt32(&s.queued, 1)))
+	var timeout <-chan time.Time
+	if s.queueTimeout > 0 {
+		timer := time.NewTimer(s.queueTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case s.slots <- struct{}{}:
+		s.dequeue(admitted, request)
+		return s.release, core.ResultSucceeded
+	case <-timeout:
+		s.dequeue(admitted, request)
+		log.Warn().Str("request", request).Dur("timeout", s.queueTimeout).Msg("Timed out waiting for signing capacity")
+		return nil, core.ResultResourceExhausted
+	case <-ctx.Done():
+		s.dequeue(admitted, request)
+		log.Warn().Str("request", request).Err(ctx.Err()).Msg("Request finished whilst waiting for signing capacity")
+		return nil, core.ResultFailed
+	}
+}
+
+// dequeue records that a request has finished waiting for capacity.
+func (s *Service) dequeue(started time.Time, request string) {
+	s.monitor.SignQueueCompleted(started, request)
+	s.monitor.SignCapacity(len(s.slots), int(atomic.AddInt32(&s.queued, -1)))
+}
+
+// release releases the capacity held by a signing request.
+func (s *Service) release() {
+	<-s.slots
+	s.monitor.SignCapacity(len(s.slots), int(atomic.LoadInt32(&s.queued)))
+}
@@ -0,0 +1,124 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/attestantio/dirk/core"
+	"github.com/stretchr/testify/require"
+)
+
+type queueMonitor struct {
+	mu          sync.Mutex
+	queued      map[string]int
+	inflight    int
+	queuedTotal int
+}
+
+func (m *queueMonitor) SignCompleted(started time.Time, request string, result core.Result) {}
+
+func (m *queueMonitor) SignCoalesced(request string) {}
+
+func (m *queueMonitor) TaggedSignCompleted(tag string, result core.Result) {}
+
+func (m *queueMonitor) SignQueueCompleted(started time.Time, request string) {
+	m.mu.Lock()
+	m.queued[request]++
+	m.mu.Unlock()
+}
+
+func (m *queueMonitor) SignCapacity(inflight int, queued int) {
+	m.mu.Lock()
+	m.inflight = inflight
+	m.queuedTotal = queued
+	m.mu.Unlock()
+}
+
+func TestAdmit(t *testing.T) {
+	ctx := context.Background()
+
+	// Unlimited.
+	s := &Service{
+		monitor: &queueMonitor{queued: make(map[string]int)},
+	}
+	release, result := s.admit(ctx, "generic")
+	require.Equal(t, core.ResultSucceeded, result)
+	release()
+
+	// Limited.
+	monitor := &queueMonitor{queued: make(map[string]int)}
+	s = &Service{
+		monitor: monitor,
+		slots:   make(chan struct{}, 1),
+	}
+	release, result = s.admit(ctx, "attestation")
+	require.Equal(t, core.ResultSucceeded, result)
+	require.Equal(t, 1, monitor.queued["attestation"])
+	require.Equal(t, 1, monitor.inflight)
+	require.Equal(t, 0, monitor.queuedTotal)
+
+	// Second request cannot be admitted until the first is released.
+	timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	_, result = s.admit(timeoutCtx, "attestation")
+	require.Equal(t, core.ResultFailed, result)
+	require.Equal(t, 2, monitor.queued["attestation"])
+	require.Equal(t, 0, monitor.queuedTotal)
+
+	release()
+	require.Equal(t, 0, monitor.inflight)
+	release, result = s.admit(ctx, "proposal")
+	require.Equal(t, core.ResultSucceeded, result)
+	require.Equal(t, 1, monitor.queued["proposal"])
+	release()
+}
+
+func TestAdmitQueueTimeout(t *testing.T) {
+	ctx := context.Background()
+
+	monitor := &queueMonitor{queued: make(map[string]int)}
+	s := &Service{
+		monitor:      monitor,
+		slots:        make(chan struct{}, 1),
+		queueTimeout: 10 * time.Millisecond,
+	}
+	release, result := s.admit(ctx, "attestation")
+	require.Equal(t, core.ResultSucceeded, result)
+
+	// Second request is queued, and times out.
+	done := make(chan core.Result)
+	go func() {
+		_, result := s.admit(ctx, "attestation")
+		done <- result
+	}()
+	require.Equal(t, core.ResultResourceExhausted, <-done)
+	require.Equal(t, 0, monitor.queuedTotal)
+	require.Equal(t, 1, monitor.inflight)
+
+	// Third request is admitted once capacity is released before the timeout.
+	go func() {
+		release, result := s.admit(ctx, "attestation")
+		if result == core.ResultSucceeded {
+			release()
+		}
+		done <- result
+	}()
+	release()
+	require.Equal(t, core.ResultSucceeded, <-done)
+	require.Equal(t, 0, monitor.inflight)
+}
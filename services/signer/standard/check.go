@@ -0,0 +1,153 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard
+
+import (
+	context "context"
+	"fmt"
+
+	"github.com/attestantio/dirk/core"
+	"github.com/attestantio/dirk/rules"
+	"github.com/attestantio/dirk/services/checker"
+	"github.com/attestantio/dirk/services/ruler"
+)
+
+// CheckSignGeneric checks if a request to sign generic data would be approved.
+func (s *Service) CheckSignGeneric(
+	ctx context.Context,
+	credentials *checker.Credentials,
+	accountName string,
+	pubKey []byte,
+	data *rules.SignData,
+) (
+	core.Result,
+	string,
+) {
+	if reason := validateSignData(data); reason != "" {
+		return core.ResultDenied, reason
+	}
+	return s.check(ctx, credentials, accountName, pubKey, ruler.ActionSign, data)
+}
+
+// CheckSignBeaconAttestation checks if a request to sign a beacon attestation would be approved.
+func (s *Service) CheckSignBeaconAttestation(
+	ctx context.Context,
+	credentials *checker.Credentials,
+	accountName string,
+	pubKey []byte,
+	data *rules.SignBeaconAttestationData,
+) (
+	core.Result,
+	string,
+) {
+	if reason := validateSignBeaconAttestationData(data); reason != "" {
+		return core.ResultDenied, reason
+	}
+	return s.check(ctx, credentials, accountName, pubKey, ruler.ActionSignBeaconAttestation, data)
+}
+
+// CheckSignBeaconProposal checks if a request to sign a proposal for a beacon block would be approved.
+func (s *Service) CheckSignBeaconProposal(
+	ctx context.Context,
+	credentials *checker.Credentials,
+	accountName string,
+	pubKey []byte,
+	data *rules.SignBeaconProposalData,
+) (
+	core.Result,
+	string,
+) {
+	if reason := validateSignBeaconProposalData(data); reason != "" {
+		return core.ResultDenied, reason
+	}
+	return s.check(ctx, credentials, accountName, pubKey, ruler.ActionSignBeaconProposal, data)
+}
+
+// check runs the checker and rules for a signing request without signing it
+// or updating the state of the rules.
+func (s *Service) check(ctx context.Context,
+	credentials *checker.Credentials,
+	accountName string,
+	pubKey []byte,
+	action string,
+	data interface{},
+) (
+	core.Result,
+	string,
+) {
+	if credentials == nil {
+		log.Error().Msg("No credentials supplied")
+		return core.ResultFailed, "No credentials supplied"
+	}
+
+	log := log.With().
+		Str("request_id", credentials.RequestID).
+		Str("client", credentials.Client).
+		Str("action", "Check").
+		Str("operation", action).
+		Logger()
+	log.Trace().Msg("Checking")
+
+	wallet, account, result := s.fetchAccount(ctx, credentials, accountName, pubKey)
+	if result != core.ResultSucceeded {
+		return result, "Account not found"
+	}
+	accountName = fmt.Sprintf("%s/%s", wallet.Name(), account.Name())
+	log = log.With().Str("account", accountName).Logger()
+
+	// The client must be allowed to check requests for this account.
+	if s.checkAccess(ctx, credentials, accountName, ruler.ActionCheckSign) != core.ResultSucceeded {
+		log.Debug().Str("result", "denied").Msg("Client not permitted to check requests")
+		return core.ResultDenied, "Client not permitted to check requests for this account"
+	}
+
+	// From here on the checks are the same as those for signing.
+	if s.checkAccess(ctx, credentials, accountName, action) != core.ResultSucceeded {
+		log.Debug().Str("result", "denied").Msg("Client not permitted to carry out operation")
+		return core.ResultDenied, "Client not permitted to carry out operation for this account"
+	}
+
+	ctx = rules.WithCheckOnly(ctx)
+	if proposalData, isProposal := data.(*rules.SignBeaconProposalData); isProposal {
+		if !s.acquireProposalLease(ctx, credentials, account.PublicKey().Marshal(), proposalData.Slot) {
+			log.Debug().Str("result", "in progress").Msg("Proposal already in progress")
+			return core.ResultInProgress, "Proposal already in progress for another client"
+		}
+	}
+
+	rulesData := []*ruler.RulesData{
+		{
+			WalletName:  wallet.Name(),
+			AccountName: account.Name(),
+			PubKey:      account.PublicKey().Marshal(),
+			Data:        data,
+		},
+	}
+	results := s.runRules(ctx, credentials, action, rulesData)
+	switch results[0] {
+	case rules.APPROVED:
+		log.Trace().Str("result", "succeeded").Msg("Approved by rules")
+		return core.ResultSucceeded, ""
+	case rules.DENIED:
+		log.Debug().Str("result", "denied").Msg("Denied by rules")
+		reason := rules.Reason(ctx)
+		if reason == "" {
+			reason = "Denied by rules"
+		}
+		return core.ResultDenied, reason
+	default:
+		log.Debug().Str("result", "failed").Stringer("rules_result", results[0]).Msg("Rules check failed")
+		return core.ResultFailed, "Rules check failed"
+	}
+}
@@ -0,0 +1,186 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard_test
+
+import (
+	context "context"
+	"testing"
+
+	"github.com/attestantio/dirk/core"
+	"github.com/attestantio/dirk/rules"
+	standardrules "github.com/attestantio/dirk/rules/standard"
+	"github.com/attestantio/dirk/services/checker"
+	mockchecker "github.com/attestantio/dirk/services/checker/mock"
+	staticchecker "github.com/attestantio/dirk/services/checker/static"
+	memfetcher "github.com/attestantio/dirk/services/fetcher/mem"
+	syncmaplocker "github.com/attestantio/dirk/services/locker/syncmap"
+	"github.com/attestantio/dirk/services/ruler/golang"
+	"github.com/attestantio/dirk/services/signer"
+	localunlocker "github.com/attestantio/dirk/services/unlocker/local"
+	"github.com/stretchr/testify/require"
+	e2types "github.com/wealdtech/go-eth2-types/v2"
+	keystorev4 "github.com/wealdtech/go-eth2-wallet-encryptor-keystorev4"
+	nd "github.com/wealdtech/go-eth2-wallet-nd/v2"
+	scratch "github.com/wealdtech/go-eth2-wallet-store-scratch"
+	e2wtypes "github.com/wealdtech/go-eth2-wallet-types/v2"
+)
+
+func TestCheckSignBeaconProposal(t *testing.T) {
+	ctx := context.Background()
+
+	store := scratch.New()
+	encryptor := keystorev4.New()
+	wallet, err := nd.CreateWallet(ctx, "Test wallet", store, encryptor)
+	require.NoError(t, err)
+	require.NoError(t, wallet.(e2wtypes.WalletLocker).Unlock(ctx, nil))
+	_, err = wallet.(e2wtypes.WalletAccountCreator).CreateAccount(ctx, "Test account", []byte("pass"))
+	require.NoError(t, err)
+	require.NoError(t, wallet.(e2wtypes.WalletLocker).Lock(ctx))
+
+	lockerSvc, err := syncmaplocker.New(ctx)
+	require.NoError(t, err)
+	fetcherSvc, err := memfetcher.New(ctx,
+		memfetcher.WithStores([]e2wtypes.Store{store}))
+	require.NoError(t, err)
+	rulesSvc, err := standardrules.New(ctx,
+		standardrules.WithStoragePath(t.TempDir()))
+	require.NoError(t, err)
+	rulerSvc, err := golang.New(ctx,
+		golang.WithLocker(lockerSvc),
+		golang.WithRules(rulesSvc))
+	require.NoError(t, err)
+	unlockerSvc, err := localunlocker.New(ctx,
+		localunlocker.WithAccountPassphrases([]string{"pass"}))
+	require.NoError(t, err)
+	checkerSvc, err := mockchecker.New()
+	require.NoError(t, err)
+
+	signerSvc := _signerSvc(ctx, checkerSvc, fetcherSvc, rulerSvc, unlockerSvc)
+	signChecker, isSignChecker := signerSvc.(signer.SignChecker)
+	require.True(t, isSignChecker)
+
+	credentials := &checker.Credentials{Client: "client1"}
+	domain := make([]byte, 32)
+	copy(domain, e2types.DomainBeaconProposer[:])
+	data := &rules.SignBeaconProposalData{
+		Domain:     domain,
+		Slot:       10,
+		ParentRoot: make([]byte, 32),
+		StateRoot:  make([]byte, 32),
+		BodyRoot:   make([]byte, 32),
+	}
+
+	// Missing data is reported.
+	res, reason := signChecker.CheckSignBeaconProposal(ctx, credentials, "Test wallet/Test account", nil, &rules.SignBeaconProposalData{})
+	require.Equal(t, core.ResultDenied, res)
+	require.Equal(t, "Request missing domain", reason)
+
+	// Unknown accounts are reported.
+	res, reason = signChecker.CheckSignBeaconProposal(ctx, credentials, "Test wallet/Unknown", nil, data)
+	require.Equal(t, core.ResultDenied, res)
+	require.Equal(t, "Account not found", reason)
+
+	// Checking repeatedly must not update slashing protection.
+	for i := 0; i < 2; i++ {
+		res, reason = signChecker.CheckSignBeaconProposal(ctx, credentials, "Test wallet/Test account", nil, data)
+		require.Equal(t, core.ResultSucceeded, res)
+		require.Empty(t, reason)
+	}
+
+	// Sign for real, after which the check should fail.
+	res, _ = signerSvc.SignBeaconProposal(ctx, credentials, "Test wallet/Test account", nil, data)
+	require.Equal(t, core.ResultSucceeded, res)
+	res, reason = signChecker.CheckSignBeaconProposal(ctx, credentials, "Test wallet/Test account", nil, data)
+	require.Equal(t, core.ResultDenied, res)
+	require.Equal(t, "Request slot 10 equal to or lower than previous signed slot 10", reason)
+
+	// A client with "All" cannot check without the explicit permission.
+	allChecker, err := staticchecker.New(ctx,
+		staticchecker.WithPermissions(map[string][]*checker.Permissions{
+			"client2": {
+				{
+					Path:       ".*",
+					Operations: []string{"All"},
+				},
+			},
+		}),
+	)
+	require.NoError(t, err)
+	restrictedSigner := _signerSvc(ctx, allChecker, fetcherSvc, rulerSvc, unlockerSvc).(signer.SignChecker)
+	res, reason = restrictedSigner.CheckSignBeaconProposal(ctx, &checker.Credentials{Client: "client2"}, "Test wallet/Test account", nil, data)
+	require.Equal(t, core.ResultDenied, res)
+	require.Equal(t, "Client not permitted to check requests for this account", reason)
+}
+
+func TestCheckSignBeaconAttestation(t *testing.T) {
+	ctx := context.Background()
+
+	store := scratch.New()
+	encryptor := keystorev4.New()
+	wallet, err := nd.CreateWallet(ctx, "Test wallet", store, encryptor)
+	require.NoError(t, err)
+	require.NoError(t, wallet.(e2wtypes.WalletLocker).Unlock(ctx, nil))
+	_, err = wallet.(e2wtypes.WalletAccountCreator).CreateAccount(ctx, "Test account", []byte("pass"))
+	require.NoError(t, err)
+	require.NoError(t, wallet.(e2wtypes.WalletLocker).Lock(ctx))
+
+	lockerSvc, err := syncmaplocker.New(ctx)
+	require.NoError(t, err)
+	fetcherSvc, err := memfetcher.New(ctx,
+		memfetcher.WithStores([]e2wtypes.Store{store}))
+	require.NoError(t, err)
+	rulesSvc, err := standardrules.New(ctx,
+		standardrules.WithStoragePath(t.TempDir()))
+	require.NoError(t, err)
+	rulerSvc, err := golang.New(ctx,
+		golang.WithLocker(lockerSvc),
+		golang.WithRules(rulesSvc))
+	require.NoError(t, err)
+	unlockerSvc, err := localunlocker.New(ctx,
+		localunlocker.WithAccountPassphrases([]string{"pass"}))
+	require.NoError(t, err)
+	checkerSvc, err := mockchecker.New()
+	require.NoError(t, err)
+
+	signerSvc := _signerSvc(ctx, checkerSvc, fetcherSvc, rulerSvc, unlockerSvc)
+	signChecker := signerSvc.(signer.SignChecker)
+
+	credentials := &checker.Credentials{Client: "client1"}
+	domain := make([]byte, 32)
+	copy(domain, e2types.DomainBeaconAttester[:])
+	data := &rules.SignBeaconAttestationData{
+		Domain:          domain,
+		Slot:            320,
+		BeaconBlockRoot: make([]byte, 32),
+		Source: &rules.Checkpoint{
+			Epoch: 9,
+			Root:  make([]byte, 32),
+		},
+		Target: &rules.Checkpoint{
+			Epoch: 10,
+			Root:  make([]byte, 32),
+		},
+	}
+
+	res, reason := signChecker.CheckSignBeaconAttestation(ctx, credentials, "Test wallet/Test account", nil, data)
+	require.Equal(t, core.ResultSucceeded, res)
+	require.Empty(t, reason)
+
+	res, _ = signerSvc.SignBeaconAttestation(ctx, credentials, "Test wallet/Test account", nil, data)
+	require.Equal(t, core.ResultSucceeded, res)
+
+	res, reason = signChecker.CheckSignBeaconAttestation(ctx, credentials, "Test wallet/Test account", nil, data)
+	require.Equal(t, core.ResultDenied, res)
+	require.Equal(t, "Request target epoch 10 equal to or lower than previous signed target epoch 10", reason)
+}
@@ -0,0 +1,99 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard
+
+import (
+	"context"
+
+	"github.com/attestantio/dirk/core"
+	"github.com/attestantio/dirk/rules"
+)
+
+// inflightSign is a signing operation that is in progress, and whose
+// result can be shared with identical requests that arrive while it runs.
+type inflightSign struct {
+	done      chan struct{}
+	result    core.Result
+	signature []byte
+	// reason and code are the reason for the result recorded by the rules.
+	reason string
+	code   rules.DenialReason
+}
+
+// coalesce runs the supplied signing function, unless an identical signing
+// operation is already in progress in which case it waits for that operation
+// and returns its result instead.  Requests are identical only if they have
+// the same public key, domain and signing root, so the called function runs
+// the rules, and hence updates slashing protection, once for all of them.
+// The reason for the result recorded by the rules is passed on to each request.
+// If de-duplication is not enabled the function is always run.
+func (s *Service) coalesce(ctx context.Context,
+	request string,
+	pubKey []byte,
+	domain []byte,
+	signingRoot []byte,
+	sign func(ctx context.Context) (core.Result, []byte),
+) (
+	core.Result,
+	[]byte,
+) {
+	if s.inflight == nil {
+		return sign(ctx)
+	}
+
+	key := string(pubKey) + string(domain) + string(signingRoot)
+	s.inflightMu.Lock()
+	if call, exists := s.inflight[key]; exists {
+		s.inflightMu.Unlock()
+		s.monitor.SignCoalesced(request)
+		log.Trace().Str("request", request).Msg("Waiting for identical in-progress request")
+		select {
+		case <-call.done:
+			rules.SetCodedReason(ctx, call.code, call.reason)
+			if call.signature == nil {
+				return call.result, nil
+			}
+			signature := make([]byte, len(call.signature))
+			copy(signature, call.signature)
+			return call.result, signature
+		case <-ctx.Done():
+			log.Warn().Str("request", request).Err(ctx.Err()).Msg("Request finished whilst waiting for identical request")
+			return core.ResultFailed, nil
+		}
+	}
+	call := &inflightSign{
+		done: make(chan struct{}),
+	}
+	s.inflight[key] = call
+	s.inflightMu.Unlock()
+
+	defer func() {
+		s.inflightMu.Lock()
+		delete(s.inflight, key)
+		s.inflightMu.Unlock()
+		close(call.done)
+	}()
+	// The rules record their reason on a context of the operation's own, so that it
+	// can be shared with the requests waiting on it.
+	signCtx := rules.WithReason(ctx)
+	if rules.IsCheckOnly(ctx) {
+		signCtx = rules.WithCheckOnly(ctx)
+	}
+	call.result, call.signature = sign(signCtx)
+	call.reason = rules.Reason(signCtx)
+	call.code = rules.ReasonCode(signCtx)
+	rules.SetCodedReason(ctx, call.code, call.reason)
+
+	return call.result, call.signature
+}
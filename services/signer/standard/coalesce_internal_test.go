@@ -0,0 +1,236 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/attestantio/dirk/core"
+	"github.com/attestantio/dirk/rules"
+	"github.com/attestantio/dirk/services/checker"
+	"github.com/stretchr/testify/require"
+)
+
+type coalesceMonitor struct {
+	coalesced int32
+}
+
+func (m *coalesceMonitor) SignCompleted(started time.Time, request string, result core.Result) {}
+
+func (m *coalesceMonitor) SignQueueCompleted(started time.Time, request string) {}
+
+func (m *coalesceMonitor) SignCapacity(inflight int, queued int) {}
+
+func (m *coalesceMonitor) SignCoalesced(request string) {
+	atomic.AddInt32(&m.coalesced, 1)
+}
+
+func (m *coalesceMonitor) TaggedSignCompleted(tag string, result core.Result) {}
+
+func TestCoalesce(t *testing.T) {
+	ctx := context.Background()
+	pubKey := []byte{0x01}
+	domain := []byte{0x02}
+	root := []byte{0x03}
+
+	// Disabled.
+	monitor := &coalesceMonitor{}
+	s := &Service{
+		monitor: monitor,
+	}
+	calls := int32(0)
+	sign := func(_ context.Context) (core.Result, []byte) {
+		atomic.AddInt32(&calls, 1)
+		return core.ResultSucceeded, []byte{0xaa}
+	}
+	for i := 0; i < 2; i++ {
+		res, signature := s.coalesce(ctx, "attestation", pubKey, domain, root, sign)
+		require.Equal(t, core.ResultSucceeded, res)
+		require.Equal(t, []byte{0xaa}, signature)
+	}
+	require.Equal(t, int32(2), calls)
+	require.Equal(t, int32(0), monitor.coalesced)
+
+	// Enabled, with identical concurrent requests.
+	s.inflight = make(map[string]*inflightSign)
+	calls = 0
+	release := make(chan struct{})
+	blockingSign := func(_ context.Context) (core.Result, []byte) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return core.ResultSucceeded, []byte{0xbb}
+	}
+	followers := 4
+	var wg sync.WaitGroup
+	results := make([][]byte, followers+1)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_, results[0] = s.coalesce(ctx, "attestation", pubKey, domain, root, blockingSign)
+	}()
+	require.Eventually(t, func() bool { return atomic.LoadInt32(&calls) == 1 }, time.Second, time.Millisecond)
+	for i := 1; i <= followers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, results[i] = s.coalesce(ctx, "attestation", pubKey, domain, root, blockingSign)
+		}(i)
+	}
+	require.Eventually(t, func() bool { return atomic.LoadInt32(&monitor.coalesced) == int32(followers) }, time.Second, time.Millisecond)
+
+	// A request with a different signing root is not coalesced.
+	res, signature := s.coalesce(ctx, "attestation", pubKey, domain, []byte{0x04}, sign)
+	require.Equal(t, core.ResultSucceeded, res)
+	require.Equal(t, []byte{0xaa}, signature)
+
+	close(release)
+	wg.Wait()
+	require.Equal(t, int32(2), calls)
+	for i := range results {
+		require.Equal(t, []byte{0xbb}, results[i])
+	}
+	require.Len(t, s.inflight, 0)
+
+	// Once complete, a new identical request runs again.
+	s.coalesce(ctx, "attestation", pubKey, domain, root, sign)
+	require.Equal(t, int32(3), calls)
+}
+
+func TestCoalesceCancelled(t *testing.T) {
+	s := &Service{
+		monitor:  &coalesceMonitor{},
+		inflight: make(map[string]*inflightSign),
+	}
+	release := make(chan struct{})
+	started := make(chan struct{})
+	go s.coalesce(context.Background(), "attestation", []byte{0x01}, nil, nil, func(ctx context.Context) (core.Result, []byte) {
+		close(started)
+		<-release
+		return core.ResultSucceeded, []byte{0xbb}
+	})
+	<-started
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	res, signature := s.coalesce(ctx, "attestation", []byte{0x01}, nil, nil, func(ctx context.Context) (core.Result, []byte) {
+		return core.ResultSucceeded, []byte{0xaa}
+	})
+	require.Equal(t, core.ResultFailed, res)
+	require.Nil(t, signature)
+	close(release)
+}
+
+func TestCoalesceReason(t *testing.T) {
+	monitor := &coalesceMonitor{}
+	s := &Service{
+		monitor:  monitor,
+		inflight: make(map[string]*inflightSign),
+	}
+	release := make(chan struct{})
+	started := make(chan struct{})
+	deny := func(ctx context.Context) (core.Result, []byte) {
+		close(started)
+		<-release
+		rules.SetCodedReason(ctx, rules.DenialDoubleAttestation, "Double attestation")
+		return core.ResultDenied, nil
+	}
+
+	leaderCtx := rules.WithReason(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		res, _ := s.coalesce(leaderCtx, "attestation", []byte{0x01}, nil, nil, deny)
+		require.Equal(t, core.ResultDenied, res)
+	}()
+	<-started
+
+	waiterCtx := rules.WithReason(context.Background())
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		res, _ := s.coalesce(waiterCtx, "attestation", []byte{0x01}, nil, nil, deny)
+		require.Equal(t, core.ResultDenied, res)
+	}()
+	require.Eventually(t, func() bool { return atomic.LoadInt32(&monitor.coalesced) == 1 }, time.Second, time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	for _, ctx := range []context.Context{leaderCtx, waiterCtx} {
+		require.Equal(t, rules.DenialDoubleAttestation, rules.ReasonCode(ctx))
+		require.Equal(t, "Double attestation", rules.Reason(ctx))
+	}
+}
+
+func TestCoalesceAdmission(t *testing.T) {
+	ctx := context.Background()
+	s, _, _, err := setupSignerService(ctx)
+	require.NoError(t, err)
+	monitor := &coalesceMonitor{}
+	s.monitor = monitor
+	s.inflight = make(map[string]*inflightSign)
+	s.slots = make(chan struct{}, 1)
+
+	credentials := &checker.Credentials{Client: "client1"}
+	data := &rules.SignBeaconAttestationData{
+		Domain:          make([]byte, 32),
+		BeaconBlockRoot: make([]byte, 32),
+		Source: &rules.Checkpoint{
+			Epoch: 1,
+			Root:  make([]byte, 32),
+		},
+		Target: &rules.Checkpoint{
+			Epoch: 2,
+			Root:  make([]byte, 32),
+		},
+	}
+
+	// Take the only slot, so that the first request waits for capacity whilst in progress.
+	s.slots <- struct{}{}
+
+	requests := 3
+	results := make([]core.Result, requests)
+	signatures := make([][]byte, requests)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		results[0], signatures[0] = s.SignBeaconAttestation(ctx, credentials, "Test wallet/Test account 1", nil, data)
+	}()
+	require.Eventually(t, func() bool { return atomic.LoadInt32(&s.queued) == 1 }, time.Second, time.Millisecond)
+	for i := 1; i < requests; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], signatures[i] = s.SignBeaconAttestation(ctx, credentials, "Test wallet/Test account 1", nil, data)
+		}(i)
+	}
+	require.Eventually(t, func() bool { return atomic.LoadInt32(&monitor.coalesced) == int32(requests-1) }, time.Second, time.Millisecond)
+
+	// Only the first request is waiting for capacity.
+	require.Equal(t, int32(1), atomic.LoadInt32(&s.queued))
+
+	<-s.slots
+	wg.Wait()
+	for i := 0; i < requests; i++ {
+		require.Equal(t, core.ResultSucceeded, results[i])
+		require.Equal(t, signatures[0], signatures[i])
+	}
+	require.NotNil(t, signatures[0])
+	require.Len(t, s.slots, 0)
+}
@@ -0,0 +1,165 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard
+
+import (
+	context "context"
+	"fmt"
+	"time"
+
+	"github.com/attestantio/dirk/core"
+	"github.com/attestantio/dirk/rules"
+	"github.com/attestantio/dirk/services/checker"
+	"github.com/attestantio/dirk/services/ruler"
+	"github.com/attestantio/dirk/services/signer"
+	spec "github.com/attestantio/go-eth2-client/spec/phase0"
+	e2wtypes "github.com/wealdtech/go-eth2-wallet-types/v2"
+)
+
+// domainDeposit is the domain type for deposits.
+var domainDeposit = []byte{0x03, 0x00, 0x00, 0x00}
+
+// GenerateDepositData generates deposit data for an account.
+func (s *Service) GenerateDepositData(
+	ctx context.Context,
+	credentials *checker.Credentials,
+	accountName string,
+	pubKey []byte,
+	data *rules.GenerateDepositData,
+) (
+	core.Result,
+	*signer.DepositData,
+) {
+	release, result := s.admit(ctx, "deposit")
+	if result != core.ResultSucceeded {
+		return result, nil
+	}
+	defer release()
+	started := time.Now()
+
+	if credentials == nil {
+		log.Warn().Msg("No credentials supplied")
+		return core.ResultFailed, nil
+	}
+
+	log := log.With().
+		Str("request_id", credentials.RequestID).
+		Str("action", "GenerateDepositData").
+		Str("client", credentials.Client).
+		Logger()
+	log.Trace().Msg("Generating deposit data")
+
+	// Check input.
+	if reason := validateGenerateDepositData(data); reason != "" {
+		log.Warn().Str("result", "denied").Msg(reason)
+		s.monitor.SignCompleted(started, "deposit", core.ResultDenied)
+		return core.ResultDenied, nil
+	}
+
+	wallet, account, checkRes := s.preCheck(ctx, credentials, accountName, pubKey, ruler.ActionGenerateDepositData)
+	if checkRes != core.ResultSucceeded {
+		s.monitor.SignCompleted(started, "deposit", checkRes)
+		return checkRes, nil
+	}
+	accountName = fmt.Sprintf("%s/%s", wallet.Name(), account.Name())
+	log = log.With().Str("account", accountName).Logger()
+
+	// A distributed account can only provide a signature share, which is not usable in deposit data.
+	if _, isDistributed := account.(e2wtypes.AccountCompositePublicKeyProvider); isDistributed {
+		log.Warn().Str("result", "denied").Msg("Deposit data cannot be generated for distributed accounts")
+		s.monitor.SignCompleted(started, "deposit", core.ResultDenied)
+		return core.ResultDenied, nil
+	}
+
+	// Confirm approval via rules.
+	rulesData := []*ruler.RulesData{
+		{
+			WalletName:  wallet.Name(),
+			AccountName: account.Name(),
+			PubKey:      account.PublicKey().Marshal(),
+			Data:        data,
+		},
+	}
+	results := s.runRules(ctx, credentials, ruler.ActionGenerateDepositData, rulesData)
+	if results[0] != rules.APPROVED {
+		log.Debug().Str("result", "denied").Stringer("rules_result", results[0]).Msg("Not approved by rules")
+		s.monitor.SignCompleted(started, "deposit", core.ResultDenied)
+		return core.ResultDenied, nil
+	}
+
+	depositData, err := s.generateDepositData(ctx, account, data)
+	if err != nil {
+		log.Error().Err(err).Str("result", "failed").Msg("Failed to generate deposit data")
+		s.monitor.SignCompleted(started, "deposit", core.ResultFailed)
+		return core.ResultFailed, nil
+	}
+
+	log.Trace().Str("result", "succeeded").Msg("Success")
+	s.monitor.SignCompleted(started, "deposit", core.ResultSucceeded)
+	return core.ResultSucceeded, depositData
+}
+
+// generateDepositData generates and signs the deposit data for an account.
+func (s *Service) generateDepositData(ctx context.Context, account e2wtypes.Account, data *rules.GenerateDepositData) (*signer.DepositData, error) {
+	var pubKey spec.BLSPubKey
+	copy(pubKey[:], account.PublicKey().Marshal())
+
+	depositMessage := &spec.DepositMessage{
+		PublicKey:             pubKey,
+		WithdrawalCredentials: data.WithdrawalCredentials,
+		Amount:                spec.Gwei(data.Amount),
+	}
+	depositMessageRoot, err := depositMessage.HashTreeRoot()
+	if err != nil {
+		return nil, err
+	}
+
+	// The deposit domain does not use the genesis validators root, so that
+	// deposits can be generated before genesis.
+	forkVersion := s.forkVersion(domainDeposit, data.ForkVersion)
+	domain, err := s.domains.domain(domainDeposit, forkVersion, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	signingRoot, err := generateSigningRoot(ctx, depositMessageRoot[:], domain)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := signRoot(ctx, ruler.ActionGenerateDepositData, account, signingRoot[:])
+	if err != nil {
+		return nil, err
+	}
+
+	depositData := &spec.DepositData{
+		PublicKey:             pubKey,
+		WithdrawalCredentials: data.WithdrawalCredentials,
+		Amount:                spec.Gwei(data.Amount),
+	}
+	copy(depositData.Signature[:], signature)
+	depositDataRoot, err := depositData.HashTreeRoot()
+	if err != nil {
+		return nil, err
+	}
+
+	return &signer.DepositData{
+		PublicKey:             pubKey[:],
+		WithdrawalCredentials: data.WithdrawalCredentials,
+		Amount:                data.Amount,
+		Signature:             signature,
+		DepositMessageRoot:    depositMessageRoot[:],
+		DepositDataRoot:       depositDataRoot[:],
+		ForkVersion:           forkVersion,
+	}, nil
+}
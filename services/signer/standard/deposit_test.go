@@ -0,0 +1,253 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard_test
+
+import (
+	context "context"
+	"testing"
+
+	"github.com/attestantio/dirk/core"
+	"github.com/attestantio/dirk/rules"
+	mockrules "github.com/attestantio/dirk/rules/mock"
+	"github.com/attestantio/dirk/services/checker"
+	mockchecker "github.com/attestantio/dirk/services/checker/mock"
+	memfetcher "github.com/attestantio/dirk/services/fetcher/mem"
+	syncmaplocker "github.com/attestantio/dirk/services/locker/syncmap"
+	"github.com/attestantio/dirk/services/ruler/golang"
+	"github.com/attestantio/dirk/services/signer"
+	localunlocker "github.com/attestantio/dirk/services/unlocker/local"
+	"github.com/attestantio/dirk/testing/accounts"
+	spec "github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/stretchr/testify/require"
+	e2types "github.com/wealdtech/go-eth2-types/v2"
+	e2wtypes "github.com/wealdtech/go-eth2-wallet-types/v2"
+)
+
+func TestGenerateDepositData(t *testing.T) {
+	ctx := context.Background()
+
+	store, err := accounts.Setup(ctx)
+	require.NoError(t, err)
+	lockerSvc, err := syncmaplocker.New(ctx)
+	require.NoError(t, err)
+	fetcherSvc, err := memfetcher.New(ctx,
+		memfetcher.WithStores([]e2wtypes.Store{store}))
+	require.NoError(t, err)
+	rulerSvc, err := golang.New(ctx,
+		golang.WithLocker(lockerSvc),
+		golang.WithRules(mockrules.New()))
+	require.NoError(t, err)
+	unlockerSvc, err := localunlocker.New(ctx,
+		localunlocker.WithAccountPassphrases([]string{"Account 1 passphrase"}))
+	require.NoError(t, err)
+	checkerSvc, err := mockchecker.New()
+	require.NoError(t, err)
+	signerSvc := _signerSvc(ctx, checkerSvc, fetcherSvc, rulerSvc, unlockerSvc)
+
+	withdrawalCredentials := make([]byte, 32)
+	withdrawalCredentials[0] = 0x01
+	withdrawalCredentials[31] = 0x01
+	badPaddingCredentials := make([]byte, 32)
+	badPaddingCredentials[0] = 0x01
+	badPaddingCredentials[1] = 0x01
+	badPrefixCredentials := make([]byte, 32)
+	badPrefixCredentials[0] = 0x02
+	forkVersion := []byte{0x00, 0x00, 0x10, 0x20}
+
+	tests := []struct {
+		name        string
+		credentials *checker.Credentials
+		accountName string
+		data        *rules.GenerateDepositData
+		res         core.Result
+	}{
+		{
+			name: "Nil",
+			res:  core.ResultFailed,
+		},
+		{
+			name:        "DataMissing",
+			credentials: &checker.Credentials{Client: "client1"},
+			accountName: "Wallet 1/Account 1",
+			res:         core.ResultDenied,
+		},
+		{
+			name:        "WithdrawalCredentialsShort",
+			credentials: &checker.Credentials{Client: "client1"},
+			accountName: "Wallet 1/Account 1",
+			data: &rules.GenerateDepositData{
+				WithdrawalCredentials: withdrawalCredentials[1:],
+				Amount:                32000000000,
+				ForkVersion:           forkVersion,
+			},
+			res: core.ResultDenied,
+		},
+		{
+			name:        "WithdrawalCredentialsBadPrefix",
+			credentials: &checker.Credentials{Client: "client1"},
+			accountName: "Wallet 1/Account 1",
+			data: &rules.GenerateDepositData{
+				WithdrawalCredentials: badPrefixCredentials,
+				Amount:                32000000000,
+				ForkVersion:           forkVersion,
+			},
+			res: core.ResultDenied,
+		},
+		{
+			name:        "WithdrawalCredentialsBadPadding",
+			credentials: &checker.Credentials{Client: "client1"},
+			accountName: "Wallet 1/Account 1",
+			data: &rules.GenerateDepositData{
+				WithdrawalCredentials: badPaddingCredentials,
+				Amount:                32000000000,
+				ForkVersion:           forkVersion,
+			},
+			res: core.ResultDenied,
+		},
+		{
+			name:        "AmountLow",
+			credentials: &checker.Credentials{Client: "client1"},
+			accountName: "Wallet 1/Account 1",
+			data: &rules.GenerateDepositData{
+				WithdrawalCredentials: withdrawalCredentials,
+				Amount:                999999999,
+				ForkVersion:           forkVersion,
+			},
+			res: core.ResultDenied,
+		},
+		{
+			name:        "ForkVersionShort",
+			credentials: &checker.Credentials{Client: "client1"},
+			accountName: "Wallet 1/Account 1",
+			data: &rules.GenerateDepositData{
+				WithdrawalCredentials: withdrawalCredentials,
+				Amount:                32000000000,
+				ForkVersion:           forkVersion[1:],
+			},
+			res: core.ResultDenied,
+		},
+		{
+			name:        "AccountUnknown",
+			credentials: &checker.Credentials{Client: "client1"},
+			accountName: "Wallet 1/Unknown",
+			data: &rules.GenerateDepositData{
+				WithdrawalCredentials: withdrawalCredentials,
+				Amount:                32000000000,
+				ForkVersion:           forkVersion,
+			},
+			res: core.ResultDenied,
+		},
+		{
+			name:        "DeniedClient",
+			credentials: &checker.Credentials{Client: "Deny this client"},
+			accountName: "Wallet 1/Account 1",
+			data: &rules.GenerateDepositData{
+				WithdrawalCredentials: withdrawalCredentials,
+				Amount:                32000000000,
+				ForkVersion:           forkVersion,
+			},
+			res: core.ResultDenied,
+		},
+		{
+			name:        "Locked",
+			credentials: &checker.Credentials{Client: "client1"},
+			accountName: "Wallet 1/Account 2",
+			data: &rules.GenerateDepositData{
+				WithdrawalCredentials: withdrawalCredentials,
+				Amount:                32000000000,
+				ForkVersion:           forkVersion,
+			},
+			res: core.ResultDenied,
+		},
+		{
+			name:        "Distributed",
+			credentials: &checker.Credentials{Client: "client1"},
+			accountName: "Wallet 2/Account 1",
+			data: &rules.GenerateDepositData{
+				WithdrawalCredentials: withdrawalCredentials,
+				Amount:                32000000000,
+				ForkVersion:           forkVersion,
+			},
+			res: core.ResultDenied,
+		},
+		{
+			name:        "Good",
+			credentials: &checker.Credentials{Client: "client1"},
+			accountName: "Wallet 1/Account 1",
+			data: &rules.GenerateDepositData{
+				WithdrawalCredentials: withdrawalCredentials,
+				Amount:                32000000000,
+				ForkVersion:           forkVersion,
+			},
+			res: core.ResultSucceeded,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			res, depositData := signerSvc.(signer.DepositDataGenerator).GenerateDepositData(ctx, test.credentials, test.accountName, nil, test.data)
+			require.Equal(t, test.res, res)
+			if res == core.ResultSucceeded {
+				verifyDepositData(t, test.data, depositData)
+			} else {
+				require.Nil(t, depositData)
+			}
+		})
+	}
+}
+
+// verifyDepositData independently confirms the signature and roots of generated deposit data.
+func verifyDepositData(t *testing.T, data *rules.GenerateDepositData, depositData *signer.DepositData) {
+	require.NotNil(t, depositData)
+	require.Equal(t, data.WithdrawalCredentials, depositData.WithdrawalCredentials)
+	require.Equal(t, data.Amount, depositData.Amount)
+	require.Equal(t, data.ForkVersion, depositData.ForkVersion)
+
+	specData := &spec.DepositData{
+		WithdrawalCredentials: depositData.WithdrawalCredentials,
+		Amount:                spec.Gwei(depositData.Amount),
+	}
+	copy(specData.PublicKey[:], depositData.PublicKey)
+	copy(specData.Signature[:], depositData.Signature)
+	depositDataRoot, err := specData.HashTreeRoot()
+	require.NoError(t, err)
+	require.Equal(t, depositData.DepositDataRoot, depositDataRoot[:])
+
+	depositMessage := &spec.DepositMessage{
+		PublicKey:             specData.PublicKey,
+		WithdrawalCredentials: specData.WithdrawalCredentials,
+		Amount:                specData.Amount,
+	}
+	depositMessageRoot, err := depositMessage.HashTreeRoot()
+	require.NoError(t, err)
+	require.Equal(t, depositData.DepositMessageRoot, depositMessageRoot[:])
+
+	forkData := &spec.ForkData{}
+	copy(forkData.CurrentVersion[:], data.ForkVersion)
+	forkDataRoot, err := forkData.HashTreeRoot()
+	require.NoError(t, err)
+	signingData := &spec.SigningData{
+		ObjectRoot: depositMessageRoot,
+	}
+	copy(signingData.Domain[:], []byte{0x03, 0x00, 0x00, 0x00})
+	copy(signingData.Domain[4:], forkDataRoot[:28])
+	signingRoot, err := signingData.HashTreeRoot()
+	require.NoError(t, err)
+
+	pubKey, err := e2types.BLSPublicKeyFromBytes(depositData.PublicKey)
+	require.NoError(t, err)
+	sig, err := e2types.BLSSignatureFromBytes(depositData.Signature)
+	require.NoError(t, err)
+	require.True(t, sig.Verify(signingRoot[:], pubKey))
+}
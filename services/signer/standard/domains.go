@@ -0,0 +1,137 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+
+	spec "github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+// maxCachedDomains is the maximum number of domains held in the cache.  Domains
+// are derived from fork versions and genesis validators roots supplied by
+// clients, so the cache is bounded to stop it growing without limit.
+const maxCachedDomains = 256
+
+// domainKey is the key of a cached domain, made up of its domain type, fork
+// version and genesis validators root.
+type domainKey [4 + 4 + 32]byte
+
+// domainCache caches domains, which are a pure function of their domain type,
+// fork version and genesis validators root, so that the hash tree root of the
+// fork data is not recalculated for each request.  The zero value is an empty
+// cache.
+type domainCache struct {
+	mu      sync.RWMutex
+	domains map[domainKey][]byte
+}
+
+// domain returns the domain for the given domain type, fork version and
+// genesis validators root, using a cached value if available.
+func (c *domainCache) domain(domainType []byte, forkVersion []byte, genesisValidatorsRoot []byte) ([]byte, error) {
+	// The key is built in the same way as the fork data, so inputs that
+	// result in the same domain share the same key.
+	var key domainKey
+	copy(key[0:4], domainType)
+	copy(key[4:8], forkVersion)
+	copy(key[8:], genesisValidatorsRoot)
+
+	c.mu.RLock()
+	domain, exists := c.domains[key]
+	c.mu.RUnlock()
+	if !exists {
+		var err error
+		domain, err = computeDomain(domainType, forkVersion, genesisValidatorsRoot)
+		if err != nil {
+			return nil, err
+		}
+		c.mu.Lock()
+		if c.domains == nil {
+			c.domains = make(map[domainKey][]byte)
+		}
+		if len(c.domains) < maxCachedDomains {
+			c.domains[key] = domain
+		}
+		c.mu.Unlock()
+	}
+
+	// Return a copy, as callers may retain or modify the domain.
+	res := make([]byte, len(domain))
+	copy(res, domain)
+	return res, nil
+}
+
+// computeDomain calculates the domain for the given domain type, fork version
+// and genesis validators root.
+func computeDomain(domainType []byte, forkVersion []byte, genesisValidatorsRoot []byte) ([]byte, error) {
+	forkData := &spec.ForkData{}
+	copy(forkData.CurrentVersion[:], forkVersion)
+	copy(forkData.GenesisValidatorsRoot[:], genesisValidatorsRoot)
+	forkDataRoot, err := forkData.HashTreeRoot()
+	if err != nil {
+		return nil, err
+	}
+	domain := make([]byte, 32)
+	copy(domain[:4], domainType)
+	copy(domain[4:], forkDataRoot[:28])
+
+	return domain, nil
+}
+
+// parseForkVersions parses a map of domain types to fork versions, both as
+// hex strings.  Only the domain types of domains calculated by the signer can
+// be given, as other domains are supplied in full by the client.
+func parseForkVersions(domains map[string]string) (map[[4]byte][]byte, error) {
+	calculated := [][]byte{domainDeposit, domainBLSToExecutionChange, domainApplicationBuilder}
+	res := make(map[[4]byte][]byte, len(domains))
+	for domainTypeStr, forkVersionStr := range domains {
+		domainTypeBytes, err := hex.DecodeString(strings.TrimPrefix(domainTypeStr, "0x"))
+		if err != nil || len(domainTypeBytes) != 4 {
+			return nil, fmt.Errorf("invalid domain type %q", domainTypeStr)
+		}
+		var domainType [4]byte
+		copy(domainType[:], domainTypeBytes)
+		known := false
+		for i := range calculated {
+			if string(calculated[i]) == string(domainType[:]) {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return nil, fmt.Errorf("domain type %q is not calculated by the signer", domainTypeStr)
+		}
+		forkVersion, err := hex.DecodeString(strings.TrimPrefix(forkVersionStr, "0x"))
+		if err != nil || len(forkVersion) != 4 {
+			return nil, fmt.Errorf("invalid fork version %q for domain type %q", forkVersionStr, domainTypeStr)
+		}
+		res[domainType] = forkVersion
+	}
+	return res, nil
+}
+
+// forkVersion returns the fork version with which to calculate a domain of the
+// given type.  This is the configured fork version for the domain type if
+// present, otherwise the fork version supplied with the request.
+func (s *Service) forkVersion(domainType []byte, supplied []byte) []byte {
+	var key [4]byte
+	copy(key[:], domainType)
+	if forkVersion, exists := s.forkVersions[key]; exists {
+		return forkVersion
+	}
+	return supplied
+}
@@ -0,0 +1,51 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// benchmarkGenesisValidatorsRoot is the mainnet genesis validators root.
+const benchmarkGenesisValidatorsRoot = "4b363db94e286120d76eb905340fdd4e54bfe9f06bf33ff6cf5ad27f511bfe95"
+
+func BenchmarkDomainComputed(b *testing.B) {
+	genesisValidatorsRoot, err := hex.DecodeString(benchmarkGenesisValidatorsRoot)
+	require.NoError(b, err)
+	forkVersion := []byte{0x03, 0x00, 0x00, 0x00}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := computeDomain(domainBLSToExecutionChange, forkVersion, genesisValidatorsRoot); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDomainCached(b *testing.B) {
+	genesisValidatorsRoot, err := hex.DecodeString(benchmarkGenesisValidatorsRoot)
+	require.NoError(b, err)
+	forkVersion := []byte{0x03, 0x00, 0x00, 0x00}
+	cache := &domainCache{}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := cache.domain(domainBLSToExecutionChange, forkVersion, genesisValidatorsRoot); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDomainCache(t *testing.T) {
+	// Mainnet genesis validators root.
+	genesisValidatorsRoot, err := hex.DecodeString("4b363db94e286120d76eb905340fdd4e54bfe9f06bf33ff6cf5ad27f511bfe95")
+	require.NoError(t, err)
+
+	cache := &domainCache{}
+	domain, err := cache.domain(domainBLSToExecutionChange, []byte{0x00, 0x00, 0x00, 0x00}, genesisValidatorsRoot)
+	require.NoError(t, err)
+	require.Equal(t, "0a000000b5303f2ad2010d699a76c8e62350947421a3e4a979779642cfdb0f66", hex.EncodeToString(domain))
+	require.Len(t, cache.domains, 1)
+
+	// Modifying the returned domain does not affect the cache.
+	domain[0] = 0xff
+	domain, err = cache.domain(domainBLSToExecutionChange, []byte{0x00, 0x00, 0x00, 0x00}, genesisValidatorsRoot)
+	require.NoError(t, err)
+	require.Equal(t, "0a000000b5303f2ad2010d699a76c8e62350947421a3e4a979779642cfdb0f66", hex.EncodeToString(domain))
+	require.Len(t, cache.domains, 1)
+
+	// A different genesis validators root results in a different domain.
+	domain, err = cache.domain(domainBLSToExecutionChange, []byte{0x00, 0x00, 0x00, 0x00}, nil)
+	require.NoError(t, err)
+	require.Equal(t, "0a000000f5a5fd42d16a20302798ef6ed309979b43003d2320d9f0e8ea9831a9", hex.EncodeToString(domain))
+	require.Len(t, cache.domains, 2)
+
+	// As does a different domain type.
+	domain, err = cache.domain(domainApplicationBuilder, []byte{0x00, 0x00, 0x00, 0x00}, nil)
+	require.NoError(t, err)
+	require.Equal(t, "00000001f5a5fd42d16a20302798ef6ed309979b43003d2320d9f0e8ea9831a9", hex.EncodeToString(domain))
+	require.Len(t, cache.domains, 3)
+}
+
+func TestDomainCacheBounded(t *testing.T) {
+	cache := &domainCache{}
+	for i := 0; i < maxCachedDomains+10; i++ {
+		forkVersion := []byte{byte(i >> 8), byte(i), 0x00, 0x00}
+		domain, err := cache.domain(domainDeposit, forkVersion, nil)
+		require.NoError(t, err)
+		expected, err := computeDomain(domainDeposit, forkVersion, nil)
+		require.NoError(t, err)
+		require.Equal(t, expected, domain)
+	}
+	require.Len(t, cache.domains, maxCachedDomains)
+}
+
+func TestForkVersion(t *testing.T) {
+	forkVersions, err := parseForkVersions(map[string]string{
+		"0x03000000": "0x10000910",
+	})
+	require.NoError(t, err)
+	s := &Service{
+		forkVersions: forkVersions,
+	}
+
+	// The configured fork version overrides that supplied.
+	require.Equal(t, []byte{0x10, 0x00, 0x09, 0x10}, s.forkVersion(domainDeposit, []byte{0x00, 0x00, 0x00, 0x00}))
+	// Domain types without a configured fork version use that supplied.
+	require.Equal(t, []byte{0x00, 0x00, 0x00, 0x00}, s.forkVersion(domainApplicationBuilder, []byte{0x00, 0x00, 0x00, 0x00}))
+
+	// Domains are calculated with the configured fork version.
+	domain, err := s.domains.domain(domainDeposit, s.forkVersion(domainDeposit, []byte{0x00, 0x00, 0x00, 0x00}), nil)
+	require.NoError(t, err)
+	expected, err := computeDomain(domainDeposit, []byte{0x10, 0x00, 0x09, 0x10}, nil)
+	require.NoError(t, err)
+	require.Equal(t, expected, domain)
+}
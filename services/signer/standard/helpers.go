@@ -19,27 +19,42 @@ import (
 
 	"github.com/attestantio/dirk/core"
 	"github.com/attestantio/dirk/services/checker"
-	"github.com/opentracing/opentracing-go"
+	"github.com/attestantio/dirk/services/ruler"
+	"github.com/attestantio/dirk/services/unlocker"
 	e2wtypes "github.com/wealdtech/go-eth2-wallet-types/v2"
 )
 
 // preCheck carries out pre-checks for all signing requests.
 func (s *Service) preCheck(ctx context.Context, credentials *checker.Credentials, name string, pubKey []byte, action string) (e2wtypes.Wallet, e2wtypes.Account, core.Result) {
-	// Fetch the account.
-	wallet, account, result := s.fetchAccount(ctx, credentials, name, pubKey)
+	wallet, account, result := s.preCheckAccess(ctx, credentials, name, pubKey, action)
 	if result != core.ResultSucceeded {
 		return nil, nil, result
 	}
-	accountName := fmt.Sprintf("%s/%s", wallet.Name(), account.Name())
 
-	// Check if the account is allowed to carry out the requested action.
-	result = s.checkAccess(ctx, credentials, accountName, action)
+	result = s.preCheckUnlock(ctx, wallet, account, action)
 	if result != core.ResultSucceeded {
 		return nil, nil, result
 	}
 
-	// Unlock the account if necessary.
-	result = s.unlockAccount(ctx, wallet, account)
+	return wallet, account, core.ResultSucceeded
+}
+
+// preCheckAccess carries out the pre-checks that fetch the account and confirm
+// that the client can carry out the requested action with it.
+func (s *Service) preCheckAccess(ctx context.Context, credentials *checker.Credentials, name string, pubKey []byte, action string) (e2wtypes.Wallet, e2wtypes.Account, core.Result) {
+	// Fetch the account.
+	stageCtx, finish := startStageSpan(ctx, "fetchAccount", action)
+	wallet, account, result := s.fetchAccount(stageCtx, credentials, name, pubKey)
+	finish(resultOutcome(result))
+	if result != core.ResultSucceeded {
+		return nil, nil, result
+	}
+	accountName := fmt.Sprintf("%s/%s", wallet.Name(), account.Name())
+
+	// Check if the account is allowed to carry out the requested action.
+	stageCtx, finish = startStageSpan(ctx, "checkAccess", action)
+	result = s.checkAccess(stageCtx, credentials, accountName, action)
+	finish(resultOutcome(result))
 	if result != core.ResultSucceeded {
 		return nil, nil, result
 	}
@@ -47,6 +62,14 @@ func (s *Service) preCheck(ctx context.Context, credentials *checker.Credentials
 	return wallet, account, core.ResultSucceeded
 }
 
+// preCheckUnlock carries out the pre-check that unlocks the account if necessary.
+func (s *Service) preCheckUnlock(ctx context.Context, wallet e2wtypes.Wallet, account e2wtypes.Account, action string) core.Result {
+	stageCtx, finish := startStageSpan(ctx, "accountUnlock", action)
+	result := s.unlockAccount(stageCtx, wallet, account)
+	finish(resultOutcome(result))
+	return result
+}
+
 // fetchAccount fetches an account by either name or public key, depending on which has been supplied.
 func (s *Service) fetchAccount(ctx context.Context, credentials *checker.Credentials, name string, pubKey []byte) (e2wtypes.Wallet, e2wtypes.Account, core.Result) {
 	if name == "" && pubKey == nil {
@@ -85,11 +108,18 @@ func (s *Service) checkAccess(ctx context.Context, credentials *checker.Credenti
 	return core.ResultDenied
 }
 
+// acquireProposalLease returns true if the client can propose for the account and slot; this is
+// always the case if the ruler does not provide proposal leases.
+func (s *Service) acquireProposalLease(ctx context.Context, credentials *checker.Credentials, pubKey []byte, slot uint64) bool {
+	leaser, isLeaser := s.ruler.(ruler.ProposalLeaser)
+	if !isLeaser {
+		return true
+	}
+	return leaser.AcquireProposalLease(ctx, credentials, pubKey, slot) == nil
+}
+
 // unlockAccount returns true if the client can access the account.
 func (s *Service) unlockAccount(ctx context.Context, wallet e2wtypes.Wallet, account e2wtypes.Account) core.Result {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "services.signer.accountUnlock")
-	defer span.Finish()
-
 	if wallet == nil {
 		log.Warn().Str("result", "denied").Msg("No wallet provided")
 		return core.ResultDenied
@@ -111,6 +141,9 @@ func (s *Service) unlockAccount(ctx context.Context, wallet e2wtypes.Wallet, acc
 		return core.ResultFailed
 	}
 	if unlocked {
+		if relocker, isRelocker := s.unlocker.(unlocker.IdleRelocker); isRelocker {
+			relocker.AccountUsed(ctx, wallet, account)
+		}
 		log.Trace().Str("result", "succeeded").Msg("Account is unlocked")
 		return core.ResultSucceeded
 	}
@@ -129,3 +162,12 @@ func (s *Service) unlockAccount(ctx context.Context, wallet e2wtypes.Wallet, acc
 	log.Trace().Str("result", "succeeded").Msg("Account is unlocked")
 	return core.ResultSucceeded
 }
+
+// messagePublicKey returns the public key of an account as it appears in signed messages.
+// For distributed accounts this is the composite public key, rather than the account's share.
+func messagePublicKey(account e2wtypes.Account) []byte {
+	if provider, isProvider := account.(e2wtypes.AccountCompositePublicKeyProvider); isProvider {
+		return provider.CompositePublicKey().Marshal()
+	}
+	return account.PublicKey().Marshal()
+}
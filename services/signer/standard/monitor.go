@@ -25,3 +25,15 @@ type noopMonitor struct{}
 
 // SignCompleted is called when a siging process has completed.
 func (n *noopMonitor) SignCompleted(started time.Time, request string, result core.Result) {}
+
+// SignQueueCompleted is called when a signing request has finished waiting for capacity.
+func (n *noopMonitor) SignQueueCompleted(started time.Time, request string) {}
+
+// SignCapacity is called when the number of signing operations in progress or waiting for capacity changes.
+func (n *noopMonitor) SignCapacity(inflight int, queued int) {}
+
+// SignCoalesced is called when a signing request shares the result of an identical in-progress request.
+func (n *noopMonitor) SignCoalesced(request string) {}
+
+// TaggedSignCompleted is called when a signing process for a permitted tagged message has completed.
+func (n *noopMonitor) TaggedSignCompleted(tag string, result core.Result) {}
@@ -35,6 +35,15 @@ func (s *Service) Multisign(ctx context.Context,
 	[]core.Result,
 	[][]byte,
 ) {
+	release, result := s.admit(ctx, "generic")
+	if result != core.ResultSucceeded {
+		results := make([]core.Result, len(data))
+		for i := range results {
+			results[i] = result
+		}
+		return results, nil
+	}
+	defer release()
 	started := time.Now()
 
 	if len(data) == 0 {
@@ -66,37 +75,36 @@ func (s *Service) Multisign(ctx context.Context,
 	log.Trace().Msg("Signing")
 	signatures := make([][]byte, len(data))
 
-	// Check input.
+	// Check input.  Invalid entries are denied individually, so that they do not
+	// prevent the remainder of the batch from being signed.
 	for i := range data {
-		if data[i] == nil {
-			log.Warn().Str("result", "denied").Msg("Request empty")
-			s.monitor.SignCompleted(started, "generic", core.ResultDenied)
-			results[i] = core.ResultDenied
-			return results, nil
+		var reason string
+		switch {
+		case data[i] == nil:
+			reason = "Request empty"
+		case data[i].Data == nil:
+			reason = "Request missing data"
+		case data[i].Domain == nil:
+			reason = "Request missing domain"
 		}
-		if data[i].Data == nil {
-			log.Warn().Str("result", "denied").Msg("Request missing data")
+		if reason != "" {
+			log.Warn().Int("entry", i).Str("result", "denied").Msg(reason)
 			s.monitor.SignCompleted(started, "generic", core.ResultDenied)
 			results[i] = core.ResultDenied
-			return results, nil
-		}
-		if data[i].Domain == nil {
-			log.Warn().Str("result", "denied").Msg("Request missing domain")
-			s.monitor.SignCompleted(started, "generic", core.ResultDenied)
-			results[i] = core.ResultDenied
-			return results, nil
 		}
 	}
 
-	// We could have either or both of account names and/or entries, so take the longer
-	entries := len(pubKeys)
-	if len(accountNames) > entries {
-		entries = len(accountNames)
-	}
+	// We could have either or both of account names and/or public keys for each
+	// entry; entries with neither are denied by the precheck.
+	entries := len(data)
 	rulesData := make([]*ruler.RulesData, entries)
 	accounts := make([]e2wtypes.Account, entries)
 	_, err := util.Scatter(entries, func(offset int, entries int, _ *sync.RWMutex) (interface{}, error) {
 		for i := offset; i < offset+entries; i++ {
+			if results[i] != core.ResultUnknown {
+				continue
+			}
+
 			var pubKey []byte
 			if len(pubKeys) > i {
 				pubKey = pubKeys[i]
@@ -126,22 +134,31 @@ func (s *Service) Multisign(ctx context.Context,
 	if err != nil {
 		log.Error().Err(err).Str("result", "failed").Msg("Failed to scatter check")
 	}
-	for i := range results {
-		if results[i] != core.ResultUnknown && results[i] != core.ResultSucceeded {
-			s.monitor.SignCompleted(started, "generic", results[i])
-			return results, nil
+	log.Trace().Dur("elapsed", time.Since(started)).Msg("Completed precheck")
+
+	// Only entries that passed the precheck are passed to the rules; each is
+	// still evaluated individually.
+	indices := make([]int, 0, entries)
+	checkedRulesData := make([]*ruler.RulesData, 0, entries)
+	for i := range rulesData {
+		if rulesData[i] != nil {
+			indices = append(indices, i)
+			checkedRulesData = append(checkedRulesData, rulesData[i])
 		}
 	}
-	log.Trace().Dur("elapsed", time.Since(started)).Msg("Completed precheck")
+	if len(indices) == 0 {
+		return results, signatures
+	}
 
 	// Confirm approval via rules.
-	rulesResults := s.ruler.RunRules(ctx, credentials, ruler.ActionSign, rulesData)
+	rulesResults := s.runRules(ctx, credentials, ruler.ActionSign, checkedRulesData)
 	log.Trace().Dur("elapsed", time.Since(started)).Msg("Completed rules")
 
 	// Carry out the signing.
 	_, err = util.Scatter(len(rulesResults), func(offset int, entries int, _ *sync.RWMutex) (interface{}, error) {
-		for i := offset; i < offset+entries; i++ {
-			switch rulesResults[i] {
+		for j := offset; j < offset+entries; j++ {
+			i := indices[j]
+			switch rulesResults[j] {
 			case rules.UNKNOWN:
 				log.Debug().Str("result", "failed").Msg("Unknown result from rules")
 				s.monitor.SignCompleted(started, "generic", core.ResultFailed)
@@ -168,7 +185,7 @@ func (s *Service) Multisign(ctx context.Context,
 			}
 
 			// Sign it.
-			signature, err := signRoot(ctx, accounts[i], signingRoot[:])
+			signature, err := signRoot(ctx, ruler.ActionSign, accounts[i], signingRoot[:])
 			if err != nil {
 				log.Error().Err(err).Str("result", "failed").Msg("Failed to sign")
 				s.monitor.SignCompleted(started, "generic", core.ResultFailed)
@@ -219,6 +219,44 @@ func TestMultisign(t *testing.T) {
 			pubKeys:      [][]byte{pubKeys["Test account 1"]},
 			res:          []core.Result{core.ResultSucceeded},
 		},
+		{
+			name:        "Partial",
+			credentials: &checker.Credentials{Client: "client1"},
+			data: []*rules.SignData{
+				{
+					Data: []byte{
+						0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01,
+						0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01,
+						0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01,
+						0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01,
+					},
+					Domain: []byte{
+						0x02, 0x02, 0x02, 0x02, 0x02, 0x02, 0x02, 0x02,
+						0x02, 0x02, 0x02, 0x02, 0x02, 0x02, 0x02, 0x02,
+						0x02, 0x02, 0x02, 0x02, 0x02, 0x02, 0x02, 0x02,
+						0x02, 0x02, 0x02, 0x02, 0x02, 0x02, 0x02, 0x02,
+					},
+				},
+				nil,
+				{
+					Data: []byte{
+						0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01,
+						0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01,
+						0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01,
+						0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01,
+					},
+					Domain: []byte{
+						0x02, 0x02, 0x02, 0x02, 0x02, 0x02, 0x02, 0x02,
+						0x02, 0x02, 0x02, 0x02, 0x02, 0x02, 0x02, 0x02,
+						0x02, 0x02, 0x02, 0x02, 0x02, 0x02, 0x02, 0x02,
+						0x02, 0x02, 0x02, 0x02, 0x02, 0x02, 0x02, 0x02,
+					},
+				},
+			},
+			accountNames: []string{"Test wallet/Test account 1", "Test wallet/Test account 1", "Test wallet/Unknown"},
+			res:          []core.Result{core.ResultSucceeded, core.ResultDenied, core.ResultDenied},
+			logEntry:     "Request empty",
+		},
 		{
 			name:        "Duplicate",
 			credentials: &checker.Credentials{Client: "client1"},
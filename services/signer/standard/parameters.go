@@ -14,6 +14,9 @@
 package standard
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/attestantio/dirk/services/checker"
 	"github.com/attestantio/dirk/services/fetcher"
 	"github.com/attestantio/dirk/services/metrics"
@@ -24,12 +27,18 @@ import (
 )
 
 type parameters struct {
-	logLevel zerolog.Level
-	monitor  metrics.SignerMonitor
-	checker  checker.Service
-	fetcher  fetcher.Service
-	ruler    ruler.Service
-	unlocker unlocker.Service
+	logLevel      zerolog.Level
+	monitor       metrics.SignerMonitor
+	checker       checker.Service
+	fetcher       fetcher.Service
+	ruler         ruler.Service
+	unlocker      unlocker.Service
+	maxConcurrent int
+	queueTimeout  time.Duration
+	messageTags   map[string][]string
+	deduplicate   bool
+	domains       map[string]string
+	forkVersions  map[[4]byte][]byte
 }
 
 // Parameter is the interface for service parameters.
@@ -85,6 +94,44 @@ func WithFetcher(fetcher fetcher.Service) Parameter {
 	})
 }
 
+// WithMaxConcurrent sets the maximum number of concurrent signing operations.  A value of 0 is unlimited.
+func WithMaxConcurrent(maxConcurrent int) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.maxConcurrent = maxConcurrent
+	})
+}
+
+// WithQueueTimeout sets the maximum time that a signing request waits for capacity when the number of concurrent
+// signing operations is limited.  A value of 0 waits for as long as the request is active.
+func WithQueueTimeout(queueTimeout time.Duration) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.queueTimeout = queueTimeout
+	})
+}
+
+// WithMessageTags sets the message type tags that each client is permitted to sign with SignTagged.
+func WithMessageTags(messageTags map[string][]string) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.messageTags = messageTags
+	})
+}
+
+// WithDeduplicate sets whether concurrent identical signing requests share a single signing operation.
+func WithDeduplicate(deduplicate bool) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.deduplicate = deduplicate
+	})
+}
+
+// WithDomains sets the fork versions, keyed by domain type, with which the
+// signer calculates domains, overriding those supplied with requests.  Both
+// are hex strings.
+func WithDomains(domains map[string]string) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.domains = domains
+	})
+}
+
 // parseAndCheckParameters parses and checks parameters to ensure that mandatory parameters are present and correct.
 func parseAndCheckParameters(params ...Parameter) (*parameters, error) {
 	parameters := parameters{
@@ -112,6 +159,24 @@ func parseAndCheckParameters(params ...Parameter) (*parameters, error) {
 	if parameters.fetcher == nil {
 		return nil, errors.New("no fetcher specified")
 	}
+	if parameters.maxConcurrent < 0 {
+		return nil, errors.New("max concurrent cannot be negative")
+	}
+	if parameters.queueTimeout < 0 {
+		return nil, errors.New("queue timeout cannot be negative")
+	}
+	for client, tags := range parameters.messageTags {
+		for _, tag := range tags {
+			if tag == "" {
+				return nil, fmt.Errorf("empty message tag for client %q", client)
+			}
+		}
+	}
+	forkVersions, err := parseForkVersions(parameters.domains)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid domains")
+	}
+	parameters.forkVersions = forkVersions
 
 	return &parameters, nil
 }
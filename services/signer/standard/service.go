@@ -15,6 +15,8 @@ package standard
 
 import (
 	context "context"
+	"sync"
+	"time"
 
 	"github.com/attestantio/dirk/services/checker"
 	"github.com/attestantio/dirk/services/fetcher"
@@ -33,6 +35,21 @@ type Service struct {
 	fetcher  fetcher.Service
 	ruler    ruler.Service
 	unlocker unlocker.Service
+	// slots limits the number of concurrent signing operations; nil if unlimited.
+	slots chan struct{}
+	// queueTimeout is the maximum time a request waits for a slot; 0 if unlimited.
+	queueTimeout time.Duration
+	// queued is the number of requests waiting for a slot.
+	queued int32
+	// domains caches the domains calculated for signing requests.
+	domains domainCache
+	// forkVersions are the configured fork versions for calculated domains, keyed by domain type.
+	forkVersions map[[4]byte][]byte
+	// messageTags are the message type tags each client is permitted to sign.
+	messageTags map[string]map[string]bool
+	// inflight are the signing operations in progress; nil if de-duplication is disabled.
+	inflight   map[string]*inflightSign
+	inflightMu sync.Mutex
 }
 
 // module-wide log.
@@ -51,11 +68,29 @@ func New(ctx context.Context, params ...Parameter) (*Service, error) {
 		log = log.Level(parameters.logLevel)
 	}
 
-	return &Service{
+	s := &Service{
 		monitor:  parameters.monitor,
 		unlocker: parameters.unlocker,
 		checker:  parameters.checker,
 		fetcher:  parameters.fetcher,
 		ruler:    parameters.ruler,
-	}, nil
+		// Fork versions are read-only once set, so need no lock.
+		forkVersions: parameters.forkVersions,
+	}
+	s.messageTags = make(map[string]map[string]bool, len(parameters.messageTags))
+	for client, tags := range parameters.messageTags {
+		s.messageTags[client] = make(map[string]bool, len(tags))
+		for _, tag := range tags {
+			s.messageTags[client][tag] = true
+		}
+	}
+	if parameters.deduplicate {
+		s.inflight = make(map[string]*inflightSign)
+	}
+	if parameters.maxConcurrent > 0 {
+		s.slots = make(chan struct{}, parameters.maxConcurrent)
+		s.queueTimeout = parameters.queueTimeout
+	}
+
+	return s, nil
 }
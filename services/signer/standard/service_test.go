@@ -104,6 +104,7 @@ func TestService(t *testing.T) {
 		checker  checker.Service
 		fetcher  fetcher.Service
 		ruler    ruler.Service
+		domains  map[string]string
 		err      string
 	}{
 		{
@@ -142,6 +143,36 @@ func TestService(t *testing.T) {
 			ruler:   rulerSvc,
 			err:     "problem with parameters: no unlocker specified",
 		},
+		{
+			name:     "DomainTypeInvalid",
+			monitor:  monitorSvc,
+			unlocker: unlockerSvc,
+			checker:  checkerSvc,
+			fetcher:  fetcherSvc,
+			ruler:    rulerSvc,
+			domains:  map[string]string{"0x030000": "0x10000910"},
+			err:      `problem with parameters: invalid domains: invalid domain type "0x030000"`,
+		},
+		{
+			name:     "DomainTypeNotCalculated",
+			monitor:  monitorSvc,
+			unlocker: unlockerSvc,
+			checker:  checkerSvc,
+			fetcher:  fetcherSvc,
+			ruler:    rulerSvc,
+			domains:  map[string]string{"0x01000000": "0x10000910"},
+			err:      `problem with parameters: invalid domains: domain type "0x01000000" is not calculated by the signer`,
+		},
+		{
+			name:     "ForkVersionInvalid",
+			monitor:  monitorSvc,
+			unlocker: unlockerSvc,
+			checker:  checkerSvc,
+			fetcher:  fetcherSvc,
+			ruler:    rulerSvc,
+			domains:  map[string]string{"0x03000000": "0xinvalid"},
+			err:      `problem with parameters: invalid domains: invalid fork version "0xinvalid" for domain type "0x03000000"`,
+		},
 		{
 			name:     "Good",
 			monitor:  monitorSvc,
@@ -150,6 +181,15 @@ func TestService(t *testing.T) {
 			fetcher:  fetcherSvc,
 			ruler:    rulerSvc,
 		},
+		{
+			name:     "GoodDomains",
+			monitor:  monitorSvc,
+			unlocker: unlockerSvc,
+			checker:  checkerSvc,
+			fetcher:  fetcherSvc,
+			ruler:    rulerSvc,
+			domains:  map[string]string{"0x03000000": "0x10000910", "0a000000": "10000910"},
+		},
 	}
 
 	for _, test := range tests {
@@ -160,7 +200,8 @@ func TestService(t *testing.T) {
 				standardsigner.WithUnlocker(test.unlocker),
 				standardsigner.WithChecker(test.checker),
 				standardsigner.WithFetcher(test.fetcher),
-				standardsigner.WithRuler(test.ruler))
+				standardsigner.WithRuler(test.ruler),
+				standardsigner.WithDomains(test.domains))
 			if test.err == "" {
 				assert.NoError(t, err)
 			} else {
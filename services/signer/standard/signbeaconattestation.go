@@ -51,43 +51,15 @@ func (s *Service) SignBeaconAttestation(
 	log.Trace().Msg("Signing")
 
 	// Check input.
-	if data == nil {
-		log.Warn().Str("result", "denied").Msg("Request missing data")
-		s.monitor.SignCompleted(started, "attestation", core.ResultDenied)
-		return core.ResultDenied, nil
-	}
-	if data.BeaconBlockRoot == nil {
-		log.Warn().Str("result", "denied").Msg("Request missing beacon block root")
-		s.monitor.SignCompleted(started, "attestation", core.ResultDenied)
-		return core.ResultDenied, nil
-	}
-	if data.Domain == nil {
-		log.Warn().Str("result", "denied").Msg("Request missing domain")
-		s.monitor.SignCompleted(started, "attestation", core.ResultDenied)
-		return core.ResultDenied, nil
-	}
-	if data.Source == nil {
-		log.Warn().Str("result", "denied").Msg("Request missing source")
-		s.monitor.SignCompleted(started, "attestation", core.ResultDenied)
-		return core.ResultDenied, nil
-	}
-	if data.Source.Root == nil {
-		log.Warn().Str("result", "denied").Msg("Request missing source root")
-		s.monitor.SignCompleted(started, "attestation", core.ResultDenied)
-		return core.ResultDenied, nil
-	}
-	if data.Target == nil {
-		log.Warn().Str("result", "denied").Msg("Request missing target")
-		s.monitor.SignCompleted(started, "attestation", core.ResultDenied)
-		return core.ResultDenied, nil
-	}
-	if data.Target.Root == nil {
-		log.Warn().Str("result", "denied").Msg("Request missing target root")
+	if reason := validateSignBeaconAttestationData(data); reason != "" {
+		log.Warn().Str("result", "denied").Msg(reason)
 		s.monitor.SignCompleted(started, "attestation", core.ResultDenied)
 		return core.ResultDenied, nil
 	}
 
-	wallet, account, checkRes := s.preCheck(ctx, credentials, accountName, pubKey, ruler.ActionSignBeaconAttestation)
+	// Access is checked for every request, but capacity is only taken for the signing itself so
+	// that identical requests sharing a signing operation do not take capacity of their own.
+	wallet, account, checkRes := s.preCheckAccess(ctx, credentials, accountName, pubKey, ruler.ActionSignBeaconAttestation)
 	if checkRes != core.ResultSucceeded {
 		s.monitor.SignCompleted(started, "attestation", checkRes)
 		return checkRes, nil
@@ -95,27 +67,6 @@ func (s *Service) SignBeaconAttestation(
 	accountName = fmt.Sprintf("%s/%s", wallet.Name(), account.Name())
 	log = log.With().Str("account", accountName).Logger()
 
-	// Confirm approval via rules.
-	rulesData := []*ruler.RulesData{
-		{
-			WalletName:  wallet.Name(),
-			AccountName: account.Name(),
-			PubKey:      account.PublicKey().Marshal(),
-			Data:        data,
-		},
-	}
-	results := s.ruler.RunRules(ctx, credentials, ruler.ActionSignBeaconAttestation, rulesData)
-	switch results[0] {
-	case rules.DENIED:
-		log.Debug().Str("result", "denied").Msg("Denied by rules")
-		s.monitor.SignCompleted(started, "attestation", core.ResultDenied)
-		return core.ResultDenied, nil
-	case rules.FAILED:
-		log.Error().Str("result", "failed").Msg("Rules check failed")
-		s.monitor.SignCompleted(started, "attestation", core.ResultFailed)
-		return core.ResultFailed, nil
-	}
-
 	// Create a spec version of the attestation to obtain its hash tree root.
 	attestation := &spec.AttestationData{
 		Slot:  spec.Slot(data.Slot),
@@ -143,12 +94,52 @@ func (s *Service) SignBeaconAttestation(
 		return core.ResultFailed, nil
 	}
 
-	// Sign it.
-	signature, err := signRoot(ctx, account, signingRoot[:])
-	if err != nil {
-		log.Error().Err(err).Str("result", "failed").Msg("Failed to sign")
-		s.monitor.SignCompleted(started, "attestation", core.ResultFailed)
-		return core.ResultFailed, nil
+	// Access has been checked for this request, so the rules and signing can be shared with identical requests.
+	accountPubKey := account.PublicKey().Marshal()
+	res, signature := s.coalesce(ctx, "attestation", accountPubKey, data.Domain, signingRoot[:], func(ctx context.Context) (core.Result, []byte) {
+		release, result := s.admit(ctx, "attestation")
+		if result != core.ResultSucceeded {
+			return result, nil
+		}
+		defer release()
+
+		if result := s.preCheckUnlock(ctx, wallet, account, ruler.ActionSignBeaconAttestation); result != core.ResultSucceeded {
+			return result, nil
+		}
+
+		// Confirm approval via rules.
+		rulesData := []*ruler.RulesData{
+			{
+				WalletName:  wallet.Name(),
+				AccountName: account.Name(),
+				PubKey:      accountPubKey,
+				Data:        data,
+			},
+		}
+		results := s.runRules(ctx, credentials, ruler.ActionSignBeaconAttestation, rulesData)
+		switch results[0] {
+		case rules.UNKNOWN:
+			log.Debug().Str("result", "failed").Msg("Unknown result from rules")
+			return core.ResultFailed, nil
+		case rules.DENIED:
+			log.Debug().Str("result", "denied").Msg("Denied by rules")
+			return core.ResultDenied, nil
+		case rules.FAILED:
+			log.Error().Str("result", "failed").Msg("Rules check failed")
+			return core.ResultFailed, nil
+		}
+
+		// Sign it.
+		signature, err := signRoot(ctx, ruler.ActionSignBeaconAttestation, account, signingRoot[:])
+		if err != nil {
+			log.Error().Err(err).Str("result", "failed").Msg("Failed to sign")
+			return core.ResultFailed, nil
+		}
+		return core.ResultSucceeded, signature
+	})
+	if res != core.ResultSucceeded {
+		s.monitor.SignCompleted(started, "attestation", res)
+		return res, nil
 	}
 
 	log.Trace().Str("result", "succeeded").Msg("Success")
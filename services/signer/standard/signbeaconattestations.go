@@ -38,6 +38,15 @@ func (s *Service) SignBeaconAttestations(
 	[]core.Result,
 	[][]byte,
 ) {
+	release, result := s.admit(ctx, "attestation")
+	if result != core.ResultSucceeded {
+		results := make([]core.Result, len(data))
+		for i := range results {
+			results[i] = result
+		}
+		return results, nil
+	}
+	defer release()
 	started := time.Now()
 
 	if len(data) == 0 {
@@ -163,7 +172,7 @@ func (s *Service) SignBeaconAttestations(
 	log.Trace().Dur("elapsed", time.Since(started)).Msg("Completed precheck")
 
 	// Confirm approval via rules.
-	rulesResults := s.ruler.RunRules(ctx, credentials, ruler.ActionSignBeaconAttestation, rulesData)
+	rulesResults := s.runRules(ctx, credentials, ruler.ActionSignBeaconAttestation, rulesData)
 	log.Trace().Dur("elapsed", time.Since(started)).Msg("Completed rules")
 
 	// Carry out the signing.
@@ -217,7 +226,7 @@ func (s *Service) SignBeaconAttestations(
 			}
 
 			// Sign it.
-			signature, err := signRoot(ctx, accounts[i], signingRoot[:])
+			signature, err := signRoot(ctx, ruler.ActionSignBeaconAttestation, accounts[i], signingRoot[:])
 			if err != nil {
 				log.Error().Err(err).Str("result", "failed").Msg("Failed to sign")
 				s.monitor.SignCompleted(started, "attestation", core.ResultFailed)
@@ -36,6 +36,11 @@ func (s *Service) SignBeaconProposal(
 	core.Result,
 	[]byte,
 ) {
+	release, result := s.admit(ctx, "proposal")
+	if result != core.ResultSucceeded {
+		return result, nil
+	}
+	defer release()
 	started := time.Now()
 
 	if credentials == nil {
@@ -51,31 +56,11 @@ func (s *Service) SignBeaconProposal(
 	log.Trace().Msg("Signing")
 
 	// Check input.
-	if data == nil {
-		log.Warn().Str("result", "denied").Msg("Request missing data")
+	if reason := validateSignBeaconProposalData(data); reason != "" {
+		log.Warn().Str("result", "denied").Msg(reason)
 		s.monitor.SignCompleted(started, "proposal", core.ResultDenied)
 		return core.ResultDenied, nil
 	}
-	if data.Domain == nil {
-		log.Warn().Str("result", "denied").Msg("Request missing domain")
-		s.monitor.SignCompleted(started, "proposal", core.ResultDenied)
-		return core.ResultDenied, nil
-	}
-	if data.ParentRoot == nil {
-		log.Warn().Str("result", "denied").Msg("Request missing parent root")
-		s.monitor.SignCompleted(started, "proposal", core.ResultDenied)
-		return core.ResultDenied, nil
-	}
-	if data.BodyRoot == nil {
-		s.monitor.SignCompleted(started, "proposal", core.ResultDenied)
-		log.Warn().Str("result", "denied").Msg("Request missing body root")
-		return core.ResultDenied, nil
-	}
-	if data.StateRoot == nil {
-		s.monitor.SignCompleted(started, "proposal", core.ResultDenied)
-		log.Warn().Str("result", "denied").Msg("Request missing state root")
-		return core.ResultDenied, nil
-	}
 
 	wallet, account, checkRes := s.preCheck(ctx, credentials, accountName, pubKey, ruler.ActionSignBeaconProposal)
 	if checkRes != core.ResultSucceeded {
@@ -85,6 +70,13 @@ func (s *Service) SignBeaconProposal(
 	accountName = fmt.Sprintf("%s/%s", wallet.Name(), account.Name())
 	log = log.With().Str("account", accountName).Logger()
 
+	// Ensure that no other client is proposing for this account and slot.
+	if !s.acquireProposalLease(ctx, credentials, account.PublicKey().Marshal(), data.Slot) {
+		log.Debug().Str("result", "in progress").Msg("Proposal already in progress")
+		s.monitor.SignCompleted(started, "proposal", core.ResultInProgress)
+		return core.ResultInProgress, nil
+	}
+
 	// Confirm approval via rules.
 	rulesData := []*ruler.RulesData{
 		{
@@ -94,8 +86,12 @@ func (s *Service) SignBeaconProposal(
 			Data:        data,
 		},
 	}
-	results := s.ruler.RunRules(ctx, credentials, ruler.ActionSignBeaconProposal, rulesData)
+	results := s.runRules(ctx, credentials, ruler.ActionSignBeaconProposal, rulesData)
 	switch results[0] {
+	case rules.UNKNOWN:
+		log.Debug().Str("result", "failed").Msg("Unknown result from rules")
+		s.monitor.SignCompleted(started, "proposal", core.ResultFailed)
+		return core.ResultFailed, nil
 	case rules.DENIED:
 		log.Debug().Str("result", "denied").Msg("Denied by rules")
 		s.monitor.SignCompleted(started, "proposal", core.ResultDenied)
@@ -128,7 +124,7 @@ func (s *Service) SignBeaconProposal(
 	}
 
 	// Sign it.
-	signature, err := signRoot(ctx, account, signingRoot[:])
+	signature, err := signRoot(ctx, ruler.ActionSignBeaconProposal, account, signingRoot[:])
 	if err != nil {
 		log.Error().Err(err).Str("result", "failed").Msg("Failed to sign")
 		s.monitor.SignCompleted(started, "proposal", core.ResultFailed)
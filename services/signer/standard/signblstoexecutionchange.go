@@ -0,0 +1,175 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard
+
+import (
+	context "context"
+	"fmt"
+	"time"
+
+	"github.com/attestantio/dirk/core"
+	"github.com/attestantio/dirk/rules"
+	"github.com/attestantio/dirk/services/checker"
+	"github.com/attestantio/dirk/services/ruler"
+	ssz "github.com/ferranbt/fastssz"
+)
+
+// domainBLSToExecutionChange is the domain type for BLS to execution changes.
+var domainBLSToExecutionChange = []byte{0x0a, 0x00, 0x00, 0x00}
+
+// blsToExecutionChange is the message that changes a validator's withdrawal
+// credentials from a BLS public key to an execution address.
+type blsToExecutionChange struct {
+	ValidatorIndex     uint64
+	FromBLSPubKey      []byte `ssz-size:"48"`
+	ToExecutionAddress []byte `ssz-size:"20"`
+}
+
+// HashTreeRoot ssz hashes the blsToExecutionChange object.
+func (b *blsToExecutionChange) HashTreeRoot() ([32]byte, error) {
+	return ssz.HashWithDefaultHasher(b)
+}
+
+// HashTreeRootWith ssz hashes the blsToExecutionChange object with a hasher.
+func (b *blsToExecutionChange) HashTreeRootWith(hh *ssz.Hasher) error {
+	indx := hh.Index()
+
+	hh.PutUint64(b.ValidatorIndex)
+	if len(b.FromBLSPubKey) != 48 {
+		return ssz.ErrBytesLength
+	}
+	hh.PutBytes(b.FromBLSPubKey)
+	if len(b.ToExecutionAddress) != 20 {
+		return ssz.ErrBytesLength
+	}
+	hh.PutBytes(b.ToExecutionAddress)
+
+	hh.Merkleize(indx)
+	return nil
+}
+
+// SignBLSToExecutionChange signs a BLS to execution change.
+func (s *Service) SignBLSToExecutionChange(
+	ctx context.Context,
+	credentials *checker.Credentials,
+	accountName string,
+	pubKey []byte,
+	data *rules.SignBLSToExecutionChangeData,
+) (
+	core.Result,
+	[]byte,
+) {
+	release, result := s.admit(ctx, "blstoexecutionchange")
+	if result != core.ResultSucceeded {
+		return result, nil
+	}
+	defer release()
+	started := time.Now()
+
+	if credentials == nil {
+		log.Error().Msg("No credentials supplied")
+		return core.ResultFailed, nil
+	}
+
+	log := log.With().
+		Str("request_id", credentials.RequestID).
+		Str("action", "SignBLSToExecutionChange").
+		Str("client", credentials.Client).
+		Logger()
+	log.Trace().Msg("Request received")
+
+	// Check input.
+	if reason := validateSignBLSToExecutionChangeData(data); reason != "" {
+		log.Warn().Str("result", "denied").Msg(reason)
+		s.monitor.SignCompleted(started, "blstoexecutionchange", core.ResultDenied)
+		return core.ResultDenied, nil
+	}
+	log = log.With().Uint64("validator_index", data.ValidatorIndex).Logger()
+
+	wallet, account, checkRes := s.preCheck(ctx, credentials, accountName, pubKey, ruler.ActionSignBLSToExecutionChange)
+	if checkRes != core.ResultSucceeded {
+		s.monitor.SignCompleted(started, "blstoexecutionchange", checkRes)
+		return checkRes, nil
+	}
+	accountName = fmt.Sprintf("%s/%s", wallet.Name(), account.Name())
+	log = log.With().Str("account", accountName).Logger()
+
+	// The change is signed by the withdrawal key, which is the key of the account.
+	data.FromBLSPubKey = messagePublicKey(account)
+	domain, err := s.blsToExecutionChangeDomain(data.GenesisForkVersion, data.GenesisValidatorsRoot)
+	if err != nil {
+		log.Error().Err(err).Str("result", "failed").Msg("Failed to generate domain")
+		s.monitor.SignCompleted(started, "blstoexecutionchange", core.ResultFailed)
+		return core.ResultFailed, nil
+	}
+	data.Domain = domain
+
+	// Confirm approval via rules.
+	rulesData := []*ruler.RulesData{
+		{
+			WalletName:  wallet.Name(),
+			AccountName: account.Name(),
+			PubKey:      account.PublicKey().Marshal(),
+			Data:        data,
+		},
+	}
+	results := s.runRules(ctx, credentials, ruler.ActionSignBLSToExecutionChange, rulesData)
+	switch results[0] {
+	case rules.APPROVED:
+	case rules.DENIED:
+		log.Debug().Str("result", "denied").Msg("Denied by rules")
+		s.monitor.SignCompleted(started, "blstoexecutionchange", core.ResultDenied)
+		return core.ResultDenied, nil
+	default:
+		log.Error().Str("result", "failed").Stringer("rules_result", results[0]).Msg("Rules check failed")
+		s.monitor.SignCompleted(started, "blstoexecutionchange", core.ResultFailed)
+		return core.ResultFailed, nil
+	}
+
+	message := &blsToExecutionChange{
+		ValidatorIndex:     data.ValidatorIndex,
+		FromBLSPubKey:      data.FromBLSPubKey,
+		ToExecutionAddress: data.ToExecutionAddress,
+	}
+	messageRoot, err := message.HashTreeRoot()
+	if err != nil {
+		log.Error().Err(err).Str("result", "failed").Msg("Failed to generate message root")
+		s.monitor.SignCompleted(started, "blstoexecutionchange", core.ResultFailed)
+		return core.ResultFailed, nil
+	}
+	signingRoot, err := generateSigningRoot(ctx, messageRoot[:], domain)
+	if err != nil {
+		log.Error().Err(err).Str("result", "failed").Msg("Failed to generate signing root")
+		s.monitor.SignCompleted(started, "blstoexecutionchange", core.ResultFailed)
+		return core.ResultFailed, nil
+	}
+
+	signature, err := signRoot(ctx, ruler.ActionSignBLSToExecutionChange, account, signingRoot[:])
+	if err != nil {
+		log.Error().Err(err).Str("result", "failed").Msg("Failed to sign")
+		s.monitor.SignCompleted(started, "blstoexecutionchange", core.ResultFailed)
+		return core.ResultFailed, nil
+	}
+
+	log.Trace().Str("result", "succeeded").Msg("Success")
+	s.monitor.SignCompleted(started, "blstoexecutionchange", core.ResultSucceeded)
+	return core.ResultSucceeded, signature
+}
+
+// blsToExecutionChangeDomain calculates the domain for a BLS to execution change.
+// Unlike other domains this always uses the genesis fork version, so that a
+// change remains valid regardless of the fork at which it is submitted.
+func (s *Service) blsToExecutionChangeDomain(genesisForkVersion []byte, genesisValidatorsRoot []byte) ([]byte, error) {
+	return s.domains.domain(domainBLSToExecutionChange, s.forkVersion(domainBLSToExecutionChange, genesisForkVersion), genesisValidatorsRoot)
+}
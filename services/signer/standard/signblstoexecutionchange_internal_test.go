@@ -0,0 +1,57 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBLSToExecutionChangeDomain(t *testing.T) {
+	// Mainnet genesis validators root.
+	genesisValidatorsRoot, err := hex.DecodeString("4b363db94e286120d76eb905340fdd4e54bfe9f06bf33ff6cf5ad27f511bfe95")
+	require.NoError(t, err)
+
+	domain, err := (&Service{}).blsToExecutionChangeDomain([]byte{0x00, 0x00, 0x00, 0x00}, genesisValidatorsRoot)
+	require.NoError(t, err)
+	require.Equal(t, "0a000000b5303f2ad2010d699a76c8e62350947421a3e4a979779642cfdb0f66", hex.EncodeToString(domain))
+}
+
+func TestBLSToExecutionChangeRoot(t *testing.T) {
+	pubKey := make([]byte, 48)
+	for i := range pubKey {
+		pubKey[i] = byte(i)
+	}
+	address := make([]byte, 20)
+	for i := range address {
+		address[i] = byte(100 + i)
+	}
+
+	root, err := (&blsToExecutionChange{
+		ValidatorIndex:     5,
+		FromBLSPubKey:      pubKey,
+		ToExecutionAddress: address,
+	}).HashTreeRoot()
+	require.NoError(t, err)
+	require.Equal(t, "b0ec41417ca5aae9c0ec038abcfcd302e33ca2510dd3e59da008a55659702fd6", hex.EncodeToString(root[:]))
+
+	_, err = (&blsToExecutionChange{
+		ValidatorIndex:     5,
+		FromBLSPubKey:      pubKey[1:],
+		ToExecutionAddress: address,
+	}).HashTreeRoot()
+	require.Error(t, err)
+}
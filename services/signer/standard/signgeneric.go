@@ -35,6 +35,11 @@ func (s *Service) SignGeneric(
 	core.Result,
 	[]byte,
 ) {
+	release, result := s.admit(ctx, "generic")
+	if result != core.ResultSucceeded {
+		return result, nil
+	}
+	defer release()
 	started := time.Now()
 
 	if credentials == nil {
@@ -50,18 +55,8 @@ func (s *Service) SignGeneric(
 	log.Trace().Msg("Request received")
 
 	// Check input.
-	if data == nil {
-		log.Warn().Str("result", "denied").Msg("Request empty")
-		s.monitor.SignCompleted(started, "generic", core.ResultDenied)
-		return core.ResultDenied, nil
-	}
-	if data.Data == nil {
-		log.Warn().Str("result", "denied").Msg("Request missing data")
-		s.monitor.SignCompleted(started, "generic", core.ResultDenied)
-		return core.ResultDenied, nil
-	}
-	if data.Domain == nil {
-		log.Warn().Str("result", "denied").Msg("Request missing domain")
+	if reason := validateSignData(data); reason != "" {
+		log.Warn().Str("result", "denied").Msg(reason)
 		s.monitor.SignCompleted(started, "generic", core.ResultDenied)
 		return core.ResultDenied, nil
 	}
@@ -83,8 +78,12 @@ func (s *Service) SignGeneric(
 			Data:        data,
 		},
 	}
-	results := s.ruler.RunRules(ctx, credentials, ruler.ActionSign, rulesData)
+	results := s.runRules(ctx, credentials, ruler.ActionSign, rulesData)
 	switch results[0] {
+	case rules.UNKNOWN:
+		log.Debug().Str("result", "failed").Msg("Unknown result from rules")
+		s.monitor.SignCompleted(started, "generic", core.ResultFailed)
+		return core.ResultFailed, nil
 	case rules.DENIED:
 		s.monitor.SignCompleted(started, "generic", core.ResultDenied)
 		log.Debug().Str("result", "denied").Msg("Denied by rules")
@@ -103,7 +102,7 @@ func (s *Service) SignGeneric(
 	}
 
 	// Sign it.
-	signature, err := signRoot(ctx, account, signingRoot[:])
+	signature, err := signRoot(ctx, ruler.ActionSign, account, signingRoot[:])
 	if err != nil {
 		log.Error().Err(err).Str("result", "failed").Msg("Failed to sign")
 		s.monitor.SignCompleted(started, "generic", core.ResultFailed)
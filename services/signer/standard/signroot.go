@@ -0,0 +1,107 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard
+
+import (
+	context "context"
+	"fmt"
+	"time"
+
+	"github.com/attestantio/dirk/core"
+	"github.com/attestantio/dirk/rules"
+	"github.com/attestantio/dirk/services/checker"
+	"github.com/attestantio/dirk/services/ruler"
+)
+
+// SignRoot signs a client-supplied signing root.  The root is signed as
+// supplied, without a domain being applied, so it is not subject to the
+// slashing protection of the typed signing operations.
+func (s *Service) SignRoot(
+	ctx context.Context,
+	credentials *checker.Credentials,
+	accountName string,
+	pubKey []byte,
+	data *rules.SignRootData,
+) (
+	core.Result,
+	[]byte,
+) {
+	release, result := s.admit(ctx, "root")
+	if result != core.ResultSucceeded {
+		return result, nil
+	}
+	defer release()
+	started := time.Now()
+
+	if credentials == nil {
+		log.Error().Msg("No credentials supplied")
+		return core.ResultFailed, nil
+	}
+
+	log := log.With().
+		Str("request_id", credentials.RequestID).
+		Str("action", "SignRoot").
+		Str("client", credentials.Client).
+		Logger()
+	log.Trace().Msg("Request received")
+
+	// Check input.
+	if reason := validateSignRootData(data); reason != "" {
+		log.Warn().Str("result", "denied").Msg(reason)
+		s.monitor.SignCompleted(started, "root", core.ResultDenied)
+		return core.ResultDenied, nil
+	}
+
+	wallet, account, checkRes := s.preCheck(ctx, credentials, accountName, pubKey, ruler.ActionSignRoot)
+	if checkRes != core.ResultSucceeded {
+		s.monitor.SignCompleted(started, "root", checkRes)
+		return checkRes, nil
+	}
+	accountName = fmt.Sprintf("%s/%s", wallet.Name(), account.Name())
+	log = log.With().Str("account", accountName).Logger()
+
+	// Confirm approval via rules.
+	rulesData := []*ruler.RulesData{
+		{
+			WalletName:  wallet.Name(),
+			AccountName: account.Name(),
+			PubKey:      account.PublicKey().Marshal(),
+			Data:        data,
+		},
+	}
+	results := s.runRules(ctx, credentials, ruler.ActionSignRoot, rulesData)
+	switch results[0] {
+	case rules.APPROVED:
+	case rules.DENIED:
+		log.Debug().Str("result", "denied").Msg("Denied by rules")
+		s.monitor.SignCompleted(started, "root", core.ResultDenied)
+		return core.ResultDenied, nil
+	default:
+		log.Error().Str("result", "failed").Stringer("rules_result", results[0]).Msg("Rules check failed")
+		s.monitor.SignCompleted(started, "root", core.ResultFailed)
+		return core.ResultFailed, nil
+	}
+
+	signature, err := signRoot(ctx, ruler.ActionSignRoot, account, data.SigningRoot)
+	if err != nil {
+		log.Error().Err(err).Str("result", "failed").Msg("Failed to sign")
+		s.monitor.SignCompleted(started, "root", core.ResultFailed)
+		return core.ResultFailed, nil
+	}
+
+	// Record the root, as there is no other record of what was signed.
+	log.Info().Str("signing_root", fmt.Sprintf("%#x", data.SigningRoot)).Str("result", "succeeded").Msg("Signed signing root")
+	s.monitor.SignCompleted(started, "root", core.ResultSucceeded)
+	return core.ResultSucceeded, signature
+}
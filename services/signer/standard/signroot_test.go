@@ -0,0 +1,168 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard_test
+
+import (
+	context "context"
+	"testing"
+
+	"github.com/attestantio/dirk/core"
+	"github.com/attestantio/dirk/rules"
+	mockrules "github.com/attestantio/dirk/rules/mock"
+	"github.com/attestantio/dirk/services/checker"
+	mockchecker "github.com/attestantio/dirk/services/checker/mock"
+	staticchecker "github.com/attestantio/dirk/services/checker/static"
+	memfetcher "github.com/attestantio/dirk/services/fetcher/mem"
+	syncmaplocker "github.com/attestantio/dirk/services/locker/syncmap"
+	"github.com/attestantio/dirk/services/ruler/golang"
+	standardsigner "github.com/attestantio/dirk/services/signer/standard"
+	localunlocker "github.com/attestantio/dirk/services/unlocker/local"
+	"github.com/attestantio/dirk/testing/accounts"
+	"github.com/stretchr/testify/require"
+	e2types "github.com/wealdtech/go-eth2-types/v2"
+	e2wtypes "github.com/wealdtech/go-eth2-wallet-types/v2"
+)
+
+func TestSignRoot(t *testing.T) {
+	ctx := context.Background()
+
+	store, err := accounts.Setup(ctx)
+	require.NoError(t, err)
+	lockerSvc, err := syncmaplocker.New(ctx)
+	require.NoError(t, err)
+	fetcherSvc, err := memfetcher.New(ctx,
+		memfetcher.WithStores([]e2wtypes.Store{store}))
+	require.NoError(t, err)
+	rulerSvc, err := golang.New(ctx,
+		golang.WithLocker(lockerSvc),
+		golang.WithRules(mockrules.New()))
+	require.NoError(t, err)
+	unlockerSvc, err := localunlocker.New(ctx,
+		localunlocker.WithAccountPassphrases([]string{"Account 1 passphrase"}))
+	require.NoError(t, err)
+	checkerSvc, err := mockchecker.New()
+	require.NoError(t, err)
+
+	signerSvc, err := standardsigner.New(ctx,
+		standardsigner.WithChecker(checkerSvc),
+		standardsigner.WithFetcher(fetcherSvc),
+		standardsigner.WithRuler(rulerSvc),
+		standardsigner.WithUnlocker(unlockerSvc),
+	)
+	require.NoError(t, err)
+
+	root := []byte{
+		0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f, 0x10,
+		0x11, 0x12, 0x13, 0x14, 0x15, 0x16, 0x17, 0x18, 0x19, 0x1a, 0x1b, 0x1c, 0x1d, 0x1e, 0x1f, 0x20,
+	}
+
+	tests := []struct {
+		name        string
+		credentials *checker.Credentials
+		accountName string
+		data        *rules.SignRootData
+		res         core.Result
+	}{
+		{
+			name: "Nil",
+			res:  core.ResultFailed,
+		},
+		{
+			name:        "DataMissing",
+			credentials: &checker.Credentials{Client: "client1"},
+			accountName: "Wallet 1/Account 1",
+			res:         core.ResultDenied,
+		},
+		{
+			name:        "SigningRootShort",
+			credentials: &checker.Credentials{Client: "client1"},
+			accountName: "Wallet 1/Account 1",
+			data:        &rules.SignRootData{SigningRoot: root[1:]},
+			res:         core.ResultDenied,
+		},
+		{
+			name:        "DeniedClient",
+			credentials: &checker.Credentials{Client: "Deny this client"},
+			accountName: "Wallet 1/Account 1",
+			data:        &rules.SignRootData{SigningRoot: root},
+			res:         core.ResultDenied,
+		},
+		{
+			name:        "Good",
+			credentials: &checker.Credentials{Client: "client1"},
+			accountName: "Wallet 1/Account 1",
+			data:        &rules.SignRootData{SigningRoot: root},
+			res:         core.ResultSucceeded,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			res, signature := signerSvc.SignRoot(ctx, test.credentials, test.accountName, nil, test.data)
+			require.Equal(t, test.res, res)
+			if res != core.ResultSucceeded {
+				require.Nil(t, signature)
+				return
+			}
+			// The root is signed as supplied, with no domain applied.
+			_, account, err := fetcherSvc.FetchAccount(ctx, test.accountName)
+			require.NoError(t, err)
+			sig, err := e2types.BLSSignatureFromBytes(signature)
+			require.NoError(t, err)
+			require.True(t, sig.Verify(root, account.PublicKey()))
+		})
+	}
+}
+
+func TestSignRootRequiresExplicitPermission(t *testing.T) {
+	ctx := context.Background()
+
+	store, err := accounts.Setup(ctx)
+	require.NoError(t, err)
+	lockerSvc, err := syncmaplocker.New(ctx)
+	require.NoError(t, err)
+	fetcherSvc, err := memfetcher.New(ctx,
+		memfetcher.WithStores([]e2wtypes.Store{store}))
+	require.NoError(t, err)
+	rulerSvc, err := golang.New(ctx,
+		golang.WithLocker(lockerSvc),
+		golang.WithRules(mockrules.New()))
+	require.NoError(t, err)
+	unlockerSvc, err := localunlocker.New(ctx,
+		localunlocker.WithAccountPassphrases([]string{"Account 1 passphrase"}))
+	require.NoError(t, err)
+	// A client with all operations is not permitted to sign roots.
+	checkerSvc, err := staticchecker.New(ctx,
+		staticchecker.WithPermissions(map[string][]*checker.Permissions{
+			"client1": {
+				{
+					Path:       ".*",
+					Operations: []string{"All"},
+				},
+			},
+		}),
+	)
+	require.NoError(t, err)
+	signerSvc, err := standardsigner.New(ctx,
+		standardsigner.WithChecker(checkerSvc),
+		standardsigner.WithFetcher(fetcherSvc),
+		standardsigner.WithRuler(rulerSvc),
+		standardsigner.WithUnlocker(unlockerSvc),
+	)
+	require.NoError(t, err)
+
+	res, signature := signerSvc.SignRoot(ctx, &checker.Credentials{Client: "client1"}, "Wallet 1/Account 1", nil, &rules.SignRootData{SigningRoot: make([]byte, 32)})
+	require.Equal(t, core.ResultDenied, res)
+	require.Nil(t, signature)
+}
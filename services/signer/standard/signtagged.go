@@ -0,0 +1,134 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard
+
+import (
+	context "context"
+	"fmt"
+	"time"
+
+	"github.com/attestantio/dirk/core"
+	"github.com/attestantio/dirk/rules"
+	"github.com/attestantio/dirk/services/checker"
+	"github.com/attestantio/dirk/services/ruler"
+	"github.com/rs/zerolog"
+)
+
+// SignTagged signs a tagged message.
+func (s *Service) SignTagged(
+	ctx context.Context,
+	credentials *checker.Credentials,
+	accountName string,
+	pubKey []byte,
+	data *rules.SignTaggedData,
+) (
+	core.Result,
+	[]byte,
+) {
+	release, result := s.admit(ctx, "tagged")
+	if result != core.ResultSucceeded {
+		return result, nil
+	}
+	defer release()
+	started := time.Now()
+
+	if credentials == nil {
+		log.Error().Msg("No credentials supplied")
+		return core.ResultFailed, nil
+	}
+
+	log := log.With().
+		Str("request_id", credentials.RequestID).
+		Str("action", "SignTagged").
+		Str("client", credentials.Client).
+		Logger()
+	log.Trace().Msg("Request received")
+
+	// Check input.
+	if reason := validateSignTaggedData(data); reason != "" {
+		log.Warn().Str("result", "denied").Msg(reason)
+		s.monitor.SignCompleted(started, "tagged", core.ResultDenied)
+		return core.ResultDenied, nil
+	}
+	log = log.With().Str("tag", data.Tag).Logger()
+
+	// The client must be permitted to sign messages with this tag.
+	if !s.messageTags[credentials.Client][data.Tag] {
+		log.Warn().Str("result", "denied").Msg("Message type tag not permitted for client")
+		s.monitor.SignCompleted(started, "tagged", core.ResultTagNotPermitted)
+		return core.ResultTagNotPermitted, nil
+	}
+
+	result, signature := s.signTagged(ctx, credentials, accountName, pubKey, data, log)
+	s.monitor.SignCompleted(started, "tagged", result)
+	s.monitor.TaggedSignCompleted(data.Tag, result)
+	if result == core.ResultSucceeded {
+		log.Trace().Str("result", "succeeded").Msg("Success")
+	}
+	return result, signature
+}
+
+// signTagged signs a tagged message whose tag has been permitted.
+func (s *Service) signTagged(
+	ctx context.Context,
+	credentials *checker.Credentials,
+	accountName string,
+	pubKey []byte,
+	data *rules.SignTaggedData,
+	log zerolog.Logger,
+) (
+	core.Result,
+	[]byte,
+) {
+	wallet, account, checkRes := s.preCheck(ctx, credentials, accountName, pubKey, ruler.ActionSignTagged)
+	if checkRes != core.ResultSucceeded {
+		return checkRes, nil
+	}
+	accountName = fmt.Sprintf("%s/%s", wallet.Name(), account.Name())
+	log = log.With().Str("account", accountName).Logger()
+
+	// Confirm approval via rules.
+	rulesData := []*ruler.RulesData{
+		{
+			WalletName:  wallet.Name(),
+			AccountName: account.Name(),
+			PubKey:      account.PublicKey().Marshal(),
+			Data:        data,
+		},
+	}
+	results := s.runRules(ctx, credentials, ruler.ActionSignTagged, rulesData)
+	switch results[0] {
+	case rules.APPROVED:
+	case rules.DENIED:
+		log.Debug().Str("result", "denied").Msg("Denied by rules")
+		return core.ResultDenied, nil
+	default:
+		log.Error().Str("result", "failed").Stringer("rules_result", results[0]).Msg("Rules check failed")
+		return core.ResultFailed, nil
+	}
+
+	signingRoot, err := generateSigningRoot(ctx, data.Data, data.Domain)
+	if err != nil {
+		log.Error().Err(err).Str("result", "failed").Msg("Failed to generate signing root")
+		return core.ResultFailed, nil
+	}
+
+	signature, err := signRoot(ctx, ruler.ActionSignTagged, account, signingRoot[:])
+	if err != nil {
+		log.Error().Err(err).Str("result", "failed").Msg("Failed to sign")
+		return core.ResultFailed, nil
+	}
+
+	return core.ResultSucceeded, signature
+}
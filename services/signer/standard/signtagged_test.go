@@ -0,0 +1,182 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard_test
+
+import (
+	context "context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/attestantio/dirk/core"
+	"github.com/attestantio/dirk/rules"
+	mockrules "github.com/attestantio/dirk/rules/mock"
+	"github.com/attestantio/dirk/services/checker"
+	mockchecker "github.com/attestantio/dirk/services/checker/mock"
+	memfetcher "github.com/attestantio/dirk/services/fetcher/mem"
+	syncmaplocker "github.com/attestantio/dirk/services/locker/syncmap"
+	"github.com/attestantio/dirk/services/ruler/golang"
+	standardsigner "github.com/attestantio/dirk/services/signer/standard"
+	localunlocker "github.com/attestantio/dirk/services/unlocker/local"
+	"github.com/attestantio/dirk/testing/accounts"
+	"github.com/stretchr/testify/require"
+	e2wtypes "github.com/wealdtech/go-eth2-wallet-types/v2"
+)
+
+// taggedMonitor records the results of tagged signing requests.
+type taggedMonitor struct {
+	mu      sync.Mutex
+	results map[string][]core.Result
+}
+
+func (m *taggedMonitor) SignCompleted(started time.Time, request string, result core.Result) {}
+
+func (m *taggedMonitor) SignQueueCompleted(started time.Time, request string) {}
+
+func (m *taggedMonitor) SignCapacity(inflight int, queued int) {}
+
+func (m *taggedMonitor) SignCoalesced(request string) {}
+
+func (m *taggedMonitor) TaggedSignCompleted(tag string, result core.Result) {
+	m.mu.Lock()
+	m.results[tag] = append(m.results[tag], result)
+	m.mu.Unlock()
+}
+
+func TestSignTagged(t *testing.T) {
+	ctx := context.Background()
+
+	store, err := accounts.Setup(ctx)
+	require.NoError(t, err)
+	lockerSvc, err := syncmaplocker.New(ctx)
+	require.NoError(t, err)
+	fetcherSvc, err := memfetcher.New(ctx,
+		memfetcher.WithStores([]e2wtypes.Store{store}))
+	require.NoError(t, err)
+	rulerSvc, err := golang.New(ctx,
+		golang.WithLocker(lockerSvc),
+		golang.WithRules(mockrules.New()))
+	require.NoError(t, err)
+	unlockerSvc, err := localunlocker.New(ctx,
+		localunlocker.WithAccountPassphrases([]string{"Account 1 passphrase"}))
+	require.NoError(t, err)
+	checkerSvc, err := mockchecker.New()
+	require.NoError(t, err)
+
+	_, err = standardsigner.New(ctx,
+		standardsigner.WithChecker(checkerSvc),
+		standardsigner.WithFetcher(fetcherSvc),
+		standardsigner.WithRuler(rulerSvc),
+		standardsigner.WithUnlocker(unlockerSvc),
+		standardsigner.WithMessageTags(map[string][]string{"client1": {""}}),
+	)
+	require.EqualError(t, err, `problem with parameters: empty message tag for client "client1"`)
+
+	monitor := &taggedMonitor{
+		results: make(map[string][]core.Result),
+	}
+	signerSvc, err := standardsigner.New(ctx,
+		standardsigner.WithMonitor(monitor),
+		standardsigner.WithChecker(checkerSvc),
+		standardsigner.WithFetcher(fetcherSvc),
+		standardsigner.WithRuler(rulerSvc),
+		standardsigner.WithUnlocker(unlockerSvc),
+		standardsigner.WithMessageTags(map[string][]string{
+			"client1":          {"test-message-v1"},
+			"Deny this client": {"test-message-v1"},
+		}),
+	)
+	require.NoError(t, err)
+
+	root := make([]byte, 32)
+	domain := make([]byte, 32)
+
+	tests := []struct {
+		name        string
+		credentials *checker.Credentials
+		accountName string
+		data        *rules.SignTaggedData
+		res         core.Result
+	}{
+		{
+			name: "Nil",
+			res:  core.ResultFailed,
+		},
+		{
+			name:        "DataMissing",
+			credentials: &checker.Credentials{Client: "client1"},
+			accountName: "Wallet 1/Account 1",
+			res:         core.ResultDenied,
+		},
+		{
+			name:        "TagMissing",
+			credentials: &checker.Credentials{Client: "client1"},
+			accountName: "Wallet 1/Account 1",
+			data:        &rules.SignTaggedData{Domain: domain, Data: root},
+			res:         core.ResultDenied,
+		},
+		{
+			name:        "DomainShort",
+			credentials: &checker.Credentials{Client: "client1"},
+			accountName: "Wallet 1/Account 1",
+			data:        &rules.SignTaggedData{Tag: "test-message-v1", Domain: domain[1:], Data: root},
+			res:         core.ResultDenied,
+		},
+		{
+			name:        "TagNotPermitted",
+			credentials: &checker.Credentials{Client: "client1"},
+			accountName: "Wallet 1/Account 1",
+			data:        &rules.SignTaggedData{Tag: "other-message-v1", Domain: domain, Data: root},
+			res:         core.ResultTagNotPermitted,
+		},
+		{
+			name:        "ClientWithoutTags",
+			credentials: &checker.Credentials{Client: "client2"},
+			accountName: "Wallet 1/Account 1",
+			data:        &rules.SignTaggedData{Tag: "test-message-v1", Domain: domain, Data: root},
+			res:         core.ResultTagNotPermitted,
+		},
+		{
+			name:        "DeniedClient",
+			credentials: &checker.Credentials{Client: "Deny this client"},
+			accountName: "Wallet 1/Account 1",
+			data:        &rules.SignTaggedData{Tag: "test-message-v1", Domain: domain, Data: root},
+			res:         core.ResultDenied,
+		},
+		{
+			name:        "Good",
+			credentials: &checker.Credentials{Client: "client1"},
+			accountName: "Wallet 1/Account 1",
+			data:        &rules.SignTaggedData{Tag: "test-message-v1", Domain: domain, Data: root},
+			res:         core.ResultSucceeded,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			res, signature := signerSvc.SignTagged(ctx, test.credentials, test.accountName, nil, test.data)
+			require.Equal(t, test.res, res)
+			if res == core.ResultSucceeded {
+				require.Len(t, signature, 96)
+			} else {
+				require.Nil(t, signature)
+			}
+		})
+	}
+
+	// Only permitted tags are recorded.
+	require.Equal(t, map[string][]core.Result{
+		"test-message-v1": {core.ResultDenied, core.ResultSucceeded},
+	}, monitor.results)
+}
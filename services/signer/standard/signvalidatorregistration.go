@@ -0,0 +1,176 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard
+
+import (
+	context "context"
+	"fmt"
+	"time"
+
+	"github.com/attestantio/dirk/core"
+	"github.com/attestantio/dirk/rules"
+	"github.com/attestantio/dirk/services/checker"
+	"github.com/attestantio/dirk/services/ruler"
+	ssz "github.com/ferranbt/fastssz"
+)
+
+// domainApplicationBuilder is the domain type for the builder API.
+var domainApplicationBuilder = []byte{0x00, 0x00, 0x00, 0x01}
+
+// validatorRegistration is the registration of a validator with the builder API.
+type validatorRegistration struct {
+	FeeRecipient []byte `ssz-size:"20"`
+	GasLimit     uint64
+	Timestamp    uint64
+	PubKey       []byte `ssz-size:"48"`
+}
+
+// HashTreeRoot ssz hashes the validatorRegistration object.
+func (v *validatorRegistration) HashTreeRoot() ([32]byte, error) {
+	return ssz.HashWithDefaultHasher(v)
+}
+
+// HashTreeRootWith ssz hashes the validatorRegistration object with a hasher.
+func (v *validatorRegistration) HashTreeRootWith(hh *ssz.Hasher) error {
+	indx := hh.Index()
+
+	if len(v.FeeRecipient) != 20 {
+		return ssz.ErrBytesLength
+	}
+	hh.PutBytes(v.FeeRecipient)
+	hh.PutUint64(v.GasLimit)
+	hh.PutUint64(v.Timestamp)
+	if len(v.PubKey) != 48 {
+		return ssz.ErrBytesLength
+	}
+	hh.PutBytes(v.PubKey)
+
+	hh.Merkleize(indx)
+	return nil
+}
+
+// SignValidatorRegistration signs a validator registration.
+func (s *Service) SignValidatorRegistration(
+	ctx context.Context,
+	credentials *checker.Credentials,
+	accountName string,
+	pubKey []byte,
+	data *rules.SignValidatorRegistrationData,
+) (
+	core.Result,
+	[]byte,
+) {
+	release, result := s.admit(ctx, "registration")
+	if result != core.ResultSucceeded {
+		return result, nil
+	}
+	defer release()
+	started := time.Now()
+
+	if credentials == nil {
+		log.Error().Msg("No credentials supplied")
+		return core.ResultFailed, nil
+	}
+
+	log := log.With().
+		Str("request_id", credentials.RequestID).
+		Str("action", "SignValidatorRegistration").
+		Str("client", credentials.Client).
+		Logger()
+	log.Trace().Msg("Request received")
+
+	// Check input.
+	if reason := validateSignValidatorRegistrationData(data); reason != "" {
+		log.Warn().Str("result", "denied").Msg(reason)
+		s.monitor.SignCompleted(started, "registration", core.ResultDenied)
+		return core.ResultDenied, nil
+	}
+
+	wallet, account, checkRes := s.preCheck(ctx, credentials, accountName, pubKey, ruler.ActionSignValidatorRegistration)
+	if checkRes != core.ResultSucceeded {
+		s.monitor.SignCompleted(started, "registration", checkRes)
+		return checkRes, nil
+	}
+	accountName = fmt.Sprintf("%s/%s", wallet.Name(), account.Name())
+	log = log.With().Str("account", accountName).Logger()
+
+	// The registration is for the validator, so uses the validator's public key.
+	data.PubKey = messagePublicKey(account)
+	domain, err := s.applicationBuilderDomain(data.GenesisForkVersion)
+	if err != nil {
+		log.Error().Err(err).Str("result", "failed").Msg("Failed to generate domain")
+		s.monitor.SignCompleted(started, "registration", core.ResultFailed)
+		return core.ResultFailed, nil
+	}
+	data.Domain = domain
+
+	// Confirm approval via rules.
+	rulesData := []*ruler.RulesData{
+		{
+			WalletName:  wallet.Name(),
+			AccountName: account.Name(),
+			PubKey:      account.PublicKey().Marshal(),
+			Data:        data,
+		},
+	}
+	results := s.runRules(ctx, credentials, ruler.ActionSignValidatorRegistration, rulesData)
+	switch results[0] {
+	case rules.APPROVED:
+	case rules.DENIED:
+		log.Debug().Str("result", "denied").Msg("Denied by rules")
+		s.monitor.SignCompleted(started, "registration", core.ResultDenied)
+		return core.ResultDenied, nil
+	default:
+		log.Error().Str("result", "failed").Stringer("rules_result", results[0]).Msg("Rules check failed")
+		s.monitor.SignCompleted(started, "registration", core.ResultFailed)
+		return core.ResultFailed, nil
+	}
+
+	message := &validatorRegistration{
+		FeeRecipient: data.FeeRecipient,
+		GasLimit:     data.GasLimit,
+		Timestamp:    data.Timestamp,
+		PubKey:       data.PubKey,
+	}
+	messageRoot, err := message.HashTreeRoot()
+	if err != nil {
+		log.Error().Err(err).Str("result", "failed").Msg("Failed to generate message root")
+		s.monitor.SignCompleted(started, "registration", core.ResultFailed)
+		return core.ResultFailed, nil
+	}
+	signingRoot, err := generateSigningRoot(ctx, messageRoot[:], domain)
+	if err != nil {
+		log.Error().Err(err).Str("result", "failed").Msg("Failed to generate signing root")
+		s.monitor.SignCompleted(started, "registration", core.ResultFailed)
+		return core.ResultFailed, nil
+	}
+
+	signature, err := signRoot(ctx, ruler.ActionSignValidatorRegistration, account, signingRoot[:])
+	if err != nil {
+		log.Error().Err(err).Str("result", "failed").Msg("Failed to sign")
+		s.monitor.SignCompleted(started, "registration", core.ResultFailed)
+		return core.ResultFailed, nil
+	}
+
+	log.Trace().Str("result", "succeeded").Msg("Success")
+	s.monitor.SignCompleted(started, "registration", core.ResultSucceeded)
+	return core.ResultSucceeded, signature
+}
+
+// applicationBuilderDomain calculates the domain for the builder API.
+// The builder API is independent of the chain, so the domain uses the
+// genesis fork version and an empty genesis validators root.
+func (s *Service) applicationBuilderDomain(genesisForkVersion []byte) ([]byte, error) {
+	return s.domains.domain(domainApplicationBuilder, s.forkVersion(domainApplicationBuilder, genesisForkVersion), nil)
+}
@@ -0,0 +1,55 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplicationBuilderDomain(t *testing.T) {
+	domain, err := (&Service{}).applicationBuilderDomain([]byte{0x00, 0x00, 0x00, 0x00})
+	require.NoError(t, err)
+	require.Equal(t, "00000001f5a5fd42d16a20302798ef6ed309979b43003d2320d9f0e8ea9831a9", hex.EncodeToString(domain))
+}
+
+func TestValidatorRegistrationRoot(t *testing.T) {
+	feeRecipient := make([]byte, 20)
+	for i := range feeRecipient {
+		feeRecipient[i] = byte(1 + i)
+	}
+	pubKey := make([]byte, 48)
+	for i := range pubKey {
+		pubKey[i] = byte(i)
+	}
+
+	root, err := (&validatorRegistration{
+		FeeRecipient: feeRecipient,
+		GasLimit:     30000000,
+		Timestamp:    1700000000,
+		PubKey:       pubKey,
+	}).HashTreeRoot()
+	require.NoError(t, err)
+	require.Equal(t, "24e3e41ad6e2fbbd4b52bd8344ec502796b7cead832b5d36949f7dd2baa6701d", hex.EncodeToString(root[:]))
+
+	_, err = (&validatorRegistration{
+		FeeRecipient: feeRecipient[1:],
+		GasLimit:     30000000,
+		Timestamp:    1700000000,
+		PubKey:       pubKey,
+	}).HashTreeRoot()
+	require.Error(t, err)
+}
@@ -0,0 +1,196 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard_test
+
+import (
+	context "context"
+	"testing"
+
+	"github.com/attestantio/dirk/core"
+	"github.com/attestantio/dirk/rules"
+	mockrules "github.com/attestantio/dirk/rules/mock"
+	"github.com/attestantio/dirk/services/checker"
+	mockchecker "github.com/attestantio/dirk/services/checker/mock"
+	memfetcher "github.com/attestantio/dirk/services/fetcher/mem"
+	syncmaplocker "github.com/attestantio/dirk/services/locker/syncmap"
+	"github.com/attestantio/dirk/services/ruler/golang"
+	standardsigner "github.com/attestantio/dirk/services/signer/standard"
+	localunlocker "github.com/attestantio/dirk/services/unlocker/local"
+	"github.com/attestantio/dirk/testing/accounts"
+	"github.com/stretchr/testify/require"
+	e2wtypes "github.com/wealdtech/go-eth2-wallet-types/v2"
+)
+
+func TestSignValidatorRegistration(t *testing.T) {
+	ctx := context.Background()
+
+	store, err := accounts.Setup(ctx)
+	require.NoError(t, err)
+	lockerSvc, err := syncmaplocker.New(ctx)
+	require.NoError(t, err)
+	fetcherSvc, err := memfetcher.New(ctx,
+		memfetcher.WithStores([]e2wtypes.Store{store}))
+	require.NoError(t, err)
+	unlockerSvc, err := localunlocker.New(ctx,
+		localunlocker.WithAccountPassphrases([]string{"Account 1 passphrase"}))
+	require.NoError(t, err)
+	checkerSvc, err := mockchecker.New()
+	require.NoError(t, err)
+
+	signers := make(map[string]*standardsigner.Service)
+	for name, rulesSvc := range map[string]rules.Service{
+		"approving": mockrules.New(),
+		"denying":   mockrules.NewDenying(),
+	} {
+		rulerSvc, err := golang.New(ctx,
+			golang.WithLocker(lockerSvc),
+			golang.WithRules(rulesSvc))
+		require.NoError(t, err)
+		signers[name], err = standardsigner.New(ctx,
+			standardsigner.WithChecker(checkerSvc),
+			standardsigner.WithFetcher(fetcherSvc),
+			standardsigner.WithRuler(rulerSvc),
+			standardsigner.WithUnlocker(unlockerSvc),
+		)
+		require.NoError(t, err)
+	}
+
+	feeRecipient := []byte{
+		0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a,
+		0x0b, 0x0c, 0x0d, 0x0e, 0x0f, 0x10, 0x11, 0x12, 0x13, 0x14,
+	}
+	forkVersion := []byte{0x00, 0x00, 0x00, 0x00}
+
+	tests := []struct {
+		name        string
+		signer      string
+		credentials *checker.Credentials
+		accountName string
+		data        *rules.SignValidatorRegistrationData
+		res         core.Result
+	}{
+		{
+			name:   "Nil",
+			signer: "approving",
+			res:    core.ResultFailed,
+		},
+		{
+			name:        "DataMissing",
+			signer:      "approving",
+			credentials: &checker.Credentials{Client: "client1"},
+			accountName: "Wallet 1/Account 1",
+			res:         core.ResultDenied,
+		},
+		{
+			name:        "FeeRecipientShort",
+			signer:      "approving",
+			credentials: &checker.Credentials{Client: "client1"},
+			accountName: "Wallet 1/Account 1",
+			data: &rules.SignValidatorRegistrationData{
+				FeeRecipient:       feeRecipient[1:],
+				GasLimit:           30000000,
+				Timestamp:          1700000000,
+				GenesisForkVersion: forkVersion,
+			},
+			res: core.ResultDenied,
+		},
+		{
+			name:        "GasLimitZero",
+			signer:      "approving",
+			credentials: &checker.Credentials{Client: "client1"},
+			accountName: "Wallet 1/Account 1",
+			data: &rules.SignValidatorRegistrationData{
+				FeeRecipient:       feeRecipient,
+				Timestamp:          1700000000,
+				GenesisForkVersion: forkVersion,
+			},
+			res: core.ResultDenied,
+		},
+		{
+			name:        "TimestampZero",
+			signer:      "approving",
+			credentials: &checker.Credentials{Client: "client1"},
+			accountName: "Wallet 1/Account 1",
+			data: &rules.SignValidatorRegistrationData{
+				FeeRecipient:       feeRecipient,
+				GasLimit:           30000000,
+				GenesisForkVersion: forkVersion,
+			},
+			res: core.ResultDenied,
+		},
+		{
+			name:        "ForkVersionMissing",
+			signer:      "approving",
+			credentials: &checker.Credentials{Client: "client1"},
+			accountName: "Wallet 1/Account 1",
+			data: &rules.SignValidatorRegistrationData{
+				FeeRecipient: feeRecipient,
+				GasLimit:     30000000,
+				Timestamp:    1700000000,
+			},
+			res: core.ResultDenied,
+		},
+		{
+			name:        "DeniedClient",
+			signer:      "approving",
+			credentials: &checker.Credentials{Client: "Deny this client"},
+			accountName: "Wallet 1/Account 1",
+			data: &rules.SignValidatorRegistrationData{
+				FeeRecipient:       feeRecipient,
+				GasLimit:           30000000,
+				Timestamp:          1700000000,
+				GenesisForkVersion: forkVersion,
+			},
+			res: core.ResultDenied,
+		},
+		{
+			name:        "DeniedByRules",
+			signer:      "denying",
+			credentials: &checker.Credentials{Client: "client1"},
+			accountName: "Wallet 1/Account 1",
+			data: &rules.SignValidatorRegistrationData{
+				FeeRecipient:       feeRecipient,
+				GasLimit:           30000000,
+				Timestamp:          1700000000,
+				GenesisForkVersion: forkVersion,
+			},
+			res: core.ResultDenied,
+		},
+		{
+			name:        "Good",
+			signer:      "approving",
+			credentials: &checker.Credentials{Client: "client1"},
+			accountName: "Wallet 1/Account 1",
+			data: &rules.SignValidatorRegistrationData{
+				FeeRecipient:       feeRecipient,
+				GasLimit:           30000000,
+				Timestamp:          1700000000,
+				GenesisForkVersion: forkVersion,
+			},
+			res: core.ResultSucceeded,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			res, signature := signers[test.signer].SignValidatorRegistration(ctx, test.credentials, test.accountName, nil, test.data)
+			require.Equal(t, test.res, res)
+			if res == core.ResultSucceeded {
+				require.Len(t, signature, 96)
+			} else {
+				require.Nil(t, signature)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard
+
+import (
+	context "context"
+	"strings"
+
+	"github.com/attestantio/dirk/core"
+	"github.com/attestantio/dirk/rules"
+	"github.com/attestantio/dirk/services/checker"
+	"github.com/attestantio/dirk/services/ruler"
+	"github.com/opentracing/opentracing-go"
+)
+
+// startStageSpan starts a span for a stage of the signing pipeline.  Spans are
+// only created if a tracer has been configured, to avoid their overhead when
+// tracing is off.  The returned function finishes the span, tagging it with
+// the outcome of the stage.
+func startStageSpan(ctx context.Context, stage string, action string) (context.Context, func(outcome string)) {
+	if !opentracing.IsGlobalTracerRegistered() {
+		return ctx, func(string) {}
+	}
+	span, ctx := opentracing.StartSpanFromContext(ctx, "services.signer."+stage)
+	span.SetTag("operation", action)
+	return ctx, func(outcome string) {
+		span.SetTag("outcome", outcome)
+		span.Finish()
+	}
+}
+
+// resultOutcome provides the span outcome for a result.
+func resultOutcome(result core.Result) string {
+	return strings.ToLower(result.String())
+}
+
+// runRules runs the rules for the given data within a span.
+func (s *Service) runRules(ctx context.Context, credentials *checker.Credentials, action string, rulesData []*ruler.RulesData) []rules.Result {
+	ctx, finish := startStageSpan(ctx, "runRules", action)
+	results := s.ruler.RunRules(ctx, credentials, action, rulesData)
+	outcome := strings.ToLower(rules.APPROVED.String())
+	for i := range results {
+		if results[i] != rules.APPROVED {
+			// Report the first result that was not an approval.
+			outcome = strings.ToLower(results[i].String())
+			break
+		}
+	}
+	finish(outcome)
+	return results
+}
@@ -0,0 +1,95 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard
+
+import (
+	context "context"
+	"testing"
+
+	"github.com/attestantio/dirk/core"
+	"github.com/attestantio/dirk/rules"
+	"github.com/attestantio/dirk/services/checker"
+	"github.com/attestantio/dirk/services/ruler"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/mocktracer"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigningSpans(t *testing.T) {
+	ctx := context.Background()
+	signerSvc, _, _, err := setupSignerService(ctx)
+	require.NoError(t, err)
+
+	// No spans are created without a configured tracer.
+	require.False(t, opentracing.IsGlobalTracerRegistered())
+	stageCtx, finish := startStageSpan(ctx, "test", ruler.ActionSign)
+	finish("succeeded")
+	require.Equal(t, ctx, stageCtx)
+	require.Nil(t, opentracing.SpanFromContext(stageCtx))
+
+	tracer := mocktracer.New()
+	opentracing.SetGlobalTracer(tracer)
+	defer opentracing.SetGlobalTracer(opentracing.NoopTracer{})
+
+	result, _ := signerSvc.SignGeneric(ctx,
+		&checker.Credentials{Client: "client1"},
+		"Test wallet/Test account 1",
+		nil,
+		&rules.SignData{
+			Data:   make([]byte, 32),
+			Domain: make([]byte, 32),
+		},
+	)
+	require.Equal(t, core.ResultSucceeded, result)
+
+	outcomes := make(map[string]string)
+	for _, span := range tracer.FinishedSpans() {
+		if span.Tag("operation") == nil {
+			// Not a signing stage span.
+			continue
+		}
+		require.Equal(t, ruler.ActionSign, span.Tag("operation"))
+		outcomes[span.OperationName] = span.Tag("outcome").(string)
+	}
+	require.Equal(t, map[string]string{
+		"services.signer.fetchAccount":  "succeeded",
+		"services.signer.checkAccess":   "succeeded",
+		"services.signer.accountUnlock": "succeeded",
+		"services.signer.runRules":      "approved",
+		"services.signer.sign":          "succeeded",
+	}, outcomes)
+
+	// A denied request reports the stage at which it was denied.
+	tracer.Reset()
+	result, _ = signerSvc.SignGeneric(ctx,
+		&checker.Credentials{Client: "Deny this client"},
+		"Test wallet/Test account 1",
+		nil,
+		&rules.SignData{
+			Data:   make([]byte, 32),
+			Domain: make([]byte, 32),
+		},
+	)
+	require.Equal(t, core.ResultDenied, result)
+	outcomes = make(map[string]string)
+	for _, span := range tracer.FinishedSpans() {
+		if span.Tag("operation") != nil {
+			outcomes[span.OperationName] = span.Tag("outcome").(string)
+		}
+	}
+	require.Equal(t, map[string]string{
+		"services.signer.fetchAccount": "succeeded",
+		"services.signer.checkAccess":  "denied",
+	}, outcomes)
+}
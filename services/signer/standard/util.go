@@ -17,6 +17,7 @@ import (
 	context "context"
 	"errors"
 
+	"github.com/attestantio/dirk/core"
 	e2wtypes "github.com/wealdtech/go-eth2-wallet-types/v2"
 )
 
@@ -29,14 +30,18 @@ func generateSigningRoot(ctx context.Context, root []byte, domain []byte) ([32]b
 	return signingData.HashTreeRoot()
 }
 
-func signRoot(ctx context.Context, account e2wtypes.Account, root []byte) ([]byte, error) {
+func signRoot(ctx context.Context, action string, account e2wtypes.Account, root []byte) ([]byte, error) {
+	ctx, finish := startStageSpan(ctx, "sign", action)
 	signer, isSigner := account.(e2wtypes.AccountSigner)
 	if !isSigner {
+		finish(resultOutcome(core.ResultFailed))
 		return nil, errors.New("not a signer")
 	}
 	signature, err := signer.Sign(ctx, root)
 	if err != nil {
+		finish(resultOutcome(core.ResultFailed))
 		return nil, err
 	}
+	finish(resultOutcome(core.ResultSucceeded))
 	return signature.Marshal(), nil
 }
@@ -0,0 +1,161 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standard
+
+import (
+	"bytes"
+
+	"github.com/attestantio/dirk/rules"
+)
+
+// minDepositAmount is the minimum amount of a deposit, in Gwei.
+const minDepositAmount = 1000000000
+
+// validateSignData validates the data for a generic signing request,
+// returning the reason it is invalid or an empty string if it is valid.
+func validateSignData(data *rules.SignData) string {
+	switch {
+	case data == nil:
+		return "Request empty"
+	case data.Data == nil:
+		return "Request missing data"
+	case data.Domain == nil:
+		return "Request missing domain"
+	}
+	return ""
+}
+
+// validateSignTaggedData validates the data for a tagged signing request,
+// returning the reason it is invalid or an empty string if it is valid.
+func validateSignTaggedData(data *rules.SignTaggedData) string {
+	switch {
+	case data == nil:
+		return "Request empty"
+	case data.Tag == "":
+		return "Request missing tag"
+	case len(data.Data) != 32:
+		return "Request data must be 32 bytes"
+	case len(data.Domain) != 32:
+		return "Request domain must be 32 bytes"
+	}
+	return ""
+}
+
+// validateSignRootData validates the data for a root signing request,
+// returning the reason it is invalid or an empty string if it is valid.
+func validateSignRootData(data *rules.SignRootData) string {
+	switch {
+	case data == nil:
+		return "Request empty"
+	case len(data.SigningRoot) != 32:
+		return "Request signing root must be 32 bytes"
+	}
+	return ""
+}
+
+// validateSignBeaconAttestationData validates the data for a beacon attestation signing request,
+// returning the reason it is invalid or an empty string if it is valid.
+func validateSignBeaconAttestationData(data *rules.SignBeaconAttestationData) string {
+	switch {
+	case data == nil:
+		return "Request missing data"
+	case data.BeaconBlockRoot == nil:
+		return "Request missing beacon block root"
+	case data.Domain == nil:
+		return "Request missing domain"
+	case data.Source == nil:
+		return "Request missing source"
+	case data.Source.Root == nil:
+		return "Request missing source root"
+	case data.Target == nil:
+		return "Request missing target"
+	case data.Target.Root == nil:
+		return "Request missing target root"
+	}
+	return ""
+}
+
+// validateSignBeaconProposalData validates the data for a beacon proposal signing request,
+// returning the reason it is invalid or an empty string if it is valid.
+func validateSignBeaconProposalData(data *rules.SignBeaconProposalData) string {
+	switch {
+	case data == nil:
+		return "Request missing data"
+	case data.Domain == nil:
+		return "Request missing domain"
+	case data.ParentRoot == nil:
+		return "Request missing parent root"
+	case data.BodyRoot == nil:
+		return "Request missing body root"
+	case data.StateRoot == nil:
+		return "Request missing state root"
+	}
+	return ""
+}
+
+// validateGenerateDepositData validates the data for a deposit data request,
+// returning the reason it is invalid or an empty string if it is valid.
+func validateGenerateDepositData(data *rules.GenerateDepositData) string {
+	switch {
+	case data == nil:
+		return "Request missing data"
+	case len(data.WithdrawalCredentials) != 32:
+		return "Withdrawal credentials must be 32 bytes"
+	case data.WithdrawalCredentials[0] != 0x00 && data.WithdrawalCredentials[0] != 0x01:
+		return "Withdrawal credentials have unknown prefix"
+	case data.WithdrawalCredentials[0] == 0x01 && !bytes.Equal(data.WithdrawalCredentials[1:12], make([]byte, 11)):
+		return "Execution address withdrawal credentials must be padded with zeros"
+	case data.Amount < minDepositAmount:
+		return "Deposit amount too low"
+	case len(data.ForkVersion) != 4:
+		return "Fork version must be 4 bytes"
+	}
+	return ""
+}
+
+// validateSignBLSToExecutionChangeData validates the data for a BLS to execution change signing request,
+// returning the reason it is invalid or an empty string if it is valid.
+func validateSignBLSToExecutionChangeData(data *rules.SignBLSToExecutionChangeData) string {
+	switch {
+	case data == nil:
+		return "Request missing data"
+	case len(data.ToExecutionAddress) != 20:
+		return "Execution address must be 20 bytes"
+	case bytes.Equal(data.ToExecutionAddress, make([]byte, 20)):
+		return "Execution address cannot be zero"
+	case len(data.GenesisForkVersion) != 4:
+		return "Genesis fork version must be 4 bytes"
+	case len(data.GenesisValidatorsRoot) != 32:
+		return "Genesis validators root must be 32 bytes"
+	}
+	return ""
+}
+
+// validateSignValidatorRegistrationData validates the data for a validator registration signing request,
+// returning the reason it is invalid or an empty string if it is valid.
+func validateSignValidatorRegistrationData(data *rules.SignValidatorRegistrationData) string {
+	switch {
+	case data == nil:
+		return "Request missing data"
+	case len(data.FeeRecipient) != 20:
+		return "Fee recipient must be 20 bytes"
+	case data.GasLimit == 0:
+		return "Gas limit cannot be zero"
+	case data.Timestamp == 0:
+		return "Timestamp cannot be zero"
+	case len(data.GenesisForkVersion) != 4:
+		return "Genesis fork version must be 4 bytes"
+	}
+	return ""
+}
@@ -0,0 +1,43 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tracing provides helpers for carrying traces between Dirk instances.
+package tracing
+
+import (
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// MetadataCarrier allows span contexts to be injected in to and extracted from
+// gRPC metadata, using the opentracing HTTP headers format.
+type MetadataCarrier metadata.MD
+
+// Set sets a key in the metadata.
+func (c MetadataCarrier) Set(key string, val string) {
+	key = strings.ToLower(key)
+	c[key] = append(c[key], val)
+}
+
+// ForeachKey calls the handler for each key and value in the metadata.
+func (c MetadataCarrier) ForeachKey(handler func(key string, val string) error) error {
+	for key, vals := range c {
+		for _, val := range vals {
+			if err := handler(key, val); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,395 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlp
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// exportMethod is the OTLP trace export method.
+const exportMethod = "/opentelemetry.proto.collector.trace.v1.TraceService/Export"
+
+// exporter sends spans to the collector.  Messages are encoded directly in
+// the OTLP protobuf wire format.
+type exporter struct {
+	conn          *grpc.ClientConn
+	headers       metadata.MD
+	resource      []byte
+	exportTimeout time.Duration
+}
+
+func newExporter(ctx context.Context, parameters *parameters) (*exporter, error) {
+	dialOpts := []grpc.DialOption{}
+	if parameters.insecure {
+		dialOpts = append(dialOpts, grpc.WithInsecure())
+	} else {
+		dialOpts = append(dialOpts, grpc.WithTransportCredentials(credentials.NewTLS(parameters.tlsConfig)))
+	}
+	// The connection is established in the background, so an unavailable
+	// collector does not prevent startup.
+	conn, err := grpc.DialContext(ctx, parameters.address, dialOpts...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to dial collector")
+	}
+
+	attributes := map[string]interface{}{
+		"service.name": parameters.serviceName,
+	}
+	for k, v := range parameters.resource {
+		if k != "service.name" {
+			attributes[k] = v
+		}
+	}
+	resource := []byte{}
+	for _, kv := range encodeAttributes(attributes) {
+		resource = protowire.AppendTag(resource, 1, protowire.BytesType)
+		resource = protowire.AppendBytes(resource, kv)
+	}
+
+	return &exporter{
+		conn:          conn,
+		headers:       metadata.New(parameters.headers),
+		resource:      resource,
+		exportTimeout: parameters.exportTimeout,
+	}, nil
+}
+
+// export sends a batch of spans to the collector.
+func (e *exporter) export(ctx context.Context, spans []*span) error {
+	ctx, cancel := context.WithTimeout(ctx, e.exportTimeout)
+	defer cancel()
+	if len(e.headers) > 0 {
+		ctx = metadata.NewOutgoingContext(ctx, e.headers)
+	}
+
+	req := encodeRequest(e.resource, spans)
+	var resp []byte
+	if err := e.conn.Invoke(ctx, exportMethod, req, &resp, grpc.ForceCodec(rawCodec{})); err != nil {
+		return err
+	}
+	if rejected, message := decodePartialSuccess(resp); rejected > 0 {
+		return fmt.Errorf("collector rejected %d spans: %s", rejected, message)
+	}
+	return nil
+}
+
+func (e *exporter) close() error {
+	return e.conn.Close()
+}
+
+// rawCodec passes pre-encoded protobuf messages through gRPC.
+type rawCodec struct{}
+
+// Marshal returns the encoded message.
+func (rawCodec) Marshal(v interface{}) ([]byte, error) {
+	data, isBytes := v.([]byte)
+	if !isBytes {
+		return nil, fmt.Errorf("unsupported message type %T", v)
+	}
+	return data, nil
+}
+
+// Unmarshal stores the encoded message.
+func (rawCodec) Unmarshal(data []byte, v interface{}) error {
+	dst, isBytes := v.(*[]byte)
+	if !isBytes {
+		return fmt.Errorf("unsupported message type %T", v)
+	}
+	*dst = append((*dst)[:0], data...)
+	return nil
+}
+
+// Name returns the content subtype, which must be proto for collectors to accept the message.
+func (rawCodec) Name() string {
+	return "proto"
+}
+
+// encodeRequest encodes an ExportTraceServiceRequest holding the spans.
+func encodeRequest(resource []byte, spans []*span) []byte {
+	scope := []byte{}
+	scope = protowire.AppendTag(scope, 1, protowire.BytesType)
+	scope = protowire.AppendString(scope, "github.com/attestantio/dirk")
+
+	scopeSpans := []byte{}
+	scopeSpans = protowire.AppendTag(scopeSpans, 1, protowire.BytesType)
+	scopeSpans = protowire.AppendBytes(scopeSpans, scope)
+	for _, span := range spans {
+		scopeSpans = protowire.AppendTag(scopeSpans, 2, protowire.BytesType)
+		scopeSpans = protowire.AppendBytes(scopeSpans, encodeSpan(span))
+	}
+
+	resourceSpans := []byte{}
+	resourceSpans = protowire.AppendTag(resourceSpans, 1, protowire.BytesType)
+	resourceSpans = protowire.AppendBytes(resourceSpans, resource)
+	resourceSpans = protowire.AppendTag(resourceSpans, 2, protowire.BytesType)
+	resourceSpans = protowire.AppendBytes(resourceSpans, scopeSpans)
+
+	req := []byte{}
+	req = protowire.AppendTag(req, 1, protowire.BytesType)
+	req = protowire.AppendBytes(req, resourceSpans)
+	return req
+}
+
+// Span kinds.
+const (
+	spanKindInternal = 1
+	spanKindServer   = 2
+	spanKindClient   = 3
+	spanKindProducer = 4
+	spanKindConsumer = 5
+)
+
+// Status codes.
+const (
+	statusCodeError = 2
+)
+
+// encodeSpan encodes a single span.
+func encodeSpan(s *span) []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res := []byte{}
+	res = protowire.AppendTag(res, 1, protowire.BytesType)
+	res = protowire.AppendBytes(res, s.context.traceID[:])
+	res = protowire.AppendTag(res, 2, protowire.BytesType)
+	res = protowire.AppendBytes(res, s.context.spanID[:])
+	if s.hasParent {
+		res = protowire.AppendTag(res, 4, protowire.BytesType)
+		res = protowire.AppendBytes(res, s.parentSpanID[:])
+	}
+	res = protowire.AppendTag(res, 5, protowire.BytesType)
+	res = protowire.AppendString(res, s.name)
+	res = protowire.AppendTag(res, 6, protowire.VarintType)
+	res = protowire.AppendVarint(res, uint64(spanKind(s.tags)))
+	res = protowire.AppendTag(res, 7, protowire.Fixed64Type)
+	res = protowire.AppendFixed64(res, uint64(s.start.UnixNano()))
+	res = protowire.AppendTag(res, 8, protowire.Fixed64Type)
+	res = protowire.AppendFixed64(res, uint64(s.end.UnixNano()))
+	for _, kv := range encodeAttributes(s.tags) {
+		res = protowire.AppendTag(res, 9, protowire.BytesType)
+		res = protowire.AppendBytes(res, kv)
+	}
+	for _, event := range s.events {
+		res = protowire.AppendTag(res, 11, protowire.BytesType)
+		res = protowire.AppendBytes(res, encodeEvent(event, s.end))
+	}
+	if s.isError() {
+		status := []byte{}
+		status = protowire.AppendTag(status, 3, protowire.VarintType)
+		status = protowire.AppendVarint(status, statusCodeError)
+		res = protowire.AppendTag(res, 15, protowire.BytesType)
+		res = protowire.AppendBytes(res, status)
+	}
+	return res
+}
+
+// spanKind returns the OTLP span kind from the opentracing span.kind tag.
+func spanKind(tags map[string]interface{}) int {
+	kind, _ := tags[string(ext.SpanKind)].(ext.SpanKindEnum)
+	if kind == "" {
+		if str, isString := tags[string(ext.SpanKind)].(string); isString {
+			kind = ext.SpanKindEnum(str)
+		}
+	}
+	switch kind {
+	case ext.SpanKindRPCServerEnum:
+		return spanKindServer
+	case ext.SpanKindRPCClientEnum:
+		return spanKindClient
+	case ext.SpanKindProducerEnum:
+		return spanKindProducer
+	case ext.SpanKindConsumerEnum:
+		return spanKindConsumer
+	default:
+		return spanKindInternal
+	}
+}
+
+// encodeEvent encodes a span event.  The event name is taken from the
+// "event" field if present.  Events without a timestamp use the fallback.
+func encodeEvent(e *event, fallback time.Time) []byte {
+	timestamp := e.timestamp.UnixNano()
+	if e.timestamp.IsZero() {
+		timestamp = fallback.UnixNano()
+	}
+	name := "log"
+	attributes := make(map[string]interface{}, len(e.fields))
+	for _, field := range e.fields {
+		if field.Key() == "event" {
+			name = fmt.Sprintf("%v", field.Value())
+			continue
+		}
+		attributes[field.Key()] = field.Value()
+	}
+
+	res := []byte{}
+	res = protowire.AppendTag(res, 1, protowire.Fixed64Type)
+	res = protowire.AppendFixed64(res, uint64(timestamp))
+	res = protowire.AppendTag(res, 2, protowire.BytesType)
+	res = protowire.AppendString(res, name)
+	for _, kv := range encodeAttributes(attributes) {
+		res = protowire.AppendTag(res, 3, protowire.BytesType)
+		res = protowire.AppendBytes(res, kv)
+	}
+	return res
+}
+
+// encodeAttributes encodes attributes as KeyValue messages, sorted by key.
+func encodeAttributes(attributes map[string]interface{}) [][]byte {
+	keys := make([]string, 0, len(attributes))
+	for k := range attributes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	res := make([][]byte, len(keys))
+	for i, k := range keys {
+		kv := []byte{}
+		kv = protowire.AppendTag(kv, 1, protowire.BytesType)
+		kv = protowire.AppendString(kv, k)
+		kv = protowire.AppendTag(kv, 2, protowire.BytesType)
+		kv = protowire.AppendBytes(kv, encodeValue(attributes[k]))
+		res[i] = kv
+	}
+	return res
+}
+
+// encodeValue encodes an AnyValue message.
+func encodeValue(value interface{}) []byte {
+	res := []byte{}
+	switch v := value.(type) {
+	case bool:
+		res = protowire.AppendTag(res, 2, protowire.VarintType)
+		res = protowire.AppendVarint(res, protowire.EncodeBool(v))
+	case int:
+		res = appendInt(res, int64(v))
+	case int8:
+		res = appendInt(res, int64(v))
+	case int16:
+		res = appendInt(res, int64(v))
+	case int32:
+		res = appendInt(res, int64(v))
+	case int64:
+		res = appendInt(res, v)
+	case uint:
+		res = appendInt(res, int64(v))
+	case uint8:
+		res = appendInt(res, int64(v))
+	case uint16:
+		res = appendInt(res, int64(v))
+	case uint32:
+		res = appendInt(res, int64(v))
+	case uint64:
+		res = appendInt(res, int64(v))
+	case float32:
+		res = appendDouble(res, float64(v))
+	case float64:
+		res = appendDouble(res, v)
+	case []byte:
+		res = protowire.AppendTag(res, 7, protowire.BytesType)
+		res = protowire.AppendBytes(res, v)
+	default:
+		res = protowire.AppendTag(res, 1, protowire.BytesType)
+		res = protowire.AppendString(res, fmt.Sprintf("%v", v))
+	}
+	return res
+}
+
+func appendInt(b []byte, v int64) []byte {
+	b = protowire.AppendTag(b, 3, protowire.VarintType)
+	return protowire.AppendVarint(b, uint64(v))
+}
+
+func appendDouble(b []byte, v float64) []byte {
+	b = protowire.AppendTag(b, 4, protowire.Fixed64Type)
+	return protowire.AppendFixed64(b, math.Float64bits(v))
+}
+
+// decodePartialSuccess decodes the partial success field of an
+// ExportTraceServiceResponse, returning the number of rejected spans and the
+// error message.
+func decodePartialSuccess(data []byte) (int64, string) {
+	partialSuccess := findField(data, 1)
+	if partialSuccess == nil {
+		return 0, ""
+	}
+	var rejected int64
+	var message string
+	for len(partialSuccess) > 0 {
+		num, typ, n := protowire.ConsumeTag(partialSuccess)
+		if n < 0 {
+			break
+		}
+		partialSuccess = partialSuccess[n:]
+		switch {
+		case num == 1 && typ == protowire.VarintType:
+			v, n := protowire.ConsumeVarint(partialSuccess)
+			if n < 0 {
+				return rejected, message
+			}
+			rejected = int64(v)
+			partialSuccess = partialSuccess[n:]
+		case num == 2 && typ == protowire.BytesType:
+			v, n := protowire.ConsumeString(partialSuccess)
+			if n < 0 {
+				return rejected, message
+			}
+			message = v
+			partialSuccess = partialSuccess[n:]
+		default:
+			n := protowire.ConsumeFieldValue(num, typ, partialSuccess)
+			if n < 0 {
+				return rejected, message
+			}
+			partialSuccess = partialSuccess[n:]
+		}
+	}
+	return rejected, message
+}
+
+// findField returns the contents of the first length-delimited field with the given number.
+func findField(data []byte, field protowire.Number) []byte {
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return nil
+		}
+		data = data[n:]
+		if num == field && typ == protowire.BytesType {
+			v, n := protowire.ConsumeBytes(data)
+			if n < 0 {
+				return nil
+			}
+			return v
+		}
+		n = protowire.ConsumeFieldValue(num, typ, data)
+		if n < 0 {
+			return nil
+		}
+		data = data[n:]
+	}
+	return nil
+}
@@ -0,0 +1,270 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlp
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+type parameters struct {
+	logLevel        zerolog.Level
+	address         string
+	serviceName     string
+	headers         map[string]string
+	insecure        bool
+	tlsConfig       *tls.Config
+	resource        map[string]string
+	queueSize       int
+	batchSize       int
+	flushInterval   time.Duration
+	exportTimeout   time.Duration
+	shutdownTimeout time.Duration
+}
+
+// Parameter is the interface for service parameters.
+type Parameter interface {
+	apply(*parameters)
+}
+
+type parameterFunc func(*parameters)
+
+func (f parameterFunc) apply(p *parameters) {
+	f(p)
+}
+
+// WithLogLevel sets the log level for the module.
+func WithLogLevel(logLevel zerolog.Level) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.logLevel = logLevel
+	})
+}
+
+// WithAddress sets the host:port of the OTLP gRPC collector.
+// If not supplied the OTEL_EXPORTER_OTLP_TRACES_ENDPOINT and
+// OTEL_EXPORTER_OTLP_ENDPOINT environment variables are used.
+func WithAddress(address string) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.address = address
+	})
+}
+
+// WithServiceName sets the name of the service reported with spans.
+// OTEL_SERVICE_NAME, if set, takes precedence.
+func WithServiceName(name string) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.serviceName = name
+	})
+}
+
+// WithQueueSize sets the maximum number of finished spans held for export.
+// Spans finished when the queue is full are dropped.
+func WithQueueSize(size int) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.queueSize = size
+	})
+}
+
+// WithBatchSize sets the maximum number of spans sent in a single export.
+func WithBatchSize(size int) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.batchSize = size
+	})
+}
+
+// WithFlushInterval sets the maximum time that a finished span is held before export.
+func WithFlushInterval(interval time.Duration) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.flushInterval = interval
+	})
+}
+
+// WithShutdownTimeout sets the maximum time that Close spends exporting outstanding spans.
+func WithShutdownTimeout(timeout time.Duration) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.shutdownTimeout = timeout
+	})
+}
+
+// parseAndCheckParameters parses and checks parameters to ensure that mandatory parameters are present and correct.
+func parseAndCheckParameters(params ...Parameter) (*parameters, error) {
+	parameters := parameters{
+		logLevel:        zerolog.GlobalLevel(),
+		serviceName:     "dirk",
+		queueSize:       2048,
+		batchSize:       512,
+		flushInterval:   5 * time.Second,
+		exportTimeout:   10 * time.Second,
+		shutdownTimeout: 2 * time.Second,
+	}
+	for _, p := range params {
+		if params != nil {
+			p.apply(&parameters)
+		}
+	}
+
+	if err := parameters.applyEnvironment(); err != nil {
+		return nil, err
+	}
+
+	if parameters.address == "" {
+		return nil, errors.New("no address specified")
+	}
+	if parameters.serviceName == "" {
+		return nil, errors.New("no service name specified")
+	}
+	if parameters.queueSize <= 0 {
+		return nil, errors.New("queue size must be positive")
+	}
+	if parameters.batchSize <= 0 {
+		return nil, errors.New("batch size must be positive")
+	}
+	if parameters.flushInterval <= 0 {
+		return nil, errors.New("flush interval must be positive")
+	}
+	if parameters.shutdownTimeout <= 0 {
+		return nil, errors.New("shutdown timeout must be positive")
+	}
+
+	return &parameters, nil
+}
+
+// applyEnvironment applies the standard OpenTelemetry exporter environment
+// variables.  Trace-specific variables take precedence over general ones.
+func (p *parameters) applyEnvironment() error {
+	if p.address == "" {
+		if endpoint := otelEnv("ENDPOINT"); endpoint != "" {
+			address, insecure, err := parseEndpoint(endpoint)
+			if err != nil {
+				return errors.Wrap(err, "invalid OTLP endpoint")
+			}
+			p.address = address
+			p.insecure = insecure
+		}
+	}
+
+	if name := os.Getenv("OTEL_SERVICE_NAME"); name != "" {
+		p.serviceName = name
+	}
+
+	var err error
+	p.resource, err = parseKeyValues(os.Getenv("OTEL_RESOURCE_ATTRIBUTES"))
+	if err != nil {
+		return errors.Wrap(err, "invalid OTEL_RESOURCE_ATTRIBUTES")
+	}
+	p.headers, err = parseKeyValues(otelEnv("HEADERS"))
+	if err != nil {
+		return errors.Wrap(err, "invalid OTLP headers")
+	}
+
+	if insecure := otelEnv("INSECURE"); insecure != "" {
+		p.insecure, err = strconv.ParseBool(insecure)
+		if err != nil {
+			return errors.Wrap(err, "invalid OTLP insecure setting")
+		}
+	}
+	if p.insecure {
+		return nil
+	}
+
+	p.tlsConfig = &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+	if caCertFile := otelEnv("CERTIFICATE"); caCertFile != "" {
+		caCert, err := ioutil.ReadFile(caCertFile)
+		if err != nil {
+			return errors.Wrap(err, "failed to read OTLP certificate")
+		}
+		certPool := x509.NewCertPool()
+		if !certPool.AppendCertsFromPEM(caCert) {
+			return errors.New("invalid OTLP certificate")
+		}
+		p.tlsConfig.RootCAs = certPool
+	}
+	clientCertFile := otelEnv("CLIENT_CERTIFICATE")
+	clientKeyFile := otelEnv("CLIENT_KEY")
+	if clientCertFile != "" || clientKeyFile != "" {
+		clientCert, err := tls.LoadX509KeyPair(clientCertFile, clientKeyFile)
+		if err != nil {
+			return errors.Wrap(err, "failed to load OTLP client certificate")
+		}
+		p.tlsConfig.Certificates = []tls.Certificate{clientCert}
+	}
+
+	return nil
+}
+
+// otelEnv returns the value of the OTLP exporter environment variable with the
+// given suffix, preferring the trace-specific variable.
+func otelEnv(suffix string) string {
+	if value := os.Getenv(fmt.Sprintf("OTEL_EXPORTER_OTLP_TRACES_%s", suffix)); value != "" {
+		return value
+	}
+	return os.Getenv(fmt.Sprintf("OTEL_EXPORTER_OTLP_%s", suffix))
+}
+
+// parseEndpoint parses an OTLP endpoint URL in to a host:port, and whether
+// the connection is insecure.
+func parseEndpoint(endpoint string) (string, bool, error) {
+	if !strings.Contains(endpoint, "://") {
+		return endpoint, false, nil
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", false, err
+	}
+	if u.Host == "" {
+		return "", false, errors.New("no host")
+	}
+	host := u.Host
+	if u.Port() == "" {
+		host = fmt.Sprintf("%s:4317", host)
+	}
+	return host, u.Scheme == "http", nil
+}
+
+// parseKeyValues parses a comma-separated list of URL-encoded key=value pairs.
+func parseKeyValues(input string) (map[string]string, error) {
+	res := make(map[string]string)
+	for _, pair := range strings.Split(input, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return nil, fmt.Errorf("invalid entry %q", pair)
+		}
+		key, err := url.QueryUnescape(strings.TrimSpace(parts[0]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %q", parts[0])
+		}
+		value, err := url.QueryUnescape(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %q", key)
+		}
+		res[key] = value
+	}
+	return res, nil
+}
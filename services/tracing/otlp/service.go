@@ -0,0 +1,255 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlp
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	zerologger "github.com/rs/zerolog/log"
+)
+
+// Service is an opentracing tracer that exports spans to an OpenTelemetry
+// collector using OTLP over gRPC.  Finished spans are queued and exported in
+// batches in the background.
+type Service struct {
+	exporter        *exporter
+	queue           chan *span
+	batchSize       int
+	flushInterval   time.Duration
+	shutdownTimeout time.Duration
+	stop            chan struct{}
+	stopped         chan struct{}
+	closeOnce       sync.Once
+}
+
+// module-wide log.
+var log zerolog.Logger
+
+// New creates a new OTLP tracer.
+func New(ctx context.Context, params ...Parameter) (*Service, error) {
+	parameters, err := parseAndCheckParameters(params...)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem with parameters")
+	}
+
+	// Set logging.
+	log = zerologger.With().Str("service", "tracing").Str("impl", "otlp").Logger()
+	if parameters.logLevel != log.GetLevel() {
+		log = log.Level(parameters.logLevel)
+	}
+
+	exporter, err := newExporter(ctx, parameters)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create exporter")
+	}
+
+	s := &Service{
+		exporter:        exporter,
+		queue:           make(chan *span, parameters.queueSize),
+		batchSize:       parameters.batchSize,
+		flushInterval:   parameters.flushInterval,
+		shutdownTimeout: parameters.shutdownTimeout,
+		stop:            make(chan struct{}),
+		stopped:         make(chan struct{}),
+	}
+	go s.export()
+
+	return s, nil
+}
+
+// StartSpan creates, starts, and returns a new span.
+func (s *Service) StartSpan(operationName string, opts ...opentracing.StartSpanOption) opentracing.Span {
+	options := opentracing.StartSpanOptions{}
+	for _, opt := range opts {
+		opt.Apply(&options)
+	}
+
+	newSpan := &span{
+		tracer: s,
+		name:   operationName,
+		start:  options.StartTime,
+		tags:   make(map[string]interface{}, len(options.Tags)),
+		context: &spanContext{
+			baggage: make(map[string]string),
+		},
+	}
+	if newSpan.start.IsZero() {
+		newSpan.start = time.Now()
+	}
+	for k, v := range options.Tags {
+		newSpan.tags[k] = v
+	}
+
+	for _, ref := range options.References {
+		parent, isSpanContext := ref.ReferencedContext.(*spanContext)
+		if !isSpanContext {
+			continue
+		}
+		newSpan.context.traceID = parent.traceID
+		newSpan.parentSpanID = parent.spanID
+		newSpan.hasParent = true
+		for k, v := range parent.baggage {
+			newSpan.context.baggage[k] = v
+		}
+		break
+	}
+	if !newSpan.hasParent {
+		randomBytes(newSpan.context.traceID[:])
+	}
+	randomBytes(newSpan.context.spanID[:])
+
+	return newSpan
+}
+
+// Inject injects the span context in to the carrier.  Text map and HTTP
+// header carriers are supported, using the W3C trace context format.
+func (s *Service) Inject(sm opentracing.SpanContext, format interface{}, carrier interface{}) error {
+	sc, isSpanContext := sm.(*spanContext)
+	if !isSpanContext {
+		return opentracing.ErrInvalidSpanContext
+	}
+	if format != opentracing.TextMap && format != opentracing.HTTPHeaders {
+		return opentracing.ErrUnsupportedFormat
+	}
+	writer, isWriter := carrier.(opentracing.TextMapWriter)
+	if !isWriter {
+		return opentracing.ErrInvalidCarrier
+	}
+	writer.Set("traceparent", fmt.Sprintf("00-%x-%x-01", sc.traceID, sc.spanID))
+	return nil
+}
+
+// Extract extracts a span context from the carrier.
+func (s *Service) Extract(format interface{}, carrier interface{}) (opentracing.SpanContext, error) {
+	if format != opentracing.TextMap && format != opentracing.HTTPHeaders {
+		return nil, opentracing.ErrUnsupportedFormat
+	}
+	reader, isReader := carrier.(opentracing.TextMapReader)
+	if !isReader {
+		return nil, opentracing.ErrInvalidCarrier
+	}
+	var traceparent string
+	if err := reader.ForeachKey(func(key, val string) error {
+		if strings.EqualFold(key, "traceparent") {
+			traceparent = val
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	if traceparent == "" {
+		return nil, opentracing.ErrSpanContextNotFound
+	}
+
+	parts := strings.Split(traceparent, "-")
+	if len(parts) < 4 || len(parts[1]) != 32 || len(parts[2]) != 16 {
+		return nil, opentracing.ErrSpanContextCorrupted
+	}
+	sc := &spanContext{
+		baggage: make(map[string]string),
+	}
+	if _, err := hex.Decode(sc.traceID[:], []byte(parts[1])); err != nil {
+		return nil, opentracing.ErrSpanContextCorrupted
+	}
+	if _, err := hex.Decode(sc.spanID[:], []byte(parts[2])); err != nil {
+		return nil, opentracing.ErrSpanContextCorrupted
+	}
+	return sc, nil
+}
+
+// Close exports any outstanding spans and stops the tracer.  It returns
+// once the spans have been exported, or the shutdown timeout has passed.
+func (s *Service) Close() error {
+	s.closeOnce.Do(func() {
+		close(s.stop)
+	})
+	<-s.stopped
+	return s.exporter.close()
+}
+
+// enqueue queues a finished span for export.
+func (s *Service) enqueue(span *span) {
+	select {
+	case <-s.stop:
+		log.Trace().Str("span", span.name).Msg("Tracer closed; dropping span")
+	case s.queue <- span:
+	default:
+		log.Debug().Str("span", span.name).Msg("Span queue full; dropping span")
+	}
+}
+
+// export exports spans until the tracer is closed.
+func (s *Service) export() {
+	defer close(s.stopped)
+	ticker := time.NewTicker(s.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*span, 0, s.batchSize)
+	send := func(ctx context.Context) {
+		if len(batch) == 0 {
+			return
+		}
+		if err := s.exporter.export(ctx, batch); err != nil {
+			log.Warn().Err(err).Int("spans", len(batch)).Msg("Failed to export spans")
+		} else {
+			log.Trace().Int("spans", len(batch)).Msg("Exported spans")
+		}
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case span := <-s.queue:
+			batch = append(batch, span)
+			if len(batch) >= s.batchSize {
+				send(context.Background())
+			}
+		case <-ticker.C:
+			send(context.Background())
+		case <-s.stop:
+			ctx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
+			defer cancel()
+		drain:
+			for {
+				select {
+				case span := <-s.queue:
+					batch = append(batch, span)
+					if len(batch) >= s.batchSize {
+						send(ctx)
+					}
+				default:
+					break drain
+				}
+			}
+			send(ctx)
+			return
+		}
+	}
+}
+
+// randomBytes fills the supplied slice with random bytes.
+func randomBytes(data []byte) {
+	if _, err := rand.Read(data); err != nil {
+		log.Warn().Err(err).Msg("Failed to generate random identifier")
+	}
+}
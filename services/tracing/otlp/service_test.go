@@ -0,0 +1,227 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlp_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/attestantio/dirk/services/tracing/otlp"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// rawCodec receives messages without decoding them.
+type rawCodec struct{}
+
+func (rawCodec) Marshal(v interface{}) ([]byte, error) { return *(v.(*[]byte)), nil }
+func (rawCodec) Unmarshal(data []byte, v interface{}) error {
+	*(v.(*[]byte)) = append([]byte{}, data...)
+	return nil
+}
+func (rawCodec) Name() string { return "proto" }
+
+// collector is a minimal OTLP collector that records the spans it receives.
+type collector struct {
+	mu            sync.Mutex
+	methods       []string
+	authorization []string
+	spans         map[string][]byte
+}
+
+func (c *collector) handle(srv interface{}, stream grpc.ServerStream) error {
+	method, _ := grpc.MethodFromServerStream(stream)
+	md, _ := metadata.FromIncomingContext(stream.Context())
+	var req []byte
+	if err := stream.RecvMsg(&req); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.methods = append(c.methods, method)
+	c.authorization = append(c.authorization, md.Get("authorization")...)
+	for _, resourceSpans := range fields(req, 1) {
+		for _, scopeSpans := range fields(resourceSpans, 2) {
+			for _, span := range fields(scopeSpans, 2) {
+				c.spans[string(fields(span, 5)[0])] = span
+			}
+		}
+	}
+	c.mu.Unlock()
+
+	resp := []byte{}
+	return stream.SendMsg(&resp)
+}
+
+// fields returns the values of the length-delimited fields with the given number.
+func fields(data []byte, field protowire.Number) [][]byte {
+	res := make([][]byte, 0)
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			panic("bad tag")
+		}
+		data = data[n:]
+		if num == field && typ == protowire.BytesType {
+			v, n := protowire.ConsumeBytes(data)
+			res = append(res, v)
+			data = data[n:]
+			continue
+		}
+		data = data[protowire.ConsumeFieldValue(num, typ, data):]
+	}
+	return res
+}
+
+// attributes returns the string form of the attributes of a message.
+func attributes(data []byte, field protowire.Number) map[string]string {
+	res := make(map[string]string)
+	for _, kv := range fields(data, field) {
+		key := string(fields(kv, 1)[0])
+		value := fields(kv, 2)[0]
+		num, typ, n := protowire.ConsumeTag(value)
+		switch {
+		case typ == protowire.BytesType:
+			v, _ := protowire.ConsumeString(value[n:])
+			res[key] = v
+		case num == 2:
+			v, _ := protowire.ConsumeVarint(value[n:])
+			res[key] = fmt.Sprintf("%t", protowire.DecodeBool(v))
+		default:
+			v, _ := protowire.ConsumeVarint(value[n:])
+			res[key] = fmt.Sprintf("%d", v)
+		}
+	}
+	return res
+}
+
+func startCollector(t *testing.T) (*collector, string) {
+	c := &collector{spans: make(map[string][]byte)}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer(grpc.ForceServerCodec(rawCodec{}), grpc.UnknownServiceHandler(c.handle))
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(server.Stop)
+	return c, listener.Addr().String()
+}
+
+func TestNew(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := otlp.New(ctx)
+	require.EqualError(t, err, "problem with parameters: no address specified")
+
+	_, err = otlp.New(ctx, otlp.WithAddress("localhost:4317"), otlp.WithBatchSize(0))
+	require.EqualError(t, err, "problem with parameters: batch size must be positive")
+
+	require.NoError(t, os.Setenv("OTEL_EXPORTER_OTLP_HEADERS", "bad"))
+	_, err = otlp.New(ctx, otlp.WithAddress("localhost:4317"))
+	require.EqualError(t, err, `problem with parameters: invalid OTLP headers: invalid entry "bad"`)
+	require.NoError(t, os.Unsetenv("OTEL_EXPORTER_OTLP_HEADERS"))
+
+	require.NoError(t, os.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "http://localhost:4317"))
+	defer os.Unsetenv("OTEL_EXPORTER_OTLP_ENDPOINT")
+	s, err := otlp.New(ctx)
+	require.NoError(t, err)
+	require.NoError(t, s.Close())
+}
+
+func TestExport(t *testing.T) {
+	c, address := startCollector(t)
+	require.NoError(t, os.Setenv("OTEL_EXPORTER_OTLP_INSECURE", "true"))
+	defer os.Unsetenv("OTEL_EXPORTER_OTLP_INSECURE")
+	require.NoError(t, os.Setenv("OTEL_EXPORTER_OTLP_TRACES_HEADERS", "authorization=Bearer%20abc"))
+	defer os.Unsetenv("OTEL_EXPORTER_OTLP_TRACES_HEADERS")
+
+	s, err := otlp.New(context.Background(),
+		otlp.WithLogLevel(zerolog.Disabled),
+		otlp.WithAddress(address),
+		otlp.WithFlushInterval(time.Hour),
+	)
+	require.NoError(t, err)
+
+	// Spans started through the global tracer, as the rest of the code does.
+	tracer := opentracing.GlobalTracer()
+	opentracing.SetGlobalTracer(s)
+	defer opentracing.SetGlobalTracer(tracer)
+
+	span, ctx := opentracing.StartSpanFromContext(context.Background(), "parent")
+	span.SetTag("operation", "Sign")
+	child, _ := opentracing.StartSpanFromContext(ctx, "child")
+	ext.Error.Set(child, true)
+	child.LogKV("event", "failed", "attempts", 3)
+	child.Finish()
+	span.Finish()
+
+	// Close flushes the outstanding spans.
+	require.NoError(t, s.Close())
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	require.Equal(t, []string{"/opentelemetry.proto.collector.trace.v1.TraceService/Export"}, c.methods)
+	require.Equal(t, []string{"Bearer abc"}, c.authorization)
+	require.Len(t, c.spans, 2)
+
+	parent := c.spans["parent"]
+	require.Equal(t, map[string]string{"operation": "Sign"}, attributes(parent, 9))
+	require.Empty(t, fields(parent, 4))
+
+	childSpan := c.spans["child"]
+	// Same trace, with the parent's span ID as its parent.
+	require.Equal(t, fields(parent, 1), fields(childSpan, 1))
+	require.Equal(t, fields(parent, 2), fields(childSpan, 4))
+	require.Len(t, fields(childSpan, 15), 1)
+	events := fields(childSpan, 11)
+	require.Len(t, events, 1)
+	require.Equal(t, "failed", string(fields(events[0], 2)[0]))
+	require.Equal(t, map[string]string{"attempts": "3"}, attributes(events[0], 3))
+}
+
+func TestPropagation(t *testing.T) {
+	s, err := otlp.New(context.Background(),
+		otlp.WithLogLevel(zerolog.Disabled),
+		otlp.WithAddress("localhost:4317"),
+	)
+	require.NoError(t, err)
+	defer s.Close()
+
+	span := s.StartSpan("test")
+	carrier := opentracing.TextMapCarrier{}
+	require.NoError(t, s.Inject(span.Context(), opentracing.TextMap, carrier))
+	require.Len(t, carrier["traceparent"], 55)
+
+	extracted, err := s.Extract(opentracing.TextMap, carrier)
+	require.NoError(t, err)
+	out := opentracing.TextMapCarrier{}
+	require.NoError(t, s.Inject(extracted, opentracing.TextMap, out))
+	require.Equal(t, carrier, out)
+
+	_, err = s.Extract(opentracing.TextMap, opentracing.TextMapCarrier{})
+	require.Equal(t, opentracing.ErrSpanContextNotFound, err)
+	_, err = s.Extract(opentracing.TextMap, opentracing.TextMapCarrier{"traceparent": "00-bad"})
+	require.Equal(t, opentracing.ErrSpanContextCorrupted, err)
+	require.Equal(t, opentracing.ErrUnsupportedFormat, s.Inject(span.Context(), opentracing.Binary, nil))
+}
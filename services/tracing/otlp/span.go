@@ -0,0 +1,172 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlp
+
+import (
+	"sync"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	otlog "github.com/opentracing/opentracing-go/log"
+)
+
+// spanContext is the context of a span.
+type spanContext struct {
+	traceID [16]byte
+	spanID  [8]byte
+	baggage map[string]string
+}
+
+// ForeachBaggageItem calls the handler for each baggage item until it returns false.
+func (c *spanContext) ForeachBaggageItem(handler func(k, v string) bool) {
+	for k, v := range c.baggage {
+		if !handler(k, v) {
+			return
+		}
+	}
+}
+
+// event is a log entry within a span.
+type event struct {
+	timestamp time.Time
+	fields    []otlog.Field
+}
+
+// span is a span that is exported to the collector when finished.
+type span struct {
+	tracer       *Service
+	context      *spanContext
+	parentSpanID [8]byte
+	hasParent    bool
+	start        time.Time
+
+	mu       sync.Mutex
+	name     string
+	end      time.Time
+	tags     map[string]interface{}
+	events   []*event
+	finished bool
+}
+
+// Finish finishes the span.
+func (s *span) Finish() {
+	s.FinishWithOptions(opentracing.FinishOptions{})
+}
+
+// FinishWithOptions finishes the span with the given options.
+func (s *span) FinishWithOptions(opts opentracing.FinishOptions) {
+	s.mu.Lock()
+	if s.finished {
+		s.mu.Unlock()
+		return
+	}
+	s.finished = true
+	s.end = opts.FinishTime
+	if s.end.IsZero() {
+		s.end = time.Now()
+	}
+	for _, record := range opts.LogRecords {
+		s.events = append(s.events, &event{timestamp: record.Timestamp, fields: record.Fields})
+	}
+	for _, data := range opts.BulkLogData {
+		record := data.ToLogRecord()
+		s.events = append(s.events, &event{timestamp: record.Timestamp, fields: record.Fields})
+	}
+	s.mu.Unlock()
+
+	s.tracer.enqueue(s)
+}
+
+// Context returns the context of the span.
+func (s *span) Context() opentracing.SpanContext {
+	return s.context
+}
+
+// SetOperationName sets the name of the span.
+func (s *span) SetOperationName(operationName string) opentracing.Span {
+	s.mu.Lock()
+	s.name = operationName
+	s.mu.Unlock()
+	return s
+}
+
+// SetTag sets a tag on the span.
+func (s *span) SetTag(key string, value interface{}) opentracing.Span {
+	s.mu.Lock()
+	s.tags[key] = value
+	s.mu.Unlock()
+	return s
+}
+
+// LogFields logs fields against the span.
+func (s *span) LogFields(fields ...otlog.Field) {
+	s.mu.Lock()
+	s.events = append(s.events, &event{timestamp: time.Now(), fields: fields})
+	s.mu.Unlock()
+}
+
+// LogKV logs alternating keys and values against the span.
+func (s *span) LogKV(alternatingKeyValues ...interface{}) {
+	fields, err := otlog.InterleavedKVToFields(alternatingKeyValues...)
+	if err != nil {
+		s.LogFields(otlog.Error(err), otlog.String("function", "LogKV"))
+		return
+	}
+	s.LogFields(fields...)
+}
+
+// SetBaggageItem sets a baggage item.
+func (s *span) SetBaggageItem(restrictedKey string, value string) opentracing.Span {
+	s.mu.Lock()
+	s.context.baggage[restrictedKey] = value
+	s.mu.Unlock()
+	return s
+}
+
+// BaggageItem returns a baggage item.
+func (s *span) BaggageItem(restrictedKey string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.context.baggage[restrictedKey]
+}
+
+// Tracer returns the tracer that created the span.
+func (s *span) Tracer() opentracing.Tracer {
+	return s.tracer
+}
+
+// LogEvent is deprecated.
+func (s *span) LogEvent(event string) {
+	s.Log(opentracing.LogData{Event: event})
+}
+
+// LogEventWithPayload is deprecated.
+func (s *span) LogEventWithPayload(event string, payload interface{}) {
+	s.Log(opentracing.LogData{Event: event, Payload: payload})
+}
+
+// Log is deprecated.
+func (s *span) Log(data opentracing.LogData) {
+	record := data.ToLogRecord()
+	s.mu.Lock()
+	s.events = append(s.events, &event{timestamp: record.Timestamp, fields: record.Fields})
+	s.mu.Unlock()
+}
+
+// isError returns true if the span is tagged as an error.
+func (s *span) isError() bool {
+	isError, ok := s.tags[string(ext.Error)].(bool)
+	return ok && isError
+}
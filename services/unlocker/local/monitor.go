@@ -16,3 +16,6 @@ package local
 // noopMonitor is a monitor that does nothing, used in place of nil if an
 // external monitor is not supplied.
 type noopMonitor struct{}
+
+// UnlockedAccounts is called with the number of accounts that are currently unlocked by the unlocker.
+func (m *noopMonitor) UnlockedAccounts(count int) {}
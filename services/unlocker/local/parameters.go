@@ -14,6 +14,8 @@
 package local
 
 import (
+	"time"
+
 	"github.com/attestantio/dirk/services/metrics"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
@@ -24,6 +26,7 @@ type parameters struct {
 	monitor            metrics.UnlockerMonitor
 	walletPassphrases  []string
 	accountPassphrases []string
+	relockAfter        time.Duration
 }
 
 // Parameter is the interface for service parameters.
@@ -65,6 +68,14 @@ func WithAccountPassphrases(passphrases []string) Parameter {
 	})
 }
 
+// WithRelockAfter sets the time after which an account unlocked by this unlocker is locked
+// again if it has not been used.  If this is 0 accounts remain unlocked.
+func WithRelockAfter(relockAfter time.Duration) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.relockAfter = relockAfter
+	})
+}
+
 // parseAndCheckParameters parses and checks parameters to ensure that mandatory parameters are present and correct.
 func parseAndCheckParameters(params ...Parameter) (*parameters, error) {
 	parameters := parameters{
@@ -88,6 +99,9 @@ func parseAndCheckParameters(params ...Parameter) (*parameters, error) {
 	if parameters.accountPassphrases == nil {
 		return nil, errors.New("no account passphrases supplied")
 	}
+	if parameters.relockAfter < 0 {
+		return nil, errors.New("relock after must not be negative")
+	}
 
 	return &parameters, nil
 }
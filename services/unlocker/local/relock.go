@@ -0,0 +1,120 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package local
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	e2wtypes "github.com/wealdtech/go-eth2-wallet-types/v2"
+)
+
+// defaultRefreshInterval is the interval at which unlocked accounts are checked
+// if accounts are not relocked.
+const defaultRefreshInterval = time.Minute
+
+// unlockedAccount is an account that has been unlocked by this unlocker.
+type unlockedAccount struct {
+	name     string
+	locker   e2wtypes.AccountLocker
+	lastUsed time.Time
+}
+
+// accountUnlocked records an account that has been unlocked by this unlocker.
+func (s *Service) accountUnlocked(wallet e2wtypes.Wallet, account e2wtypes.Account, locker e2wtypes.AccountLocker) {
+	s.unlockedMu.Lock()
+	s.unlocked[account.ID()] = &unlockedAccount{
+		name:     fmt.Sprintf("%s/%s", wallet.Name(), account.Name()),
+		locker:   locker,
+		lastUsed: time.Now(),
+	}
+	unlockedAccounts := len(s.unlocked)
+	s.unlockedMu.Unlock()
+	s.monitor.UnlockedAccounts(unlockedAccounts)
+}
+
+// AccountUsed is called when an unlocked account is used, resetting its idle time.
+func (s *Service) AccountUsed(ctx context.Context, wallet e2wtypes.Wallet, account e2wtypes.Account) {
+	if account == nil {
+		return
+	}
+	s.unlockedMu.Lock()
+	defer s.unlockedMu.Unlock()
+	if unlocked, exists := s.unlocked[account.ID()]; exists {
+		unlocked.lastUsed = time.Now()
+	}
+}
+
+// IdleTime returns the time since an unlocked account was last used, and the idle
+// time after which it is relocked.
+func (s *Service) IdleTime(ctx context.Context, wallet e2wtypes.Wallet, account e2wtypes.Account) (time.Duration, time.Duration, bool) {
+	if account == nil || s.relockAfter == 0 {
+		return 0, 0, false
+	}
+	s.unlockedMu.Lock()
+	defer s.unlockedMu.Unlock()
+	unlocked, exists := s.unlocked[account.ID()]
+	if !exists {
+		return 0, 0, false
+	}
+	return time.Since(unlocked.lastUsed), s.relockAfter, true
+}
+
+// relocker periodically relocks idle accounts until the context is done.
+func (s *Service) relocker(ctx context.Context) {
+	interval := defaultRefreshInterval
+	if s.relockAfter > 0 {
+		// Check twice per period, so accounts are relocked within one and a half
+		// periods of their last use.
+		interval = s.relockAfter / 2
+		if interval == 0 {
+			interval = s.relockAfter
+		}
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.relockIdle(ctx)
+		}
+	}
+}
+
+// relockIdle locks accounts that have not been used within the relock period.  It also
+// stops tracking accounts that have been locked by other means.
+func (s *Service) relockIdle(ctx context.Context) {
+	s.unlockedMu.Lock()
+	defer s.unlockedMu.Unlock()
+	for id, unlocked := range s.unlocked {
+		isUnlocked, err := unlocked.locker.IsUnlocked(ctx)
+		if err == nil && !isUnlocked {
+			delete(s.unlocked, id)
+			continue
+		}
+		if s.relockAfter == 0 || time.Since(unlocked.lastUsed) < s.relockAfter {
+			continue
+		}
+		if err := unlocked.locker.Lock(ctx); err != nil {
+			log.Warn().Err(err).Str("account", unlocked.name).Msg("Failed to relock idle account")
+			continue
+		}
+		log.Trace().Str("account", unlocked.name).Msg("Relocked idle account")
+		delete(s.unlocked, id)
+	}
+	s.monitor.UnlockedAccounts(len(s.unlocked))
+}
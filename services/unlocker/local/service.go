@@ -15,8 +15,11 @@ package local
 
 import (
 	"context"
+	"sync"
+	"time"
 
 	"github.com/attestantio/dirk/services/metrics"
+	"github.com/google/uuid"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
 	zerologger "github.com/rs/zerolog/log"
@@ -28,6 +31,9 @@ type Service struct {
 	monitor            metrics.UnlockerMonitor
 	walletPassphrases  []string
 	accountPassphrases []string
+	relockAfter        time.Duration
+	unlockedMu         sync.Mutex
+	unlocked           map[uuid.UUID]*unlockedAccount
 }
 
 // module-wide log.
@@ -50,8 +56,12 @@ func New(ctx context.Context, params ...Parameter) (*Service, error) {
 		monitor:            parameters.monitor,
 		walletPassphrases:  parameters.walletPassphrases,
 		accountPassphrases: parameters.accountPassphrases,
+		relockAfter:        parameters.relockAfter,
+		unlocked:           make(map[uuid.UUID]*unlockedAccount),
 	}
 
+	go s.relocker(ctx)
+
 	return s, nil
 }
 
@@ -100,6 +110,7 @@ func (s *Service) UnlockAccount(ctx context.Context, wallet e2wtypes.Wallet, acc
 
 	for _, passphrase := range s.accountPassphrases {
 		if err := locker.Unlock(ctx, []byte(passphrase)); err == nil {
+			s.accountUnlocked(wallet, account, locker)
 			return true, nil
 		}
 	}
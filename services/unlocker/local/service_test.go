@@ -15,7 +15,9 @@ package local_test
 
 import (
 	"context"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/attestantio/dirk/services/unlocker/local"
 	"github.com/attestantio/dirk/testing/mock"
@@ -210,3 +212,68 @@ func TestUnlockAccount(t *testing.T) {
 		})
 	}
 }
+
+type unlockedMonitor struct {
+	mu       sync.Mutex
+	unlocked int
+}
+
+func (m *unlockedMonitor) UnlockedAccounts(count int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.unlocked = count
+}
+
+func (m *unlockedMonitor) count() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.unlocked
+}
+
+func TestRelockAfter(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	require.NoError(t, e2types.InitBLS())
+
+	_, err := local.New(ctx, local.WithRelockAfter(-time.Second))
+	require.EqualError(t, err, "problem with parameters: relock after must not be negative")
+
+	monitor := &unlockedMonitor{}
+	relockAfter := 200 * time.Millisecond
+	service, err := local.New(ctx,
+		local.WithMonitor(monitor),
+		local.WithAccountPassphrases([]string{"secret"}),
+		local.WithRelockAfter(relockAfter),
+	)
+	require.NoError(t, err)
+
+	wallet := mock.NewWallet("Test wallet")
+	idleAccount := mock.NewAccount("Account 1", []byte("secret"))
+	usedAccount := mock.NewAccount("Account 2", []byte("secret"))
+	for _, account := range []e2wtypes.Account{idleAccount, usedAccount} {
+		unlocked, err := service.UnlockAccount(ctx, wallet, account)
+		require.NoError(t, err)
+		require.True(t, unlocked)
+	}
+	require.Equal(t, 2, monitor.count())
+
+	// Keep using one of the accounts beyond the relock period.
+	for i := 0; i < 8; i++ {
+		time.Sleep(relockAfter / 4)
+		service.AccountUsed(ctx, wallet, usedAccount)
+	}
+	unlocked, err := idleAccount.IsUnlocked(ctx)
+	require.NoError(t, err)
+	require.False(t, unlocked)
+	unlocked, err = usedAccount.IsUnlocked(ctx)
+	require.NoError(t, err)
+	require.True(t, unlocked)
+	require.Equal(t, 1, monitor.count())
+
+	// The idle account is unlocked again on request.
+	unlocked, err = service.UnlockAccount(ctx, wallet, idleAccount)
+	require.NoError(t, err)
+	require.True(t, unlocked)
+	require.Equal(t, 2, monitor.count())
+}
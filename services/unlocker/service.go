@@ -15,6 +15,7 @@ package unlocker
 
 import (
 	"context"
+	"time"
 
 	e2wtypes "github.com/wealdtech/go-eth2-wallet-types/v2"
 )
@@ -25,3 +26,14 @@ type Service interface {
 
 	UnlockAccount(context.Context, e2wtypes.Wallet, e2wtypes.Account) (bool, error)
 }
+
+// IdleRelocker is the interface for an unlocker that relocks accounts once they have been idle.
+type IdleRelocker interface {
+	// AccountUsed is called when an unlocked account is used, resetting its idle time.
+	AccountUsed(ctx context.Context, wallet e2wtypes.Wallet, account e2wtypes.Account)
+
+	// IdleTime returns the time since an unlocked account was last used, and the idle
+	// time after which it is relocked.  The final value is false if the account is not
+	// relocked by this unlocker, in which case the times are not set.
+	IdleTime(ctx context.Context, wallet e2wtypes.Wallet, account e2wtypes.Account) (time.Duration, time.Duration, bool)
+}
@@ -14,19 +14,27 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/attestantio/dirk/rules"
+	standardrules "github.com/attestantio/dirk/rules/standard"
+	"github.com/attestantio/dirk/services/fetcher/mem"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
 	"github.com/spf13/viper"
+	keystorev4 "github.com/wealdtech/go-eth2-wallet-encryptor-keystorev4"
+	majordomo "github.com/wealdtech/go-majordomo"
 )
 
 // SlashingProtection is the top-level structure for slashing protection data.
@@ -60,8 +68,8 @@ type SlashingProtectionAttestation struct {
 }
 
 // exportSlashingProtection is a command to export the slashing protection database.
-func exportSlashingProtection(ctx context.Context) {
-	protection, err := fetchSlashingProtection(ctx)
+func exportSlashingProtection(ctx context.Context, majordomo majordomo.Service) {
+	protection, err := fetchSlashingProtection(ctx, majordomo)
 	if err != nil {
 		fmt.Printf("%v\n", err)
 		os.Exit(1)
@@ -85,7 +93,7 @@ func exportSlashingProtection(ctx context.Context) {
 }
 
 // fetchSlashingProtection obtains the slashing protection database.
-func fetchSlashingProtection(ctx context.Context) (*SlashingProtection, error) {
+func fetchSlashingProtection(ctx context.Context, majordomo majordomo.Service) (*SlashingProtection, error) {
 	zerolog.SetGlobalLevel(zerolog.Disabled)
 	if viper.GetString("genesis-validators-root") == "" {
 		return nil, errors.New("genesis-validators-root is required for export")
@@ -98,11 +106,35 @@ func fetchSlashingProtection(ctx context.Context) (*SlashingProtection, error) {
 	if len(genesisValidatorsRoot) != 32 {
 		return nil, errors.New("genesis-validators-root must be 32 bytes")
 	}
+	if err := checkInterchangeFormat(viper.GetString("interchange-format")); err != nil {
+		return nil, err
+	}
 
-	rules, err := initRules(ctx)
+	rules, err := initRules(ctx, majordomo, nil)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to set up rules")
 	}
+	defer closeRules(ctx, rules)
+	return slashingProtectionFromRules(ctx, rules, genesisValidatorsRoot)
+}
+
+// checkInterchangeFormat checks that slashing protection can be exported in the given interchange format.
+func checkInterchangeFormat(format string) error {
+	switch format {
+	case "minimal":
+		return nil
+	case "complete":
+		// Dirk records only the highest signed attestation and block for each validator,
+		// so it cannot provide the history that the complete format requires.
+		return errors.New("complete interchange format is not available as Dirk does not retain signing history; use minimal")
+	default:
+		return fmt.Errorf("unknown interchange format %q; must be minimal or complete", format)
+	}
+}
+
+// slashingProtectionFromRules obtains slashing protection in minimal interchange format from a rules service,
+// with the highest attestation source and target epochs and the highest block slot for each validator.
+func slashingProtectionFromRules(ctx context.Context, rules rules.Service, genesisValidatorsRoot []byte) (*SlashingProtection, error) {
 	protection, err := rules.ExportSlashingProtection(ctx)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to obtain slashing protection")
@@ -139,8 +171,90 @@ func fetchSlashingProtection(ctx context.Context) (*SlashingProtection, error) {
 	return res, nil
 }
 
+// shutdownExportConfig is the configuration for exporting slashing protection on shutdown.
+type shutdownExportConfig struct {
+	path                  string
+	genesisValidatorsRoot []byte
+}
+
+// initShutdownExport obtains the configuration for exporting slashing protection on shutdown.
+// It returns nil if export on shutdown is not configured.
+func initShutdownExport() (*shutdownExportConfig, error) {
+	path := viper.GetString("server.rules.export-on-shutdown.path")
+	if path == "" {
+		return nil, nil
+	}
+	if viper.GetString("server.rules.export-on-shutdown.genesis-validators-root") == "" {
+		return nil, errors.New("server.rules.export-on-shutdown.genesis-validators-root is required for export")
+	}
+	genesisValidatorsRoot, err := hex.DecodeString(strings.TrimPrefix(viper.GetString("server.rules.export-on-shutdown.genesis-validators-root"), "0x"))
+	if err != nil {
+		return nil, errors.Wrap(err, "server.rules.export-on-shutdown.genesis-validators-root is invalid")
+	}
+	if len(genesisValidatorsRoot) != 32 {
+		return nil, errors.New("server.rules.export-on-shutdown.genesis-validators-root must be 32 bytes")
+	}
+
+	return &shutdownExportConfig{
+		path:                  resolvePath(path),
+		genesisValidatorsRoot: genesisValidatorsRoot,
+	}, nil
+}
+
+// exportSlashingProtectionOnShutdown exports the slashing protection database to a file.
+// The file is only written if the export completes before the context is done, so
+// a partial export never overwrites a previous complete export.
+func exportSlashingProtectionOnShutdown(ctx context.Context, rulesSvc rules.Service, config *shutdownExportConfig) {
+	log := log.With().Str("path", config.path).Logger()
+	log.Info().Msg("Exporting slashing protection")
+	started := time.Now()
+
+	type exportResult struct {
+		data []byte
+		err  error
+	}
+	resCh := make(chan *exportResult, 1)
+	go func() {
+		protection, err := slashingProtectionFromRules(ctx, rulesSvc, config.genesisValidatorsRoot)
+		if err != nil {
+			resCh <- &exportResult{err: err}
+			return
+		}
+		data, err := json.Marshal(protection)
+		if err != nil {
+			resCh <- &exportResult{err: errors.Wrap(err, "failed to generate output")}
+			return
+		}
+		resCh <- &exportResult{data: data}
+	}()
+
+	var res *exportResult
+	select {
+	case <-ctx.Done():
+		log.Error().Dur("elapsed", time.Since(started)).Msg("Slashing protection export did not complete within the shutdown timeout; export truncated and not written")
+		return
+	case res = <-resCh:
+	}
+	if res.err != nil {
+		log.Error().Err(res.err).Msg("Failed to export slashing protection")
+		return
+	}
+
+	// Write to a temporary file and rename, so the export appears atomically.
+	tmpPath := fmt.Sprintf("%s.tmp", config.path)
+	if err := ioutil.WriteFile(tmpPath, res.data, 0600); err != nil {
+		log.Error().Err(err).Msg("Failed to write slashing protection export")
+		return
+	}
+	if err := os.Rename(tmpPath, config.path); err != nil {
+		log.Error().Err(err).Msg("Failed to move slashing protection export in to place")
+		return
+	}
+	log.Info().Dur("elapsed", time.Since(started)).Msg("Exported slashing protection")
+}
+
 // importSlashingProtection is a command to import a slashing protection database.
-func importSlashingProtection(ctx context.Context) {
+func importSlashingProtection(ctx context.Context, majordomo majordomo.Service) {
 	if viper.GetString("slashing-protection-file") == "" {
 		fmt.Println("Slashing protection file required for import")
 		os.Exit(1)
@@ -156,7 +270,7 @@ func importSlashingProtection(ctx context.Context) {
 		fmt.Printf("Failed to parse slashing protection file: %v\n", err)
 		os.Exit(1)
 	}
-	if err := storeSlashingProtection(ctx, &protection); err != nil {
+	if err := storeSlashingProtection(ctx, majordomo, &protection); err != nil {
 		fmt.Printf("Failed to store slashing protection: %v\n", err)
 		os.Exit(1)
 	}
@@ -164,64 +278,281 @@ func importSlashingProtection(ctx context.Context) {
 	os.Exit(0)
 }
 
-// storeSlashingProtection updates the slashing protection database.
-func storeSlashingProtection(ctx context.Context, protection *SlashingProtection) error {
+// migrateSlashingProtection is a command to migrate the slashing protection database from local storage to SQLite storage.
+func migrateSlashingProtection(ctx context.Context) {
 	zerolog.SetGlobalLevel(zerolog.Disabled)
-	// Confirm format and metadata.
-	if protection == nil {
-		return errors.New("slashing protection missing")
-	}
-	if protection.Metadata == nil {
-		return errors.New("no metadata in file")
+	storagePath := resolvePath(viper.GetString("storage-path"))
+
+	source, err := standardrules.New(ctx,
+		standardrules.WithStoragePath(storagePath),
+		standardrules.WithStorageType("badger"),
+	)
+	if err != nil {
+		fmt.Printf("Failed to open local slashing protection: %v\n", err)
+		os.Exit(1)
 	}
-	if protection.Metadata.InterchangeFormatVersion != "5" {
-		return fmt.Errorf("interchange format incorrect; expected 5, found %s", protection.Metadata.InterchangeFormatVersion)
+	protection, err := source.ExportSlashingProtection(ctx)
+	if err != nil {
+		fmt.Printf("Failed to obtain local slashing protection: %v\n", err)
+		os.Exit(1)
 	}
-	if viper.GetString("genesis-validators-root") == "" {
-		return errors.New("genesis-validators-root is required for import")
+
+	destination, err := standardrules.New(ctx,
+		standardrules.WithStoragePath(storagePath),
+		standardrules.WithStorageType("sqlite"),
+	)
+	if err != nil {
+		fmt.Printf("Failed to open SQLite slashing protection: %v\n", err)
+		os.Exit(1)
 	}
-	genesisValidatorsRoot, err := hex.DecodeString(strings.TrimPrefix(viper.GetString("genesis-validators-root"), "0x"))
+	existing, err := destination.ExportSlashingProtection(ctx)
 	if err != nil {
-		return errors.Wrap(err, "genesis-validators-root is invalid")
+		fmt.Printf("Failed to obtain SQLite slashing protection: %v\n", err)
+		os.Exit(1)
 	}
-	if len(genesisValidatorsRoot) != 32 {
-		return errors.New("genesis-validators-root must be 32 bytes")
+	if len(existing) > 0 {
+		fmt.Println("SQLite slashing protection already contains data; not migrating")
+		os.Exit(1)
 	}
-	if viper.GetString("genesis-validators-root") != protection.Metadata.GenesisValidatorsRoot {
-		return fmt.Errorf("genesis validators root incorrect; expected %s, found %s", viper.GetString("genesis-validators-root"), protection.Metadata.GenesisValidatorsRoot)
+	if err := destination.ImportSlashingProtection(ctx, protection); err != nil {
+		fmt.Printf("Failed to store SQLite slashing protection: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := source.Close(ctx); err != nil {
+		fmt.Printf("Failed to close local slashing protection: %v\n", err)
+		os.Exit(1)
+	}
+	if err := destination.Close(ctx); err != nil {
+		fmt.Printf("Failed to close SQLite slashing protection: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Migrated slashing protection for %d validators\n", len(protection))
+	os.Exit(0)
+}
+
+// closeRules closes a rules service, so that its storage is left in a clean state when a command exits.
+func closeRules(ctx context.Context, rulesSvc rules.Service) {
+	closer, isCloser := rulesSvc.(interface {
+		Close(ctx context.Context) error
+	})
+	if !isCloser {
+		return
+	}
+	if err := closer.Close(ctx); err != nil {
+		fmt.Printf("Failed to close slashing protection storage: %v\n", err)
 	}
+}
 
-	rulesSvc, err := initRules(ctx)
+// localStorageExists returns true unless slashing protection is held locally and the storage path does not exist.
+func localStorageExists() bool {
+	if viper.GetString("server.rules.storage-type") == "postgres" {
+		return true
+	}
+	_, err := os.Stat(resolvePath(viper.GetString("storage-path")))
+	return err == nil
+}
+
+// noSlashingProtection is the slashing protection of a validator that has not signed anything.
+var noSlashingProtection = &rules.SlashingProtection{
+	HighestAttestedSourceEpoch: -1,
+	HighestAttestedTargetEpoch: -1,
+	HighestProposedSlot:        -1,
+}
+
+// slashingProtectionUpdates returns the slashing protection to store for each validator whose
+// protection would change on import.  Existing protection is never weakened, so importing the
+// same data again has no effect, and validators with nothing to import are left untouched.
+func slashingProtectionUpdates(imported map[[48]byte]*rules.SlashingProtection,
+	existing map[[48]byte]*rules.SlashingProtection,
+) map[[48]byte]*rules.SlashingProtection {
+	updates := make(map[[48]byte]*rules.SlashingProtection)
+	for key, importedProtection := range imported {
+		current, exists := existing[key]
+		if !exists {
+			current = noSlashingProtection
+		}
+		merged := mergeSlashingProtection(current, importedProtection)
+		if merged.HighestAttestedSourceEpoch != current.HighestAttestedSourceEpoch ||
+			merged.HighestAttestedTargetEpoch != current.HighestAttestedTargetEpoch ||
+			merged.HighestProposedSlot != current.HighestProposedSlot {
+			updates[key] = merged
+		}
+	}
+	return updates
+}
+
+// reportSlashingProtectionImport reports the changes that an import would make for each validator.
+func reportSlashingProtectionImport(out io.Writer,
+	imported map[[48]byte]*rules.SlashingProtection,
+	existing map[[48]byte]*rules.SlashingProtection,
+	updates map[[48]byte]*rules.SlashingProtection,
+	knownPubKeys map[[48]byte]bool,
+) {
+	keys := make([][48]byte, 0, len(imported))
+	for key := range imported {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return bytes.Compare(keys[i][:], keys[j][:]) < 0 })
+
+	for _, key := range keys {
+		current, exists := existing[key]
+		if !exists {
+			current = noSlashingProtection
+		}
+		update, updated := updates[key]
+		if !updated {
+			update = current
+		}
+		attestationChange := "no-op"
+		if update.HighestAttestedSourceEpoch != current.HighestAttestedSourceEpoch ||
+			update.HighestAttestedTargetEpoch != current.HighestAttestedTargetEpoch {
+			attestationChange = "advance"
+		}
+		blockChange := "no-op"
+		if update.HighestProposedSlot != current.HighestProposedSlot {
+			blockChange = "advance"
+		}
+		unknown := ""
+		if knownPubKeys != nil && !knownPubKeys[key] {
+			unknown = " (unknown to this Dirk)"
+		}
+		fmt.Fprintf(out, "%#x%s: attestation %s, imported %s: %s; block %s, imported %s: %s\n",
+			key,
+			unknown,
+			describeAttestation(current), describeAttestation(imported[key]), attestationChange,
+			describeSlot(current.HighestProposedSlot), describeSlot(imported[key].HighestProposedSlot), blockChange,
+		)
+	}
+	fmt.Fprintf(out, "Dry run: %d of %d validators would advance; nothing written\n", len(updates), len(imported))
+}
+
+// describeAttestation describes the highest attestation in slashing protection.
+func describeAttestation(protection *rules.SlashingProtection) string {
+	if protection.HighestAttestedSourceEpoch == -1 && protection.HighestAttestedTargetEpoch == -1 {
+		return "none"
+	}
+	return fmt.Sprintf("source %d target %d", protection.HighestAttestedSourceEpoch, protection.HighestAttestedTargetEpoch)
+}
+
+// describeSlot describes the highest block slot in slashing protection.
+func describeSlot(slot int64) string {
+	if slot == -1 {
+		return "none"
+	}
+	return fmt.Sprintf("slot %d", slot)
+}
+
+// validatorsFilter parses the public keys of the validators for which to import slashing protection.
+// The value is either a comma-separated list of public keys or the path to a file containing one
+// public key per line.  It returns nil if the value is empty, in which case all validators are imported.
+func validatorsFilter(value string) (map[[48]byte]bool, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	var entries []string
+	if info, err := os.Stat(resolvePath(value)); err == nil && !info.IsDir() {
+		data, err := ioutil.ReadFile(resolvePath(value))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read validators file")
+		}
+		entries = publicKeyLines(data)
+	} else {
+		for _, entry := range strings.Split(value, ",") {
+			entries = append(entries, strings.TrimSpace(entry))
+		}
+	}
+
+	filter := make(map[[48]byte]bool, len(entries))
+	for _, entry := range entries {
+		key, err := parsePublicKey(entry)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid validator %q", entry)
+		}
+		filter[key] = true
+	}
+	return filter, nil
+}
+
+// publicKeyLines returns the entries in a file of public keys, one per line.
+// Blank lines and lines starting with # are ignored.
+func publicKeyLines(data []byte) []string {
+	entries := make([]string, 0)
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		entries = append(entries, line)
+	}
+	return entries
+}
+
+// parsePublicKey parses a hex-encoded public key.
+func parsePublicKey(value string) ([48]byte, error) {
+	var key [48]byte
+	data, err := hex.DecodeString(strings.TrimPrefix(value, "0x"))
 	if err != nil {
-		return errors.Wrap(err, "failed to set up rules")
+		return key, errors.Wrap(err, "invalid public key")
 	}
+	if len(data) != len(key) {
+		return key, errors.New("public key must be 48 bytes")
+	}
+	copy(key[:], data)
+	return key, nil
+}
 
-	existingProtection, err := rulesSvc.ExportSlashingProtection(ctx)
+// storePublicKeys returns the public keys of the accounts in the configured stores.
+func storePublicKeys(ctx context.Context, majordomo majordomo.Service) (map[[48]byte]bool, error) {
+	stores, err := initStores(ctx, majordomo)
 	if err != nil {
-		return errors.Wrap(err, "failed to obtain existing protection")
+		return nil, err
 	}
+	accounts, _ := mem.VerifyStores(ctx, stores, keystorev4.New())
+	pubKeys := make(map[[48]byte]bool, len(accounts))
+	for _, account := range accounts {
+		var key [48]byte
+		copy(key[:], account.Account.PublicKey().Marshal())
+		pubKeys[key] = true
+	}
+	return pubKeys, nil
+}
 
-	protectionMap := make(map[[48]byte]*rules.SlashingProtection)
+// parseSlashingProtection parses interchange data in to the highest attestation and block for each validator,
+// restricted to the validators in the filter if supplied.
+func parseSlashingProtection(protection *SlashingProtection, filter map[[48]byte]bool) (map[[48]byte]*rules.SlashingProtection, error) {
+	res := make(map[[48]byte]*rules.SlashingProtection)
 	for i := range protection.Data {
-		var key [48]byte
-		copy(key[:], protection.Data[i].PublicKey)
+		key, err := parsePublicKey(protection.Data[i].PublicKey)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid validator %q in slashing protection file", protection.Data[i].PublicKey)
+		}
+		if filter != nil && !filter[key] {
+			continue
+		}
 		keyProtection := &rules.SlashingProtection{
+			PubKey:                     key[:],
 			HighestAttestedSourceEpoch: -1,
 			HighestAttestedTargetEpoch: -1,
 			HighestProposedSlot:        -1,
 		}
+		if existing, exists := res[key]; exists {
+			// The same validator may appear more than once.
+			keyProtection = existing
+		}
 		// We take the absolute highest source epoch and target epoch across all provided attestations.
 		for _, attestation := range protection.Data[i].SignedAttestations {
 			sourceEpoch, err := strconv.ParseInt(attestation.SourceEpoch, 10, 64)
 			if err != nil {
-				return errors.Wrap(err, "invalid attestation source epoch")
+				return nil, errors.Wrap(err, "invalid attestation source epoch")
 			}
 			if sourceEpoch > keyProtection.HighestAttestedSourceEpoch {
 				keyProtection.HighestAttestedSourceEpoch = sourceEpoch
 			}
 			targetEpoch, err := strconv.ParseInt(attestation.TargetEpoch, 10, 64)
 			if err != nil {
-				return errors.Wrap(err, "invalid attestation target epoch")
+				return nil, errors.Wrap(err, "invalid attestation target epoch")
 			}
 			if targetEpoch > keyProtection.HighestAttestedTargetEpoch {
 				keyProtection.HighestAttestedTargetEpoch = targetEpoch
@@ -231,27 +562,110 @@ func storeSlashingProtection(ctx context.Context, protection *SlashingProtection
 		for _, proposal := range protection.Data[i].SignedBlocks {
 			slot, err := strconv.ParseInt(proposal.Slot, 10, 64)
 			if err != nil {
-				return errors.Wrap(err, "invalid proposal slot")
+				return nil, errors.Wrap(err, "invalid proposal slot")
 			}
 			if slot > keyProtection.HighestProposedSlot {
 				keyProtection.HighestProposedSlot = slot
 			}
 		}
+		res[key] = keyProtection
+	}
+	return res, nil
+}
+
+// mergeSlashingProtection merges existing and imported slashing protection, taking the highest of each value.
+// The result is at least as strict as both.
+func mergeSlashingProtection(existing *rules.SlashingProtection, imported *rules.SlashingProtection) *rules.SlashingProtection {
+	merged := *existing
+	if imported.HighestAttestedSourceEpoch > merged.HighestAttestedSourceEpoch {
+		merged.HighestAttestedSourceEpoch = imported.HighestAttestedSourceEpoch
+	}
+	if imported.HighestAttestedTargetEpoch > merged.HighestAttestedTargetEpoch {
+		merged.HighestAttestedTargetEpoch = imported.HighestAttestedTargetEpoch
+	}
+	if imported.HighestProposedSlot > merged.HighestProposedSlot {
+		merged.HighestProposedSlot = imported.HighestProposedSlot
+	}
+	return &merged
+}
+
+// storeSlashingProtection updates the slashing protection database.
+func storeSlashingProtection(ctx context.Context, majordomo majordomo.Service, protection *SlashingProtection) error {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	// Confirm format and metadata.
+	if protection == nil {
+		return errors.New("slashing protection missing")
+	}
+	if protection.Metadata == nil {
+		return errors.New("no metadata in file")
+	}
+	if protection.Metadata.InterchangeFormatVersion != "5" {
+		return fmt.Errorf("interchange format incorrect; expected 5, found %s", protection.Metadata.InterchangeFormatVersion)
+	}
+	if viper.GetString("genesis-validators-root") == "" {
+		return errors.New("genesis-validators-root is required for import")
+	}
+	genesisValidatorsRoot, err := hex.DecodeString(strings.TrimPrefix(viper.GetString("genesis-validators-root"), "0x"))
+	if err != nil {
+		return errors.Wrap(err, "genesis-validators-root is invalid")
+	}
+	if len(genesisValidatorsRoot) != 32 {
+		return errors.New("genesis-validators-root must be 32 bytes")
+	}
+	if viper.GetString("genesis-validators-root") != protection.Metadata.GenesisValidatorsRoot {
+		return fmt.Errorf("genesis validators root incorrect; expected %s, found %s", viper.GetString("genesis-validators-root"), protection.Metadata.GenesisValidatorsRoot)
+	}
+
+	filter, err := validatorsFilter(viper.GetString("validators"))
+	if err != nil {
+		return err
+	}
+	importedProtection, err := parseSlashingProtection(protection, filter)
+	if err != nil {
+		return err
+	}
+	if filter != nil {
+		for key := range filter {
+			if _, exists := importedProtection[key]; !exists {
+				fmt.Printf("Warning: validator %#x not present in slashing protection file\n", key)
+			}
+		}
+	}
 
-		existingKeyProtection, exists := existingProtection[key]
-		if exists {
-			// We already have an entry; only add this if it contains newer data.
-			if existingKeyProtection.HighestAttestedSourceEpoch <= keyProtection.HighestAttestedSourceEpoch &&
-				existingKeyProtection.HighestAttestedTargetEpoch <= keyProtection.HighestAttestedTargetEpoch &&
-				existingKeyProtection.HighestProposedSlot <= keyProtection.HighestProposedSlot {
-				protectionMap[key] = keyProtection
-			} else {
-				fmt.Printf("Existing entry for public key %#x contains newer data; not importing\n", key)
+	knownPubKeys, err := storePublicKeys(ctx, majordomo)
+	if err != nil {
+		fmt.Printf("Warning: cannot check validators against stores: %v\n", err)
+	} else {
+		for key := range importedProtection {
+			if !knownPubKeys[key] {
+				fmt.Printf("Warning: validator %#x is not an account in Dirk's stores\n", key)
 			}
-		} else {
-			protectionMap[key] = keyProtection
 		}
 	}
+
+	dryRun := viper.GetBool("import-dry-run")
+	var rulesSvc rules.Service
+	existingProtection := make(map[[48]byte]*rules.SlashingProtection)
+	if dryRun && !localStorageExists() {
+		// Nothing to compare against; do not create the storage.
+	} else {
+		// A dry run opens the storage read-only, so that nothing can be written to it.
+		rulesSvc, err = initRules(ctx, majordomo, nil, standardrules.WithReadOnly(dryRun))
+		if err != nil {
+			return errors.Wrap(err, "failed to set up rules")
+		}
+		defer closeRules(ctx, rulesSvc)
+		existingProtection, err = rulesSvc.ExportSlashingProtection(ctx)
+		if err != nil {
+			return errors.Wrap(err, "failed to obtain existing protection")
+		}
+	}
+
+	protectionMap := slashingProtectionUpdates(importedProtection, existingProtection)
+	if dryRun {
+		reportSlashingProtectionImport(os.Stdout, importedProtection, existingProtection, protectionMap, knownPubKeys)
+		return nil
+	}
 	if err := rulesSvc.ImportSlashingProtection(ctx, protectionMap); err != nil {
 		return errors.Wrap(err, "failed to obtain slashing protection")
 	}
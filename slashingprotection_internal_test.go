@@ -0,0 +1,65 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/attestantio/dirk/rules"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportSlashingProtectionImport(t *testing.T) {
+	newKey := [48]byte{0x01}
+	emptyKey := [48]byte{0x02}
+	existingKey := [48]byte{0x03}
+
+	imported := map[[48]byte]*rules.SlashingProtection{
+		newKey: {
+			HighestAttestedSourceEpoch: 2,
+			HighestAttestedTargetEpoch: 3,
+			HighestProposedSlot:        10,
+		},
+		emptyKey: {
+			HighestAttestedSourceEpoch: -1,
+			HighestAttestedTargetEpoch: -1,
+			HighestProposedSlot:        -1,
+		},
+		existingKey: {
+			HighestAttestedSourceEpoch: 4,
+			HighestAttestedTargetEpoch: 5,
+			HighestProposedSlot:        20,
+		},
+	}
+	existing := map[[48]byte]*rules.SlashingProtection{
+		existingKey: {
+			HighestAttestedSourceEpoch: 4,
+			HighestAttestedTargetEpoch: 5,
+			HighestProposedSlot:        20,
+		},
+	}
+
+	updates := slashingProtectionUpdates(imported, existing)
+	require.Len(t, updates, 1)
+	require.Contains(t, updates, newKey)
+
+	out := &bytes.Buffer{}
+	reportSlashingProtectionImport(out, imported, existing, updates, nil)
+	require.Contains(t, out.String(), fmt.Sprintf("%#x: attestation none, imported source 2 target 3: advance; block none, imported slot 10: advance\n", newKey))
+	require.Contains(t, out.String(), fmt.Sprintf("%#x: attestation none, imported none: no-op; block none, imported none: no-op\n", emptyKey))
+	require.Contains(t, out.String(), fmt.Sprintf("%#x: attestation source 4 target 5, imported source 4 target 5: no-op; block slot 20, imported slot 20: no-op\n", existingKey))
+	require.Contains(t, out.String(), "Dry run: 1 of 3 validators would advance; nothing written\n")
+}
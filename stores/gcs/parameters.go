@@ -0,0 +1,106 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcs
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+type parameters struct {
+	logLevel        zerolog.Level
+	bucket          string
+	prefix          string
+	credentialsPath string
+	endpoint        string
+	passphrase      []byte
+}
+
+// Parameter is the interface for service parameters.
+type Parameter interface {
+	apply(*parameters)
+}
+
+type parameterFunc func(*parameters)
+
+func (f parameterFunc) apply(p *parameters) {
+	f(p)
+}
+
+// WithLogLevel sets the log level for the module.
+func WithLogLevel(logLevel zerolog.Level) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.logLevel = logLevel
+	})
+}
+
+// WithBucket sets the bucket that holds the wallets.
+func WithBucket(bucket string) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.bucket = bucket
+	})
+}
+
+// WithPrefix sets the prefix within the bucket under which the wallets are held.
+func WithPrefix(prefix string) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.prefix = prefix
+	})
+}
+
+// WithCredentialsPath sets the path to the service account credentials with
+// which to access the bucket.  If not set the application default credentials
+// are used.
+func WithCredentialsPath(path string) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.credentialsPath = path
+	})
+}
+
+// WithEndpoint sets the endpoint of the storage API, for example that of an
+// emulator.  If set without credentials requests are not authenticated.
+func WithEndpoint(endpoint string) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.endpoint = endpoint
+	})
+}
+
+// WithPassphrase sets the passphrase with which the store's data is encrypted.
+// If not set the data is stored as provided.
+func WithPassphrase(passphrase []byte) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.passphrase = passphrase
+	})
+}
+
+// parseAndCheckParameters parses and checks parameters to ensure that mandatory parameters are present and correct.
+func parseAndCheckParameters(params ...Parameter) (*parameters, error) {
+	parameters := parameters{
+		logLevel: zerolog.GlobalLevel(),
+	}
+	for _, p := range params {
+		if params != nil {
+			p.apply(&parameters)
+		}
+	}
+
+	if parameters.bucket == "" {
+		return nil, errors.New("no bucket specified")
+	}
+	parameters.prefix = strings.Trim(parameters.prefix, "/")
+
+	return &parameters, nil
+}
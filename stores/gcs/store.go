@@ -0,0 +1,340 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcs
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	zerologger "github.com/rs/zerolog/log"
+	"github.com/wealdtech/go-ecodec"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/option"
+	storage "google.golang.org/api/storage/v1"
+)
+
+// Store is a store for wallets held in a Google Cloud Storage bucket.
+// Wallets are laid out in the same way as in the filesystem store, under
+// an optional prefix:
+//
+//	<prefix>/<wallet ID>/<wallet ID>   wallet data
+//	<prefix>/<wallet ID>/<account ID>  account data
+//	<prefix>/<wallet ID>/index         account index
+type Store struct {
+	service    *storage.Service
+	bucket     string
+	prefix     string
+	passphrase []byte
+}
+
+// module-wide log.
+var log zerolog.Logger
+
+// New creates a new GCS store.
+func New(ctx context.Context, params ...Parameter) (*Store, error) {
+	parameters, err := parseAndCheckParameters(params...)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem with parameters")
+	}
+
+	// Set logging.
+	log = zerologger.With().Str("service", "stores").Str("impl", "gcs").Logger()
+	if parameters.logLevel != log.GetLevel() {
+		log = log.Level(parameters.logLevel)
+	}
+
+	opts := []option.ClientOption{
+		option.WithScopes(storage.DevstorageReadWriteScope),
+	}
+	if parameters.credentialsPath != "" {
+		opts = append(opts, option.WithCredentialsFile(parameters.credentialsPath))
+	}
+	if parameters.endpoint != "" {
+		opts = append(opts, option.WithEndpoint(parameters.endpoint))
+		if parameters.credentialsPath == "" {
+			opts = append(opts, option.WithoutAuthentication())
+		}
+	}
+	// The service is created with a background context as it outlives this call.
+	service, err := storage.NewService(context.Background(), opts...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create storage client")
+	}
+
+	s := &Store{
+		service:    service,
+		bucket:     parameters.bucket,
+		prefix:     parameters.prefix,
+		passphrase: parameters.passphrase,
+	}
+
+	// Confirm the bucket is accessible, so that a misconfiguration is
+	// reported at startup rather than as missing wallets.
+	if _, err := service.Buckets.Get(s.bucket).Context(ctx).Do(); err != nil {
+		return nil, errors.Wrap(err, fmt.Sprintf("failed to access bucket %s", s.bucket))
+	}
+	log.Trace().Str("bucket", s.bucket).Str("prefix", s.prefix).Msg("Accessed bucket")
+
+	return s, nil
+}
+
+// Name returns the name of this store.
+func (s *Store) Name() string {
+	return "gcs"
+}
+
+// Location returns the location of this store.
+func (s *Store) Location() string {
+	if s.prefix == "" {
+		return s.bucket
+	}
+	return fmt.Sprintf("%s/%s", s.bucket, s.prefix)
+}
+
+// StoreWallet stores wallet-level data.
+// Note that this will overwrite any existing data; it is up to higher-level functions to check for the presence of a wallet with
+// the wallet name and handle clashes accordingly.
+func (s *Store) StoreWallet(walletID uuid.UUID, walletName string, data []byte) error {
+	data, err := s.encryptIfRequired(data)
+	if err != nil {
+		return errors.Wrap(err, "failed to encrypt wallet")
+	}
+	if err := s.put(s.walletHeaderName(walletID), data); err != nil {
+		return errors.Wrap(err, "failed to store wallet")
+	}
+	return nil
+}
+
+// RetrieveWallet retrieves wallet-level data given the name of the wallet.
+func (s *Store) RetrieveWallet(walletName string) ([]byte, error) {
+	for data := range s.RetrieveWallets() {
+		info := &struct {
+			Name string `json:"name"`
+		}{}
+		if err := json.Unmarshal(data, info); err == nil && info.Name == walletName {
+			return data, nil
+		}
+	}
+	return nil, errors.New("wallet not found")
+}
+
+// RetrieveWalletByID retrieves wallet-level data given the ID of the wallet.
+func (s *Store) RetrieveWalletByID(walletID uuid.UUID) ([]byte, error) {
+	data, err := s.get(s.walletHeaderName(walletID))
+	if err != nil {
+		return nil, err
+	}
+	return s.decryptIfRequired(data)
+}
+
+// RetrieveWallets retrieves wallet-level data for all wallets.
+func (s *Store) RetrieveWallets() <-chan []byte {
+	ch := make(chan []byte, 1024)
+	go func() {
+		defer close(ch)
+		walletIDs := make([]uuid.UUID, 0)
+		err := s.service.Objects.List(s.bucket).Prefix(s.namePrefix()).Delimiter("/").Pages(context.Background(), func(objects *storage.Objects) error {
+			for _, prefix := range objects.Prefixes {
+				walletID, err := uuid.Parse(path.Base(prefix))
+				if err != nil {
+					// Not a wallet.
+					continue
+				}
+				walletIDs = append(walletIDs, walletID)
+			}
+			return nil
+		})
+		if err != nil {
+			log.Error().Err(err).Str("bucket", s.bucket).Msg("Failed to list wallets")
+			return
+		}
+		for _, walletID := range walletIDs {
+			data, err := s.RetrieveWalletByID(walletID)
+			if err != nil {
+				log.Warn().Err(err).Str("wallet_id", walletID.String()).Msg("Failed to retrieve wallet")
+				continue
+			}
+			ch <- data
+		}
+	}()
+	return ch
+}
+
+// StoreAccount stores account-level data.  It will fail if it cannot store the data.
+// Note that this will overwrite any existing data; it is up to higher-level functions to check for the presence of an account with
+// the account name and handle clashes accordingly.
+func (s *Store) StoreAccount(walletID uuid.UUID, accountID uuid.UUID, data []byte) error {
+	data, err := s.encryptIfRequired(data)
+	if err != nil {
+		return errors.Wrap(err, "failed to encrypt account")
+	}
+	if err := s.put(s.accountName(walletID, accountID), data); err != nil {
+		return errors.Wrap(err, "failed to store account")
+	}
+	return nil
+}
+
+// RetrieveAccount retrieves account-level data.  It will fail if it cannot retrieve the data.
+func (s *Store) RetrieveAccount(walletID uuid.UUID, accountID uuid.UUID) ([]byte, error) {
+	data, err := s.get(s.accountName(walletID, accountID))
+	if err != nil {
+		return nil, err
+	}
+	return s.decryptIfRequired(data)
+}
+
+// RetrieveAccounts retrieves all account-level data for a wallet.
+func (s *Store) RetrieveAccounts(walletID uuid.UUID) <-chan []byte {
+	ch := make(chan []byte, 1024)
+	go func() {
+		defer close(ch)
+		accountIDs := make([]uuid.UUID, 0)
+		err := s.service.Objects.List(s.bucket).Prefix(s.walletNamePrefix(walletID)).Pages(context.Background(), func(objects *storage.Objects) error {
+			for _, object := range objects.Items {
+				accountID, err := uuid.Parse(path.Base(object.Name))
+				if err != nil || accountID == walletID {
+					// Not an account.
+					continue
+				}
+				accountIDs = append(accountIDs, accountID)
+			}
+			return nil
+		})
+		if err != nil {
+			log.Error().Err(err).Str("wallet_id", walletID.String()).Msg("Failed to list accounts")
+			return
+		}
+		for _, accountID := range accountIDs {
+			data, err := s.RetrieveAccount(walletID, accountID)
+			if err != nil {
+				log.Warn().Err(err).Str("wallet_id", walletID.String()).Str("account_id", accountID.String()).Msg("Failed to retrieve account")
+				continue
+			}
+			ch <- data
+		}
+	}()
+	return ch
+}
+
+// DeleteAccount deletes account data.
+func (s *Store) DeleteAccount(walletID uuid.UUID, accountID uuid.UUID) error {
+	if err := s.service.Objects.Delete(s.bucket, s.accountName(walletID, accountID)).Do(); err != nil {
+		return errors.Wrap(err, "failed to delete account")
+	}
+	return nil
+}
+
+// StoreAccountsIndex stores the account index.
+func (s *Store) StoreAccountsIndex(walletID uuid.UUID, data []byte) error {
+	var err error
+	// Do not encrypt empty index.
+	if len(data) != 2 {
+		data, err = s.encryptIfRequired(data)
+		if err != nil {
+			return errors.Wrap(err, "failed to encrypt index")
+		}
+	}
+	if err := s.put(s.indexName(walletID), data); err != nil {
+		return errors.Wrap(err, "failed to store index")
+	}
+	return nil
+}
+
+// RetrieveAccountsIndex retrieves the account index.
+func (s *Store) RetrieveAccountsIndex(walletID uuid.UUID) ([]byte, error) {
+	data, err := s.get(s.indexName(walletID))
+	if err != nil {
+		return nil, err
+	}
+	// Do not decrypt empty index.
+	if len(data) == 2 {
+		return data, nil
+	}
+	return s.decryptIfRequired(data)
+}
+
+// put writes an object.  The object's checksum is supplied so that the
+// service rejects a write that has been corrupted in transit; a write
+// either stores the complete object or fails.
+func (s *Store) put(name string, data []byte) error {
+	checksum := md5.Sum(data)
+	_, err := s.service.Objects.Insert(s.bucket, &storage.Object{
+		Name:    name,
+		Md5Hash: base64.StdEncoding.EncodeToString(checksum[:]),
+	}).Media(bytes.NewReader(data), googleapi.ContentType("application/octet-stream")).Do()
+	return err
+}
+
+// get reads an object.
+func (s *Store) get(name string) ([]byte, error) {
+	res, err := s.service.Objects.Get(s.bucket, name).Download()
+	if err != nil {
+		if apiErr, isAPIErr := err.(*googleapi.Error); isAPIErr && apiErr.Code == http.StatusNotFound {
+			return nil, errors.New("not found")
+		}
+		return nil, err
+	}
+	defer res.Body.Close()
+	return ioutil.ReadAll(res.Body)
+}
+
+// namePrefix returns the prefix for all object names in the store.
+func (s *Store) namePrefix() string {
+	if s.prefix == "" {
+		return ""
+	}
+	return s.prefix + "/"
+}
+
+func (s *Store) walletNamePrefix(walletID uuid.UUID) string {
+	return fmt.Sprintf("%s%s/", s.namePrefix(), walletID.String())
+}
+
+func (s *Store) walletHeaderName(walletID uuid.UUID) string {
+	return s.walletNamePrefix(walletID) + walletID.String()
+}
+
+func (s *Store) accountName(walletID uuid.UUID, accountID uuid.UUID) string {
+	return s.walletNamePrefix(walletID) + accountID.String()
+}
+
+func (s *Store) indexName(walletID uuid.UUID) string {
+	return s.walletNamePrefix(walletID) + "index"
+}
+
+// encryptIfRequired encrypts data if required.
+func (s *Store) encryptIfRequired(data []byte) ([]byte, error) {
+	if len(data) == 0 || len(s.passphrase) == 0 {
+		return data, nil
+	}
+	return ecodec.Encrypt(data, s.passphrase)
+}
+
+// decryptIfRequired decrypts data if required.
+func (s *Store) decryptIfRequired(data []byte) ([]byte, error) {
+	if len(data) == 0 || len(s.passphrase) == 0 {
+		return data, nil
+	}
+	return ecodec.Decrypt(data, s.passphrase)
+}
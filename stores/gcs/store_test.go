@@ -0,0 +1,362 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcs_test
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/attestantio/dirk/stores/gcs"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+	e2types "github.com/wealdtech/go-eth2-types/v2"
+	e2wallet "github.com/wealdtech/go-eth2-wallet"
+	e2wtypes "github.com/wealdtech/go-eth2-wallet-types/v2"
+)
+
+func TestMain(m *testing.M) {
+	if err := e2types.InitBLS(); err != nil {
+		os.Exit(1)
+	}
+	os.Exit(m.Run())
+}
+
+// memServer is an in-memory implementation of the parts of the storage
+// JSON API used by the store, holding a single bucket.
+type memServer struct {
+	bucket string
+	// pageSize is the maximum number of entries returned in each page of a listing.
+	pageSize int
+	mu       sync.Mutex
+	objects  map[string][]byte
+}
+
+func newMemServer(bucket string) *memServer {
+	return &memServer{
+		bucket:   bucket,
+		pageSize: 2,
+		objects:  make(map[string][]byte),
+	}
+}
+
+func writeError(w http.ResponseWriter, code int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_, _ = fmt.Fprintf(w, `{"error":{"code":%d,"message":"%s"}}`, code, http.StatusText(code))
+}
+
+func writeJSON(w http.ResponseWriter, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+func (s *memServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	bucketPath := fmt.Sprintf("/storage/v1/b/%s", s.bucket)
+	uploadPath := fmt.Sprintf("/upload/storage/v1/b/%s/o", s.bucket)
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == bucketPath:
+		writeJSON(w, map[string]string{"name": s.bucket})
+	case r.Method == http.MethodPost && r.URL.Path == uploadPath:
+		s.insert(w, r)
+	case r.Method == http.MethodGet && r.URL.Path == bucketPath+"/o":
+		s.list(w, r)
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, bucketPath+"/o/"):
+		s.mu.Lock()
+		data, exists := s.objects[strings.TrimPrefix(r.URL.Path, bucketPath+"/o/")]
+		s.mu.Unlock()
+		if !exists {
+			writeError(w, http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(data)
+	case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, bucketPath+"/o/"):
+		s.mu.Lock()
+		delete(s.objects, strings.TrimPrefix(r.URL.Path, bucketPath+"/o/"))
+		s.mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusNotFound)
+	}
+}
+
+func (s *memServer) insert(w http.ResponseWriter, r *http.Request) {
+	_, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest)
+		return
+	}
+	reader := multipart.NewReader(r.Body, params["boundary"])
+	part, err := reader.NextPart()
+	if err != nil {
+		writeError(w, http.StatusBadRequest)
+		return
+	}
+	metadata := &struct {
+		Name    string `json:"name"`
+		Md5Hash string `json:"md5Hash"`
+	}{}
+	if err := json.NewDecoder(part).Decode(metadata); err != nil {
+		writeError(w, http.StatusBadRequest)
+		return
+	}
+	part, err = reader.NextPart()
+	if err != nil {
+		writeError(w, http.StatusBadRequest)
+		return
+	}
+	data, err := ioutil.ReadAll(part)
+	if err != nil {
+		writeError(w, http.StatusBadRequest)
+		return
+	}
+	checksum := md5.Sum(data)
+	if metadata.Md5Hash != base64.StdEncoding.EncodeToString(checksum[:]) {
+		writeError(w, http.StatusBadRequest)
+		return
+	}
+	s.mu.Lock()
+	s.objects[metadata.Name] = data
+	s.mu.Unlock()
+	writeJSON(w, map[string]string{"name": metadata.Name})
+}
+
+func (s *memServer) list(w http.ResponseWriter, r *http.Request) {
+	prefix := r.URL.Query().Get("prefix")
+	delimiter := r.URL.Query().Get("delimiter")
+
+	// Build the full listing, then return the requested page of it.
+	s.mu.Lock()
+	names := make([]string, 0)
+	prefixes := make(map[string]struct{})
+	for name := range s.objects {
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		if delimiter != "" {
+			if i := strings.Index(name[len(prefix):], delimiter); i >= 0 {
+				prefixes[name[:len(prefix)+i+1]] = struct{}{}
+				continue
+			}
+		}
+		names = append(names, name)
+	}
+	s.mu.Unlock()
+	for prefix := range prefixes {
+		names = append(names, prefix)
+	}
+	sort.Strings(names)
+
+	start := 0
+	if token := r.URL.Query().Get("pageToken"); token != "" {
+		start, _ = strconv.Atoi(token)
+	}
+	end := start + s.pageSize
+	if end > len(names) {
+		end = len(names)
+	}
+	res := &struct {
+		Items         []map[string]string `json:"items,omitempty"`
+		Prefixes      []string            `json:"prefixes,omitempty"`
+		NextPageToken string              `json:"nextPageToken,omitempty"`
+	}{}
+	for _, name := range names[start:end] {
+		if _, isPrefix := prefixes[name]; isPrefix {
+			res.Prefixes = append(res.Prefixes, name)
+		} else {
+			res.Items = append(res.Items, map[string]string{"name": name})
+		}
+	}
+	if end < len(names) {
+		res.NextPageToken = strconv.Itoa(end)
+	}
+	writeJSON(w, res)
+}
+
+func TestNew(t *testing.T) {
+	ctx := context.Background()
+	server := httptest.NewServer(newMemServer("wallets"))
+	defer server.Close()
+	endpoint := server.URL + "/storage/v1/"
+
+	tests := []struct {
+		name   string
+		params []gcs.Parameter
+		err    string
+	}{
+		{
+			name: "BucketMissing",
+			params: []gcs.Parameter{
+				gcs.WithLogLevel(zerolog.Disabled),
+				gcs.WithEndpoint(endpoint),
+			},
+			err: "problem with parameters: no bucket specified",
+		},
+		{
+			name: "CredentialsBad",
+			params: []gcs.Parameter{
+				gcs.WithLogLevel(zerolog.Disabled),
+				gcs.WithEndpoint(endpoint),
+				gcs.WithBucket("wallets"),
+				gcs.WithCredentialsPath("/does/not/exist"),
+			},
+			err: "failed to create storage client: cannot read credentials file: open /does/not/exist: no such file or directory",
+		},
+		{
+			name: "BucketInaccessible",
+			params: []gcs.Parameter{
+				gcs.WithLogLevel(zerolog.Disabled),
+				gcs.WithEndpoint(endpoint),
+				gcs.WithBucket("other"),
+			},
+			err: "failed to access bucket other: googleapi: Error 404: Not Found",
+		},
+		{
+			name: "Good",
+			params: []gcs.Parameter{
+				gcs.WithLogLevel(zerolog.Disabled),
+				gcs.WithEndpoint(endpoint),
+				gcs.WithBucket("wallets"),
+				gcs.WithPrefix("/dirk/"),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store, err := gcs.New(ctx, test.params...)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, "gcs", store.Name())
+				require.Equal(t, "wallets/dirk", store.Location())
+			}
+		})
+	}
+}
+
+func TestWalletsAndAccounts(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		prefix     string
+		passphrase []byte
+	}{
+		{
+			name: "NoPrefix",
+		},
+		{
+			name:   "Prefix",
+			prefix: "dirk/instance1",
+		},
+		{
+			name:       "Encrypted",
+			prefix:     "dirk",
+			passphrase: []byte("store secret"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			backend := newMemServer("wallets")
+			// Objects outside of the prefix should be ignored.
+			backend.objects["other/object"] = []byte("data")
+			server := httptest.NewServer(backend)
+			defer server.Close()
+			params := []gcs.Parameter{
+				gcs.WithLogLevel(zerolog.Disabled),
+				gcs.WithEndpoint(server.URL + "/storage/v1/"),
+				gcs.WithBucket("wallets"),
+				gcs.WithPrefix(test.prefix),
+				gcs.WithPassphrase(test.passphrase),
+			}
+			store, err := gcs.New(ctx, params...)
+			require.NoError(t, err)
+
+			// Create more wallets and accounts than fit in a single page of a listing.
+			accounts := make(map[string]e2wtypes.Account)
+			for _, walletName := range []string{"Wallet 1", "Wallet 2", "Wallet 3"} {
+				wallet, err := e2wallet.CreateWallet(walletName, e2wallet.WithStore(store), e2wallet.WithType("nd"))
+				require.NoError(t, err)
+				require.NoError(t, wallet.(e2wtypes.WalletLocker).Unlock(ctx, nil))
+				for _, accountName := range []string{"Account 1", "Account 2", "Account 3"} {
+					account, err := wallet.(e2wtypes.WalletAccountCreator).CreateAccount(ctx, accountName, []byte("pass"))
+					require.NoError(t, err)
+					accounts[walletName+"/"+accountName] = account
+				}
+			}
+			if len(test.passphrase) > 0 {
+				for name, data := range backend.objects {
+					if strings.HasPrefix(name, "dirk/") && !strings.HasSuffix(name, "/index") {
+						require.NotContains(t, string(data), "Wallet", name)
+					}
+				}
+			}
+
+			// Reopen the store to confirm everything is read back from the bucket.
+			store, err = gcs.New(ctx, params...)
+			require.NoError(t, err)
+			wallets := 0
+			for wallet := range e2wallet.Wallets(e2wallet.WithStore(store)) {
+				wallets++
+				found := 0
+				for account := range wallet.Accounts(ctx) {
+					expected, exists := accounts[wallet.Name()+"/"+account.Name()]
+					require.True(t, exists)
+					require.Equal(t, expected.PublicKey().Marshal(), account.PublicKey().Marshal())
+					found++
+				}
+				require.Equal(t, 3, found)
+			}
+			require.Equal(t, 3, wallets)
+
+			wallet, err := e2wallet.OpenWallet("Wallet 2", e2wallet.WithStore(store))
+			require.NoError(t, err)
+			account, err := wallet.(e2wtypes.WalletAccountByNameProvider).AccountByName(ctx, "Account 3")
+			require.NoError(t, err)
+			require.Equal(t, accounts["Wallet 2/Account 3"].PublicKey().Marshal(), account.PublicKey().Marshal())
+
+			// Delete an account.
+			require.NoError(t, store.DeleteAccount(wallet.ID(), account.ID()))
+			_, err = store.RetrieveAccount(wallet.ID(), account.ID())
+			require.EqualError(t, err, "not found")
+			found := 0
+			for range store.RetrieveAccounts(wallet.ID()) {
+				found++
+			}
+			require.Equal(t, 2, found)
+
+			_, err = store.RetrieveWalletByID(uuid.New())
+			require.EqualError(t, err, "not found")
+			_, err = store.RetrieveWallet("Unknown")
+			require.EqualError(t, err, "wallet not found")
+		})
+	}
+}
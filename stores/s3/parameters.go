@@ -0,0 +1,131 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3
+
+import (
+	"strings"
+
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+type parameters struct {
+	logLevel        zerolog.Level
+	bucket          string
+	prefix          string
+	region          string
+	endpoint        string
+	accessKeyID     string
+	secretAccessKey string
+	passphrase      []byte
+	client          s3iface.S3API
+}
+
+// Parameter is the interface for service parameters.
+type Parameter interface {
+	apply(*parameters)
+}
+
+type parameterFunc func(*parameters)
+
+func (f parameterFunc) apply(p *parameters) {
+	f(p)
+}
+
+// WithLogLevel sets the log level for the module.
+func WithLogLevel(logLevel zerolog.Level) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.logLevel = logLevel
+	})
+}
+
+// WithBucket sets the bucket that holds the wallets.
+func WithBucket(bucket string) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.bucket = bucket
+	})
+}
+
+// WithPrefix sets the prefix within the bucket under which the wallets are held.
+func WithPrefix(prefix string) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.prefix = prefix
+	})
+}
+
+// WithRegion sets the region of the bucket.
+func WithRegion(region string) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.region = region
+	})
+}
+
+// WithEndpoint sets the endpoint of an S3-compatible service to use in place of AWS.
+func WithEndpoint(endpoint string) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.endpoint = endpoint
+	})
+}
+
+// WithCredentials sets static credentials with which to access the bucket.
+// If not set the AWS default credential chain is used.
+func WithCredentials(accessKeyID string, secretAccessKey string) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.accessKeyID = accessKeyID
+		p.secretAccessKey = secretAccessKey
+	})
+}
+
+// WithPassphrase sets the passphrase with which the store's data is encrypted.
+// If not set the data is stored as provided.
+func WithPassphrase(passphrase []byte) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.passphrase = passphrase
+	})
+}
+
+// WithClient sets the S3 client, in place of one created from the region,
+// endpoint and credentials.
+func WithClient(client s3iface.S3API) Parameter {
+	return parameterFunc(func(p *parameters) {
+		p.client = client
+	})
+}
+
+// parseAndCheckParameters parses and checks parameters to ensure that mandatory parameters are present and correct.
+func parseAndCheckParameters(params ...Parameter) (*parameters, error) {
+	parameters := parameters{
+		logLevel: zerolog.GlobalLevel(),
+		region:   "us-east-1",
+	}
+	for _, p := range params {
+		if params != nil {
+			p.apply(&parameters)
+		}
+	}
+
+	if parameters.bucket == "" {
+		return nil, errors.New("no bucket specified")
+	}
+	if parameters.region == "" {
+		return nil, errors.New("no region specified")
+	}
+	if (parameters.accessKeyID == "") != (parameters.secretAccessKey == "") {
+		return nil, errors.New("access key ID and secret access key must be specified together")
+	}
+	parameters.prefix = strings.Trim(parameters.prefix, "/")
+
+	return &parameters, nil
+}
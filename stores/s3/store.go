@@ -0,0 +1,357 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	zerologger "github.com/rs/zerolog/log"
+	"github.com/wealdtech/go-ecodec"
+)
+
+// Store is a store for wallets held in an Amazon S3 bucket, or a bucket
+// in an S3-compatible service.  Wallets are laid out in the same way as
+// in the filesystem store, under an optional prefix:
+//
+//	<prefix>/<wallet ID>/<wallet ID>   wallet data
+//	<prefix>/<wallet ID>/<account ID>  account data
+//	<prefix>/<wallet ID>/index         account index
+type Store struct {
+	client     s3iface.S3API
+	bucket     string
+	prefix     string
+	passphrase []byte
+}
+
+// module-wide log.
+var log zerolog.Logger
+
+// New creates a new S3 store.
+func New(ctx context.Context, params ...Parameter) (*Store, error) {
+	parameters, err := parseAndCheckParameters(params...)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem with parameters")
+	}
+
+	// Set logging.
+	log = zerologger.With().Str("service", "stores").Str("impl", "s3").Logger()
+	if parameters.logLevel != log.GetLevel() {
+		log = log.Level(parameters.logLevel)
+	}
+
+	client := parameters.client
+	if client == nil {
+		config := aws.NewConfig().WithRegion(parameters.region)
+		if parameters.endpoint != "" {
+			// S3-compatible services generally do not support virtual-hosted buckets.
+			config = config.WithEndpoint(parameters.endpoint).WithS3ForcePathStyle(true)
+		}
+		if parameters.accessKeyID != "" {
+			config = config.WithCredentials(credentials.NewStaticCredentials(parameters.accessKeyID, parameters.secretAccessKey, ""))
+		}
+		session, err := session.NewSession(config)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create session")
+		}
+		client = s3.New(session)
+	}
+
+	s := &Store{
+		client:     client,
+		bucket:     parameters.bucket,
+		prefix:     parameters.prefix,
+		passphrase: parameters.passphrase,
+	}
+
+	// Confirm the bucket is accessible, so that a misconfiguration is
+	// reported at startup rather than as missing wallets.
+	if _, err := client.HeadBucketWithContext(ctx, &s3.HeadBucketInput{Bucket: aws.String(s.bucket)}); err != nil {
+		return nil, errors.Wrap(err, fmt.Sprintf("failed to access bucket %s", s.bucket))
+	}
+	log.Trace().Str("bucket", s.bucket).Str("prefix", s.prefix).Msg("Accessed bucket")
+
+	return s, nil
+}
+
+// Name returns the name of this store.
+func (s *Store) Name() string {
+	return "s3"
+}
+
+// Location returns the location of this store.
+func (s *Store) Location() string {
+	if s.prefix == "" {
+		return s.bucket
+	}
+	return fmt.Sprintf("%s/%s", s.bucket, s.prefix)
+}
+
+// StoreWallet stores wallet-level data.
+// Note that this will overwrite any existing data; it is up to higher-level functions to check for the presence of a wallet with
+// the wallet name and handle clashes accordingly.
+func (s *Store) StoreWallet(walletID uuid.UUID, walletName string, data []byte) error {
+	data, err := s.encryptIfRequired(data)
+	if err != nil {
+		return errors.Wrap(err, "failed to encrypt wallet")
+	}
+	if err := s.put(s.walletHeaderKey(walletID), data); err != nil {
+		return errors.Wrap(err, "failed to store wallet")
+	}
+	return nil
+}
+
+// RetrieveWallet retrieves wallet-level data given the name of the wallet.
+func (s *Store) RetrieveWallet(walletName string) ([]byte, error) {
+	for data := range s.RetrieveWallets() {
+		info := &struct {
+			Name string `json:"name"`
+		}{}
+		if err := json.Unmarshal(data, info); err == nil && info.Name == walletName {
+			return data, nil
+		}
+	}
+	return nil, errors.New("wallet not found")
+}
+
+// RetrieveWalletByID retrieves wallet-level data given the ID of the wallet.
+func (s *Store) RetrieveWalletByID(walletID uuid.UUID) ([]byte, error) {
+	data, err := s.get(s.walletHeaderKey(walletID))
+	if err != nil {
+		return nil, err
+	}
+	return s.decryptIfRequired(data)
+}
+
+// RetrieveWallets retrieves wallet-level data for all wallets.
+func (s *Store) RetrieveWallets() <-chan []byte {
+	ch := make(chan []byte, 1024)
+	go func() {
+		defer close(ch)
+		walletIDs := make([]uuid.UUID, 0)
+		err := s.client.ListObjectsV2Pages(&s3.ListObjectsV2Input{
+			Bucket:    aws.String(s.bucket),
+			Prefix:    aws.String(s.keyPrefix()),
+			Delimiter: aws.String("/"),
+		}, func(page *s3.ListObjectsV2Output, lastPage bool) bool {
+			for _, commonPrefix := range page.CommonPrefixes {
+				walletID, err := uuid.Parse(path.Base(aws.StringValue(commonPrefix.Prefix)))
+				if err != nil {
+					// Not a wallet.
+					continue
+				}
+				walletIDs = append(walletIDs, walletID)
+			}
+			return true
+		})
+		if err != nil {
+			log.Error().Err(err).Str("bucket", s.bucket).Msg("Failed to list wallets")
+			return
+		}
+		for _, walletID := range walletIDs {
+			data, err := s.RetrieveWalletByID(walletID)
+			if err != nil {
+				log.Warn().Err(err).Str("wallet_id", walletID.String()).Msg("Failed to retrieve wallet")
+				continue
+			}
+			ch <- data
+		}
+	}()
+	return ch
+}
+
+// StoreAccount stores account-level data.  It will fail if it cannot store the data.
+// Note that this will overwrite any existing data; it is up to higher-level functions to check for the presence of an account with
+// the account name and handle clashes accordingly.
+func (s *Store) StoreAccount(walletID uuid.UUID, accountID uuid.UUID, data []byte) error {
+	data, err := s.encryptIfRequired(data)
+	if err != nil {
+		return errors.Wrap(err, "failed to encrypt account")
+	}
+	if err := s.put(s.accountKey(walletID, accountID), data); err != nil {
+		return errors.Wrap(err, "failed to store account")
+	}
+	return nil
+}
+
+// RetrieveAccount retrieves account-level data.  It will fail if it cannot retrieve the data.
+func (s *Store) RetrieveAccount(walletID uuid.UUID, accountID uuid.UUID) ([]byte, error) {
+	data, err := s.get(s.accountKey(walletID, accountID))
+	if err != nil {
+		return nil, err
+	}
+	return s.decryptIfRequired(data)
+}
+
+// RetrieveAccounts retrieves all account-level data for a wallet.
+func (s *Store) RetrieveAccounts(walletID uuid.UUID) <-chan []byte {
+	ch := make(chan []byte, 1024)
+	go func() {
+		defer close(ch)
+		accountIDs := make([]uuid.UUID, 0)
+		err := s.client.ListObjectsV2Pages(&s3.ListObjectsV2Input{
+			Bucket: aws.String(s.bucket),
+			Prefix: aws.String(s.walletKeyPrefix(walletID)),
+		}, func(page *s3.ListObjectsV2Output, lastPage bool) bool {
+			for _, object := range page.Contents {
+				accountID, err := uuid.Parse(path.Base(aws.StringValue(object.Key)))
+				if err != nil || accountID == walletID {
+					// Not an account.
+					continue
+				}
+				accountIDs = append(accountIDs, accountID)
+			}
+			return true
+		})
+		if err != nil {
+			log.Error().Err(err).Str("wallet_id", walletID.String()).Msg("Failed to list accounts")
+			return
+		}
+		for _, accountID := range accountIDs {
+			data, err := s.RetrieveAccount(walletID, accountID)
+			if err != nil {
+				log.Warn().Err(err).Str("wallet_id", walletID.String()).Str("account_id", accountID.String()).Msg("Failed to retrieve account")
+				continue
+			}
+			ch <- data
+		}
+	}()
+	return ch
+}
+
+// DeleteAccount deletes account data.
+func (s *Store) DeleteAccount(walletID uuid.UUID, accountID uuid.UUID) error {
+	if _, err := s.client.DeleteObject(&s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.accountKey(walletID, accountID)),
+	}); err != nil {
+		return errors.Wrap(err, "failed to delete account")
+	}
+	return nil
+}
+
+// StoreAccountsIndex stores the account index.
+func (s *Store) StoreAccountsIndex(walletID uuid.UUID, data []byte) error {
+	var err error
+	// Do not encrypt empty index.
+	if len(data) != 2 {
+		data, err = s.encryptIfRequired(data)
+		if err != nil {
+			return errors.Wrap(err, "failed to encrypt index")
+		}
+	}
+	if err := s.put(s.indexKey(walletID), data); err != nil {
+		return errors.Wrap(err, "failed to store index")
+	}
+	return nil
+}
+
+// RetrieveAccountsIndex retrieves the account index.
+func (s *Store) RetrieveAccountsIndex(walletID uuid.UUID) ([]byte, error) {
+	data, err := s.get(s.indexKey(walletID))
+	if err != nil {
+		return nil, err
+	}
+	// Do not decrypt empty index.
+	if len(data) == 2 {
+		return data, nil
+	}
+	return s.decryptIfRequired(data)
+}
+
+// put writes an object.  The object's checksum is supplied so that the
+// service rejects a write that has been corrupted in transit; a write
+// either stores the complete object or fails.
+func (s *Store) put(key string, data []byte) error {
+	checksum := md5.Sum(data)
+	_, err := s.client.PutObject(&s3.PutObjectInput{
+		Bucket:        aws.String(s.bucket),
+		Key:           aws.String(key),
+		Body:          bytes.NewReader(data),
+		ContentLength: aws.Int64(int64(len(data))),
+		ContentMD5:    aws.String(base64.StdEncoding.EncodeToString(checksum[:])),
+	})
+	return err
+}
+
+// get reads an object.
+func (s *Store) get(key string) ([]byte, error) {
+	res, err := s.client.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		if aerr, isAWSErr := err.(awserr.Error); isAWSErr && aerr.Code() == s3.ErrCodeNoSuchKey {
+			return nil, errors.New("not found")
+		}
+		return nil, err
+	}
+	defer res.Body.Close()
+	return ioutil.ReadAll(res.Body)
+}
+
+// keyPrefix returns the prefix for all keys in the store.
+func (s *Store) keyPrefix() string {
+	if s.prefix == "" {
+		return ""
+	}
+	return s.prefix + "/"
+}
+
+func (s *Store) walletKeyPrefix(walletID uuid.UUID) string {
+	return fmt.Sprintf("%s%s/", s.keyPrefix(), walletID.String())
+}
+
+func (s *Store) walletHeaderKey(walletID uuid.UUID) string {
+	return s.walletKeyPrefix(walletID) + walletID.String()
+}
+
+func (s *Store) accountKey(walletID uuid.UUID, accountID uuid.UUID) string {
+	return s.walletKeyPrefix(walletID) + accountID.String()
+}
+
+func (s *Store) indexKey(walletID uuid.UUID) string {
+	return s.walletKeyPrefix(walletID) + "index"
+}
+
+// encryptIfRequired encrypts data if required.
+func (s *Store) encryptIfRequired(data []byte) ([]byte, error) {
+	if len(data) == 0 || len(s.passphrase) == 0 {
+		return data, nil
+	}
+	return ecodec.Encrypt(data, s.passphrase)
+}
+
+// decryptIfRequired decrypts data if required.
+func (s *Store) decryptIfRequired(data []byte) ([]byte, error) {
+	if len(data) == 0 || len(s.passphrase) == 0 {
+		return data, nil
+	}
+	return ecodec.Decrypt(data, s.passphrase)
+}
@@ -0,0 +1,343 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"errors"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	dirks3 "github.com/attestantio/dirk/stores/s3"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+	e2types "github.com/wealdtech/go-eth2-types/v2"
+	e2wallet "github.com/wealdtech/go-eth2-wallet"
+	e2wtypes "github.com/wealdtech/go-eth2-wallet-types/v2"
+)
+
+func TestMain(m *testing.M) {
+	if err := e2types.InitBLS(); err != nil {
+		os.Exit(1)
+	}
+	os.Exit(m.Run())
+}
+
+// memClient is an in-memory S3 client holding a single bucket.
+type memClient struct {
+	s3iface.S3API
+	bucket string
+	// pageSize is the maximum number of entries returned in each page of a listing.
+	pageSize int
+	mu       sync.Mutex
+	objects  map[string][]byte
+}
+
+func newMemClient(bucket string) *memClient {
+	return &memClient{
+		bucket:   bucket,
+		pageSize: 2,
+		objects:  make(map[string][]byte),
+	}
+}
+
+func (c *memClient) checkBucket(bucket *string) error {
+	if aws.StringValue(bucket) != c.bucket {
+		return awserr.New(s3.ErrCodeNoSuchBucket, "no such bucket", nil)
+	}
+	return nil
+}
+
+func (c *memClient) HeadBucketWithContext(_ aws.Context, input *s3.HeadBucketInput, _ ...request.Option) (*s3.HeadBucketOutput, error) {
+	if err := c.checkBucket(input.Bucket); err != nil {
+		return nil, err
+	}
+	return &s3.HeadBucketOutput{}, nil
+}
+
+func (c *memClient) PutObject(input *s3.PutObjectInput) (*s3.PutObjectOutput, error) {
+	if err := c.checkBucket(input.Bucket); err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadAll(input.Body)
+	if err != nil {
+		return nil, err
+	}
+	checksum := md5.Sum(data)
+	if aws.StringValue(input.ContentMD5) != base64.StdEncoding.EncodeToString(checksum[:]) {
+		return nil, errors.New("bad digest")
+	}
+	c.mu.Lock()
+	c.objects[aws.StringValue(input.Key)] = data
+	c.mu.Unlock()
+	return &s3.PutObjectOutput{}, nil
+}
+
+func (c *memClient) GetObject(input *s3.GetObjectInput) (*s3.GetObjectOutput, error) {
+	if err := c.checkBucket(input.Bucket); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	data, exists := c.objects[aws.StringValue(input.Key)]
+	c.mu.Unlock()
+	if !exists {
+		return nil, awserr.New(s3.ErrCodeNoSuchKey, "no such key", nil)
+	}
+	return &s3.GetObjectOutput{Body: ioutil.NopCloser(bytes.NewReader(data))}, nil
+}
+
+func (c *memClient) DeleteObject(input *s3.DeleteObjectInput) (*s3.DeleteObjectOutput, error) {
+	if err := c.checkBucket(input.Bucket); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	delete(c.objects, aws.StringValue(input.Key))
+	c.mu.Unlock()
+	return &s3.DeleteObjectOutput{}, nil
+}
+
+func (c *memClient) ListObjectsV2Pages(input *s3.ListObjectsV2Input, fn func(*s3.ListObjectsV2Output, bool) bool) error {
+	if err := c.checkBucket(input.Bucket); err != nil {
+		return err
+	}
+	prefix := aws.StringValue(input.Prefix)
+	delimiter := aws.StringValue(input.Delimiter)
+
+	// Build the full listing, then return it in pages.
+	c.mu.Lock()
+	keys := make([]string, 0)
+	commonPrefixes := make(map[string]struct{})
+	for key := range c.objects {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		if delimiter != "" {
+			if i := strings.Index(key[len(prefix):], delimiter); i >= 0 {
+				commonPrefixes[key[:len(prefix)+i+1]] = struct{}{}
+				continue
+			}
+		}
+		keys = append(keys, key)
+	}
+	c.mu.Unlock()
+	for commonPrefix := range commonPrefixes {
+		keys = append(keys, commonPrefix)
+	}
+	sort.Strings(keys)
+
+	for start := 0; start < len(keys) || start == 0; start += c.pageSize {
+		end := start + c.pageSize
+		if end > len(keys) {
+			end = len(keys)
+		}
+		page := &s3.ListObjectsV2Output{}
+		for _, key := range keys[start:end] {
+			if _, isCommonPrefix := commonPrefixes[key]; isCommonPrefix {
+				page.CommonPrefixes = append(page.CommonPrefixes, &s3.CommonPrefix{Prefix: aws.String(key)})
+			} else {
+				page.Contents = append(page.Contents, &s3.Object{Key: aws.String(key)})
+			}
+		}
+		if !fn(page, end == len(keys)) || end == len(keys) {
+			break
+		}
+	}
+	return nil
+}
+
+func TestNew(t *testing.T) {
+	ctx := context.Background()
+	client := newMemClient("wallets")
+
+	tests := []struct {
+		name   string
+		params []dirks3.Parameter
+		err    string
+	}{
+		{
+			name: "BucketMissing",
+			params: []dirks3.Parameter{
+				dirks3.WithLogLevel(zerolog.Disabled),
+				dirks3.WithClient(client),
+			},
+			err: "problem with parameters: no bucket specified",
+		},
+		{
+			name: "RegionMissing",
+			params: []dirks3.Parameter{
+				dirks3.WithLogLevel(zerolog.Disabled),
+				dirks3.WithClient(client),
+				dirks3.WithBucket("wallets"),
+				dirks3.WithRegion(""),
+			},
+			err: "problem with parameters: no region specified",
+		},
+		{
+			name: "SecretAccessKeyMissing",
+			params: []dirks3.Parameter{
+				dirks3.WithLogLevel(zerolog.Disabled),
+				dirks3.WithBucket("wallets"),
+				dirks3.WithCredentials("id", ""),
+			},
+			err: "problem with parameters: access key ID and secret access key must be specified together",
+		},
+		{
+			name: "BucketInaccessible",
+			params: []dirks3.Parameter{
+				dirks3.WithLogLevel(zerolog.Disabled),
+				dirks3.WithClient(client),
+				dirks3.WithBucket("other"),
+			},
+			err: "failed to access bucket other: NoSuchBucket: no such bucket",
+		},
+		{
+			name: "Good",
+			params: []dirks3.Parameter{
+				dirks3.WithLogLevel(zerolog.Disabled),
+				dirks3.WithClient(client),
+				dirks3.WithBucket("wallets"),
+				dirks3.WithPrefix("/dirk/"),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store, err := dirks3.New(ctx, test.params...)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, "s3", store.Name())
+				require.Equal(t, "wallets/dirk", store.Location())
+			}
+		})
+	}
+}
+
+func TestWalletsAndAccounts(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		prefix     string
+		passphrase []byte
+	}{
+		{
+			name: "NoPrefix",
+		},
+		{
+			name:   "Prefix",
+			prefix: "dirk/instance1",
+		},
+		{
+			name:       "Encrypted",
+			prefix:     "dirk",
+			passphrase: []byte("store secret"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client := newMemClient("wallets")
+			// Objects outside of the prefix should be ignored.
+			client.objects["other/object"] = []byte("data")
+			store, err := dirks3.New(ctx,
+				dirks3.WithLogLevel(zerolog.Disabled),
+				dirks3.WithClient(client),
+				dirks3.WithBucket("wallets"),
+				dirks3.WithPrefix(test.prefix),
+				dirks3.WithPassphrase(test.passphrase),
+			)
+			require.NoError(t, err)
+
+			// Create more wallets and accounts than fit in a single page of a listing.
+			accounts := make(map[string]e2wtypes.Account)
+			for _, walletName := range []string{"Wallet 1", "Wallet 2", "Wallet 3"} {
+				wallet, err := e2wallet.CreateWallet(walletName, e2wallet.WithStore(store), e2wallet.WithType("nd"))
+				require.NoError(t, err)
+				require.NoError(t, wallet.(e2wtypes.WalletLocker).Unlock(ctx, nil))
+				for _, accountName := range []string{"Account 1", "Account 2", "Account 3"} {
+					account, err := wallet.(e2wtypes.WalletAccountCreator).CreateAccount(ctx, accountName, []byte("pass"))
+					require.NoError(t, err)
+					accounts[walletName+"/"+accountName] = account
+				}
+			}
+			if len(test.passphrase) > 0 {
+				for key, data := range client.objects {
+					if strings.HasPrefix(key, "dirk/") && !strings.HasSuffix(key, "/index") {
+						require.NotContains(t, string(data), "Wallet", key)
+					}
+				}
+			}
+
+			// Reopen the store to confirm everything is read back from the bucket.
+			store, err = dirks3.New(ctx,
+				dirks3.WithLogLevel(zerolog.Disabled),
+				dirks3.WithClient(client),
+				dirks3.WithBucket("wallets"),
+				dirks3.WithPrefix(test.prefix),
+				dirks3.WithPassphrase(test.passphrase),
+			)
+			require.NoError(t, err)
+			wallets := 0
+			for wallet := range e2wallet.Wallets(e2wallet.WithStore(store)) {
+				wallets++
+				found := 0
+				for account := range wallet.Accounts(ctx) {
+					expected, exists := accounts[wallet.Name()+"/"+account.Name()]
+					require.True(t, exists)
+					require.Equal(t, expected.PublicKey().Marshal(), account.PublicKey().Marshal())
+					found++
+				}
+				require.Equal(t, 3, found)
+			}
+			require.Equal(t, 3, wallets)
+
+			wallet, err := e2wallet.OpenWallet("Wallet 2", e2wallet.WithStore(store))
+			require.NoError(t, err)
+			account, err := wallet.(e2wtypes.WalletAccountByNameProvider).AccountByName(ctx, "Account 3")
+			require.NoError(t, err)
+			require.Equal(t, accounts["Wallet 2/Account 3"].PublicKey().Marshal(), account.PublicKey().Marshal())
+
+			// Delete an account.
+			require.NoError(t, store.DeleteAccount(wallet.ID(), account.ID()))
+			_, err = store.RetrieveAccount(wallet.ID(), account.ID())
+			require.EqualError(t, err, "not found")
+			found := 0
+			for range store.RetrieveAccounts(wallet.ID()) {
+				found++
+			}
+			require.Equal(t, 2, found)
+
+			_, err = store.RetrieveWalletByID(uuid.New())
+			require.EqualError(t, err, "not found")
+			_, err = store.RetrieveWallet("Unknown")
+			require.EqualError(t, err, "wallet not found")
+		})
+	}
+}
@@ -28,12 +28,15 @@ import (
 
 // Setup sets up a number of well-known accounts in a store.
 func Setup(ctx context.Context) (e2wtypes.Store, error) {
+	return SetupWithStore(ctx, scratch.New())
+}
+
+// SetupWithStore sets up a number of well-known accounts in the supplied store.
+func SetupWithStore(ctx context.Context, store e2wtypes.Store) (e2wtypes.Store, error) {
 	if err := e2types.InitBLS(); err != nil {
 		return nil, errors.Wrap(err, "failed to setup BLS")
 	}
 
-	// Create a store.
-	store := scratch.New()
 	encryptor := keystorev4.New()
 
 	seed := []byte{
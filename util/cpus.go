@@ -0,0 +1,81 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"io/ioutil"
+	"math"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// cgroupRoot is the location of the cgroup filesystem.
+const cgroupRoot = "/sys/fs/cgroup"
+
+// AvailableCPUs returns the number of CPUs available to the process.  This is
+// the number of CPUs on the host, reduced to the cgroup CPU limit if there is
+// one, so that containerized processes do not over-schedule.
+func AvailableCPUs() int {
+	cpus := runtime.NumCPU()
+	if limit := CgroupCPULimit(cgroupRoot); limit > 0 {
+		limited := int(math.Ceil(limit))
+		if limited < cpus {
+			cpus = limited
+		}
+	}
+	return cpus
+}
+
+// CgroupCPULimit returns the CPU limit, in CPUs, imposed by the cgroup
+// filesystem at the given root.  Both cgroup v2 and v1 are supported.  It
+// returns 0 if there is no limit.
+func CgroupCPULimit(root string) float64 {
+	// cgroup v2: "<quota> <period>", where quota can be "max".
+	if data, err := ioutil.ReadFile(filepath.Join(root, "cpu.max")); err == nil {
+		fields := strings.Fields(string(data))
+		if len(fields) != 2 || fields[0] == "max" {
+			return 0
+		}
+		return cpuLimit(fields[0], fields[1])
+	}
+
+	// cgroup v1: separate quota and period files, with a quota of -1 for no limit.
+	for _, dir := range []string{"cpu", "cpu,cpuacct", "cpuacct,cpu"} {
+		quota, err := ioutil.ReadFile(filepath.Join(root, dir, "cpu.cfs_quota_us"))
+		if err != nil {
+			continue
+		}
+		period, err := ioutil.ReadFile(filepath.Join(root, dir, "cpu.cfs_period_us"))
+		if err != nil {
+			continue
+		}
+		return cpuLimit(strings.TrimSpace(string(quota)), strings.TrimSpace(string(period)))
+	}
+
+	return 0
+}
+
+func cpuLimit(quota string, period string) float64 {
+	q, err := strconv.ParseFloat(quota, 64)
+	if err != nil || q <= 0 {
+		return 0
+	}
+	p, err := strconv.ParseFloat(period, 64)
+	if err != nil || p <= 0 {
+		return 0
+	}
+	return q / p
+}
@@ -0,0 +1,90 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/attestantio/dirk/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCgroupCPULimit(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		limit float64
+	}{
+		{
+			name: "None",
+		},
+		{
+			name:  "V2Limited",
+			files: map[string]string{"cpu.max": "250000 100000\n"},
+			limit: 2.5,
+		},
+		{
+			name:  "V2Unlimited",
+			files: map[string]string{"cpu.max": "max 100000\n"},
+		},
+		{
+			name:  "V2Invalid",
+			files: map[string]string{"cpu.max": "bad\n"},
+		},
+		{
+			name: "V1Limited",
+			files: map[string]string{
+				"cpu/cpu.cfs_quota_us":  "400000\n",
+				"cpu/cpu.cfs_period_us": "100000\n",
+			},
+			limit: 4,
+		},
+		{
+			name: "V1Combined",
+			files: map[string]string{
+				"cpu,cpuacct/cpu.cfs_quota_us":  "50000\n",
+				"cpu,cpuacct/cpu.cfs_period_us": "100000\n",
+			},
+			limit: 0.5,
+		},
+		{
+			name: "V1Unlimited",
+			files: map[string]string{
+				"cpu/cpu.cfs_quota_us":  "-1\n",
+				"cpu/cpu.cfs_period_us": "100000\n",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			root, err := ioutil.TempDir("", "")
+			require.NoError(t, err)
+			defer os.RemoveAll(root)
+			for name, contents := range test.files {
+				path := filepath.Join(root, name)
+				require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+				require.NoError(t, ioutil.WriteFile(path, []byte(contents), 0600))
+			}
+			require.Equal(t, test.limit, util.CgroupCPULimit(root))
+		})
+	}
+}
+
+func TestAvailableCPUs(t *testing.T) {
+	require.Greater(t, util.AvailableCPUs(), 0)
+}
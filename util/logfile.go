@@ -0,0 +1,191 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// backupTimeFormat is the format of the timestamp in the names of rotated log files.
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// LogFile is a log file that can be reopened, for use with external log
+// rotation, and can optionally rotate itself when it reaches a given size.
+type LogFile struct {
+	mu         sync.Mutex
+	path       string
+	maxSize    int64
+	maxAge     time.Duration
+	maxBackups int
+	file       *os.File
+	size       int64
+}
+
+// NewLogFile opens a log file at the given path for appending.
+// If maxSizeMB is greater than 0 the file is rotated when it reaches that
+// size, with rotated files kept alongside it.  Rotated files older than
+// maxAgeDays, or beyond the newest maxBackups, are removed; a value of 0
+// for either means no limit.
+func NewLogFile(path string, maxSizeMB int, maxAgeDays int, maxBackups int) (*LogFile, error) {
+	if path == "" {
+		return nil, errors.New("no path supplied")
+	}
+	if maxSizeMB < 0 {
+		return nil, errors.New("maximum size cannot be negative")
+	}
+	if maxAgeDays < 0 {
+		return nil, errors.New("maximum age cannot be negative")
+	}
+	if maxBackups < 0 {
+		return nil, errors.New("maximum backups cannot be negative")
+	}
+	l := &LogFile{
+		path:       path,
+		maxSize:    int64(maxSizeMB) * 1024 * 1024,
+		maxAge:     time.Duration(maxAgeDays) * 24 * time.Hour,
+		maxBackups: maxBackups,
+	}
+	if err := l.open(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// Write writes to the log file, rotating it first if required.
+func (l *LogFile) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.maxSize > 0 && l.size > 0 && l.size+int64(len(p)) > l.maxSize {
+		if err := l.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := l.file.Write(p)
+	l.size += int64(n)
+	return n, err
+}
+
+// Reopen reopens the log file, for use after the file has been moved by
+// external log rotation.  If the file cannot be reopened the existing file
+// continues to be used.
+func (l *LogFile) Reopen() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	previous := l.file
+	if err := l.open(); err != nil {
+		return err
+	}
+	return previous.Close()
+}
+
+// Close closes the log file.
+func (l *LogFile) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.file.Close()
+}
+
+// open opens the log file.  This must be called with the lock held.
+func (l *LogFile) open() error {
+	file, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return errors.Wrap(err, "failed to open log file")
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return errors.Wrap(err, "failed to obtain log file information")
+	}
+	l.file = file
+	l.size = info.Size()
+	return nil
+}
+
+// rotate moves the current log file to a backup and opens a new log file.
+// This must be called with the lock held.
+func (l *LogFile) rotate() error {
+	if err := l.file.Close(); err != nil {
+		return errors.Wrap(err, "failed to close log file")
+	}
+	rotated := time.Now()
+	backupPath := l.backupPath(rotated)
+	for {
+		// Avoid overwriting an existing backup if rotating more than once in the same millisecond.
+		if _, err := os.Stat(backupPath); os.IsNotExist(err) {
+			break
+		}
+		rotated = rotated.Add(time.Millisecond)
+		backupPath = l.backupPath(rotated)
+	}
+	if err := os.Rename(l.path, backupPath); err != nil {
+		return errors.Wrap(err, "failed to move log file")
+	}
+	if err := l.open(); err != nil {
+		return err
+	}
+	// Failure to remove old backups does not affect logging.
+	_ = l.prune(time.Now())
+	return nil
+}
+
+// backupPath returns the path of the backup for a log file rotated at the given time.
+func (l *LogFile) backupPath(rotated time.Time) string {
+	ext := filepath.Ext(l.path)
+	return strings.TrimSuffix(l.path, ext) + "-" + rotated.UTC().Format(backupTimeFormat) + ext
+}
+
+// prune removes backups that are too old or too many.
+func (l *LogFile) prune(now time.Time) error {
+	if l.maxAge == 0 && l.maxBackups == 0 {
+		return nil
+	}
+	ext := filepath.Ext(l.path)
+	prefix := strings.TrimSuffix(l.path, ext) + "-"
+	matches, err := filepath.Glob(prefix + "*" + ext)
+	if err != nil {
+		return err
+	}
+	type backup struct {
+		path    string
+		rotated time.Time
+	}
+	backups := make([]*backup, 0, len(matches))
+	for _, match := range matches {
+		rotated, err := time.Parse(backupTimeFormat, strings.TrimSuffix(strings.TrimPrefix(match, prefix), ext))
+		if err != nil {
+			// Not one of our backups.
+			continue
+		}
+		backups = append(backups, &backup{path: match, rotated: rotated})
+	}
+	// Newest first.
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].rotated.After(backups[j].rotated)
+	})
+	for i, backup := range backups {
+		if (l.maxBackups > 0 && i >= l.maxBackups) || (l.maxAge > 0 && now.Sub(backup.rotated) > l.maxAge) {
+			if err := os.Remove(backup.path); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/attestantio/dirk/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogFileRotate(t *testing.T) {
+	base, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(base)
+	path := filepath.Join(base, "dirk.log")
+
+	logFile, err := util.NewLogFile(path, 1, 0, 2)
+	require.NoError(t, err)
+	defer logFile.Close()
+
+	// Each write is just over half of the maximum size, so each write after the first rotates.
+	entry := bytes.Repeat([]byte{'a'}, 512*1024+1)
+	for i := 0; i < 4; i++ {
+		_, err := logFile.Write(entry)
+		require.NoError(t, err)
+	}
+
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, entry, data)
+
+	// Only the newest backups are kept.
+	backups, err := filepath.Glob(filepath.Join(base, "dirk-*.log"))
+	require.NoError(t, err)
+	require.Len(t, backups, 2)
+}
+
+func TestLogFileReopen(t *testing.T) {
+	base, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(base)
+	path := filepath.Join(base, "dirk.log")
+
+	logFile, err := util.NewLogFile(path, 0, 0, 0)
+	require.NoError(t, err)
+	defer logFile.Close()
+
+	_, err = logFile.Write([]byte("first\n"))
+	require.NoError(t, err)
+
+	// Move the file, as external log rotation would.
+	require.NoError(t, os.Rename(path, path+".1"))
+	_, err = logFile.Write([]byte("second\n"))
+	require.NoError(t, err)
+	require.NoError(t, logFile.Reopen())
+	_, err = logFile.Write([]byte("third\n"))
+	require.NoError(t, err)
+
+	data, err := ioutil.ReadFile(path + ".1")
+	require.NoError(t, err)
+	require.Equal(t, "first\nsecond\n", string(data))
+	data, err = ioutil.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "third\n", string(data))
+}
+
+func TestLogFileBadParameters(t *testing.T) {
+	_, err := util.NewLogFile("", 0, 0, 0)
+	require.EqualError(t, err, "no path supplied")
+	_, err = util.NewLogFile("dirk.log", -1, 0, 0)
+	require.EqualError(t, err, "maximum size cannot be negative")
+}
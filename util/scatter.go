@@ -27,7 +27,9 @@ type ScatterResult struct {
 	Extent interface{}
 }
 
-// Scatter scatters a computation across multiple goroutines, returning a set of per-worker results
+// Scatter scatters a computation across multiple goroutines, returning a set of per-worker results.
+// If a worker panics then the panic is raised again in the calling goroutine once all workers have
+// completed, allowing the caller to recover from it and release any resources that it holds.
 func Scatter(inputLen int, work func(int, int, *sync.RWMutex) (interface{}, error)) ([]*ScatterResult, error) {
 	if inputLen <= 0 {
 		return nil, errors.New("no data with which to work")
@@ -43,6 +45,8 @@ func Scatter(inputLen int, work func(int, int, *sync.RWMutex) (interface{}, erro
 	defer close(resultCh)
 	errorCh := make(chan error, workers)
 	defer close(errorCh)
+	panicCh := make(chan interface{}, workers)
+	defer close(panicCh)
 	mutex := new(sync.RWMutex)
 	for worker := 0; worker < workers; worker++ {
 		offset := worker * extentSize
@@ -51,6 +55,11 @@ func Scatter(inputLen int, work func(int, int, *sync.RWMutex) (interface{}, erro
 			entries = inputLen - offset
 		}
 		go func(offset int, entries int) {
+			defer func() {
+				if r := recover(); r != nil {
+					panicCh <- r
+				}
+			}()
 			extent, err := work(offset, entries, mutex)
 			if err != nil {
 				errorCh <- err
@@ -66,6 +75,7 @@ func Scatter(inputLen int, work func(int, int, *sync.RWMutex) (interface{}, erro
 	// Collect results from workers
 	results := make([]*ScatterResult, workers)
 	var err error
+	var panicked interface{}
 	for i := 0; i < workers; i++ {
 		select {
 		case result := <-resultCh:
@@ -73,8 +83,15 @@ func Scatter(inputLen int, work func(int, int, *sync.RWMutex) (interface{}, erro
 		case err = <-errorCh:
 			// Error occurred; don't return because that closes the channels
 			// and can cause other workers to write to the closed channel.
+		case r := <-panicCh:
+			if panicked == nil {
+				panicked = r
+			}
 		}
 	}
+	if panicked != nil {
+		panic(panicked)
+	}
 	return results, err
 }
 
@@ -114,3 +114,22 @@ func TestError(t *testing.T) {
 	})
 	require.EqualError(t, err, "bad number")
 }
+
+func TestPanic(t *testing.T) {
+	totalRuns := 1024
+	val := 0
+	require.PanicsWithValue(t, "bad number", func() {
+		_, _ = util.Scatter(totalRuns, func(offset int, entries int, mu *sync.RWMutex) (interface{}, error) {
+			for i := 0; i < entries; i++ {
+				mu.Lock()
+				val++
+				if val == 1011 {
+					mu.Unlock()
+					panic("bad number")
+				}
+				mu.Unlock()
+			}
+			return nil, nil
+		})
+	})
+}
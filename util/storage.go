@@ -0,0 +1,57 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"io/ioutil"
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+// CheckWritable confirms that the given existing directory can be written
+// to, by creating, syncing and removing a probe file within it.
+func CheckWritable(path string) error {
+	if path == "" {
+		return errors.New("no path supplied")
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return errors.Wrapf(err, "failed to access directory %s", path)
+	}
+	if !info.IsDir() {
+		return errors.Errorf("%s is not a directory", path)
+	}
+	probe, err := ioutil.TempFile(path, ".dirk-write-probe-*")
+	if err != nil {
+		return errors.Wrapf(err, "directory %s is not writable", path)
+	}
+	probeName := probe.Name()
+	defer os.Remove(probeName)
+	if _, err := probe.Write([]byte{0x00}); err != nil {
+		probe.Close()
+		return errors.Wrapf(err, "failed to write probe file %s", probeName)
+	}
+	if err := probe.Sync(); err != nil {
+		probe.Close()
+		return errors.Wrapf(err, "failed to sync probe file %s", probeName)
+	}
+	if err := probe.Close(); err != nil {
+		return errors.Wrapf(err, "failed to close probe file %s", probeName)
+	}
+	if err := os.Remove(probeName); err != nil {
+		return errors.Wrapf(err, "failed to remove probe file %s", probeName)
+	}
+	return nil
+}
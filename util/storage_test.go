@@ -0,0 +1,90 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/attestantio/dirk/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckWritable(t *testing.T) {
+	base, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(base)
+
+	readOnly := filepath.Join(base, "readonly")
+	require.NoError(t, os.Mkdir(readOnly, 0o500))
+	defer os.Chmod(readOnly, 0o700)
+
+	writable := filepath.Join(base, "writable")
+	require.NoError(t, os.Mkdir(writable, 0o700))
+
+	file := filepath.Join(base, "file")
+	require.NoError(t, ioutil.WriteFile(file, []byte{0x00}, 0o600))
+
+	tests := []struct {
+		name string
+		path string
+		err  string
+	}{
+		{
+			name: "Empty",
+			err:  "no path supplied",
+		},
+		{
+			name: "Missing",
+			path: filepath.Join(base, "missing"),
+			err:  "failed to access directory",
+		},
+		{
+			name: "NotDirectory",
+			path: file,
+			err:  "is not a directory",
+		},
+		{
+			name: "Good",
+			path: writable,
+		},
+		{
+			name: "ReadOnly",
+			path: readOnly,
+			err:  "is not writable",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if test.name == "ReadOnly" && os.Geteuid() == 0 {
+				t.Skip("permissions are not enforced for root")
+			}
+			err := util.CheckWritable(test.path)
+			if test.err != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), test.err)
+				require.Contains(t, err.Error(), test.path)
+			} else {
+				require.NoError(t, err)
+				// Probe file must have been removed.
+				files, err := ioutil.ReadDir(test.path)
+				require.NoError(t, err)
+				require.Len(t, files, 0)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"crypto/tls"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// TLSVersion parses a TLS version as supplied in configuration.  An empty
+// version returns TLS 1.3.
+func TLSVersion(version string) (uint16, error) {
+	switch strings.TrimPrefix(strings.ToLower(version), "tls") {
+	case "", "1.3":
+		return tls.VersionTLS13, nil
+	case "1.2":
+		return tls.VersionTLS12, nil
+	default:
+		return 0, errors.Errorf("unknown TLS version %q; must be 1.2 or 1.3", version)
+	}
+}
+
+// TLSCipherSuites parses a list of TLS cipher suites as supplied in
+// configuration, using the names defined by the Go TLS library, for example
+// TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384.  Only secure cipher suites that
+// can be configured, which are those for TLS 1.2, are accepted.
+func TLSCipherSuites(names []string) ([]uint16, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	available := make(map[string]uint16)
+	for _, suite := range tls.CipherSuites() {
+		for _, version := range suite.SupportedVersions {
+			if version == tls.VersionTLS12 {
+				available[suite.Name] = suite.ID
+				break
+			}
+		}
+	}
+	insecure := make(map[string]bool)
+	for _, suite := range tls.InsecureCipherSuites() {
+		insecure[suite.Name] = true
+	}
+
+	suites := make([]uint16, 0, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if insecure[name] {
+			return nil, errors.Errorf("cipher suite %s is insecure", name)
+		}
+		id, exists := available[name]
+		if !exists {
+			return nil, errors.Errorf("unknown TLS 1.2 cipher suite %q", name)
+		}
+		suites = append(suites, id)
+	}
+	return suites, nil
+}
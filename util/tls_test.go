@@ -0,0 +1,118 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util_test
+
+import (
+	"crypto/tls"
+	"testing"
+
+	"github.com/attestantio/dirk/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTLSVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		res     uint16
+		err     string
+	}{
+		{
+			name: "Default",
+			res:  tls.VersionTLS13,
+		},
+		{
+			name:    "1.2",
+			version: "1.2",
+			res:     tls.VersionTLS12,
+		},
+		{
+			name:    "1.3",
+			version: "1.3",
+			res:     tls.VersionTLS13,
+		},
+		{
+			name:    "Prefixed",
+			version: "TLS1.2",
+			res:     tls.VersionTLS12,
+		},
+		{
+			name:    "1.1",
+			version: "1.1",
+			err:     `unknown TLS version "1.1"; must be 1.2 or 1.3`,
+		},
+		{
+			name:    "Invalid",
+			version: "latest",
+			err:     `unknown TLS version "latest"; must be 1.2 or 1.3`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			res, err := util.TLSVersion(test.version)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, test.res, res)
+			}
+		})
+	}
+}
+
+func TestTLSCipherSuites(t *testing.T) {
+	tests := []struct {
+		name  string
+		names []string
+		res   []uint16
+		err   string
+	}{
+		{
+			name: "Empty",
+		},
+		{
+			name:  "Good",
+			names: []string{"TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384", " TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"},
+			res:   []uint16{tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384, tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256},
+		},
+		{
+			name:  "Unknown",
+			names: []string{"TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384", "TLS_MADE_UP"},
+			err:   `unknown TLS 1.2 cipher suite "TLS_MADE_UP"`,
+		},
+		{
+			name:  "TLS13",
+			names: []string{"TLS_AES_128_GCM_SHA256"},
+			err:   `unknown TLS 1.2 cipher suite "TLS_AES_128_GCM_SHA256"`,
+		},
+		{
+			name:  "Insecure",
+			names: []string{"TLS_RSA_WITH_RC4_128_SHA"},
+			err:   "cipher suite TLS_RSA_WITH_RC4_128_SHA is insecure",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			res, err := util.TLSCipherSuites(test.names)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, test.res, res)
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/attestantio/dirk/core"
+	"github.com/attestantio/dirk/services/checker"
+	staticchecker "github.com/attestantio/dirk/services/checker/static"
+	"github.com/attestantio/dirk/services/ruler"
+	"github.com/attestantio/dirk/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	"github.com/wealdtech/go-majordomo"
+)
+
+// configProblem is a problem found when validating configuration.  Fatal
+// problems would stop Dirk from starting, or from serving requests as
+// configured.
+type configProblem struct {
+	area  string
+	fatal bool
+	err   error
+}
+
+// validateConfig is a command to load the full configuration and report any
+// problems with it.  It does not start any listeners.
+func validateConfig(ctx context.Context, majordomo majordomo.Service) {
+	ctx, cancel := context.WithCancel(ctx)
+	problems := checkConfig(ctx, majordomo)
+	cancel()
+
+	fatal := 0
+	for _, problem := range problems {
+		severity := "warning"
+		if problem.fatal {
+			severity = "error"
+			fatal++
+		}
+		fmt.Printf("%s: %s: %v\n", severity, problem.area, problem.err)
+	}
+	fmt.Printf("Configuration validated; %d errors and %d warnings found\n", fatal, len(problems)-fatal)
+	if fatal > 0 {
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+// checkConfig checks each area of the configuration in turn, returning all
+// problems found rather than stopping at the first.
+func checkConfig(ctx context.Context, majordomo majordomo.Service) []*configProblem {
+	problems := make([]*configProblem, 0)
+	problems = append(problems, checkServerConfig()...)
+	problems = append(problems, checkCertificatesConfig(ctx, majordomo)...)
+	problems = append(problems, checkStoresConfig(ctx, majordomo)...)
+	problems = append(problems, checkUnlockerConfig(ctx, majordomo)...)
+	problems = append(problems, checkPermissionsConfig(ctx)...)
+	problems = append(problems, checkPeersConfig(ctx)...)
+	return problems
+}
+
+func checkServerConfig() []*configProblem {
+	problems := make([]*configProblem, 0)
+	if viper.GetString("server.name") == "" {
+		problems = append(problems, &configProblem{area: "server", fatal: true, err: errors.New("no server name set")})
+	}
+	if _, err := strconv.ParseUint(viper.GetString("server.id"), 10, 64); err != nil {
+		problems = append(problems, &configProblem{area: "server", fatal: true, err: errors.Wrap(err, "invalid server ID")})
+	}
+	if len(viper.GetStringSlice("server.listen-address")) == 0 && !viper.IsSet("server.listeners") {
+		problems = append(problems, &configProblem{area: "server", fatal: true, err: errors.New("no listen address set")})
+	}
+	if _, err := util.TLSVersion(viper.GetString("server.tls.min-version")); err != nil {
+		problems = append(problems, &configProblem{area: "server", fatal: true, err: errors.Wrap(err, "invalid server.tls.min-version")})
+	}
+	if _, err := util.TLSCipherSuites(viper.GetStringSlice("server.tls.cipher-suites")); err != nil {
+		problems = append(problems, &configProblem{area: "server", fatal: true, err: errors.Wrap(err, "invalid server.tls.cipher-suites")})
+	}
+	return problems
+}
+
+func checkCertificatesConfig(ctx context.Context, majordomo majordomo.Service) []*configProblem {
+	certPEMBlock, keyPEMBlock, caPEMBlock, err := fetchCertificates(ctx, majordomo)
+	if err != nil {
+		return []*configProblem{{area: "certificates", fatal: true, err: err}}
+	}
+	problems := make([]*configProblem, 0)
+	if _, err := tls.X509KeyPair(certPEMBlock, keyPEMBlock); err != nil {
+		problems = append(problems, &configProblem{area: "certificates", fatal: true, err: errors.Wrap(err, "invalid server certificate or key")})
+	}
+	if caPEMBlock != nil && !x509.NewCertPool().AppendCertsFromPEM(caPEMBlock) {
+		problems = append(problems, &configProblem{area: "certificates", fatal: true, err: errors.New("no certificates found in CA certificate")})
+	}
+	return problems
+}
+
+func checkStoresConfig(ctx context.Context, majordomo majordomo.Service) []*configProblem {
+	problems := make([]*configProblem, 0)
+	storesCfg := &core.Stores{}
+	if err := viper.Unmarshal(&storesCfg); err != nil {
+		return []*configProblem{{area: "stores", fatal: true, err: errors.Wrap(err, "failed to obtain stores configuration")}}
+	}
+	// Filesystem stores are created on demand, so a location that cannot be
+	// reached would otherwise only show up as missing accounts.
+	for _, store := range storesCfg.Stores {
+		if store.Type != "filesystem" || store.Location == "" {
+			continue
+		}
+		if _, err := os.Stat(store.Location); err != nil {
+			problems = append(problems, &configProblem{area: "stores", fatal: true, err: errors.Wrapf(err, "location of store %q is not accessible", store.Name)})
+		} else if err := util.CheckWritable(store.Location); err != nil {
+			problems = append(problems, &configProblem{area: "stores", err: errors.Wrapf(err, "store %q is not writable; account creation will fail", store.Name)})
+		}
+	}
+	if _, err := initStores(ctx, majordomo); err != nil {
+		problems = append(problems, &configProblem{area: "stores", fatal: true, err: err})
+	}
+	return problems
+}
+
+func checkUnlockerConfig(ctx context.Context, majordomo majordomo.Service) []*configProblem {
+	if _, err := startUnlocker(ctx, majordomo, nil); err != nil {
+		return []*configProblem{{area: "unlocker", fatal: true, err: err}}
+	}
+	return nil
+}
+
+func checkPermissionsConfig(ctx context.Context) []*configProblem {
+	permissions, err := obtainPermissions()
+	if err != nil {
+		return []*configProblem{{area: "permissions", fatal: true, err: err}}
+	}
+	problems := make([]*configProblem, 0)
+	if len(permissions) == 0 {
+		problems = append(problems, &configProblem{area: "permissions", err: errors.New("no permissions set; all requests will be denied")})
+	}
+	for _, unknown := range checker.UnknownOperations(permissions, ruler.Actions) {
+		problems = append(problems, &configProblem{area: "permissions", fatal: true, err: fmt.Errorf("unknown operation %s", unknown)})
+	}
+	// The checker validates paths and allowed IPs.
+	if _, err := staticchecker.New(ctx,
+		staticchecker.WithLogLevel(util.LogLevel("checker")),
+		staticchecker.WithPermissions(permissions),
+		staticchecker.WithAllowedIPs(viper.GetStringMapStringSlice("checker.allowed-ips")),
+	); err != nil {
+		problems = append(problems, &configProblem{area: "permissions", fatal: true, err: err})
+	}
+	return problems
+}
+
+func checkPeersConfig(ctx context.Context) []*configProblem {
+	// Peers are not probed, as that requires a connection to each of them.
+	peersSvc, err := startPeers(ctx, nil, nil)
+	if err != nil {
+		return []*configProblem{{area: "peers", fatal: true, err: err}}
+	}
+	serverID, err := strconv.ParseUint(viper.GetString("server.id"), 10, 64)
+	if err != nil {
+		// Already reported with the server configuration.
+		return nil
+	}
+	if _, err := peersSvc.Peer(serverID); err != nil {
+		return []*configProblem{{area: "peers", err: fmt.Errorf("server ID %d is not listed in peers; distributed accounts cannot be generated or used", serverID)}}
+	}
+	return nil
+}
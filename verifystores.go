@@ -0,0 +1,101 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/attestantio/dirk/services/fetcher/mem"
+	"github.com/attestantio/dirk/services/unlocker"
+	"github.com/attestantio/dirk/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	keystorev4 "github.com/wealdtech/go-eth2-wallet-encryptor-keystorev4"
+	e2wtypes "github.com/wealdtech/go-eth2-wallet-types/v2"
+	"github.com/wealdtech/go-majordomo"
+)
+
+// verifyStores is a command to check that every account in the configured
+// stores can be loaded and, if requested, decrypted with the unlocker's
+// passphrases.  It does not write to the stores.
+func verifyStores(ctx context.Context, majordomo majordomo.Service) {
+	stores, err := initStores(ctx, majordomo)
+	if err != nil {
+		fmt.Printf("%v\n", err)
+		os.Exit(1)
+	}
+
+	accounts, problems := mem.VerifyStores(ctx, stores, keystorev4.New())
+
+	if viper.GetBool("verify-stores-decrypt") {
+		unlockerSvc, err := startUnlocker(ctx, majordomo, nil)
+		if err != nil {
+			fmt.Printf("Failed to start unlocker: %v\n", err)
+			os.Exit(1)
+		}
+		// Decrypting keystores is expensive, so spread the work across CPUs.
+		_, err = util.Scatter(len(accounts), func(offset int, entries int, mu *sync.RWMutex) (interface{}, error) {
+			for i := offset; i < offset+entries; i++ {
+				if err := verifyDecrypt(ctx, unlockerSvc, accounts[i]); err != nil {
+					mu.Lock()
+					problems = append(problems, &mem.StoreProblem{
+						Store:   accounts[i].Store,
+						Wallet:  accounts[i].Wallet.Name(),
+						Account: accounts[i].Account.Name(),
+						Err:     err,
+					})
+					mu.Unlock()
+				}
+			}
+			return nil, nil
+		})
+		if err != nil {
+			fmt.Printf("Failed to decrypt accounts: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	for _, problem := range problems {
+		if problem.Account == "" {
+			fmt.Printf("%s: wallet %s: %v\n", problem.Store, problem.Wallet, problem.Err)
+		} else {
+			fmt.Printf("%s: account %s/%s: %v\n", problem.Store, problem.Wallet, problem.Account, problem.Err)
+		}
+	}
+	fmt.Printf("Verified %d accounts; %d problems found\n", len(accounts), len(problems))
+	if len(problems) > 0 {
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+// verifyDecrypt attempts to decrypt an account with the unlocker's passphrases,
+// relocking it afterwards.
+func verifyDecrypt(ctx context.Context, unlockerSvc unlocker.Service, account *mem.VerifiedAccount) error {
+	locker, isLocker := account.Account.(e2wtypes.AccountLocker)
+	if !isLocker {
+		return nil
+	}
+	unlocked, err := unlockerSvc.UnlockAccount(ctx, account.Wallet, account.Account)
+	if err != nil {
+		return err
+	}
+	if !unlocked {
+		return errors.New("no passphrase decrypts account")
+	}
+	return locker.Lock(ctx)
+}
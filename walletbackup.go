@@ -0,0 +1,103 @@
+// Copyright © 2021 Attestant Limited.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/attestantio/dirk/cmd"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	"github.com/wealdtech/go-majordomo"
+)
+
+// exportWallet exports the wallet given by export-wallet to wallet-backup-file.
+func exportWallet(ctx context.Context, majordomo majordomo.Service) {
+	if viper.GetString("wallet-backup-file") == "" {
+		fmt.Println("Wallet backup file required for export")
+		os.Exit(1)
+	}
+	passphrase, err := walletBackupPassphrase(ctx, majordomo)
+	if err != nil {
+		fmt.Printf("%v\n", err)
+		os.Exit(1)
+	}
+	stores, err := initStores(ctx, majordomo)
+	if err != nil {
+		fmt.Printf("%v\n", err)
+		os.Exit(1)
+	}
+
+	data, err := cmd.ExportWallet(ctx, stores, viper.GetString("export-wallet"), passphrase)
+	if err != nil {
+		fmt.Printf("Failed to export wallet: %v\n", err)
+		os.Exit(1)
+	}
+	if err := ioutil.WriteFile(viper.GetString("wallet-backup-file"), data, 0600); err != nil {
+		fmt.Printf("Failed to write wallet backup file: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Exported wallet %s to %s\n", viper.GetString("export-wallet"), viper.GetString("wallet-backup-file"))
+	os.Exit(0)
+}
+
+// importWallet imports a wallet from wallet-backup-file in to the first store.
+func importWallet(ctx context.Context, majordomo majordomo.Service) {
+	if viper.GetString("wallet-backup-file") == "" {
+		fmt.Println("Wallet backup file required for import")
+		os.Exit(1)
+	}
+	data, err := ioutil.ReadFile(viper.GetString("wallet-backup-file"))
+	if err != nil {
+		fmt.Printf("Failed to read wallet backup file: %v\n", err)
+		os.Exit(1)
+	}
+	passphrase, err := walletBackupPassphrase(ctx, majordomo)
+	if err != nil {
+		fmt.Printf("%v\n", err)
+		os.Exit(1)
+	}
+	stores, err := initStores(ctx, majordomo)
+	if err != nil {
+		fmt.Printf("%v\n", err)
+		os.Exit(1)
+	}
+
+	wallet, err := cmd.ImportWallet(ctx, stores[0], data, passphrase)
+	if err != nil {
+		fmt.Printf("Failed to import wallet: %v\n", err)
+		os.Exit(1)
+	}
+	accounts := 0
+	for range wallet.Accounts(ctx) {
+		accounts++
+	}
+	fmt.Printf("Imported %s wallet %s with %d accounts\n", wallet.Type(), wallet.Name(), accounts)
+	os.Exit(0)
+}
+
+// walletBackupPassphrase obtains the passphrase for wallet backups.
+func walletBackupPassphrase(ctx context.Context, majordomo majordomo.Service) ([]byte, error) {
+	if viper.GetString("wallet-backup-passphrase") == "" {
+		return nil, errors.New("wallet backup passphrase required")
+	}
+	passphrase, err := majordomo.Fetch(ctx, viper.GetString("wallet-backup-passphrase"))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to obtain wallet backup passphrase")
+	}
+	return passphrase, nil
+}